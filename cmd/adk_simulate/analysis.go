@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cpunion/sci-bot/pkg/publication"
 	"github.com/cpunion/sci-bot/pkg/simulation"
 )
 
@@ -19,14 +20,28 @@ type summaryStats struct {
 	ToolCalls   int
 	AvgRespLen  float64
 
+	// FailedTurns counts turns whose model call failed, by ErrorKind;
+	// ToolErrors counts failed tool calls in turns that completed.
+	FailedTurns    int
+	FailuresByKind map[string]int
+	ToolErrors     int
+
+	// Heartbeats are kept out of the counts above.
+	Heartbeats       int
+	HeartbeatsByKind map[string]int
+
 	UsageEvents         int
 	PromptTokens        int
 	CandidatesTokens    int
 	ThoughtsTokens      int
 	ToolUsePromptTokens int
+	CachedContentTokens int
 	TotalTokens         int
 	AvgTokensPerEvent   float64
 	AvgTokensPerCall    float64
+
+	// TokensByTick sums TotalTokens over each tick's events.
+	TokensByTick map[int]int
 }
 
 func analyzeLog(path string) (*summaryStats, error) {
@@ -39,6 +54,10 @@ func analyzeLog(path string) (*summaryStats, error) {
 	stats := &summaryStats{
 		ByAgent:  make(map[string]int),
 		ByAction: make(map[string]int),
+
+		FailuresByKind:   make(map[string]int),
+		HeartbeatsByKind: make(map[string]int),
+		TokensByTick:     make(map[int]int),
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -52,6 +71,11 @@ func analyzeLog(path string) (*summaryStats, error) {
 		if err := json.Unmarshal(line, &ev); err != nil {
 			continue
 		}
+		if ev.Action == simulation.ActionHeartbeat {
+			stats.Heartbeats++
+			stats.HeartbeatsByKind[ev.Liveness]++
+			continue
+		}
 		stats.TotalEvents++
 		stats.ByAgent[ev.AgentName]++
 		stats.ByAction[ev.Action]++
@@ -59,6 +83,11 @@ func analyzeLog(path string) (*summaryStats, error) {
 			stats.SleepEvents++
 		}
 		stats.ToolCalls += len(ev.ToolCalls)
+		stats.ToolErrors += ev.ToolErrors
+		if ev.Failed {
+			stats.FailedTurns++
+			stats.FailuresByKind[ev.ErrorKind]++
+		}
 		totalRespLen += len(ev.Response)
 
 		stats.UsageEvents += ev.UsageEvents
@@ -66,7 +95,11 @@ func analyzeLog(path string) (*summaryStats, error) {
 		stats.CandidatesTokens += ev.CandidatesTokens
 		stats.ThoughtsTokens += ev.ThoughtsTokens
 		stats.ToolUsePromptTokens += ev.ToolUsePromptTokens
+		stats.CachedContentTokens += ev.CachedContentTokens
 		stats.TotalTokens += ev.TotalTokens
+		if ev.TotalTokens > 0 {
+			stats.TokensByTick[ev.Tick] += ev.TotalTokens
+		}
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
@@ -90,20 +123,38 @@ func printSummary(stats *summaryStats) {
 	fmt.Println("\n=== Log Analysis ===")
 	fmt.Printf("Total events: %d\n", stats.TotalEvents)
 	fmt.Printf("Sleep events: %d\n", stats.SleepEvents)
-	fmt.Printf("Tool calls: %d\n", stats.ToolCalls)
+	fmt.Printf("Tool calls: %d (%d failed)\n", stats.ToolCalls, stats.ToolErrors)
+	if stats.FailedTurns > 0 {
+		kinds := make([]string, 0, len(stats.FailuresByKind))
+		for _, kind := range sortedKeys(stats.FailuresByKind) {
+			kinds = append(kinds, fmt.Sprintf("%s=%d", kind, stats.FailuresByKind[kind]))
+		}
+		fmt.Printf("Failed turns: %d (%s)\n", stats.FailedTurns, strings.Join(kinds, ", "))
+	}
 	fmt.Printf("Avg response length: %.1f chars\n", stats.AvgRespLen)
 	if stats.TotalTokens > 0 {
-		fmt.Printf("Total tokens: %d (prompt=%d, candidates=%d, tool_use_prompt=%d, thoughts=%d)\n",
+		fmt.Printf("Total tokens: %d (prompt=%d, candidates=%d, tool_use_prompt=%d, thoughts=%d, cached=%d)\n",
 			stats.TotalTokens,
 			stats.PromptTokens,
 			stats.CandidatesTokens,
 			stats.ToolUsePromptTokens,
 			stats.ThoughtsTokens,
+			stats.CachedContentTokens,
 		)
 		fmt.Printf("Avg tokens/event: %.1f\n", stats.AvgTokensPerEvent)
 		if stats.UsageEvents > 0 {
 			fmt.Printf("Avg tokens/call: %.1f (calls=%d)\n", stats.AvgTokensPerCall, stats.UsageEvents)
 		}
+		if n := len(stats.TokensByTick); n > 0 {
+			peakTick, peak := 0, 0
+			for tick, tokens := range stats.TokensByTick {
+				if tokens > peak || (tokens == peak && tick < peakTick) {
+					peakTick, peak = tick, tokens
+				}
+			}
+			fmt.Printf("Avg tokens/tick: %.1f over %d ticks (peak %d at tick %d)\n",
+				float64(stats.TotalTokens)/float64(n), n, peak, peakTick)
+		}
 	}
 
 	fmt.Println("\nEvents by agent:")
@@ -117,6 +168,13 @@ func printSummary(stats *summaryStats) {
 	for _, action := range actions {
 		fmt.Printf("  %s: %d\n", action, stats.ByAction[action])
 	}
+
+	if stats.Heartbeats > 0 {
+		fmt.Printf("\nHeartbeats: %d\n", stats.Heartbeats)
+		for _, kind := range sortedKeys(stats.HeartbeatsByKind) {
+			fmt.Printf("  %s: %d\n", kind, stats.HeartbeatsByKind[kind])
+		}
+	}
 }
 
 func sortedKeys(m map[string]int) []string {
@@ -127,3 +185,45 @@ func sortedKeys(m map[string]int) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+func printCivility(report *publication.CivilityReport) {
+	if report == nil || report.Overall.Comments == 0 {
+		return
+	}
+	fmt.Println("\n=== Comment Civility ===")
+	fmt.Printf("Comments: %d, mean: %.2f, hostile: %d, flagged: %d\n",
+		report.Overall.Comments, report.Overall.Mean, report.Overall.Hostile, report.Overall.Flagged)
+
+	days := make([]string, 0, len(report.Overall.Daily))
+	for day := range report.Overall.Daily {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	for _, day := range days {
+		fmt.Printf("  %s: %.2f\n", day, report.Overall.Daily[day])
+	}
+
+	printGroup := func(title string, groups map[string]*publication.CivilityStats) {
+		fmt.Printf("\nCivility by %s (least civil first):\n", title)
+		keys := make([]string, 0, len(groups))
+		for k := range groups {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if groups[keys[i]].Mean == groups[keys[j]].Mean {
+				return keys[i] < keys[j]
+			}
+			return groups[keys[i]].Mean < groups[keys[j]].Mean
+		})
+		for _, k := range keys {
+			g := groups[k]
+			name := g.Name
+			if name == "" {
+				name = k
+			}
+			fmt.Printf("  %s: mean=%.2f hostile=%d/%d flagged=%d\n", name, g.Mean, g.Hostile, g.Comments, g.Flagged)
+		}
+	}
+	printGroup("agent", report.ByAgent)
+	printGroup("subreddit", report.BySubreddit)
+}
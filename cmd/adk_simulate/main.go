@@ -3,18 +3,28 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"math"
+	"math/rand"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
-	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/budget"
 	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/llm"
+	"github.com/cpunion/sci-bot/pkg/metrics"
+	"github.com/cpunion/sci-bot/pkg/prompts"
 	"github.com/cpunion/sci-bot/pkg/publication"
 	"github.com/cpunion/sci-bot/pkg/simulation"
 	"github.com/cpunion/sci-bot/pkg/site"
@@ -39,36 +49,152 @@ func main() {
 	logAppend := flag.Bool("log-append", true, "Append to log file instead of truncating")
 	feedDir := flag.String("feed", "feed", "Feed shards directory (relative to data directory). Set '-' to disable.")
 	feedMaxEvents := flag.Int("feed-max-events", 200, "Max events per feed shard file")
+	maxTokens := flag.Int("max-tokens", 0, "Max total tokens for this run (0 = unlimited)")
+	agentDailyTokens := flag.Int("agent-daily-tokens", 0, "Max tokens per agent per simulated day; exhausted agents sleep (0 = unlimited)")
 	maxOutputTokens := flag.Int("max-output-tokens", 2048, "Max output tokens per LLM call (maps to OpenAI/OpenRouter max_tokens)")
+	contextBudget := flag.Int("context-budget", 0, "Estimated prompt token budget per LLM call: agent_summary is fitted to it (newest entries kept) and oversized tool outputs trimmed (0 keeps the fixed 2000-character agent_summary)")
+	contextBudgets := flag.String("context-budgets", "", "Per-model prompt token budgets overriding -context-budget, by model name, e.g. gemini-3-flash-preview=200000,gpt-4o-mini=32000")
 	turnLimit := flag.Int("turns", 10, "Per-agent turn limit before sleep")
 	graceTurns := flag.Int("grace", 3, "Grace turns after bell")
 	agentsPerTick := flag.Int("per-tick", 1, "Number of agents to run per tick")
+	maxParallel := flag.Int("max-parallel", 1, "Max agents to run concurrently within a tick")
+	batchTurns := flag.Bool("batch", false, "Dispatch each tick's LLM requests together (higher latency, better throughput for large populations; ignores -max-parallel)")
+	rps := flag.Float64("rps", 0, "Default LLM requests per second per provider (0 = unlimited)")
+	providerRPS := flag.String("provider-rps", "", "Per-provider rate limits, e.g. gemini=2,openrouter=5")
+	llmRetries := flag.Int("llm-retries", 3, "Attempts per LLM call on transient errors (429, 5xx, timeouts) before the agent's turn is logged as failed (1 disables retries)")
+	llmBackoff := flag.Duration("llm-backoff", 2*time.Second, "Initial wait before retrying a failed LLM call; doubles each retry up to 1m")
+	breakerFailures := flag.Int("breaker-failures", 5, "Consecutive transient LLM failures that open a provider's circuit, failing its calls fast until the cooldown passes (0 disables)")
+	breakerCooldown := flag.Duration("breaker-cooldown", time.Minute, "How long an open circuit skips a provider before a trial call")
+	reviewCycle := flag.Duration("review-cycle", 7*24*time.Hour, "Simulated journal review cycle; submissions are batched at each cutoff and decided by the next (0 = instant review)")
+	reviewersPerPaper := flag.Int("reviewers-per-paper", 2, "Reviewers assigned to each submission in a review cycle")
+	editor := flag.Bool("editor", false, "Add a journal editor agent that assigns reviewers, desk-rejects out-of-scope papers and nags late reviewers")
+	consensusSupporters := flag.Int("consensus-supporters", 3, "Supporters, requester included, a consensus request needs to be achieved")
+	consensusShare := flag.Float64("consensus-share", 2.0/3, "Share of consensus votes that must be in support for a request to be achieved")
+	editorNag := flag.Duration("editor-nag", 3*24*time.Hour, "Simulated time an assigned review may be missing before the editor is asked to remind the reviewer (0 disables)")
+	summarizerModel := flag.String("summarizer-model", "", "LLM model spec for background thread summaries, usually a cheap model (e.g. gemini:gemini-2.5-flash-lite); after each tick long threads without a fresh summary are summarized so agents rarely have to (empty disables)")
+	summariesPerTick := flag.Int("summaries-per-tick", 2, "Max background thread summaries per tick (with -summarizer-model)")
+	weeklyDigest := flag.Bool("weekly-digest", true, "Have a communicator post a weekly digest of each subreddit's top threads to r/meta (indexed in digest.json)")
+	dream := flag.Bool("dream", true, "Consolidate each agent's day into long-term memory when its bell rings (one extra LLM call per agent per day)")
+	planner := flag.String("planner", "", "Plan multi-step research arcs instead of sampling each free turn's action: heuristic (from action weights and notifications) or llm (one call per plan, with -summarizer-model if set); empty disables")
+	embedderSpec := flag.String("embedder", "hash", "Embedder for agents' semantic memory (recall_memory): hash (local) or gemini[:model]")
+	inboxEvery := flag.Duration("inbox-every", 24*time.Hour, "Simulated interval between posts of the most-voted approved spectator questions from inbox.jsonl (0 disables)")
+	inboxQuestions := flag.Int("inbox-questions", 1, "Spectator questions posted each -inbox-every")
+	heartbeatEvery := flag.Duration("heartbeat", 6*time.Hour, "Simulated interval between liveness events for idle agents (0 disables)")
+	workHours := flag.String("work-hours", "", "Simulated working hours, e.g. 9-18; agents sleep outside them and wake each working day with a fresh turn budget (empty = always on, bell by turn count only)")
+	weekendsOff := flag.Bool("weekends-off", false, "Agents take simulated Saturdays and Sundays off")
+	seminarSpec := flag.String("seminar", "", "Weekly seminar where all working agents discuss the week's top thread, e.g. fri@15 (empty disables)")
+	retireIdle := flag.Duration("retire-idle", 0, "Retire agents after this much simulated time without acting (0 disables)")
+	retireReputation := flag.Float64("retire-reputation", 0, "Retire agents whose reputation score drops below this value, e.g. -2 (0 disables)")
+	minTenure := flag.Duration("min-tenure", 7*24*time.Hour, "Simulated time a new agent is protected from retirement")
+	recruit := flag.Bool("recruit", true, "Recruit a new agent of the same role for each retiree")
+	groupCheckIn := flag.Duration("group-checkin", 24*time.Hour, "Simulated interval between prompts asking research group members to coordinate in their group channel (0 disables)")
 	checkpointEvery := flag.Int("checkpoint", 1, "Checkpoint every N ticks (0 disables)")
+	journalsPath := flag.String("journals", "./config/journals.json", "Journals config (name, domains, acceptance threshold); missing file keeps the journals stored with the data")
+	profilesDir := flag.String("profiles", "", "Per-agent profile directory (e.g. ./config/agents from gen_agent_profiles); each agent's IDENTITY.md, SOUL.md, HEARTBEAT.md and USER.md join its instruction and are re-read when changed (empty disables)")
+	scenarioPath := flag.String("scenario", "", "Scenario file (JSON): agents, models per role, step, budgets, journals, subreddits and literature drops; flags given explicitly override it")
+	seedContent := flag.String("seed-content", "", "Directory of seed documents (*.md with front matter, *.json, optional authors.json) imported as initial forum posts and journal papers instead of the built-in seed posts")
 	agentCount := flag.Int("agents", 5, "Number of agents")
+	promptsDir := flag.String("prompts", "", "Prompt template directory: <lang>/*.tmpl files override the built-in instruction and action templates (pkg/prompts/templates), per role via \"<name>.<role>\" templates, and vars.json is injected as .Vars (empty uses the built-ins)")
+	llmCache := flag.String("llm-cache", "", "Directory caching LLM responses by request hash, so re-runs replay them instead of calling the API (empty disables)")
+	llmCacheMode := flag.String("llm-cache-mode", "rw", "LLM cache mode: rw (replay hits, record misses), replay (hits only; misses fail, no API key needed) or record (always call and overwrite)")
+	langName := flag.String("lang", "zh", "Prompt language: zh, en, or mixed to split agents between them; a persona's own language field takes precedence")
+	resume := flag.Bool("resume", true, "Continue the run saved in the data directory (sim_state.json); false refuses to start over existing run state")
+	metricsAddr := flag.String("metrics-addr", "", "Serve Prometheus metrics on this address at /metrics, e.g. :9091 (empty disables)")
+	controlAddr := flag.String("control-addr", "", "Serve the control API (/api/sim/status, live, pause, resume, step, config) on this address, e.g. 127.0.0.1:9092; the run then stays up after its ticks until interrupted (empty disables)")
+	controlToken := flag.String("control-token", "", "Bearer token required by control API calls that change the run (empty: none)")
 	seed := flag.Int64("seed", time.Now().UnixNano(), "Random seed for personas")
 	flag.Parse()
 
+	// Scenario settings fill in the flags not given on the command line, so
+	// everything below sees the merged values.
+	var scenario *simulation.Scenario
+	explicit := explicitFlags()
+	if strings.TrimSpace(*scenarioPath) != "" {
+		var err error
+		scenario, err = simulation.LoadScenario(*scenarioPath)
+		if err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+		if err := applyScenarioFlags(scenario, explicit); err != nil {
+			log.Fatalf("Invalid scenario %s: %v", *scenarioPath, err)
+		}
+	}
+
+	lang, err := prompts.ParseMode(*langName)
+	if err != nil {
+		log.Fatalf("Invalid -lang: %v", err)
+	}
+	if !simulation.ValidPlanner(*planner) {
+		log.Fatalf("Invalid -planner: %q (want heuristic or llm)", *planner)
+	}
+	var templates *prompts.Templates
+	if strings.TrimSpace(*promptsDir) != "" {
+		templates, err = prompts.LoadTemplates(*promptsDir)
+		if err != nil {
+			log.Fatalf("Failed to load prompt templates: %v", err)
+		}
+	}
+
+	var corpus *simulation.SeedCorpus
+	if strings.TrimSpace(*seedContent) != "" {
+		var err error
+		corpus, err = simulation.LoadSeedCorpus(*seedContent)
+		if err != nil {
+			log.Fatalf("Failed to load seed content: %v", err)
+		}
+	}
+
 	if *days > 0 {
 		*ticks = int(math.Ceil(float64(time.Duration(*days)*24*time.Hour) / float64(*step)))
 	}
 
-	ctx := context.Background()
+	// The first SIGINT/SIGTERM stops the run after the current tick and falls
+	// through to the normal checkpoint and manifest writing; a second one
+	// kills the process.
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stopSignals()
+		log.Printf("Interrupt received: finishing the current tick, then saving (interrupt again to abort)")
+	}()
 
 	if err := os.MkdirAll(*dataPath, 0755); err != nil {
 		log.Fatalf("Failed to create data directory: %v", err)
 	}
 
-	defaultModel, err := newLLM(ctx, normalizeModelSpec(*modelName))
+	models := newModelPool(ctx)
+	if strings.TrimSpace(*llmCache) != "" {
+		mode, err := llm.ParseCacheMode(*llmCacheMode)
+		if err != nil {
+			log.Fatalf("Invalid -llm-cache-mode: %v", err)
+		}
+		models.enableCache(*llmCache, mode)
+	}
+	defaultModel, err := models.get(*modelName)
 	if err != nil {
 		log.Fatalf("Failed to create model (%s): %v", *modelName, err)
 	}
+	if _, err := models.get(*reviewerModelName); err != nil {
+		log.Fatalf("Failed to create reviewer model (%s): %v", *reviewerModelName, err)
+	}
 
-	reviewerModel := defaultModel
-	if *reviewerModelName != *modelName {
-		reviewerModel, err = newLLM(ctx, normalizeModelSpec(*reviewerModelName))
-		if err != nil {
-			log.Fatalf("Failed to create reviewer model (%s): %v", *reviewerModelName, err)
-		}
+	providerLimits, err := parseProviderRates(*providerRPS)
+	if err != nil {
+		log.Fatalf("Invalid -provider-rps: %v", err)
+	}
+	modelBudgets, err := parseModelBudgets(*contextBudgets)
+	if err != nil {
+		log.Fatalf("Invalid -context-budgets: %v", err)
+	}
+
+	var rateLimiter *simulation.RateLimiter
+	if *rps > 0 || len(providerLimits) > 0 {
+		rateLimiter = simulation.NewRateLimiter(*rps, providerLimits)
+	}
+
+	var breaker *simulation.CircuitBreaker
+	if *breakerFailures > 0 {
+		breaker = simulation.NewCircuitBreaker(*breakerFailures, *breakerCooldown)
 	}
 
 	var fileLogger simulation.EventLogger
@@ -111,8 +237,21 @@ func main() {
 	fmt.Printf("Ticks: %d\n", *ticks)
 	fmt.Printf("Step: %s\n", step.String())
 	fmt.Printf("Agents per tick: %d\n", *agentsPerTick)
+	fmt.Printf("Max parallel: %d\n", *maxParallel)
 	fmt.Printf("Max output tokens: %d\n", *maxOutputTokens)
+	if *maxTokens > 0 {
+		fmt.Printf("Run token budget: %d\n", *maxTokens)
+	}
+	if *agentDailyTokens > 0 {
+		fmt.Printf("Agent daily token budget: %d\n", *agentDailyTokens)
+	}
+	if *reviewCycle > 0 {
+		fmt.Printf("Review cycle: %s (%d reviewers/paper)\n", reviewCycle.String(), *reviewersPerPaper)
+	}
 	fmt.Printf("Checkpoint every: %d\n", *checkpointEvery)
+	if strings.TrimSpace(*llmCache) != "" {
+		fmt.Printf("LLM cache: %s (%s)\n", *llmCache, *llmCacheMode)
+	}
 	if strings.TrimSpace(*logPath) != "" {
 		fmt.Printf("Log: %s\n", *logPath)
 	}
@@ -123,17 +262,70 @@ func main() {
 
 	journal := publication.NewJournal("科学前沿", filepath.Join(*dataPath, "journal"))
 	_ = journal.Load()
+	if scenario != nil && len(scenario.Journals) > 0 && !explicit["journals"] {
+		if err := journal.SetJournals(scenario.Journals); err != nil {
+			log.Fatalf("Invalid scenario journals: %v", err)
+		}
+	} else if strings.TrimSpace(*journalsPath) != "" {
+		journals, err := publication.LoadJournalConfig(*journalsPath)
+		switch {
+		case err == nil:
+			if err := journal.SetJournals(journals); err != nil {
+				log.Fatalf("Invalid journals config: %v", err)
+			}
+		case !os.IsNotExist(err):
+			log.Fatalf("Failed to load journals config: %v", err)
+		}
+	}
+	for _, info := range journal.JournalList() {
+		fmt.Printf("Journal: %s (%s)\n", info.Name, info.ID)
+	}
 
 	forum := publication.NewForum("自由论坛", filepath.Join(*dataPath, "forum"))
 	_ = forum.Load()
+	if scenario != nil {
+		if err := createScenarioSubreddits(forum, scenario.Subreddits); err != nil {
+			log.Fatalf("Invalid scenario subreddits: %v", err)
+		}
+	}
+	var roleModels map[types.AgentRole]string
+	if scenario != nil {
+		roleModels = scenario.Models.Roles
+	}
 
 	startTime := time.Now()
+	simOrigin := startTime
+	startTick := 0
+	runID := simulation.NewRunID()
+	var fork *simulation.ForkOrigin
 	if state, err := simulation.LoadSimState(*dataPath); err == nil && !state.SimTime.IsZero() {
+		if !*resume {
+			log.Fatalf("%s already holds a run (sim_state.json at tick %d); pass -resume or use a fresh -data directory", *dataPath, state.Ticks)
+		}
 		startTime = state.SimTime
+		startTick = state.Ticks
+		simOrigin = state.Origin
+		if simOrigin.IsZero() {
+			simOrigin = startTime
+		}
 		if state.StepSeconds > 0 && time.Duration(state.StepSeconds)*time.Second != *step {
 			log.Printf("Warning: sim step changed (prev %s, now %s)", time.Duration(state.StepSeconds)*time.Second, step.String())
 		}
-		fmt.Printf("Resume sim time: %s\n", startTime.Format(time.RFC3339))
+		if state.RunID != "" {
+			runID = state.RunID
+		}
+		fork = state.Fork
+		fmt.Printf("Resume sim time: %s (tick %d)\n", startTime.Format(time.RFC3339), startTick)
+		if fork != nil {
+			fmt.Printf("Forked from: %s (%s) at %s (tick %d)\n", fork.ParentRunID, fork.ParentData, fork.SimTime.Format(time.RFC3339), fork.Ticks)
+		}
+	}
+	fmt.Printf("Run ID: %s\n", runID)
+
+	if scenario != nil {
+		for _, drop := range scenario.LiteratureDrops {
+			fmt.Printf("Literature drop: %s at %s (%s)\n", drop.ID, drop.Due(simOrigin).Format(time.RFC3339), drop.Channel)
+		}
 	}
 
 	personas, err := loadOrCreatePersonas(*dataPath, *agentCount, *seed)
@@ -142,31 +334,140 @@ func main() {
 		personas = simulation.GeneratePersonas(*agentCount, *seed)
 	}
 
+	if *editor && !hasRole(personas, types.RoleEditor) {
+		personas = append(personas, simulation.EditorPersona())
+	}
+	// Agents recruited by earlier runs rejoin regardless of -agents.
+	recruits, err := loadRecruits(*dataPath)
+	if err != nil {
+		log.Printf("Warning: failed to load recruits: %v", err)
+	}
+	personas = append(personas, recruits...)
+
+	var questions *inbox.Inbox
+	if *inboxEvery > 0 {
+		if questions, err = inbox.Open(filepath.Join(*dataPath, inbox.FileName)); err != nil {
+			log.Printf("Warning: failed to open the spectator inbox: %v", err)
+		}
+	}
+
 	// Keep a static agents index for the frontend (no server API required).
 	if err := site.WriteAgentCatalog(filepath.Join(*dataPath, "agents", "agents.json"), personas); err != nil {
 		log.Printf("Warning: failed to write agents index: %v", err)
 	}
-	if len(forum.AllPosts()) == 0 {
-		seedInitialContent(forum, personas)
+	// Per-persona model overrides come from personas.json; create them up front
+	// so a bad spec or missing key fails before the run starts.
+	for _, p := range personas {
+		spec := personaModelSpec(p, *modelName, *reviewerModelName, roleModels)
+		if _, err := models.get(spec); err != nil {
+			log.Fatalf("Failed to create model for %s (%s): %v", p.ID, spec, err)
+		}
+	}
+	if corpus != nil {
+		posts, papers := corpus.Import(forum, journal, personas)
+		fmt.Printf("Seed content: %d of %d documents imported (%d posts, %d papers) from %s\n",
+			posts+papers, len(corpus.Docs), posts, papers, *seedContent)
+	} else if len(forum.AllPosts()) == 0 {
+		seedInitialContent(forum, personas, lang)
+	}
+
+	tracker := budget.NewTracker(budget.Config{
+		MaxRunTokens:        *maxTokens,
+		MaxAgentDailyTokens: *agentDailyTokens,
+	}, *dataPath)
+	if err := tracker.Load(); err != nil {
+		log.Printf("Warning: failed to load budget: %v", err)
+	}
+
+	var summarizer model.LLM
+	var summarizerProvider string
+	if *summarizerModel != "" {
+		summarizerProvider = llm.ModelProvider(*summarizerModel)
+		if summarizer, err = models.get(*summarizerModel); err != nil {
+			log.Fatalf("Failed to create summarizer model (%s): %v", *summarizerModel, err)
+		}
+	}
+
+	var metricsReg *metrics.Registry
+	if *metricsAddr != "" {
+		metricsReg = metrics.NewRegistry()
+	}
+
+	embedder, err := newEmbedder(ctx, *embedderSpec)
+	if err != nil {
+		log.Fatalf("Failed to create embedder: %v", err)
+	}
+
+	calendar, err := buildCalendar(*workHours, *weekendsOff, *seminarSpec)
+	if err != nil {
+		log.Fatalf("Invalid calendar: %v", err)
+	}
+
+	var recruitPersona func(*types.Persona, []*types.Persona) *types.Persona
+	if *recruit {
+		// Offset by earlier recruits so resumed runs don't replay the same traits.
+		rng := rand.New(rand.NewSource(*seed + int64(len(recruits))))
+		recruitPersona = func(retiree *types.Persona, roster []*types.Persona) *types.Persona {
+			return simulation.RecruitPersona(rng, retiree, roster)
+		}
 	}
 
 	sched := simulation.NewADKScheduler(simulation.ADKSchedulerConfig{
-		DataPath:        *dataPath,
-		Model:           defaultModel,
-		Logger:          logger,
-		SimStep:         *step,
-		StartTime:       startTime,
-		TurnLimit:       *turnLimit,
-		GraceTurns:      *graceTurns,
-		AgentsPerTick:   *agentsPerTick,
-		CheckpointEvery: *checkpointEvery,
-		MaxOutputTokens: int32(*maxOutputTokens),
+		DataPath:          *dataPath,
+		Model:             defaultModel,
+		Logger:            logger,
+		SimStep:           *step,
+		StartTime:         startTime,
+		TurnLimit:         *turnLimit,
+		GraceTurns:        *graceTurns,
+		AgentsPerTick:     *agentsPerTick,
+		CheckpointEvery:   *checkpointEvery,
+		MaxOutputTokens:   int32(*maxOutputTokens),
+		MaxParallel:       *maxParallel,
+		RateLimiter:       rateLimiter,
+		Retry:             simulation.RetryPolicy{MaxAttempts: *llmRetries, BaseDelay: *llmBackoff, MaxDelay: time.Minute},
+		Breaker:           breaker,
+		BatchTurns:        *batchTurns,
+		Budget:            tracker,
+		ReviewCycle:       *reviewCycle,
+		ReviewersPerPaper: *reviewersPerPaper,
+		EditorNagAfter:    *editorNag,
+		Consensus:         publication.ConsensusThresholds{MinSupporters: *consensusSupporters, MinShare: *consensusShare},
+		Dream:             *dream,
+		Planner:           *planner,
+		WeeklyDigest:      *weeklyDigest,
+		HeartbeatEvery:    *heartbeatEvery,
+		Scenario:          scenario,
+		Inbox:             questions,
+		InboxInterval:     *inboxEvery,
+		InboxQuestions:    *inboxQuestions,
+		SimOrigin:         simOrigin,
+		StartTick:         startTick,
+		Calendar:          calendar,
+		Embedder:          embedder,
+		RetireIdle:        *retireIdle,
+		RetireReputation:  *retireReputation,
+		MinTenure:         *minTenure,
+		Recruit:           recruitPersona,
+		Metrics:           metricsReg,
+		ProfilesDir:       *profilesDir,
+		GroupCheckIn:      *groupCheckIn,
+		RunID:             runID,
+		Fork:              fork,
+		Lang:              lang,
+		Prompts:           templates,
 		ModelForPersona: func(p *types.Persona) model.LLM {
-			if p.Role == types.RoleReviewer {
-				return reviewerModel
-			}
-			return defaultModel
+			m, _ := models.get(personaModelSpec(p, *modelName, *reviewerModelName, roleModels))
+			return m
+		},
+		ProviderForPersona: func(p *types.Persona) string {
+			return llm.ModelProvider(personaModelSpec(p, *modelName, *reviewerModelName, roleModels))
 		},
+		Summarizer:         summarizer,
+		SummarizerProvider: summarizerProvider,
+		SummariesPerTick:   *summariesPerTick,
+		ContextBudget:      *contextBudget,
+		ContextBudgets:     modelBudgets,
 	})
 	sched.SetJournal(journal)
 	sched.SetForum(forum)
@@ -177,27 +478,78 @@ func main() {
 		}
 	}
 
+	// Metrics and the control API share a listener when given the same address.
+	muxes := map[string]*http.ServeMux{}
+	muxFor := func(addr string) *http.ServeMux {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+	if metricsReg != nil {
+		muxFor(*metricsAddr).Handle("/metrics", metricsReg.Handler())
+		log.Printf("Metrics listening on %s/metrics", *metricsAddr)
+	}
+	var control *simulation.Controller
+	if *controlAddr != "" {
+		control = simulation.NewController(sched, *controlToken)
+		muxFor(*controlAddr).Handle("/api/sim/", control.Handler())
+		log.Printf("Control API listening on %s/api/sim/status", *controlAddr)
+	}
+	for addr, mux := range muxes {
+		go func() {
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				log.Printf("Warning: HTTP server on %s stopped: %v", addr, err)
+			}
+		}()
+	}
+
 	start := time.Now()
-	if err := sched.RunFor(ctx, *ticks); err != nil {
-		log.Fatalf("Simulation failed: %v", err)
+	interrupted := false
+	run := sched.RunFor
+	if control != nil {
+		run = control.Run
+	}
+	if err := run(ctx, *ticks); err != nil {
+		if !errors.Is(err, context.Canceled) {
+			log.Fatalf("Simulation failed: %v", err)
+		}
+		interrupted = true
 	}
 	elapsed := time.Since(start)
 
 	stats := sched.Stats()
-	fmt.Println("\n=== Simulation Complete ===")
+	if interrupted {
+		fmt.Println("\n=== Simulation Interrupted ===")
+	} else {
+		fmt.Println("\n=== Simulation Complete ===")
+	}
 	fmt.Printf("Duration: %v\n", elapsed)
 	fmt.Printf("Ticks: %v\n", stats["ticks"])
 	fmt.Printf("Agents: %v\n", stats["agents"])
 	fmt.Printf("Actions: %v\n", stats["action_stats"])
+	usage := tracker.RunUsage()
+	fmt.Printf("Tokens: %d (prompt=%d, candidates=%d)\n", usage.TotalTokens, usage.PromptTokens, usage.CandidatesTokens)
+	if strings.TrimSpace(*llmCache) != "" {
+		hits, misses := models.cacheStats()
+		fmt.Printf("LLM cache: %d hits, %d misses\n", hits, misses)
+	}
 
 	if err := sched.Save(); err != nil {
 		log.Printf("Warning: failed to save state: %v", err)
 	}
 
 	// Persist personas so resumed runs keep consistent identities without needing flags.
-	if err := savePersonas(*dataPath, *seed, personas); err != nil {
+	if err := savePersonas(*dataPath, *seed, personas[:len(personas)-len(recruits)]); err != nil {
 		log.Printf("Warning: failed to write personas.json: %v", err)
 	}
+	if newRecruits := sched.Recruits(); len(newRecruits) > 0 {
+		recruits = append(recruits, newRecruits...)
+		personas = append(personas, newRecruits...)
+		if err := saveRecruits(*dataPath, recruits); err != nil {
+			log.Printf("Warning: failed to write recruits.json: %v", err)
+		}
+	}
 	// Re-write the public agents index after the run, because agent names may
 	// have been updated from persisted state during AddAgent.
 	if err := site.WriteAgentCatalog(filepath.Join(*dataPath, "agents", "agents.json"), personas); err != nil {
@@ -221,10 +573,20 @@ func main() {
 			log.Printf("Log analysis skipped: %v", err)
 		}
 	}
+	printCivility(forum.CivilityReport())
 
 	fmt.Println("\nState saved to:", *dataPath)
 }
 
+func hasRole(personas []*types.Persona, role types.AgentRole) bool {
+	for _, p := range personas {
+		if p != nil && p.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
 func loadOrCreatePersonas(dataPath string, count int, seed int64) ([]*types.Persona, error) {
 	path := filepath.Join(dataPath, "personas.json")
 	data, err := os.ReadFile(path)
@@ -297,6 +659,65 @@ func savePersonas(dataPath string, seed int64, personas []*types.Persona) error
 	return os.WriteFile(filepath.Join(dataPath, "personas.json"), data, 0644)
 }
 
+// buildCalendar assembles the sim calendar from flags; nil when none is set.
+func buildCalendar(workHours string, weekendsOff bool, seminar string) (*simulation.Calendar, error) {
+	if strings.TrimSpace(workHours) == "" && !weekendsOff && strings.TrimSpace(seminar) == "" {
+		return nil, nil
+	}
+	cal := &simulation.Calendar{Weekends: weekendsOff}
+	if strings.TrimSpace(workHours) != "" {
+		start, end, err := simulation.ParseWorkHours(workHours)
+		if err != nil {
+			return nil, err
+		}
+		cal.WorkStart, cal.WorkEnd = start, end
+	}
+	if strings.TrimSpace(seminar) != "" {
+		sem, err := simulation.ParseSeminar(seminar)
+		if err != nil {
+			return nil, err
+		}
+		cal.Seminar = sem
+	}
+	return cal, nil
+}
+
+// loadRecruits reads the personas recruited mid-run by earlier runs. They are
+// kept apart from personas.json so -agents never truncates them away.
+func loadRecruits(dataPath string) ([]*types.Persona, error) {
+	data, err := os.ReadFile(filepath.Join(dataPath, "recruits.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var store struct {
+		Personas []*types.Persona `json:"personas"`
+	}
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, err
+	}
+	return store.Personas, nil
+}
+
+func saveRecruits(dataPath string, recruits []*types.Persona) error {
+	store := struct {
+		Version     int              `json:"version"`
+		GeneratedAt time.Time        `json:"generated_at"`
+		Personas    []*types.Persona `json:"personas"`
+	}{
+		Version:     1,
+		GeneratedAt: time.Now(),
+		Personas:    recruits,
+	}
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dataPath, "recruits.json"), data, 0644)
+}
+
 func writeStaticManifest(dataPath string, logPath string, feedIndexRel string, forum *publication.Forum, journal *publication.Journal, personas []*types.Persona) error {
 	state, _ := simulation.LoadSimState(dataPath)
 
@@ -343,6 +764,7 @@ func writeStaticManifest(dataPath string, logPath string, feedIndexRel string, f
 		ForumPath:     "forum/forum.json",
 		JournalPath:   "journal/journal.json",
 		FeedIndexPath: feedIndexRel,
+		ActivityPath:  "activity.json",
 		Logs:          logs,
 		DefaultLog:    defaultLog,
 		Stats: site.ManifestStats{
@@ -356,10 +778,18 @@ func writeStaticManifest(dataPath string, logPath string, feedIndexRel string, f
 		m.SimTime = state.SimTime
 		m.StepSeconds = state.StepSeconds
 	}
+	if _, err := os.Stat(filepath.Join(dataPath, "digest.json")); err == nil {
+		m.DigestPath = "digest.json"
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, "emergence.json")); err == nil {
+		m.EmergencePath = "emergence.json"
+	}
 
 	return site.WriteManifest(filepath.Join(dataPath, "site.json"), m)
 }
 
+// discoverLogs lists logs*.jsonl in dataPath, archived ones included;
+// the static site fetches and decompresses the archives itself.
 func discoverLogs(dataPath string) []string {
 	entries, err := os.ReadDir(dataPath)
 	if err != nil {
@@ -371,7 +801,7 @@ func discoverLogs(dataPath string) []string {
 			continue
 		}
 		name := e.Name()
-		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(name, ".jsonl") {
+		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(strings.TrimSuffix(name, feed.GzipExt), ".jsonl") {
 			continue
 		}
 		out = append(out, name)
@@ -380,38 +810,46 @@ func discoverLogs(dataPath string) []string {
 	return out
 }
 
-func normalizeModelSpec(spec string) string {
-	spec = strings.TrimSpace(spec)
-	if spec == "" {
-		return spec
-	}
-	// If the provider is explicitly specified, keep it.
-	if strings.Contains(spec, ":") {
-		return spec
-	}
-	// If the spec contains a slash, treat it as an OpenRouter-style model name and
-	// let the factory default to openrouter (backward compatible).
-	if strings.Contains(spec, "/") {
-		return spec
+// parseProviderRates parses "provider=rps,provider=rps" into a map.
+func parseProviderRates(spec string) (map[string]float64, error) {
+	out := make(map[string]float64)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected provider=rps, got %q", item)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate for %s: %w", key, err)
+		}
+		out[strings.ToLower(strings.TrimSpace(key))] = rate
 	}
-	// Backward compatible default: our project historically assumes Gemini.
-	return ailibmodel.ProviderGemini + ":" + spec
+	return out, nil
 }
 
-func newLLM(ctx context.Context, modelSpec string) (model.LLM, error) {
-	provider, _ := ailibmodel.ParseModelString(modelSpec)
-	// Keep compatibility with existing .env (`GOOGLE_API_KEY`) while still
-	// allowing provider-specific env vars for other providers.
-	//
-	// ailib expects GEMINI_API_KEY for Gemini provider, while this repo uses
-	// GOOGLE_API_KEY historically.
-	if provider == ailibmodel.ProviderGemini {
-		if os.Getenv("GEMINI_API_KEY") == "" && os.Getenv("GOOGLE_API_KEY") != "" {
-			_ = os.Setenv("GEMINI_API_KEY", os.Getenv("GOOGLE_API_KEY"))
+// parseModelBudgets parses "model=tokens,model=tokens" into a map.
+func parseModelBudgets(spec string) (map[string]int, error) {
+	out := make(map[string]int)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
 		}
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected model=tokens, got %q", item)
+		}
+		tokens, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget for %s: %w", key, err)
+		}
+		out[strings.TrimSpace(key)] = tokens
 	}
-
-	return ailibmodel.New(ctx, modelSpec)
+	return out, nil
 }
 
 func envOr(key, fallback string) string {
@@ -421,41 +859,21 @@ func envOr(key, fallback string) string {
 	return fallback
 }
 
-func seedInitialContent(forum *publication.Forum, personas []*types.Persona) {
+func seedInitialContent(forum *publication.Forum, personas []*types.Persona, lang prompts.Mode) {
 	if len(personas) < 3 {
 		return
 	}
-	initialPosts := []*types.Publication{
-		{
-			ID:         "seed-1",
-			AuthorID:   personas[0].ID,
-			AuthorName: personas[0].Name,
-			Title:      "关于自由落体的思考",
-			Content:    "如果在真空中，羽毛和铁球会以相同速度下落吗？",
-			Abstract:   "对亚里士多德物理学的质疑",
-			Subreddit:  types.SubPhysics,
-		},
-		{
-			ID:         "seed-2",
-			AuthorID:   personas[1].ID,
-			AuthorName: personas[1].Name,
-			Title:      "几何学第一原理",
-			Content:    "过两点有且仅有一条直线。这是不证自明的公理。",
-			Abstract:   "欧几里得几何的基础",
-			Subreddit:  types.SubMathematics,
-		},
-		{
-			ID:         "seed-3",
-			AuthorID:   personas[2].ID,
-			AuthorName: personas[2].Name,
-			Title:      "理论可证伪性的重要性",
-			Content:    "任何科学理论都必须允许被实验推翻，否则只是形而上学。",
-			Abstract:   "科学方法的核心要求",
-			Subreddit:  types.SubPhilosophy,
-		},
-	}
-
-	for _, post := range initialPosts {
-		forum.Post(post)
+	for i := range 3 {
+		// Each post is written in its author's prompt language.
+		seed := prompts.For(lang.For(personas[i])).SeedPosts[i]
+		forum.Post(&types.Publication{
+			ID:         fmt.Sprintf("seed-%d", i+1),
+			AuthorID:   personas[i].ID,
+			AuthorName: personas[i].Name,
+			Title:      seed.Title,
+			Content:    seed.Content,
+			Abstract:   seed.Abstract,
+			Subreddit:  seed.Subreddit,
+		})
 	}
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/adk/model"
+
+	"github.com/cpunion/sci-bot/pkg/llm"
+	"github.com/cpunion/sci-bot/pkg/memory"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// modelPool creates each distinct model spec once so personas sharing a spec
+// share one client.
+type modelPool struct {
+	ctx    context.Context
+	models map[string]model.LLM
+
+	// Response cache (optional); entries are keyed by model spec.
+	cacheDir  string
+	cacheMode llm.CacheMode
+	cached    []*llm.CachedLLM
+}
+
+func newModelPool(ctx context.Context) *modelPool {
+	return &modelPool{ctx: ctx, models: make(map[string]model.LLM)}
+}
+
+// enableCache wraps every model the pool creates with an on-disk response
+// cache under dir.
+func (p *modelPool) enableCache(dir string, mode llm.CacheMode) {
+	p.cacheDir, p.cacheMode = dir, mode
+}
+
+// cacheStats sums the hits and misses of the pool's cached models.
+func (p *modelPool) cacheStats() (hits, misses int64) {
+	for _, c := range p.cached {
+		h, m := c.Stats()
+		hits += h
+		misses += m
+	}
+	return hits, misses
+}
+
+func (p *modelPool) get(spec string) (model.LLM, error) {
+	spec = llm.NormalizeModelSpec(spec)
+	if m, ok := p.models[spec]; ok {
+		return m, nil
+	}
+	m, err := llm.NewModel(p.ctx, spec)
+	if err != nil && !(p.cacheDir != "" && p.cacheMode == llm.CacheReplay) {
+		return nil, err
+	}
+	if p.cacheDir != "" {
+		// Replays don't need the provider's client (or API key).
+		if err != nil {
+			m = nil
+		}
+		c, err := llm.NewCachedLLM(m, spec, p.cacheDir, p.cacheMode)
+		if err != nil {
+			return nil, err
+		}
+		p.cached = append(p.cached, c)
+		m = c
+	}
+	p.models[spec] = m
+	return m, nil
+}
+
+// personaModelSpec returns the model spec for a persona: its own override from
+// personas.json if set, then its role's model from the scenario, otherwise the
+// role default.
+func personaModelSpec(p *types.Persona, defaultSpec, reviewerSpec string, roleSpecs map[types.AgentRole]string) string {
+	if spec := strings.TrimSpace(p.Model); spec != "" {
+		return llm.NormalizeModelSpec(spec)
+	}
+	if spec := strings.TrimSpace(roleSpecs[p.Role]); spec != "" {
+		return llm.NormalizeModelSpec(spec)
+	}
+	if p.Role == types.RoleReviewer {
+		return llm.NormalizeModelSpec(reviewerSpec)
+	}
+	return llm.NormalizeModelSpec(defaultSpec)
+}
+
+// newEmbedder builds the semantic memory embedder from a spec: "hash" (local,
+// default) or "gemini[:model]".
+func newEmbedder(ctx context.Context, spec string) (memory.Embedder, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "" || spec == "hash":
+		return memory.NewHashEmbedder(0), nil
+	case spec == "gemini" || strings.HasPrefix(spec, "gemini:"):
+		return llm.NewGeminiEmbedder(ctx, "", strings.TrimPrefix(strings.TrimPrefix(spec, "gemini"), ":"))
+	default:
+		return nil, fmt.Errorf("unknown embedder %q (use hash or gemini[:model])", spec)
+	}
+}
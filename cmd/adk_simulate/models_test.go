@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/llm"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestPersonaModelSpec(t *testing.T) {
+	const (
+		defaultSpec  = "gemini-2.5-flash"
+		reviewerSpec = "openai:gpt-4o"
+	)
+	roleSpecs := map[types.AgentRole]string{
+		types.RoleReviewer: " anthropic:claude-sonnet-4 ",
+		types.RoleExplorer: "meta-llama/llama-3-70b",
+	}
+	for _, tc := range []struct {
+		name      string
+		persona   types.Persona
+		roleSpecs map[types.AgentRole]string
+		want      string
+	}{
+		{"persona override wins", types.Persona{Role: types.RoleReviewer, Model: "openai:o3"}, roleSpecs, "openai:o3"},
+		{"bare persona override is gemini", types.Persona{Role: types.RoleExplorer, Model: " gemini-2.5-pro "}, roleSpecs, "gemini:gemini-2.5-pro"},
+		{"blank override falls through", types.Persona{Role: types.RoleReviewer, Model: "  "}, roleSpecs, "anthropic:claude-sonnet-4"},
+		{"role spec beats reviewer default", types.Persona{Role: types.RoleReviewer}, roleSpecs, "anthropic:claude-sonnet-4"},
+		{"openrouter role spec kept", types.Persona{Role: types.RoleExplorer}, roleSpecs, "meta-llama/llama-3-70b"},
+		{"reviewer default", types.Persona{Role: types.RoleReviewer}, nil, "openai:gpt-4o"},
+		{"default", types.Persona{Role: types.RoleExplorer}, nil, "gemini:gemini-2.5-flash"},
+		{"role without spec uses default", types.Persona{Role: types.RoleBuilder}, roleSpecs, "gemini:gemini-2.5-flash"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := personaModelSpec(&tc.persona, defaultSpec, reviewerSpec, tc.roleSpecs); got != tc.want {
+				t.Errorf("personaModelSpec = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestModelPool_Get(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	pool := newModelPool(context.Background())
+
+	for _, tc := range []struct {
+		spec     string
+		provider string
+	}{
+		{"openai:gpt-4o", "openai"},
+		{"anthropic:claude-sonnet-4", llm.ProviderAnthropic},
+		{"gemini-2.5-flash", "gemini"},
+	} {
+		if got := llm.ModelProvider(llm.NormalizeModelSpec(tc.spec)); got != tc.provider {
+			t.Errorf("provider of %q = %q, want %q", tc.spec, got, tc.provider)
+		}
+	}
+
+	gpt, err := pool.get("openai:gpt-4o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := pool.get(" openai:gpt-4o "); err != nil || again != gpt {
+		t.Errorf("same spec with spaces gave %v, %v; want the shared client", again, err)
+	}
+	claude, err := pool.get("anthropic:claude-sonnet-4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claude == gpt || !strings.Contains(claude.Name(), "claude-sonnet-4") {
+		t.Errorf("anthropic model = %q, want a separate claude-sonnet-4 client", claude.Name())
+	}
+	if len(pool.models) != 2 {
+		t.Errorf("pool holds %d models, want 2", len(pool.models))
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	if _, err := pool.get("anthropic:claude-opus-4"); err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY") {
+		t.Errorf("anthropic without a key: err = %v, want one naming ANTHROPIC_API_KEY", err)
+	}
+}
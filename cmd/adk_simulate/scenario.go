@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// explicitFlags returns the names of the flags given on the command line.
+func explicitFlags() map[string]bool {
+	out := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		out[f.Name] = true
+	})
+	return out
+}
+
+// applyScenarioFlags sets each flag the scenario configures, unless it was
+// given on the command line. Values are parsed like command-line values.
+// An explicit -ticks also overrides the scenario's days, which would
+// otherwise take precedence over it.
+func applyScenarioFlags(sc *simulation.Scenario, explicit map[string]bool) error {
+	settings := sc.Flags()
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if explicit[name] || (name == "days" && explicit["ticks"]) {
+			fmt.Printf("Scenario %s=%s overridden by the command line\n", name, settings[name])
+			continue
+		}
+		if err := flag.Set(name, settings[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// createScenarioSubreddits registers the scenario's subreddits that the
+// forum doesn't have yet; they are saved with the next forum checkpoint.
+func createScenarioSubreddits(forum *publication.Forum, subs []*types.SubredditInfo) error {
+	for _, sub := range subs {
+		info := *sub
+		if forum.HasSubreddit(publication.NormalizeSubreddit(string(info.Name))) {
+			continue
+		}
+		if err := forum.CreateSubreddit(&info); err != nil {
+			return err
+		}
+		fmt.Printf("Subreddit: r/%s\n", info.Name)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct":   func(f float64) string { return fmt.Sprintf("%.1f%%", f*100) },
+	"num":   func(f float64) string { return fmt.Sprintf("%.1f", f) },
+	"join":  strings.Join,
+	"width": barWidth,
+	"alpha": func(n, max int) string {
+		if max == 0 || n == 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%.2f", 0.15+0.85*float64(n)/float64(max))
+	},
+	"sum":      func(a, b int) int { return a + b },
+	"permille": func(f float64) int { return int(f * 1000) },
+	"maxPosts": func(days []dayStats) int {
+		n := 0
+		for _, d := range days {
+			n = max(n, d.Threads+d.Comments)
+		}
+		return n
+	},
+}).Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8" />
+<title>Sci-Bot run report</title>
+<style>
+body { font: 14px/1.5 system-ui, sans-serif; margin: 2rem auto; max-width: 1100px; color: #1f2937; padding: 0 1rem; }
+h1 { margin-bottom: 0; }
+h2 { margin-top: 2rem; border-bottom: 1px solid #e5e7eb; padding-bottom: .25rem; }
+.meta { color: #6b7280; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .25rem .5rem; border-bottom: 1px solid #f3f4f6; vertical-align: top; }
+td.n, th.n { text-align: right; font-variant-numeric: tabular-nums; }
+.bar { background: #2563eb; height: .6rem; border-radius: 2px; }
+.cards { display: flex; gap: 1rem; flex-wrap: wrap; }
+.card { border: 1px solid #e5e7eb; border-radius: 6px; padding: .75rem 1rem; min-width: 10rem; }
+.card b { display: block; font-size: 1.4rem; }
+.heat td.c { text-align: center; min-width: 2rem; }
+.terms { color: #4b5563; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Sci-Bot run report</h1>
+<p class="meta">{{.DataPath}} · generated {{.GeneratedAt.Format "2006-01-02 15:04 UTC"}} · days on the {{if .SimClock}}simulated{{else}}wall{{end}} clock</p>
+
+<h2>Activity per day</h2>
+{{- $maxPosts := maxPosts .Days}}
+<table>
+<tr><th>Day</th><th class="n">Threads</th><th class="n">Comments</th><th></th><th class="n">Submissions</th><th class="n">Reviews</th><th class="n">Turns</th><th class="n">Active agents</th></tr>
+{{- range .Days}}
+<tr><td>{{.Day}}</td><td class="n">{{.Threads}}</td><td class="n">{{.Comments}}</td><td style="width:30%"><div class="bar" style="width: {{width (sum .Threads .Comments) $maxPosts}}%"></div></td><td class="n">{{.Submissions}}</td><td class="n">{{.Reviews}}</td><td class="n">{{.Turns}}</td><td class="n">{{.ActiveAgents}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Peer review</h2>
+<div class="cards">
+<div class="card">Accepted<b>{{.Acceptance.Accepted}}</b></div>
+<div class="card">Rejected<b>{{.Acceptance.Rejected}}</b></div>
+<div class="card">Pending<b>{{.Acceptance.Pending}}</b></div>
+<div class="card">Acceptance rate<b>{{pct .Acceptance.Rate}}</b></div>
+</div>
+<table style="margin-top:1rem">
+<tr><th>Hours from submission to</th><th class="n">Papers</th><th class="n">Mean</th><th class="n">Median</th><th class="n">P90</th><th class="n">Max</th></tr>
+{{- with .Turnaround.FirstReview}}
+<tr><td>first review</td><td class="n">{{.Count}}</td><td class="n">{{num .Mean}}</td><td class="n">{{num .Median}}</td><td class="n">{{num .P90}}</td><td class="n">{{num .Max}}</td></tr>
+{{- end}}
+{{- with .Turnaround.Decision}}
+<tr><td>decision</td><td class="n">{{.Count}}</td><td class="n">{{num .Mean}}</td><td class="n">{{num .Median}}</td><td class="n">{{num .P90}}</td><td class="n">{{num .Max}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Top collaborators</h2>
+{{- if .Collaborators}}
+<table>
+<tr><th>Agents</th><th class="n">Co-authored drafts</th><th class="n">Replies</th><th class="n">Reviews</th><th class="n">Score</th></tr>
+{{- range .Collaborators}}
+<tr><td>{{or .AName .A}} · {{or .BName .B}}</td><td class="n">{{.CoAuthored}}</td><td class="n">{{.Replies}}</td><td class="n">{{.Reviews}}</td><td class="n">{{.Score}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="meta">No agents have interacted yet.</p>
+{{- end}}
+
+<h2>Subreddit activity</h2>
+{{- $heat := .Subreddits}}
+<table class="heat">
+<tr><th>Subreddit</th>{{range .Days}}<th class="n">{{slice .Day 5}}</th>{{end}}</tr>
+{{- range $i, $sub := $heat.Subreddits}}
+{{- $row := index $heat.Counts $i}}
+<tr><td>r/{{$sub}}</td>{{range $j, $n := $row}}<td class="c" style="background: rgba(37, 99, 235, {{alpha $n $heat.Max}})">{{if $n}}{{$n}}{{end}}</td>{{end}}</tr>
+{{- end}}
+</table>
+
+<h2>Vocabulary drift</h2>
+<table>
+<tr><th>Day</th><th class="n">Tokens</th><th class="n">Drift</th><th>New terms</th><th>Top terms</th></tr>
+{{- range .Vocabulary}}
+<tr><td>{{.Day}}</td><td class="n">{{.Tokens}}</td><td class="n">{{pct .Drift}}</td><td>{{join .New ", "}}</td><td class="terms">{{join .Terms ", "}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Network density</h2>
+<table>
+<tr><th>Day</th><th class="n">Agents</th><th class="n">Pairs</th><th class="n">Density</th><th></th></tr>
+{{- range .Network}}
+<tr><td>{{.Day}}</td><td class="n">{{.Agents}}</td><td class="n">{{.Edges}}</td><td class="n">{{pct .Density}}</td><td style="width:40%"><div class="bar" style="width: {{width (permille .Density) 1000}}%"></div></td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, rp *report) error {
+	return reportTemplate.Execute(w, rp)
+}
+
+func barWidth(n, max int) int {
+	if max <= 0 {
+		return 0
+	}
+	return n * 100 / max
+}
@@ -0,0 +1,104 @@
+// Command analyze_run summarises a finished (or running) simulation from
+// its stores and logs: activity per simulated day, review turnaround and
+// acceptance, the most connected agent pairs, subreddit activity, how the
+// community's vocabulary drifts and how dense its interaction network gets.
+// Output is a self-contained HTML page or JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+)
+
+func main() {
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory (contains forum/, journal/, workflow/ and logs*.jsonl)")
+	format := flag.String("format", "html", "Output format: html or json")
+	outPath := flag.String("out", "", "Write the report to this file instead of stdout")
+	topPairs := flag.Int("top", 10, "Number of agent pairs listed as top collaborators")
+	topTerms := flag.Int("terms", 30, "Terms per day compared for vocabulary drift")
+	flag.Parse()
+
+	rp, err := analyze(*dataPath, reportOptions{TopPairs: *topPairs, TopTerms: *topTerms})
+	if err != nil {
+		log.Fatal(err)
+	}
+	rp.GeneratedAt = time.Now().UTC()
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Create %s: %v", *outPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	switch strings.ToLower(strings.TrimSpace(*format)) {
+	case "html", "":
+		err = writeHTML(out, rp)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rp)
+	default:
+		log.Fatalf("Unknown -format %q (use html or json)", *format)
+	}
+	if err != nil {
+		log.Fatalf("Write report: %v", err)
+	}
+}
+
+// analyze loads the stores and logs under dataPath and builds the report.
+// A missing workflow only drops the review figures.
+func analyze(dataPath string, opts reportOptions) (*report, error) {
+	forum := publication.NewForum("自由论坛", filepath.Join(dataPath, "forum"))
+	if err := forum.Load(); err != nil {
+		return nil, fmt.Errorf("load forum: %w", err)
+	}
+	journal := publication.NewJournal("科学前沿", filepath.Join(dataPath, "journal"))
+	if err := journal.Load(); err != nil {
+		return nil, fmt.Errorf("load journal: %w", err)
+	}
+	workflow := publication.NewWorkflow(filepath.Join(dataPath, "workflow"))
+	if err := workflow.Load(); err != nil {
+		log.Printf("Load workflow: %v (reporting without reviews)", err)
+	}
+	tl, err := readTimeline(dataPath, discoverLogs(dataPath))
+	if err != nil {
+		return nil, fmt.Errorf("read logs: %w", err)
+	}
+
+	rp := buildReport(tl, forum, journal, workflow, opts)
+	rp.DataPath = dataPath
+	return rp, nil
+}
+
+// discoverLogs lists logs*.jsonl in dataPath, archived ones included.
+func discoverLogs(dataPath string) []string {
+	entries, err := os.ReadDir(dataPath)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "logs") {
+			continue
+		}
+		if strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".jsonl.gz") {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,529 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/metrics"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+const dayLayout = "2006-01-02"
+
+type report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	DataPath    string    `json:"data_path"`
+	// SimClock is true when days come from the simulated clock recorded in
+	// the logs; without logs the stores' wall-clock times are used.
+	SimClock bool `json:"sim_clock"`
+
+	Days          []dayStats     `json:"days"`
+	Acceptance    acceptance     `json:"acceptance"`
+	Turnaround    turnaround     `json:"turnaround"`
+	Collaborators []pairStats    `json:"collaborators"`
+	Subreddits    heatmap        `json:"subreddits"`
+	Vocabulary    []vocabDay     `json:"vocabulary"`
+	Network       []networkPoint `json:"network"`
+}
+
+type dayStats struct {
+	Day          string `json:"day"`
+	Threads      int    `json:"threads"`
+	Comments     int    `json:"comments"`
+	Submissions  int    `json:"submissions"`
+	Reviews      int    `json:"reviews"`
+	Turns        int    `json:"turns"` // logged agent turns, heartbeats excluded
+	ActiveAgents int    `json:"active_agents"`
+}
+
+// acceptance counts journal decisions; exogenous papers, which skip peer
+// review, are left out.
+type acceptance struct {
+	Accepted int     `json:"accepted"`
+	Rejected int     `json:"rejected"`
+	Pending  int     `json:"pending"`
+	Rate     float64 `json:"rate"` // accepted / decided
+}
+
+// turnaround measures hours from submission to the first review and to
+// the decision.
+type turnaround struct {
+	FirstReview summary `json:"first_review_hours"`
+	Decision    summary `json:"decision_hours"`
+}
+
+type summary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	Max    float64 `json:"max"`
+}
+
+// pairStats counts the interactions between two agents: co-authored
+// drafts, replies to each other's posts and comments, and reviews of each
+// other's submissions.
+type pairStats struct {
+	A          string `json:"a"`
+	AName      string `json:"a_name"`
+	B          string `json:"b"`
+	BName      string `json:"b_name"`
+	CoAuthored int    `json:"co_authored"`
+	Replies    int    `json:"replies"`
+	Reviews    int    `json:"reviews"`
+	Score      int    `json:"score"`
+}
+
+// heatmap counts threads and comments per subreddit (rows) and day
+// (columns, the report's days in order).
+type heatmap struct {
+	Subreddits []string `json:"subreddits"`
+	Counts     [][]int  `json:"counts"`
+	Max        int      `json:"max"`
+}
+
+// vocabDay is one day's most frequent terms in forum posts and comments.
+// Drift is the Jaccard distance to the previous day's terms; New lists
+// terms never among an earlier day's.
+type vocabDay struct {
+	Day    string   `json:"day"`
+	Tokens int      `json:"tokens"`
+	Terms  []string `json:"terms"`
+	New    []string `json:"new,omitempty"`
+	Drift  float64  `json:"drift"`
+}
+
+// networkPoint is the interaction network at the end of a day, counting
+// every agent active and every pair that interacted up to then.
+type networkPoint struct {
+	Day     string  `json:"day"`
+	Agents  int     `json:"agents"`
+	Edges   int     `json:"edges"`
+	Density float64 `json:"density"`
+}
+
+type reportOptions struct {
+	TopPairs int
+	TopTerms int
+}
+
+// timeline maps the IDs tool calls created to the simulated time of the
+// turn, and records per-day turns and agent names from the logs.
+type timeline struct {
+	created map[string]time.Time
+	turns   map[string]int
+	active  map[string]map[string]bool // day -> agent IDs
+	names   map[string]string
+}
+
+func readTimeline(dataPath string, names []string) (*timeline, error) {
+	tl := &timeline{
+		created: make(map[string]time.Time),
+		turns:   make(map[string]int),
+		active:  make(map[string]map[string]bool),
+		names:   make(map[string]string),
+	}
+	for _, name := range names {
+		f, err := feed.OpenJSONL(filepath.Join(dataPath, name))
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var ev simulation.EventLog
+			if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.SimTime.IsZero() {
+				continue
+			}
+			if ev.Action == simulation.ActionHeartbeat {
+				continue
+			}
+			if ev.AgentName != "" {
+				tl.names[ev.AgentID] = ev.AgentName
+			}
+			day := ev.SimTime.Format(dayLayout)
+			tl.turns[day]++
+			tl.markActive(day, ev.AgentID)
+			for _, o := range ev.Outcomes {
+				for _, id := range o.Result {
+					if t, ok := tl.created[id]; id != "" && (!ok || ev.SimTime.Before(t)) {
+						tl.created[id] = ev.SimTime
+					}
+				}
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return tl, nil
+}
+
+func (tl *timeline) markActive(day, agentID string) {
+	if agentID == "" {
+		return
+	}
+	if tl.active[day] == nil {
+		tl.active[day] = make(map[string]bool)
+	}
+	tl.active[day][agentID] = true
+}
+
+// at returns when id was created: the simulated time of the turn that
+// created it, or stored (a wall-clock time) if no log mentions it.
+func (tl *timeline) at(id string, stored time.Time) time.Time {
+	if t, ok := tl.created[id]; ok {
+		return t
+	}
+	return stored
+}
+
+// interaction is one agent acting on another's work on a given day.
+type interaction struct {
+	day, from, to, kind string
+}
+
+func buildReport(tl *timeline, forum *publication.Forum, journal *publication.Journal, workflow *publication.Workflow, opts reportOptions) *report {
+	rp := &report{SimClock: len(tl.created) > 0}
+	daily := make(map[string]*dayStats)
+	dayOf := func(day string) *dayStats {
+		d := daily[day]
+		if d == nil {
+			d = &dayStats{Day: day}
+			daily[day] = d
+		}
+		return d
+	}
+	for day, n := range tl.turns {
+		dayOf(day).Turns = n
+	}
+	names := tl.names
+	var interactions []interaction
+
+	// Forum: threads, comments, replies and text per day.
+	posts := forum.AllPublications()
+	byID := make(map[string]*types.Publication, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+	subDays := make(map[string]map[string]int)
+	text := make(map[string][]string)
+	for _, p := range posts {
+		if p.Exogenous() {
+			continue
+		}
+		day := tl.at(p.ID, p.PublishedAt).Format(dayLayout)
+		if p.IsComment {
+			dayOf(day).Comments++
+			if parent := byID[p.ParentID]; parent != nil && parent.AuthorID != p.AuthorID {
+				interactions = append(interactions, interaction{day, p.AuthorID, parent.AuthorID, "reply"})
+			}
+		} else {
+			dayOf(day).Threads++
+		}
+		if names[p.AuthorID] == "" {
+			names[p.AuthorID] = p.AuthorName
+		}
+		tl.markActive(day, p.AuthorID)
+		sub := string(p.Subreddit)
+		if sub == "" {
+			sub = string(types.SubGeneral)
+		}
+		if subDays[sub] == nil {
+			subDays[sub] = make(map[string]int)
+		}
+		subDays[sub][day]++
+		text[day] = append(text[day], p.Title, p.Content)
+	}
+
+	// Workflow: submissions, reviews, turnaround and co-authored drafts.
+	var firstReview, decision []float64
+	for _, sub := range workflow.Submissions {
+		submitted := tl.at(sub.ID, sub.CreatedAt)
+		day := submitted.Format(dayLayout)
+		dayOf(day).Submissions++
+		tl.markActive(day, sub.AuthorID)
+		if names[sub.AuthorID] == "" {
+			names[sub.AuthorID] = sub.AuthorName
+		}
+
+		var first, last time.Time
+		for _, r := range workflow.Reviews[sub.ID] {
+			at := tl.at(r.ID, r.CreatedAt)
+			rday := at.Format(dayLayout)
+			dayOf(rday).Reviews++
+			tl.markActive(rday, r.ReviewerID)
+			if names[r.ReviewerID] == "" {
+				names[r.ReviewerID] = r.ReviewerName
+			}
+			if r.ReviewerID != sub.AuthorID {
+				interactions = append(interactions, interaction{rday, r.ReviewerID, sub.AuthorID, "review"})
+			}
+			if first.IsZero() || at.Before(first) {
+				first = at
+			}
+			if at.After(last) {
+				last = at
+			}
+		}
+		if !first.IsZero() && !first.Before(submitted) {
+			firstReview = append(firstReview, first.Sub(submitted).Hours())
+		}
+		// Scheduled reviews record the decision time; immediate ones are
+		// decided by the last review.
+		decided := sub.DecidedAt
+		if decided.IsZero() && (sub.Status == types.SubmissionAccepted || sub.Status == types.SubmissionRejected) {
+			decided = last
+		}
+		if !decided.IsZero() && !decided.Before(submitted) {
+			decision = append(decision, decided.Sub(submitted).Hours())
+		}
+	}
+	rp.Turnaround = turnaround{FirstReview: summarize(firstReview), Decision: summarize(decision)}
+	for _, d := range workflow.Drafts {
+		day := tl.at(d.ID, d.CreatedAt).Format(dayLayout)
+		for i, a := range d.Authors {
+			for _, b := range d.Authors[i+1:] {
+				if a != b {
+					interactions = append(interactions, interaction{day, a, b, "coauthor"})
+				}
+			}
+		}
+	}
+
+	accepted, rejected, pending := 0, 0, 0
+	for _, p := range journal.GetApproved() {
+		if !p.Exogenous() {
+			accepted++
+		}
+	}
+	rejected = len(journal.GetRejectedList())
+	pending = len(journal.GetPending())
+	rp.Acceptance = acceptance{Accepted: accepted, Rejected: rejected, Pending: pending}
+	if decided := accepted + rejected; decided > 0 {
+		rp.Acceptance.Rate = float64(accepted) / float64(decided)
+	}
+
+	for day, agents := range tl.active {
+		dayOf(day).ActiveAgents = len(agents)
+	}
+	days := make([]string, 0, len(daily))
+	for day := range daily {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	for _, day := range days {
+		rp.Days = append(rp.Days, *daily[day])
+	}
+
+	rp.Collaborators = topPairs(interactions, names, opts.TopPairs)
+	rp.Subreddits = buildHeatmap(subDays, days)
+	rp.Vocabulary = vocabularyDrift(days, text, opts.TopTerms)
+	rp.Network = networkDensity(days, tl.active, interactions)
+	return rp
+}
+
+func summarize(hours []float64) summary {
+	if len(hours) == 0 {
+		return summary{}
+	}
+	sort.Float64s(hours)
+	sum := 0.0
+	for _, h := range hours {
+		sum += h
+	}
+	pct := func(p float64) float64 {
+		return hours[int(math.Ceil(p*float64(len(hours))))-1]
+	}
+	return summary{
+		Count:  len(hours),
+		Mean:   sum / float64(len(hours)),
+		Median: pct(0.5),
+		P90:    pct(0.9),
+		Max:    hours[len(hours)-1],
+	}
+}
+
+// topPairs ranks agent pairs by their interactions, a co-authored draft
+// weighing as much as three replies or reviews.
+func topPairs(interactions []interaction, names map[string]string, limit int) []pairStats {
+	pairs := make(map[[2]string]*pairStats)
+	for _, in := range interactions {
+		a, b := in.from, in.to
+		if a == "" || b == "" {
+			continue
+		}
+		if b < a {
+			a, b = b, a
+		}
+		p := pairs[[2]string{a, b}]
+		if p == nil {
+			p = &pairStats{A: a, AName: names[a], B: b, BName: names[b]}
+			pairs[[2]string{a, b}] = p
+		}
+		switch in.kind {
+		case "coauthor":
+			p.CoAuthored++
+			p.Score += 3
+		case "reply":
+			p.Replies++
+			p.Score++
+		case "review":
+			p.Reviews++
+			p.Score++
+		}
+	}
+	out := make([]pairStats, 0, len(pairs))
+	for _, p := range pairs {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].A != out[j].A {
+			return out[i].A < out[j].A
+		}
+		return out[i].B < out[j].B
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func buildHeatmap(subDays map[string]map[string]int, days []string) heatmap {
+	h := heatmap{Subreddits: make([]string, 0, len(subDays))}
+	for sub := range subDays {
+		h.Subreddits = append(h.Subreddits, sub)
+	}
+	sort.Strings(h.Subreddits)
+	for _, sub := range h.Subreddits {
+		row := make([]int, len(days))
+		for i, day := range days {
+			row[i] = subDays[sub][day]
+			h.Max = max(h.Max, row[i])
+		}
+		h.Counts = append(h.Counts, row)
+	}
+	return h
+}
+
+func vocabularyDrift(days []string, text map[string][]string, topN int) []vocabDay {
+	var out []vocabDay
+	seen := make(map[string]bool)
+	var prev []string
+	for _, day := range days {
+		if len(text[day]) == 0 {
+			continue
+		}
+		counts := make(map[string]int)
+		tokens := 0
+		for _, s := range text[day] {
+			for _, term := range metrics.Terms(s) {
+				counts[term]++
+				tokens++
+			}
+		}
+		terms := topTerms(counts, topN)
+		v := vocabDay{Day: day, Tokens: tokens, Terms: terms}
+		if prev != nil {
+			v.Drift = jaccardDistance(prev, terms)
+			for _, t := range terms {
+				if !seen[t] {
+					v.New = append(v.New, t)
+				}
+			}
+		}
+		for _, t := range terms {
+			seen[t] = true
+		}
+		prev = terms
+		out = append(out, v)
+	}
+	return out
+}
+
+func topTerms(counts map[string]int, n int) []string {
+	terms := make([]string, 0, len(counts))
+	for t := range counts {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if n > 0 && len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
+
+func jaccardDistance(a, b []string) float64 {
+	set := make(map[string]int, len(a)+len(b))
+	for _, t := range a {
+		set[t] |= 1
+	}
+	for _, t := range b {
+		set[t] |= 2
+	}
+	if len(set) == 0 {
+		return 0
+	}
+	both := 0
+	for _, v := range set {
+		if v == 3 {
+			both++
+		}
+	}
+	return 1 - float64(both)/float64(len(set))
+}
+
+func networkDensity(days []string, active map[string]map[string]bool, interactions []interaction) []networkPoint {
+	byDay := make(map[string][]interaction)
+	for _, in := range interactions {
+		byDay[in.day] = append(byDay[in.day], in)
+	}
+	agents := make(map[string]bool)
+	edges := make(map[[2]string]bool)
+	out := make([]networkPoint, 0, len(days))
+	for _, day := range days {
+		for id := range active[day] {
+			agents[id] = true
+		}
+		for _, in := range byDay[day] {
+			a, b := in.from, in.to
+			if a == "" || b == "" || a == b {
+				continue
+			}
+			if b < a {
+				a, b = b, a
+			}
+			agents[a], agents[b] = true, true
+			edges[[2]string{a, b}] = true
+		}
+		p := networkPoint{Day: day, Agents: len(agents), Edges: len(edges)}
+		if n := len(agents); n > 1 {
+			p.Density = 2 * float64(len(edges)) / float64(n*(n-1))
+		}
+		out = append(out, p)
+	}
+	return out
+}
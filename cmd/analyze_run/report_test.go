@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// writeFixture saves a two-day run: Ada posts a thread and submits a paper
+// on day one, Bo comments on the thread and reviews the paper on day two.
+func writeFixture(t *testing.T, dataPath string) {
+	t.Helper()
+	forum := publication.NewForum("F", filepath.Join(dataPath, "forum"))
+	if err := forum.Post(&types.Publication{ID: "post-1", AuthorID: "ada", AuthorName: "Ada", Title: "Tired light",
+		Content: "Redshift without expansion", Subreddit: types.SubPhysics}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Comment("post-1", &types.Publication{ID: "comment-1", AuthorID: "bo", AuthorName: "Bo",
+		Content: "Redshift drift rules it out"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	journal := publication.NewJournal("J", filepath.Join(dataPath, "journal"))
+	for _, id := range []string{"paper-1", "paper-2", "paper-3"} {
+		if err := journal.Submit(&types.Publication{ID: id, AuthorID: "ada", Title: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := journal.Approve("paper-1", "bo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Reject("paper-2", "bo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	workflow := publication.NewWorkflow(filepath.Join(dataPath, "workflow"))
+	workflow.AddSubmission(&types.Submission{ID: "sub-1", AuthorID: "ada", AuthorName: "Ada", Title: "paper-1",
+		Status: types.SubmissionAccepted})
+	workflow.AddReview(&types.PaperReview{ID: "review-1", SubmissionID: "sub-1", ReviewerID: "bo", ReviewerName: "Bo"})
+	if err := workflow.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	day1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(25 * time.Hour)
+	events := []simulation.EventLog{
+		{SimTime: day1, AgentID: "ada", AgentName: "Ada", Action: "turn", Outcomes: []simulation.ToolOutcome{
+			{Tool: "create_post", Result: map[string]string{"post_id": "post-1"}},
+			{Tool: "submit_paper", Result: map[string]string{"submission_id": "sub-1"}},
+		}},
+		{SimTime: day2, AgentID: "bo", AgentName: "Bo", Action: "turn", Outcomes: []simulation.ToolOutcome{
+			{Tool: "comment", Result: map[string]string{"comment_id": "comment-1"}},
+		}},
+		{SimTime: day2, AgentID: "bo", AgentName: "Bo", Action: "turn", Outcomes: []simulation.ToolOutcome{
+			{Tool: "review_paper", Result: map[string]string{"review_id": "review-1"}},
+		}},
+		{SimTime: day2, AgentID: "ada", AgentName: "Ada", Action: simulation.ActionHeartbeat},
+	}
+	f, err := os.Create(filepath.Join(dataPath, "logs.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyze_Fixture(t *testing.T) {
+	dataPath := t.TempDir()
+	writeFixture(t, dataPath)
+
+	rp, err := analyze(dataPath, reportOptions{TopPairs: 10, TopTerms: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rp.SimClock {
+		t.Error("expected days from the simulated clock")
+	}
+
+	wantDays := []dayStats{
+		{Day: "2026-03-01", Threads: 1, Submissions: 1, Turns: 1, ActiveAgents: 1},
+		{Day: "2026-03-02", Comments: 1, Reviews: 1, Turns: 2, ActiveAgents: 1},
+	}
+	if !reflect.DeepEqual(rp.Days, wantDays) {
+		t.Errorf("days = %+v, want %+v", rp.Days, wantDays)
+	}
+
+	if want := (acceptance{Accepted: 1, Rejected: 1, Pending: 1, Rate: 0.5}); rp.Acceptance != want {
+		t.Errorf("acceptance = %+v, want %+v", rp.Acceptance, want)
+	}
+	want := summary{Count: 1, Mean: 25, Median: 25, P90: 25, Max: 25}
+	if rp.Turnaround.FirstReview != want || rp.Turnaround.Decision != want {
+		t.Errorf("turnaround = %+v, want %+v for both", rp.Turnaround, want)
+	}
+
+	wantPairs := []pairStats{{A: "ada", AName: "Ada", B: "bo", BName: "Bo", Replies: 1, Reviews: 1, Score: 2}}
+	if !reflect.DeepEqual(rp.Collaborators, wantPairs) {
+		t.Errorf("collaborators = %+v, want %+v", rp.Collaborators, wantPairs)
+	}
+
+	wantHeat := heatmap{Subreddits: []string{"physics"}, Counts: [][]int{{1, 1}}, Max: 1}
+	if !reflect.DeepEqual(rp.Subreddits, wantHeat) {
+		t.Errorf("subreddits = %+v, want %+v", rp.Subreddits, wantHeat)
+	}
+
+	wantNet := []networkPoint{
+		{Day: "2026-03-01", Agents: 1},
+		{Day: "2026-03-02", Agents: 2, Edges: 1, Density: 1},
+	}
+	if !reflect.DeepEqual(rp.Network, wantNet) {
+		t.Errorf("network = %+v, want %+v", rp.Network, wantNet)
+	}
+
+	if len(rp.Vocabulary) != 2 || rp.Vocabulary[0].Drift != 0 || rp.Vocabulary[1].Drift <= 0 {
+		t.Errorf("vocabulary = %+v, want two days with drift on the second", rp.Vocabulary)
+	}
+}
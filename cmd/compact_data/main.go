@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/feed"
+)
+
+// compact_data archives what a long run no longer appends to: logs*.jsonl,
+// feed shards and per-agent daily notes not written for -days are gzipped
+// in place (name + ".gz"). The server, index_data and the static site read
+// the archives transparently.
+func main() {
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory")
+	days := flag.Int("days", 7, "Compress files not modified for this many days")
+	feedDir := flag.String("feed", "feed", "Feed shards directory (relative to data directory). Set '-' to skip.")
+	dryRun := flag.Bool("dry-run", false, "List what would be compressed without changing anything")
+	flag.Parse()
+
+	cutoff := time.Now().AddDate(0, 0, -*days)
+	c := &compactor{cutoff: cutoff, dryRun: *dryRun}
+
+	var indexed map[string]int64
+	if dir := strings.TrimSpace(*feedDir); dir != "-" && dir != "" {
+		store := filepath.Join(*dataPath, dir)
+		if idx, err := feed.LoadIndex(filepath.Join(store, "index.json")); err == nil {
+			indexed = idx.Sources
+			if err := c.shards(store, idx); err != nil {
+				log.Fatalf("Compact feed shards: %v", err)
+			}
+		} else if !os.IsNotExist(err) {
+			log.Fatalf("Load feed index: %v", err)
+		}
+	}
+
+	logs, _ := filepath.Glob(filepath.Join(*dataPath, "logs*.jsonl"))
+	for _, path := range logs {
+		// index_data -update-feed skips archived logs it has indexed, so
+		// only archive a log once all of it is in the feed store.
+		if len(indexed) > 0 {
+			if info, err := os.Stat(path); err == nil && indexed[filepath.Base(path)] != info.Size() {
+				fmt.Printf("Skip %s: not fully indexed (run index_data -update-feed first)\n", filepath.Base(path))
+				continue
+			}
+		}
+		if err := c.compress(path); err != nil {
+			log.Fatalf("Compress %s: %v", path, err)
+		}
+	}
+
+	daily, _ := filepath.Glob(filepath.Join(*dataPath, "agents", "*", "daily", "*.jsonl"))
+	for _, path := range daily {
+		if err := c.compress(path); err != nil {
+			log.Fatalf("Compress %s: %v", path, err)
+		}
+	}
+
+	verb := "Compressed"
+	if *dryRun {
+		verb = "Would compress"
+	}
+	fmt.Printf("%s %d files (%s)", verb, c.files, formatBytes(c.before))
+	if !*dryRun && c.files > 0 {
+		fmt.Printf(" -> %s", formatBytes(c.after))
+	}
+	fmt.Println()
+}
+
+type compactor struct {
+	cutoff time.Time
+	dryRun bool
+
+	files         int
+	before, after int64
+}
+
+// compress gzips path if it hasn't been modified since the cutoff.
+func (c *compactor) compress(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || !info.ModTime().Before(c.cutoff) {
+		return err
+	}
+	c.files++
+	c.before += info.Size()
+	if c.dryRun {
+		fmt.Printf("  %s\n", path)
+		return nil
+	}
+	if err := feed.CompressFile(path); err != nil {
+		return err
+	}
+	if gz, err := os.Stat(path + feed.GzipExt); err == nil {
+		c.after += gz.Size()
+	}
+	return nil
+}
+
+// shards compresses old feed shards and points the index at them.
+func (c *compactor) shards(store string, idx *feed.Index) error {
+	if c.dryRun {
+		for i, shard := range idx.Shards {
+			if i < len(idx.Shards)-1 && !strings.HasSuffix(shard.File, feed.GzipExt) {
+				if err := c.compress(filepath.Join(store, shard.File)); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	sizes := make(map[string]int64)
+	for _, shard := range idx.Shards {
+		if info, err := os.Stat(filepath.Join(store, shard.File)); err == nil && info.ModTime().Before(c.cutoff) {
+			sizes[shard.File] = info.Size()
+		}
+	}
+	if _, err := feed.CompactShards(store, c.cutoff); err != nil {
+		return err
+	}
+	for file, size := range sizes {
+		if gz, err := os.Stat(filepath.Join(store, file+feed.GzipExt)); err == nil {
+			c.files++
+			c.before += size
+			c.after += gz.Size()
+		}
+	}
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
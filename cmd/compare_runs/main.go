@@ -0,0 +1,229 @@
+// Command compare_runs puts the headline numbers of two data directories
+// side by side — publications, consensus, the relationship network,
+// emergence metrics and token spend — for A/B experiments such as a
+// cheaper reviewer model or a larger population.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/budget"
+)
+
+// metric is one compared number. Change is B relative to A (0 when A is
+// 0).
+type metric struct {
+	Section string  `json:"section"`
+	Name    string  `json:"name"`
+	A       float64 `json:"a"`
+	B       float64 `json:"b"`
+	Delta   float64 `json:"delta"`
+	Change  float64 `json:"change"`
+
+	format string // fmt verb for A, B and Delta
+}
+
+type comparison struct {
+	Runs    [2]*runSummary `json:"runs"`
+	Metrics []metric       `json:"metrics"`
+}
+
+func main() {
+	labels := flag.String("labels", "", "Comma-separated names for the two runs (default: directory names)")
+	pricesPath := flag.String("prices", "", "Price table (JSON) as for cost_report; empty compares tokens only")
+	format := flag.String("format", "text", "Output format: text, csv or json")
+	outPath := flag.String("out", "", "Write the comparison to this file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: compare_runs [flags] <data-a> <data-b>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var prices budget.PriceTable
+	if strings.TrimSpace(*pricesPath) != "" {
+		var err error
+		if prices, err = budget.LoadPrices(*pricesPath); err != nil {
+			log.Fatalf("Load prices: %v", err)
+		}
+	}
+	names := [2]string{filepath.Base(filepath.Clean(flag.Arg(0))), filepath.Base(filepath.Clean(flag.Arg(1)))}
+	if strings.TrimSpace(*labels) != "" {
+		parts := strings.Split(*labels, ",")
+		if len(parts) != 2 {
+			log.Fatalf("-labels needs two names, got %q", *labels)
+		}
+		names = [2]string{strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])}
+	}
+
+	var cmp comparison
+	for i := range cmp.Runs {
+		s, err := loadSummary(names[i], flag.Arg(i), prices)
+		if err != nil {
+			log.Fatalf("%s: %v", flag.Arg(i), err)
+		}
+		if len(s.Unpriced) > 0 {
+			log.Printf("%s: no price for %s; counted as 0", s.Label, strings.Join(s.Unpriced, ", "))
+		}
+		cmp.Runs[i] = s
+	}
+	cmp.Metrics = compare(cmp.Runs[0], cmp.Runs[1], prices != nil)
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Create %s: %v", *outPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	var err error
+	switch strings.ToLower(strings.TrimSpace(*format)) {
+	case "text", "":
+		cmp.writeText(out)
+	case "csv":
+		err = cmp.writeCSV(out)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(cmp)
+	default:
+		log.Fatalf("Unknown -format %q (use text, csv or json)", *format)
+	}
+	if err != nil {
+		log.Fatalf("Write comparison: %v", err)
+	}
+}
+
+// row defines a compared metric: its format verb and how to read it from a
+// run.
+type row struct {
+	section, name, format string
+	value                 func(*runSummary) float64
+}
+
+// compare lists the metrics in display order. Costs are left out without
+// a price table.
+func compare(a, b *runSummary, priced bool) []metric {
+	perTurn := func(s *runSummary) float64 { return ratio(float64(s.TotalTokens), float64(s.Turns)) }
+	perPaper := func(s *runSummary) float64 { return ratio(float64(s.TotalTokens), float64(s.Accepted)) }
+	costPerPaper := func(s *runSummary) float64 { return ratio(s.CostUSD, float64(s.Accepted)) }
+	rows := []row{
+		{"Run", "Ticks", "%.0f", func(s *runSummary) float64 { return float64(s.Ticks) }},
+		{"Run", "Sim days", "%.1f", func(s *runSummary) float64 { return s.SimDays }},
+		{"Run", "Agents", "%.0f", func(s *runSummary) float64 { return float64(s.Agents) }},
+		{"Run", "Turns", "%.0f", func(s *runSummary) float64 { return float64(s.Turns) }},
+		{"Publications", "Threads", "%.0f", func(s *runSummary) float64 { return float64(s.Threads) }},
+		{"Publications", "Comments", "%.0f", func(s *runSummary) float64 { return float64(s.Comments) }},
+		{"Publications", "Submissions", "%.0f", func(s *runSummary) float64 { return float64(s.Submissions) }},
+		{"Publications", "Reviews", "%.0f", func(s *runSummary) float64 { return float64(s.Reviews) }},
+		{"Publications", "Papers accepted", "%.0f", func(s *runSummary) float64 { return float64(s.Accepted) }},
+		{"Publications", "Papers rejected", "%.0f", func(s *runSummary) float64 { return float64(s.Rejected) }},
+		{"Publications", "Papers pending", "%.0f", func(s *runSummary) float64 { return float64(s.Pending) }},
+		{"Publications", "Acceptance rate", "%.3f", func(s *runSummary) float64 { return s.AcceptanceRate }},
+		{"Consensus", "Requests", "%.0f", func(s *runSummary) float64 { return float64(s.ConsensusRequests) }},
+		{"Consensus", "Achieved", "%.0f", func(s *runSummary) float64 { return float64(s.ConsensusAchieved) }},
+		{"Consensus", "Closed", "%.0f", func(s *runSummary) float64 { return float64(s.ConsensusClosed) }},
+		{"Network", "Related pairs", "%.0f", func(s *runSummary) float64 { return float64(s.Pairs) }},
+		{"Network", "Density", "%.3f", func(s *runSummary) float64 { return s.Density }},
+		{"Network", "Mean degree", "%.2f", func(s *runSummary) float64 { return s.MeanDegree }},
+		{"Emergence", "Novelty", "%.3f", func(s *runSummary) float64 { return s.Novelty }},
+		{"Emergence", "Topic entropy (bits)", "%.3f", func(s *runSummary) float64 { return s.TopicEntropy }},
+		{"Emergence", "Citation Gini", "%.3f", func(s *runSummary) float64 { return s.CitationGini }},
+		{"Tokens", "LLM calls", "%.0f", func(s *runSummary) float64 { return float64(s.Calls) }},
+		{"Tokens", "Prompt tokens", "%.0f", func(s *runSummary) float64 { return float64(s.PromptTokens) }},
+		{"Tokens", "Output tokens", "%.0f", func(s *runSummary) float64 { return float64(s.OutputTokens) }},
+		{"Tokens", "Total tokens", "%.0f", func(s *runSummary) float64 { return float64(s.TotalTokens) }},
+		{"Tokens", "Tokens per turn", "%.0f", perTurn},
+		{"Tokens", "Tokens per accepted paper", "%.0f", perPaper},
+	}
+	if priced {
+		rows = append(rows,
+			row{"Tokens", "Cost (USD)", "%.4f", func(s *runSummary) float64 { return s.CostUSD }},
+			row{"Tokens", "Cost per accepted paper (USD)", "%.4f", costPerPaper},
+		)
+	}
+	out := make([]metric, 0, len(rows))
+	for _, r := range rows {
+		m := metric{Section: r.section, Name: r.name, A: r.value(a), B: r.value(b), format: r.format}
+		m.Delta = m.B - m.A
+		m.Change = ratio(m.Delta, math.Abs(m.A))
+		out = append(out, m)
+	}
+	return out
+}
+
+func ratio(n, d float64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+// writeText prints one line per metric, grouped by section.
+func (c *comparison) writeText(w io.Writer) {
+	a, b := c.Runs[0], c.Runs[1]
+	fmt.Fprintf(w, "A: %s  %s", a.Label, a.Data)
+	if len(a.Models) > 0 {
+		fmt.Fprintf(w, "  [%s]", strings.Join(a.Models, ", "))
+	}
+	fmt.Fprintf(w, "\nB: %s  %s", b.Label, b.Data)
+	if len(b.Models) > 0 {
+		fmt.Fprintf(w, "  [%s]", strings.Join(b.Models, ", "))
+	}
+	fmt.Fprintln(w)
+
+	section := ""
+	for _, m := range c.Metrics {
+		if m.Section != section {
+			section = m.Section
+			fmt.Fprintf(w, "\n%-32s %14s %14s %14s %9s\n", section, clipLabel(a.Label), clipLabel(b.Label), "diff", "change")
+		}
+		change := ""
+		if m.A != 0 {
+			change = fmt.Sprintf("%+.1f%%", m.Change*100)
+		}
+		fmt.Fprintf(w, "  %-30s %14s %14s %14s %9s\n", m.Name,
+			fmt.Sprintf(m.format, m.A), fmt.Sprintf(m.format, m.B), fmt.Sprintf("%+"+strings.TrimPrefix(m.format, "%"), m.Delta), change)
+	}
+}
+
+func clipLabel(s string) string {
+	if r := []rune(s); len(r) > 14 {
+		return string(r[:13]) + "…"
+	}
+	return s
+}
+
+// writeCSV writes one line per metric.
+func (c *comparison) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"section", "metric", c.Runs[0].Label, c.Runs[1].Label, "delta", "change"}); err != nil {
+		return err
+	}
+	for _, m := range c.Metrics {
+		record := []string{m.Section, m.Name}
+		for _, v := range []float64{m.A, m.B, m.Delta, m.Change} {
+			record = append(record, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
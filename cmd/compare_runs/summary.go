@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/budget"
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/metrics"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// runSummary holds the headline numbers of one data directory.
+type runSummary struct {
+	Label   string   `json:"label"`
+	Data    string   `json:"data"`
+	RunID   string   `json:"run_id,omitempty"`
+	Ticks   int      `json:"ticks"`
+	SimDays float64  `json:"sim_days"`
+	Agents  int      `json:"agents"`
+	Models  []string `json:"models"`
+
+	Threads        int     `json:"threads"`
+	Comments       int     `json:"comments"`
+	Submissions    int     `json:"submissions"`
+	Reviews        int     `json:"reviews"`
+	Accepted       int     `json:"accepted"`
+	Rejected       int     `json:"rejected"`
+	Pending        int     `json:"pending"`
+	AcceptanceRate float64 `json:"acceptance_rate"`
+
+	ConsensusRequests int `json:"consensus_requests"`
+	ConsensusAchieved int `json:"consensus_achieved"`
+	ConsensusClosed   int `json:"consensus_closed"`
+
+	// The relationship network: pairs of agents where either side knows
+	// the other.
+	Pairs      int     `json:"pairs"`
+	Density    float64 `json:"density"`
+	MeanDegree float64 `json:"mean_degree"`
+
+	// From emergence.json when the run recorded one.
+	Novelty      float64 `json:"novelty"`
+	TopicEntropy float64 `json:"topic_entropy"`
+	CitationGini float64 `json:"citation_gini"`
+
+	Turns        int     `json:"turns"`
+	Calls        int     `json:"calls"`
+	PromptTokens int     `json:"prompt_tokens"`
+	OutputTokens int     `json:"output_tokens"` // candidates and thoughts
+	TotalTokens  int     `json:"total_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+	// Unpriced lists models without an entry in the price table.
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+func loadSummary(label, dataPath string, prices budget.PriceTable) (*runSummary, error) {
+	if _, err := os.Stat(dataPath); err != nil {
+		return nil, err
+	}
+	s := &runSummary{Label: label, Data: dataPath}
+	if state, err := simulation.LoadSimState(dataPath); err == nil {
+		s.RunID = state.RunID
+		s.Ticks = state.Ticks
+		if !state.Origin.IsZero() {
+			s.SimDays = state.SimTime.Sub(state.Origin).Hours() / 24
+		}
+	}
+
+	forum := publication.NewForum("自由论坛", filepath.Join(dataPath, "forum"))
+	if err := forum.Load(); err != nil {
+		return nil, fmt.Errorf("load forum: %w", err)
+	}
+	s.Threads, s.Comments = forum.Counts()
+
+	journal := publication.NewJournal("科学前沿", filepath.Join(dataPath, "journal"))
+	if err := journal.Load(); err != nil {
+		return nil, fmt.Errorf("load journal: %w", err)
+	}
+	for _, p := range journal.GetApproved() {
+		if !p.Exogenous() {
+			s.Accepted++
+		}
+	}
+	s.Rejected = len(journal.GetRejectedList())
+	s.Pending = len(journal.GetPending())
+	if decided := s.Accepted + s.Rejected; decided > 0 {
+		s.AcceptanceRate = float64(s.Accepted) / float64(decided)
+	}
+
+	workflow := publication.NewWorkflow(filepath.Join(dataPath, "workflow"))
+	if err := workflow.Load(); err != nil {
+		return nil, fmt.Errorf("load workflow: %w", err)
+	}
+	s.Submissions = len(workflow.Submissions)
+	s.Reviews = len(workflow.AllReviews())
+	for _, c := range workflow.Consensus {
+		s.ConsensusRequests++
+		switch c.Status {
+		case types.ConsensusAchieved:
+			s.ConsensusAchieved++
+		case types.ConsensusClosed:
+			s.ConsensusClosed++
+		}
+	}
+
+	if err := s.loadNetwork(dataPath); err != nil {
+		return nil, fmt.Errorf("load agent states: %w", err)
+	}
+	if err := s.loadEmergence(dataPath); err != nil {
+		return nil, fmt.Errorf("load emergence index: %w", err)
+	}
+	if err := s.loadUsage(dataPath, prices); err != nil {
+		return nil, fmt.Errorf("read logs: %w", err)
+	}
+	return s, nil
+}
+
+// loadNetwork reads the relationships in agents/<id>/state.json.
+func (s *runSummary) loadNetwork(dataPath string) error {
+	paths, _ := filepath.Glob(filepath.Join(dataPath, "agents", "*", "state.json"))
+	pairs := make(map[[2]string]bool)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var state struct {
+			Relationships map[string]*types.Relationship `json:"relationships"`
+		}
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		id := filepath.Base(filepath.Dir(path))
+		s.Agents++
+		for peer, rel := range state.Relationships {
+			if rel == nil || peer == id {
+				continue
+			}
+			a, b := id, peer
+			if b < a {
+				a, b = b, a
+			}
+			pairs[[2]string{a, b}] = true
+		}
+	}
+	s.Pairs = len(pairs)
+	if n := s.Agents; n > 1 {
+		s.Density = 2 * float64(s.Pairs) / float64(n*(n-1))
+		s.MeanDegree = 2 * float64(s.Pairs) / float64(n)
+	}
+	return nil
+}
+
+// loadEmergence averages novelty over the scored documents and topic
+// entropy over the weeks, and takes the latest citation Gini.
+func (s *runSummary) loadEmergence(dataPath string) error {
+	path := filepath.Join(dataPath, "emergence.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	idx, err := metrics.LoadEmergenceIndex(path)
+	if err != nil {
+		return err
+	}
+	scored, novelty := 0, 0.0
+	entropy, weeks := 0.0, 0
+	for _, w := range idx.Weeks {
+		novelty += w.Novelty * float64(w.Scored)
+		scored += w.Scored
+		if w.Documents > 0 {
+			entropy += w.Entropy
+			weeks++
+		}
+	}
+	if scored > 0 {
+		s.Novelty = novelty / float64(scored)
+	}
+	if weeks > 0 {
+		s.TopicEntropy = entropy / float64(weeks)
+	}
+	if n := len(idx.Points); n > 0 {
+		s.CitationGini = idx.Points[n-1].CitationGini
+	}
+	return nil
+}
+
+// loadUsage sums turns and token usage over logs*.jsonl (archived ones
+// included), pricing each model with prices.
+func (s *runSummary) loadUsage(dataPath string, prices budget.PriceTable) error {
+	entries, err := os.ReadDir(dataPath)
+	if err != nil {
+		return err
+	}
+	models := make(map[string]bool)
+	unpriced := make(map[string]bool)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "logs") || !(strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".jsonl.gz")) {
+			continue
+		}
+		f, err := feed.OpenJSONL(filepath.Join(dataPath, name))
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var ev simulation.EventLog
+			if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.Action == simulation.ActionHeartbeat {
+				continue
+			}
+			s.Turns++
+			if ev.ModelName != "" {
+				models[ev.ModelName] = true
+			}
+			s.Calls += ev.UsageEvents
+			s.PromptTokens += ev.PromptTokens
+			s.OutputTokens += ev.CandidatesTokens + ev.ThoughtsTokens
+			s.TotalTokens += ev.TotalTokens
+			if ev.TotalTokens == 0 || prices == nil {
+				continue
+			}
+			if p, ok := prices.Lookup(ev.ModelName); ok {
+				s.CostUSD += p.Cost(ev.PromptTokens, ev.CachedContentTokens, ev.ToolUsePromptTokens, ev.CandidatesTokens, ev.ThoughtsTokens)
+			} else {
+				unpriced[ev.ModelName] = true
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	s.Models = sortedSet(models)
+	s.Unpriced = sortedSet(unpriced)
+	return nil
+}
+
+func sortedSet(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,301 @@
+// Command cost_report sums the token usage recorded in a run's logs*.jsonl
+// per agent, model and simulated day, and prices it with a table of
+// per-model rates. Output is a text summary, CSV or JSON.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/budget"
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+)
+
+// usage is the token usage and cost of one breakdown row.
+type usage struct {
+	Calls               int     `json:"calls"`
+	PromptTokens        int     `json:"prompt_tokens"`
+	CandidatesTokens    int     `json:"candidates_tokens"`
+	ThoughtsTokens      int     `json:"thoughts_tokens"`
+	ToolUsePromptTokens int     `json:"tool_use_prompt_tokens"`
+	CachedTokens        int     `json:"cached_tokens"`
+	TotalTokens         int     `json:"total_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+}
+
+func (u *usage) add(o usage) {
+	u.Calls += o.Calls
+	u.PromptTokens += o.PromptTokens
+	u.CandidatesTokens += o.CandidatesTokens
+	u.ThoughtsTokens += o.ThoughtsTokens
+	u.ToolUsePromptTokens += o.ToolUsePromptTokens
+	u.CachedTokens += o.CachedTokens
+	u.TotalTokens += o.TotalTokens
+	u.CostUSD += o.CostUSD
+}
+
+// row is the usage of one agent with one model on one simulated day.
+type row struct {
+	Day       string `json:"day"`
+	AgentID   string `json:"agent_id"`
+	AgentName string `json:"agent_name"`
+	Model     string `json:"model"`
+	usage
+}
+
+type report struct {
+	Rows    []*row            `json:"rows"`
+	ByAgent map[string]*usage `json:"by_agent"`
+	ByModel map[string]*usage `json:"by_model"`
+	ByDay   map[string]*usage `json:"by_day"`
+	Total   usage             `json:"total"`
+	// Unpriced lists models without an entry in the price table; their
+	// cost is counted as 0.
+	Unpriced []string `json:"unpriced,omitempty"`
+}
+
+func main() {
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory containing logs*.jsonl")
+	pricesPath := flag.String("prices", "", "Price table (JSON): {\"<model>\": {\"input\": 0.3, \"output\": 2.5, \"cached\": 0.075}} in USD per million tokens; \"<prefix>*\" keys match model families and \"*\" any model (empty reports tokens only)")
+	format := flag.String("format", "text", "Output format: text, csv or json")
+	outPath := flag.String("out", "", "Write the report to this file instead of stdout")
+	flag.Parse()
+
+	var prices budget.PriceTable
+	if strings.TrimSpace(*pricesPath) != "" {
+		var err error
+		if prices, err = budget.LoadPrices(*pricesPath); err != nil {
+			log.Fatalf("Load prices: %v", err)
+		}
+	}
+
+	logNames := discoverLogs(*dataPath)
+	if len(logNames) == 0 {
+		log.Fatalf("No logs*.jsonl in %s", *dataPath)
+	}
+	rp, err := buildReport(*dataPath, logNames, prices)
+	if err != nil {
+		log.Fatalf("Read logs: %v", err)
+	}
+	if len(rp.Unpriced) > 0 {
+		log.Printf("No price for %s; counted as 0", strings.Join(rp.Unpriced, ", "))
+	}
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Create %s: %v", *outPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	switch strings.ToLower(strings.TrimSpace(*format)) {
+	case "text", "":
+		rp.writeText(out)
+	case "csv":
+		err = rp.writeCSV(out)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rp)
+	default:
+		log.Fatalf("Unknown -format %q (use text, csv or json)", *format)
+	}
+	if err != nil {
+		log.Fatalf("Write report: %v", err)
+	}
+}
+
+// discoverLogs lists logs*.jsonl in dataPath, archived ones included.
+func discoverLogs(dataPath string) []string {
+	entries, err := os.ReadDir(dataPath)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(strings.TrimSuffix(name, feed.GzipExt), ".jsonl") {
+			continue
+		}
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// buildReport sums the usage of every event in the named logs. Events
+// without token usage (heartbeats, providers that report none) are skipped.
+func buildReport(dataPath string, names []string, prices budget.PriceTable) (*report, error) {
+	rows := make(map[[3]string]*row)
+	unpriced := make(map[string]bool)
+	for _, name := range names {
+		f, err := feed.OpenJSONL(filepath.Join(dataPath, name))
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var ev simulation.EventLog
+			if err := json.Unmarshal([]byte(line), &ev); err != nil {
+				continue
+			}
+			if ev.UsageEvents == 0 && ev.TotalTokens == 0 {
+				continue
+			}
+			model := ev.ModelName
+			if model == "" {
+				model = "unknown"
+			}
+			day := ev.SimTime.Format("2006-01-02")
+			key := [3]string{day, ev.AgentID, model}
+			r := rows[key]
+			if r == nil {
+				r = &row{Day: day, AgentID: ev.AgentID, AgentName: ev.AgentName, Model: model}
+				rows[key] = r
+			}
+			u := usage{
+				Calls:               ev.UsageEvents,
+				PromptTokens:        ev.PromptTokens,
+				CandidatesTokens:    ev.CandidatesTokens,
+				ThoughtsTokens:      ev.ThoughtsTokens,
+				ToolUsePromptTokens: ev.ToolUsePromptTokens,
+				CachedTokens:        ev.CachedContentTokens,
+				TotalTokens:         ev.TotalTokens,
+			}
+			if p, ok := prices.Lookup(model); ok {
+				u.CostUSD = p.Cost(u.PromptTokens, u.CachedTokens, u.ToolUsePromptTokens, u.CandidatesTokens, u.ThoughtsTokens)
+			} else if prices != nil {
+				unpriced[model] = true
+			}
+			r.add(u)
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	rp := &report{
+		Rows:    make([]*row, 0, len(rows)),
+		ByAgent: make(map[string]*usage),
+		ByModel: make(map[string]*usage),
+		ByDay:   make(map[string]*usage),
+	}
+	for _, r := range rows {
+		rp.Rows = append(rp.Rows, r)
+		for _, group := range []struct {
+			m   map[string]*usage
+			key string
+		}{{rp.ByAgent, r.AgentID}, {rp.ByModel, r.Model}, {rp.ByDay, r.Day}} {
+			if group.m[group.key] == nil {
+				group.m[group.key] = &usage{}
+			}
+			group.m[group.key].add(r.usage)
+		}
+		rp.Total.add(r.usage)
+	}
+	sort.Slice(rp.Rows, func(i, j int) bool {
+		a, b := rp.Rows[i], rp.Rows[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.AgentID != b.AgentID {
+			return a.AgentID < b.AgentID
+		}
+		return a.Model < b.Model
+	})
+	for model := range unpriced {
+		rp.Unpriced = append(rp.Unpriced, model)
+	}
+	sort.Strings(rp.Unpriced)
+	return rp, nil
+}
+
+var csvHeader = []string{"day", "agent_id", "agent_name", "model", "calls", "prompt_tokens", "candidates_tokens",
+	"thoughts_tokens", "tool_use_prompt_tokens", "cached_tokens", "total_tokens", "cost_usd"}
+
+// writeCSV writes one line per day, agent and model.
+func (rp *report) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range rp.Rows {
+		record := []string{r.Day, r.AgentID, r.AgentName, r.Model}
+		for _, n := range []int{r.Calls, r.PromptTokens, r.CandidatesTokens, r.ThoughtsTokens,
+			r.ToolUsePromptTokens, r.CachedTokens, r.TotalTokens} {
+			record = append(record, strconv.Itoa(n))
+		}
+		record = append(record, strconv.FormatFloat(r.CostUSD, 'f', 6, 64))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeText prints the totals by agent, model and day.
+func (rp *report) writeText(w io.Writer) {
+	names := make(map[string]string)
+	for _, r := range rp.Rows {
+		names[r.AgentID] = r.AgentName
+	}
+	fmt.Fprintf(w, "Total: %d tokens in %d calls (prompt=%d, candidates=%d, thoughts=%d, cached=%d), $%.4f\n",
+		rp.Total.TotalTokens, rp.Total.Calls, rp.Total.PromptTokens, rp.Total.CandidatesTokens,
+		rp.Total.ThoughtsTokens, rp.Total.CachedTokens, rp.Total.CostUSD)
+	for _, section := range []struct {
+		title string
+		m     map[string]*usage
+		label func(string) string
+	}{
+		{"By agent", rp.ByAgent, func(id string) string {
+			if name := names[id]; name != "" && name != id {
+				return id + " (" + name + ")"
+			}
+			return id
+		}},
+		{"By model", rp.ByModel, nil},
+		{"By day", rp.ByDay, nil},
+	} {
+		fmt.Fprintf(w, "\n%s:\n", section.title)
+		keys := make([]string, 0, len(section.m))
+		for k := range section.m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			label := k
+			if section.label != nil {
+				label = section.label(k)
+			}
+			u := section.m[k]
+			fmt.Fprintf(w, "  %-32s %12d tokens %8d calls  $%.4f\n", label, u.TotalTokens, u.Calls, u.CostUSD)
+		}
+	}
+	if len(rp.Unpriced) > 0 {
+		fmt.Fprintf(w, "\nUnpriced models (cost 0): %s\n", strings.Join(rp.Unpriced, ", "))
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/budget"
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+)
+
+func writeLog(t *testing.T, path string, events []simulation.EventLog, extra ...string) {
+	t.Helper()
+	var b strings.Builder
+	for _, ev := range events {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	for _, line := range extra {
+		b.WriteString(line + "\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildReport_SumsCost(t *testing.T) {
+	dataPath := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	writeLog(t, filepath.Join(dataPath, "logs-20260301.jsonl"), []simulation.EventLog{
+		{SimTime: day1, AgentID: "agent-1", AgentName: "Ada", ModelName: "gemini-flash", UsageEvents: 2,
+			PromptTokens: 1000, CachedContentTokens: 200, CandidatesTokens: 500, ThoughtsTokens: 100, TotalTokens: 1600},
+		{SimTime: day1, AgentID: "agent-2", AgentName: "Bo", ModelName: "gemini-pro", UsageEvents: 1,
+			PromptTokens: 2000, CandidatesTokens: 1000, TotalTokens: 3000},
+		{SimTime: day1, AgentID: "agent-2", AgentName: "Bo", Action: simulation.ActionHeartbeat},
+	}, "not json")
+	writeLog(t, filepath.Join(dataPath, "logs.jsonl"), []simulation.EventLog{
+		{SimTime: day2, AgentID: "agent-1", AgentName: "Ada", ModelName: "gemini-flash", UsageEvents: 1,
+			PromptTokens: 1000000, TotalTokens: 1000000},
+		{SimTime: day2, AgentID: "agent-2", AgentName: "Bo", ModelName: "mystery", UsageEvents: 1, TotalTokens: 10},
+	})
+	writeLog(t, filepath.Join(dataPath, "other.jsonl"), []simulation.EventLog{
+		{SimTime: day2, AgentID: "agent-3", ModelName: "gemini-pro", UsageEvents: 1, PromptTokens: 5, TotalTokens: 5},
+	})
+	prices := budget.PriceTable{
+		"gemini-flash": {Input: 0.3, Output: 2.5, Cached: 0.075},
+		"gemini-pro":   {Input: 1.25, Output: 10},
+	}
+
+	names := discoverLogs(dataPath)
+	if want := []string{"logs-20260301.jsonl", "logs.jsonl"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("discoverLogs = %v, want %v", names, want)
+	}
+	rp, err := buildReport(dataPath, names, prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// flash day 1: (800*0.3 + 200*0.075 + 600*2.5) / 1e6 = 0.001755
+	// pro day 1:   (2000*1.25 + 1000*10) / 1e6           = 0.0125
+	// flash day 2: 1e6*0.3 / 1e6                          = 0.3
+	costs := map[string]float64{
+		"total":        0.314255,
+		"agent-1":      0.301755,
+		"agent-2":      0.0125,
+		"gemini-flash": 0.301755,
+		"gemini-pro":   0.0125,
+		"mystery":      0,
+		"2026-03-01":   0.014255,
+		"2026-03-02":   0.3,
+	}
+	got := map[string]float64{"total": rp.Total.CostUSD}
+	for _, m := range []map[string]*usage{rp.ByAgent, rp.ByModel, rp.ByDay} {
+		for k, u := range m {
+			got[k] = u.CostUSD
+		}
+	}
+	if len(got) != len(costs) {
+		t.Errorf("breakdown keys = %v, want %v", got, costs)
+	}
+	for k, want := range costs {
+		if math.Abs(got[k]-want) > 1e-9 {
+			t.Errorf("cost[%s] = %.9f, want %.9f", k, got[k], want)
+		}
+	}
+
+	if rp.Total.Calls != 5 || rp.Total.TotalTokens != 1004610 || rp.Total.PromptTokens != 1003000 || rp.Total.CachedTokens != 200 {
+		t.Errorf("total = %+v", rp.Total)
+	}
+	if len(rp.Rows) != 4 || rp.Rows[0].Day != "2026-03-01" || rp.Rows[0].AgentID != "agent-1" {
+		t.Errorf("rows = %+v", rp.Rows)
+	}
+	if want := []string{"mystery"}; !reflect.DeepEqual(rp.Unpriced, want) {
+		t.Errorf("unpriced = %v, want %v", rp.Unpriced, want)
+	}
+}
+
+func TestBuildReport_SameAfterCompaction(t *testing.T) {
+	dataPath := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	writeLog(t, filepath.Join(dataPath, "logs-20260301.jsonl"), []simulation.EventLog{
+		{SimTime: day1, AgentID: "agent-1", AgentName: "Ada", ModelName: "gemini-flash", UsageEvents: 2,
+			PromptTokens: 1000, CandidatesTokens: 500, TotalTokens: 1500},
+	})
+	writeLog(t, filepath.Join(dataPath, "logs.jsonl"), []simulation.EventLog{
+		{SimTime: day1.Add(24 * time.Hour), AgentID: "agent-2", AgentName: "Bo", ModelName: "gemini-flash", UsageEvents: 1,
+			PromptTokens: 2000, TotalTokens: 2000},
+	})
+	prices := budget.PriceTable{"gemini-flash": {Input: 0.3, Output: 2.5}}
+
+	before, err := buildReport(dataPath, discoverLogs(dataPath), prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := feed.CompressFile(filepath.Join(dataPath, "logs-20260301.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	names := discoverLogs(dataPath)
+	if want := []string{"logs-20260301.jsonl.gz", "logs.jsonl"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("discoverLogs after compaction = %v, want %v", names, want)
+	}
+	after, err := buildReport(dataPath, names, prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Total.Calls != 3 || !reflect.DeepEqual(before, after) {
+		t.Errorf("report after compaction = %+v, want %+v", after.Total, before.Total)
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/cpunion/sci-bot/pkg/agent"
+	"github.com/cpunion/sci-bot/pkg/idgen"
 	"github.com/cpunion/sci-bot/pkg/knowledge"
 	"github.com/cpunion/sci-bot/pkg/llm"
 	"github.com/cpunion/sci-bot/pkg/network"
@@ -26,7 +29,9 @@ func main() {
 	fmt.Println("=== Sci-Bot Network Demo with Gemini ===")
 	fmt.Println()
 
-	ctx := context.Background()
+	// Ctrl-C ends the interactive loop and shuts the agents down.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
 	// Setup data directory
 	dataPath := "./data"
@@ -57,7 +62,7 @@ func main() {
 	agents := createDiverseAgents(dataPath, gemini)
 	for _, a := range agents {
 		broker.Register(a)
-		if err := a.Start(); err != nil {
+		if err := a.Start(ctx); err != nil {
 			log.Printf("Failed to start agent %s: %v", a.ID(), err)
 		}
 	}
@@ -89,19 +94,27 @@ func main() {
 	fmt.Println("  /quit                    - Exit")
 	fmt.Println()
 
-	scanner := bufio.NewScanner(os.Stdin)
+	lines := readLines(os.Stdin)
+loop:
 	for {
 		fmt.Print("> ")
-		if !scanner.Scan() {
-			break
+		var line string
+		select {
+		case <-ctx.Done():
+			break loop
+		case l, ok := <-lines:
+			if !ok {
+				break loop
+			}
+			line = l
 		}
-		input := strings.TrimSpace(scanner.Text())
+		input := strings.TrimSpace(line)
 		if input == "" {
 			continue
 		}
 
 		if input == "/quit" || input == "/exit" {
-			break
+			break loop
 		}
 
 		if input == "/list" {
@@ -144,7 +157,7 @@ func main() {
 
 			// Send message directly to agent
 			msg := &types.Message{
-				ID:         fmt.Sprintf("user-%d", time.Now().UnixNano()),
+				ID:         idgen.New("user"),
 				Type:       types.MsgQuestion,
 				From:       "user",
 				To:         []string{targetAgent.ID()},
@@ -153,23 +166,14 @@ func main() {
 				Timestamp:  time.Now(),
 			}
 
-			targetAgent.Inbox <- msg
-			fmt.Printf("Sent to %s. Waiting for response...\n", targetAgent.Name())
-
-			// Wait for response
-			select {
-			case resp := <-targetAgent.Outbox:
-				fmt.Printf("\n[%s]: %s\n\n", targetAgent.Name(), resp.Content)
-			case <-time.After(30 * time.Second):
-				fmt.Println("Response timeout")
-			}
+			ask(ctx, targetAgent, msg)
 			continue
 		}
 
 		if strings.HasPrefix(input, "/broadcast ") {
 			message := input[11:]
 			msg := &types.Message{
-				ID:         fmt.Sprintf("user-%d", time.Now().UnixNano()),
+				ID:         idgen.New("user"),
 				Type:       types.MsgChat,
 				From:       "user",
 				To:         []string{},
@@ -186,7 +190,7 @@ func main() {
 		if strings.HasPrefix(input, "/theory ") {
 			title := input[8:]
 			theory := &types.Theory{
-				ID:          fmt.Sprintf("theory-%d", time.Now().UnixNano()),
+				ID:          idgen.New("theory"),
 				Title:       title,
 				Authors:     []string{"user"},
 				Status:      types.StatusDraft,
@@ -204,7 +208,7 @@ func main() {
 		for _, a := range agents {
 			if a.Role() == types.RoleExplorer {
 				msg := &types.Message{
-					ID:         fmt.Sprintf("user-%d", time.Now().UnixNano()),
+					ID:         idgen.New("user"),
 					Type:       types.MsgQuestion,
 					From:       "user",
 					To:         []string{a.ID()},
@@ -212,21 +216,14 @@ func main() {
 					Visibility: types.VisibilityPublic,
 					Timestamp:  time.Now(),
 				}
-				a.Inbox <- msg
-				fmt.Printf("Sent to %s (explorer). Waiting for response...\n", a.Name())
-
-				select {
-				case resp := <-a.Outbox:
-					fmt.Printf("\n[%s]: %s\n\n", a.Name(), resp.Content)
-				case <-time.After(30 * time.Second):
-					fmt.Println("Response timeout")
-				}
+				ask(ctx, a, msg)
 				break
 			}
 		}
 	}
 
-	// Cleanup
+	// Cleanup: Stop waits for each agent's loop and unregisters it from the
+	// broker, dropping its subscriptions.
 	fmt.Println("\nStopping agents...")
 	for _, a := range agents {
 		if err := a.Stop(); err != nil {
@@ -236,6 +233,39 @@ func main() {
 	fmt.Println("Done.")
 }
 
+// readLines feeds r's lines into the returned channel, closing it at EOF, so
+// the interactive loop can also wait on ctx. The reader goroutine ends with
+// the process if it is blocked on a terminal.
+func readLines(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// ask sends msg to a and prints its reply, giving up after 30 seconds or when
+// ctx is done.
+func ask(ctx context.Context, a *agent.Agent, msg *types.Message) {
+	if err := a.Deliver(ctx, msg); err != nil {
+		fmt.Printf("Failed to send to %s: %v\n", a.Name(), err)
+		return
+	}
+	fmt.Printf("Sent to %s (%s). Waiting for response...\n", a.Name(), a.Role())
+
+	select {
+	case resp := <-a.Outbox:
+		fmt.Printf("\n[%s]: %s\n\n", a.Name(), resp.Content)
+	case <-time.After(30 * time.Second):
+		fmt.Println("Response timeout")
+	case <-ctx.Done():
+	}
+}
+
 // createDiverseAgents creates a diverse set of agents with Gemini.
 func createDiverseAgents(dataPath string, llmProvider agent.LLMProvider) []*agent.Agent {
 	personas := []*types.Persona{
@@ -289,57 +319,12 @@ func createDiverseAgents(dataPath string, llmProvider agent.LLMProvider) []*agen
 
 // setupDefaultAxiomSystems creates some default axiom systems.
 func setupDefaultAxiomSystems(reg *knowledge.AxiomRegistry) error {
-	systems := []*types.AxiomSystem{
-		{
-			ID:          "euclidean-geometry",
-			Name:        "欧几里得几何公理体系",
-			Description: "经典平面几何的公理体系",
-			Axioms: []types.Axiom{
-				{ID: "e1", Statement: "两点确定一条直线"},
-				{ID: "e2", Statement: "线段可以无限延长"},
-				{ID: "e3", Statement: "以任意点为圆心、任意长度为半径可以画圆"},
-				{ID: "e4", Statement: "所有直角都相等"},
-				{ID: "e5", Statement: "平行公设：过直线外一点有且仅有一条平行线"},
-			},
-			CreatedBy: "system",
-			CreatedAt: time.Now(),
-		},
-		{
-			ID:          "hyperbolic-geometry",
-			Name:        "双曲几何公理体系",
-			Description: "罗巴切夫斯基几何，否定第五公设",
-			Axioms: []types.Axiom{
-				{ID: "h1", Statement: "两点确定一条直线"},
-				{ID: "h2", Statement: "线段可以无限延长"},
-				{ID: "h3", Statement: "以任意点为圆心、任意长度为半径可以画圆"},
-				{ID: "h4", Statement: "所有直角都相等"},
-				{ID: "h5", Statement: "过直线外一点有无穷多条平行线"},
-			},
-			Parent:      "euclidean-geometry",
-			Differences: []string{"修改第五公设：允许无穷多平行线"},
-			CreatedBy:   "system",
-			CreatedAt:   time.Now(),
-		},
-		{
-			ID:          "zfc-set-theory",
-			Name:        "ZFC 集合论公理体系",
-			Description: "策梅洛-弗兰克尔集合论（含选择公理）",
-			Axioms: []types.Axiom{
-				{ID: "zfc1", Statement: "外延公理：两个集合相等当且仅当它们有相同的元素"},
-				{ID: "zfc2", Statement: "空集公理：存在一个不包含任何元素的集合"},
-				{ID: "zfc3", Statement: "配对公理：对于任意两个集合，存在一个恰好包含它们作为元素的集合"},
-			},
-			CreatedBy: "system",
-			CreatedAt: time.Now(),
-		},
-	}
-
-	for _, sys := range systems {
-		_ = reg.Register(sys) // Ignore already exists errors
+	if err := reg.RegisterDefaults(); err != nil {
+		return err
 	}
 
 	// Save as JSON for reference
-	data, _ := json.MarshalIndent(systems, "", "  ")
+	data, _ := json.MarshalIndent(reg.List(), "", "  ")
 	os.WriteFile("data/axiom_systems_reference.json", data, 0644)
 
 	return nil
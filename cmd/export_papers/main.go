@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// citationPattern matches publication IDs referenced in paper text
+// (journal papers, forum posts/comments, seed posts).
+var citationPattern = regexp.MustCompile(`\b(?:journal|forum|comment|seed)-[0-9A-Za-z_]+(?:-[0-9A-Za-z_]+)*`)
+
+// paper is one exported publication with everything its page needs.
+type paper struct {
+	pub       *types.Publication
+	file      string
+	journal   string // name of the journal that published it
+	reviews   []*types.PaperReview
+	sub       *types.Submission
+	citations []string
+	citedBy   []string
+}
+
+func main() {
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory (contains journal/, workflow/, forum/)")
+	outDir := flag.String("out", "./export/papers", "Output directory for the Markdown bundle")
+	title := flag.String("title", "", "Proceedings title for index.md (default: the configured journal names)")
+	withReviews := flag.Bool("reviews", true, "Include peer review comments in each paper")
+	flag.Parse()
+
+	journal := publication.NewJournal("", filepath.Join(*dataPath, "journal"))
+	if err := journal.Load(); err != nil {
+		log.Fatalf("Load journal: %v", err)
+	}
+	workflow := publication.NewWorkflow(filepath.Join(*dataPath, "workflow"))
+	if err := workflow.Load(); err != nil {
+		log.Printf("Load workflow: %v (exporting without reviews)", err)
+	}
+	forum := publication.NewForum("自由论坛", filepath.Join(*dataPath, "forum"))
+	if err := forum.Load(); err != nil {
+		log.Printf("Load forum: %v (forum citations will show IDs only)", err)
+	}
+
+	papers := collectPapers(journal, workflow)
+	if len(papers) == 0 {
+		fmt.Println("No approved papers to export.")
+		return
+	}
+
+	name := strings.TrimSpace(*title)
+	if name == "" {
+		name = defaultTitle(journal)
+	}
+	if err := writeBundle(*outDir, name, papers, forum, *withReviews); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Exported %d papers to %s\n", len(papers), *outDir)
+}
+
+// writeBundle writes one Markdown file per paper and an index.md into
+// outDir, linking each paper to the papers that cite it.
+func writeBundle(outDir, title string, papers []*paper, forum *publication.Forum, withReviews bool) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	byID := make(map[string]*paper, len(papers))
+	for _, p := range papers {
+		byID[p.pub.ID] = p
+	}
+	for _, p := range papers {
+		for _, id := range p.citations {
+			if cited, ok := byID[id]; ok {
+				cited.citedBy = append(cited.citedBy, p.pub.ID)
+			}
+		}
+	}
+
+	for _, p := range papers {
+		path := filepath.Join(outDir, p.file)
+		if err := os.WriteFile(path, []byte(renderPaper(p, byID, forum, withReviews)), 0644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	indexPath := filepath.Join(outDir, "index.md")
+	if err := os.WriteFile(indexPath, []byte(renderIndex(title, papers)), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", indexPath, err)
+	}
+	return nil
+}
+
+// defaultTitle names the bundle after the run's journals, in configuration
+// order.
+func defaultTitle(journal *publication.Journal) string {
+	var names []string
+	for _, info := range journal.JournalList() {
+		if name := strings.TrimSpace(info.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "Proceedings"
+	}
+	return strings.Join(names, ", ")
+}
+
+// journalName returns the name of the journal pub appeared in, or its ID
+// when the journal is no longer configured.
+func journalName(journal *publication.Journal, pub *types.Publication) string {
+	id := journal.JournalOf(pub)
+	if info, ok := journal.JournalInfo(id); ok && info.Name != "" {
+		return info.Name
+	}
+	return id
+}
+
+// collectPapers returns approved publications, oldest first, with their
+// reviews and cited IDs attached.
+func collectPapers(journal *publication.Journal, workflow *publication.Workflow) []*paper {
+	approved := journal.GetApproved()
+	sort.Slice(approved, func(i, j int) bool {
+		if approved[i].PublishedAt.Equal(approved[j].PublishedAt) {
+			return approved[i].ID < approved[j].ID
+		}
+		return approved[i].PublishedAt.Before(approved[j].PublishedAt)
+	})
+
+	papers := make([]*paper, 0, len(approved))
+	for _, pub := range approved {
+		p := &paper{
+			pub:       pub,
+			file:      fileName(pub.ID),
+			journal:   journalName(journal, pub),
+			reviews:   workflow.GetReviews(pub.ID),
+			sub:       workflow.GetSubmission(pub.ID),
+			citations: extractCitations(pub),
+		}
+		sort.Slice(p.reviews, func(i, j int) bool { return p.reviews[i].CreatedAt.Before(p.reviews[j].CreatedAt) })
+		papers = append(papers, p)
+	}
+	return papers
+}
+
+func extractCitations(pub *types.Publication) []string {
+	seen := map[string]bool{pub.ID: true}
+	out := make([]string, 0)
+	for _, text := range []string{pub.Abstract, pub.Content} {
+		for _, id := range citationPattern.FindAllString(text, -1) {
+			if !seen[id] {
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+	}
+	if pub.ResubmissionOf != "" && !seen[pub.ResubmissionOf] {
+		out = append(out, pub.ResubmissionOf)
+	}
+	return out
+}
+
+func fileName(id string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(id) + ".md"
+}
+
+func renderPaper(p *paper, byID map[string]*paper, forum *publication.Forum, withReviews bool) string {
+	pub := p.pub
+	var b strings.Builder
+
+	// Front matter: title/author/date/abstract are the keys pandoc understands.
+	b.WriteString("---\n")
+	writeYAML(&b, "title", pub.Title)
+	writeYAMLList(&b, "author", []string{pub.AuthorName})
+	writeYAML(&b, "author_id", pub.AuthorID)
+	writeYAML(&b, "id", pub.ID)
+	if p.journal != "" {
+		writeYAML(&b, "journal", p.journal)
+	}
+	if !pub.PublishedAt.IsZero() {
+		writeYAML(&b, "date", pub.PublishedAt.Format("2006-01-02"))
+	}
+	if pub.Abstract != "" {
+		writeYAML(&b, "abstract", pub.Abstract)
+	}
+	writeYAMLList(&b, "reviewers", reviewerNames(p))
+	if scores, n := averageScores(p.reviews); n > 0 {
+		fmt.Fprintf(&b, "scores:\n  novelty: %.2f\n  rigor: %.2f\n  falsifiability: %.2f\n  reproducibility: %.2f\n  cross_domain: %.2f\n",
+			scores.Novelty, scores.Rigor, scores.Falsifiability, scores.Reproducibility, scores.CrossDomain)
+	}
+	if p.sub != nil && p.sub.CycleID != "" {
+		writeYAML(&b, "review_cycle", p.sub.CycleID)
+	}
+	if pub.ResubmissionOf != "" {
+		writeYAML(&b, "resubmission_of", pub.ResubmissionOf)
+	}
+	writeYAMLList(&b, "citations", p.citations)
+	writeYAMLList(&b, "cited_by", p.citedBy)
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n\n", pub.Title)
+	fmt.Fprintf(&b, "**%s**", pub.AuthorName)
+	if !pub.PublishedAt.IsZero() {
+		fmt.Fprintf(&b, " · %s", pub.PublishedAt.Format("2006-01-02"))
+	}
+	b.WriteString("\n\n")
+	if pub.Abstract != "" {
+		fmt.Fprintf(&b, "## Abstract\n\n%s\n\n", strings.TrimSpace(pub.Abstract))
+	}
+	b.WriteString(strings.TrimSpace(pub.Content))
+	b.WriteString("\n")
+
+	if len(p.citations) > 0 {
+		b.WriteString("\n## Cited Publications\n\n")
+		for _, id := range p.citations {
+			fmt.Fprintf(&b, "- %s\n", citationLink(id, byID, forum))
+		}
+	}
+	if len(p.citedBy) > 0 {
+		b.WriteString("\n## Cited By\n\n")
+		for _, id := range p.citedBy {
+			fmt.Fprintf(&b, "- %s\n", citationLink(id, byID, forum))
+		}
+	}
+
+	if withReviews && len(p.reviews) > 0 {
+		b.WriteString("\n## Peer Reviews\n")
+		for _, r := range p.reviews {
+			name := r.ReviewerName
+			if name == "" {
+				name = r.ReviewerID
+			}
+			fmt.Fprintf(&b, "\n### %s (%s)\n\n", name, r.Verdict)
+			fmt.Fprintf(&b, "| Novelty | Rigor | Falsifiability | Reproducibility | Cross-domain |\n|---|---|---|---|---|\n| %.1f | %.1f | %.1f | %.1f | %.1f |\n",
+				r.Scores.Novelty, r.Scores.Rigor, r.Scores.Falsifiability, r.Scores.Reproducibility, r.Scores.CrossDomain)
+			if c := strings.TrimSpace(r.Comments); c != "" {
+				fmt.Fprintf(&b, "\n%s\n", c)
+			}
+		}
+	}
+	return b.String()
+}
+
+func citationLink(id string, byID map[string]*paper, forum *publication.Forum) string {
+	if p, ok := byID[id]; ok {
+		return fmt.Sprintf("[%s](%s) — %s", p.pub.Title, p.file, p.pub.AuthorName)
+	}
+	if post := forum.Get(id); post != nil {
+		label := post.Title
+		if label == "" {
+			label = truncate(post.Content, 80)
+		}
+		return fmt.Sprintf("%s — %s (forum `%s`)", label, post.AuthorName, id)
+	}
+	return fmt.Sprintf("`%s`", id)
+}
+
+func renderIndex(title string, papers []*paper) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	writeYAML(&b, "title", title)
+	writeYAML(&b, "date", time.Now().Format("2006-01-02"))
+	fmt.Fprintf(&b, "papers: %d\n", len(papers))
+	b.WriteString("---\n\n")
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for _, p := range papers {
+		fmt.Fprintf(&b, "- [%s](%s) — %s", p.pub.Title, p.file, p.pub.AuthorName)
+		if !p.pub.PublishedAt.IsZero() {
+			fmt.Fprintf(&b, " (%s)", p.pub.PublishedAt.Format("2006-01-02"))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func reviewerNames(p *paper) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, r := range p.reviews {
+		name := r.ReviewerName
+		if name == "" {
+			name = r.ReviewerID
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		// Papers approved without workflow reviews only carry reviewer IDs.
+		for _, id := range p.pub.Reviewers {
+			if !seen[id] {
+				seen[id] = true
+				names = append(names, id)
+			}
+		}
+	}
+	return names
+}
+
+func averageScores(reviews []*types.PaperReview) (types.PaperReviewScores, int) {
+	var sum types.PaperReviewScores
+	for _, r := range reviews {
+		sum.Novelty += r.Scores.Novelty
+		sum.Rigor += r.Scores.Rigor
+		sum.Falsifiability += r.Scores.Falsifiability
+		sum.Reproducibility += r.Scores.Reproducibility
+		sum.CrossDomain += r.Scores.CrossDomain
+	}
+	n := len(reviews)
+	if n == 0 {
+		return sum, 0
+	}
+	f := float64(n)
+	return types.PaperReviewScores{
+		Novelty:         sum.Novelty / f,
+		Rigor:           sum.Rigor / f,
+		Falsifiability:  sum.Falsifiability / f,
+		Reproducibility: sum.Reproducibility / f,
+		CrossDomain:     sum.CrossDomain / f,
+	}, n
+}
+
+// writeYAML writes a scalar; JSON string quoting is valid YAML.
+func writeYAML(b *strings.Builder, key, value string) {
+	q, _ := json.Marshal(value)
+	fmt.Fprintf(b, "%s: %s\n", key, q)
+}
+
+func writeYAMLList(b *strings.Builder, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", key)
+	for _, v := range values {
+		q, _ := json.Marshal(v)
+		fmt.Fprintf(b, "  - %s\n", q)
+	}
+}
+
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max]) + "..."
+}
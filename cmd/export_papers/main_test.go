@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestExport_ApprovedPapersOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	journal := publication.NewJournal("J", filepath.Join(tempDir, "journal"))
+	workflow := publication.NewWorkflow(filepath.Join(tempDir, "workflow"))
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+
+	for _, pub := range []*types.Publication{
+		{ID: "journal-a", AuthorID: "agent-1", AuthorName: "Ada", Title: "Tired light", Abstract: "Redshift \"without\" expansion.", Content: "Body of A."},
+		{ID: "journal-b", AuthorID: "agent-2", AuthorName: "Bo", Title: "Against tired light", Content: "Builds on journal-a."},
+		{ID: "journal-pending", AuthorID: "agent-1", AuthorName: "Ada", Title: "Still in review", Content: "Cites journal-a."},
+		{ID: "journal-rejected", AuthorID: "agent-2", AuthorName: "Bo", Title: "Perpetual motion", Content: "Cites journal-a."},
+	} {
+		if err := journal.Submit(pub); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range []string{"journal-a", "journal-b"} {
+		if err := journal.Approve(id, "editor"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := journal.Reject("journal-rejected", "editor"); err != nil {
+		t.Fatal(err)
+	}
+	workflow.AddSubmission(&types.Submission{ID: "journal-a", AuthorID: "agent-1", Title: "Tired light"})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "journal-a", ReviewerID: "agent-2", ReviewerName: "Bo",
+		Verdict: types.VerdictAccept, Comments: "Convincing.",
+		Scores: types.PaperReviewScores{Novelty: 8, Rigor: 7, Falsifiability: 6, Reproducibility: 5, CrossDomain: 4}})
+
+	papers := collectPapers(journal, workflow)
+	outDir := filepath.Join(tempDir, "out")
+	if err := writeBundle(outDir, "Proceedings", papers, forum, true); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, e := range entries {
+		files = append(files, e.Name())
+	}
+	if want := []string{"index.md", "journal-a.md", "journal-b.md"}; !reflect.DeepEqual(files, want) {
+		t.Fatalf("exported %v, want %v", files, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, "journal-a.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := journal.Get("journal-a").PublishedAt.Format("2006-01-02")
+	wantFront := `---
+title: "Tired light"
+author:
+  - "Ada"
+author_id: "agent-1"
+id: "journal-a"
+journal: "J"
+date: "` + date + `"
+abstract: "Redshift \"without\" expansion."
+reviewers:
+  - "Bo"
+scores:
+  novelty: 8.00
+  rigor: 7.00
+  falsifiability: 6.00
+  reproducibility: 5.00
+  cross_domain: 4.00
+cited_by:
+  - "journal-b"
+---
+
+# Tired light
+`
+	got := string(data)
+	if !strings.HasPrefix(got, wantFront) {
+		t.Fatalf("front matter:\n%s\nwant prefix:\n%s", got, wantFront)
+	}
+	for _, want := range []string{"## Abstract\n\nRedshift \"without\" expansion.", "Body of A.",
+		"## Cited By\n\n- [Against tired light](journal-b.md) — Bo", "### Bo (accept)", "| 8.0 | 7.0 | 6.0 | 5.0 | 4.0 |", "Convincing."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("journal-a.md missing %q:\n%s", want, got)
+		}
+	}
+
+	index, err := os.ReadFile(filepath.Join(outDir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, absent := range []string{"Still in review", "Perpetual motion"} {
+		if strings.Contains(string(index), absent) {
+			t.Errorf("index lists unapproved paper %q:\n%s", absent, index)
+		}
+	}
+	if !strings.Contains(string(index), "papers: 2\n") {
+		t.Errorf("index does not count 2 papers:\n%s", index)
+	}
+}
+
+func TestExport_JournalNames(t *testing.T) {
+	tempDir := t.TempDir()
+	journal := publication.NewJournal("科学前沿", filepath.Join(tempDir, "journal"))
+	if err := journal.SetJournals([]*types.JournalInfo{
+		{ID: "physics", Name: "Physical Review"},
+		{ID: "bio", Name: "Living Systems"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, pub := range []*types.Publication{
+		{ID: "journal-a", AuthorID: "agent-1", AuthorName: "Ada", Title: "Tired light"},
+		{ID: "journal-b", AuthorID: "agent-2", AuthorName: "Bo", Title: "Cell clocks", JournalID: "bio"},
+		{ID: "journal-c", AuthorID: "agent-2", AuthorName: "Bo", Title: "Orphan", JournalID: "retired"},
+	} {
+		if err := journal.Submit(pub); err != nil {
+			t.Fatal(err)
+		}
+		if err := journal.Approve(pub.ID, "editor"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, want := defaultTitle(journal), "Physical Review, Living Systems"; got != want {
+		t.Errorf("defaultTitle = %q, want %q", got, want)
+	}
+	if got := defaultTitle(publication.NewJournal("", filepath.Join(tempDir, "empty"))); got != "Proceedings" {
+		t.Errorf("defaultTitle without a name = %q, want Proceedings", got)
+	}
+
+	papers := collectPapers(journal, publication.NewWorkflow(filepath.Join(tempDir, "workflow")))
+	got := make(map[string]string, len(papers))
+	for _, p := range papers {
+		got[p.pub.ID] = p.journal
+	}
+	want := map[string]string{"journal-a": "Physical Review", "journal-b": "Living Systems", "journal-c": "retired"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("journals = %v, want %v", got, want)
+	}
+	for _, p := range papers {
+		if page := renderPaper(p, nil, publication.NewForum("F", ""), false); !strings.Contains(page, "\njournal: \""+want[p.pub.ID]+"\"\n") {
+			t.Errorf("%s front matter has no journal %q:\n%s", p.pub.ID, want[p.pub.ID], page)
+		}
+	}
+}
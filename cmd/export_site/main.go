@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/client"
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/site"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// dataDirs and dataFiles are the raw data the static pages read, relative
+// to the data root. Rebuild backups and agent sessions are left out.
+var (
+	dataDirs  = []string{"agents", "forum", "journal", "workflow", "feed"}
+	dataFiles = []string{"sim_state.json", "activity.json", "digest.json", "emergence.json"}
+)
+
+func main() {
+	serverURL := flag.String("server", "http://localhost:8061", "URL of a running cmd/server over the same data directory")
+	apiKey := flag.String("api-key", os.Getenv("SCI_BOT_API_KEY"), "API key of the server, if it requires one")
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory (run cmd/index_data on it first for site.json)")
+	webPath := flag.String("web", "./web", "Web assets directory")
+	outDir := flag.String("out", "./public", "Output directory")
+	feedPageSize := flag.Int("feed-page-size", 200, "Events per exported feed page")
+	pages := flag.Bool("pages", true, "Write paper/{id}/ and thread/{id}/ pages with Open Graph tags and JSON-LD")
+	baseURL := flag.String("base", "https://cpunion.github.io/sci-bot/", "Canonical base URL of the published site, for page metadata")
+	flag.Parse()
+
+	manifest, err := loadManifest(filepath.Join(*dataPath, "site.json"))
+	if err != nil {
+		log.Fatalf("Load manifest: %v (run cmd/index_data first)", err)
+	}
+	c, err := client.New(client.Config{BaseURL: *serverURL, APIKey: *apiKey})
+	if err != nil {
+		log.Fatalf("Client: %v", err)
+	}
+
+	if err := copyWeb(*webPath, *outDir); err != nil {
+		log.Fatalf("Copy web assets: %v", err)
+	}
+	outData := filepath.Join(*outDir, "data")
+	if err := copyData(*dataPath, outData); err != nil {
+		log.Fatalf("Copy data: %v", err)
+	}
+
+	e := &exporter{ctx: context.Background(), c: c, dir: filepath.Join(outData, "api")}
+	if *pages {
+		if e.pages, err = newPageWriter(*webPath, *outDir, *baseURL); err != nil {
+			log.Fatalf("Load page templates: %v", err)
+		}
+	}
+	idx, err := e.export(*feedPageSize)
+	if err != nil {
+		log.Fatalf("Export API: %v", err)
+	}
+	if err := e.write("index", idx); err != nil {
+		log.Fatalf("Write API index: %v", err)
+	}
+
+	manifest.APIPath = "api"
+	manifest.GeneratedAt = time.Now()
+	if err := site.WriteManifest(filepath.Join(outData, "site.json"), manifest); err != nil {
+		log.Fatalf("Write manifest: %v", err)
+	}
+
+	fmt.Printf("Exported %d agents, %d threads, %d papers, %d feed pages -> %s\n",
+		idx.Agents, idx.Threads, idx.Papers, idx.FeedPages, *outDir)
+}
+
+func loadManifest(path string) (site.Manifest, error) {
+	var m site.Manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// exporter writes server responses as files named after their routes.
+type exporter struct {
+	ctx   context.Context
+	c     *client.Client
+	dir   string
+	pages *pageWriter // nil: no per-item pages
+}
+
+// write stores payload as <dir>/<route>.json.
+func (e *exporter) write(route string, payload any) error {
+	path := filepath.Join(e.dir, filepath.FromSlash(route)+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (e *exporter) export(feedPageSize int) (*site.APIIndex, error) {
+	idx := &site.APIIndex{Version: 1, GeneratedAt: time.Now(), FeedPageSize: feedPageSize}
+
+	stats, err := e.c.Stats(e.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("stats: %w", err)
+	}
+	if err := e.write("stats", stats); err != nil {
+		return nil, err
+	}
+
+	if idx.Agents, err = e.exportAgents(); err != nil {
+		return nil, err
+	}
+	if idx.Threads, err = e.exportForum(); err != nil {
+		return nil, err
+	}
+	if idx.Papers, err = e.exportJournal(); err != nil {
+		return nil, err
+	}
+	if idx.FeedPages, err = e.exportFeed(feedPageSize); err != nil {
+		return nil, err
+	}
+	if idx.Groups, err = e.exportGroups(); err != nil {
+		return nil, err
+	}
+
+	graph, err := e.c.Graph(e.ctx, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("graph: %w", err)
+	}
+	if err := e.write("graph", graph); err != nil {
+		return nil, err
+	}
+	lineage, err := e.c.Lineage(e.ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("lineage: %w", err)
+	}
+	if err := e.write("lineage", lineage); err != nil {
+		return nil, err
+	}
+	experiments := []experiment.Experiment{}
+	for p := (client.Page{Limit: 200}); ; p.Offset += p.Limit {
+		page, err := e.c.Experiments(e.ctx, "", p)
+		if err != nil {
+			return nil, fmt.Errorf("experiments: %w", err)
+		}
+		experiments = append(experiments, page...)
+		if len(page) < p.Limit {
+			break
+		}
+	}
+	return idx, e.write("experiments", experiments)
+}
+
+func (e *exporter) exportAgents() (int, error) {
+	agents, err := e.c.Agents(e.ctx)
+	if err != nil {
+		return 0, fmt.Errorf("agents: %w", err)
+	}
+	if err := e.write("agents", map[string]any{"agents": agents}); err != nil {
+		return 0, err
+	}
+	for _, a := range agents {
+		detail, err := e.c.Agent(e.ctx, a.ID)
+		if err != nil {
+			return 0, fmt.Errorf("agent %s: %w", a.ID, err)
+		}
+		if err := e.write("agents/"+url.PathEscape(a.ID), detail); err != nil {
+			return 0, err
+		}
+	}
+	return len(agents), nil
+}
+
+// exportForum writes the whole thread list, hot first, and every thread
+// with its comments.
+func (e *exporter) exportForum() (int, error) {
+	first, err := e.c.Forum(e.ctx, client.ForumQuery{Page: client.Page{Limit: 1}})
+	if err != nil {
+		return 0, fmt.Errorf("forum: %w", err)
+	}
+	listing := *first
+	listing.Posts = []*types.Publication{}
+	for post, err := range e.c.ForumPosts(e.ctx, client.ForumQuery{}) {
+		if err != nil {
+			return 0, fmt.Errorf("forum: %w", err)
+		}
+		listing.Posts = append(listing.Posts, post)
+		thread, err := e.c.ForumPost(e.ctx, post.ID, "")
+		if err != nil {
+			return 0, fmt.Errorf("thread %s: %w", post.ID, err)
+		}
+		if err := e.write("forum/posts/"+url.PathEscape(post.ID), thread); err != nil {
+			return 0, err
+		}
+		if e.pages != nil {
+			if err := e.pages.writeThread(thread); err != nil {
+				return 0, fmt.Errorf("thread page %s: %w", post.ID, err)
+			}
+		}
+	}
+	return len(listing.Posts), e.write("forum", listing)
+}
+
+// exportJournal writes the journal with every published paper, and the
+// detail of each published and pending paper.
+func (e *exporter) exportJournal() (int, error) {
+	resp, err := e.c.Journal(e.ctx, client.JournalQuery{Page: client.Page{Limit: 1}})
+	if err != nil {
+		return 0, fmt.Errorf("journal: %w", err)
+	}
+	journal := *resp
+	journal.Approved = []*types.Publication{}
+	for paper, err := range e.c.Papers(e.ctx, client.JournalQuery{}) {
+		if err != nil {
+			return 0, fmt.Errorf("journal: %w", err)
+		}
+		journal.Approved = append(journal.Approved, paper)
+	}
+	if err := e.write("journal", journal); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, list := range [][]*types.Publication{journal.Approved, journal.Pending} {
+		for _, paper := range list {
+			detail, err := e.c.Paper(e.ctx, paper.ID)
+			if err != nil {
+				return 0, fmt.Errorf("paper %s: %w", paper.ID, err)
+			}
+			if err := e.write("journal/papers/"+url.PathEscape(paper.ID), detail); err != nil {
+				return 0, err
+			}
+			if e.pages != nil {
+				if err := e.pages.writePaper(detail); err != nil {
+					return 0, fmt.Errorf("paper page %s: %w", paper.ID, err)
+				}
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// exportFeed pages through the feed, newest first, following the server's
+// cursors when it reads the sharded store and offsets otherwise. Each
+// page's next field is rewritten to the number of the page after it.
+func (e *exporter) exportFeed(pageSize int) (int, error) {
+	q := client.FeedQuery{Page: client.Page{Limit: pageSize}}
+	fetch := func() (*client.FeedResponse, error) {
+		resp, err := e.c.Feed(e.ctx, q)
+		if client.IsNotFound(err) {
+			// No logs yet: export an empty first page.
+			return &client.FeedResponse{Events: []client.FeedEvent{}}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("feed: %w", err)
+		}
+		if resp.Next != "" {
+			q.Before, q.Offset = resp.Next, 0
+		} else {
+			q.Offset += len(resp.Events)
+		}
+		return resp, nil
+	}
+
+	cur, err := fetch()
+	if err != nil {
+		return 0, err
+	}
+	pages := 1
+	for {
+		var next *client.FeedResponse
+		if len(cur.Events) == pageSize {
+			if next, err = fetch(); err != nil {
+				return 0, err
+			}
+		}
+		cur.Prev, cur.Next = "", ""
+		if next != nil && len(next.Events) > 0 {
+			cur.Next = strconv.Itoa(pages + 1)
+		}
+		if err := e.write("feed/"+strconv.Itoa(pages), cur); err != nil {
+			return 0, err
+		}
+		if cur.Next == "" {
+			return pages, nil
+		}
+		cur = next
+		pages++
+	}
+}
+
+func (e *exporter) exportGroups() (int, error) {
+	groups := []group.Group{}
+	for p := (client.Page{Limit: 200}); ; p.Offset += p.Limit {
+		page, err := e.c.Groups(e.ctx, "", p)
+		if err != nil {
+			return 0, fmt.Errorf("groups: %w", err)
+		}
+		for _, g := range page {
+			groups = append(groups, g)
+			full, err := e.c.Group(e.ctx, g.ID)
+			if err != nil {
+				return 0, fmt.Errorf("group %s: %w", g.ID, err)
+			}
+			if err := e.write("groups/"+url.PathEscape(g.ID), full); err != nil {
+				return 0, err
+			}
+		}
+		if len(page) < p.Limit {
+			break
+		}
+	}
+	return len(groups), e.write("groups", groups)
+}
+
+// copyWeb copies the HTML pages and assets.
+func copyWeb(webPath, outDir string) error {
+	entries, err := os.ReadDir(webPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		src := filepath.Join(webPath, entry.Name())
+		dst := filepath.Join(outDir, entry.Name())
+		switch {
+		case entry.IsDir() && entry.Name() == "assets":
+			err = copyTree(src, dst)
+		case !entry.IsDir() && filepath.Ext(entry.Name()) == ".html":
+			err = copyFile(src, dst)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyData copies the raw data files the static pages read; missing ones
+// are skipped.
+func copyData(dataPath, outData string) error {
+	for _, dir := range dataDirs {
+		if err := copyTree(filepath.Join(dataPath, dir), filepath.Join(outData, dir)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	files := append([]string(nil), dataFiles...)
+	logs, _ := filepath.Glob(filepath.Join(dataPath, "logs*.jsonl"))
+	archived, _ := filepath.Glob(filepath.Join(dataPath, "logs*.jsonl"+feed.GzipExt))
+	logs = append(logs, archived...)
+	for _, path := range logs {
+		files = append(files, filepath.Base(path))
+	}
+	for _, name := range files {
+		if err := copyFile(filepath.Join(dataPath, name), filepath.Join(outData, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyTree(src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		return copyFile(path, filepath.Join(dst, rel))
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
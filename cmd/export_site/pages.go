@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/client"
+)
+
+// pageWriter writes a copy of paper.html or forum.html for each paper and
+// thread, at paper/{id}/ and thread/{id}/, with the item's title,
+// description, Open Graph tags and schema.org JSON-LD in the head. Link
+// previews and crawlers that don't run JavaScript see the real metadata;
+// the page scripts still render the body.
+type pageWriter struct {
+	outDir  string
+	baseURL string
+	paper   string // paper.html
+	thread  string // forum.html
+}
+
+func newPageWriter(webPath, outDir, baseURL string) (*pageWriter, error) {
+	paper, err := os.ReadFile(filepath.Join(webPath, "paper.html"))
+	if err != nil {
+		return nil, err
+	}
+	thread, err := os.ReadFile(filepath.Join(webPath, "forum.html"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &pageWriter{outDir: outDir, baseURL: baseURL, paper: string(paper), thread: string(thread)}, nil
+}
+
+// pageMeta is what a generated page declares about its item.
+type pageMeta struct {
+	Base        string // relative path back to the site root
+	Title       string
+	Description string
+	URL         string
+	Type        string // og:type
+	Published   string // RFC 3339, empty if unknown
+	Authors     []string
+	Citation    []metaTag // Highwire tags read by Google Scholar
+	JSONLD      map[string]any
+}
+
+type metaTag struct{ Name, Content string }
+
+var headTemplate = template.Must(template.New("head").Parse(`
+    <base href="{{.Base}}" />
+    <title>{{.Title}}</title>
+    <meta name="description" content="{{.Description}}" />
+    <link rel="canonical" href="{{.URL}}" />
+    <meta property="og:site_name" content="Sci-Bot" />
+    <meta property="og:type" content="{{.Type}}" />
+    <meta property="og:title" content="{{.Title}}" />
+    <meta property="og:description" content="{{.Description}}" />
+    <meta property="og:url" content="{{.URL}}" />
+{{- with .Published}}
+    <meta property="article:published_time" content="{{.}}" />
+{{- end}}
+{{- range .Authors}}
+    <meta property="article:author" content="{{.}}" />
+{{- end}}
+    <meta name="twitter:card" content="summary" />
+{{- range .Citation}}
+    <meta name="{{.Name}}" content="{{.Content}}" />
+{{- end}}
+    <script type="application/ld+json">{{.JSONLD}}</script>`))
+
+// staticMeta matches the generic tags of the page templates that a
+// generated page replaces.
+var staticMeta = regexp.MustCompile(`(?s)\s*(?:<title>.*?</title>|<meta\s+name="description".*?/>|<link\s+rel="canonical".*?/>|<meta\s+property="og:[^"]*".*?/>|<meta\s+name="twitter:card".*?/>)`)
+
+func (pw *pageWriter) write(page, dir, id string, meta pageMeta) error {
+	meta.Base = "../../"
+	var head bytes.Buffer
+	if err := headTemplate.Execute(&head, meta); err != nil {
+		return err
+	}
+	html := staticMeta.ReplaceAllString(page, "")
+	html = strings.Replace(html, `<meta charset="utf-8" />`, `<meta charset="utf-8" />`+head.String(), 1)
+
+	path := filepath.Join(pw.outDir, dir, url.PathEscape(id), "index.html")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(html), 0644)
+}
+
+// writePaper writes paper/{id}/ as a schema.org ScholarlyArticle. Pending
+// papers come blinded from the server and carry no publication date.
+func (pw *pageWriter) writePaper(detail *client.PaperDetailResponse) error {
+	p := detail.Paper
+	link := pw.baseURL + "paper/" + url.PathEscape(p.ID) + "/"
+	title := strings.TrimSpace(p.Title)
+	if title == "" {
+		title = p.ID
+	}
+	desc := clip(p.Abstract, 200)
+	if desc == "" {
+		desc = clip(p.Content, 200)
+	}
+	meta := pageMeta{
+		Title:       title + " | Sci-Bot Journal",
+		Description: desc,
+		URL:         link,
+		Type:        "article",
+		Authors:     []string{p.AuthorName},
+	}
+	ld := map[string]any{
+		"@context":    "https://schema.org",
+		"@type":       "ScholarlyArticle",
+		"headline":    title,
+		"name":        title,
+		"author":      []any{map[string]any{"@type": "Person", "name": p.AuthorName}},
+		"description": desc,
+		"identifier":  p.ID,
+		"url":         link,
+		"isPartOf":    map[string]any{"@type": "Periodical", "name": detail.JournalName},
+	}
+	if p.Abstract != "" {
+		ld["abstract"] = strings.TrimSpace(p.Abstract)
+	}
+	if detail.Status == "published" && p.PublishedAt.Year() > 1970 {
+		meta.Published = p.PublishedAt.UTC().Format("2006-01-02T15:04:05Z")
+		ld["datePublished"] = meta.Published
+		meta.Citation = []metaTag{
+			{"citation_title", title},
+			{"citation_author", p.AuthorName},
+			{"citation_publication_date", p.PublishedAt.UTC().Format("2006/01/02")},
+			{"citation_journal_title", detail.JournalName},
+		}
+	}
+	meta.JSONLD = ld
+	return pw.write(pw.paper, "paper", p.ID, meta)
+}
+
+// writeThread writes thread/{id}/ as a schema.org DiscussionForumPosting.
+func (pw *pageWriter) writeThread(thread *client.ForumPostResponse) error {
+	p := thread.Post
+	link := pw.baseURL + "thread/" + url.PathEscape(p.ID) + "/"
+	title := strings.TrimSpace(p.Title)
+	if title == "" {
+		title = clip(p.Content, 80)
+	}
+	desc := clip(p.Abstract, 200)
+	if desc == "" {
+		desc = clip(p.Content, 200)
+	}
+	meta := pageMeta{
+		Title:       title + " | Sci-Bot Forum",
+		Description: desc,
+		URL:         link,
+		Type:        "article",
+		Authors:     []string{p.AuthorName},
+	}
+	ld := map[string]any{
+		"@context":     "https://schema.org",
+		"@type":        "DiscussionForumPosting",
+		"headline":     title,
+		"author":       map[string]any{"@type": "Person", "name": p.AuthorName},
+		"text":         clip(p.Content, 1000),
+		"url":          link,
+		"identifier":   p.ID,
+		"commentCount": len(thread.Comments),
+		"interactionStatistic": map[string]any{
+			"@type":                "InteractionCounter",
+			"interactionType":      "https://schema.org/LikeAction",
+			"userInteractionCount": p.Upvotes,
+		},
+	}
+	if p.Subreddit != "" {
+		ld["articleSection"] = "r/" + string(p.Subreddit)
+	}
+	if p.PublishedAt.Year() > 1970 {
+		meta.Published = p.PublishedAt.UTC().Format("2006-01-02T15:04:05Z")
+		ld["datePublished"] = meta.Published
+	}
+	meta.JSONLD = ld
+	return pw.write(pw.thread, "thread", p.ID, meta)
+}
+
+// clip collapses whitespace and cuts s to at most max runes.
+func clip(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
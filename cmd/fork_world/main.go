@@ -0,0 +1,260 @@
+// Command fork_world copies a run's data directory into a new one so the
+// simulation can branch: both directories resume from the same state and
+// diverge from there. With -at earlier than the source's sim time, the
+// copy is rewound to that sim time: log events from later ticks are
+// dropped, and forum posts, votes and journal papers are dropped by the
+// wall-clock time of the last kept event. The fork gets a new run ID and
+// records its parent in sim_state.json; continue it with
+// adk_simulate -data <to> -resume.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+)
+
+func main() {
+	from := flag.String("from", "./data/adk-simulation", "Source data directory")
+	to := flag.String("to", "", "New data directory (must not exist or be empty)")
+	at := flag.String("at", "", "Sim time to fork at (RFC3339); empty forks at the source's current sim time")
+	runID := flag.String("run-id", "", "Run ID of the fork (default: generated)")
+	flag.Parse()
+
+	if strings.TrimSpace(*to) == "" {
+		log.Fatal("-to is required")
+	}
+	state, err := simulation.LoadSimState(*from)
+	if err != nil {
+		log.Fatalf("Load sim state from %s: %v", *from, err)
+	}
+	forkAt := state.SimTime
+	if strings.TrimSpace(*at) != "" {
+		forkAt, err = time.Parse(time.RFC3339, *at)
+		if err != nil {
+			log.Fatalf("Invalid -at: %v", err)
+		}
+		if forkAt.After(state.SimTime) {
+			log.Fatalf("-at %s is after the source's sim time %s", forkAt.Format(time.RFC3339), state.SimTime.Format(time.RFC3339))
+		}
+	}
+	if rel, err := filepath.Rel(*from, *to); err == nil && !strings.HasPrefix(rel, "..") {
+		log.Fatalf("-to %s must not be inside -from %s", *to, *from)
+	}
+	if err := ensureEmpty(*to); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := copyTree(*from, *to); err != nil {
+		log.Fatalf("Copy %s to %s: %v", *from, *to, err)
+	}
+	fmt.Printf("Copied %s to %s\n", *from, *to)
+
+	ticks := state.Ticks
+	if forkAt.Before(state.SimTime) {
+		ticks, err = rewind(*to, forkAt)
+		if err != nil {
+			log.Fatalf("Rewind to %s: %v", forkAt.Format(time.RFC3339), err)
+		}
+	}
+
+	parentData, err := filepath.Abs(*from)
+	if err != nil {
+		parentData = *from
+	}
+	id := strings.TrimSpace(*runID)
+	if id == "" {
+		id = simulation.NewRunID()
+	}
+	forked := simulation.SimState{
+		SimTime:     forkAt,
+		Origin:      state.Origin,
+		Ticks:       ticks,
+		StepSeconds: state.StepSeconds,
+		RunID:       id,
+		Fork: &simulation.ForkOrigin{
+			ParentRunID: state.RunID,
+			ParentData:  parentData,
+			SimTime:     forkAt,
+			Ticks:       ticks,
+			ForkedAt:    time.Now(),
+		},
+	}
+	if err := simulation.SaveSimState(*to, forked); err != nil {
+		log.Fatalf("Save sim state: %v", err)
+	}
+	fmt.Printf("Forked run %s at %s (tick %d); continue with: adk_simulate -data %s -resume\n",
+		id, forkAt.Format(time.RFC3339), ticks, *to)
+}
+
+// rewind drops everything the copied run did from forkAt on and returns the
+// number of ticks that ran before it. Stores only record wall-clock times,
+// so the cutoff for them is the latest timestamp among the kept log events.
+func rewind(dataPath string, forkAt time.Time) (int, error) {
+	cutoff, ticks, dropped, err := truncateLogs(dataPath, forkAt)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Printf("Dropped %d log events from sim time %s on (kept %d ticks)\n", dropped, forkAt.Format(time.RFC3339), ticks)
+
+	forum := publication.NewForum("", filepath.Join(dataPath, "forum"))
+	if err := forum.Load(); err != nil {
+		return 0, fmt.Errorf("load forum: %w", err)
+	}
+	n := forum.TruncateAfter(cutoff)
+	if err := forum.Save(); err != nil {
+		return 0, fmt.Errorf("save forum: %w", err)
+	}
+	fmt.Printf("Dropped %d forum posts and comments\n", n)
+
+	journal := publication.NewJournal("", filepath.Join(dataPath, "journal"))
+	if err := journal.Load(); err != nil {
+		return 0, fmt.Errorf("load journal: %w", err)
+	}
+	n = journal.TruncateAfter(cutoff)
+	if err := journal.Save(); err != nil {
+		return 0, fmt.Errorf("save journal: %w", err)
+	}
+	fmt.Printf("Dropped %d journal papers\n", n)
+
+	log.Printf("Warning: agent states, memories, workflow, experiments and groups are copied from the source's latest checkpoint, not rewound")
+	return ticks, nil
+}
+
+// truncateLogs rewrites each logs*.jsonl under dataPath without the events
+// at or after forkAt; archived logs are rewritten and compressed again. It
+// returns the latest wall-clock timestamp and the highest tick among the
+// kept events, and how many events were dropped.
+func truncateLogs(dataPath string, forkAt time.Time) (time.Time, int, int, error) {
+	names, err := filepath.Glob(filepath.Join(dataPath, "logs*.jsonl"))
+	if err != nil {
+		return time.Time{}, 0, 0, err
+	}
+	archived, err := filepath.Glob(filepath.Join(dataPath, "logs*.jsonl"+feed.GzipExt))
+	if err != nil {
+		return time.Time{}, 0, 0, err
+	}
+	names = append(names, archived...)
+	if len(names) == 0 {
+		return time.Time{}, 0, 0, fmt.Errorf("no logs*.jsonl in %s to map sim time to store timestamps", dataPath)
+	}
+	var cutoff time.Time
+	ticks, dropped := 0, 0
+	for _, name := range names {
+		data, err := readLog(name)
+		if err != nil {
+			return time.Time{}, 0, 0, err
+		}
+		var kept []byte
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			var ev struct {
+				Timestamp time.Time `json:"timestamp"`
+				SimTime   time.Time `json:"sim_time"`
+				Tick      int       `json:"tick"`
+			}
+			if err := json.Unmarshal(line, &ev); err != nil {
+				// Keep lines we can't read rather than lose them.
+				kept = append(append(kept, line...), '\n')
+				continue
+			}
+			if !ev.SimTime.Before(forkAt) {
+				dropped++
+				continue
+			}
+			kept = append(append(kept, line...), '\n')
+			if ev.Timestamp.After(cutoff) {
+				cutoff = ev.Timestamp
+			}
+			ticks = max(ticks, ev.Tick)
+		}
+		if err := scanner.Err(); err != nil {
+			return time.Time{}, 0, 0, fmt.Errorf("%s: %w", name, err)
+		}
+		plain := strings.TrimSuffix(name, feed.GzipExt)
+		if err := os.WriteFile(plain, kept, 0644); err != nil {
+			return time.Time{}, 0, 0, err
+		}
+		if plain != name {
+			if err := feed.CompressFile(plain); err != nil {
+				return time.Time{}, 0, 0, err
+			}
+		}
+	}
+	return cutoff, ticks, dropped, nil
+}
+
+func readLog(path string) ([]byte, error) {
+	r, err := feed.OpenJSONL(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func ensureEmpty(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("%s is not empty; fork into a new directory", dir)
+	}
+	return nil
+}
+
+// copyTree copies regular files under src into dst, keeping the layout.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,291 @@
+// Command fsck_data validates a data directory: comments whose parent post
+// is missing, votes on missing posts, workflow submissions the journal has
+// no paper for, and daily note files with lines that are not JSON. It
+// prints a report and exits non-zero if anything is broken. With -repair it
+// drops the broken records; -quarantine also keeps copies of them in a
+// directory so nothing is lost. Stop the simulation before repairing.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// badLine is an unparsable line of a daily note file.
+type badLine struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// dailyFile is a daily note file with unparsable lines.
+type dailyFile struct {
+	Path string // relative to the data directory
+	Bad  []badLine
+}
+
+func main() {
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory to check")
+	repair := flag.Bool("repair", false, "Drop broken records and rewrite the stores")
+	quarantine := flag.Bool("quarantine", false, "With -repair, keep copies of dropped records under -quarantine-dir")
+	quarantineDir := flag.String("quarantine-dir", "", "Where quarantined records go (default <data>/quarantine/<time>)")
+	limit := flag.Int("limit", 20, "Max records listed per problem")
+	flag.Parse()
+
+	if *quarantine && !*repair {
+		log.Fatal("-quarantine needs -repair")
+	}
+	if _, err := os.Stat(*dataPath); err != nil {
+		log.Fatalf("Open %s: %v", *dataPath, err)
+	}
+
+	forum := publication.NewForum("", filepath.Join(*dataPath, "forum"))
+	if err := forum.Load(); err != nil {
+		log.Fatalf("Load forum: %v", err)
+	}
+	journal := publication.NewJournal("", filepath.Join(*dataPath, "journal"))
+	if err := journal.Load(); err != nil {
+		log.Fatalf("Load journal: %v", err)
+	}
+	workflow := publication.NewWorkflow(filepath.Join(*dataPath, "workflow"))
+	if err := workflow.Load(); err != nil {
+		log.Fatalf("Load workflow: %v", err)
+	}
+
+	fc := forum.Check()
+	missing := workflow.MissingSubmissions(journal)
+	daily, err := checkDaily(*dataPath)
+	if err != nil {
+		log.Fatalf("Check daily notes: %v", err)
+	}
+
+	report(fc, missing, daily, *limit)
+	problems := len(fc.OrphanedComments) + len(fc.DanglingVotes) + len(missing) + len(daily)
+	if problems == 0 {
+		fmt.Println("OK: no problems found")
+		return
+	}
+	if !*repair {
+		fmt.Println("Run with -repair to drop the broken records (add -quarantine to keep copies).")
+		os.Exit(1)
+	}
+
+	qdir := ""
+	if *quarantine {
+		qdir = *quarantineDir
+		if qdir == "" {
+			qdir = filepath.Join(*dataPath, "quarantine", time.Now().Format("20060102-150405"))
+		}
+	}
+	if err := repairAll(*dataPath, qdir, forum, fc, workflow, missing, daily); err != nil {
+		log.Fatalf("Repair: %v", err)
+	}
+	if qdir != "" {
+		fmt.Printf("Repaired; dropped records are in %s\n", qdir)
+	} else {
+		fmt.Println("Repaired")
+	}
+}
+
+func report(fc publication.ForumCheck, missing []*types.Submission, daily []dailyFile, limit int) {
+	fmt.Printf("Orphaned comments: %d\n", len(fc.OrphanedComments))
+	for i, c := range fc.OrphanedComments {
+		if i == limit {
+			fmt.Printf("  ... %d more\n", len(fc.OrphanedComments)-i)
+			break
+		}
+		fmt.Printf("  %s (parent %s missing or orphaned) by %s\n", c.ID, c.ParentID, c.AuthorID)
+	}
+
+	keys := make([]string, 0, len(fc.DanglingVotes))
+	for key := range fc.DanglingVotes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Printf("Votes on missing posts: %d\n", len(keys))
+	for i, key := range keys {
+		if i == limit {
+			fmt.Printf("  ... %d more\n", len(keys)-i)
+			break
+		}
+		fmt.Printf("  %s\n", key)
+	}
+
+	fmt.Printf("Workflow submissions missing from the journal: %d\n", len(missing))
+	for i, sub := range missing {
+		if i == limit {
+			fmt.Printf("  ... %d more\n", len(missing)-i)
+			break
+		}
+		fmt.Printf("  %s %q by %s [%s]\n", sub.ID, sub.Title, sub.AuthorID, sub.Status)
+	}
+
+	lines := 0
+	for _, f := range daily {
+		lines += len(f.Bad)
+	}
+	fmt.Printf("Unparsable daily note lines: %d in %d file(s)\n", lines, len(daily))
+	for i, f := range daily {
+		if i == limit {
+			fmt.Printf("  ... %d more files\n", len(daily)-i)
+			break
+		}
+		nums := make([]string, 0, len(f.Bad))
+		for _, b := range f.Bad {
+			nums = append(nums, fmt.Sprint(b.Line))
+		}
+		fmt.Printf("  %s: line %s\n", f.Path, strings.Join(nums, ", "))
+	}
+}
+
+// checkDaily scans agents/<id>/daily/*.jsonl for non-empty lines that are
+// not valid JSON.
+func checkDaily(dataPath string) ([]dailyFile, error) {
+	names, err := filepath.Glob(filepath.Join(dataPath, "agents", "*", "daily", "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	var out []dailyFile
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var bad []badLine
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+		for n := 1; scanner.Scan(); n++ {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) > 0 && !json.Valid(line) {
+				bad = append(bad, badLine{Line: n, Text: string(line)})
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if len(bad) > 0 {
+			rel, err := filepath.Rel(dataPath, name)
+			if err != nil {
+				rel = name
+			}
+			out = append(out, dailyFile{Path: rel, Bad: bad})
+		}
+	}
+	return out, nil
+}
+
+// repairAll drops the broken records, first copying them under qdir when
+// it is set, and saves the stores it changed.
+func repairAll(dataPath, qdir string, forum *publication.Forum, fc publication.ForumCheck, workflow *publication.Workflow, missing []*types.Submission, daily []dailyFile) error {
+	if !fc.Empty() {
+		if len(fc.OrphanedComments) > 0 {
+			if err := quarantineJSON(qdir, "forum-comments.json", fc.OrphanedComments); err != nil {
+				return err
+			}
+		}
+		if len(fc.DanglingVotes) > 0 {
+			if err := quarantineJSON(qdir, "forum-votes.json", fc.DanglingVotes); err != nil {
+				return err
+			}
+		}
+		forum.DropBroken(fc)
+		if err := forum.Save(); err != nil {
+			return fmt.Errorf("save forum: %w", err)
+		}
+		fmt.Printf("Dropped %d comments and %d votes from the forum\n", len(fc.OrphanedComments), len(fc.DanglingVotes))
+	}
+
+	if len(missing) > 0 {
+		ids := make([]string, 0, len(missing))
+		for _, sub := range missing {
+			ids = append(ids, sub.ID)
+		}
+		reviews := workflow.DropSubmissions(ids)
+		quarantined := struct {
+			Submissions []*types.Submission             `json:"submissions"`
+			Reviews     map[string][]*types.PaperReview `json:"reviews,omitempty"`
+		}{missing, reviews}
+		if err := quarantineJSON(qdir, "workflow-submissions.json", quarantined); err != nil {
+			return err
+		}
+		if err := workflow.Save(); err != nil {
+			return fmt.Errorf("save workflow: %w", err)
+		}
+		fmt.Printf("Dropped %d submissions from the workflow\n", len(missing))
+	}
+
+	for _, f := range daily {
+		if err := repairDaily(dataPath, qdir, f); err != nil {
+			return err
+		}
+	}
+	if len(daily) > 0 {
+		fmt.Printf("Rewrote %d daily note file(s) without their unparsable lines\n", len(daily))
+	}
+	return nil
+}
+
+// repairDaily rewrites a daily note file without its unparsable lines; with
+// a quarantine directory they go to the same relative path under it.
+func repairDaily(dataPath, qdir string, f dailyFile) error {
+	path := filepath.Join(dataPath, f.Path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	drop := make(map[int]bool, len(f.Bad))
+	for _, b := range f.Bad {
+		drop[b.Line] = true
+	}
+	var kept, dropped []byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := append(scanner.Bytes(), '\n')
+		if drop[n] {
+			dropped = append(dropped, line...)
+		} else {
+			kept = append(kept, line...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if qdir != "" {
+		target := filepath.Join(qdir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, dropped, 0644); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, kept, 0644)
+}
+
+// quarantineJSON writes v to qdir/name; it does nothing without qdir.
+func quarantineJSON(qdir, name string, v any) error {
+	if qdir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(qdir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(qdir, name), data, 0644)
+}
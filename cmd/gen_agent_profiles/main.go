@@ -153,6 +153,8 @@ func roleMission(p *types.Persona) string {
 		return "Connect disparate domains and translate between frames."
 	case types.RoleCommunicator:
 		return "Explain complex ideas clearly and keep knowledge accessible."
+	case types.RoleEditor:
+		return "Triage submissions, match them with suitable reviewers, and keep reviews on schedule."
 	default:
 		return "Contribute to scientific discussion with your unique perspective."
 	}
@@ -170,6 +172,8 @@ func roleFocus(p *types.Persona) string {
 		return "Cross-domain synthesis and conceptual bridges."
 	case types.RoleCommunicator:
 		return "Clarity, teaching, and outreach to non-experts."
+	case types.RoleEditor:
+		return "Submission triage, reviewer matching, and editorial scope."
 	default:
 		return "Balanced scientific contribution."
 	}
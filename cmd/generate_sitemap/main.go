@@ -88,6 +88,17 @@ func joinBase(base string, path string) string {
 	return base + path
 }
 
+// pageLoc prefers the page cmd/export_site wrote for an item under
+// dir/{id}/ in the output directory, so the sitemap lists the URL that page
+// declares canonical; fallback is the query-string URL.
+func pageLoc(baseURL, outDir, dir, id, fallback string) string {
+	rel := dir + "/" + url.PathEscape(id) + "/"
+	if _, err := os.Stat(filepath.Join(outDir, filepath.FromSlash(rel), "index.html")); err == nil {
+		return joinBase(baseURL, rel)
+	}
+	return joinBase(baseURL, fallback)
+}
+
 func main() {
 	var dataDir string
 	var outDir string
@@ -147,7 +158,7 @@ func main() {
 					return
 				}
 				entries = append(entries, urlEntry{
-					Loc:        joinBase(baseURL, "paper.html?id="+url.QueryEscape(id)),
+					Loc:        pageLoc(baseURL, outDir, "paper", id, "paper.html?id="+url.QueryEscape(id)),
 					LastMod:    parseLastMod(publishedAt),
 					ChangeFreq: "monthly",
 					Priority:   priority,
@@ -175,7 +186,7 @@ func main() {
 					continue
 				}
 				entries = append(entries, urlEntry{
-					Loc:        joinBase(baseURL, "forum.html?post="+url.QueryEscape(id)),
+					Loc:        pageLoc(baseURL, outDir, "thread", id, "forum.html?post="+url.QueryEscape(id)),
 					LastMod:    parseLastMod(p.PublishedAt),
 					ChangeFreq: "weekly",
 					Priority:   "0.7",
@@ -2,9 +2,11 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -23,6 +25,7 @@ func main() {
 	feedDir := flag.String("feed", "feed", "Feed shards directory (relative to data directory). Set '-' to disable.")
 	feedMaxEvents := flag.Int("feed-max-events", 200, "Max events per feed shard file")
 	rebuildFeed := flag.Bool("rebuild-feed", false, "Rebuild sharded feed store from logs*.jsonl")
+	updateFeed := flag.Bool("update-feed", false, "Append only the events logged since the last -rebuild-feed/-update-feed to the feed store, using the per-log offsets recorded in its index (builds it when missing)")
 	feedHydrateDaily := flag.Bool("feed-hydrate-daily", true, "When rebuilding feed, hydrate prompt/response/error from per-agent daily JSONLs if available")
 	flag.Parse()
 
@@ -46,10 +49,16 @@ func main() {
 		feedIndexRel = filepath.ToSlash(filepath.Join(dirName, "index.json"))
 	}
 
-	if *rebuildFeed && feedIndexRel != "" {
+	switch {
+	case feedIndexRel == "":
+	case *rebuildFeed:
 		if err := rebuildFeedStore(*dataPath, *feedDir, *feedMaxEvents, *feedHydrateDaily); err != nil {
 			log.Fatalf("Rebuild feed store: %v", err)
 		}
+	case *updateFeed:
+		if err := updateFeedStore(*dataPath, *feedDir, *feedMaxEvents, *feedHydrateDaily); err != nil {
+			log.Fatalf("Update feed store: %v", err)
+		}
 	}
 
 	manifest, err := buildManifest(*dataPath, agents, feedIndexRel)
@@ -175,6 +184,15 @@ func buildManifest(dataPath string, agents []site.Agent, feedIndexRel string) (s
 		m.SimTime = state.SimTime
 		m.StepSeconds = state.StepSeconds
 	}
+	if _, err := os.Stat(filepath.Join(dataPath, "activity.json")); err == nil {
+		m.ActivityPath = "activity.json"
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, "digest.json")); err == nil {
+		m.DigestPath = "digest.json"
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, "emergence.json")); err == nil {
+		m.EmergencePath = "emergence.json"
+	}
 	return m, nil
 }
 
@@ -189,7 +207,7 @@ func discoverLogs(dataPath string) []string {
 			continue
 		}
 		name := e.Name()
-		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(name, ".jsonl") {
+		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(strings.TrimSuffix(name, feed.GzipExt), ".jsonl") {
 			continue
 		}
 		out = append(out, name)
@@ -260,18 +278,11 @@ type dailyLogEntry struct {
 }
 
 func rebuildFeedFromLogs(outDir, dataPath string, logPaths []string, maxEventsPerShard int, hydrateDaily bool) error {
-	events, err := readLogEvents(logPaths)
+	events, offsets, err := readLogEvents(logPaths, nil)
 	if err != nil {
 		return err
 	}
 
-	sort.SliceStable(events, func(i, j int) bool {
-		if events[i].SimTime.Equal(events[j].SimTime) {
-			return events[i].Timestamp.Before(events[j].Timestamp)
-		}
-		return events[i].SimTime.Before(events[j].SimTime)
-	})
-
 	w, err := feed.OpenWriter(feed.WriterConfig{
 		Dir:               outDir,
 		MaxEventsPerShard: maxEventsPerShard,
@@ -282,6 +293,85 @@ func rebuildFeedFromLogs(outDir, dataPath string, logPaths []string, maxEventsPe
 	}
 	defer w.Close()
 
+	if err := appendFeedEvents(w, dataPath, events, hydrateDaily); err != nil {
+		return err
+	}
+	if err := w.SetSourceOffsets(offsets); err != nil {
+		return err
+	}
+
+	_, err = feed.LoadIndex(filepath.Join(outDir, "index.json"))
+	return err
+}
+
+// updateFeedStore appends the events logged since the offsets recorded in
+// the feed index. A store without offsets, or a log that has shrunk since,
+// needs a full rebuild.
+func updateFeedStore(dataPath string, feedDir string, maxEventsPerShard int, hydrateDaily bool) error {
+	dirName := strings.TrimSpace(feedDir)
+	if dirName == "" {
+		dirName = "feed"
+	}
+	abs := filepath.Join(dataPath, dirName)
+	idx, err := feed.LoadIndex(filepath.Join(abs, "index.json"))
+	if os.IsNotExist(err) {
+		return rebuildFeedStore(dataPath, feedDir, maxEventsPerShard, hydrateDaily)
+	}
+	if err != nil {
+		return err
+	}
+	if len(idx.Sources) == 0 && idx.TotalEvents > 0 {
+		return fmt.Errorf("%s records no log offsets (written live by adk_simulate or by an older index_data); run -rebuild-feed once", abs)
+	}
+
+	logPaths := make([]string, 0)
+	for _, name := range discoverLogs(dataPath) {
+		path := filepath.Join(dataPath, name)
+		if plain, ok := strings.CutSuffix(name, feed.GzipExt); ok {
+			// compact_data only archives logs that were fully indexed.
+			if _, indexed := idx.Sources[plain]; indexed {
+				continue
+			}
+		} else if st, err := os.Stat(path); err == nil && st.Size() < idx.Sources[name] {
+			return fmt.Errorf("%s is shorter than when last indexed; run -rebuild-feed", name)
+		}
+		logPaths = append(logPaths, path)
+	}
+	events, offsets, err := readLogEvents(logPaths, idx.Sources)
+	if err != nil {
+		return err
+	}
+
+	w, err := feed.OpenWriter(feed.WriterConfig{
+		Dir:               abs,
+		MaxEventsPerShard: maxEventsPerShard,
+		Append:            true,
+	})
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := appendFeedEvents(w, dataPath, events, hydrateDaily); err != nil {
+		return err
+	}
+	if err := w.SetSourceOffsets(offsets); err != nil {
+		return err
+	}
+	fmt.Printf("Feed store: appended %d new events\n", len(events))
+	return nil
+}
+
+// appendFeedEvents writes events to the feed in sim time order, hydrating
+// prompt/response/error from the agents' daily logs when asked.
+func appendFeedEvents(w *feed.Writer, dataPath string, events []simulation.EventLog, hydrateDaily bool) error {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].SimTime.Equal(events[j].SimTime) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		return events[i].SimTime.Before(events[j].SimTime)
+	})
+
 	dailyCache := make(map[string]map[int64]dailyLogEntry)
 	loadDaily := func(agentID, dateKey string) map[int64]dailyLogEntry {
 		cacheKey := agentID + "|" + dateKey
@@ -291,7 +381,12 @@ func rebuildFeedFromLogs(outDir, dataPath string, logPaths []string, maxEventsPe
 
 		m := make(map[int64]dailyLogEntry)
 		path := filepath.Join(dataPath, "agents", agentID, "daily", dateKey+".jsonl")
-		data, err := os.ReadFile(path)
+		var data []byte
+		f, err := feed.OpenJSONL(path)
+		if err == nil {
+			data, err = io.ReadAll(f)
+			f.Close()
+		}
 		if err == nil {
 			lines := strings.Split(string(data), "\n")
 			for _, line := range lines {
@@ -340,32 +435,53 @@ func rebuildFeedFromLogs(outDir, dataPath string, logPaths []string, maxEventsPe
 			return err
 		}
 	}
-
-	_, err = feed.LoadIndex(filepath.Join(outDir, "index.json"))
-	return err
+	return nil
 }
 
-func readLogEvents(paths []string) ([]simulation.EventLog, error) {
+// readLogEvents reads each log from its offset in from (keyed by file name;
+// nil reads everything) and returns the events with the offsets reached.
+// A trailing line without a newline may still be being written, so it is
+// left for the next run. A compacted log (.jsonl.gz) keeps the name and
+// offset of the log it was compressed from; offsets count uncompressed
+// bytes.
+func readLogEvents(paths []string, from map[string]int64) ([]simulation.EventLog, map[string]int64, error) {
 	out := make([]simulation.EventLog, 0, 1024)
+	offsets := make(map[string]int64, len(paths))
 	for _, path := range paths {
-		f, err := os.Open(path)
+		name := strings.TrimSuffix(filepath.Base(path), feed.GzipExt)
+		f, err := feed.OpenJSONL(path)
 		if err != nil {
 			continue
 		}
-		scanner := bufio.NewScanner(f)
-		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if line == "" {
+		offset := from[name]
+		if seeker, ok := f.(io.Seeker); ok {
+			_, err = seeker.Seek(offset, io.SeekStart)
+		} else {
+			_, err = io.CopyN(io.Discard, f, offset)
+		}
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		reader := bufio.NewReaderSize(f, 256*1024)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				break
+			}
+			offset += int64(len(line))
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
 				continue
 			}
 			var ev simulation.EventLog
-			if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			if err := json.Unmarshal(line, &ev); err != nil {
 				continue
 			}
 			out = append(out, ev)
 		}
 		_ = f.Close()
+		offsets[name] = offset
 	}
-	return out, nil
+	return out, offsets, nil
 }
@@ -0,0 +1,224 @@
+// Command interview lets a human talk with a saved agent after (or between)
+// runs: the agent answers from its persona, memory summary, beliefs and
+// relationships, using the model it ran with. The agent gets no tools and
+// nothing is written to the data directory, so interviews never change the
+// simulation.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/cpunion/sci-bot/pkg/llm"
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory of the run")
+	agentID := flag.String("agent", "", "Agent to interview, by ID or name (required)")
+	modelName := flag.String("model", "", "LLM model spec; empty uses the agent's own model from personas.json, else GOOGLE_MODEL (GOOGLE_REVIEWER_MODEL for reviewers)")
+	langName := flag.String("lang", "zh", "Prompt language as for adk_simulate: zh, en or mixed; a persona's own language takes precedence")
+	promptsDir := flag.String("prompts", "", "Prompt template directory used for the run (empty uses the built-ins)")
+	profilesDir := flag.String("profiles", "", "Per-agent profile directory used for the run (empty: none)")
+	maxOutputTokens := flag.Int("max-output-tokens", 2048, "Max output tokens per answer")
+	transcriptPath := flag.String("transcript", "", "Append each question and answer to this JSONL file")
+	showInstruction := flag.Bool("show-instruction", false, "Print the agent's system instruction before the interview")
+	var questions []string
+	flag.Func("q", "Ask this question and exit instead of reading questions from stdin (repeatable; later questions see earlier answers)", func(s string) error {
+		questions = append(questions, s)
+		return nil
+	})
+	flag.Parse()
+
+	if strings.TrimSpace(*agentID) == "" {
+		log.Fatalf("-agent is required")
+	}
+	persona, err := findPersona(*dataPath, strings.TrimSpace(*agentID))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	mode, err := prompts.ParseMode(*langName)
+	if err != nil {
+		log.Fatalf("Invalid -lang: %v", err)
+	}
+	var templates *prompts.Templates
+	if strings.TrimSpace(*promptsDir) != "" {
+		if templates, err = prompts.LoadTemplates(*promptsDir); err != nil {
+			log.Fatalf("Failed to load prompt templates: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	spec := llm.NormalizeModelSpec(modelSpec(persona, *modelName))
+	model, err := llm.NewModel(ctx, spec)
+	if err != nil {
+		log.Fatalf("Failed to create model %s: %v", spec, err)
+	}
+	iv, err := simulation.NewInterview(simulation.InterviewConfig{
+		DataPath:        *dataPath,
+		Persona:         persona,
+		Model:           model,
+		Lang:            mode.For(persona),
+		Templates:       templates,
+		ProfilesDir:     *profilesDir,
+		MaxOutputTokens: int32(*maxOutputTokens),
+	})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	var transcript *json.Encoder
+	if *transcriptPath != "" {
+		f, err := os.OpenFile(*transcriptPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Fatalf("Open transcript: %v", err)
+		}
+		defer f.Close()
+		transcript = json.NewEncoder(f)
+	}
+	if *showInstruction {
+		fmt.Printf("%s\n\n---\n\n", iv.Instruction())
+	}
+
+	name := iv.Persona().Name
+	ask := func(q string) error {
+		answer, err := iv.Ask(ctx, q)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n\n", name, answer)
+		if transcript != nil {
+			return transcript.Encode(turn{
+				Time:     time.Now(),
+				Data:     *dataPath,
+				Agent:    persona.ID,
+				Model:    spec,
+				Question: q,
+				Answer:   answer,
+			})
+		}
+		return nil
+	}
+
+	if len(questions) > 0 {
+		for _, q := range questions {
+			fmt.Printf("> %s\n", q)
+			if err := ask(q); err != nil {
+				log.Fatalf("Ask: %v", err)
+			}
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Interviewing %s (%s, %s) with %s. Empty line or Ctrl-D ends the interview.\n\n", name, persona.ID, persona.Role, spec)
+		in := bufio.NewScanner(os.Stdin)
+		in.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for {
+			fmt.Print("> ")
+			if !in.Scan() {
+				fmt.Println()
+				break
+			}
+			q := strings.TrimSpace(in.Text())
+			if q == "" {
+				break
+			}
+			if err := ask(q); err != nil {
+				if errors.Is(err, context.Canceled) {
+					break
+				}
+				log.Printf("Ask: %v", err)
+			}
+		}
+		if err := in.Err(); err != nil && !errors.Is(err, io.EOF) {
+			log.Fatalf("Read stdin: %v", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Tokens: %d prompt, %d output\n", iv.PromptTokens, iv.OutputTokens)
+}
+
+// turn is one transcript line.
+type turn struct {
+	Time     time.Time `json:"time"`
+	Data     string    `json:"data"`
+	Agent    string    `json:"agent"`
+	Model    string    `json:"model"`
+	Question string    `json:"question"`
+	Answer   string    `json:"answer"`
+}
+
+// modelSpec picks the model the agent ran with, as far as the data
+// directory records it: an explicit -model, the persona's own model, or
+// adk_simulate's role default. Scenario role models aren't saved with the
+// run, so pass -model for those.
+func modelSpec(p *types.Persona, override string) string {
+	if spec := strings.TrimSpace(override); spec != "" {
+		return spec
+	}
+	if spec := strings.TrimSpace(p.Model); spec != "" {
+		return spec
+	}
+	if p.Role == types.RoleReviewer {
+		return envOr("GOOGLE_REVIEWER_MODEL", "gemini-3-pro-preview")
+	}
+	return envOr("GOOGLE_MODEL", "gemini-3-flash-preview")
+}
+
+// findPersona looks the agent up by ID, then by name, in personas.json and
+// recruits.json.
+func findPersona(dataPath, key string) (*types.Persona, error) {
+	var all []*types.Persona
+	for _, name := range []string{"personas.json", "recruits.json"} {
+		data, err := os.ReadFile(filepath.Join(dataPath, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var store struct {
+			Personas []*types.Persona `json:"personas"`
+		}
+		if err := json.Unmarshal(data, &store); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		all = append(all, store.Personas...)
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no personas.json in %s", dataPath)
+	}
+	for _, p := range all {
+		if p != nil && p.ID == key {
+			return p, nil
+		}
+	}
+	for _, p := range all {
+		if p != nil && strings.EqualFold(p.Name, key) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("agent %q not found in %s", key, dataPath)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,97 @@
+// Command lineage_report rebuilds how theories spread through a run's
+// population from the sources agents recorded when they learned them, and
+// prints one propagation tree per theory as text or JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/lineage"
+)
+
+func main() {
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory containing agents/<id>/state.json")
+	theoryID := flag.String("theory", "", "Only report this theory")
+	minAdopters := flag.Int("min-adopters", 1, "Skip theories learned by fewer agents")
+	format := flag.String("format", "text", "Output format: text or json")
+	outPath := flag.String("out", "", "Write the report to this file instead of stdout")
+	flag.Parse()
+
+	adoptions, err := lineage.LoadAdoptions(*dataPath)
+	if err != nil {
+		log.Fatalf("Read agent states: %v", err)
+	}
+	if id := strings.TrimSpace(*theoryID); id != "" {
+		adoptions = slices.DeleteFunc(adoptions, func(a lineage.Adoption) bool { return a.TheoryID != id })
+	}
+	trees := slices.DeleteFunc(lineage.Build(adoptions), func(t lineage.Tree) bool { return t.Adopters < *minAdopters })
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Create %s: %v", *outPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	switch strings.ToLower(strings.TrimSpace(*format)) {
+	case "text", "":
+		writeText(out, trees)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(trees)
+	default:
+		log.Fatalf("Unknown -format %q (use text or json)", *format)
+	}
+	if err != nil {
+		log.Fatalf("Write report: %v", err)
+	}
+}
+
+// writeText prints each theory's stats and its tree, one agent per line
+// indented under the agent it learned the theory from.
+func writeText(w io.Writer, trees []lineage.Tree) {
+	if len(trees) == 0 {
+		fmt.Fprintln(w, "No agent has learned a theory yet.")
+		return
+	}
+	for i, t := range trees {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		title := t.TheoryID
+		if t.TheoryTitle != "" {
+			title += " " + t.TheoryTitle
+		}
+		fmt.Fprintf(w, "%s: %d adopters, depth %d, max fanout %d\n", title, t.Adopters, t.Depth, t.MaxFanout)
+		for _, root := range t.Roots {
+			writeNode(w, root, 1)
+		}
+	}
+}
+
+func writeNode(w io.Writer, n *lineage.Node, depth int) {
+	line := strings.Repeat("  ", depth) + n.AgentID
+	if !n.LearnedAt.IsZero() {
+		line += " " + n.LearnedAt.Format("2006-01-02 15:04")
+	}
+	if n.PostID != "" {
+		line += " via " + n.PostID
+	}
+	if n.Level != "" {
+		line += " [" + string(n.Level) + "]"
+	}
+	fmt.Fprintln(w, line)
+	for _, c := range n.Children {
+		writeNode(w, c, depth+1)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
 type DailyEntry struct {
@@ -25,50 +26,57 @@ func main() {
 	dataPath := flag.String("data", "./data/adk-simulation", "Data directory")
 	deleteMD := flag.Bool("delete-md", false, "Delete .md files after successful migration")
 	overwrite := flag.Bool("overwrite", false, "Overwrite existing .jsonl files")
+	dryRun := flag.Bool("dry-run", false, "Report which files would be created/overwritten/deleted without touching anything")
+	undoLog := flag.String("undo-log", "", "Where to write the undo log (default <data>/migrations/daily-notes-<time>.undo.json); written before any file is changed")
+	undo := flag.String("undo", "", "Roll back a previous migration using its undo log, then exit")
 	flag.Parse()
 
+	if *undo != "" {
+		if err := rollback(*undo); err != nil {
+			fmt.Printf("Rollback failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	agentsDir := filepath.Join(*dataPath, "agents")
-	entries, err := os.ReadDir(agentsDir)
+	plan, err := buildPlan(agentsDir, *overwrite, *deleteMD)
 	if err != nil {
 		fmt.Printf("Failed to read agents directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	migrated := 0
-	for _, agent := range entries {
-		if !agent.IsDir() {
-			continue
-		}
-		dailyDir := filepath.Join(agentsDir, agent.Name(), "daily")
-		mdFiles, _ := filepath.Glob(filepath.Join(dailyDir, "*.md"))
-		if len(mdFiles) == 0 {
-			continue
-		}
-		for _, mdPath := range mdFiles {
-			date := strings.TrimSuffix(filepath.Base(mdPath), ".md")
-			jsonPath := filepath.Join(dailyDir, date+".jsonl")
-			if !*overwrite {
-				if _, err := os.Stat(jsonPath); err == nil {
-					continue
-				}
-			}
-			entries, err := parseDailyMarkdown(mdPath)
-			if err != nil {
-				fmt.Printf("Failed to parse %s: %v\n", mdPath, err)
-				continue
-			}
-			if len(entries) == 0 {
-				continue
-			}
-			if err := writeJSONL(jsonPath, entries); err != nil {
-				fmt.Printf("Failed to write %s: %v\n", jsonPath, err)
-				continue
-			}
-			migrated++
-			if *deleteMD {
-				_ = os.Remove(mdPath)
-			}
-		}
+	if *dryRun {
+		printPlan(os.Stdout, plan)
+		printSummary(os.Stdout, plan)
+		fmt.Println("Dry run: no files were changed.")
+		return
+	}
+
+	logPath := *undoLog
+	if logPath == "" {
+		logPath = filepath.Join(*dataPath, "migrations", fmt.Sprintf("daily-notes-%s.undo.json", time.Now().Format("20060102-150405")))
+	}
+	ulog, err := createUndoLog(logPath)
+	if err != nil {
+		fmt.Printf("Failed to create undo log %s: %v\n", logPath, err)
+		os.Exit(1)
+	}
+	migrated, err := applyPlan(plan, ulog)
+	closeErr := ulog.Close()
+	printSummary(os.Stdout, plan)
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Printf("Migration stopped: %v\n", err)
+		fmt.Printf("Changes made so far are in %s (roll back with -undo %s)\n", logPath, logPath)
+		os.Exit(1)
+	}
+	if ulog.n == 0 {
+		os.Remove(logPath)
+	} else {
+		fmt.Printf("Undo log: %s (roll back with -undo %s)\n", logPath, logPath)
 	}
 
 	fmt.Printf("Migration complete. Files migrated: %d\n", migrated)
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	actionCreate    = "create"
+	actionOverwrite = "overwrite"
+	actionSkip      = "skip"
+)
+
+// fileOp is the planned migration of one daily .md file.
+type fileOp struct {
+	Agent      string
+	Date       string
+	MDPath     string
+	JSONPath   string
+	Action     string // create | overwrite | skip
+	SkipReason string
+	Entries    []DailyEntry
+	OldEntries int  // lines in the existing .jsonl being overwritten
+	DeleteMD   bool // remove the .md after writing
+	Err        error
+}
+
+// buildPlan scans every agent's daily directory and decides what to do with
+// each .md file without changing anything on disk.
+func buildPlan(agentsDir string, overwrite, deleteMD bool) ([]*fileOp, error) {
+	agents, err := os.ReadDir(agentsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([]*fileOp, 0)
+	for _, agent := range agents {
+		if !agent.IsDir() {
+			continue
+		}
+		dailyDir := filepath.Join(agentsDir, agent.Name(), "daily")
+		mdFiles, _ := filepath.Glob(filepath.Join(dailyDir, "*.md"))
+		sort.Strings(mdFiles)
+		for _, mdPath := range mdFiles {
+			date := strings.TrimSuffix(filepath.Base(mdPath), ".md")
+			op := &fileOp{
+				Agent:    agent.Name(),
+				Date:     date,
+				MDPath:   mdPath,
+				JSONPath: filepath.Join(dailyDir, date+".jsonl"),
+				Action:   actionCreate,
+			}
+			plan = append(plan, op)
+
+			if n, err := countLines(op.JSONPath); err == nil {
+				if !overwrite {
+					op.Action, op.SkipReason = actionSkip, ".jsonl exists (use -overwrite)"
+					continue
+				}
+				op.Action, op.OldEntries = actionOverwrite, n
+			}
+			entries, err := parseDailyMarkdown(mdPath)
+			if err != nil {
+				op.Action, op.SkipReason = actionSkip, fmt.Sprintf("parse error: %v", err)
+				continue
+			}
+			if len(entries) == 0 {
+				op.Action, op.SkipReason = actionSkip, "no entries"
+				continue
+			}
+			op.Entries = entries
+			op.DeleteMD = deleteMD
+		}
+	}
+	return plan, nil
+}
+
+func countLines(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	lines, err := readLines(file)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// printPlan lists every file the migration would touch.
+func printPlan(w io.Writer, plan []*fileOp) {
+	for _, op := range plan {
+		switch op.Action {
+		case actionCreate:
+			fmt.Fprintf(w, "create     %s (%d entries)\n", op.JSONPath, len(op.Entries))
+		case actionOverwrite:
+			fmt.Fprintf(w, "overwrite  %s (%d -> %d entries)\n", op.JSONPath, op.OldEntries, len(op.Entries))
+		case actionSkip:
+			fmt.Fprintf(w, "skip       %s (%s)\n", op.MDPath, op.SkipReason)
+			continue
+		}
+		if op.DeleteMD {
+			fmt.Fprintf(w, "delete     %s\n", op.MDPath)
+		}
+	}
+}
+
+// printSummary prints a per-agent table. Before applying it shows planned
+// counts; after applying, failed files are moved to the FAILED column.
+func printSummary(w io.Writer, plan []*fileOp) {
+	type row struct{ create, overwrite, del, skip, failed, entries int }
+	rows := make(map[string]*row)
+	agents := make([]string, 0)
+	total := &row{}
+	for _, op := range plan {
+		r, ok := rows[op.Agent]
+		if !ok {
+			r = &row{}
+			rows[op.Agent] = r
+			agents = append(agents, op.Agent)
+		}
+		for _, t := range []*row{r, total} {
+			switch {
+			case op.Err != nil:
+				t.failed++
+			case op.Action == actionSkip:
+				t.skip++
+			default:
+				if op.Action == actionCreate {
+					t.create++
+				} else {
+					t.overwrite++
+				}
+				if op.DeleteMD {
+					t.del++
+				}
+				t.entries += len(op.Entries)
+			}
+		}
+	}
+	sort.Strings(agents)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "AGENT\tCREATE\tOVERWRITE\tDELETE\tSKIP\tFAILED\tENTRIES\t")
+	for _, agent := range agents {
+		r := rows[agent]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t\n", agent, r.create, r.overwrite, r.del, r.skip, r.failed, r.entries)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%d\t%d\t%d\t%d\t%d\t\n", total.create, total.overwrite, total.del, total.skip, total.failed, total.entries)
+	tw.Flush()
+}
+
+// applyPlan performs the planned writes and deletions. Each change is
+// appended to the undo log, with any content it replaces, before it is made,
+// so the log is never behind the files on disk. It returns the number of
+// files migrated and stops at the first undo log write failure.
+func applyPlan(plan []*fileOp, ulog *undoWriter) (int, error) {
+	todo := 0
+	for _, op := range plan {
+		if op.Action != actionSkip {
+			todo++
+		}
+	}
+
+	done, migrated := 0, 0
+	for _, op := range plan {
+		if op.Action == actionSkip {
+			continue
+		}
+		done++
+		fmt.Printf("[%d/%d] %s %s: %s (%d entries)\n", done, todo, op.Agent, op.Date, op.Action, len(op.Entries))
+
+		entry := undoOp{Path: op.JSONPath, Action: "created"}
+		if op.Action == actionOverwrite {
+			data, err := os.ReadFile(op.JSONPath)
+			if err != nil {
+				op.Err = err
+				fmt.Printf("Failed to back up %s: %v\n", op.JSONPath, err)
+				continue
+			}
+			entry = undoOp{Path: op.JSONPath, Action: "overwritten", Backup: string(data)}
+		}
+		if err := ulog.record(entry); err != nil {
+			op.Err = err
+			return migrated, err
+		}
+		if err := writeJSONL(op.JSONPath, op.Entries); err != nil {
+			op.Err = err
+			fmt.Printf("Failed to write %s: %v\n", op.JSONPath, err)
+			continue
+		}
+		migrated++
+
+		if op.DeleteMD {
+			data, err := os.ReadFile(op.MDPath)
+			if err != nil {
+				fmt.Printf("Keeping %s: %v\n", op.MDPath, err)
+				op.DeleteMD = false
+				continue
+			}
+			if err := ulog.record(undoOp{Path: op.MDPath, Action: "deleted", Backup: string(data)}); err != nil {
+				op.DeleteMD = false
+				return migrated, err
+			}
+			if err := os.Remove(op.MDPath); err != nil {
+				fmt.Printf("Failed to delete %s: %v\n", op.MDPath, err)
+				op.DeleteMD = false
+				continue
+			}
+		}
+	}
+	return migrated, nil
+}
+
+// undoLog is the header of an undo log file. It is followed by one undoOp
+// per line in the order the changes were made. Logs written by older
+// versions hold every op in Ops instead.
+type undoLog struct {
+	CreatedAt time.Time `json:"created_at"`
+	Ops       []undoOp  `json:"ops,omitempty"`
+}
+
+type undoOp struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`           // created | overwritten | deleted
+	Backup string `json:"backup,omitempty"` // previous content for overwritten/deleted
+}
+
+// undoWriter appends ops to an undo log file, syncing after each one.
+type undoWriter struct {
+	path string
+	f    *os.File
+	enc  *json.Encoder
+	n    int
+}
+
+// createUndoLog creates the undo log and writes its header. It fails if the
+// file already exists so an earlier log is never clobbered.
+func createUndoLog(path string) (*undoWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w := &undoWriter{path: path, f: f, enc: json.NewEncoder(f)}
+	if err := w.enc.Encode(undoLog{CreatedAt: time.Now()}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *undoWriter) record(op undoOp) error {
+	if err := w.enc.Encode(op); err != nil {
+		return fmt.Errorf("write undo log %s: %w", w.path, err)
+	}
+	if err := w.f.Sync(); err != nil {
+		return fmt.Errorf("sync undo log %s: %w", w.path, err)
+	}
+	w.n++
+	return nil
+}
+
+func (w *undoWriter) Close() error {
+	return w.f.Close()
+}
+
+// readUndoLog decodes an undo log, tolerating a final op cut short by a
+// crash while it was being appended: that change had not been made yet.
+func readUndoLog(path string) (*undoLog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	var ulog undoLog
+	if err := dec.Decode(&ulog); err != nil {
+		return nil, err
+	}
+	for {
+		var op undoOp
+		err := dec.Decode(&op)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ulog.Ops = append(ulog.Ops, op)
+	}
+	return &ulog, nil
+}
+
+// rollback replays an undo log in reverse: created files are removed and
+// overwritten or deleted files get their previous content back.
+func rollback(path string) error {
+	ulog, err := readUndoLog(path)
+	if err != nil {
+		return err
+	}
+
+	restored, failed := 0, 0
+	for i := len(ulog.Ops) - 1; i >= 0; i-- {
+		op := ulog.Ops[i]
+		var err error
+		switch op.Action {
+		case "created":
+			err = os.Remove(op.Path)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		case "overwritten", "deleted":
+			if err = os.MkdirAll(filepath.Dir(op.Path), 0755); err == nil {
+				err = os.WriteFile(op.Path, []byte(op.Backup), 0644)
+			}
+		default:
+			err = fmt.Errorf("unknown action %q", op.Action)
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("Failed to undo %s %s: %v\n", op.Action, op.Path, err)
+			continue
+		}
+		restored++
+		fmt.Printf("undo %-11s %s\n", op.Action, op.Path)
+	}
+
+	fmt.Printf("Rollback complete. Restored: %d, failed: %d (log from %s)\n", restored, failed, ulog.CreatedAt.Format(time.RFC3339))
+	if failed > 0 {
+		return fmt.Errorf("%d operation(s) could not be undone", failed)
+	}
+	return nil
+}
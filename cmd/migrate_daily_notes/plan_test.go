@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// snapshot returns the content of every regular file under dir.
+func snapshot(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[path] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateDryRunApplyRollback(t *testing.T) {
+	dataDir := t.TempDir()
+	agentsDir := filepath.Join(dataDir, "agents")
+	daily := filepath.Join(agentsDir, "agent-1", "daily")
+	writeFile(t, filepath.Join(daily, "2026-01-01.md"),
+		"2026-01-01T09:00:00 | prompt: read | reply: done\nfollow up on lemma 2\n\n2026-01-01T10:00:00 | prompt: post | reply: ok\n")
+	writeFile(t, filepath.Join(daily, "2026-01-02.md"), "2026-01-02T09:00:00 | prompt: review | reply: accepted\n")
+	writeFile(t, filepath.Join(daily, "2026-01-02.jsonl"), "{\"timestamp\":\"old\"}\n")
+	writeFile(t, filepath.Join(agentsDir, "agent-2", "daily", "2026-01-01.md"), "\n\n")
+	before := snapshot(t, dataDir)
+
+	// Dry run: plan and print, nothing on disk changes.
+	plan, err := buildPlan(agentsDir, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printPlan(&out, plan)
+	for _, want := range []string{"create     " + filepath.Join(daily, "2026-01-01.jsonl") + " (2 entries)",
+		"overwrite  " + filepath.Join(daily, "2026-01-02.jsonl") + " (1 -> 1 entries)", "no entries"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("plan missing %q:\n%s", want, out.String())
+		}
+	}
+	if after := snapshot(t, dataDir); !equalFiles(before, after) {
+		t.Fatalf("dry run changed files: %v", after)
+	}
+
+	// Apply: .jsonl files written, .md files removed, undo log on disk.
+	logPath := filepath.Join(dataDir, "migrations", "test.undo.json")
+	ulog, err := createUndoLog(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated, err := applyPlan(plan, ulog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ulog.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if migrated != 2 || ulog.n != 4 {
+		t.Fatalf("migrated %d files with %d undo ops, want 2 and 4", migrated, ulog.n)
+	}
+	if _, err := os.Stat(filepath.Join(daily, "2026-01-01.md")); !os.IsNotExist(err) {
+		t.Errorf("2026-01-01.md not deleted: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(daily, "2026-01-02.jsonl"))
+	if err != nil || !strings.Contains(string(data), `"reply":"accepted"`) {
+		t.Errorf("2026-01-02.jsonl not overwritten: %q, %v", data, err)
+	}
+	if _, err := createUndoLog(logPath); err == nil {
+		t.Error("createUndoLog overwrote an existing log")
+	}
+
+	// Rollback restores the original tree byte for byte.
+	if err := rollback(logPath); err != nil {
+		t.Fatal(err)
+	}
+	after := snapshot(t, dataDir)
+	delete(after, logPath)
+	if !equalFiles(before, after) {
+		t.Fatalf("rollback did not restore files:\nbefore %q\nafter  %q", before, after)
+	}
+}
+
+func TestRollbackTruncatedLog(t *testing.T) {
+	dir := t.TempDir()
+	created := filepath.Join(dir, "created.jsonl")
+	writeFile(t, created, "{}\n")
+	logPath := filepath.Join(dir, "undo.json")
+	writeFile(t, logPath, `{"created_at":"2026-01-01T00:00:00Z"}`+"\n"+
+		`{"path":"`+created+`","action":"created"}`+"\n"+
+		`{"path":"`+filepath.Join(dir, "x.md")+`","action":"del`)
+
+	if err := rollback(logPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("created file not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "x.md")); !os.IsNotExist(err) {
+		t.Errorf("truncated op was replayed: %v", err)
+	}
+}
+
+func equalFiles(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, data := range a {
+		if other, ok := b[path]; !ok || !bytes.Equal(data, other) {
+			return false
+		}
+	}
+	return true
+}
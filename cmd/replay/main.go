@@ -0,0 +1,178 @@
+// Command replay rebuilds the forum, journal and workflow from the
+// structured tool outcomes in a run's logs*.jsonl and checks the result
+// against the persisted JSON. With -out it also writes the rebuilt stores,
+// e.g. to recover from a corrupted forum.json.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+)
+
+func main() {
+	dataPath := flag.String("data", "./data/adk-simulation", "Data directory (contains logs*.jsonl, forum/, journal/, workflow/)")
+	outPath := flag.String("out", "", "Write the rebuilt forum/, journal/ and workflow/ under this directory")
+	limit := flag.Int("limit", 20, "Max differences listed per category")
+	flag.Parse()
+
+	logNames := discoverLogs(*dataPath)
+	if len(logNames) == 0 {
+		log.Fatalf("No logs*.jsonl in %s", *dataPath)
+	}
+	events, err := readLogEvents(*dataPath, logNames)
+	if err != nil {
+		log.Fatalf("Read logs: %v", err)
+	}
+	withOutcomes := 0
+	for _, ev := range events {
+		if len(ev.Outcomes) > 0 {
+			withOutcomes++
+		}
+	}
+	fmt.Printf("Replaying %d events from %d log file(s); %d record tool outcomes\n", len(events), len(logNames), withOutcomes)
+
+	persistedForum := publication.NewForum("", filepath.Join(*dataPath, "forum"))
+	if err := persistedForum.Load(); err != nil {
+		log.Printf("Load forum: %v (comparing against an empty forum)", err)
+	}
+	persistedJournal := publication.NewJournal("", filepath.Join(*dataPath, "journal"))
+	if err := persistedJournal.Load(); err != nil {
+		log.Printf("Load journal: %v (comparing against an empty journal)", err)
+	}
+	persistedWorkflow := publication.NewWorkflow(filepath.Join(*dataPath, "workflow"))
+	if err := persistedWorkflow.Load(); err != nil {
+		log.Printf("Load workflow: %v (comparing against an empty workflow)", err)
+	}
+
+	// Without -out the rebuilt stores stay in memory; an empty data path
+	// also keeps the forum from writing a WAL.
+	out := *outPath
+	storePath := func(name string) string {
+		if out == "" {
+			return ""
+		}
+		return filepath.Join(out, name)
+	}
+	forum := publication.NewForum(persistedForum.Name, storePath("forum"))
+	journal := publication.NewJournal(persistedJournal.Name, storePath("journal"))
+	if len(persistedJournal.Journals) > 0 {
+		if err := journal.SetJournals(persistedJournal.Journals); err != nil {
+			log.Printf("Copy journal list: %v", err)
+		}
+	}
+	workflow := publication.NewWorkflow(storePath("workflow"))
+
+	r := newReplayer(forum, journal, workflow)
+	r.replay(events)
+
+	tools := make([]string, 0, len(r.applied))
+	for tool := range r.applied {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		fmt.Printf("  %-16s %d\n", tool, r.applied[tool])
+	}
+	if len(r.skipped) > 0 {
+		fmt.Printf("Skipped %d outcome(s):\n", len(r.skipped))
+		for i, s := range r.skipped {
+			if i == *limit {
+				fmt.Printf("  ... %d more\n", len(r.skipped)-i)
+				break
+			}
+			fmt.Printf("  %s\n", s)
+		}
+	}
+
+	ok := true
+	for _, rp := range []*report{
+		r.verifyForum(persistedForum),
+		r.verifyJournal(persistedJournal),
+		r.verifyWorkflow(persistedWorkflow),
+	} {
+		rp.print(os.Stdout, *limit)
+		ok = ok && rp.ok()
+	}
+
+	if out != "" {
+		if err := forum.Save(); err != nil {
+			log.Fatalf("Save forum: %v", err)
+		}
+		if err := journal.Save(); err != nil {
+			log.Fatalf("Save journal: %v", err)
+		}
+		if err := workflow.Save(); err != nil {
+			log.Fatalf("Save workflow: %v", err)
+		}
+		fmt.Printf("Rebuilt stores written to %s\n", out)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// discoverLogs lists logs*.jsonl in dataPath, archived ones included.
+func discoverLogs(dataPath string) []string {
+	entries, err := os.ReadDir(dataPath)
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(strings.TrimSuffix(name, feed.GzipExt), ".jsonl") {
+			continue
+		}
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// readLogEvents reads the named logs in order. Events keep their order
+// within a file; files are merged by sim time.
+func readLogEvents(dataPath string, names []string) ([]simulation.EventLog, error) {
+	out := make([]simulation.EventLog, 0, 1024)
+	for _, name := range names {
+		f, err := feed.OpenJSONL(filepath.Join(dataPath, name))
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var ev simulation.EventLog
+			if err := json.Unmarshal([]byte(line), &ev); err != nil {
+				continue
+			}
+			out = append(out, ev)
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].SimTime.Before(out[j].SimTime)
+	})
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+)
+
+func writeLog(t *testing.T, path string, events ...simulation.EventLog) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// replayDir replays the logs under dataPath into empty in-memory stores.
+func replayDir(t *testing.T, dataPath string) *replayer {
+	t.Helper()
+	events, err := readLogEvents(dataPath, discoverLogs(dataPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newReplayer(publication.NewForum("F", ""), publication.NewJournal("J", ""), publication.NewWorkflow(""))
+	r.replay(events)
+	return r
+}
+
+func TestReplay_SameAfterCompaction(t *testing.T) {
+	dataPath := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	writeLog(t, filepath.Join(dataPath, "logs-20260301.jsonl"), simulation.EventLog{
+		SimTime: day1, Tick: 1, AgentID: "ada", AgentName: "Ada", Action: "turn",
+		Outcomes: []simulation.ToolOutcome{{Tool: "create_post",
+			Args:   map[string]string{"title": "Tired light"},
+			Text:   map[string]string{"content": "Redshift without expansion"},
+			Result: map[string]string{"post_id": "post-1"}}},
+	})
+	writeLog(t, filepath.Join(dataPath, "logs.jsonl"), simulation.EventLog{
+		SimTime: day1.Add(24 * time.Hour), Tick: 2, AgentID: "bo", AgentName: "Bo", Action: "turn",
+		Outcomes: []simulation.ToolOutcome{{Tool: "comment",
+			Args:   map[string]string{"parent_id": "post-1"},
+			Text:   map[string]string{"content": "Redshift drift rules it out"},
+			Result: map[string]string{"comment_id": "comment-1"}}},
+	})
+
+	before := replayDir(t, dataPath)
+	if err := feed.CompressFile(filepath.Join(dataPath, "logs-20260301.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	after := replayDir(t, dataPath)
+
+	want := map[string]int{"create_post": 1, "comment": 1}
+	if !reflect.DeepEqual(before.applied, want) || len(before.skipped) != 0 {
+		t.Fatalf("applied %v, skipped %v before compaction, want %v", before.applied, before.skipped, want)
+	}
+	if !reflect.DeepEqual(after.applied, before.applied) || len(after.skipped) != 0 {
+		t.Errorf("applied %v, skipped %v after compaction, want %v", after.applied, after.skipped, before.applied)
+	}
+	if after.forum.Get("comment-1") == nil {
+		t.Error("comment from the live log not rebuilt on the archived post")
+	}
+}
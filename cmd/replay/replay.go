@@ -0,0 +1,427 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// errNotYet marks an outcome that depends on content the replay has not
+// created yet, such as a comment on a post from a turn logged later in the
+// same tick.
+var errNotYet = errors.New("depends on content not replayed yet")
+
+// replayer rebuilds the forum, journal and workflow from logged tool
+// outcomes. Only mutations made through agent tools are in the log; review
+// decisions, editor escalations and other system actions are not replayed.
+type replayer struct {
+	forum    *publication.Forum
+	journal  *publication.Journal
+	workflow *publication.Workflow
+
+	applied map[string]int // outcomes replayed, by tool
+	skipped []string       // outcomes that could not be replayed, with reasons
+	// textless holds IDs rebuilt from outcomes without free text (logs
+	// written before outcomes kept it), so their bodies are not compared.
+	textless map[string]bool
+}
+
+func newReplayer(forum *publication.Forum, journal *publication.Journal, workflow *publication.Workflow) *replayer {
+	return &replayer{
+		forum:    forum,
+		journal:  journal,
+		workflow: workflow,
+		applied:  make(map[string]int),
+		textless: make(map[string]bool),
+	}
+}
+
+type pendingOutcome struct {
+	ev  *simulation.EventLog
+	out simulation.ToolOutcome
+}
+
+// replay applies the outcomes of events, which must be in log order. Turns
+// in a tick run concurrently but are logged in selection order, so outcomes
+// that fail for want of content are retried once at the end of their tick.
+func (r *replayer) replay(events []simulation.EventLog) {
+	var deferred []pendingOutcome
+	flush := func() {
+		for _, p := range deferred {
+			if err := r.apply(p.ev, p.out); err != nil {
+				r.skip(p.ev, p.out, err)
+			}
+		}
+		deferred = nil
+	}
+
+	tick := -1
+	for i := range events {
+		ev := &events[i]
+		if ev.Tick != tick {
+			flush()
+			tick = ev.Tick
+		}
+		for _, out := range ev.Outcomes {
+			if out.Error != "" {
+				continue
+			}
+			err := r.apply(ev, out)
+			if errors.Is(err, errNotYet) {
+				deferred = append(deferred, pendingOutcome{ev: ev, out: out})
+				continue
+			}
+			if err != nil {
+				r.skip(ev, out, err)
+			}
+		}
+	}
+	flush()
+}
+
+func (r *replayer) skip(ev *simulation.EventLog, out simulation.ToolOutcome, err error) {
+	r.skipped = append(r.skipped, fmt.Sprintf("tick %d %s %s: %v", ev.Tick, ev.AgentID, out.Tool, err))
+}
+
+// apply replays one successful tool call. Tools that do not change the
+// stores are ignored.
+func (r *replayer) apply(ev *simulation.EventLog, out simulation.ToolOutcome) error {
+	var err error
+	switch out.Tool {
+	case "create_subreddit":
+		err = r.createSubreddit(ev, out)
+	case "create_post":
+		err = r.createPost(ev, out)
+	case "comment":
+		err = r.comment(ev, out)
+	case "vote":
+		err = r.vote(ev, out)
+	case "submit_paper":
+		err = r.submitPaper(ev, out)
+	case "review_paper":
+		err = r.reviewPaper(ev, out)
+	default:
+		return nil
+	}
+	if err == nil {
+		r.applied[out.Tool]++
+	}
+	return err
+}
+
+func (r *replayer) createSubreddit(ev *simulation.EventLog, out simulation.ToolOutcome) error {
+	return r.forum.CreateSubreddit(&types.SubredditInfo{
+		Name:        types.Subreddit(out.Args["name"]),
+		Description: out.Text["description"],
+		CreatorID:   ev.AgentID,
+		CreatorName: ev.AgentName,
+	})
+}
+
+func (r *replayer) createPost(ev *simulation.EventLog, out simulation.ToolOutcome) error {
+	id := out.Result["post_id"]
+	if id == "" {
+		return errors.New("no post_id in result")
+	}
+	// Mirror create_post: unknown subreddits fall back to general.
+	sub := publication.NormalizeSubreddit(out.Args["subreddit"])
+	if sub == "" || !r.forum.HasSubreddit(sub) {
+		sub = types.SubGeneral
+	}
+	pub := &types.Publication{
+		ID:         id,
+		AuthorID:   ev.AgentID,
+		AuthorName: ev.AgentName,
+		Title:      out.Args["title"],
+		Content:    out.Text["content"],
+		Abstract:   out.Text["abstract"],
+		Subreddit:  sub,
+	}
+	if err := r.forum.Post(pub); err != nil {
+		return err
+	}
+	pub.PublishedAt = ev.Timestamp
+	r.noteText(id, out)
+	return nil
+}
+
+func (r *replayer) comment(ev *simulation.EventLog, out simulation.ToolOutcome) error {
+	id := out.Result["comment_id"]
+	if id == "" {
+		return errors.New("no comment_id in result")
+	}
+	parentID := out.Args["parent_id"]
+	if parentID == "" {
+		parentID = out.Args["post_id"]
+	}
+	if r.forum.Get(parentID) == nil {
+		return fmt.Errorf("parent %s: %w", parentID, errNotYet)
+	}
+	c := &types.Publication{
+		ID:         id,
+		AuthorID:   ev.AgentID,
+		AuthorName: ev.AgentName,
+		Content:    out.Text["content"],
+	}
+	if err := r.forum.Comment(parentID, c); err != nil {
+		return err
+	}
+	c.PublishedAt = ev.Timestamp
+	r.noteText(id, out)
+	return nil
+}
+
+func (r *replayer) vote(ev *simulation.EventLog, out simulation.ToolOutcome) error {
+	postID := out.Args["post_id"]
+	if r.forum.Get(postID) == nil {
+		return fmt.Errorf("post %s: %w", postID, errNotYet)
+	}
+	switch out.Args["vote_type"] {
+	case "upvote":
+		return r.forum.Upvote(ev.AgentID, postID)
+	case "downvote":
+		return r.forum.Downvote(ev.AgentID, postID)
+	}
+	return fmt.Errorf("unknown vote type %q", out.Args["vote_type"])
+}
+
+func (r *replayer) submitPaper(ev *simulation.EventLog, out simulation.ToolOutcome) error {
+	id := out.Result["submission_id"]
+	if id == "" {
+		return errors.New("no submission_id in result")
+	}
+	pub := &types.Publication{
+		ID:             id,
+		AuthorID:       ev.AgentID,
+		AuthorName:     ev.AgentName,
+		Title:          out.Args["title"],
+		Abstract:       out.Text["abstract"],
+		Content:        out.Text["content"],
+		DraftID:        out.Args["draft_id"],
+		ResubmissionOf: out.Args["resubmission_of"],
+		JournalID:      out.Result["journal_id"],
+	}
+	if err := r.journal.Submit(pub); err != nil {
+		return err
+	}
+	pub.PublishedAt = ev.Timestamp
+	r.workflow.AddSubmission(&types.Submission{
+		ID:             id,
+		DraftID:        pub.DraftID,
+		Title:          pub.Title,
+		Abstract:       pub.Abstract,
+		Content:        pub.Content,
+		AuthorID:       pub.AuthorID,
+		AuthorName:     pub.AuthorName,
+		JournalID:      pub.JournalID,
+		Status:         types.SubmissionPending,
+		CreatedAt:      ev.Timestamp,
+		ResubmissionOf: pub.ResubmissionOf,
+	})
+	r.noteText(id, out)
+	return nil
+}
+
+func (r *replayer) reviewPaper(ev *simulation.EventLog, out simulation.ToolOutcome) error {
+	id := out.Result["review_id"]
+	if id == "" {
+		return errors.New("no review_id in result")
+	}
+	subID := out.Args["submission_id"]
+	if r.workflow.GetSubmission(subID) == nil {
+		return fmt.Errorf("submission %s: %w", subID, errNotYet)
+	}
+	review := &types.PaperReview{
+		ID:           id,
+		SubmissionID: subID,
+		ReviewerID:   ev.AgentID,
+		ReviewerName: ev.AgentName,
+		Verdict:      types.PaperReviewVerdict(strings.ToLower(strings.TrimSpace(out.Args["verdict"]))),
+		Comments:     out.Text["comments"],
+		CreatedAt:    ev.Timestamp,
+	}
+	if scores := out.Args["scores"]; scores != "" {
+		if err := json.Unmarshal([]byte(scores), &review.Scores); err != nil {
+			return fmt.Errorf("scores: %w", err)
+		}
+	}
+	r.workflow.AddReview(review)
+	r.workflow.AttachReview(subID, id)
+	r.noteText(id, out)
+	return nil
+}
+
+func (r *replayer) noteText(id string, out simulation.ToolOutcome) {
+	if out.Text == nil {
+		r.textless[id] = true
+	}
+}
+
+// report compares one rebuilt store against its persisted JSON.
+type report struct {
+	store    string
+	checked  int
+	missing  []string // replayed but absent from the persisted store
+	mismatch []string // present in both but different
+	extra    []string // persisted but never created by a logged tool call
+}
+
+func (rp *report) ok() bool { return len(rp.missing) == 0 && len(rp.mismatch) == 0 }
+
+func (rp *report) print(w io.Writer, limit int) {
+	status := "OK"
+	if !rp.ok() {
+		status = "DIVERGED"
+	}
+	fmt.Fprintf(w, "%-8s %s: %d checked, %d missing, %d mismatched, %d not in log\n",
+		rp.store, status, rp.checked, len(rp.missing), len(rp.mismatch), len(rp.extra))
+	for _, list := range []struct {
+		label string
+		items []string
+	}{{"missing", rp.missing}, {"mismatch", rp.mismatch}, {"not in log", rp.extra}} {
+		sort.Strings(list.items)
+		for i, item := range list.items {
+			if i == limit {
+				fmt.Fprintf(w, "  %s: ... %d more\n", list.label, len(list.items)-limit)
+				break
+			}
+			fmt.Fprintf(w, "  %s: %s\n", list.label, item)
+		}
+	}
+}
+
+// compare records a mismatch for each differing field of id. Fields are
+// name, replayed, persisted triples.
+func (rp *report) compare(id string, fields ...string) {
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+1] != fields[i+2] {
+			rp.mismatch = append(rp.mismatch, fmt.Sprintf("%s %s: replayed %q, persisted %q", id, fields[i], clip(fields[i+1]), clip(fields[i+2])))
+		}
+	}
+}
+
+func clip(s string) string {
+	if len(s) > 60 {
+		return s[:60] + "..."
+	}
+	return s
+}
+
+// verifyForum checks replayed posts and comments, including vote tallies.
+// Mentions and timestamps are not compared.
+func (r *replayer) verifyForum(persisted *publication.Forum) *report {
+	rp := &report{store: "forum"}
+	replayed := map[string]bool{}
+	for _, pub := range r.forum.AllPublications() {
+		replayed[pub.ID] = true
+		rp.checked++
+		got := persisted.Get(pub.ID)
+		if got == nil {
+			rp.missing = append(rp.missing, pub.ID)
+			continue
+		}
+		rp.compare(pub.ID,
+			"author", pub.AuthorID, got.AuthorID,
+			"parent", pub.ParentID, got.ParentID,
+			"title", pub.Title, got.Title,
+			"subreddit", string(pub.Subreddit), string(got.Subreddit),
+			"upvotes", fmt.Sprint(pub.Upvotes), fmt.Sprint(got.Upvotes),
+			"downvotes", fmt.Sprint(pub.Downvotes), fmt.Sprint(got.Downvotes),
+		)
+		if !r.textless[pub.ID] {
+			rp.compare(pub.ID, "content", pub.Content, got.Content, "abstract", pub.Abstract, got.Abstract)
+		}
+	}
+	for _, pub := range persisted.AllPublications() {
+		if !replayed[pub.ID] {
+			rp.extra = append(rp.extra, pub.ID)
+		}
+	}
+	return rp
+}
+
+// verifyJournal checks that each replayed submission reached the persisted
+// journal, in whatever state later decisions left it.
+func (r *replayer) verifyJournal(persisted *publication.Journal) *report {
+	rp := &report{store: "journal"}
+	stored := map[string]*types.Publication{}
+	for _, m := range []map[string]*types.Publication{persisted.Publications, persisted.Pending, persisted.Rejected} {
+		for id, pub := range m {
+			stored[id] = pub
+		}
+	}
+	for _, pub := range r.journal.GetPending() {
+		id := pub.ID
+		rp.checked++
+		got := stored[id]
+		delete(stored, id)
+		if got == nil {
+			rp.missing = append(rp.missing, id)
+			continue
+		}
+		rp.compare(id,
+			"author", pub.AuthorID, got.AuthorID,
+			"title", pub.Title, got.Title,
+			"journal", pub.JournalID, got.JournalID,
+		)
+	}
+	for id := range stored {
+		rp.extra = append(rp.extra, id)
+	}
+	return rp
+}
+
+// verifyWorkflow checks submissions and reviews. Submission content is not
+// compared: submit_paper appends cited experiments and may pull it from a
+// draft.
+func (r *replayer) verifyWorkflow(persisted *publication.Workflow) *report {
+	rp := &report{store: "workflow"}
+	for id, sub := range r.workflow.Submissions {
+		rp.checked++
+		got := persisted.GetSubmission(id)
+		if got == nil {
+			rp.missing = append(rp.missing, id)
+			continue
+		}
+		rp.compare(id,
+			"author", sub.AuthorID, got.AuthorID,
+			"title", sub.Title, got.Title,
+			"journal", sub.JournalID, got.JournalID,
+		)
+	}
+	for id := range persisted.Submissions {
+		if r.workflow.Submissions[id] == nil {
+			rp.extra = append(rp.extra, id)
+		}
+	}
+
+	for subID, reviews := range r.workflow.Reviews {
+		stored := map[string]*types.PaperReview{}
+		for _, review := range persisted.Reviews[subID] {
+			stored[review.ID] = review
+		}
+		for _, review := range reviews {
+			rp.checked++
+			got := stored[review.ID]
+			if got == nil {
+				rp.missing = append(rp.missing, review.ID)
+				continue
+			}
+			rp.compare(review.ID,
+				"reviewer", review.ReviewerID, got.ReviewerID,
+				"verdict", string(review.Verdict), string(got.Verdict),
+			)
+			if !r.textless[review.ID] {
+				rp.compare(review.ID, "comments", review.Comments, got.Comments)
+			}
+		}
+	}
+	return rp
+}
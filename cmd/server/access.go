@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// apiKeyHeader carries the -api-key. Human accounts keep using
+// Authorization for their own tokens, so a write can present both.
+const apiKeyHeader = "X-API-Key"
+
+// guardAccess wraps next with the API key check and, outside it, CORS
+// handling for origins. An empty key leaves every route open.
+func guardAccess(next http.Handler, key string, origins []string) http.Handler {
+	if key != "" {
+		next = requireAPIKey(key, next)
+	}
+	if len(origins) > 0 {
+		next = allowCORS(origins, next)
+	}
+	return next
+}
+
+// allowCORS lets pages on origins embed the viewer and call the API from
+// the browser. origins lists exact origins such as "https://example.org",
+// or "*" for any; requests from other origins get no CORS headers. Preflight
+// requests are answered here, before any API key check, since browsers
+// send them without credentials.
+func allowCORS(origins []string, next http.Handler) http.Handler {
+	anyOrigin := slices.Contains(origins, "*")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!anyOrigin && !slices.Contains(origins, origin)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		h.Set("Access-Control-Expose-Headers", "ETag")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, HEAD, POST, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, If-None-Match, "+apiKeyHeader)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAPIKey guards write and admin endpoints with a shared key: every
+// request that isn't a read (GET, HEAD, OPTIONS), and /metrics, must send
+// it in the X-API-Key header or as a bearer token. Reads of the viewer and
+// API stay open, as do spectators' questions and votes (see
+// spectatorInbox), which exist for people without credentials.
+func requireAPIKey(key string, next http.Handler) http.Handler {
+	want := sha256.Sum256([]byte(key))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !needsAPIKey(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		got := r.Header.Get(apiKeyHeader)
+		if got == "" {
+			got, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		sum := sha256.Sum256([]byte(strings.TrimSpace(got)))
+		if got == "" || subtle.ConstantTimeCompare(sum[:], want[:]) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{
+				"error": "missing or invalid API key (send it in the " + apiKeyHeader + " header)",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func needsAPIKey(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return r.URL.Path == "/metrics"
+	case http.MethodPost:
+		if r.URL.Path == "/api/inbox" {
+			return false
+		}
+		if rest, ok := strings.CutPrefix(r.URL.Path, "/api/inbox/"); ok {
+			id, action, _ := strings.Cut(strings.Trim(rest, "/"), "/")
+			return id == "" || action != "vote"
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGuardAccess(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	const key = "s3cret"
+	origins := []string{"https://example.org"}
+
+	for _, tc := range []struct {
+		name    string
+		key     string // -api-key; empty leaves routes open
+		method  string
+		path    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "read", key: key, method: http.MethodGet, path: "/api/forum", want: http.StatusOK},
+		{name: "head", key: key, method: http.MethodHead, path: "/api/journal", want: http.StatusOK},
+		{name: "static page", key: key, method: http.MethodGet, path: "/forum", want: http.StatusOK},
+		{name: "metrics without key", key: key, method: http.MethodGet, path: "/metrics", want: http.StatusUnauthorized},
+		{name: "metrics with key", key: key, method: http.MethodGet, path: "/metrics",
+			headers: map[string]string{apiKeyHeader: key}, want: http.StatusOK},
+		{name: "write without key", key: key, method: http.MethodPost, path: "/api/forum/posts", want: http.StatusUnauthorized},
+		{name: "write with wrong key", key: key, method: http.MethodPost, path: "/api/forum/posts",
+			headers: map[string]string{apiKeyHeader: "guess"}, want: http.StatusUnauthorized},
+		{name: "write with key", key: key, method: http.MethodPost, path: "/api/forum/posts",
+			headers: map[string]string{apiKeyHeader: key}, want: http.StatusOK},
+		{name: "write with bearer key", key: key, method: http.MethodPost, path: "/api/forum/posts",
+			headers: map[string]string{"Authorization": "Bearer " + key}, want: http.StatusOK},
+		{name: "write with wrong bearer", key: key, method: http.MethodPost, path: "/api/forum/posts",
+			headers: map[string]string{"Authorization": "Bearer guess"}, want: http.StatusUnauthorized},
+		{name: "delete without key", key: key, method: http.MethodDelete, path: "/api/forum/posts/p1", want: http.StatusUnauthorized},
+		{name: "ask", key: key, method: http.MethodPost, path: "/api/inbox", want: http.StatusOK},
+		{name: "vote", key: key, method: http.MethodPost, path: "/api/inbox/q-1/vote", want: http.StatusOK},
+		{name: "vote trailing slash", key: key, method: http.MethodPost, path: "/api/inbox/q-1/vote/", want: http.StatusOK},
+		{name: "moderate without key", key: key, method: http.MethodPost, path: "/api/inbox/q-1/moderate", want: http.StatusUnauthorized},
+		{name: "inbox item without id", key: key, method: http.MethodPost, path: "/api/inbox/", want: http.StatusUnauthorized},
+		{name: "preflight without key", key: key, method: http.MethodOptions, path: "/api/forum/posts",
+			headers: map[string]string{"Origin": origins[0], "Access-Control-Request-Method": "POST"}, want: http.StatusNoContent},
+		{name: "preflight from other origin", key: key, method: http.MethodOptions, path: "/api/forum/posts",
+			headers: map[string]string{"Origin": "https://evil.example", "Access-Control-Request-Method": "POST"}, want: http.StatusOK},
+		{name: "no key: write open", method: http.MethodPost, path: "/api/forum/posts", want: http.StatusOK},
+		{name: "no key: metrics open", method: http.MethodGet, path: "/metrics", want: http.StatusOK},
+		{name: "no key: moderate open", method: http.MethodPost, path: "/api/inbox/q-1/moderate", want: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			guardAccess(next, tc.key, origins).ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Errorf("%s %s = %d, want %d (%s)", tc.method, tc.path, rec.Code, tc.want, rec.Body)
+			}
+			if tc.want == http.StatusUnauthorized && rec.Header().Get("Content-Type") == "" {
+				t.Error("401 without a JSON error body")
+			}
+		})
+	}
+}
+
+func TestAllowCORS_Headers(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	for _, tc := range []struct {
+		origins []string
+		origin  string
+		want    string // Access-Control-Allow-Origin
+	}{
+		{[]string{"https://example.org"}, "https://example.org", "https://example.org"},
+		{[]string{"https://example.org"}, "https://evil.example", ""},
+		{[]string{"*"}, "https://anywhere.example", "*"},
+		{[]string{"*"}, "", ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/forum", nil)
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		rec := httptest.NewRecorder()
+		allowCORS(tc.origins, next).ServeHTTP(rec, req)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tc.want {
+			t.Errorf("origins %v, Origin %q: Allow-Origin = %q, want %q", tc.origins, tc.origin, got, tc.want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/forum/posts", nil)
+	req.Header.Set("Origin", "https://example.org")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	allowCORS([]string{"https://example.org"}, next).ServeHTTP(rec, req)
+	if allow := rec.Header().Get("Access-Control-Allow-Headers"); !slices.Contains(splitCSV(allow), apiKeyHeader) || !slices.Contains(splitCSV(allow), "Authorization") {
+		t.Errorf("preflight Allow-Headers = %q, want Authorization and %s", allow, apiKeyHeader)
+	}
+}
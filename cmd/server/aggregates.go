@@ -0,0 +1,211 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AggregatesResponse is the public, aggregates-only view of a run. It never
+// contains post/paper text, agent IDs, or agent names.
+type AggregatesResponse struct {
+	GeneratedAt   time.Time                 `json:"generated_at"`
+	Privacy       AggregatePrivacy          `json:"privacy"`
+	Totals        map[string]int            `json:"totals"`
+	Distributions map[string]map[string]int `json:"distributions"`
+	Trends        map[string][]TrendPoint   `json:"trends"`
+}
+
+type AggregatePrivacy struct {
+	Epsilon           float64 `json:"epsilon"`   // 0 = no noise
+	MinCount          int     `json:"min_count"` // distribution buckets below this are suppressed
+	SuppressedBuckets int     `json:"suppressed_buckets"`
+}
+
+type TrendPoint struct {
+	Date  string `json:"date"` // sim date
+	Value int    `json:"value"`
+}
+
+// aggregateConfig gates and tunes the aggregates endpoint.
+type aggregateConfig struct {
+	Epsilon  float64
+	MinCount int
+	// salt makes noise stable within a server process (repeated queries can't
+	// be averaged out) but different across restarts.
+	salt string
+}
+
+func newAggregateConfig(epsilon float64, minCount int) aggregateConfig {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return aggregateConfig{Epsilon: epsilon, MinCount: minCount, salt: hex.EncodeToString(buf)}
+}
+
+// aggregatesOnly blocks every raw-data route and /metrics so only
+// /api/aggregates, its OpenAPI document and the static pages are reachable.
+func aggregatesOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := r.URL.Path
+		if (strings.HasPrefix(p, "/api/") && p != "/api/aggregates" && p != "/api/openapi.json") || strings.HasPrefix(p, "/data/") || p == "/metrics" {
+			writeJSON(w, http.StatusNotFound, map[string]any{
+				"error": "not available in aggregates-only mode",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func buildAggregates(dataPath, agentsPath string, cfg aggregateConfig) AggregatesResponse {
+	n := &aggregateNoiser{cfg: cfg}
+	totals := map[string]int{}
+	dists := map[string]map[string]int{}
+	trends := map[string][]TrendPoint{}
+
+	if agents, err := loadAgentsMerged(dataPath, agentsPath); err == nil {
+		roles := map[string]int{}
+		for _, a := range agents {
+			roles[a.Role]++
+		}
+		totals["agents"] = n.count("agents", len(agents))
+		dists["agents_by_role"] = n.buckets("agents_by_role", roles)
+	}
+
+	if forum, err := loadForum(dataPath); err == nil {
+		posts, comments, votes := 0, 0, len(forum.Votes)
+		subs := map[string]int{}
+		scores := map[string]int{}
+		for _, p := range forum.AllPublications() {
+			if p == nil {
+				continue
+			}
+			if p.IsComment {
+				comments++
+				continue
+			}
+			posts++
+			subs[string(p.Subreddit)]++
+			scores[scoreBucket(p.Score)]++
+		}
+		totals["forum_posts"] = n.count("forum_posts", posts)
+		totals["forum_comments"] = n.count("forum_comments", comments)
+		totals["forum_votes"] = n.count("forum_votes", votes)
+		dists["posts_by_subreddit"] = n.buckets("posts_by_subreddit", subs)
+		dists["post_score"] = n.buckets("post_score", scores)
+	}
+
+	if journal, err := loadJournal(dataPath); err == nil {
+		totals["journal_approved"] = n.count("journal_approved", len(journal.GetApproved()))
+		totals["journal_pending"] = n.count("journal_pending", len(journal.GetPending()))
+		_, rejected, _ := journal.AcceptanceStats()
+		totals["journal_rejected"] = n.count("journal_rejected", rejected)
+	}
+
+	statuses := map[string]int{}
+	for _, sub := range loadWorkflow(dataPath).Submissions {
+		statuses[string(sub.Status)]++
+	}
+	dists["submissions_by_status"] = n.buckets("submissions_by_status", statuses)
+
+	if events, err := loadFeedEventsAll(dataPath, 100000, feedFilter{}); err == nil {
+		actions := map[string]int{}
+		perDay := map[string]int{}
+		tokensPerDay := map[string]int{}
+		for _, ev := range events {
+			actions[ev.Action]++
+			if ev.SimTime.IsZero() {
+				continue
+			}
+			day := ev.SimTime.Format("2006-01-02")
+			perDay[day]++
+			tokensPerDay[day] += ev.TotalTokens
+		}
+		totals["events"] = n.count("events", len(events))
+		dists["actions"] = n.buckets("actions", actions)
+		trends["events_per_day"] = n.series("events_per_day", perDay)
+		trends["tokens_per_day"] = n.series("tokens_per_day", tokensPerDay)
+	}
+
+	return AggregatesResponse{
+		GeneratedAt: time.Now(),
+		Privacy: AggregatePrivacy{
+			Epsilon:           cfg.Epsilon,
+			MinCount:          cfg.MinCount,
+			SuppressedBuckets: n.suppressed,
+		},
+		Totals:        totals,
+		Distributions: dists,
+		Trends:        trends,
+	}
+}
+
+func scoreBucket(score int) string {
+	switch {
+	case score <= 0:
+		return "<=0"
+	case score == 1:
+		return "1"
+	case score <= 4:
+		return "2-4"
+	case score <= 9:
+		return "5-9"
+	default:
+		return "10+"
+	}
+}
+
+// aggregateNoiser adds Laplace noise (scale 1/epsilon) to released counts and
+// suppresses small distribution buckets.
+type aggregateNoiser struct {
+	cfg        aggregateConfig
+	suppressed int
+}
+
+func (n *aggregateNoiser) count(key string, value int) int {
+	if n.cfg.Epsilon <= 0 {
+		return value
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(n.cfg.salt + "|" + key))
+	// Mix in the true value so noise changes when the data changes.
+	u := float64((h.Sum64()^uint64(value)*0x9e3779b97f4a7c15)%1_000_000)/1_000_000 - 0.5
+	if u == -0.5 {
+		u = 0
+	}
+	scale := 1 / n.cfg.Epsilon
+	noise := -scale * math.Copysign(math.Log(1-2*math.Abs(u)), u)
+	return max(0, int(math.Round(float64(value)+noise)))
+}
+
+func (n *aggregateNoiser) buckets(key string, values map[string]int) map[string]int {
+	out := make(map[string]int, len(values))
+	for k, v := range values {
+		if k == "" {
+			k = "unknown"
+		}
+		noisy := n.count(key+"/"+k, v)
+		if noisy < n.cfg.MinCount {
+			n.suppressed++
+			continue
+		}
+		out[k] += noisy
+	}
+	return out
+}
+
+// series noises a per-day trend; days are kept even when small so the time
+// axis stays continuous.
+func (n *aggregateNoiser) series(key string, values map[string]int) []TrendPoint {
+	out := make([]TrendPoint, 0, len(values))
+	for day, v := range values {
+		out = append(out, TrendPoint{Date: day, Value: n.count(key+"/"+day, v)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
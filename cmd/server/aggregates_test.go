@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestAggregatesOnly_BlocksRawRoutes(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for path, want := range map[string]int{
+		"/api/forum":              http.StatusNotFound,
+		"/api/forum/posts/post-1": http.StatusNotFound,
+		"/api/feed":               http.StatusNotFound,
+		"/api/inbox":              http.StatusNotFound,
+		"/api/aggregates/extra":   http.StatusNotFound,
+		"/data/forum/forum.json":  http.StatusNotFound,
+		"/data/logs.jsonl":        http.StatusNotFound,
+		"/metrics":                http.StatusNotFound,
+		"/api/aggregates":         http.StatusOK,
+		"/api/openapi.json":       http.StatusOK,
+		"/":                       http.StatusOK,
+		"/forum":                  http.StatusOK,
+		"/assets/data.js":         http.StatusOK,
+	} {
+		rec := httptest.NewRecorder()
+		aggregatesOnly(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestAggregateNoiser_StableNoise(t *testing.T) {
+	cfg := aggregateConfig{Epsilon: 0.5, salt: "salt-a"}
+	a, b := &aggregateNoiser{cfg: cfg}, &aggregateNoiser{cfg: cfg}
+	other := &aggregateNoiser{cfg: aggregateConfig{Epsilon: 0.5, salt: "salt-b"}}
+	differs := false
+	for value := range 50 {
+		got := a.count("forum_posts", value)
+		if again := b.count("forum_posts", value); again != got {
+			t.Fatalf("count(%d) = %d then %d with the same salt", value, got, again)
+		}
+		if got < 0 {
+			t.Fatalf("count(%d) = %d, want a non-negative count", value, got)
+		}
+		differs = differs || other.count("forum_posts", value) != got
+	}
+	if !differs {
+		t.Error("noise is the same under a different salt")
+	}
+
+	exact := &aggregateNoiser{cfg: aggregateConfig{salt: "salt-a"}}
+	if got := exact.count("forum_posts", 42); got != 42 {
+		t.Errorf("count with epsilon 0 = %d, want 42", got)
+	}
+}
+
+func TestAggregateNoiser_SuppressesSmallBuckets(t *testing.T) {
+	n := &aggregateNoiser{cfg: aggregateConfig{MinCount: 5}}
+	got := n.buckets("posts_by_subreddit", map[string]int{"physics": 12, "math": 5, "biology": 4, "chemistry": 1, "": 7})
+	want := map[string]int{"physics": 12, "math": 5, "unknown": 7}
+	if !reflect.DeepEqual(got, want) || n.suppressed != 2 {
+		t.Errorf("buckets = %v with %d suppressed, want %v with 2", got, n.suppressed, want)
+	}
+	n.buckets("actions", map[string]int{"turn": 3})
+	if n.suppressed != 3 {
+		t.Errorf("suppressed = %d after another small bucket, want 3", n.suppressed)
+	}
+}
+
+func TestBuildAggregates_ReportsSuppressedBuckets(t *testing.T) {
+	dataPath := t.TempDir()
+	forum := publication.NewForum("F", filepath.Join(dataPath, "forum"))
+	for i, sub := range []types.Subreddit{types.SubPhysics, types.SubPhysics, types.SubPhysics, types.SubGeneral} {
+		pub := &types.Publication{ID: fmt.Sprintf("post-%d", i), AuthorID: "ada", Title: "T", Subreddit: sub}
+		if err := forum.Post(pub); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := forum.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rp := buildAggregates(dataPath, filepath.Join(dataPath, "agents"), aggregateConfig{MinCount: 2})
+	if got, want := rp.Distributions["posts_by_subreddit"], map[string]int{"physics": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("posts_by_subreddit = %v, want %v", got, want)
+	}
+	if rp.Privacy.MinCount != 2 || rp.Privacy.SuppressedBuckets != 1 {
+		t.Errorf("privacy = %+v, want min count 2 and 1 suppressed bucket", rp.Privacy)
+	}
+	if rp.Totals["forum_posts"] != 4 {
+		t.Errorf("forum_posts = %d, want 4", rp.Totals["forum_posts"])
+	}
+}
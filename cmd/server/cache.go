@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/publication"
+)
+
+// storeCache is the server's in-memory data layer: it keeps the forum,
+// journal, workflow and groups parsed between requests and reloads one only
+// when a file it is read from changes on disk (by modification time and
+// size). With polling on, a background loop does the checking and reloading
+// and requests only read the last loaded value; otherwise each request
+// checks the files. Cached stores are shared by concurrent requests, so
+// handlers must treat them and their publications as read-only; the human
+// write API loads its own copy.
+type storeCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedStore
+	polling bool
+}
+
+type cachedStore struct {
+	files []string
+	load  func() (any, error)
+
+	loading sync.Mutex // one load of this store at a time
+
+	mu    sync.RWMutex
+	stamp string
+	value any
+}
+
+var stores = &storeCache{entries: make(map[string]*cachedStore)}
+
+// get returns the value cached for key, loading it on first use and, unless
+// polling, reloading it if files changed. Failed loads are not cached.
+func (c *storeCache) get(key string, files []string, load func() (any, error)) (any, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cachedStore{files: files, load: load}
+		c.entries[key] = e
+	}
+	polling := c.polling
+	c.mu.Unlock()
+
+	if polling {
+		e.mu.RLock()
+		value := e.value
+		e.mu.RUnlock()
+		if value != nil {
+			return value, nil
+		}
+	}
+	return e.refresh()
+}
+
+// refresh reloads the store if its files changed since the last load and
+// returns the current value.
+func (e *cachedStore) refresh() (any, error) {
+	e.loading.Lock()
+	defer e.loading.Unlock()
+
+	stamp := fileStamp(e.files)
+	e.mu.RLock()
+	value, current := e.value, e.value != nil && e.stamp == stamp
+	e.mu.RUnlock()
+	if current {
+		return value, nil
+	}
+	value, err := e.load()
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.stamp, e.value = stamp, value
+	e.mu.Unlock()
+	return value, nil
+}
+
+// poll reloads changed stores every interval in the background, so a
+// request never waits for a reload of a store it has used before.
+func (c *storeCache) poll(interval time.Duration) {
+	c.mu.Lock()
+	c.polling = true
+	c.mu.Unlock()
+	go func() {
+		for range time.Tick(interval) {
+			c.mu.Lock()
+			entries := make(map[string]*cachedStore, len(c.entries))
+			maps.Copy(entries, c.entries)
+			c.mu.Unlock()
+			for key, e := range entries {
+				if _, err := e.refresh(); err != nil {
+					log.Printf("Reload %s: %v", key, err)
+				}
+			}
+		}
+	}()
+}
+
+// fileStamp summarizes the modification time and size of files; missing
+// files count too, so creating one invalidates the cache.
+func fileStamp(files []string) string {
+	var b strings.Builder
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			b.WriteString("-;")
+			continue
+		}
+		fmt.Fprintf(&b, "%d:%d;", info.ModTime().UnixNano(), info.Size())
+	}
+	return b.String()
+}
+
+func loadForum(dataPath string) (*publication.Forum, error) {
+	dir := filepath.Join(dataPath, "forum")
+	v, err := stores.get("forum:"+dir, []string{filepath.Join(dir, "forum.json"), filepath.Join(dir, "forum.wal")}, func() (any, error) {
+		return openForum(dataPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*publication.Forum), nil
+}
+
+// reloadForum picks up a forum snapshot the server itself just saved, so
+// the writer sees its change without waiting for the next poll.
+func reloadForum(dataPath string) {
+	key := "forum:" + filepath.Join(dataPath, "forum")
+	stores.mu.Lock()
+	e := stores.entries[key]
+	stores.mu.Unlock()
+	if e == nil {
+		return
+	}
+	if _, err := e.refresh(); err != nil {
+		log.Printf("Reload %s: %v", key, err)
+	}
+}
+
+func loadJournal(dataPath string) (*publication.Journal, error) {
+	dir := filepath.Join(dataPath, "journal")
+	v, err := stores.get("journal:"+dir, []string{filepath.Join(dir, "journal.json")}, func() (any, error) {
+		return openJournal(dataPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*publication.Journal), nil
+}
+
+// loadWorkflow returns the workflow store, empty if it cannot be read.
+func loadWorkflow(dataPath string) *publication.Workflow {
+	dir := filepath.Join(dataPath, "workflow")
+	v, err := stores.get("workflow:"+dir, []string{filepath.Join(dir, "workflow.json")}, func() (any, error) {
+		workflow := publication.NewWorkflow(dir)
+		if err := workflow.Load(); err != nil {
+			return nil, err
+		}
+		return workflow, nil
+	})
+	if err != nil {
+		return publication.NewWorkflow(dir)
+	}
+	return v.(*publication.Workflow)
+}
+
+func loadGroups(dataPath string) (*group.Store, error) {
+	dir := filepath.Join(dataPath, "groups")
+	v, err := stores.get("groups:"+dir, []string{filepath.Join(dir, "groups.json")}, func() (any, error) {
+		store := group.NewStore(dir)
+		if err := store.Load(); err != nil {
+			return nil, err
+		}
+		return store, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*group.Store), nil
+}
+
+// writeCachedJSON writes a successful GET response with an ETag of its
+// content. Clients revalidate with If-None-Match on every use and get an
+// empty 304 while the content is unchanged.
+func writeCachedJSON(w http.ResponseWriter, r *http.Request, status int, payload any) {
+	if stream, ok := payload.(jsonStreamer); ok {
+		writeStreamedJSON(w, r, status, stream)
+		return
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(payload); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// writeStreamedJSON is writeCachedJSON for streamed payloads: it encodes
+// the payload twice, once into a hash for the ETag and once to the client,
+// so neither pass holds the whole response in memory.
+func writeStreamedJSON(w http.ResponseWriter, r *http.Request, status int, stream jsonStreamer) {
+	hash := sha256.New()
+	if err := stream.streamJSON(hash); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := stream.streamJSON(w); err != nil {
+		log.Printf("Stream %s: %v", r.URL.Path, err)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using
+// weak comparison as RFC 9110 requires.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// recentTitles serves the titles of the newest threads in the forum under
+// dataPath, read through the store cache like the real handlers.
+func recentTitles(dataPath string) http.HandlerFunc {
+	return withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		forum, err := loadForum(dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		var titles []string
+		for _, pub := range forum.GetRecent(10) {
+			titles = append(titles, pub.Title)
+		}
+		return titles, http.StatusOK, nil
+	})
+}
+
+func getWithETag(handler http.Handler, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/forum", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `"abc"`
+	for _, tc := range []struct {
+		header string
+		want   bool
+	}{
+		{``, false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`"xyz",W/"abc"`, true},
+		{`"xyz", "abd"`, false},
+		{`abc`, false},
+		{`*`, true},
+	} {
+		if got := etagMatches(tc.header, etag); got != tc.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestWriteCachedJSON_NotModified(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeCachedJSON(w, r, http.StatusOK, map[string]int{"threads": 3})
+	})
+	first := getWithETag(handler, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Body.String() != "{\"threads\":3}\n" {
+		t.Fatalf("first GET = %d, ETag %q, body %q", first.Code, etag, first.Body)
+	}
+	if cc := first.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", cc)
+	}
+
+	for _, header := range []string{etag, "W/" + etag, `"stale", ` + etag} {
+		rec := getWithETag(handler, header)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s = %d with %d bytes, want an empty 304", header, rec.Code, rec.Body.Len())
+		}
+		if rec.Header().Get("ETag") != etag {
+			t.Errorf("304 ETag = %q, want %q", rec.Header().Get("ETag"), etag)
+		}
+	}
+	if rec := getWithETag(handler, `"stale"`); rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+		t.Errorf("stale If-None-Match = %d, want 200 with the body", rec.Code)
+	}
+}
+
+func TestWriteCachedJSON_NewETagWhenStoreChanges(t *testing.T) {
+	dataPath := t.TempDir()
+	forum := publication.NewForum("F", filepath.Join(dataPath, "forum"))
+	if err := forum.Post(&types.Publication{ID: "post-1", AuthorID: "ada", Title: "Tired light"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Save(); err != nil {
+		t.Fatal(err)
+	}
+	handler := recentTitles(dataPath)
+	etag := getWithETag(handler, "").Header().Get("ETag")
+	if rec := getWithETag(handler, etag); rec.Code != http.StatusNotModified {
+		t.Fatalf("unchanged store = %d, want 304", rec.Code)
+	}
+
+	if err := forum.Post(&types.Publication{ID: "post-2", AuthorID: "bo", Title: "Against tired light"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Save(); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the modification time moves even on coarse clocks.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dataPath, "forum", "forum.json"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	rec := getWithETag(handler, etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("changed store = %d, want 200", rec.Code)
+	}
+	if next := rec.Header().Get("ETag"); next == "" || next == etag {
+		t.Errorf("ETag after the store changed = %q, want a new one (was %q)", next, etag)
+	}
+}
+
+// newSavedForum saves a forum with one thread under dataPath and returns
+// it for further writes, which append to forum.wal until the next Save.
+func newSavedForum(t *testing.T, dataPath string) *publication.Forum {
+	t.Helper()
+	forum := publication.NewForum("F", filepath.Join(dataPath, "forum"))
+	if err := forum.Post(&types.Publication{ID: "post-1", AuthorID: "ada", Title: "Tired light"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return forum
+}
+
+func TestLoadForum_PicksUpWALAndSnapshot(t *testing.T) {
+	dataPath := t.TempDir()
+	writer := newSavedForum(t, dataPath)
+
+	served, err := loadForum(dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if served.Get("post-1") == nil {
+		t.Fatal("post-1 missing from the first load")
+	}
+	if again, _ := loadForum(dataPath); again != served {
+		t.Error("unchanged forum was reloaded")
+	}
+
+	// A post the simulation makes between checkpoints only reaches forum.wal.
+	if err := writer.Post(&types.Publication{ID: "post-2", AuthorID: "bo", Title: "Against tired light"}); err != nil {
+		t.Fatal(err)
+	}
+	served, err = loadForum(dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if served.Get("post-2") == nil {
+		t.Error("post appended to forum.wal not served")
+	}
+
+	// A checkpoint rewrites forum.json and compacts the log.
+	if err := writer.Comment("post-2", &types.Publication{ID: "comment-1", AuthorID: "ada", Content: "Redshift drift?"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Save(); err != nil {
+		t.Fatal(err)
+	}
+	served, err = loadForum(dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if served.Get("post-2") == nil || served.Get("comment-1") == nil {
+		t.Error("rewritten forum.json not served")
+	}
+}
+
+func TestStoreCache_PollReloadsInBackground(t *testing.T) {
+	dataPath := t.TempDir()
+	writer := newSavedForum(t, dataPath)
+	dir := filepath.Join(dataPath, "forum")
+	files := []string{filepath.Join(dir, "forum.json"), filepath.Join(dir, "forum.wal")}
+	load := func() (any, error) { return openForum(dataPath) }
+	c := &storeCache{entries: make(map[string]*cachedStore)}
+	get := func() *publication.Forum {
+		t.Helper()
+		v, err := c.get("forum", files, load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v.(*publication.Forum)
+	}
+	get()
+	// The poll loop runs for the rest of the test binary; it only stats
+	// files, and a removed directory loads as an empty forum.
+	c.poll(5 * time.Millisecond)
+
+	if err := writer.Post(&types.Publication{ID: "post-2", AuthorID: "bo", Title: "Against tired light"}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for get().Get("post-2") == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("poll never picked up the appended post")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/client"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// graphState is the part of an agent's state.json the graph needs.
+type graphState struct {
+	Relationships map[string]*types.Relationship `json:"relationships"`
+	Karma         *types.Karma                   `json:"karma"`
+	Reputation    *types.Reputation              `json:"reputation"`
+	Retirement    *types.Retirement              `json:"retirement"`
+}
+
+// loadGraph builds /api/graph from every agent's relationships. Non-zero
+// since/until keep only edges whose last interaction is in [since, until);
+// nodes are kept regardless so isolated agents still show up.
+func loadGraph(dataPath, agentsPath string, since, until time.Time) (*client.GraphResponse, error) {
+	agents, err := loadAgentsMerged(dataPath, agentsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*client.GraphNode, len(agents))
+	edges := make([]client.GraphEdge, 0)
+	peerNames := make(map[string]string)
+	for _, agent := range agents {
+		node := &client.GraphNode{ID: agent.ID, Name: agent.Name, Role: agent.Role}
+		nodes[agent.ID] = node
+
+		data, err := os.ReadFile(filepath.Join(dataPath, "agents", agent.ID, "state.json"))
+		if err != nil {
+			continue
+		}
+		var state graphState
+		if err := json.Unmarshal(data, &state); err != nil {
+			continue
+		}
+		if state.Karma != nil {
+			node.Karma = state.Karma.Total()
+		}
+		if state.Reputation != nil {
+			node.Reputation = state.Reputation.Score
+		}
+		node.Retired = state.Retirement != nil
+
+		for peerID, rel := range state.Relationships {
+			if rel == nil || peerID == agent.ID {
+				continue
+			}
+			if !since.IsZero() && rel.LastInteraction.Before(since) {
+				continue
+			}
+			if !until.IsZero() && !rel.LastInteraction.Before(until) {
+				continue
+			}
+			peerNames[peerID] = rel.PeerName
+			edges = append(edges, client.GraphEdge{
+				Source:          agent.ID,
+				Target:          peerID,
+				State:           string(rel.State),
+				Trust:           rel.TrustScore,
+				Familiarity:     rel.Familiarity,
+				Interactions:    rel.InteractionCount,
+				LastInteraction: rel.LastInteraction,
+				SharedTopics:    rel.SharedTopics,
+			})
+		}
+	}
+
+	// Peers that only appear in someone's relationships still need a node.
+	for _, edge := range edges {
+		if _, ok := nodes[edge.Target]; !ok {
+			nodes[edge.Target] = &client.GraphNode{ID: edge.Target, Name: peerNames[edge.Target]}
+		}
+	}
+
+	out := &client.GraphResponse{
+		Nodes: make([]client.GraphNode, 0, len(nodes)),
+		Edges: edges,
+	}
+	for _, node := range nodes {
+		out.Nodes = append(out.Nodes, *node)
+	}
+	sort.Slice(out.Nodes, func(i, j int) bool { return out.Nodes[i].ID < out.Nodes[j].ID })
+	sort.Slice(out.Edges, func(i, j int) bool {
+		if out.Edges[i].Source != out.Edges[j].Source {
+			return out.Edges[i].Source < out.Edges[j].Source
+		}
+		return out.Edges[i].Target < out.Edges[j].Target
+	})
+	return out, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/client"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// humanAccount is one entry of the -humans file. ID is stored under the
+// reserved namespace, so "alice" writes as "human-alice".
+type humanAccount struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+func (h humanAccount) authorID() string { return types.HumanAuthorPrefix + h.ID }
+
+var humanIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// humanWriter serves the write API. Each request loads the forum, applies
+// one change and saves a snapshot, serialized so concurrent requests don't
+// overwrite each other.
+type humanWriter struct {
+	dataPath string
+	accounts map[[sha256.Size]byte]humanAccount // token hash -> account
+	mu       sync.Mutex
+}
+
+func loadHumanWriter(path, dataPath string) (*humanWriter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []humanAccount
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	hw := &humanWriter{dataPath: dataPath, accounts: make(map[[sha256.Size]byte]humanAccount, len(list))}
+	for _, acct := range list {
+		acct.ID = strings.ToLower(strings.TrimSpace(acct.ID))
+		if !humanIDPattern.MatchString(acct.ID) {
+			return nil, fmt.Errorf("invalid human id %q: use up to 32 lowercase letters, digits, '-' or '_'", acct.ID)
+		}
+		if len(acct.Token) < 16 {
+			return nil, fmt.Errorf("token for %s is shorter than 16 characters", acct.ID)
+		}
+		if strings.TrimSpace(acct.Name) == "" {
+			acct.Name = acct.ID
+		}
+		key := sha256.Sum256([]byte(acct.Token))
+		if _, dup := hw.accounts[key]; dup {
+			return nil, fmt.Errorf("duplicate token for %s", acct.ID)
+		}
+		hw.accounts[key] = acct
+	}
+	return hw, nil
+}
+
+// authenticate resolves the request's bearer token to an account. Tokens
+// are compared as fixed-size hashes, so lookup time doesn't depend on how
+// much of a guess matches.
+func (hw *humanWriter) authenticate(r *http.Request) (humanAccount, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return humanAccount{}, false
+	}
+	key := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	for k, acct := range hw.accounts {
+		if subtle.ConstantTimeCompare(k[:], key[:]) == 1 {
+			return acct, true
+		}
+	}
+	return humanAccount{}, false
+}
+
+// handleWrite wraps a write endpoint: POST only, authenticated, JSON body of
+// type T. The change goes to the audit log like the simulation's own writes,
+// and the forum is saved after apply succeeds.
+func handleWrite[T any](hw *humanWriter, apply func(*publication.Forum, humanAccount, T) (*types.Publication, int, error)) http.HandlerFunc {
+	return withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodPost {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		if hw == nil {
+			return nil, http.StatusNotFound, errors.New("write API disabled (start the server with -humans)")
+		}
+		acct, ok := hw.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="sci-bot"`)
+			return nil, http.StatusUnauthorized, errors.New("missing or invalid bearer token")
+		}
+		var req T
+		dec := json.NewDecoder(io.LimitReader(r.Body, 256*1024))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err)
+		}
+
+		hw.mu.Lock()
+		defer hw.mu.Unlock()
+		forum, err := openForum(hw.dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		auditLog := audit.NewLog(filepath.Join(hw.dataPath, audit.FileName))
+		defer auditLog.Close()
+		forum.SetAuditLog(auditLog)
+		pub, status, err := apply(forum, acct, req)
+		if err != nil {
+			return nil, status, err
+		}
+		if err := forum.Save(); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		reloadForum(hw.dataPath)
+		return client.WriteResponse{Publication: pub}, status, nil
+	})
+}
+
+func createHumanPost(forum *publication.Forum, acct humanAccount, req client.CreatePostRequest) (*types.Publication, int, error) {
+	title, content := strings.TrimSpace(req.Title), strings.TrimSpace(req.Content)
+	if title == "" || content == "" {
+		return nil, http.StatusBadRequest, errors.New("title and content are required")
+	}
+	sub := publication.NormalizeSubreddit(req.Subreddit)
+	if sub == "" {
+		sub = types.SubGeneral
+	} else if !forum.HasSubreddit(sub) {
+		return nil, http.StatusBadRequest, fmt.Errorf("unknown subreddit: %s", sub)
+	}
+	pub := &types.Publication{
+		AuthorID:   acct.authorID(),
+		AuthorName: acct.Name,
+		Title:      title,
+		Content:    content,
+		Abstract:   strings.TrimSpace(req.Abstract),
+		Subreddit:  sub,
+	}
+	if err := forum.Post(pub); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return pub, http.StatusCreated, nil
+}
+
+func createHumanComment(forum *publication.Forum, acct humanAccount, req client.CreateCommentRequest) (*types.Publication, int, error) {
+	content := strings.TrimSpace(req.Content)
+	if req.ParentID == "" || content == "" {
+		return nil, http.StatusBadRequest, errors.New("parent_id and content are required")
+	}
+	if forum.Get(req.ParentID) == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("parent not found: %s", req.ParentID)
+	}
+	pub := &types.Publication{
+		AuthorID:   acct.authorID(),
+		AuthorName: acct.Name,
+		Content:    content,
+	}
+	if err := forum.Comment(req.ParentID, pub); err != nil {
+		return nil, http.StatusConflict, err
+	}
+	return pub, http.StatusCreated, nil
+}
+
+func castHumanVote(forum *publication.Forum, acct humanAccount, req client.VoteRequest) (*types.Publication, int, error) {
+	if req.PostID == "" {
+		return nil, http.StatusBadRequest, errors.New("post_id is required")
+	}
+	if forum.Get(req.PostID) == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("post not found: %s", req.PostID)
+	}
+	vote := forum.Downvote
+	if req.Up {
+		vote = forum.Upvote
+	}
+	if err := vote(acct.authorID(), req.PostID); err != nil {
+		return nil, http.StatusConflict, err
+	}
+	return forum.Get(req.PostID), http.StatusOK, nil
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/client"
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// spectatorInbox serves /api/inbox: anyone may ask and vote, identified
+// by the address they connect from; human accounts moderate. adk_simulate
+// posts the approved questions. box is nil unless the server runs with
+// -inbox.
+type spectatorInbox struct {
+	dataPath string
+	box      *inbox.Inbox
+	humans   *humanWriter
+	limiter  *addrLimiter // asks and votes per address; nil: unlimited
+}
+
+var errInboxDisabled = errors.New("spectator inbox disabled (start the server with -inbox)")
+
+// questionPermalink is the page an asker watches their question on.
+func questionPermalink(id string) string {
+	return "./ask.html?id=" + url.QueryEscape(id)
+}
+
+func questionResponse(q inbox.Question) QuestionResponse {
+	return QuestionResponse{Question: q, Permalink: questionPermalink(q.ID)}
+}
+
+// decodeBody reads a JSON request body as handleWrite does.
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, 64*1024))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// handleList serves GET /api/inbox (the queue) and POST /api/inbox (ask).
+func (si *spectatorInbox) handleList(w http.ResponseWriter, r *http.Request) (any, int, error) {
+	if si.box == nil {
+		return nil, http.StatusNotFound, errInboxDisabled
+	}
+	switch r.Method {
+	case http.MethodGet:
+		status := inbox.Status(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status"))))
+		switch status {
+		case "":
+			status = inbox.Approved
+		case inbox.Approved, inbox.Posted:
+		case inbox.Pending, inbox.Rejected:
+			if _, ok := si.moderator(w, r); !ok {
+				return nil, http.StatusUnauthorized, errors.New("listing pending or rejected questions needs a human account")
+			}
+		default:
+			return nil, http.StatusBadRequest, fmt.Errorf("unknown status: %s", status)
+		}
+		if err := si.box.Reload(); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return InboxResponse{Status: status, Questions: si.box.List(status)}, http.StatusOK, nil
+
+	case http.MethodPost:
+		var req client.AskRequest
+		if err := decodeBody(r, &req); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if status, err := si.throttle(w, r); err != nil {
+			return nil, status, err
+		}
+		sub := publication.NormalizeSubreddit(req.Subreddit)
+		if sub != "" && sub != types.SubGeneral {
+			forum, err := loadForum(si.dataPath)
+			if err != nil {
+				return nil, http.StatusInternalServerError, err
+			}
+			if !forum.HasSubreddit(sub) {
+				return nil, http.StatusBadRequest, fmt.Errorf("unknown subreddit: %s", sub)
+			}
+		}
+		q, err := si.box.Ask(req.Title, req.Question, string(sub), req.Name, inbox.VisitorKey(remoteHost(r)))
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return questionResponse(q), http.StatusCreated, nil
+	}
+	return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+}
+
+// handleItem serves GET /api/inbox/{id}, POST /api/inbox/{id}/vote and
+// POST /api/inbox/{id}/moderate.
+func (si *spectatorInbox) handleItem(w http.ResponseWriter, r *http.Request) (any, int, error) {
+	if si.box == nil {
+		return nil, http.StatusNotFound, errInboxDisabled
+	}
+	id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/inbox/"), "/"), "/")
+	if id == "" {
+		return nil, http.StatusBadRequest, errors.New("missing question id")
+	}
+	want := http.MethodPost
+	if action == "" {
+		want = http.MethodGet
+	}
+	if r.Method != want {
+		return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+	}
+
+	var (
+		q   inbox.Question
+		err error
+	)
+	switch action {
+	case "":
+		if err := si.box.Reload(); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		var ok bool
+		if q, ok = si.box.Get(id); !ok {
+			err = inbox.ErrNotFound
+		}
+	case "vote":
+		var req client.InboxVoteRequest
+		if err := decodeBody(r, &req); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if status, err := si.throttle(w, r); err != nil {
+			return nil, status, err
+		}
+		q, err = si.box.Vote(id, inbox.VisitorKey(remoteHost(r)))
+	case "moderate":
+		acct, ok := si.moderator(w, r)
+		if !ok {
+			return nil, http.StatusUnauthorized, errors.New("missing or invalid bearer token")
+		}
+		var req client.ModerateRequest
+		if err := decodeBody(r, &req); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		q, err = si.box.Moderate(id, req.Approve, acct.authorID(), req.Reason)
+	default:
+		return nil, http.StatusNotFound, fmt.Errorf("unknown inbox action: %s", action)
+	}
+	switch {
+	case errors.Is(err, inbox.ErrNotFound):
+		return nil, http.StatusNotFound, err
+	case err != nil:
+		return nil, http.StatusConflict, err
+	}
+	return questionResponse(q), http.StatusOK, nil
+}
+
+// moderator authenticates a human account from -humans.
+func (si *spectatorInbox) moderator(w http.ResponseWriter, r *http.Request) (humanAccount, bool) {
+	if si.humans != nil {
+		if acct, ok := si.humans.authenticate(r); ok {
+			return acct, true
+		}
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="sci-bot"`)
+	return humanAccount{}, false
+}
+
+// throttle takes one of the requester's asks or votes, or reports 429 with
+// a Retry-After hint when the address has used them up.
+func (si *spectatorInbox) throttle(w http.ResponseWriter, r *http.Request) (int, error) {
+	wait := si.limiter.reserve(remoteHost(r))
+	if wait <= 0 {
+		return 0, nil
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	return http.StatusTooManyRequests, errors.New("too many questions or votes from this address; try again later")
+}
+
+// remoteHost is the address a request came from, without the port. Behind
+// a reverse proxy every spectator shares the proxy's address.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// maxTrackedAddrs bounds the buckets addrLimiter keeps; beyond it, buckets
+// that have refilled are dropped since a new one starts full anyway.
+const maxTrackedAddrs = 10000
+
+// addrLimiter is a token bucket per remote address.
+type addrLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // tokens per second
+	burst   float64
+	buckets map[string]*addrBucket
+	now     func() time.Time
+}
+
+type addrBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newAddrLimiter allows perMinute requests a minute from one address, in
+// bursts of up to perMinute. It returns nil (unlimited) for perMinute <= 0.
+func newAddrLimiter(perMinute float64) *addrLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &addrLimiter{
+		rate:    perMinute / 60,
+		burst:   max(perMinute, 1),
+		buckets: make(map[string]*addrBucket),
+		now:     time.Now,
+	}
+}
+
+// reserve takes a token for addr and returns 0, or returns how long until
+// one is available.
+func (l *addrLimiter) reserve(addr string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.buckets[addr]
+	if b == nil {
+		if len(l.buckets) >= maxTrackedAddrs {
+			l.prune(now)
+		}
+		b = &addrBucket{tokens: l.burst, last: now}
+		l.buckets[addr] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *addrLimiter) prune(now time.Time) {
+	for addr, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, addr)
+		}
+	}
+}
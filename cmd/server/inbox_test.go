@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/inbox"
+)
+
+func inboxRequest(t *testing.T, handler http.HandlerFunc, path, remoteAddr, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestSpectatorInbox_KeysVisitorsByAddress(t *testing.T) {
+	dataPath := t.TempDir()
+	box, err := inbox.Open(filepath.Join(dataPath, inbox.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	si := &spectatorInbox{dataPath: dataPath, box: box}
+	ask, item := withJSON(si.handleList), withJSON(si.handleItem)
+
+	// Each request uses a new port, as a client reconnecting would; the
+	// open-question cap still applies to the address.
+	var ids []string
+	for i := range inbox.MaxOpenPerVisitor + 1 {
+		rec := inboxRequest(t, ask, "/api/inbox", fmt.Sprintf("192.0.2.1:%d", 1000+i),
+			`{"title":"Why redshift?","question":"Is light tired?"}`)
+		if i == inbox.MaxOpenPerVisitor {
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("ask over the cap = %d %s, want 400", rec.Code, rec.Body)
+			}
+			break
+		}
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("ask %d = %d %s", i, rec.Code, rec.Body)
+		}
+		var resp QuestionResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, resp.Question.ID)
+	}
+	if rec := inboxRequest(t, ask, "/api/inbox", "198.51.100.7:4000", `{"title":"T","question":"Q"}`); rec.Code != http.StatusCreated {
+		t.Errorf("ask from another address = %d %s, want 201", rec.Code, rec.Body)
+	}
+	if rec := inboxRequest(t, ask, "/api/inbox", "192.0.2.1:5000", `{"title":"T","question":"Q","visitor":"fresh-secret"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("ask with a client-chosen visitor = %d, want 400", rec.Code)
+	}
+
+	if _, err := box.Moderate(ids[0], true, "human-mod", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{"203.0.113.5:1000", "203.0.113.5:2000", "203.0.113.6:1000"} {
+		inboxRequest(t, item, "/api/inbox/"+ids[0]+"/vote", addr, `{}`)
+	}
+	if q, _ := box.Get(ids[0]); q.Votes != 2 {
+		t.Errorf("votes = %d, want 2 (one per address)", q.Votes)
+	}
+}
+
+func TestSpectatorInbox_RateLimitsAddress(t *testing.T) {
+	dataPath := t.TempDir()
+	box, err := inbox.Open(filepath.Join(dataPath, inbox.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	limiter := newAddrLimiter(2)
+	limiter.now = func() time.Time { return now }
+	si := &spectatorInbox{dataPath: dataPath, box: box, limiter: limiter}
+	item := withJSON(si.handleItem)
+
+	// Votes on a missing question still spend the address's allowance.
+	for i, want := range []int{http.StatusNotFound, http.StatusNotFound, http.StatusTooManyRequests} {
+		rec := inboxRequest(t, item, "/api/inbox/q-missing/vote", "192.0.2.1:1000", `{}`)
+		if rec.Code != want {
+			t.Fatalf("vote %d = %d %s, want %d", i, rec.Code, rec.Body, want)
+		}
+		if want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "30" {
+			t.Errorf("Retry-After = %q, want 30", rec.Header().Get("Retry-After"))
+		}
+	}
+	if rec := inboxRequest(t, item, "/api/inbox/q-missing/vote", "192.0.2.2:1000", `{}`); rec.Code != http.StatusNotFound {
+		t.Errorf("vote from another address = %d, want 404", rec.Code)
+	}
+	now = now.Add(30 * time.Second)
+	if rec := inboxRequest(t, item, "/api/inbox/q-missing/vote", "192.0.2.1:1000", `{}`); rec.Code != http.StatusNotFound {
+		t.Errorf("vote after refill = %d, want 404", rec.Code)
+	}
+}
@@ -6,125 +6,109 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/client"
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/lineage"
+	"github.com/cpunion/sci-bot/pkg/memory"
 	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/site"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
 
-type AgentInfo struct {
-	ID                  string   `json:"id"`
-	Name                string   `json:"name"`
-	Role                string   `json:"role"`
-	ThinkingStyle       string   `json:"thinking_style"`
-	Domains             []string `json:"domains"`
-	Creativity          float64  `json:"creativity"`
-	Rigor               float64  `json:"rigor"`
-	RiskTolerance       float64  `json:"risk_tolerance"`
-	Sociability         float64  `json:"sociability"`
-	Influence           float64  `json:"influence"`
-	ResearchOrientation string   `json:"research_orientation"`
-}
-
-type DailyNote struct {
-	Date    string       `json:"date"`
-	Content string       `json:"content"`
-	Entries []DailyEntry `json:"entries,omitempty"`
-}
-
-type DailyEntry struct {
-	Timestamp string `json:"timestamp"`
-	Prompt    string `json:"prompt,omitempty"`
-	Reply     string `json:"reply,omitempty"`
-	Notes     string `json:"notes,omitempty"`
-	Raw       string `json:"raw,omitempty"`
-}
-
-type AgentDetail struct {
-	Agent           AgentInfo            `json:"agent"`
-	ForumPosts      []*types.Publication `json:"forum_posts"`
-	ForumComments   []*types.Publication `json:"forum_comments"`
-	JournalApproved []*types.Publication `json:"journal_approved"`
-	JournalPending  []*types.Publication `json:"journal_pending"`
-	DailyNotes      []DailyNote          `json:"daily_notes"`
-}
-
-type ForumResponse struct {
-	Name           string               `json:"name"`
-	Posts          []*types.Publication `json:"posts"`
-	SubredditStats map[string]int       `json:"subreddit_stats"`
-}
-
-type ForumPostResponse struct {
-	Post     *types.Publication   `json:"post"`
-	Comments []*types.Publication `json:"comments"`
-}
-
-type JournalResponse struct {
-	Name     string               `json:"name"`
-	Approved []*types.Publication `json:"approved"`
-	Pending  []*types.Publication `json:"pending"`
-}
-
-type PaperDetailResponse struct {
-	JournalName string             `json:"journal_name"`
-	Status      string             `json:"status"` // published | pending
-	Paper       *types.Publication `json:"paper"`
-}
-
-type FeedEvent struct {
-	Timestamp      time.Time `json:"timestamp"`
-	SimTime        time.Time `json:"sim_time"`
-	Tick           int       `json:"tick"`
-	AgentID        string    `json:"agent_id"`
-	AgentName      string    `json:"agent_name"`
-	ModelName      string    `json:"model_name,omitempty"`
-	Action         string    `json:"action"`
-	Prompt         string    `json:"prompt"`
-	Response       string    `json:"response"`
-	ToolCalls      []string  `json:"tool_calls,omitempty"`
-	ToolResponses  []string  `json:"tool_responses,omitempty"`
-	TurnCount      int       `json:"turn_count"`
-	BellRung       bool      `json:"bell_rung"`
-	GraceRemaining int       `json:"grace_remaining"`
-	Sleeping       bool      `json:"sleeping"`
-
-	UsageEvents         int `json:"usage_events,omitempty"`
-	PromptTokens        int `json:"prompt_tokens,omitempty"`
-	CandidatesTokens    int `json:"candidates_tokens,omitempty"`
-	ThoughtsTokens      int `json:"thoughts_tokens,omitempty"`
-	ToolUsePromptTokens int `json:"tool_use_prompt_tokens,omitempty"`
-	CachedContentTokens int `json:"cached_content_tokens,omitempty"`
-	TotalTokens         int `json:"total_tokens,omitempty"`
-
-	// Derived links for UI. Not part of the simulation log format.
-	ActorURL     string `json:"actor_url,omitempty"`
-	ContentKind  string `json:"content_kind,omitempty"`
-	ContentID    string `json:"content_id,omitempty"`
-	ContentTitle string `json:"content_title,omitempty"`
-	ContentURL   string `json:"content_url,omitempty"`
-}
+// API payloads are defined in pkg/client so the Go client and the server
+// share one specification.
+type (
+	AgentInfo           = client.AgentInfo
+	DailyNote           = client.DailyNote
+	DailyEntry          = client.DailyEntry
+	DailyRecord         = client.DailyRecord
+	DailyResponse       = client.DailyResponse
+	AgentDetail         = client.AgentDetail
+	ForumResponse       = client.ForumResponse
+	ForumPostResponse   = client.ForumPostResponse
+	ModerationResponse  = client.ModerationResponse
+	JournalResponse     = client.JournalResponse
+	RejectedPaper       = client.RejectedPaper
+	RejectedResponse    = client.RejectedResponse
+	PaperDetailResponse = client.PaperDetailResponse
+	FeedEvent           = client.FeedEvent
+	FeedResponse        = client.FeedResponse
+	SearchResponse      = client.SearchResponse
+	AuditResponse       = client.AuditResponse
+	GraphResponse       = client.GraphResponse
+	LineageResponse     = client.LineageResponse
+	SnapshotResponse    = client.SnapshotResponse
+	TimelineResponse    = client.TimelineResponse
+	InboxResponse       = client.InboxResponse
+	QuestionResponse    = client.QuestionResponse
+)
 
-type FeedResponse struct {
-	Log    string      `json:"log"`
-	Events []FeedEvent `json:"events"`
-}
+// heartbeatAction matches simulation.ActionHeartbeat (liveness events).
+const heartbeatAction = "heartbeat"
 
 func main() {
 	addr := flag.String("addr", ":8061", "Listen address")
 	dataPath := flag.String("data", "./data/adk-simulation", "Data directory")
 	agentsPath := flag.String("agents", "./config/agents", "Agents directory")
 	webPath := flag.String("web", "./web", "Web assets directory")
+	feedDir := flag.String("feed", "feed", "Sharded feed store written by adk_simulate (relative to the data directory); /api/feed reads it when present instead of scanning logs*.jsonl. Set '-' to always scan the logs.")
+	showRejected := flag.Bool("show-rejected", false, "Expose rejected submissions and their reviews via /api/journal/rejected")
+	aggregates := flag.Bool("aggregates", false, "Serve /api/aggregates (noisy community statistics, no content or identities)")
+	aggregatesOnlyMode := flag.Bool("aggregates-only", false, "Public mode: serve only /api/aggregates and static pages; block raw /api and /data routes (implies -aggregates)")
+	aggregatesEpsilon := flag.Float64("aggregates-epsilon", 1.0, "Laplace noise privacy budget per released count (0 disables noise)")
+	aggregatesMinCount := flag.Int("aggregates-min-count", 5, "Suppress distribution buckets with fewer than this many items")
+	reloadEvery := flag.Duration("reload-interval", 2*time.Second, "Check the data files this often and reload changed stores in the background (0: check on every request instead)")
+	inboxEnabled := flag.Bool("inbox", false, "Let spectators ask the community questions (inbox.jsonl, moderated by -humans accounts; adk_simulate posts the approved ones). Asking and voting need no -api-key")
+	inboxRate := flag.Float64("inbox-rate", 6, "Questions and votes per minute allowed from one address with -inbox (0: unlimited)")
+	humansPath := flag.String("humans", "", "JSON file of human accounts ([{id, name, token}]); enables the authenticated forum write API")
+	corsOrigins := flag.String("cors-origins", "", "Comma-separated origins allowed to call the API from the browser (e.g. https://example.org, or * for any)")
+	apiKey := flag.String("api-key", os.Getenv("SCI_BOT_API_KEY"), "Shared key required for write requests and /metrics, sent as X-API-Key (default $SCI_BOT_API_KEY; empty leaves them open)")
 	flag.Parse()
+	if *reloadEvery > 0 {
+		stores.poll(*reloadEvery)
+	}
+
+	var humans *humanWriter
+	if *humansPath != "" {
+		hw, err := loadHumanWriter(*humansPath, *dataPath)
+		if err != nil {
+			log.Fatalf("Load humans: %v", err)
+		}
+		humans = hw
+		log.Printf("Forum write API enabled for %d human account(s)", len(hw.accounts))
+	}
+
+	spectators := &spectatorInbox{dataPath: *dataPath, humans: humans}
+	if *inboxEnabled {
+		box, err := inbox.Open(filepath.Join(*dataPath, inbox.FileName))
+		if err != nil {
+			log.Fatalf("Open inbox: %v", err)
+		}
+		spectators.box = box
+		spectators.limiter = newAddrLimiter(*inboxRate)
+		if humans == nil {
+			log.Printf("Warning: -inbox without -humans: questions can be asked but nobody can approve them")
+		}
+	}
 
 	mux := http.NewServeMux()
+	srvMetrics := newServerMetrics()
+	mux.Handle("/metrics", srvMetrics.handler(*dataPath))
 
 	mux.HandleFunc("/api/agents", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
 		if r.Method != http.MethodGet {
@@ -147,36 +131,36 @@ func main() {
 			activeAgents, _ = countAgentDirs(*agentsPath)
 		}
 
-		var forumThreads int
-		if forum, err := loadForum(*dataPath); err == nil {
-			for _, p := range forum.AllPosts() {
-				if p != nil && !p.IsComment {
-					forumThreads++
-				}
-			}
+		stats, err := loadActivityStats(*dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
 		}
-
-		var journalApproved int
-		if journal, err := loadJournal(*dataPath); err == nil {
-			journalApproved = len(journal.GetApproved())
+		if stats == nil {
+			// No activity index yet (older data): count from the stores.
+			stats = &client.Stats{}
+			if forum, err := loadForum(*dataPath); err == nil {
+				stats.ForumThreads, stats.ForumComments = forum.Counts()
+			}
+			if journal, err := loadJournal(*dataPath); err == nil {
+				stats.JournalApproved, stats.JournalRejected, stats.JournalPending = journal.AcceptanceStats()
+			}
 		}
-
-		return map[string]any{
-			"active_agents":    activeAgents,
-			"forum_threads":    forumThreads,
-			"journal_approved": journalApproved,
-		}, http.StatusOK, nil
+		stats.ActiveAgents = activeAgents
+		stats.AcceptanceRate = acceptanceRate(stats.JournalApproved, stats.JournalRejected)
+		return stats, http.StatusOK, nil
 	}))
 
 	mux.HandleFunc("/api/agents/", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
 		if r.Method != http.MethodGet {
 			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
 		}
-		id := strings.TrimPrefix(r.URL.Path, "/api/agents/")
-		id = strings.Trim(id, "/")
+		id, sub, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/agents/"), "/"), "/")
 		if id == "" {
 			return nil, http.StatusBadRequest, errors.New("missing agent id")
 		}
+		if sub != "" && sub != "daily" {
+			return nil, http.StatusNotFound, fmt.Errorf("unknown agent resource: %s", sub)
+		}
 		agent, err := loadAgentMerged(*dataPath, *agentsPath, id)
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
@@ -188,6 +172,24 @@ func main() {
 		if resolvedID == "" {
 			resolvedID = id
 		}
+		if sub == "daily" {
+			q := r.URL.Query()
+			from, to := strings.TrimSpace(q.Get("from")), strings.TrimSpace(q.Get("to"))
+			for _, date := range []string{from, to} {
+				if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+					return nil, http.StatusBadRequest, fmt.Errorf("invalid date: %s", date)
+				}
+			}
+			records, err := loadDailyRange(*dataPath, resolvedID, from, to)
+			if err != nil {
+				return nil, http.StatusInternalServerError, err
+			}
+			return DailyResponse{
+				AgentID: resolvedID,
+				Total:   len(records),
+				Entries: page(records, parseOffset(r), parseLimit(q.Get("limit"), 100, 1, 1000)),
+			}, http.StatusOK, nil
+		}
 
 		forum, _ := loadForum(*dataPath)
 		journal, _ := loadJournal(*dataPath)
@@ -210,37 +212,101 @@ func main() {
 		pending := filterJournalByAuthor(journal, resolvedID, false)
 
 		dailyNotes := loadDailyNotes(*dataPath, resolvedID, 10)
+		researchNotes, err := memory.ReadNotes(filepath.Join(*dataPath, "agents", resolvedID, memory.NotesDir), "", "")
+		if err != nil {
+			log.Printf("Read research notes of %s: %v", resolvedID, err)
+		}
+		slices.Reverse(researchNotes)
+		if len(researchNotes) > 20 {
+			researchNotes = researchNotes[:20]
+		}
+		var groups []group.Group
+		if store, err := loadGroups(*dataPath); err == nil {
+			groups = withoutChannels(store.List(resolvedID))
+		}
+		standing := loadAgentStanding(*dataPath, resolvedID)
+		standing.apply(&agent)
 
 		return AgentDetail{
 			Agent:           agent,
-			ForumPosts:      forumPosts,
-			ForumComments:   forumComments,
-			JournalApproved: approved,
-			JournalPending:  pending,
+			ForumPosts:      renderedList(forumPosts),
+			ForumComments:   renderedList(forumComments),
+			JournalApproved: renderedList(approved),
+			JournalPending:  renderedList(pending),
 			DailyNotes:      dailyNotes,
+			ResearchNotes:   researchNotes,
+			Karma:           standing.Karma,
+			Traits:          standing.Traits,
+			Groups:          groups,
+			Agenda:          standing.Agenda,
 		}, http.StatusOK, nil
 	}))
 
+	if *aggregates || *aggregatesOnlyMode {
+		aggCfg := newAggregateConfig(*aggregatesEpsilon, *aggregatesMinCount)
+		mux.HandleFunc("/api/aggregates", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+			if r.Method != http.MethodGet {
+				return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+			}
+			return buildAggregates(*dataPath, *agentsPath, aggCfg), http.StatusOK, nil
+		}))
+	}
+
 	mux.HandleFunc("/api/feed", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
 		if r.Method != http.MethodGet {
 			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
 		}
 
-		limit := parseLimit(r.URL.Query().Get("limit"), 200, 1, 2000)
-		requestedLog := strings.TrimSpace(r.URL.Query().Get("log"))
+		query := r.URL.Query()
+		limit := parseLimit(query.Get("limit"), 200, 1, 2000)
+		offset := parseOffset(r)
+		requestedLog := strings.TrimSpace(query.Get("log"))
+		filter := feedFilter{agent: strings.TrimSpace(query.Get("agent"))}
+		// Liveness heartbeats are for debugging; opt in with ?heartbeats=1.
+		filter.heartbeats, _ = strconv.ParseBool(query.Get("heartbeats"))
+		for _, action := range strings.Split(query.Get("action"), ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				filter.actions = append(filter.actions, action)
+			}
+		}
+		var err error
+		if filter.since, err = parseTimeParam(query.Get("since")); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if filter.until, err = parseTimeParam(query.Get("until")); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		before := strings.TrimSpace(query.Get("before"))
+		after := strings.TrimSpace(query.Get("after"))
 
-		var logName string
+		resp := FeedResponse{}
 		var events []FeedEvent
-		var err error
 
-		if requestedLog == "" || requestedLog == "all" {
-			logName = "all"
-			events, err = loadFeedEventsAll(*dataPath, limit)
-		} else {
+		store := ""
+		if dir := strings.TrimSpace(*feedDir); dir != "-" && (requestedLog == "" || requestedLog == "all") {
+			store = filepath.Join(*dataPath, dir)
+		}
+		var reader *feed.Reader
+		readerErr := os.ErrNotExist
+		if store != "" {
+			reader, readerErr = feed.OpenReader(store)
+		}
+		switch {
+		case readerErr == nil:
+			resp.Log = "feed"
+			events, resp.Next, err = readFeedStore(reader, filter, before, after, limit+offset)
+		case !errors.Is(readerErr, os.ErrNotExist):
+			return nil, http.StatusInternalServerError, readerErr
+		case before != "" || after != "":
+			return nil, http.StatusBadRequest, errors.New("before/after cursors need the sharded feed store")
+		case requestedLog == "" || requestedLog == "all":
+			resp.Log = "all"
+			events, err = loadFeedEventsAll(*dataPath, limit+offset, filter)
+		default:
 			var logPath string
-			logPath, logName, err = resolveFeedLog(*dataPath, requestedLog)
+			logPath, resp.Log, err = resolveFeedLog(*dataPath, requestedLog)
 			if err == nil {
-				events, err = loadFeedEvents(logPath, limit)
+				events, err = loadFeedEvents(logPath, limit+offset, filter)
 			}
 		}
 		if err != nil {
@@ -249,20 +315,28 @@ func main() {
 			}
 			return nil, http.StatusBadRequest, err
 		}
+		srvMetrics.feedEvents.Add(float64(len(events)), resp.Log)
 
+		if reader == nil {
+			sort.SliceStable(events, func(i, j int) bool {
+				if events[i].SimTime.Equal(events[j].SimTime) {
+					return events[i].Timestamp.After(events[j].Timestamp)
+				}
+				return events[i].SimTime.After(events[j].SimTime)
+			})
+		}
+		events = page(events, offset, limit)
 		hydrateFeedEventsFromDailyNotes(*dataPath, events)
 		enrichFeedEvents(*dataPath, events)
-		sort.SliceStable(events, func(i, j int) bool {
-			if events[i].SimTime.Equal(events[j].SimTime) {
-				return events[i].Timestamp.After(events[j].Timestamp)
-			}
-			return events[i].SimTime.After(events[j].SimTime)
-		})
 
-		return FeedResponse{
-			Log:    logName,
-			Events: events,
-		}, http.StatusOK, nil
+		if reader != nil {
+			// Poll for newer events from the newest one shown.
+			resp.Prev = after
+			if len(events) > 0 {
+				resp.Prev = events[0].Cursor
+			}
+		}
+		return streamList(resp, "events", events), http.StatusOK, nil
 	}))
 
 	mux.HandleFunc("/api/forum", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
@@ -274,21 +348,33 @@ func main() {
 			return nil, http.StatusInternalServerError, err
 		}
 		limit := parseLimit(r.URL.Query().Get("limit"), 30, 1, 200)
-		sortBy := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sort")))
+		offset := parseOffset(r)
+		sortBy, err := publication.ParsePostSort(r.URL.Query().Get("sort"))
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		window, err := publication.ParseTimeWindow(r.URL.Query().Get("window"))
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
 		subreddit := strings.TrimSpace(r.URL.Query().Get("subreddit"))
+		channel := types.ChannelType(strings.TrimSpace(r.URL.Query().Get("channel")))
+		if channel != "" && channel != types.ChannelForum && channel != types.ChannelPreprint {
+			return nil, http.StatusBadRequest, fmt.Errorf("unknown channel %q", channel)
+		}
 
-		posts := selectForumPosts(forum, subreddit, sortBy, limit)
+		posts := page(forum.RankedChannel(channel, types.Subreddit(subreddit), sortBy, window, limit+offset), offset, limit)
 		stats := forum.GetSubredditStats()
 		statsOut := make(map[string]int, len(stats))
 		for k, v := range stats {
 			statsOut[string(k)] = v
 		}
 
-		return ForumResponse{
+		return streamList(ForumResponse{
 			Name:           forum.Name,
-			Posts:          posts,
 			SubredditStats: statsOut,
-		}, http.StatusOK, nil
+			Subreddits:     forum.ListSubreddits(),
+		}, "posts", renderedList(posts)), http.StatusOK, nil
 	}))
 
 	mux.HandleFunc("/api/forum/posts/", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
@@ -308,8 +394,239 @@ func main() {
 		if post == nil {
 			return nil, http.StatusNotFound, fmt.Errorf("post not found")
 		}
-		comments := forum.GetThreadComments(postID)
-		return ForumPostResponse{Post: post, Comments: comments}, http.StatusOK, nil
+		order, err := publication.ParseCommentSort(r.URL.Query().Get("sort"))
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		comments := forum.CommentTree(postID, order)
+		return ForumPostResponse{
+			Post:      rendered(post),
+			Sort:      order,
+			Comments:  renderedComments(comments),
+			Consensus: loadWorkflow(*dataPath).ConsensusForPost(postID),
+		}, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/forum/posts", handleWrite(humans, createHumanPost))
+	mux.HandleFunc("/api/forum/comments", handleWrite(humans, createHumanComment))
+	mux.HandleFunc("/api/votes", handleWrite(humans, castHumanVote))
+	mux.HandleFunc("/api/inbox", withJSON(spectators.handleList))
+	mux.HandleFunc("/api/inbox/", withJSON(spectators.handleItem))
+
+	mux.HandleFunc("/api/moderation", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		forum, err := loadForum(*dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		limit := parseLimit(r.URL.Query().Get("limit"), 50, 1, 500)
+		openOnly := strings.EqualFold(strings.TrimSpace(r.URL.Query().Get("status")), "open")
+
+		reports := forum.GetReports(openOnly)
+		openCount := len(forum.GetReports(true))
+		actions := forum.GetModerationLog()
+		duplicates := forum.GetDuplicates()
+		sort.SliceStable(reports, func(i, j int) bool { return reports[i].CreatedAt.After(reports[j].CreatedAt) })
+		sort.SliceStable(actions, func(i, j int) bool { return actions[i].At.After(actions[j].At) })
+		if limit < len(reports) {
+			reports = reports[:limit]
+		}
+		if limit < len(actions) {
+			actions = actions[:limit]
+		}
+		if limit < len(duplicates) {
+			duplicates = duplicates[:limit]
+		}
+		return ModerationResponse{
+			OpenReports: openCount,
+			Reports:     reports,
+			Actions:     actions,
+			Duplicates:  duplicates,
+		}, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/experiments", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		store := experiment.NewStore(filepath.Join(*dataPath, "experiments"))
+		if err := store.Load(); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		experiments := store.List(r.URL.Query().Get("agent"))
+		limit := parseLimit(r.URL.Query().Get("limit"), 100, 1, 1000)
+		return page(experiments, parseOffset(r), limit), http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/groups", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		store, err := loadGroups(*dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		groups := withoutChannels(store.List(r.URL.Query().Get("agent")))
+		limit := parseLimit(r.URL.Query().Get("limit"), 100, 1, 1000)
+		return page(groups, parseOffset(r), limit), http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/groups/", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/groups/"), "/")
+		if id == "" {
+			return nil, http.StatusBadRequest, errors.New("missing group id")
+		}
+		store, err := loadGroups(*dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		g, err := store.Get(id)
+		if err != nil {
+			return nil, http.StatusNotFound, err
+		}
+		return g, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/audit", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		q := r.URL.Query()
+		filter := audit.Filter{
+			Actor:  strings.TrimSpace(q.Get("actor")),
+			Store:  strings.TrimSpace(q.Get("store")),
+			Op:     strings.TrimSpace(q.Get("op")),
+			Target: strings.TrimSpace(q.Get("target")),
+		}
+		var err error
+		if filter.Since, err = parseTimeParam(q.Get("since")); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if filter.Until, err = parseTimeParam(q.Get("until")); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		entries, err := audit.Read(filepath.Join(*dataPath, audit.FileName), filter)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		limit := parseLimit(q.Get("limit"), 100, 1, 1000)
+		return AuditResponse{
+			Total:   len(entries),
+			Entries: page(entries, parseOffset(r), limit),
+		}, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/snapshot", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		at, err := parseTimeParam(r.URL.Query().Get("at"))
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if at.IsZero() {
+			return nil, http.StatusBadRequest, errors.New("missing at")
+		}
+		snapshot, err := buildSnapshot(*dataPath, at)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return snapshot, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/timeline", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		timeline, err := buildTimeline(*dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return timeline, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/graph", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		since, err := parseTimeParam(r.URL.Query().Get("since"))
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		until, err := parseTimeParam(r.URL.Query().Get("until"))
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		graph, err := loadGraph(*dataPath, *agentsPath, since, until)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return graph, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/lineage", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		adoptions, err := lineage.LoadAdoptions(*dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if theoryID := strings.TrimSpace(r.URL.Query().Get("theory")); theoryID != "" {
+			adoptions = slices.DeleteFunc(adoptions, func(a lineage.Adoption) bool { return a.TheoryID != theoryID })
+		}
+		return LineageResponse{Theories: lineage.Build(adoptions)}, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/search", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			return nil, http.StatusBadRequest, errors.New("missing query")
+		}
+		scope := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("scope")))
+		if scope == "" {
+			scope = "all"
+		}
+		if scope != "all" && scope != "forum" && scope != "journal" {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid scope: %s", scope)
+		}
+
+		candidates := make([]*types.Publication, 0)
+		if scope != "journal" {
+			if forum, err := loadForum(*dataPath); err == nil {
+				candidates = append(candidates, forum.AllPosts()...)
+			}
+		}
+		if scope != "forum" {
+			if journal, err := loadJournal(*dataPath); err == nil {
+				candidates = append(candidates, journal.GetApproved()...)
+			}
+		}
+		limit := parseLimit(r.URL.Query().Get("limit"), 20, 1, 200)
+		return SearchResponse{
+			Query: query,
+			Scope: scope,
+			Hits:  page(publication.Search(query, candidates), parseOffset(r), limit),
+		}, http.StatusOK, nil
+	}))
+
+	mux.HandleFunc("/api/civility", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		forum, err := loadForum(*dataPath)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return forum.CivilityReport(), http.StatusOK, nil
 	}))
 
 	mux.HandleFunc("/api/journal", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
@@ -323,18 +640,35 @@ func main() {
 
 		approved := journal.GetApproved()
 		pending := journal.GetPending()
+		retracted := journal.GetRetracted()
+		name := journal.Name
+		journalID := strings.TrimSpace(r.URL.Query().Get("journal"))
+		if journalID != "" {
+			info, ok := journal.JournalInfo(journalID)
+			if !ok {
+				return nil, http.StatusNotFound, errors.New("journal not found")
+			}
+			name = info.Name
+			approved = journal.FilterByJournal(approved, journalID)
+			pending = journal.FilterByJournal(pending, journalID)
+			retracted = journal.FilterByJournal(retracted, journalID)
+		}
 		sortPublicationsByTimeDesc(approved)
 		sortPublicationsByTimeDesc(pending)
+		for i, p := range pending {
+			pending[i] = journal.BlindPending(p)
+		}
 
 		limit := parseLimit(r.URL.Query().Get("limit"), 50, 1, 200)
-		if limit < len(approved) {
-			approved = approved[:limit]
-		}
+		approved = page(approved, parseOffset(r), limit)
 
 		return JournalResponse{
-			Name:     journal.Name,
-			Approved: approved,
-			Pending:  pending,
+			Name:      name,
+			Journal:   journalID,
+			Journals:  journal.JournalSummaries(),
+			Approved:  renderedList(approved),
+			Pending:   renderedList(pending),
+			Retracted: renderedList(retracted),
 		}, http.StatusOK, nil
 	}))
 
@@ -354,24 +688,123 @@ func main() {
 
 		var paper *types.Publication
 		status := ""
+		blinded := false
 		if journal != nil {
 			if p, ok := journal.Publications[paperID]; ok {
 				paper = p
 				status = "published"
 			} else if p, ok := journal.Pending[paperID]; ok {
-				paper = p
+				paper = journal.BlindPending(p)
 				status = "pending"
+				blinded = paper != p
+			} else if p, ok := journal.Rejected[paperID]; ok && *showRejected {
+				paper = p
+				status = "rejected"
 			}
 		}
 		if paper == nil {
 			return nil, http.StatusNotFound, fmt.Errorf("paper not found")
 		}
+		workflow := loadWorkflow(*dataPath)
+		forum, _ := loadForum(*dataPath)
+		if paper.RelatedAt.IsZero() && forum != nil {
+			// Papers approved before linking existed (or not yet checkpointed):
+			// compute on the fly without persisting, on a copy since the
+			// journal is shared between requests.
+			linked := *paper
+			linked.RelatedThreads = publication.RelatedDiscussions(paper, workflow, forum)
+			paper = &linked
+		}
 
-		return PaperDetailResponse{
+		resp := PaperDetailResponse{
 			JournalName: journal.Name,
 			Status:      status,
-			Paper:       paper,
-		}, http.StatusOK, nil
+			Paper:       rendered(paper),
+			Disputes:    workflow.DisputesOf(paper.ID),
+			Retraction:  workflow.RetractionOf(paper.ID),
+			Submission:  journal.BlindSubmission(workflow.GetSubmission(paper.ID)),
+			Reviews:     workflow.GetReviews(paper.ID),
+		}
+		if blinded {
+			// The draft, its source thread and the preprint name the
+			// authors of a double-blind submission.
+			return resp, http.StatusOK, nil
+		}
+		if paper.PreprintID != "" && forum != nil {
+			resp.Preprint = rendered(forum.Get(paper.PreprintID))
+		}
+		draftID := paper.DraftID
+		if resp.Submission != nil && resp.Submission.DraftID != "" {
+			draftID = resp.Submission.DraftID
+		}
+		if draftID != "" {
+			resp.Draft = workflow.GetDraft(draftID)
+		}
+		if resp.Draft != nil && forum != nil {
+			sourceID := resp.Draft.SourcePostID
+			if sourceID == "" && resp.Draft.ConsensusID != "" {
+				if req := workflow.GetConsensus(resp.Draft.ConsensusID); req != nil {
+					sourceID = req.PostID
+				}
+			}
+			if sourceID != "" {
+				resp.SourceThread = rendered(forum.Get(sourceID))
+			}
+		}
+		return resp, http.StatusOK, nil
+	}))
+
+	if *showRejected {
+		mux.HandleFunc("/api/journal/rejected", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+			if r.Method != http.MethodGet {
+				return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+			}
+			journal, err := loadJournal(*dataPath)
+			if err != nil {
+				return nil, http.StatusInternalServerError, err
+			}
+			workflow := loadWorkflow(*dataPath)
+
+			rejected := journal.GetRejectedList()
+			sort.Slice(rejected, func(i, j int) bool { return rejected[i].RejectedAt.After(rejected[j].RejectedAt) })
+			limit := parseLimit(r.URL.Query().Get("limit"), 50, 1, 200)
+			if limit < len(rejected) {
+				rejected = rejected[:limit]
+			}
+
+			papers := make([]RejectedPaper, 0, len(rejected))
+			for _, p := range rejected {
+				papers = append(papers, RejectedPaper{Paper: rendered(p), Reviews: workflow.GetReviews(p.ID)})
+			}
+			accepted, rejectedCount, _ := journal.AcceptanceStats()
+			return RejectedResponse{
+				JournalName:    journal.Name,
+				AcceptanceRate: acceptanceRate(accepted, rejectedCount),
+				Papers:         papers,
+			}, http.StatusOK, nil
+		}))
+	}
+
+	mux.HandleFunc("/api/openapi.json", withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		if r.Method != http.MethodGet {
+			return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+		}
+		routes := make([]client.Route, 0, len(client.Routes)+1)
+		for _, route := range client.Routes {
+			if *aggregatesOnlyMode || (route.Path == "/api/journal/rejected" && !*showRejected) {
+				continue
+			}
+			routes = append(routes, route)
+		}
+		if *aggregates || *aggregatesOnlyMode {
+			routes = append(routes, client.Route{
+				Method:   http.MethodGet,
+				Path:     "/api/aggregates",
+				Summary:  "Noisy community statistics without content or identities",
+				Response: AggregatesResponse{},
+			})
+		}
+		return client.OpenAPI(routes), http.StatusOK, nil
 	}))
 
 	// Serve simulation data for the static frontend (no server API required).
@@ -386,11 +819,22 @@ func main() {
 	mux.HandleFunc("/paper/", serveStaticFile(*webPath, "paper.html"))
 	mux.HandleFunc("/agent/", serveStaticFile(*webPath, "agent.html"))
 	mux.HandleFunc("/feed", serveStaticFile(*webPath, "feed.html"))
+	mux.HandleFunc("/ask", serveStaticFile(*webPath, "ask.html"))
 
 	mux.Handle("/", serveStaticDir(*webPath))
 
+	var handler http.Handler = mux
+	if *aggregatesOnlyMode {
+		handler = aggregatesOnly(mux)
+		log.Printf("Aggregates-only mode: raw /api and /data routes are disabled")
+	}
+	if *apiKey != "" {
+		log.Printf("API key required for write requests and /metrics")
+	}
+	handler = guardAccess(handler, *apiKey, splitCSV(*corsOrigins))
+
 	log.Printf("Web server listening on %s", *addr)
-	if err := http.ListenAndServe(*addr, logRequest(mux)); err != nil {
+	if err := http.ListenAndServe(*addr, logRequest(srvMetrics.instrument(gzipResponses(handler)))); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -404,6 +848,10 @@ func withJSON(handler func(http.ResponseWriter, *http.Request) (any, int, error)
 			})
 			return
 		}
+		if r.Method == http.MethodGet && status == http.StatusOK {
+			writeCachedJSON(w, r, status, payload)
+			return
+		}
 		writeJSON(w, status, payload)
 	}
 }
@@ -444,7 +892,9 @@ func serveStaticDir(webPath string) http.Handler {
 	})
 }
 
-func loadForum(dataPath string) (*publication.Forum, error) {
+// openForum reads the forum from disk; read-only handlers use the cached
+// loadForum instead.
+func openForum(dataPath string) (*publication.Forum, error) {
 	forum := publication.NewForum("自由论坛", filepath.Join(dataPath, "forum"))
 	if err := forum.Load(); err != nil {
 		return nil, err
@@ -452,7 +902,16 @@ func loadForum(dataPath string) (*publication.Forum, error) {
 	return forum, nil
 }
 
-func loadJournal(dataPath string) (*publication.Journal, error) {
+// withoutChannels drops channel messages from group listings; fetch one
+// group for its channel.
+func withoutChannels(groups []group.Group) []group.Group {
+	for i := range groups {
+		groups[i].Channel = nil
+	}
+	return groups
+}
+
+func openJournal(dataPath string) (*publication.Journal, error) {
 	journal := publication.NewJournal("科学前沿", filepath.Join(dataPath, "journal"))
 	if err := journal.Load(); err != nil {
 		return nil, err
@@ -485,6 +944,7 @@ func loadAgentsMerged(dataPath, agentsPath string) ([]AgentInfo, error) {
 		if err != nil {
 			continue
 		}
+		loadAgentStanding(dataPath, id).apply(&agent)
 		agents = append(agents, agent)
 	}
 
@@ -536,6 +996,59 @@ func countAgentDirs(path string) (int, error) {
 	return count, nil
 }
 
+// loadActivityStats builds /api/stats from the scheduler's activity index.
+// It returns nil when the data directory has no index yet.
+func loadActivityStats(dataPath string) (*client.Stats, error) {
+	path := filepath.Join(dataPath, "activity.json")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	idx, err := site.LoadActivityIndex(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := idx.Totals
+	stats := &client.Stats{
+		ForumThreads:    t.ForumThreads,
+		ForumComments:   t.ForumComments,
+		JournalApproved: t.JournalApproved,
+		JournalRejected: t.JournalRejected,
+		JournalPending:  t.JournalPending,
+		SimTime:         idx.SimTime,
+		PendingReviews:  t.PendingReviews,
+		OpenConsensus:   t.OpenConsensus,
+	}
+	if idx.SimTime.IsZero() {
+		return stats, nil
+	}
+	stats.ActiveLastDay = idx.ActiveSince(idx.SimTime.Add(-24 * time.Hour))
+	today := statsDay(idx.Day(idx.SimTime))
+	yesterday := statsDay(idx.Day(idx.SimTime.AddDate(0, 0, -1)))
+	stats.Today = &today
+	stats.Yesterday = &yesterday
+	stats.Deltas = &client.StatsDelta{
+		Posts:        today.Posts - yesterday.Posts,
+		Comments:     today.Comments - yesterday.Comments,
+		Papers:       today.Papers - yesterday.Papers,
+		ActiveAgents: today.ActiveAgents - yesterday.ActiveAgents,
+	}
+	return stats, nil
+}
+
+func statsDay(d site.DayActivity) client.StatsDay {
+	return client.StatsDay{
+		Day:          d.Day,
+		Posts:        d.Posts,
+		Comments:     d.Comments,
+		Papers:       d.Papers,
+		ActiveAgents: len(d.ActiveAgents),
+	}
+}
+
 func loadAgentMerged(dataPath, agentsPath, id string) (AgentInfo, error) {
 	state, stateErr := loadAgentFromState(dataPath, id)
 	cfg, cfgErr := loadAgent(agentsPath, id)
@@ -631,6 +1144,40 @@ func loadAgentFromState(dataPath, id string) (AgentInfo, error) {
 	return info, nil
 }
 
+// agentStanding is the part of an agent's state.json the API reports.
+type agentStanding struct {
+	Karma      *types.Karma       `json:"karma"`
+	Reputation *types.Reputation  `json:"reputation"`
+	Traits     *types.TraitRecord `json:"traits"`
+	Agenda     *types.Agenda      `json:"agenda"`
+}
+
+// loadAgentStanding reads karma, reputation, evolved traits and the research
+// agenda from an agent's state.json.
+func loadAgentStanding(dataPath, id string) agentStanding {
+	var st agentStanding
+	data, err := os.ReadFile(filepath.Join(dataPath, "agents", id, "state.json"))
+	if err != nil {
+		return st
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return agentStanding{}
+	}
+	return st
+}
+
+// apply fills in the agent's reputation and, once its traits have drifted,
+// their current values in place of the configured ones.
+func (st agentStanding) apply(agent *AgentInfo) {
+	agent.Reputation = st.Reputation
+	if st.Traits != nil {
+		agent.Creativity = st.Traits.Current.Creativity
+		agent.Rigor = st.Traits.Current.Rigor
+		agent.Sociability = st.Traits.Current.Sociability
+		agent.Influence = st.Traits.Current.Influence
+	}
+}
+
 func roleFromID(id string) string {
 	// Convention: agent-<role>-<n>
 	if strings.HasPrefix(id, "agent-") {
@@ -808,34 +1355,6 @@ func parseFloat(value string) float64 {
 	return val
 }
 
-func selectForumPosts(forum *publication.Forum, subreddit, sortBy string, limit int) []*types.Publication {
-	if subreddit != "" {
-		return filterForumBySubreddit(forum, subreddit, sortBy, limit)
-	}
-	if sortBy == "recent" || sortBy == "new" {
-		return forum.GetRecent(limit)
-	}
-	return forum.GetHot(limit)
-}
-
-func filterForumBySubreddit(forum *publication.Forum, subreddit, sortBy string, limit int) []*types.Publication {
-	target := types.Subreddit(subreddit)
-	if sortBy == "recent" || sortBy == "new" {
-		posts := make([]*types.Publication, 0)
-		for _, p := range forum.AllPosts() {
-			if p.Subreddit == target {
-				posts = append(posts, p)
-			}
-		}
-		sortPublicationsByTimeDesc(posts)
-		if limit < len(posts) {
-			posts = posts[:limit]
-		}
-		return posts
-	}
-	return forum.GetBySubreddit(target, limit)
-}
-
 func sortPublicationsByTimeDesc(items []*types.Publication) {
 	sort.Slice(items, func(i, j int) bool {
 		return items[i].PublishedAt.After(items[j].PublishedAt)
@@ -861,7 +1380,8 @@ func filterJournalByAuthor(journal *publication.Journal, authorID string, approv
 		}
 	} else {
 		for _, p := range journal.GetPending() {
-			if p.AuthorID == authorID {
+			// Listing a blinded submission under its author would unblind it.
+			if p.AuthorID == authorID && journal.BlindPending(p) == p {
 				result = append(result, p)
 			}
 		}
@@ -882,10 +1402,10 @@ func loadDailyNotes(dataPath, agentID string, limit int) []DailyNote {
 			continue
 		}
 		name := entry.Name()
-		if !strings.HasSuffix(name, ".jsonl") {
+		date, ok := dailyFileDate(name)
+		if !ok {
 			continue
 		}
-		date := strings.TrimSuffix(name, ".jsonl")
 		entries, err := readDailyEntries(filepath.Join(dir, name))
 		if err != nil || len(entries) == 0 {
 			continue
@@ -910,8 +1430,51 @@ func loadDailyNotes(dataPath, agentID string, limit int) []DailyNote {
 	return notes
 }
 
+// loadDailyRange returns an agent's daily log entries dated from..to
+// (inclusive YYYY-MM-DD; empty is open), oldest first.
+func loadDailyRange(dataPath, agentID, from, to string) ([]DailyRecord, error) {
+	dir := filepath.Join(dataPath, "agents", agentID, "daily")
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []DailyRecord{}, nil
+		}
+		return nil, err
+	}
+	// ReadDir sorts by name, so YYYY-MM-DD.jsonl(.gz) files come in date
+	// order.
+	out := make([]DailyRecord, 0)
+	for _, file := range files {
+		date, ok := dailyFileDate(file.Name())
+		if file.IsDir() || !ok || (from != "" && date < from) || (to != "" && date > to) {
+			continue
+		}
+		entries, err := readDailyEntries(filepath.Join(dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			out = append(out, DailyRecord{Date: date, DailyEntry: entry})
+		}
+	}
+	return out, nil
+}
+
+// dailyFileDate returns the date of a daily notes file, YYYY-MM-DD.jsonl
+// or its compacted .jsonl.gz.
+func dailyFileDate(name string) (string, bool) {
+	return strings.CutSuffix(strings.TrimSuffix(name, feed.GzipExt), ".jsonl")
+}
+
+// readDailyEntries reads a daily notes file, or its archive if it has been
+// compacted.
 func readDailyEntries(path string) ([]DailyEntry, error) {
-	data, err := os.ReadFile(path)
+	f, err := feed.OpenJSONL(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
 	if err != nil {
 		return nil, err
 	}
@@ -938,11 +1501,17 @@ func resolveFeedLog(dataPath, requested string) (path string, name string, err e
 		if name != requested {
 			return "", "", fmt.Errorf("invalid log path")
 		}
-		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(name, ".jsonl") {
+		if !isLogFile(name) {
 			return "", "", fmt.Errorf("invalid log file")
 		}
 		path = filepath.Join(dataPath, name)
 		st, statErr := os.Stat(path)
+		if os.IsNotExist(statErr) && !strings.HasSuffix(path, feed.GzipExt) {
+			// Compacted since the client listed it.
+			if gzSt, gzErr := os.Stat(path + feed.GzipExt); gzErr == nil {
+				path, st, statErr = path+feed.GzipExt, gzSt, nil
+			}
+		}
 		if statErr != nil {
 			return "", "", statErr
 		}
@@ -964,7 +1533,7 @@ func resolveFeedLog(dataPath, requested string) (path string, name string, err e
 			continue
 		}
 		n := entry.Name()
-		if !strings.HasPrefix(n, "logs") || !strings.HasSuffix(n, ".jsonl") {
+		if !isLogFile(n) {
 			continue
 		}
 		info, infoErr := entry.Info()
@@ -984,7 +1553,66 @@ func resolveFeedLog(dataPath, requested string) (path string, name string, err e
 	return filepath.Join(dataPath, newestName), newestName, nil
 }
 
-func loadFeedEvents(path string, limit int) ([]FeedEvent, error) {
+// feedFilter selects /api/feed events. since and until bound the sim time
+// (since inclusive, until exclusive); zero is open.
+type feedFilter struct {
+	heartbeats   bool
+	agent        string
+	actions      []string
+	since, until time.Time
+}
+
+func (f feedFilter) match(ev *FeedEvent) bool {
+	if ev.Action == heartbeatAction && !f.heartbeats && !slices.Contains(f.actions, heartbeatAction) {
+		return false
+	}
+	if f.agent != "" && ev.AgentID != f.agent {
+		return false
+	}
+	at := ev.SimTime
+	if at.IsZero() {
+		at = ev.Timestamp
+	}
+	if (!f.since.IsZero() && at.Before(f.since)) || (!f.until.IsZero() && !at.Before(f.until)) {
+		return false
+	}
+	return len(f.actions) == 0 || slices.Contains(f.actions, ev.Action)
+}
+
+// readFeedStore reads up to limit events from the sharded feed store,
+// newest first, and returns the cursor of the next older page.
+func readFeedStore(reader *feed.Reader, filter feedFilter, before, after string, limit int) ([]FeedEvent, string, error) {
+	q := feed.Query{
+		Limit:   limit,
+		Before:  before,
+		After:   after,
+		AgentID: filter.agent,
+		Actions: filter.actions,
+		Since:   filter.since,
+		Until:   filter.until,
+	}
+	if !filter.heartbeats && !slices.Contains(filter.actions, heartbeatAction) {
+		q.SkipActions = []string{heartbeatAction}
+	}
+	p, err := reader.Read(q)
+	if err != nil {
+		return nil, "", err
+	}
+	events := make([]FeedEvent, 0, len(p.Events))
+	for _, raw := range p.Events {
+		var ev FeedEvent
+		if err := json.Unmarshal(raw.Data, &ev); err != nil {
+			continue
+		}
+		ev.Cursor = raw.Cursor
+		events = append(events, ev)
+	}
+	return events, p.Next, nil
+}
+
+// loadFeedEvents returns the newest limit events of a log that match
+// filter.
+func loadFeedEvents(path string, limit int, filter feedFilter) ([]FeedEvent, error) {
 	if limit <= 0 {
 		limit = 200
 	}
@@ -992,7 +1620,7 @@ func loadFeedEvents(path string, limit int) ([]FeedEvent, error) {
 		limit = 2000
 	}
 
-	file, err := os.Open(path)
+	file, err := feed.OpenJSONL(path)
 	if err != nil {
 		return nil, err
 	}
@@ -1017,6 +1645,9 @@ func loadFeedEvents(path string, limit int) ([]FeedEvent, error) {
 			// File may be actively appended; ignore partial lines.
 			continue
 		}
+		if !filter.match(&ev) {
+			continue
+		}
 		ring[idx] = ev
 		idx = (idx + 1) % limit
 		if n < limit {
@@ -1044,7 +1675,13 @@ func loadFeedEvents(path string, limit int) ([]FeedEvent, error) {
 	return out, nil
 }
 
-func loadFeedEventsAll(dataPath string, limit int) ([]FeedEvent, error) {
+// isLogFile reports whether name is a simulation log, logs*.jsonl, or one
+// compacted to logs*.jsonl.gz.
+func isLogFile(name string) bool {
+	return strings.HasPrefix(name, "logs") && strings.HasSuffix(strings.TrimSuffix(name, feed.GzipExt), ".jsonl")
+}
+
+func loadFeedEventsAll(dataPath string, limit int, filter feedFilter) ([]FeedEvent, error) {
 	entries, err := os.ReadDir(dataPath)
 	if err != nil {
 		return nil, err
@@ -1056,7 +1693,7 @@ func loadFeedEventsAll(dataPath string, limit int) ([]FeedEvent, error) {
 			continue
 		}
 		name := entry.Name()
-		if !strings.HasPrefix(name, "logs") || !strings.HasSuffix(name, ".jsonl") {
+		if !isLogFile(name) {
 			continue
 		}
 		paths = append(paths, filepath.Join(dataPath, name))
@@ -1067,7 +1704,7 @@ func loadFeedEventsAll(dataPath string, limit int) ([]FeedEvent, error) {
 
 	all := make([]FeedEvent, 0, limit*minInt(len(paths), 10))
 	for _, path := range paths {
-		evs, err := loadFeedEvents(path, limit)
+		evs, err := loadFeedEvents(path, limit, filter)
 		if err != nil {
 			continue
 		}
@@ -1138,6 +1775,9 @@ func hydrateFeedEventsFromDailyNotes(dataPath string, events []FeedEvent) {
 		if entry.Reply != "" {
 			ev.Response = entry.Reply
 		}
+		if entry.Error != "" && ev.Error == "" {
+			ev.Error, ev.ErrorKind = entry.Error, entry.ErrorKind
+		}
 	}
 }
 
@@ -1189,6 +1829,12 @@ func enrichFeedEvents(dataPath string, events []FeedEvent) {
 		if ev.ContentURL != "" {
 			continue
 		}
+		// Events that record outcomes name their content exactly; only
+		// older logs fall back to matching content by time.
+		if len(ev.Outcomes) > 0 {
+			linkOutcomes(ev, forum)
+			continue
+		}
 
 		// Prioritize linking to actual content created in this event.
 		if containsString(ev.ToolCalls, "create_post") {
@@ -1227,6 +1873,56 @@ func enrichFeedEvents(dataPath string, events []FeedEvent) {
 	}
 }
 
+// linkOutcomes links ev to the content its tool calls created, preferring
+// a new post over a comment over a paper submission.
+func linkOutcomes(ev *FeedEvent, forum *publication.Forum) {
+	find := func(tools []string, key string) (client.ToolOutcome, string) {
+		for _, out := range ev.Outcomes {
+			if id := out.Result[key]; id != "" && containsString(tools, out.Tool) {
+				return out, id
+			}
+		}
+		return client.ToolOutcome{}, ""
+	}
+
+	if _, id := find([]string{"create_post"}, "post_id"); id != "" {
+		if post := forum.Get(id); post != nil {
+			ev.ContentKind = "forum_post"
+			ev.ContentID = post.ID
+			ev.ContentTitle = post.Title
+			ev.ContentURL = "/forum?post=" + post.ID
+			return
+		}
+	}
+	if _, id := find([]string{"comment", "request_consensus"}, "comment_id"); id != "" {
+		rootID := forum.ResolveRootPostID(id)
+		if rootID != "" {
+			title := ""
+			if root := forum.Get(rootID); root != nil {
+				title = root.Title
+			}
+			if title == "" {
+				title = "Open thread"
+			}
+			ev.ContentKind = "forum_comment"
+			ev.ContentID = id
+			ev.ContentTitle = title
+			ev.ContentURL = "/forum?post=" + rootID + "#" + id
+			return
+		}
+	}
+	if out, id := find([]string{"submit_paper"}, "submission_id"); id != "" {
+		title := out.Args["title"]
+		if title == "" {
+			title = "Paper submission"
+		}
+		ev.ContentKind = "submission"
+		ev.ContentID = id
+		ev.ContentTitle = title
+		ev.ContentURL = "/paper/" + id
+	}
+}
+
 func containsString(items []string, target string) bool {
 	for _, v := range items {
 		if v == target {
@@ -1270,6 +1966,40 @@ func findClosestByTime(items []*types.Publication, at time.Time, maxDelta time.D
 	return best
 }
 
+// parseOffset reads the ?offset= page start (default 0).
+// parseTimeParam accepts RFC 3339 timestamps or YYYY-MM-DD dates; empty
+// means unset.
+func parseTimeParam(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time: %s", value)
+	}
+	return t, nil
+}
+
+func parseOffset(r *http.Request) int {
+	return parseLimit(r.URL.Query().Get("offset"), 0, 0, 1_000_000)
+}
+
+// page returns items[offset:offset+limit], clipped to the slice.
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
 func parseLimit(value string, fallback, min, max int) int {
 	if value == "" {
 		return fallback
@@ -1287,6 +2017,13 @@ func parseLimit(value string, fallback, min, max int) int {
 	return parsed
 }
 
+func acceptanceRate(accepted, rejected int) float64 {
+	if accepted+rejected == 0 {
+		return 0
+	}
+	return float64(accepted) / float64(accepted+rejected)
+}
+
 func minInt(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/metrics"
+)
+
+// serverMetrics instruments the API server and reports store sizes, read
+// from disk at scrape time.
+type serverMetrics struct {
+	reg *metrics.Registry
+
+	requestSeconds *metrics.HistogramVec
+	feedEvents     *metrics.CounterVec
+	forumPosts     *metrics.GaugeVec
+	journalPapers  *metrics.GaugeVec
+}
+
+func newServerMetrics() *serverMetrics {
+	reg := metrics.NewRegistry()
+	return &serverMetrics{
+		reg:            reg,
+		requestSeconds: reg.Histogram("scibot_http_request_duration_seconds", "HTTP request latency.", nil, "route", "method", "code"),
+		feedEvents:     reg.Counter("scibot_feed_events_loaded_total", "Feed events read from logs to answer /api/feed.", "log"),
+		forumPosts:     reg.Gauge("scibot_forum_posts", "Forum posts by kind.", "kind"),
+		journalPapers:  reg.Gauge("scibot_journal_papers", "Journal papers by status.", "status"),
+	}
+}
+
+// handler refreshes the store gauges and serves the registry.
+func (m *serverMetrics) handler(dataPath string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if forum, err := loadForum(dataPath); err == nil {
+			threads, comments := forum.Counts()
+			m.forumPosts.Set(float64(threads), "thread")
+			m.forumPosts.Set(float64(comments), "comment")
+		}
+		if journal, err := loadJournal(dataPath); err == nil {
+			approved, rejected, pending := journal.AcceptanceStats()
+			m.journalPapers.Set(float64(approved), "approved")
+			m.journalPapers.Set(float64(rejected), "rejected")
+			m.journalPapers.Set(float64(pending), "pending")
+		}
+		m.reg.Handler().ServeHTTP(w, r)
+	})
+}
+
+// instrument records each request's latency under the mux pattern that
+// served it, which keeps IDs and file names out of the label values.
+func (m *serverMetrics) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		m.requestSeconds.Observe(time.Since(start).Seconds(), route, r.Method, strconv.Itoa(rec.status))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/cpunion/sci-bot/pkg/markdown"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// excerptChars is the length of a publication's plain-text excerpt.
+const excerptChars = 280
+
+// rendered returns a copy of pub with its Markdown rendered to sanitized
+// HTML and a plain-text excerpt. The stores are shared between requests, so
+// the stored publication is left alone.
+func rendered(pub *types.Publication) *types.Publication {
+	if pub == nil {
+		return nil
+	}
+	out := *pub
+	out.ContentHTML = markdown.Render(pub.Content)
+	out.AbstractHTML = markdown.Render(pub.Abstract)
+	source := pub.Abstract
+	if source == "" {
+		source = pub.Content
+	}
+	out.Excerpt = markdown.Excerpt(source, excerptChars)
+	return &out
+}
+
+func renderedList(pubs []*types.Publication) []*types.Publication {
+	out := make([]*types.Publication, len(pubs))
+	for i, p := range pubs {
+		out[i] = rendered(p)
+	}
+	return out
+}
+
+func renderedComments(comments []publication.ThreadComment) []publication.ThreadComment {
+	out := make([]publication.ThreadComment, len(comments))
+	for i, c := range comments {
+		c.Publication = rendered(c.Publication)
+		out[i] = c
+	}
+	return out
+}
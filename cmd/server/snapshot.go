@@ -0,0 +1,177 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/client"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// readAuditLog returns the whole audit trail, oldest first, with each
+// entry's sim time filled in (the wall clock for entries recorded without
+// one).
+func readAuditLog(dataPath string) ([]audit.Entry, error) {
+	entries, err := audit.Read(filepath.Join(dataPath, audit.FileName), audit.Filter{})
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	for i := range entries {
+		if entries[i].SimTime.IsZero() {
+			entries[i].SimTime = entries[i].Time
+		}
+	}
+	return entries, nil
+}
+
+// achievedConsensus returns the support_consensus entries that achieved
+// their consensus. The audit trail doesn't say which vote did, so each is
+// the first vote recorded at or after the request's AchievedAt (both are
+// wall-clock times).
+func achievedConsensus(entries []audit.Entry, dataPath string) map[int]bool {
+	workflow := loadWorkflow(dataPath)
+	achieved := make(map[int]bool)
+	seen := make(map[string]bool)
+	for i, e := range entries {
+		if e.Op != "support_consensus" || seen[e.Target] {
+			continue
+		}
+		req := workflow.GetConsensus(e.Target)
+		if req == nil || req.Status != types.ConsensusAchieved || req.AchievedAt.IsZero() {
+			seen[e.Target] = true
+			continue
+		}
+		if !e.Time.Before(req.AchievedAt) {
+			achieved[i] = true
+			seen[e.Target] = true
+		}
+	}
+	return achieved
+}
+
+// buildSnapshot counts the operations recorded at or before sim time at.
+func buildSnapshot(dataPath string, at time.Time) (*client.SnapshotResponse, error) {
+	entries, err := readAuditLog(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	achieved := achievedConsensus(entries, dataPath)
+
+	resp := &client.SnapshotResponse{At: at}
+	actors := make(map[string]bool)
+	recent := make(map[string]bool)
+	dayBefore := at.Add(-24 * time.Hour)
+	for i, e := range entries {
+		if e.SimTime.After(at) {
+			continue
+		}
+		resp.Tick = max(resp.Tick, e.Tick)
+		if e.Actor != audit.ActorSystem {
+			actors[e.Actor] = true
+			if e.SimTime.After(dayBefore) {
+				recent[e.Actor] = true
+			}
+		}
+		switch e.Store {
+		case audit.StoreForum:
+			switch e.Op {
+			case "post":
+				resp.Forum.Threads++
+			case "comment":
+				resp.Forum.Comments++
+			case "create_subreddit":
+				resp.Forum.Subreddits++
+			case "report":
+				resp.Forum.Reports++
+			}
+		case audit.StoreJournal:
+			switch e.Op {
+			case "submit":
+				resp.Journal.Submitted++
+			case "approve":
+				resp.Journal.Accepted++
+				resp.Journal.Papers++
+			case "publish":
+				resp.Journal.Papers++
+			case "reject":
+				resp.Journal.Rejected++
+			case "withdraw":
+				resp.Journal.Withdrawn++
+			case "retract":
+				resp.Journal.Retracted++
+			}
+		case audit.StoreWorkflow:
+			switch e.Op {
+			case "submit":
+				resp.Workflow.Submissions++
+			case "review":
+				resp.Workflow.Reviews++
+			case "decide":
+				resp.Workflow.Decisions++
+			case "draft":
+				resp.Workflow.Drafts++
+			case "request_consensus":
+				resp.Workflow.ConsensusRequests++
+			case "support_consensus":
+				if achieved[i] {
+					resp.Workflow.ConsensusAchieved++
+				}
+			}
+		}
+	}
+	resp.Journal.Pending = max(0, resp.Journal.Submitted-resp.Journal.Accepted-resp.Journal.Rejected-resp.Journal.Withdrawn)
+	resp.Agents.Active = len(actors)
+	resp.Agents.ActiveLast = len(recent)
+	return resp, nil
+}
+
+// milestoneKinds are the milestones /api/timeline reports: the first entry
+// matching store and op.
+var milestoneKinds = []struct {
+	kind, label, store, op string
+}{
+	{"first_post", "First forum thread", audit.StoreForum, "post"},
+	{"first_comment", "First comment", audit.StoreForum, "comment"},
+	{"first_subreddit", "First new subreddit", audit.StoreForum, "create_subreddit"},
+	{"first_submission", "First journal submission", audit.StoreJournal, "submit"},
+	{"first_review", "First peer review", audit.StoreWorkflow, "review"},
+	{"first_paper", "First accepted paper", audit.StoreJournal, "approve"},
+	{"first_rejection", "First rejection", audit.StoreJournal, "reject"},
+	{"first_consensus_request", "First consensus request", audit.StoreWorkflow, "request_consensus"},
+	{"first_draft", "First collaborative draft", audit.StoreWorkflow, "draft"},
+	{"first_dispute", "First dispute", audit.StoreWorkflow, "dispute"},
+	{"first_retraction", "First retraction", audit.StoreJournal, "retract"},
+}
+
+// buildTimeline lists the first occurrence of each milestone, oldest first.
+func buildTimeline(dataPath string) (*client.TimelineResponse, error) {
+	entries, err := readAuditLog(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	achieved := achievedConsensus(entries, dataPath)
+
+	resp := &client.TimelineResponse{Milestones: []client.Milestone{}}
+	found := make(map[string]bool)
+	add := func(kind, label string, e audit.Entry) {
+		found[kind] = true
+		resp.Milestones = append(resp.Milestones, client.Milestone{
+			Kind: kind, Label: label, SimTime: e.SimTime, Tick: e.Tick, Actor: e.Actor, Target: e.Target,
+		})
+	}
+	for i, e := range entries {
+		if achieved[i] && !found["first_consensus"] {
+			add("first_consensus", "First consensus achieved", e)
+		}
+		for _, m := range milestoneKinds {
+			if !found[m.kind] && e.Store == m.store && e.Op == m.op {
+				add(m.kind, m.label, e)
+			}
+		}
+	}
+	return resp, nil
+}
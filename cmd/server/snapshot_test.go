@@ -0,0 +1,81 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestBuildSnapshot_ExcludesLaterOperations(t *testing.T) {
+	dataPath := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	day3 := day1.Add(48 * time.Hour)
+	cutoff := day1.Add(24 * time.Hour)
+
+	simTime, tick := day1, 1
+	auditLog := audit.NewLog(filepath.Join(dataPath, audit.FileName))
+	auditLog.SetClock(func() (time.Time, int) { return simTime, tick })
+	forum := publication.NewForum("F", filepath.Join(dataPath, "forum"))
+	forum.SetAuditLog(auditLog)
+	journal := publication.NewJournal("J", filepath.Join(dataPath, "journal"))
+	journal.SetAuditLog(auditLog)
+
+	if err := forum.Post(&types.Publication{ID: "post-early", AuthorID: "ada", Title: "Early"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Submit(&types.Publication{ID: "paper-1", AuthorID: "ada", Title: "Paper"}); err != nil {
+		t.Fatal(err)
+	}
+	simTime, tick = day3, 9
+	if err := forum.Post(&types.Publication{ID: "post-late", AuthorID: "bo", Title: "Late"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Comment("post-early", &types.Publication{ID: "comment-late", AuthorID: "bo", Content: "Late reply"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Approve("paper-1", "editor"); err != nil {
+		t.Fatal(err)
+	}
+	if err := auditLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := buildSnapshot(dataPath, cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Forum.Threads != 1 || snap.Forum.Comments != 0 {
+		t.Errorf("forum at cutoff = %+v, want 1 thread and no comments", snap.Forum)
+	}
+	if snap.Journal.Submitted != 1 || snap.Journal.Accepted != 0 || snap.Journal.Pending != 1 {
+		t.Errorf("journal at cutoff = %+v, want 1 pending submission", snap.Journal)
+	}
+	if snap.Tick != 1 || snap.Agents.Active != 1 {
+		t.Errorf("tick %d with %d agents at cutoff, want tick 1 and 1 agent", snap.Tick, snap.Agents.Active)
+	}
+
+	snap, err = buildSnapshot(dataPath, day3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Forum.Threads != 2 || snap.Forum.Comments != 1 || snap.Journal.Accepted != 1 || snap.Journal.Pending != 0 {
+		t.Errorf("snapshot at end = %+v", snap)
+	}
+	if snap.Tick != 9 || snap.Agents.Active != 3 || snap.Agents.ActiveLast != 2 {
+		t.Errorf("tick %d with %d agents (%d recent) at end, want 9, 3 and 2", snap.Tick, snap.Agents.Active, snap.Agents.ActiveLast)
+	}
+
+	timeline, err := buildTimeline(dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range timeline.Milestones {
+		if m.Kind == "first_post" && (m.Target != "post-early" || !m.SimTime.Equal(day1)) {
+			t.Errorf("first_post = %+v, want post-early on day 1", m)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// jsonStreamer is a response payload that encodes itself piece by piece
+// instead of being marshaled into one buffer, for lists that can run to
+// megabytes in big simulations.
+type jsonStreamer interface {
+	streamJSON(w io.Writer) error
+}
+
+// listStream encodes head with its list field key holding items, one item
+// at a time.
+type listStream[T any] struct {
+	head  any
+	key   string
+	items []T
+}
+
+// streamList returns a payload that encodes as head with the list field key
+// set to items. The field must be nil in head and come before any nested
+// field of the same name.
+func streamList[T any](head any, key string, items []T) jsonStreamer {
+	return &listStream[T]{head: head, key: key, items: items}
+}
+
+func (s *listStream[T]) streamJSON(w io.Writer) error {
+	data, err := json.Marshal(s.head)
+	if err != nil {
+		return err
+	}
+	marker := []byte(`"` + s.key + `":null`)
+	at := bytes.Index(data, marker)
+	if at < 0 {
+		return fmt.Errorf("stream %s: field not found", s.key)
+	}
+	at += len(marker) - len("null")
+
+	bw := bufio.NewWriterSize(w, 32<<10)
+	bw.Write(data[:at])
+	bw.WriteByte('[')
+	for i, item := range s.items {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+	}
+	bw.WriteByte(']')
+	bw.Write(data[at+len("null"):])
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+var gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+
+// gzipResponses compresses text and JSON responses for clients that accept
+// gzip. Range requests, 304s and responses that already set an encoding
+// pass through untouched.
+func gzipResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+// WriteHeader decides whether to compress, once the handler has set its
+// headers.
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	h := g.Header()
+	if status != http.StatusNoContent && status != http.StatusNotModified &&
+		h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		g.gz = gzipWriters.Get().(*gzip.Writer)
+		g.gz.Reset(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !g.wroteHeader {
+		if g.Header().Get("Content-Type") == "" {
+			g.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.gz == nil {
+		return g.ResponseWriter.Write(p)
+	}
+	return g.gz.Write(p)
+}
+
+// Flush sends what has been compressed so far, for streamed responses.
+func (g *gzipResponseWriter) Flush() {
+	if g.gz != nil {
+		_ = g.gz.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter { return g.ResponseWriter }
+
+func (g *gzipResponseWriter) close() {
+	if g.gz == nil {
+		return
+	}
+	_ = g.gz.Close()
+	g.gz.Reset(io.Discard)
+	gzipWriters.Put(g.gz)
+	g.gz = nil
+}
+
+// compressible reports whether a content type is worth compressing; images
+// and other binary data usually are compressed already.
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "json"),
+		strings.HasSuffix(mediaType, "javascript"),
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/xml":
+		return true
+	}
+	return false
+}
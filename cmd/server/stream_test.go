@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestListStream_MatchesEncoder(t *testing.T) {
+	posts := []*types.Publication{
+		{ID: "post-1", AuthorID: "ada", Title: "Tired <light> & \"redshift\"", Content: "第一帖"},
+		{ID: "post-2", AuthorID: "bo", Title: "Against tired light", Score: 3},
+	}
+	stats := map[string]int{"physics": 2}
+	for _, tc := range []struct {
+		name   string
+		stream jsonStreamer
+		want   any // the same payload with the list filled in
+	}{
+		{
+			name:   "forum",
+			stream: streamList(ForumResponse{Name: "F", SubredditStats: stats}, "posts", posts),
+			want:   ForumResponse{Name: "F", Posts: posts, SubredditStats: stats},
+		},
+		{
+			name:   "empty list",
+			stream: streamList(ForumResponse{Name: "F"}, "posts", []*types.Publication{}),
+			want:   ForumResponse{Name: "F", Posts: []*types.Publication{}},
+		},
+		{
+			name:   "list before other fields",
+			stream: streamList(FeedResponse{Log: "logs.jsonl", Prev: "c-9"}, "events", []FeedEvent{{Action: "turn"}}),
+			want:   FeedResponse{Log: "logs.jsonl", Events: []FeedEvent{{Action: "turn"}}, Prev: "c-9"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got, want bytes.Buffer
+			if err := tc.stream.streamJSON(&got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.NewEncoder(&want).Encode(tc.want); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("streamed:\n%s\nwant:\n%s", got.String(), want.String())
+			}
+		})
+	}
+
+	if err := streamList(FeedResponse{}, "missing", posts).streamJSON(io.Discard); err == nil {
+		t.Error("streaming into a field the head lacks succeeded")
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	payload := ForumResponse{Name: "F", Posts: []*types.Publication{{ID: "post-1", Title: "Tired light"}}}
+	var plain bytes.Buffer
+	if err := json.NewEncoder(&plain).Encode(payload); err != nil {
+		t.Fatal(err)
+	}
+	handler := gzipResponses(withJSON(func(w http.ResponseWriter, r *http.Request) (any, int, error) {
+		return streamList(ForumResponse{Name: "F"}, "posts", payload.Posts), http.StatusOK, nil
+	}))
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/forum", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(nil)
+	if enc := rec.Header().Get("Content-Encoding"); enc != "" || rec.Body.String() != plain.String() {
+		t.Errorf("without Accept-Encoding: encoding %q, body %q; want plain %q", enc, rec.Body, plain.String())
+	}
+	if rec.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", rec.Header().Get("Vary"))
+	}
+	if rec := get(map[string]string{"Accept-Encoding": "gzip;q=0"}); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("compressed for gzip;q=0")
+	}
+
+	rec = get(map[string]string{"Accept-Encoding": "br, gzip"})
+	if enc := rec.Header().Get("Content-Encoding"); enc != "gzip" {
+		t.Fatalf("with Accept-Encoding: encoding %q, want gzip", enc)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != plain.String() {
+		t.Errorf("decompressed body %q, want %q", body, plain.String())
+	}
+	etag := rec.Header().Get("ETag")
+
+	rec = get(map[string]string{"Accept-Encoding": "gzip", "If-None-Match": etag})
+	if rec.Code != http.StatusNotModified || rec.Header().Get("Content-Encoding") != "" || rec.Body.Len() != 0 {
+		t.Errorf("304: status %d, encoding %q, %d body bytes; want an empty, unencoded 304",
+			rec.Code, rec.Header().Get("Content-Encoding"), rec.Body.Len())
+	}
+}
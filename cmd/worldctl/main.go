@@ -0,0 +1,435 @@
+// Command worldctl inspects and edits a saved world between runs: list
+// agents, show a forum thread, post or submit a paper as an agent, and
+// approve or reject pending submissions. It works directly on the data
+// directory, so stop the simulation first; changes go to the audit log
+// like the simulation's own writes.
+//
+//	worldctl [-data dir] agents
+//	worldctl [-data dir] thread <post-id>
+//	worldctl [-data dir] post -as <agent-id> -title T (-content C | -file F) [-subreddit S] [-parent ID]
+//	worldctl [-data dir] submit -as <agent-id> -title T (-content C | -file F) [-abstract A] [-journal J]
+//	worldctl [-data dir] pending
+//	worldctl [-data dir] approve [-by editor] <submission-id>
+//	worldctl [-data dir] reject [-by editor] [-reason R] <submission-id>
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/agent"
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/simulation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// curatorID is the default actor for decisions made with worldctl.
+const curatorID = "worldctl"
+
+// world is a data directory's stores, loaded for one command.
+type world struct {
+	dataPath string
+	forum    *publication.Forum
+	journal  *publication.Journal
+	workflow *publication.Workflow
+	audit    *audit.Log
+	out      io.Writer // command output
+	errOut   io.Writer // usage and flag errors
+}
+
+// errUsage reports a missing or unknown command; the usage has been
+// printed already.
+var errUsage = errors.New("usage")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+		case errors.Is(err, errUsage):
+			os.Exit(2)
+		default:
+			log.Fatal(err)
+		}
+	}
+}
+
+// run parses the global flags and runs one command on the world.
+func run(args []string, out, errOut io.Writer) error {
+	fs := flag.NewFlagSet("worldctl", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	dataPath := fs.String("data", "./data/adk-simulation", "Data directory of the saved world")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "agents", "thread", "post", "submit", "pending", "approve", "reject":
+	default:
+		fs.Usage()
+		return errUsage
+	}
+
+	w, err := openWorld(*dataPath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", *dataPath, err)
+	}
+	defer w.audit.Close()
+	w.out, w.errOut = out, errOut
+
+	switch cmd {
+	case "agents":
+		err = w.listAgents()
+	case "thread":
+		err = w.showThread(args)
+	case "post":
+		err = w.post(args)
+	case "submit":
+		err = w.submit(args)
+	case "pending":
+		w.listPending()
+	case "approve":
+		err = w.decide(args, types.VerdictAccept)
+	case "reject":
+		err = w.decide(args, types.VerdictReject)
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return fmt.Errorf("%s: %w", cmd, err)
+	}
+	return err
+}
+
+func usage(fs *flag.FlagSet) {
+	fmt.Fprintf(fs.Output(), `Usage: worldctl [-data dir] <command> [flags]
+
+Commands:
+  agents                 list agents with karma and reputation
+  thread <post-id>       show a forum thread with its comments
+  post -as <agent-id>    post (or with -parent, comment) as an agent
+  submit -as <agent-id>  submit a paper to the journal as an agent
+  pending                list submissions awaiting a decision
+  approve <id>           accept a pending submission
+  reject <id>            reject a pending submission
+
+Run "worldctl <command> -h" for a command's flags.
+`)
+	fs.PrintDefaults()
+}
+
+// flags returns a command's flag set, reporting errors instead of exiting.
+func (w *world) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w.errOut)
+	return fs
+}
+
+func openWorld(dataPath string) (*world, error) {
+	if _, err := os.Stat(dataPath); err != nil {
+		return nil, err
+	}
+	w := &world{
+		dataPath: dataPath,
+		forum:    publication.NewForum("自由论坛", filepath.Join(dataPath, "forum")),
+		journal:  publication.NewJournal("科学前沿", filepath.Join(dataPath, "journal")),
+		workflow: publication.NewWorkflow(filepath.Join(dataPath, "workflow")),
+		audit:    audit.NewLog(filepath.Join(dataPath, audit.FileName)),
+	}
+	if err := w.forum.Load(); err != nil {
+		return nil, fmt.Errorf("load forum: %w", err)
+	}
+	if err := w.journal.Load(); err != nil {
+		return nil, fmt.Errorf("load journal: %w", err)
+	}
+	if err := w.workflow.Load(); err != nil {
+		return nil, fmt.Errorf("load workflow: %w", err)
+	}
+	w.forum.SetAuditLog(w.audit)
+	w.journal.SetAuditLog(w.audit)
+	w.workflow.SetAuditLog(w.audit)
+	return w, nil
+}
+
+// agentState loads a saved agent; acting as an agent requires one.
+func (w *world) agentState(id string) (*agent.AgentState, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("-as is required")
+	}
+	state, err := agent.LoadAgentState(filepath.Join(w.dataPath, "agents", id))
+	if err != nil {
+		return nil, err
+	}
+	if state.AgentID == "" {
+		return nil, fmt.Errorf("unknown agent %s (no agents/%s/state.json)", id, id)
+	}
+	return state, nil
+}
+
+func (w *world) listAgents() error {
+	entries, err := os.ReadDir(filepath.Join(w.dataPath, "agents"))
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		state, err := agent.LoadAgentState(filepath.Join(w.dataPath, "agents", entry.Name()))
+		if err != nil || state.AgentID == "" {
+			continue
+		}
+		karma := state.GetKarma()
+		line := fmt.Sprintf("%-28s %-20s karma %4d", state.AgentID, state.AgentName, karma.Total())
+		if state.Reputation != nil {
+			line += fmt.Sprintf("  reputation %6.2f", state.Reputation.Score)
+		}
+		if state.Retirement != nil {
+			line += "  (retired)"
+		}
+		fmt.Fprintln(w.out, line)
+	}
+	return nil
+}
+
+func (w *world) showThread(args []string) error {
+	fs := w.flags("thread")
+	sortFlag := fs.String("sort", "old", "Comment order: top, new or old")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: worldctl thread [-sort order] <post-id>")
+	}
+	order, err := publication.ParseCommentSort(*sortFlag)
+	if err != nil {
+		return err
+	}
+	rootID := w.forum.ResolveRootPostID(fs.Arg(0))
+	root := w.forum.Get(rootID)
+	if root == nil {
+		return fmt.Errorf("post not found: %s", fs.Arg(0))
+	}
+	fmt.Fprintf(w.out, "%s  r/%s  by %s  score %d  %s\n", root.ID, root.Subreddit, root.AuthorName, root.Score, root.PublishedAt.Format(time.RFC3339))
+	fmt.Fprintf(w.out, "# %s\n\n%s\n", root.Title, strings.TrimSpace(root.Content))
+	for _, c := range w.forum.CommentTree(rootID, order) {
+		indent := strings.Repeat("  ", c.Depth)
+		fmt.Fprintf(w.out, "\n%s%s by %s (score %d):\n", indent, c.ID, c.AuthorName, c.Score)
+		for _, line := range strings.Split(strings.TrimSpace(c.Content), "\n") {
+			fmt.Fprintf(w.out, "%s  %s\n", indent, line)
+		}
+	}
+	return nil
+}
+
+func (w *world) post(args []string) error {
+	fs := w.flags("post")
+	as := fs.String("as", "", "Agent ID to post as")
+	title := fs.String("title", "", "Post title (ignored for comments)")
+	content := fs.String("content", "", "Post content (Markdown)")
+	file := fs.String("file", "", "Read the content from this file")
+	subreddit := fs.String("subreddit", "", "Subreddit (default general)")
+	parent := fs.String("parent", "", "Comment on this post or comment instead of posting")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	state, err := w.agentState(*as)
+	if err != nil {
+		return err
+	}
+	body, err := readContent(*content, *file)
+	if err != nil {
+		return err
+	}
+	pub := &types.Publication{
+		AuthorID:   state.AgentID,
+		AuthorName: state.AgentName,
+		Content:    body,
+	}
+	if parentID := strings.TrimSpace(*parent); parentID != "" {
+		if err := w.forum.Comment(parentID, pub); err != nil {
+			return err
+		}
+	} else {
+		pub.Title = strings.TrimSpace(*title)
+		if pub.Title == "" {
+			return errors.New("-title is required")
+		}
+		sub := publication.NormalizeSubreddit(*subreddit)
+		if sub != "" && !w.forum.HasSubreddit(sub) {
+			return fmt.Errorf("unknown subreddit: %s", sub)
+		}
+		pub.Subreddit = sub
+		if err := w.forum.Post(pub); err != nil {
+			return err
+		}
+	}
+	if err := w.forum.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, "Published %s as %s\n", pub.ID, state.AgentID)
+	return nil
+}
+
+func (w *world) submit(args []string) error {
+	fs := w.flags("submit")
+	as := fs.String("as", "", "Agent ID to submit as")
+	title := fs.String("title", "", "Paper title")
+	abstract := fs.String("abstract", "", "Paper abstract")
+	content := fs.String("content", "", "Paper content (Markdown)")
+	file := fs.String("file", "", "Read the content from this file")
+	journalFlag := fs.String("journal", "", "Target journal ID or name (default: routed by content)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	state, err := w.agentState(*as)
+	if err != nil {
+		return err
+	}
+	body, err := readContent(*content, *file)
+	if err != nil {
+		return err
+	}
+	paperTitle := strings.TrimSpace(*title)
+	if paperTitle == "" {
+		return errors.New("-title is required")
+	}
+	summary := strings.TrimSpace(*abstract)
+	target, err := w.journal.Route(*journalFlag, paperTitle+"\n"+summary+"\n"+body, nil)
+	if err != nil {
+		return err
+	}
+
+	pub := &types.Publication{
+		AuthorID:   state.AgentID,
+		AuthorName: state.AgentName,
+		Title:      paperTitle,
+		Abstract:   summary,
+		Content:    body,
+		JournalID:  target.ID,
+	}
+	if err := w.journal.Submit(pub); err != nil {
+		return err
+	}
+	now := time.Now()
+	w.workflow.AddSubmission(&types.Submission{
+		ID:         pub.ID,
+		Title:      paperTitle,
+		Abstract:   summary,
+		Content:    body,
+		AuthorID:   state.AgentID,
+		AuthorName: state.AgentName,
+		JournalID:  target.ID,
+		Status:     types.SubmissionPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err := w.journal.Save(); err != nil {
+		return err
+	}
+	if err := w.workflow.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, "Submitted %s to %s as %s\n", pub.ID, target.Name, state.AgentID)
+	return nil
+}
+
+func (w *world) listPending() {
+	pending := w.journal.GetPending()
+	if len(pending) == 0 {
+		fmt.Fprintln(w.out, "No pending submissions.")
+		return
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	for _, p := range pending {
+		line := fmt.Sprintf("%s  %s  by %s", p.ID, p.Title, p.AuthorName)
+		if sub := w.workflow.GetSubmission(p.ID); sub != nil {
+			line += fmt.Sprintf("  [%s, %d review(s)]", sub.Status, len(w.workflow.GetReviews(p.ID)))
+		}
+		fmt.Fprintln(w.out, line)
+	}
+}
+
+// decide accepts or rejects a pending submission outright: unlike the
+// scheduler's decisions, the journal's acceptance threshold is not applied.
+func (w *world) decide(args []string, verdict types.PaperReviewVerdict) error {
+	name := "approve"
+	if verdict == types.VerdictReject {
+		name = "reject"
+	}
+	fs := w.flags(name)
+	by := fs.String("by", curatorID, "Editor recorded as making the decision")
+	reason := fs.String("reason", "", "Rejection reason shown to the author")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: worldctl %s [flags] <submission-id>", name)
+	}
+	id := fs.Arg(0)
+
+	if verdict == types.VerdictAccept {
+		if err := w.journal.Approve(id, *by); err != nil {
+			return err
+		}
+	} else {
+		rationale := strings.TrimSpace(*reason)
+		if rationale == "" {
+			rationale = "Rejected by the editor."
+		}
+		if err := w.journal.RejectWithReason(id, *by, rationale); err != nil {
+			return err
+		}
+	}
+	// The journal is already decided; a nil journal records the decision in
+	// the workflow without re-applying it or the acceptance threshold.
+	status, err := w.workflow.Decide(nil, id, verdict, *by, w.simTime(), false)
+	if err != nil {
+		return err
+	}
+	if err := w.journal.Save(); err != nil {
+		return err
+	}
+	if err := w.workflow.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, "%s -> %s\n", id, status)
+	return nil
+}
+
+// simTime is the world's current sim time, which stamps decisions like the
+// scheduler does; worlds without sim_state.json fall back to the clock.
+func (w *world) simTime() time.Time {
+	if state, err := simulation.LoadSimState(w.dataPath); err == nil && !state.SimTime.IsZero() {
+		return state.SimTime
+	}
+	return time.Now()
+}
+
+func readContent(content, file string) (string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		content = string(data)
+	}
+	if content = strings.TrimSpace(content); content == "" {
+		return "", errors.New("-content or -file is required")
+	}
+	return content, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/agent"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// newWorld saves a world with one agent, one thread and one pending
+// submission.
+func newWorld(t *testing.T) string {
+	t.Helper()
+	dataPath := t.TempDir()
+	if err := agent.NewAgentState("agent-1", "Ada", filepath.Join(dataPath, "agents", "agent-1")).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	forum := publication.NewForum("F", filepath.Join(dataPath, "forum"))
+	if err := forum.Post(&types.Publication{ID: "post-1", AuthorID: "agent-1", AuthorName: "Ada", Title: "Tired light", Content: "Redshift without expansion."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Comment("post-1", &types.Publication{ID: "comment-1", AuthorID: "agent-1", AuthorName: "Ada", Content: "Follow-up."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	journal := publication.NewJournal("J", filepath.Join(dataPath, "journal"))
+	if err := journal.Submit(&types.Publication{ID: "paper-1", AuthorID: "agent-1", AuthorName: "Ada", Title: "On tired light"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Save(); err != nil {
+		t.Fatal(err)
+	}
+	workflow := publication.NewWorkflow(filepath.Join(dataPath, "workflow"))
+	workflow.AddSubmission(&types.Submission{ID: "paper-1", AuthorID: "agent-1", AuthorName: "Ada", Title: "On tired light"})
+	if err := workflow.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return dataPath
+}
+
+func TestRun_Parsing(t *testing.T) {
+	dataPath := newWorld(t)
+	for _, tc := range []struct {
+		name string
+		args []string
+		want error  // matched with errors.Is
+		msg  string // substring of the error otherwise
+	}{
+		{name: "no command", args: []string{"-data", dataPath}, want: errUsage},
+		{name: "unknown command", args: []string{"-data", dataPath, "frobnicate"}, want: errUsage},
+		{name: "help", args: []string{"-h"}, want: flag.ErrHelp},
+		{name: "bad global flag", args: []string{"-bogus"}, msg: "flag provided but not defined"},
+		{name: "missing data", args: []string{"-data", filepath.Join(dataPath, "missing"), "agents"}, msg: "open "},
+		{name: "thread without id", args: []string{"-data", dataPath, "thread"}, msg: "usage: worldctl thread"},
+		{name: "bad command flag", args: []string{"-data", dataPath, "approve", "-bogus", "paper-1"}, msg: "flag provided but not defined"},
+		{name: "post without agent", args: []string{"-data", dataPath, "post", "-title", "T", "-content", "C"}, msg: "-as is required"},
+		{name: "submit as unknown agent", args: []string{"-data", dataPath, "submit", "-as", "agent-9", "-title", "T", "-content", "C"}, msg: "unknown agent agent-9"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			err := run(tc.args, &out, &errOut)
+			switch {
+			case tc.want != nil && !errors.Is(err, tc.want):
+				t.Errorf("err = %v, want %v", err, tc.want)
+			case tc.want == nil && (err == nil || !strings.Contains(err.Error(), tc.msg)):
+				t.Errorf("err = %v, want one containing %q", err, tc.msg)
+			}
+			if out.Len() != 0 {
+				t.Errorf("unexpected output:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestRun_ReadOnlyCommands(t *testing.T) {
+	dataPath := newWorld(t)
+	journalBefore, err := os.ReadFile(filepath.Join(dataPath, "journal", "journal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"pending"}, []string{"paper-1  On tired light  by Ada  [pending, 0 review(s)]"}},
+		{[]string{"agents"}, []string{"agent-1", "Ada", "karma"}},
+		{[]string{"thread", "comment-1"}, []string{"post-1  r/general  by Ada", "# Tired light", "comment-1 by Ada", "Follow-up."}},
+	} {
+		var out, errOut bytes.Buffer
+		if err := run(append([]string{"-data", dataPath}, tc.args...), &out, &errOut); err != nil {
+			t.Fatalf("%v: %v\n%s", tc.args, err, errOut.String())
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%v output missing %q:\n%s", tc.args, want, out.String())
+			}
+		}
+	}
+
+	journalAfter, err := os.ReadFile(filepath.Join(dataPath, "journal", "journal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(journalBefore, journalAfter) {
+		t.Error("read-only commands changed the journal")
+	}
+}
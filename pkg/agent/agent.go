@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cpunion/sci-bot/pkg/idgen"
 	"github.com/cpunion/sci-bot/pkg/memory"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
@@ -36,6 +37,8 @@ type Agent struct {
 	// Control
 	ctx    context.Context
 	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	onStop []func()
 
 	// Timing
 	thinkInterval   time.Duration
@@ -96,14 +99,17 @@ func (a *Agent) SetLLM(llm LLMProvider) {
 	a.LLM = llm
 }
 
-// Start begins the agent's main loop.
-func (a *Agent) Start() error {
+// Start begins the agent's main loop. The loop and any in-flight LLM calls
+// stop when ctx is done or Stop is called.
+func (a *Agent) Start(ctx context.Context) error {
 	a.mu.Lock()
 	if a.running {
 		a.mu.Unlock()
 		return fmt.Errorf("agent already running")
 	}
 	a.running = true
+	a.ctx, a.cancel = context.WithCancel(ctx)
+	runCtx := a.ctx
 	a.mu.Unlock()
 
 	// Load persisted memory
@@ -111,11 +117,16 @@ func (a *Agent) Start() error {
 		// Ignore load errors for new agents
 	}
 
-	go a.run()
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.run(runCtx)
+	}()
 	return nil
 }
 
-// Stop stops the agent.
+// Stop stops the agent, waits for its loop to exit, runs the OnStop hooks and
+// persists memory.
 func (a *Agent) Stop() error {
 	a.mu.Lock()
 	if !a.running {
@@ -123,16 +134,50 @@ func (a *Agent) Stop() error {
 		return fmt.Errorf("agent not running")
 	}
 	a.running = false
+	cancel := a.cancel
+	hooks := a.onStop
 	a.mu.Unlock()
 
-	a.cancel()
+	cancel()
+	a.wg.Wait()
+	for _, fn := range hooks {
+		fn()
+	}
 
 	// Persist memory
 	return a.Memory.Save()
 }
 
+// OnStop registers fn to run after the agent's loop has exited in Stop, e.g.
+// to drop broker subscriptions.
+func (a *Agent) OnStop(fn func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onStop = append(a.onStop, fn)
+}
+
+// Deliver queues msg in the agent's inbox, waiting for room until the agent
+// stops or ctx is done.
+func (a *Agent) Deliver(ctx context.Context, msg *types.Message) error {
+	a.mu.RLock()
+	running, done := a.running, a.ctx.Done()
+	a.mu.RUnlock()
+	if !running {
+		return fmt.Errorf("agent not running")
+	}
+
+	select {
+	case a.Inbox <- msg:
+		return nil
+	case <-done:
+		return fmt.Errorf("agent stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // run is the main event loop.
-func (a *Agent) run() {
+func (a *Agent) run(ctx context.Context) {
 	thinkTicker := time.NewTicker(a.thinkInterval)
 	exploreTicker := time.NewTicker(a.exploreInterval)
 	defer thinkTicker.Stop()
@@ -140,7 +185,7 @@ func (a *Agent) run() {
 
 	for {
 		select {
-		case <-a.ctx.Done():
+		case <-ctx.Done():
 			return
 
 		case msg := <-a.Inbox:
@@ -329,7 +374,7 @@ func (a *Agent) send(msg *types.Message) {
 // createResponse creates a response message.
 func (a *Agent) createResponse(original *types.Message, content string) *types.Message {
 	return &types.Message{
-		ID:         fmt.Sprintf("%s-%d", a.Persona.ID, time.Now().UnixNano()),
+		ID:         idgen.New(a.Persona.ID),
 		Type:       types.MsgReply,
 		From:       a.Persona.ID,
 		To:         []string{original.From},
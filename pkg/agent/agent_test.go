@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// blockingLLM answers only after ctx is done, like a slow model call that
+// must be interrupted on shutdown.
+type blockingLLM struct{}
+
+func (blockingLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+type echoLLM struct{}
+
+func (echoLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	return "echo", nil
+}
+
+func newTestAgent(t *testing.T, llm LLMProvider) *Agent {
+	persona := &types.Persona{ID: "agent-1", Name: "Galileo", Role: types.RoleExplorer}
+	a := New(DefaultConfig(persona, t.TempDir()))
+	a.SetLLM(llm)
+	return a
+}
+
+func TestAgent_DeliverAndReply(t *testing.T) {
+	a := newTestAgent(t, echoLLM{})
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	msg := &types.Message{ID: "m1", From: "user", To: []string{"agent-1"}, Content: "hi"}
+	if err := a.Deliver(ctx, msg); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	select {
+	case resp := <-a.Outbox:
+		if resp.Content != "echo" || resp.InReplyTo != "m1" {
+			t.Fatalf("unexpected reply: %+v", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reply")
+	}
+
+	if err := a.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := a.Deliver(ctx, msg); err == nil {
+		t.Fatal("expected Deliver to fail after Stop")
+	}
+}
+
+func TestAgent_StopInterruptsLLMAndRunsHooks(t *testing.T) {
+	a := newTestAgent(t, blockingLLM{})
+	stopped := 0
+	a.OnStop(func() { stopped++ })
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := a.Deliver(context.Background(), &types.Message{ID: "m1", From: "user"}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- a.Stop() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop hung on in-flight LLM call")
+	}
+	if stopped != 1 {
+		t.Fatalf("expected OnStop hook to run once, got %d", stopped)
+	}
+
+	// The agent can be restarted after a clean stop.
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if err := a.Stop(); err != nil {
+		t.Fatalf("second Stop: %v", err)
+	}
+}
+
+func TestAgent_ParentContextCancel(t *testing.T) {
+	a := newTestAgent(t, blockingLLM{})
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := a.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := a.Deliver(ctx, &types.Message{ID: "m1", From: "user"}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	cancel()
+
+	if err := a.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+}
@@ -21,6 +21,12 @@ type AgentState struct {
 	Knowledge     map[string]*types.KnowledgeItem `json:"knowledge"`
 	Subscriptions []string                        `json:"subscriptions"`
 	LastActive    time.Time                       `json:"last_active"`
+	Karma         *types.Karma                    `json:"karma,omitempty"`
+	Reputation    *types.Reputation               `json:"reputation,omitempty"`
+	Retirement    *types.Retirement               `json:"retirement,omitempty"`
+	Traits        *types.TraitRecord              `json:"traits,omitempty"`
+	Agenda        *types.Agenda                   `json:"agenda,omitempty"`
+	Notifications *types.Notifications            `json:"notifications,omitempty"`
 
 	// Persistence path
 	dataPath string
@@ -143,6 +149,13 @@ func (s *AgentState) GetKnowledge(theoryID string) *types.KnowledgeItem {
 
 // LearnTheory adds or updates knowledge about a theory.
 func (s *AgentState) LearnTheory(theoryID, theoryTitle, source string) {
+	s.LearnTheoryFrom(theoryID, theoryTitle, source, "")
+}
+
+// LearnTheoryFrom is LearnTheory via a post: the first time the agent hears
+// of the theory it records who it learned it from (source) and in which post,
+// so propagation trees can be rebuilt later. Later reviews keep that origin.
+func (s *AgentState) LearnTheoryFrom(theoryID, theoryTitle, source, postID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -158,6 +171,7 @@ func (s *AgentState) LearnTheory(theoryID, theoryTitle, source string) {
 			LastReviewed: now,
 			Confidence:   0.3,
 			Source:       source,
+			SourcePostID: postID,
 		}
 		return
 	}
@@ -216,6 +230,231 @@ func (s *AgentState) GetActivePeers() []*types.Relationship {
 	return result
 }
 
+// ApplyReceivedVote updates karma for a vote cast on this agent's publication.
+func (s *AgentState) ApplyReceivedVote(change types.VoteChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := s.ensureKarmaLocked()
+	delta := change.ScoreDelta()
+	if change.IsComment {
+		k.CommentKarma += delta
+	} else {
+		k.PostKarma += delta
+	}
+	k.ReceivedUp += change.UpDelta
+	k.ReceivedDown += change.DownDelta
+	s.appendKarmaLocked(k, types.KarmaRecord{
+		Direction: "received",
+		PeerID:    change.VoterID,
+		PostID:    change.PostID,
+		IsComment: change.IsComment,
+		Delta:     delta,
+		At:        change.At,
+	})
+}
+
+// ApplyGivenVote updates karma history for a vote cast by this agent.
+func (s *AgentState) ApplyGivenVote(change types.VoteChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := s.ensureKarmaLocked()
+	k.GivenUp += change.UpDelta
+	k.GivenDown += change.DownDelta
+	s.appendKarmaLocked(k, types.KarmaRecord{
+		Direction: "given",
+		PeerID:    change.AuthorID,
+		PostID:    change.PostID,
+		IsComment: change.IsComment,
+		Delta:     change.ScoreDelta(),
+		At:        change.At,
+	})
+}
+
+// GetKarma returns a copy of the agent's karma.
+func (s *AgentState) GetKarma() types.Karma {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Karma == nil {
+		return types.Karma{}
+	}
+	k := *s.Karma
+	k.History = append([]types.KarmaRecord(nil), s.Karma.History...)
+	return k
+}
+
+// SetReputation stores the agent's latest reputation snapshot.
+func (s *AgentState) SetReputation(rep types.Reputation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Reputation = &rep
+}
+
+// GetReputation returns the agent's last reputation snapshot, if any.
+func (s *AgentState) GetReputation() (types.Reputation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Reputation == nil {
+		return types.Reputation{}, false
+	}
+	return *s.Reputation, true
+}
+
+// Retire marks the agent as retired from the community.
+func (s *AgentState) Retire(r types.Retirement) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Retirement = &r
+}
+
+// GetRetirement returns the agent's retirement record, if it has retired.
+func (s *AgentState) GetRetirement() (types.Retirement, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Retirement == nil {
+		return types.Retirement{}, false
+	}
+	return *s.Retirement, true
+}
+
+// SetTraits stores the agent's evolved traits and their history.
+func (s *AgentState) SetTraits(rec types.TraitRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec.History = append([]types.TraitChange(nil), rec.History...)
+	s.Traits = &rec
+}
+
+// GetTraits returns a copy of the agent's trait record, if traits have
+// been tracked.
+func (s *AgentState) GetTraits() (types.TraitRecord, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Traits == nil {
+		return types.TraitRecord{}, false
+	}
+	rec := *s.Traits
+	rec.History = append([]types.TraitChange(nil), s.Traits.History...)
+	return rec, true
+}
+
+// UpdateAgenda applies fn to the agent's research agenda under the state
+// lock, creating the agenda on first use.
+func (s *AgentState) UpdateAgenda(fn func(*types.Agenda) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Agenda == nil {
+		s.Agenda = &types.Agenda{}
+	}
+	return fn(s.Agenda)
+}
+
+// GetAgenda returns a copy of the agent's research agenda, if it has one.
+func (s *AgentState) GetAgenda() (types.Agenda, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Agenda == nil {
+		return types.Agenda{}, false
+	}
+	a := *s.Agenda
+	a.Goals = append([]types.Goal(nil), s.Agenda.Goals...)
+	a.Projects = append([]types.Project(nil), s.Agenda.Projects...)
+	return a, true
+}
+
+// NotificationsReadThrough returns the time up to which all of the agent's
+// mentions and replies count as read.
+func (s *AgentState) NotificationsReadThrough() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Notifications == nil {
+		return time.Time{}
+	}
+	return s.Notifications.ReadThrough
+}
+
+// NotificationRead reports whether the agent has read a mention, or with
+// reply set a reply, published at publishedAt.
+func (s *AgentState) NotificationRead(reply bool, pubID string, publishedAt time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := s.Notifications
+	if n == nil {
+		return false
+	}
+	if !publishedAt.After(n.ReadThrough) {
+		return true
+	}
+	seen := n.SeenMentions
+	if reply {
+		seen = n.SeenReplies
+	}
+	_, ok := seen[pubID]
+	return ok
+}
+
+// MarkNotificationRead marks a mention, or with reply set a reply, as read.
+// Past MaxSeenNotifications marks the oldest are dropped and ReadThrough
+// moves up to cover them.
+func (s *AgentState) MarkNotificationRead(reply bool, pubID string, publishedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Notifications == nil {
+		s.Notifications = &types.Notifications{}
+	}
+	n := s.Notifications
+	if !publishedAt.After(n.ReadThrough) {
+		return
+	}
+	seen := &n.SeenMentions
+	if reply {
+		seen = &n.SeenReplies
+	}
+	if *seen == nil {
+		*seen = make(map[string]time.Time)
+	}
+	(*seen)[pubID] = publishedAt
+	if len(*seen) <= types.MaxSeenNotifications {
+		return
+	}
+	for len(*seen) > types.MaxSeenNotifications {
+		oldestID, oldest := "", time.Time{}
+		for id, at := range *seen {
+			if oldestID == "" || at.Before(oldest) {
+				oldestID, oldest = id, at
+			}
+		}
+		delete(*seen, oldestID)
+		if oldest.After(n.ReadThrough) {
+			n.ReadThrough = oldest
+		}
+	}
+	// Marks the new ReadThrough covers are redundant, in either kind.
+	for _, m := range []map[string]time.Time{n.SeenMentions, n.SeenReplies} {
+		for id, at := range m {
+			if !at.After(n.ReadThrough) {
+				delete(m, id)
+			}
+		}
+	}
+}
+
+func (s *AgentState) ensureKarmaLocked() *types.Karma {
+	if s.Karma == nil {
+		s.Karma = &types.Karma{}
+	}
+	return s.Karma
+}
+
+func (s *AgentState) appendKarmaLocked(k *types.Karma, rec types.KarmaRecord) {
+	k.History = append(k.History, rec)
+	if len(k.History) > types.MaxKarmaHistory {
+		k.History = k.History[len(k.History)-types.MaxKarmaHistory:]
+	}
+	k.LastUpdatedAt = rec.At
+}
+
 // Save persists the agent state to disk.
 func (s *AgentState) Save() error {
 	s.mu.RLock()
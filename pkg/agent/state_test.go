@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -163,3 +164,89 @@ func TestAgentState_GetPeers(t *testing.T) {
 		t.Errorf("expected 2 active peers (not forgotten), got %d", len(active))
 	}
 }
+
+func TestAgentState_Karma(t *testing.T) {
+	author := NewAgentState("agent-1", "Galileo", t.TempDir())
+	voter := NewAgentState("agent-2", "Euclid", t.TempDir())
+
+	now := time.Now()
+	changes := []types.VoteChange{
+		{VoterID: "agent-2", AuthorID: "agent-1", PostID: "post-1", UpDelta: 1, At: now},
+		{VoterID: "agent-2", AuthorID: "agent-1", PostID: "comment-1", IsComment: true, DownDelta: 1, At: now},
+		// Switching the comment vote from down to up.
+		{VoterID: "agent-2", AuthorID: "agent-1", PostID: "comment-1", IsComment: true, UpDelta: 1, DownDelta: -1, At: now},
+	}
+	for _, c := range changes {
+		author.ApplyReceivedVote(c)
+		voter.ApplyGivenVote(c)
+	}
+
+	k := author.GetKarma()
+	if k.PostKarma != 1 || k.CommentKarma != 1 || k.Total() != 2 {
+		t.Errorf("expected post=1 comment=1 total=2, got post=%d comment=%d total=%d", k.PostKarma, k.CommentKarma, k.Total())
+	}
+	if k.ReceivedUp != 2 || k.ReceivedDown != 0 {
+		t.Errorf("expected received up=2 down=0, got up=%d down=%d", k.ReceivedUp, k.ReceivedDown)
+	}
+	if len(k.History) != 3 || k.History[0].Direction != "received" {
+		t.Errorf("expected 3 received records, got %+v", k.History)
+	}
+
+	g := voter.GetKarma()
+	if g.GivenUp != 2 || g.GivenDown != 0 || g.Total() != 0 {
+		t.Errorf("expected given up=2 down=0 total=0, got up=%d down=%d total=%d", g.GivenUp, g.GivenDown, g.Total())
+	}
+
+	if err := author.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	loaded, err := LoadAgentState(author.dataPath)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if lk := loaded.GetKarma(); lk.Total() != 2 {
+		t.Errorf("expected persisted karma total=2, got %d", lk.Total())
+	}
+}
+
+func TestAgentState_NotificationsRead(t *testing.T) {
+	dir := t.TempDir()
+	state := NewAgentState("agent-1", "Galileo", dir)
+	base := time.Now().Add(-time.Hour)
+
+	if state.NotificationRead(false, "c1", base) {
+		t.Fatal("nothing should be read yet")
+	}
+	state.MarkNotificationRead(false, "c1", base)
+	if !state.NotificationRead(false, "c1", base) {
+		t.Error("expected c1 to be read as a mention")
+	}
+	if state.NotificationRead(true, "c1", base) {
+		t.Error("a read mention should not mark the reply kind")
+	}
+
+	// Past the cap the oldest marks fold into ReadThrough.
+	for i := 1; i <= types.MaxSeenNotifications+1; i++ {
+		state.MarkNotificationRead(true, fmt.Sprintf("r%d", i), base.Add(time.Duration(i)*time.Second))
+	}
+	if got := state.NotificationsReadThrough(); !got.Equal(base.Add(time.Second)) {
+		t.Errorf("expected ReadThrough at the oldest dropped reply, got %v", got)
+	}
+	if !state.NotificationRead(false, "c1", base) || !state.NotificationRead(true, "r1", base.Add(time.Second)) {
+		t.Error("marks covered by ReadThrough should still read as seen")
+	}
+	if state.NotificationRead(true, "new", base.Add(time.Hour)) {
+		t.Error("a newer reply should be unread")
+	}
+
+	if err := state.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	loaded, err := LoadAgentState(dir)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if !loaded.NotificationRead(true, "r2", base.Add(2*time.Second)) || loaded.NotificationRead(true, "new", base.Add(time.Hour)) {
+		t.Errorf("read state did not survive a reload: %+v", loaded.Notifications)
+	}
+}
@@ -0,0 +1,215 @@
+// Package audit keeps an append-only trail of mutating operations on the
+// shared stores (forum, journal, workflow): who did what to which object,
+// when, and how the object changed.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Stores covered by the audit trail.
+const (
+	StoreForum    = "forum"
+	StoreJournal  = "journal"
+	StoreWorkflow = "workflow"
+)
+
+// FileName is the audit trail's file under the data root.
+const FileName = "audit.jsonl"
+
+// ActorSystem is the actor of operations not triggered by an agent, such as
+// scheduled review cycles.
+const ActorSystem = "system"
+
+// Entry is one mutating operation.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	SimTime time.Time `json:"sim_time,omitempty"`
+	Tick    int       `json:"tick,omitempty"`
+	Actor   string    `json:"actor"`
+	Store   string    `json:"store"`
+	Op      string    `json:"op"`
+	Target  string    `json:"target"`
+	// Before and After hash the target object's JSON around the operation;
+	// Before is empty for creations.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Log appends entries to a JSONL file. A nil *Log records nothing, so stores
+// can call it unconditionally.
+type Log struct {
+	mu    sync.Mutex
+	path  string
+	f     *os.File
+	clock func() (time.Time, int)
+}
+
+// NewLog creates a log appending to path. The file is opened on first use.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// SetClock supplies the sim time and tick stamped on new entries.
+func (l *Log) SetClock(fn func() (simTime time.Time, tick int)) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = fn
+}
+
+// Hash returns a short content hash of v, or "" when l is nil so callers
+// don't pay for hashing with auditing disabled.
+func (l *Log) Hash(v any) string {
+	if l == nil {
+		return ""
+	}
+	return Hash(v)
+}
+
+// Record appends e, filling in the wall clock and, if a clock is set, the
+// sim time and tick. Write errors are logged, not returned: auditing must
+// not fail the operation it describes.
+func (l *Log) Record(e Entry) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Time = time.Now()
+	if l.clock != nil {
+		e.SimTime, e.Tick = l.clock()
+	}
+	if e.Actor == "" {
+		e.Actor = ActorSystem
+	}
+	if err := l.appendLocked(e); err != nil {
+		log.Printf("Failed to write audit entry: %v", err)
+	}
+}
+
+func (l *Log) appendLocked(e Entry) error {
+	if l.f == nil {
+		if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		l.f = f
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = l.f.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the underlying file; later records reopen it.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// Hash returns the first 16 hex digits of the SHA-256 of v's JSON.
+func Hash(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Filter selects entries; empty fields match everything. Since and Until
+// compare sim time when an entry has one, wall time otherwise.
+type Filter struct {
+	Actor  string
+	Store  string
+	Op     string
+	Target string
+	Since  time.Time
+	Until  time.Time
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Entry) bool {
+	if f.Actor != "" && e.Actor != f.Actor {
+		return false
+	}
+	if f.Store != "" && e.Store != f.Store {
+		return false
+	}
+	if f.Op != "" && e.Op != f.Op {
+		return false
+	}
+	if f.Target != "" && e.Target != f.Target {
+		return false
+	}
+	at := e.SimTime
+	if at.IsZero() {
+		at = e.Time
+	}
+	if !f.Since.IsZero() && at.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !at.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Read returns the entries in path that match f, newest first. A missing
+// file yields no entries; corrupt lines are skipped.
+func Read(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var out []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, nil
+}
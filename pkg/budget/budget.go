@@ -0,0 +1,180 @@
+// Package budget tracks LLM token spend per agent and per run and enforces quotas.
+package budget
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Config sets token quotas. Zero values disable the corresponding limit.
+type Config struct {
+	// MaxRunTokens caps total tokens spent by this process.
+	MaxRunTokens int
+	// MaxAgentDailyTokens caps total tokens an agent may spend per sim day.
+	MaxAgentDailyTokens int
+}
+
+// Usage aggregates token counts.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CandidatesTokens int `json:"candidates_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u *Usage) add(prompt, candidates, total int) {
+	u.PromptTokens += prompt
+	u.CandidatesTokens += candidates
+	u.TotalTokens += total
+}
+
+// AgentUsage tracks cumulative and per-day usage for one agent.
+type AgentUsage struct {
+	Usage
+	Daily map[string]int `json:"daily"` // YYYY-MM-DD (sim time) -> total tokens
+}
+
+// Tracker accumulates token usage. It is safe for concurrent use.
+type Tracker struct {
+	mu sync.RWMutex
+
+	cfg    Config
+	run    Usage
+	agents map[string]*AgentUsage
+
+	dataPath string
+}
+
+type trackerStore struct {
+	Agents map[string]*AgentUsage `json:"agents"`
+}
+
+// NewTracker creates a tracker persisting per-agent usage under dataPath.
+func NewTracker(cfg Config, dataPath string) *Tracker {
+	return &Tracker{
+		cfg:      cfg,
+		agents:   make(map[string]*AgentUsage),
+		dataPath: dataPath,
+	}
+}
+
+// Record adds usage for one agent turn taken at sim time at.
+func (t *Tracker) Record(agentID string, at time.Time, prompt, candidates, total int) {
+	if t == nil {
+		return
+	}
+	if total <= 0 {
+		total = prompt + candidates
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.run.add(prompt, candidates, total)
+	au, ok := t.agents[agentID]
+	if !ok {
+		au = &AgentUsage{Daily: make(map[string]int)}
+		t.agents[agentID] = au
+	}
+	if au.Daily == nil {
+		au.Daily = make(map[string]int)
+	}
+	au.add(prompt, candidates, total)
+	au.Daily[dayKey(at)] += total
+}
+
+// AgentExhausted reports whether agentID has used its daily quota for the sim day of at.
+func (t *Tracker) AgentExhausted(agentID string, at time.Time) bool {
+	if t == nil || t.cfg.MaxAgentDailyTokens <= 0 {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	au, ok := t.agents[agentID]
+	if !ok {
+		return false
+	}
+	return au.Daily[dayKey(at)] >= t.cfg.MaxAgentDailyTokens
+}
+
+// RunExhausted reports whether the run-wide quota has been used up.
+func (t *Tracker) RunExhausted() bool {
+	if t == nil || t.cfg.MaxRunTokens <= 0 {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.run.TotalTokens >= t.cfg.MaxRunTokens
+}
+
+// RunUsage returns the usage accumulated by this process.
+func (t *Tracker) RunUsage() Usage {
+	if t == nil {
+		return Usage{}
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.run
+}
+
+// AgentUsage returns a copy of the cumulative usage for agentID.
+func (t *Tracker) AgentUsage(agentID string) AgentUsage {
+	if t == nil {
+		return AgentUsage{}
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	au, ok := t.agents[agentID]
+	if !ok {
+		return AgentUsage{}
+	}
+	out := AgentUsage{Usage: au.Usage, Daily: make(map[string]int, len(au.Daily))}
+	for k, v := range au.Daily {
+		out.Daily[k] = v
+	}
+	return out
+}
+
+// Load reads persisted per-agent usage. Run totals always start at zero.
+func (t *Tracker) Load() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(t.dataPath, "budget.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var store trackerStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return err
+	}
+	if store.Agents != nil {
+		t.agents = store.Agents
+	}
+	return nil
+}
+
+// Save persists per-agent usage to disk.
+func (t *Tracker) Save() error {
+	if t == nil || t.dataPath == "" {
+		return nil
+	}
+	t.mu.RLock()
+	data, err := json.MarshalIndent(trackerStore{Agents: t.agents}, "", "  ")
+	t.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dataPath, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(t.dataPath, "budget.json"), data, 0644)
+}
+
+func dayKey(at time.Time) string {
+	return at.Format("2006-01-02")
+}
@@ -0,0 +1,83 @@
+package budget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker_AgentDailyQuota(t *testing.T) {
+	tr := NewTracker(Config{MaxAgentDailyTokens: 100}, t.TempDir())
+	day1 := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	tr.Record("agent-1", day1, 40, 20, 0)
+	if tr.AgentExhausted("agent-1", day1) {
+		t.Fatalf("agent should not be exhausted after 60 tokens")
+	}
+	tr.Record("agent-1", day1, 30, 10, 40)
+	if !tr.AgentExhausted("agent-1", day1) {
+		t.Fatalf("agent should be exhausted after 100 tokens")
+	}
+	if tr.AgentExhausted("agent-1", day2) {
+		t.Fatalf("quota should reset on the next sim day")
+	}
+	if tr.AgentExhausted("agent-2", day1) {
+		t.Fatalf("other agents should not be affected")
+	}
+}
+
+func TestTracker_RunQuotaAndPersistence(t *testing.T) {
+	dir := t.TempDir()
+	tr := NewTracker(Config{MaxRunTokens: 50}, dir)
+	at := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tr.Record("agent-1", at, 20, 10, 30)
+	if tr.RunExhausted() {
+		t.Fatalf("run should not be exhausted at 30 tokens")
+	}
+	tr.Record("agent-2", at, 20, 10, 30)
+	if !tr.RunExhausted() {
+		t.Fatalf("run should be exhausted at 60 tokens")
+	}
+	if err := tr.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	tr2 := NewTracker(Config{MaxRunTokens: 50}, dir)
+	if err := tr2.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := tr2.AgentUsage("agent-1").TotalTokens; got != 30 {
+		t.Fatalf("agent-1 total=%d, want 30", got)
+	}
+	if tr2.RunExhausted() {
+		t.Fatalf("run totals should not carry over between processes")
+	}
+}
+
+func TestPriceTable_LookupAndCost(t *testing.T) {
+	table := PriceTable{
+		"*":                {Input: 1, Output: 1},
+		"gemini-*":         {Input: 0.5, Output: 2},
+		"gemini-2.5-pro*":  {Input: 1.25, Output: 10, Cached: 0.3},
+		"gemini-2.5-flash": {Input: 0.3, Output: 2.5},
+	}
+	for model, want := range map[string]float64{
+		"gemini-2.5-flash":      0.3,
+		"gemini-2.5-pro-latest": 1.25,
+		"gemini-1.5":            0.5,
+		"gpt-4o":                1,
+	} {
+		if p, ok := table.Lookup(model); !ok || p.Input != want {
+			t.Fatalf("Lookup(%q) = %+v, %v; want input %v", model, p, ok, want)
+		}
+	}
+
+	p, _ := table.Lookup("gemini-2.5-pro")
+	// 1M prompt tokens of which 400k cached, 100k output and 100k thoughts.
+	got := p.Cost(1_000_000, 400_000, 0, 100_000, 100_000)
+	want := 0.6*1.25 + 0.4*0.3 + 0.2*10
+	if got < want-1e-9 || got > want+1e-9 {
+		t.Fatalf("Cost = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package budget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Price is a model's rate in USD per million tokens.
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+	// Cached is the rate for cached prompt tokens (0 bills them as Input).
+	Cached float64 `json:"cached,omitempty"`
+}
+
+// PriceTable maps model names to prices. A key ending in "*" matches any
+// model name with that prefix, and "*" alone matches every model; the
+// longest match wins.
+type PriceTable map[string]Price
+
+// LoadPrices reads a price table from a JSON file.
+func LoadPrices(path string) (PriceTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var table PriceTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for name, p := range table {
+		if p.Input < 0 || p.Output < 0 || p.Cached < 0 {
+			return nil, fmt.Errorf("%s: negative price for %q", path, name)
+		}
+	}
+	return table, nil
+}
+
+// Lookup returns the price for a model name.
+func (t PriceTable) Lookup(model string) (Price, bool) {
+	if p, ok := t[model]; ok {
+		return p, true
+	}
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		if strings.HasSuffix(k, "*") && strings.HasPrefix(model, strings.TrimSuffix(k, "*")) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return Price{}, false
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	return t[keys[0]], true
+}
+
+// Cost prices one call's (or a sum of calls') token counts. The prompt
+// count includes cached tokens; tool-use prompt tokens are billed as
+// input and thoughts as output.
+func (p Price) Cost(prompt, cached, toolUsePrompt, candidates, thoughts int) float64 {
+	cachedRate := p.Cached
+	if cachedRate == 0 {
+		cachedRate = p.Input
+	}
+	uncached := prompt - cached
+	if uncached < 0 {
+		uncached = 0
+	}
+	input := float64(uncached+toolUsePrompt)*p.Input + float64(cached)*cachedRate
+	output := float64(candidates+thoughts) * p.Output
+	return (input + output) / 1e6
+}
@@ -0,0 +1,724 @@
+// Package client is a typed Go client for the sci-bot server API
+// (cmd/server). Response types are shared with the server, so they stay in
+// sync with what it serves.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Config configures a Client.
+type Config struct {
+	// BaseURL of the server, e.g. "http://localhost:8061".
+	BaseURL string
+	// HTTPClient defaults to a client with a 30s timeout.
+	HTTPClient *http.Client
+	// MaxRetries is how often a request is retried after a network error,
+	// 429 or 5xx response (default 2; negative disables retries).
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// further attempt (default 200ms).
+	RetryBackoff time.Duration
+	// Token authenticates write calls (CreatePost, Comment, Vote) as a human
+	// account from the server's -humans file.
+	Token string
+	// APIKey is sent with every request for servers started with -api-key.
+	APIKey string
+}
+
+// Client calls the sci-bot server API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	http       *http.Client
+	maxRetries int
+	backoff    time.Duration
+	token      string
+	apiKey     string
+}
+
+// APIError is a non-2xx response from the server.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("sci-bot api: %d %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from the server.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// New creates a client.
+func New(cfg Config) (*Client, error) {
+	base, err := url.Parse(strings.TrimRight(cfg.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: %q", cfg.BaseURL)
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	maxRetries := cfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 2
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	backoff := cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = 200 * time.Millisecond
+	}
+	return &Client{
+		baseURL:    base,
+		http:       httpClient,
+		maxRetries: maxRetries,
+		backoff:    backoff,
+		token:      cfg.Token,
+		apiKey:     cfg.APIKey,
+	}, nil
+}
+
+// Agents lists all agents.
+func (c *Client) Agents(ctx context.Context) ([]AgentInfo, error) {
+	var out struct {
+		Agents []AgentInfo `json:"agents"`
+	}
+	if err := c.get(ctx, "/api/agents", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Agents, nil
+}
+
+// Agent returns an agent's profile, publications and recent daily notes.
+func (c *Client) Agent(ctx context.Context, id string) (*AgentDetail, error) {
+	var out AgentDetail
+	if err := c.get(ctx, "/api/agents/"+url.PathEscape(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DailyQuery selects an agent's daily log entries by note date
+// (YYYY-MM-DD, inclusive; empty is open).
+type DailyQuery struct {
+	From string
+	To   string
+	Page
+}
+
+// Daily returns an agent's daily log entries, oldest first.
+func (c *Client) Daily(ctx context.Context, id string, q DailyQuery) (*DailyResponse, error) {
+	values := url.Values{}
+	if q.From != "" {
+		values.Set("from", q.From)
+	}
+	if q.To != "" {
+		values.Set("to", q.To)
+	}
+	var out DailyResponse
+	if err := c.get(ctx, "/api/agents/"+url.PathEscape(id)+"/daily", q.Page.values(values), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DailyEntries iterates over an agent's daily log entries, oldest first.
+func (c *Client) DailyEntries(ctx context.Context, id string, q DailyQuery) iter.Seq2[DailyRecord, error] {
+	return paginate(q.Page, 100, func(p Page) ([]DailyRecord, error) {
+		q.Page = p
+		resp, err := c.Daily(ctx, id, q)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Entries, nil
+	})
+}
+
+// Stats returns headline community statistics.
+func (c *Client) Stats(ctx context.Context) (*Stats, error) {
+	var out Stats
+	if err := c.get(ctx, "/api/stats", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Page selects a window of a paginated endpoint. Zero Limit uses the
+// server default.
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+func (p Page) values(q url.Values) url.Values {
+	if q == nil {
+		q = url.Values{}
+	}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return q
+}
+
+// ForumQuery filters forum threads.
+type ForumQuery struct {
+	Subreddit string
+	Channel   types.ChannelType      // forum | preprint; empty lists both
+	Sort      publication.PostSort   // hot (default) | new | top | controversial
+	Window    publication.TimeWindow // day | week | all (default); for top and controversial
+	Page
+}
+
+// Forum lists forum threads.
+func (c *Client) Forum(ctx context.Context, q ForumQuery) (*ForumResponse, error) {
+	values := url.Values{}
+	if q.Subreddit != "" {
+		values.Set("subreddit", q.Subreddit)
+	}
+	if q.Channel != "" {
+		values.Set("channel", string(q.Channel))
+	}
+	if q.Sort != "" {
+		values.Set("sort", string(q.Sort))
+	}
+	if q.Window != "" {
+		values.Set("window", string(q.Window))
+	}
+	var out ForumResponse
+	if err := c.get(ctx, "/api/forum", q.Page.values(values), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ForumPosts iterates over all threads matching q, fetching q.Limit per page.
+func (c *Client) ForumPosts(ctx context.Context, q ForumQuery) iter.Seq2[*types.Publication, error] {
+	return paginate(q.Page, 30, func(p Page) ([]*types.Publication, error) {
+		q.Page = p
+		resp, err := c.Forum(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Posts, nil
+	})
+}
+
+// ForumPost returns a thread with its comments in tree order, siblings
+// sorted by order (the server defaults to top when empty).
+func (c *Client) ForumPost(ctx context.Context, id string, order publication.CommentSort) (*ForumPostResponse, error) {
+	var values url.Values
+	if order != "" {
+		values = url.Values{"sort": {string(order)}}
+	}
+	var out ForumPostResponse
+	if err := c.get(ctx, "/api/forum/posts/"+url.PathEscape(id), values, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePost starts a thread as the human account behind Config.Token.
+func (c *Client) CreatePost(ctx context.Context, req CreatePostRequest) (*types.Publication, error) {
+	var out WriteResponse
+	if err := c.post(ctx, "/api/forum/posts", req, &out); err != nil {
+		return nil, err
+	}
+	return out.Publication, nil
+}
+
+// Comment replies to a post or comment as the human account behind
+// Config.Token.
+func (c *Client) Comment(ctx context.Context, req CreateCommentRequest) (*types.Publication, error) {
+	var out WriteResponse
+	if err := c.post(ctx, "/api/forum/comments", req, &out); err != nil {
+		return nil, err
+	}
+	return out.Publication, nil
+}
+
+// Vote votes on a post or comment as the human account behind Config.Token
+// and returns it with updated counts.
+func (c *Client) Vote(ctx context.Context, req VoteRequest) (*types.Publication, error) {
+	var out WriteResponse
+	if err := c.post(ctx, "/api/votes", req, &out); err != nil {
+		return nil, err
+	}
+	return out.Publication, nil
+}
+
+// Inbox lists spectator questions with the given status: approved (the
+// default) or posted for anyone, pending or rejected for human accounts.
+func (c *Client) Inbox(ctx context.Context, status inbox.Status) (*InboxResponse, error) {
+	values := url.Values{}
+	if status != "" {
+		values.Set("status", string(status))
+	}
+	var out InboxResponse
+	if err := c.get(ctx, "/api/inbox", values, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Question returns one spectator question and its permalink.
+func (c *Client) Question(ctx context.Context, id string) (*QuestionResponse, error) {
+	var out QuestionResponse
+	if err := c.get(ctx, "/api/inbox/"+url.PathEscape(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Ask queues a spectator question for moderation.
+func (c *Client) Ask(ctx context.Context, req AskRequest) (*QuestionResponse, error) {
+	var out QuestionResponse
+	if err := c.post(ctx, "/api/inbox", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VoteQuestion votes for an approved spectator question.
+func (c *Client) VoteQuestion(ctx context.Context, id string, req InboxVoteRequest) (*QuestionResponse, error) {
+	var out QuestionResponse
+	if err := c.post(ctx, "/api/inbox/"+url.PathEscape(id)+"/vote", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ModerateQuestion approves or rejects a pending spectator question as the
+// human account behind Config.Token.
+func (c *Client) ModerateQuestion(ctx context.Context, id string, req ModerateRequest) (*QuestionResponse, error) {
+	var out QuestionResponse
+	if err := c.post(ctx, "/api/inbox/"+url.PathEscape(id)+"/moderate", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Moderation returns reports (only open ones when openOnly) and moderation
+// actions, newest first.
+func (c *Client) Moderation(ctx context.Context, openOnly bool, limit int) (*ModerationResponse, error) {
+	values := Page{Limit: limit}.values(nil)
+	if openOnly {
+		values.Set("status", "open")
+	}
+	var out ModerationResponse
+	if err := c.get(ctx, "/api/moderation", values, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Civility returns comment civility statistics.
+func (c *Client) Civility(ctx context.Context) (*publication.CivilityReport, error) {
+	var out publication.CivilityReport
+	if err := c.get(ctx, "/api/civility", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// JournalQuery selects journal papers.
+type JournalQuery struct {
+	// Journal filters by journal ID; empty includes every journal.
+	Journal string
+	Page
+}
+
+// Journal returns the configured journals, a page of published papers
+// (newest first) and all pending submissions.
+func (c *Client) Journal(ctx context.Context, q JournalQuery) (*JournalResponse, error) {
+	values := url.Values{}
+	if q.Journal != "" {
+		values.Set("journal", q.Journal)
+	}
+	var out JournalResponse
+	if err := c.get(ctx, "/api/journal", q.Page.values(values), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Papers iterates over all published papers matching q, newest first.
+func (c *Client) Papers(ctx context.Context, q JournalQuery) iter.Seq2[*types.Publication, error] {
+	return paginate(q.Page, 50, func(p Page) ([]*types.Publication, error) {
+		q.Page = p
+		resp, err := c.Journal(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Approved, nil
+	})
+}
+
+// Paper returns a journal paper by ID.
+func (c *Client) Paper(ctx context.Context, id string) (*PaperDetailResponse, error) {
+	var out PaperDetailResponse
+	if err := c.get(ctx, "/api/journal/papers/"+url.PathEscape(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Rejected returns rejected submissions with their reviews. The server only
+// serves this when started with -show-rejected.
+func (c *Client) Rejected(ctx context.Context, limit int) (*RejectedResponse, error) {
+	var out RejectedResponse
+	if err := c.get(ctx, "/api/journal/rejected", Page{Limit: limit}.values(nil), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FeedQuery selects simulation log events.
+type FeedQuery struct {
+	// Log names a log file; empty merges all logs.
+	Log string
+	// Heartbeats includes liveness heartbeat events.
+	Heartbeats bool
+	// Agent and Actions keep only events of this agent and these actions.
+	Agent   string
+	Actions []string
+	// Since and Until bound the events' sim time (Until exclusive).
+	Since time.Time
+	Until time.Time
+	// Before and After are cursors from a FeedResponse (Next and Prev); the
+	// server accepts them only when it reads the sharded feed store.
+	Before string
+	After  string
+	Page
+}
+
+// Feed returns log events, newest first. Scanning logs, the server reads at
+// most the last 2000 events per log, so offsets beyond that return nothing;
+// from the sharded feed store, page with the response's Next cursor instead.
+func (c *Client) Feed(ctx context.Context, q FeedQuery) (*FeedResponse, error) {
+	values := url.Values{}
+	if q.Log != "" {
+		values.Set("log", q.Log)
+	}
+	if q.Heartbeats {
+		values.Set("heartbeats", "1")
+	}
+	if q.Agent != "" {
+		values.Set("agent", q.Agent)
+	}
+	if len(q.Actions) > 0 {
+		values.Set("action", strings.Join(q.Actions, ","))
+	}
+	if !q.Since.IsZero() {
+		values.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		values.Set("until", q.Until.Format(time.RFC3339))
+	}
+	if q.Before != "" {
+		values.Set("before", q.Before)
+	}
+	if q.After != "" {
+		values.Set("after", q.After)
+	}
+	var out FeedResponse
+	if err := c.get(ctx, "/api/feed", q.Page.values(values), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FeedEvents iterates over log events, newest first.
+func (c *Client) FeedEvents(ctx context.Context, q FeedQuery) iter.Seq2[FeedEvent, error] {
+	return paginate(q.Page, 200, func(p Page) ([]FeedEvent, error) {
+		q.Page = p
+		resp, err := c.Feed(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Events, nil
+	})
+}
+
+// SearchQuery is a full-text search over forum posts and journal papers.
+type SearchQuery struct {
+	Query string
+	Scope string // all (default) | forum | journal
+	Page
+}
+
+// Search returns publications matching the query, best first.
+func (c *Client) Search(ctx context.Context, q SearchQuery) (*SearchResponse, error) {
+	values := url.Values{}
+	values.Set("q", q.Query)
+	if q.Scope != "" {
+		values.Set("scope", q.Scope)
+	}
+	var out SearchResponse
+	if err := c.get(ctx, "/api/search", q.Page.values(values), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AuditQuery filters the audit trail; empty fields match everything.
+type AuditQuery struct {
+	Actor  string
+	Store  string // forum | journal | workflow
+	Op     string
+	Target string
+	Since  time.Time // sim time
+	Until  time.Time
+	Page
+}
+
+// Audit returns mutating operations on the forum, journal and workflow,
+// newest first.
+func (c *Client) Audit(ctx context.Context, q AuditQuery) (*AuditResponse, error) {
+	values := url.Values{}
+	for key, v := range map[string]string{"actor": q.Actor, "store": q.Store, "op": q.Op, "target": q.Target} {
+		if v != "" {
+			values.Set(key, v)
+		}
+	}
+	if !q.Since.IsZero() {
+		values.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		values.Set("until", q.Until.Format(time.RFC3339))
+	}
+	var out AuditResponse
+	if err := c.get(ctx, "/api/audit", q.Page.values(values), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Snapshot returns the forum, journal, workflow and agent counts as of sim
+// time at.
+func (c *Client) Snapshot(ctx context.Context, at time.Time) (*SnapshotResponse, error) {
+	values := url.Values{"at": {at.Format(time.RFC3339)}}
+	var out SnapshotResponse
+	if err := c.get(ctx, "/api/snapshot", values, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Timeline returns the run's milestones, oldest first.
+func (c *Client) Timeline(ctx context.Context) (*TimelineResponse, error) {
+	var out TimelineResponse
+	if err := c.get(ctx, "/api/timeline", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Graph returns the agents' social network. Non-zero since/until keep only
+// relationships whose last interaction falls in [since, until).
+func (c *Client) Graph(ctx context.Context, since, until time.Time) (*GraphResponse, error) {
+	values := url.Values{}
+	if !since.IsZero() {
+		values.Set("since", since.Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		values.Set("until", until.Format(time.RFC3339))
+	}
+	var out GraphResponse
+	if err := c.get(ctx, "/api/graph", values, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Lineage returns the propagation trees of theories; a non-empty theoryID
+// returns only that theory's.
+func (c *Client) Lineage(ctx context.Context, theoryID string) (*LineageResponse, error) {
+	values := url.Values{}
+	if theoryID != "" {
+		values.Set("theory", theoryID)
+	}
+	var out LineageResponse
+	if err := c.get(ctx, "/api/lineage", values, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Experiments lists virtual experiments, newest first, optionally only one
+// agent's.
+func (c *Client) Experiments(ctx context.Context, agentID string, p Page) ([]experiment.Experiment, error) {
+	values := url.Values{}
+	if agentID != "" {
+		values.Set("agent", agentID)
+	}
+	var out []experiment.Experiment
+	if err := c.get(ctx, "/api/experiments", p.values(values), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Groups lists research groups, largest first, optionally only those an
+// agent belongs to. Channel messages are omitted; use Group for them.
+func (c *Client) Groups(ctx context.Context, agentID string, p Page) ([]group.Group, error) {
+	values := url.Values{}
+	if agentID != "" {
+		values.Set("agent", agentID)
+	}
+	var out []group.Group
+	if err := c.get(ctx, "/api/groups", p.values(values), &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Group returns one research group with its channel.
+func (c *Client) Group(ctx context.Context, id string) (*group.Group, error) {
+	var out group.Group
+	if err := c.get(ctx, "/api/groups/"+url.PathEscape(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// paginate walks pages from start until a short page; pageSize applies when
+// start.Limit is unset.
+func paginate[T any](start Page, pageSize int, fetch func(Page) ([]T, error)) iter.Seq2[T, error] {
+	if start.Limit <= 0 {
+		start.Limit = pageSize
+	}
+	return func(yield func(T, error) bool) {
+		p := start
+		for {
+			items, err := fetch(p)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < p.Limit {
+				return
+			}
+			p.Offset += len(items)
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	u := *c.baseURL
+	u.Path = strings.TrimRight(u.Path, "/") + path
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.backoff << (attempt - 1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		retry, err := c.do(ctx, http.MethodGet, u.String(), nil, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+// post sends body as JSON. Writes are not idempotent, so they are never
+// retried.
+func (c *Client) post(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	u := *c.baseURL
+	u.Path = strings.TrimRight(u.Path, "/") + path
+	_, err = c.do(ctx, http.MethodPost, u.String(), data, out)
+	return err
+}
+
+// do performs one request; the bool reports whether a failure is retryable.
+func (c *Client) do(ctx context.Context, method, rawURL string, body []byte, out any) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+		var payload struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+			apiErr.Message = payload.Error
+		}
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, apiErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("decode %s: %w", req.URL.Path, err)
+	}
+	return false, nil
+}
@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestClient_PaginatesAndRetries(t *testing.T) {
+	const total = 7
+	var failures atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/forum", func(w http.ResponseWriter, r *http.Request) {
+		// The first request fails transiently.
+		if failures.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if r.URL.Query().Get("subreddit") != "physics" {
+			t.Errorf("missing subreddit filter: %s", r.URL.RawQuery)
+		}
+		posts := make([]*types.Publication, 0)
+		for i := offset; i < total && i < offset+limit; i++ {
+			posts = append(posts, &types.Publication{ID: fmt.Sprintf("forum-%d", i)})
+		}
+		json.NewEncoder(w).Encode(ForumResponse{Name: "forum", Posts: posts})
+	})
+	mux.HandleFunc("/api/forum/posts/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "post not found"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+
+	ids := make([]string, 0)
+	for post, err := range c.ForumPosts(ctx, ForumQuery{Subreddit: "physics", Page: Page{Limit: 3}}) {
+		if err != nil {
+			t.Fatalf("iterate: %v", err)
+		}
+		ids = append(ids, post.ID)
+	}
+	if len(ids) != total || ids[0] != "forum-0" || ids[total-1] != fmt.Sprintf("forum-%d", total-1) {
+		t.Fatalf("unexpected posts: %v", ids)
+	}
+
+	_, err = c.ForumPost(ctx, "missing", "")
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if apiErr, ok := err.(*APIError); !ok || apiErr.Message != "post not found" {
+		t.Fatalf("expected server error message, got %v", err)
+	}
+}
+
+func TestClient_ContextCancelsRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, MaxRetries: 5, RetryBackoff: time.Hour})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Stats(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected 1 call before the backoff was cancelled, got %d", n)
+	}
+}
+
+func TestClient_WritesSendTokenWithoutRetry(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/forum/posts", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret-token" || r.Header.Get("X-API-Key") != "site-key" {
+			t.Errorf("unexpected request: %s auth=%q key=%q", r.Method, r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+		}
+		var req CreatePostRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Title != "Hello" {
+			t.Errorf("unexpected body: %+v (%v)", req, err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(WriteResponse{Publication: &types.Publication{ID: "forum-1", AuthorID: types.HumanAuthorPrefix + "alice"}})
+	})
+	mux.HandleFunc("/api/votes", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, RetryBackoff: time.Millisecond, Token: "secret-token", APIKey: "site-key"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	pub, err := c.CreatePost(ctx, CreatePostRequest{Title: "Hello", Content: "World"})
+	if err != nil || pub.ID != "forum-1" || !types.IsHumanAuthor(pub.AuthorID) {
+		t.Fatalf("create post: %+v, %v", pub, err)
+	}
+	if _, err := c.Vote(ctx, VoteRequest{PostID: "forum-1", Up: true}); err == nil {
+		t.Fatal("expected vote to fail")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected writes not to be retried, got %d calls", got)
+	}
+}
+
+// TestOpenAPI_DescribesClientCalls checks that every request the client
+// makes matches a documented route and parameter, and that the document's
+// schema references resolve.
+func TestOpenAPI_DescribesClientCalls(t *testing.T) {
+	type call struct{ method, path, query string }
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, call{r.Method, r.URL.Path, r.URL.RawQuery})
+		w.Write([]byte("null"))
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{BaseURL: srv.URL, Token: "t"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	since, until := time.Now().Add(-time.Hour), time.Now()
+	page := Page{Offset: 1, Limit: 2}
+	c.Agents(ctx)
+	c.Agent(ctx, "a")
+	c.Daily(ctx, "a", DailyQuery{From: "2026-01-01", To: "2026-01-02", Page: page})
+	c.Stats(ctx)
+	c.Forum(ctx, ForumQuery{Subreddit: "physics", Channel: types.ChannelPreprint, Sort: "new", Window: "day", Page: page})
+	c.ForumPost(ctx, "p", "new")
+	c.CreatePost(ctx, CreatePostRequest{})
+	c.Comment(ctx, CreateCommentRequest{})
+	c.Vote(ctx, VoteRequest{})
+	c.Inbox(ctx, inbox.Pending)
+	c.Question(ctx, "q")
+	c.Ask(ctx, AskRequest{})
+	c.VoteQuestion(ctx, "q", InboxVoteRequest{})
+	c.ModerateQuestion(ctx, "q", ModerateRequest{})
+	c.Moderation(ctx, true, 5)
+	c.Civility(ctx)
+	c.Journal(ctx, JournalQuery{Journal: "j", Page: page})
+	c.Paper(ctx, "p")
+	c.Rejected(ctx, 5)
+	c.Feed(ctx, FeedQuery{Log: "l", Heartbeats: true, Agent: "a", Actions: []string{"post"}, Since: since, Until: until, Before: "b", After: "c", Page: page})
+	c.Search(ctx, SearchQuery{Query: "q", Scope: "forum", Page: page})
+	c.Audit(ctx, AuditQuery{Actor: "a", Store: "forum", Op: "o", Target: "t", Since: since, Until: until, Page: page})
+	c.Snapshot(ctx, until)
+	c.Timeline(ctx)
+	c.Graph(ctx, since, until)
+	c.Lineage(ctx, "th")
+	c.Experiments(ctx, "a", page)
+	c.Groups(ctx, "a", page)
+	c.Group(ctx, "g")
+
+	match := func(method, path string) *Route {
+		for i, r := range Routes {
+			parts, want := strings.Split(path, "/"), strings.Split(r.Path, "/")
+			if r.Method != method || len(parts) != len(want) {
+				continue
+			}
+			ok := true
+			for j := range want {
+				if !strings.HasPrefix(want[j], "{") && want[j] != parts[j] {
+					ok = false
+				}
+			}
+			if ok {
+				return &Routes[i]
+			}
+		}
+		return nil
+	}
+	for _, cl := range calls {
+		r := match(cl.method, cl.path)
+		if r == nil {
+			t.Errorf("%s %s is not documented", cl.method, cl.path)
+			continue
+		}
+		q, _ := url.ParseQuery(cl.query)
+		for key := range q {
+			declared := false
+			for _, p := range r.Params {
+				declared = declared || (p.In == "query" && p.Name == key)
+			}
+			if !declared {
+				t.Errorf("%s %s: query parameter %q is not documented", cl.method, r.Path, key)
+			}
+		}
+	}
+
+	data, err := json.Marshal(OpenAPI(Routes))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var doc struct {
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(doc.Paths) == 0 || doc.Components.Schemas["types.Publication"] == nil {
+		t.Fatalf("expected paths and a types.Publication schema, got %d paths", len(doc.Paths))
+	}
+	for _, m := range regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllSubmatch(data, -1) {
+		if doc.Components.Schemas[string(m[1])] == nil {
+			t.Errorf("unresolved schema reference %s", m[1])
+		}
+	}
+}
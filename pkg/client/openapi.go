@@ -0,0 +1,320 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/publication"
+)
+
+// Route describes one server endpoint for the OpenAPI document. Request and
+// Response are values of the JSON body types; their schemas are derived
+// from the Go types, so the document cannot drift from what the server
+// encodes.
+type Route struct {
+	Method   string
+	Path     string // OpenAPI template, e.g. "/api/agents/{id}"
+	Summary  string
+	Params   []Param
+	Request  any // nil for no body
+	Response any
+	// Auth marks endpoints that need a bearer token (Config.Token).
+	Auth bool
+}
+
+// Param is a path or query parameter.
+type Param struct {
+	Name        string
+	In          string // path | query
+	Type        string // string | integer | boolean
+	Description string
+}
+
+func pathParam(name, desc string) Param {
+	return Param{Name: name, In: "path", Type: "string", Description: desc}
+}
+
+func query(name, desc string) Param {
+	return Param{Name: name, In: "query", Type: "string", Description: desc}
+}
+
+var (
+	offsetParam = Param{Name: "offset", In: "query", Type: "integer", Description: "Items to skip"}
+	limitParam  = Param{Name: "limit", In: "query", Type: "integer", Description: "Page size (server default when omitted)"}
+	sinceParam  = query("since", "RFC 3339 sim time, inclusive")
+	untilParam  = query("until", "RFC 3339 sim time, exclusive")
+)
+
+// Routes are the endpoints cmd/server always serves, plus
+// /api/journal/rejected (only with -show-rejected). The Client methods call
+// exactly these.
+var Routes = []Route{
+	{Method: http.MethodGet, Path: "/api/agents", Summary: "List agents",
+		Response: struct {
+			Agents []AgentInfo `json:"agents"`
+		}{}},
+	{Method: http.MethodGet, Path: "/api/agents/{id}", Summary: "Agent profile, publications and recent daily notes",
+		Params: []Param{pathParam("id", "Agent ID")}, Response: AgentDetail{}},
+	{Method: http.MethodGet, Path: "/api/agents/{id}/daily", Summary: "Agent daily log entries, oldest first",
+		Params:   []Param{pathParam("id", "Agent ID"), query("from", "First note date (YYYY-MM-DD)"), query("to", "Last note date (YYYY-MM-DD)"), offsetParam, limitParam},
+		Response: DailyResponse{}},
+	{Method: http.MethodGet, Path: "/api/stats", Summary: "Headline community statistics", Response: Stats{}},
+	{Method: http.MethodGet, Path: "/api/forum", Summary: "List forum threads",
+		Params:   []Param{query("subreddit", "Subreddit name"), query("channel", "forum | preprint (papers under journal review); empty lists both"), query("sort", "hot (default) | new | top | controversial"), query("window", "day | week | all (default)"), offsetParam, limitParam},
+		Response: ForumResponse{}},
+	{Method: http.MethodGet, Path: "/api/forum/posts/{id}", Summary: "Forum thread with its comment tree",
+		Params: []Param{pathParam("id", "Post ID"), query("sort", "Comment order (default top)")}, Response: ForumPostResponse{}},
+	{Method: http.MethodPost, Path: "/api/forum/posts", Summary: "Start a thread as a human account",
+		Request: CreatePostRequest{}, Response: WriteResponse{}, Auth: true},
+	{Method: http.MethodPost, Path: "/api/forum/comments", Summary: "Reply to a post or comment as a human account",
+		Request: CreateCommentRequest{}, Response: WriteResponse{}, Auth: true},
+	{Method: http.MethodPost, Path: "/api/votes", Summary: "Vote on a post or comment as a human account",
+		Request: VoteRequest{}, Response: WriteResponse{}, Auth: true},
+	{Method: http.MethodGet, Path: "/api/inbox", Summary: "Spectator questions (with -inbox)",
+		Params: []Param{query("status", "approved (default) | posted | pending | rejected; the last two need a human account")}, Response: InboxResponse{}},
+	{Method: http.MethodPost, Path: "/api/inbox", Summary: "Ask the community a question as a spectator",
+		Request: AskRequest{}, Response: QuestionResponse{}},
+	{Method: http.MethodGet, Path: "/api/inbox/{id}", Summary: "One spectator question and its forum thread once posted",
+		Params: []Param{pathParam("id", "Question ID")}, Response: QuestionResponse{}},
+	{Method: http.MethodPost, Path: "/api/inbox/{id}/vote", Summary: "Vote for an approved spectator question",
+		Params: []Param{pathParam("id", "Question ID")}, Request: InboxVoteRequest{}, Response: QuestionResponse{}},
+	{Method: http.MethodPost, Path: "/api/inbox/{id}/moderate", Summary: "Approve or reject a pending spectator question as a human account",
+		Params: []Param{pathParam("id", "Question ID")}, Request: ModerateRequest{}, Response: QuestionResponse{}, Auth: true},
+	{Method: http.MethodGet, Path: "/api/moderation", Summary: "Reports and moderation actions, newest first",
+		Params: []Param{query("status", "open to list only open reports"), limitParam}, Response: ModerationResponse{}},
+	{Method: http.MethodGet, Path: "/api/civility", Summary: "Comment civility statistics", Response: publication.CivilityReport{}},
+	{Method: http.MethodGet, Path: "/api/journal", Summary: "Journals, published papers (newest first) and pending submissions",
+		Params: []Param{query("journal", "Journal ID"), offsetParam, limitParam}, Response: JournalResponse{}},
+	{Method: http.MethodGet, Path: "/api/journal/papers/{id}", Summary: "Journal paper with its review record",
+		Params: []Param{pathParam("id", "Paper ID")}, Response: PaperDetailResponse{}},
+	{Method: http.MethodGet, Path: "/api/journal/rejected", Summary: "Rejected submissions with their reviews",
+		Params: []Param{limitParam}, Response: RejectedResponse{}},
+	{Method: http.MethodGet, Path: "/api/feed", Summary: "Simulation log events, newest first",
+		Params: []Param{query("log", "Log file; empty merges all logs"), query("heartbeats", "1 to include heartbeat events"),
+			query("agent", "Agent ID"), query("action", "Comma-separated actions"), sinceParam, untilParam,
+			query("before", "Cursor (FeedResponse.next)"), query("after", "Cursor (FeedResponse.prev)"), offsetParam, limitParam},
+		Response: FeedResponse{}},
+	{Method: http.MethodGet, Path: "/api/search", Summary: "Full-text search over forum posts and journal papers",
+		Params:   []Param{query("q", "Query"), query("scope", "all (default) | forum | journal"), offsetParam, limitParam},
+		Response: SearchResponse{}},
+	{Method: http.MethodGet, Path: "/api/audit", Summary: "Mutations of the forum, journal and workflow, newest first",
+		Params: []Param{query("actor", "Actor ID"), query("store", "forum | journal | workflow"), query("op", "Operation"),
+			query("target", "Target ID"), sinceParam, untilParam, offsetParam, limitParam},
+		Response: AuditResponse{}},
+	{Method: http.MethodGet, Path: "/api/snapshot", Summary: "Forum, journal, workflow and agent counts as of a sim time",
+		Params: []Param{query("at", "RFC 3339 sim time or YYYY-MM-DD (required), inclusive")}, Response: SnapshotResponse{}},
+	{Method: http.MethodGet, Path: "/api/timeline", Summary: "Milestones of the run, oldest first", Response: TimelineResponse{}},
+	{Method: http.MethodGet, Path: "/api/graph", Summary: "The agents' social network",
+		Params: []Param{sinceParam, untilParam}, Response: GraphResponse{}},
+	{Method: http.MethodGet, Path: "/api/lineage", Summary: "Propagation trees of theories",
+		Params: []Param{query("theory", "Theory ID")}, Response: LineageResponse{}},
+	{Method: http.MethodGet, Path: "/api/experiments", Summary: "Virtual experiments, newest first",
+		Params: []Param{query("agent", "Agent ID"), offsetParam, limitParam}, Response: []experiment.Experiment{}},
+	{Method: http.MethodGet, Path: "/api/groups", Summary: "Research groups, largest first",
+		Params: []Param{query("agent", "Member agent ID"), offsetParam, limitParam}, Response: []group.Group{}},
+	{Method: http.MethodGet, Path: "/api/groups/{id}", Summary: "Research group with its channel",
+		Params: []Param{pathParam("id", "Group ID")}, Response: group.Group{}},
+}
+
+// OpenAPI returns an OpenAPI 3.0 document for routes. Named Go types become
+// shared component schemas, keyed by package and type name.
+func OpenAPI(routes []Route) map[string]any {
+	g := &schemaGen{components: make(map[string]any)}
+	paths := make(map[string]any)
+	for _, r := range routes {
+		op := map[string]any{
+			"summary":     r.Summary,
+			"operationId": operationID(r),
+			"responses": map[string]any{
+				"200": jsonContent("OK", g.schema(reflect.TypeOf(r.Response))),
+				"default": jsonContent("Error", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"error": map[string]any{"type": "string"}},
+				}),
+			},
+		}
+		if len(r.Params) > 0 {
+			params := make([]any, 0, len(r.Params))
+			for _, p := range r.Params {
+				params = append(params, map[string]any{
+					"name":        p.Name,
+					"in":          p.In,
+					"required":    p.In == "path",
+					"description": p.Description,
+					"schema":      map[string]any{"type": p.Type},
+				})
+			}
+			op["parameters"] = params
+		}
+		if r.Request != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(r.Request))}},
+			}
+		}
+		if r.Auth {
+			op["security"] = []any{map[string]any{"bearer": []any{}}}
+		}
+		item, _ := paths[r.Path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[r.Path] = item
+		}
+		item[strings.ToLower(r.Method)] = op
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": "sci-bot server API", "version": "1"},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas":         g.components,
+			"securitySchemes": map[string]any{"bearer": map[string]any{"type": "http", "scheme": "bearer"}},
+		},
+	}
+}
+
+// operationID names an operation after its method and path, e.g.
+// "getApiAgentsId".
+func operationID(r Route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(r.Method))
+	for _, part := range strings.FieldsFunc(r.Path, func(c rune) bool { return c == '/' || c == '{' || c == '}' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func jsonContent(desc string, schema any) map[string]any {
+	return map[string]any{
+		"description": desc,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	rawJSONType   = reflect.TypeOf(json.RawMessage(nil))
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaGen derives JSON schemas from Go types the way encoding/json
+// encodes them.
+type schemaGen struct {
+	components map[string]any
+}
+
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]any{"type": "integer", "description": "nanoseconds"}
+	case rawJSONType:
+		return map[string]any{}
+	}
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		// Custom encodings: nothing to say about the shape.
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		name := componentName(t)
+		if name == "" {
+			return g.object(t)
+		}
+		ref := map[string]any{"$ref": "#/components/schemas/" + name}
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = nil // placeholder for recursive types
+			g.components[name] = g.object(t)
+		}
+		return ref
+	default:
+		return map[string]any{}
+	}
+}
+
+// object builds the schema of a struct's JSON fields, promoting the fields
+// of untagged embedded structs as encoding/json does.
+func (g *schemaGen) object(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			ft := f.Type
+			if f.Anonymous && name == "" {
+				for ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if _, ok := props[name]; ok {
+				continue // shallower fields win
+			}
+			props[name] = g.schema(ft)
+			if !strings.Contains(opts, "omitempty") && ft.Kind() != reflect.Pointer {
+				required = append(required, name)
+			}
+		}
+	}
+	walk(t)
+	out := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	return out
+}
+
+// componentName keys a named struct type by package and name, e.g.
+// "types.Publication". Anonymous and generic types are inlined.
+func componentName(t reflect.Type) string {
+	if t.Name() == "" || strings.ContainsAny(t.Name(), "[]") {
+		return ""
+	}
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
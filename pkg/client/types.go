@@ -0,0 +1,454 @@
+package client
+
+import (
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/lineage"
+	"github.com/cpunion/sci-bot/pkg/memory"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// AgentInfo is an agent profile as listed by /api/agents.
+type AgentInfo struct {
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	Role                string   `json:"role"`
+	ThinkingStyle       string   `json:"thinking_style"`
+	Domains             []string `json:"domains"`
+	Creativity          float64  `json:"creativity"`
+	Rigor               float64  `json:"rigor"`
+	RiskTolerance       float64  `json:"risk_tolerance"`
+	Sociability         float64  `json:"sociability"`
+	Influence           float64  `json:"influence"`
+	ResearchOrientation string   `json:"research_orientation"`
+
+	Reputation *types.Reputation `json:"reputation,omitempty"`
+}
+
+// DailyNote is one day of an agent's daily log.
+type DailyNote struct {
+	Date    string       `json:"date"`
+	Content string       `json:"content"`
+	Entries []DailyEntry `json:"entries,omitempty"`
+}
+
+// DailyEntry is one turn recorded in a daily note.
+type DailyEntry struct {
+	Timestamp string `json:"timestamp"`
+	Prompt    string `json:"prompt,omitempty"`
+	Reply     string `json:"reply,omitempty"`
+	// Error is what failed in the turn, ErrorKind its class (see
+	// FeedEvent.ErrorKind).
+	Error     string `json:"error,omitempty"`
+	ErrorKind string `json:"error_kind,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+	Raw       string `json:"raw,omitempty"`
+}
+
+// DailyRecord is a daily note entry with the date of its note.
+type DailyRecord struct {
+	Date string `json:"date"`
+	DailyEntry
+}
+
+// DailyResponse is returned by /api/agents/{id}/daily, oldest entries first.
+type DailyResponse struct {
+	AgentID string        `json:"agent_id"`
+	Total   int           `json:"total"` // matching entries before pagination
+	Entries []DailyRecord `json:"entries"`
+}
+
+// AgentDetail is returned by /api/agents/{id}.
+type AgentDetail struct {
+	Agent           AgentInfo            `json:"agent"`
+	ForumPosts      []*types.Publication `json:"forum_posts"`
+	ForumComments   []*types.Publication `json:"forum_comments"`
+	JournalApproved []*types.Publication `json:"journal_approved"`
+	JournalPending  []*types.Publication `json:"journal_pending"`
+	DailyNotes      []DailyNote          `json:"daily_notes"`
+	Karma           *types.Karma         `json:"karma,omitempty"`
+	// Traits is how the agent's traits drifted with its outcomes (nil until
+	// the simulation has tracked them).
+	Traits *types.TraitRecord `json:"traits,omitempty"`
+	// Groups are the research groups the agent belongs to, without their
+	// channel messages.
+	Groups []group.Group `json:"groups,omitempty"`
+	// ResearchNotes are the agent's latest record_note notes, newest first.
+	ResearchNotes []memory.ResearchNote `json:"research_notes,omitempty"`
+	// Agenda is the agent's research agenda: its goals and projects (nil
+	// until it sets one).
+	Agenda *types.Agenda `json:"agenda,omitempty"`
+}
+
+// ForumResponse is returned by /api/forum.
+type ForumResponse struct {
+	Name           string                 `json:"name"`
+	Posts          []*types.Publication   `json:"posts"`
+	SubredditStats map[string]int         `json:"subreddit_stats"`
+	Subreddits     []*types.SubredditInfo `json:"subreddits,omitempty"`
+}
+
+// ForumPostResponse is returned by /api/forum/posts/{id}. Comments are in
+// tree order: each comment is followed by its replies, siblings ordered by
+// Sort.
+type ForumPostResponse struct {
+	Post     *types.Publication          `json:"post"`
+	Sort     publication.CommentSort     `json:"sort"`
+	Comments []publication.ThreadComment `json:"comments"`
+	// Consensus lists the consensus requests about the post with their
+	// status histories.
+	Consensus []*types.ConsensusRequest `json:"consensus,omitempty"`
+}
+
+// CreatePostRequest is the body of POST /api/forum/posts.
+type CreatePostRequest struct {
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Abstract  string `json:"abstract,omitempty"`
+	Subreddit string `json:"subreddit,omitempty"` // default general
+}
+
+// CreateCommentRequest is the body of POST /api/forum/comments.
+type CreateCommentRequest struct {
+	ParentID string `json:"parent_id"` // post or comment being replied to
+	Content  string `json:"content"`
+}
+
+// VoteRequest is the body of POST /api/votes. Repeating a vote withdraws
+// it; voting the other way switches it.
+type VoteRequest struct {
+	PostID string `json:"post_id"`
+	Up     bool   `json:"up"`
+}
+
+// WriteResponse is returned by the write endpoints: the post or comment as
+// stored after the change.
+type WriteResponse struct {
+	Publication *types.Publication `json:"publication"`
+}
+
+// InboxResponse is returned by GET /api/inbox.
+type InboxResponse struct {
+	Status    inbox.Status     `json:"status"`
+	Questions []inbox.Question `json:"questions"` // approved: most votes first; others newest first
+}
+
+// AskRequest is the body of POST /api/inbox. The server ties a spectator's
+// questions and votes together by the address they connect from, so no
+// account is needed.
+type AskRequest struct {
+	Title     string `json:"title"`
+	Question  string `json:"question"`
+	Subreddit string `json:"subreddit,omitempty"` // default general
+	Name      string `json:"name,omitempty"`      // shown with the posted question
+}
+
+// InboxVoteRequest is the body of POST /api/inbox/{id}/vote. Each address
+// counts once per question.
+type InboxVoteRequest struct{}
+
+// ModerateRequest is the body of POST /api/inbox/{id}/moderate.
+type ModerateRequest struct {
+	Approve bool   `json:"approve"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// QuestionResponse is returned by the /api/inbox/{id} endpoints. Permalink
+// is the page where the asker can follow the question into the forum.
+type QuestionResponse struct {
+	Question  inbox.Question `json:"question"`
+	Permalink string         `json:"permalink"`
+}
+
+// ModerationResponse is returned by /api/moderation.
+type ModerationResponse struct {
+	OpenReports int                       `json:"open_reports"`
+	Reports     []*types.PostReport       `json:"reports"` // newest first
+	Actions     []*types.ModerationAction `json:"actions"` // newest first
+	// Duplicates are posts the duplicate filter flagged, newest first; see
+	// Publication.DuplicateOf.
+	Duplicates []*types.Publication `json:"duplicates,omitempty"`
+}
+
+// JournalResponse is returned by /api/journal.
+type JournalResponse struct {
+	Name string `json:"name"`
+	// Journal is the journal ID the papers are filtered by, if any.
+	Journal  string                       `json:"journal,omitempty"`
+	Journals []publication.JournalSummary `json:"journals"`
+	Approved []*types.Publication         `json:"approved"`
+	Pending  []*types.Publication         `json:"pending"`
+	// Retracted lists retraction notices, most recent first. Retracted
+	// papers also stay in Approved, flagged.
+	Retracted []*types.Publication `json:"retracted,omitempty"`
+}
+
+// RejectedPaper is a rejected submission with its reviews.
+type RejectedPaper struct {
+	Paper   *types.Publication   `json:"paper"`
+	Reviews []*types.PaperReview `json:"reviews,omitempty"`
+}
+
+// RejectedResponse is returned by /api/journal/rejected.
+type RejectedResponse struct {
+	JournalName    string          `json:"journal_name"`
+	AcceptanceRate float64         `json:"acceptance_rate"` // accepted / (accepted + rejected)
+	Papers         []RejectedPaper `json:"papers"`
+}
+
+// PaperDetailResponse is returned by /api/journal/papers/{id}.
+type PaperDetailResponse struct {
+	JournalName string             `json:"journal_name"`
+	Status      string             `json:"status"` // published | pending | rejected
+	Paper       *types.Publication `json:"paper"`
+	// Disputes are the formal challenges raised against the paper, oldest
+	// first.
+	Disputes []*types.Dispute `json:"disputes,omitempty"`
+	// Retraction holds the votes to retract the paper, if any.
+	Retraction *types.RetractionRequest `json:"retraction,omitempty"`
+	// Submission is the paper's workflow record: status, assigned reviewers
+	// and the editor's decision.
+	Submission *types.Submission `json:"submission,omitempty"`
+	// Reviews are the peer reviews of the submission, with scores and
+	// verdicts.
+	Reviews []*types.PaperReview `json:"reviews,omitempty"`
+	// Preprint is the forum post the paper was preprinted as, hidden while
+	// a double-blind review is undecided.
+	Preprint *types.Publication `json:"preprint,omitempty"`
+	// Draft is the draft the paper was written from, and SourceThread the
+	// forum post that draft started from. Both are omitted while a
+	// double-blind submission awaits its decision.
+	Draft        *types.Draft       `json:"draft,omitempty"`
+	SourceThread *types.Publication `json:"source_thread,omitempty"`
+}
+
+// FeedEvent is one simulation log event as served by /api/feed.
+type FeedEvent struct {
+	Timestamp      time.Time `json:"timestamp"`
+	SimTime        time.Time `json:"sim_time"`
+	Tick           int       `json:"tick"`
+	AgentID        string    `json:"agent_id"`
+	AgentName      string    `json:"agent_name"`
+	ModelName      string    `json:"model_name,omitempty"`
+	Action         string    `json:"action"`
+	Prompt         string    `json:"prompt"`
+	Response       string    `json:"response"`
+	ToolCalls      []string  `json:"tool_calls,omitempty"`
+	ToolResponses  []string  `json:"tool_responses,omitempty"`
+	TurnCount      int       `json:"turn_count"`
+	BellRung       bool      `json:"bell_rung"`
+	GraceRemaining int       `json:"grace_remaining"`
+	Sleeping       bool      `json:"sleeping"`
+	Liveness       string    `json:"liveness,omitempty"`
+	IdleTicks      int       `json:"idle_ticks,omitempty"`
+
+	// Failed marks a turn the model call failed; ErrorKind is rate_limited,
+	// server_error, timeout, circuit_open or error then, and tool_error for
+	// a turn that completed with ToolErrors failed tool calls. Error says
+	// what failed.
+	Error      string `json:"error,omitempty"`
+	Failed     bool   `json:"failed,omitempty"`
+	ErrorKind  string `json:"error_kind,omitempty"`
+	Attempts   int    `json:"attempts,omitempty"`
+	ToolErrors int    `json:"tool_errors,omitempty"`
+
+	Outcomes []ToolOutcome `json:"outcomes,omitempty"`
+
+	UsageEvents         int `json:"usage_events,omitempty"`
+	PromptTokens        int `json:"prompt_tokens,omitempty"`
+	CandidatesTokens    int `json:"candidates_tokens,omitempty"`
+	ThoughtsTokens      int `json:"thoughts_tokens,omitempty"`
+	ToolUsePromptTokens int `json:"tool_use_prompt_tokens,omitempty"`
+	CachedContentTokens int `json:"cached_content_tokens,omitempty"`
+	TotalTokens         int `json:"total_tokens,omitempty"`
+
+	// Cursor is the event's position in the sharded feed store, if served
+	// from it.
+	Cursor string `json:"cursor,omitempty"`
+
+	// Derived links for UI. Not part of the simulation log format.
+	ActorURL     string `json:"actor_url,omitempty"`
+	ContentKind  string `json:"content_kind,omitempty"`
+	ContentID    string `json:"content_id,omitempty"`
+	ContentTitle string `json:"content_title,omitempty"`
+	ContentURL   string `json:"content_url,omitempty"`
+}
+
+// ToolOutcome is one tool call recorded with a feed event: its key
+// arguments and the IDs in its result (post_id, comment_id, ...).
+type ToolOutcome struct {
+	Tool   string            `json:"tool"`
+	Args   map[string]string `json:"args,omitempty"`
+	Text   map[string]string `json:"text,omitempty"`
+	Result map[string]string `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// FeedResponse is returned by /api/feed.
+type FeedResponse struct {
+	Log    string      `json:"log"`
+	Events []FeedEvent `json:"events"`
+	// Next and Prev are set when the server reads the sharded feed store:
+	// pass Next as FeedQuery.Before for older events and Prev as
+	// FeedQuery.After to poll for newer ones.
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// Stats is returned by /api/stats. The activity fields are filled from the
+// simulation's activity index and are empty for data without one.
+type Stats struct {
+	ActiveAgents    int     `json:"active_agents"`
+	ForumThreads    int     `json:"forum_threads"`
+	ForumComments   int     `json:"forum_comments"`
+	JournalApproved int     `json:"journal_approved"`
+	JournalRejected int     `json:"journal_rejected"`
+	JournalPending  int     `json:"journal_pending"`
+	AcceptanceRate  float64 `json:"acceptance_rate"`
+
+	SimTime        time.Time `json:"sim_time,omitempty"`
+	ActiveLastDay  int       `json:"active_last_day"` // agents with a turn in the last sim day
+	PendingReviews int       `json:"pending_reviews"`
+	OpenConsensus  int       `json:"open_consensus"`
+
+	Today     *StatsDay   `json:"today,omitempty"`
+	Yesterday *StatsDay   `json:"yesterday,omitempty"`
+	Deltas    *StatsDelta `json:"deltas,omitempty"` // today minus yesterday
+}
+
+// StatsDay is the activity of one sim day.
+type StatsDay struct {
+	Day          string `json:"day"`
+	Posts        int    `json:"posts"`
+	Comments     int    `json:"comments"`
+	Papers       int    `json:"papers"`
+	ActiveAgents int    `json:"active_agents"`
+}
+
+// StatsDelta is the day-over-day change of a StatsDay.
+type StatsDelta struct {
+	Posts        int `json:"posts"`
+	Comments     int `json:"comments"`
+	Papers       int `json:"papers"`
+	ActiveAgents int `json:"active_agents"`
+}
+
+// AuditResponse is returned by /api/audit, newest entries first.
+type AuditResponse struct {
+	Total   int           `json:"total"` // matching entries before pagination
+	Entries []audit.Entry `json:"entries"`
+}
+
+// GraphResponse is returned by /api/graph: the agents' social network as
+// seen from their relationship state.
+type GraphResponse struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is an agent. Peers known only from someone's relationships have
+// no role or standing.
+type GraphNode struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Role       string  `json:"role,omitempty"`
+	Karma      int     `json:"karma"`
+	Reputation float64 `json:"reputation"`
+	Retired    bool    `json:"retired,omitempty"`
+}
+
+// GraphEdge is one agent's relationship toward a peer. Edges are directed:
+// A's trust in B and B's trust in A are separate edges.
+type GraphEdge struct {
+	Source          string    `json:"source"`
+	Target          string    `json:"target"`
+	State           string    `json:"state"`
+	Trust           float64   `json:"trust"`
+	Familiarity     float64   `json:"familiarity"`
+	Interactions    int       `json:"interactions"`
+	LastInteraction time.Time `json:"last_interaction"`
+	SharedTopics    []string  `json:"shared_topics,omitempty"`
+}
+
+// LineageResponse is returned by /api/lineage: how theories spread from
+// agent to agent, most adopted first.
+type LineageResponse struct {
+	Theories []lineage.Tree `json:"theories"`
+}
+
+// SnapshotResponse is returned by /api/snapshot: the world as of a sim
+// time, rebuilt from the audit trail.
+type SnapshotResponse struct {
+	At   time.Time `json:"at"`
+	Tick int       `json:"tick"` // tick of the last operation at or before At
+
+	Forum    ForumSnapshot    `json:"forum"`
+	Journal  JournalSnapshot  `json:"journal"`
+	Workflow WorkflowSnapshot `json:"workflow"`
+	Agents   AgentsSnapshot   `json:"agents"`
+}
+
+// ForumSnapshot counts forum content as of a snapshot.
+type ForumSnapshot struct {
+	Threads    int `json:"threads"`
+	Comments   int `json:"comments"`
+	Subreddits int `json:"subreddits"` // created during the run
+	Reports    int `json:"reports"`
+}
+
+// JournalSnapshot counts journal submissions by outcome as of a snapshot.
+// Papers includes exogenous ones published without review.
+type JournalSnapshot struct {
+	Submitted int `json:"submitted"`
+	Accepted  int `json:"accepted"`
+	Rejected  int `json:"rejected"`
+	Withdrawn int `json:"withdrawn"`
+	Pending   int `json:"pending"`
+	Papers    int `json:"papers"`
+	Retracted int `json:"retracted"`
+}
+
+// WorkflowSnapshot counts review workflow activity as of a snapshot.
+type WorkflowSnapshot struct {
+	Submissions       int `json:"submissions"`
+	Reviews           int `json:"reviews"`
+	Decisions         int `json:"decisions"`
+	Drafts            int `json:"drafts"`
+	ConsensusRequests int `json:"consensus_requests"`
+	ConsensusAchieved int `json:"consensus_achieved"`
+}
+
+// AgentsSnapshot counts the actors seen in the audit trail as of a
+// snapshot.
+type AgentsSnapshot struct {
+	Active     int `json:"active"`      // distinct actors so far
+	ActiveLast int `json:"active_last"` // distinct actors in the 24 sim hours before At
+}
+
+// TimelineResponse is returned by /api/timeline: the run's milestones,
+// oldest first.
+type TimelineResponse struct {
+	Milestones []Milestone `json:"milestones"`
+}
+
+// Milestone is the first occurrence of something in the run, such as the
+// first accepted paper or the first achieved consensus.
+type Milestone struct {
+	Kind    string    `json:"kind"` // e.g. first_paper
+	Label   string    `json:"label"`
+	SimTime time.Time `json:"sim_time"`
+	Tick    int       `json:"tick,omitempty"`
+	Actor   string    `json:"actor,omitempty"`
+	Target  string    `json:"target,omitempty"`
+}
+
+// SearchResponse is returned by /api/search.
+type SearchResponse struct {
+	Query string                  `json:"query"`
+	Scope string                  `json:"scope"` // forum | journal | all
+	Hits  []publication.SearchHit `json:"hits"`
+}
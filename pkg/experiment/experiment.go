@@ -0,0 +1,302 @@
+// Package experiment runs parameterized virtual experiments that test agent
+// hypotheses and keeps their results so papers can cite them.
+package experiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/idgen"
+)
+
+// Outcome is the verdict of an experiment on its prediction.
+type Outcome string
+
+const (
+	OutcomeSupported    Outcome = "supported"
+	OutcomeRefuted      Outcome = "refuted"
+	OutcomeInconclusive Outcome = "inconclusive" // no prediction, or metric not measured
+)
+
+// Prediction is the falsifiable claim an experiment checks: metric op value.
+type Prediction struct {
+	Metric string `json:"metric"`
+	// Op is one of "<", "<=", ">", ">=", "≈" (relative Tolerance).
+	Op        string  `json:"op"`
+	Value     float64 `json:"value"`
+	Tolerance float64 `json:"tolerance,omitempty"` // relative, for "≈" (default 0.05)
+}
+
+// Experiment is a stored run of a template against a hypothesis.
+type Experiment struct {
+	ID         string `json:"id"`
+	AgentID    string `json:"agent_id"`
+	AgentName  string `json:"agent_name"`
+	Hypothesis string `json:"hypothesis"`
+	// Optional link to a hypothesis of a formal theory.
+	TheoryID     string `json:"theory_id,omitempty"`
+	HypothesisID string `json:"hypothesis_id,omitempty"`
+
+	Template   string             `json:"template"`
+	Params     map[string]float64 `json:"params"`
+	Seed       int64              `json:"seed"`
+	Metrics    map[string]float64 `json:"metrics"`
+	Prediction *Prediction        `json:"prediction,omitempty"`
+	Outcome    Outcome            `json:"outcome"`
+	CreatedAt  time.Time          `json:"created_at"`
+
+	// Journal submissions citing this experiment.
+	CitedBy []string `json:"cited_by,omitempty"`
+}
+
+// Store keeps experiments in experiments.json under dataPath.
+type Store struct {
+	mu          sync.RWMutex
+	saveMu      sync.Mutex // orders concurrent saves
+	dataPath    string
+	experiments map[string]*Experiment
+}
+
+// NewStore creates an experiment store.
+func NewStore(dataPath string) *Store {
+	return &Store{
+		dataPath:    dataPath,
+		experiments: make(map[string]*Experiment),
+	}
+}
+
+// Run resolves the template parameters, runs the experiment, evaluates the
+// prediction and stores the result. A zero Seed picks one from the clock so
+// the run can be reproduced from the stored record.
+func (s *Store) Run(exp *Experiment) error {
+	if strings.TrimSpace(exp.Hypothesis) == "" {
+		return fmt.Errorf("hypothesis is required")
+	}
+	tmpl, err := GetTemplate(exp.Template)
+	if err != nil {
+		return err
+	}
+	params, err := tmpl.resolve(exp.Params)
+	if err != nil {
+		return err
+	}
+	if exp.Seed == 0 {
+		exp.Seed = time.Now().UnixNano()
+	}
+	exp.Params = params
+	exp.Metrics = roundMetrics(tmpl.run(params, rand.New(rand.NewSource(exp.Seed))))
+	exp.Outcome = Evaluate(exp.Prediction, exp.Metrics)
+	if exp.CreatedAt.IsZero() {
+		exp.CreatedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	if exp.ID == "" {
+		exp.ID = idgen.New("exp")
+	}
+	if _, exists := s.experiments[exp.ID]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("experiment already exists: %s", exp.ID)
+	}
+	s.experiments[exp.ID] = exp
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// Evaluate checks a prediction against measured metrics.
+func Evaluate(pred *Prediction, metrics map[string]float64) Outcome {
+	if pred == nil || pred.Metric == "" {
+		return OutcomeInconclusive
+	}
+	got, ok := metrics[pred.Metric]
+	if !ok {
+		return OutcomeInconclusive
+	}
+	var hold bool
+	switch pred.Op {
+	case "<":
+		hold = got < pred.Value
+	case "<=":
+		hold = got <= pred.Value
+	case ">":
+		hold = got > pred.Value
+	case ">=":
+		hold = got >= pred.Value
+	case "≈", "~", "approx", "=", "==":
+		tol := pred.Tolerance
+		if tol <= 0 {
+			tol = 0.05
+		}
+		hold = math.Abs(got-pred.Value) <= tol*math.Max(math.Abs(pred.Value), 1e-9)
+	default:
+		return OutcomeInconclusive
+	}
+	if hold {
+		return OutcomeSupported
+	}
+	return OutcomeRefuted
+}
+
+// Get returns a copy of an experiment.
+func (s *Store) Get(id string) (Experiment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	exp, ok := s.experiments[id]
+	if !ok {
+		return Experiment{}, fmt.Errorf("experiment not found: %s", id)
+	}
+	return *exp, nil
+}
+
+// List returns experiments (optionally only one agent's), newest first.
+func (s *Store) List(agentID string) []Experiment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Experiment, 0, len(s.experiments))
+	for _, exp := range s.experiments {
+		if agentID == "" || exp.AgentID == agentID {
+			out = append(out, *exp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// Cite records that a journal submission cites the experiments.
+func (s *Store) Cite(pubID string, ids []string) error {
+	s.mu.Lock()
+	for _, id := range ids {
+		exp, ok := s.experiments[id]
+		if !ok {
+			s.mu.Unlock()
+			return fmt.Errorf("experiment not found: %s", id)
+		}
+		cited := false
+		for _, p := range exp.CitedBy {
+			if p == pubID {
+				cited = true
+				break
+			}
+		}
+		if !cited {
+			exp.CitedBy = append(exp.CitedBy, pubID)
+		}
+	}
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// Uncite drops pubID from the experiments' citations, undoing Cite.
+func (s *Store) Uncite(pubID string, ids []string) error {
+	s.mu.Lock()
+	for _, id := range ids {
+		if exp, ok := s.experiments[id]; ok {
+			exp.CitedBy = slices.DeleteFunc(exp.CitedBy, func(p string) bool { return p == pubID })
+		}
+	}
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// Save writes all experiments to disk atomically. Saves are serialized so
+// an older snapshot never replaces a newer one.
+func (s *Store) Save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	// Marshal under the read lock: agents may run experiments concurrently.
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.experiments, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dataPath, 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(s.dataPath, "experiments.json"), data)
+}
+
+// writeFileAtomic writes data to a temp file beside path and renames it
+// into place, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads experiments from disk.
+func (s *Store) Load() error {
+	data, err := os.ReadFile(filepath.Join(s.dataPath, "experiments.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	experiments := make(map[string]*Experiment)
+	if err := json.Unmarshal(data, &experiments); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.experiments = experiments
+	s.mu.Unlock()
+	return nil
+}
+
+func roundMetrics(m map[string]float64) map[string]float64 {
+	for k, v := range m {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			delete(m, k)
+			continue
+		}
+		// Six significant digits keep the JSON readable without hiding effects.
+		if v != 0 {
+			scale := math.Pow(10, 5-math.Floor(math.Log10(math.Abs(v))))
+			m[k] = math.Round(v*scale) / scale
+		}
+	}
+	return m
+}
+
+// Citation renders a one-line reference to an experiment for paper appendices.
+func Citation(exp Experiment) string {
+	keys := make([]string, 0, len(exp.Params))
+	for k := range exp.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := make([]string, 0, len(keys))
+	for _, k := range keys {
+		params = append(params, fmt.Sprintf("%s=%g", k, exp.Params[k]))
+	}
+	line := fmt.Sprintf("[%s] %s — %s(%s), seed %d: %s", exp.ID, exp.Hypothesis, exp.Template, strings.Join(params, ", "), exp.Seed, exp.Outcome)
+	if p := exp.Prediction; p != nil {
+		if got, ok := exp.Metrics[p.Metric]; ok {
+			line += fmt.Sprintf("（预测 %s %s %g，实测 %g）", p.Metric, p.Op, p.Value, got)
+		}
+	}
+	return line
+}
@@ -0,0 +1,141 @@
+package experiment
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStore_RunEvaluatesPredictions(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	projectile := &Experiment{
+		AgentID:    "galileo",
+		Hypothesis: "Without drag the range is v0²/g at 45°",
+		Template:   "projectile",
+		Params:     map[string]float64{"v0": 20},
+		Prediction: &Prediction{Metric: "range", Op: "≈", Value: 400 / 9.81, Tolerance: 0.01},
+	}
+	if err := store.Run(projectile); err != nil {
+		t.Fatalf("run projectile: %v", err)
+	}
+	if projectile.Outcome != OutcomeSupported {
+		t.Fatalf("expected supported, got %s (metrics %v)", projectile.Outcome, projectile.Metrics)
+	}
+
+	pendulum := &Experiment{
+		AgentID:    "huygens",
+		Hypothesis: "A pendulum's period does not depend on amplitude",
+		Template:   "pendulum",
+		Params:     map[string]float64{"amplitude_deg": 90},
+		Prediction: &Prediction{Metric: "period_ratio", Op: "≈", Value: 1, Tolerance: 0.05},
+	}
+	if err := store.Run(pendulum); err != nil {
+		t.Fatalf("run pendulum: %v", err)
+	}
+	if pendulum.Outcome != OutcomeRefuted {
+		t.Fatalf("expected refuted at 90°, got %s (metrics %v)", pendulum.Outcome, pendulum.Metrics)
+	}
+
+	if err := store.Run(&Experiment{Hypothesis: "h", Template: "pendulum", Params: map[string]float64{"mass": 1}}); err == nil {
+		t.Fatalf("expected unknown parameter error")
+	}
+	if err := store.Run(&Experiment{Hypothesis: "h", Template: "warp_drive"}); err == nil {
+		t.Fatalf("expected unknown template error")
+	}
+
+	// Same seed reproduces a stochastic run.
+	a := &Experiment{Hypothesis: "pi", Template: "monte_carlo_pi", Seed: 42}
+	b := &Experiment{Hypothesis: "pi", Template: "monte_carlo_pi", Seed: 42}
+	if err := store.Run(a); err != nil {
+		t.Fatalf("run a: %v", err)
+	}
+	if err := store.Run(b); err != nil {
+		t.Fatalf("run b: %v", err)
+	}
+	if a.Metrics["pi_estimate"] != b.Metrics["pi_estimate"] || math.Abs(a.Metrics["pi_estimate"]-math.Pi) > 0.05 {
+		t.Fatalf("unexpected pi estimates %v / %v", a.Metrics, b.Metrics)
+	}
+
+	if err := store.Cite("journal-1", []string{projectile.ID}); err != nil {
+		t.Fatalf("cite: %v", err)
+	}
+	if err := store.Cite("journal-1", []string{"exp-missing"}); err == nil {
+		t.Fatalf("expected error citing unknown experiment")
+	}
+
+	reloaded := NewStore(dir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got, err := reloaded.Get(projectile.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Outcome != OutcomeSupported || len(got.CitedBy) != 1 || got.Params["angle_deg"] != 45 {
+		t.Fatalf("unexpected reloaded experiment: %+v", got)
+	}
+	if n := len(reloaded.List("galileo")); n != 1 {
+		t.Fatalf("expected 1 experiment for galileo, got %d", n)
+	}
+}
+
+func TestStore_ConcurrentRunsAllSaved(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	const runs = 16
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exp := &Experiment{
+				AgentID:    fmt.Sprintf("agent-%d", i),
+				Hypothesis: "Range grows with launch speed",
+				Template:   "projectile",
+				Params:     map[string]float64{"v0": float64(10 + i)},
+			}
+			if err := store.Run(exp); err != nil {
+				t.Errorf("run: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	reloaded := NewStore(dir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if n := len(reloaded.List("")); n != runs {
+		t.Fatalf("expected %d saved experiments, got %d", runs, n)
+	}
+}
+
+func TestPlotSVG(t *testing.T) {
+	exps := []Experiment{
+		{ID: "exp-b", Params: map[string]float64{"length": 4}, Metrics: map[string]float64{"period": 4.01}},
+		{ID: "exp-a", Params: map[string]float64{"length": 1}, Metrics: map[string]float64{"period": 2.006}},
+	}
+	svg, err := PlotSVG(exps, "length", "period")
+	if err != nil {
+		t.Fatalf("plot: %v", err)
+	}
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "<polyline") || strings.Count(svg, "<circle") != 2 {
+		t.Fatalf("unexpected svg: %s", svg)
+	}
+	// Points are ordered by x: the shorter pendulum comes first.
+	if !strings.Contains(svg, "length=1, period=2.006</title></circle><circle") {
+		t.Fatalf("points not ordered by x: %s", svg)
+	}
+	if _, err := PlotSVG(exps, "length", "range"); err == nil {
+		t.Fatalf("expected error for a missing metric")
+	}
+	odd := []Experiment{{ID: "exp-c", Params: map[string]float64{"<x>": 1}, Metrics: map[string]float64{"period": 2}}}
+	if svg, err := PlotSVG(odd, "<x>", "period"); err != nil || strings.Contains(svg, "<x>") {
+		t.Fatalf("axis names must be escaped: %s", svg)
+	}
+}
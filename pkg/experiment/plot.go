@@ -0,0 +1,98 @@
+package experiment
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Plot dimensions in SVG user units.
+const (
+	plotWidth   = 480
+	plotHeight  = 300
+	plotMarginL = 64
+	plotMarginR = 16
+	plotMarginT = 16
+	plotMarginB = 44
+)
+
+// Value returns a parameter or, failing that, a metric of the experiment.
+func (exp Experiment) Value(name string) (float64, bool) {
+	if v, ok := exp.Params[name]; ok {
+		return v, true
+	}
+	v, ok := exp.Metrics[name]
+	return v, ok
+}
+
+// PlotSVG renders y against x (each a parameter or metric name) over the
+// experiments as a line plot with point markers, ordered by x. Every
+// experiment must have both values.
+func PlotSVG(exps []Experiment, x, y string) (string, error) {
+	if len(exps) == 0 {
+		return "", fmt.Errorf("plot: no experiments")
+	}
+	type point struct{ x, y float64 }
+	points := make([]point, 0, len(exps))
+	for _, exp := range exps {
+		px, okX := exp.Value(x)
+		py, okY := exp.Value(y)
+		if !okX || !okY {
+			return "", fmt.Errorf("plot: experiment %s has no %s or %s", exp.ID, x, y)
+		}
+		points = append(points, point{px, py})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].x < points[j].x })
+
+	minX, maxX := points[0].x, points[len(points)-1].x
+	minY, maxY := points[0].y, points[0].y
+	for _, p := range points {
+		minY = math.Min(minY, p.y)
+		maxY = math.Max(maxY, p.y)
+	}
+	// A flat range still needs a scale; center it.
+	if maxX == minX {
+		minX, maxX = minX-1, maxX+1
+	}
+	if maxY == minY {
+		minY, maxY = minY-1, maxY+1
+	}
+	innerW := float64(plotWidth - plotMarginL - plotMarginR)
+	innerH := float64(plotHeight - plotMarginT - plotMarginB)
+	sx := func(v float64) float64 { return plotMarginL + (v-minX)/(maxX-minX)*innerW }
+	sy := func(v float64) float64 { return plotMarginT + (maxY-v)/(maxY-minY)*innerH }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`, plotWidth, plotHeight)
+	left, right := float64(plotMarginL), float64(plotWidth-plotMarginR)
+	top, bottom := float64(plotMarginT), float64(plotHeight-plotMarginB)
+	fmt.Fprintf(&b, `<path d="M%.1f %.1fV%.1fH%.1f" fill="none" stroke="#555"/>`, left, top, bottom, right)
+	// Axis bounds
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="start">%s</text>`, left, bottom+14, plotNumber(minX))
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`, right, bottom+14, plotNumber(maxX))
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`, left-4, bottom, plotNumber(minY))
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`, left-4, top+8, plotNumber(maxY))
+	// Axis labels
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`, (left+right)/2, plotHeight-8, html.EscapeString(x))
+	fmt.Fprintf(&b, `<text x="12" y="%.1f" text-anchor="middle" transform="rotate(-90 12 %.1f)">%s</text>`, (top+bottom)/2, (top+bottom)/2, html.EscapeString(y))
+
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", sx(p.x), sy(p.y))
+	}
+	if len(points) > 1 {
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#2563eb" stroke-width="1.5"/>`, strings.Join(coords, " "))
+	}
+	for _, p := range points {
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="#2563eb"><title>%s=%s, %s=%s</title></circle>`,
+			sx(p.x), sy(p.y), html.EscapeString(x), plotNumber(p.x), html.EscapeString(y), plotNumber(p.y))
+	}
+	b.WriteString(`</svg>`)
+	return b.String(), nil
+}
+
+func plotNumber(v float64) string {
+	return fmt.Sprintf("%.4g", v)
+}
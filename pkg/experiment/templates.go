@@ -0,0 +1,304 @@
+package experiment
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Param describes a template parameter.
+type Param struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Default     float64 `json:"default"`
+	Min         float64 `json:"min"`
+	Max         float64 `json:"max"`
+}
+
+// Template is a parameterized virtual experiment. Run must be deterministic
+// for a given parameter set and random source.
+type Template struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Params      []Param  `json:"params"`
+	Metrics     []string `json:"metrics"`
+	run         func(p map[string]float64, rng *rand.Rand) map[string]float64
+}
+
+var templates = map[string]*Template{
+	"projectile": {
+		Name:        "projectile",
+		Description: "抛体运动数值积分（可选线性空气阻力）",
+		Params: []Param{
+			{Name: "v0", Description: "初速度 m/s", Default: 20, Min: 0.1, Max: 1000},
+			{Name: "angle_deg", Description: "发射角（度）", Default: 45, Min: 0, Max: 90},
+			{Name: "g", Description: "重力加速度 m/s²", Default: 9.81, Min: 0.1, Max: 100},
+			{Name: "drag", Description: "线性阻力系数 1/s", Default: 0, Min: 0, Max: 10},
+		},
+		Metrics: []string{"range", "max_height", "flight_time"},
+		run:     runProjectile,
+	},
+	"pendulum": {
+		Name:        "pendulum",
+		Description: "单摆（非线性，RK4 积分，可选阻尼），测量周期",
+		Params: []Param{
+			{Name: "length", Description: "摆长 m", Default: 1, Min: 0.01, Max: 100},
+			{Name: "amplitude_deg", Description: "初始摆角（度）", Default: 10, Min: 0.1, Max: 179},
+			{Name: "g", Description: "重力加速度 m/s²", Default: 9.81, Min: 0.1, Max: 100},
+			{Name: "damping", Description: "阻尼系数 1/s", Default: 0, Min: 0, Max: 5},
+		},
+		Metrics: []string{"period", "small_angle_period", "period_ratio", "final_amplitude_deg"},
+		run:     runPendulum,
+	},
+	"random_walk": {
+		Name:        "random_walk",
+		Description: "格点随机游走，测量均方位移",
+		Params: []Param{
+			{Name: "steps", Description: "步数", Default: 1000, Min: 1, Max: 100000},
+			{Name: "walkers", Description: "游走者数量", Default: 200, Min: 1, Max: 10000},
+			{Name: "dims", Description: "维度", Default: 2, Min: 1, Max: 3},
+		},
+		Metrics: []string{"msd", "msd_per_step"},
+		run:     runRandomWalk,
+	},
+	"monte_carlo_pi": {
+		Name:        "monte_carlo_pi",
+		Description: "蒙特卡洛估计 π",
+		Params: []Param{
+			{Name: "samples", Description: "采样点数", Default: 100000, Min: 1, Max: 1000000},
+		},
+		Metrics: []string{"pi_estimate", "abs_error"},
+		run:     runMonteCarloPi,
+	},
+	"logistic_map": {
+		Name:        "logistic_map",
+		Description: "Logistic 映射 x→r·x·(1−x)，测量 Lyapunov 指数与周期",
+		Params: []Param{
+			{Name: "r", Description: "增长率", Default: 3.5, Min: 0, Max: 4},
+			{Name: "x0", Description: "初值", Default: 0.2, Min: 0, Max: 1},
+			{Name: "iterations", Description: "迭代次数", Default: 2000, Min: 100, Max: 100000},
+		},
+		Metrics: []string{"lyapunov", "final_x", "period"},
+		run:     runLogisticMap,
+	},
+	"decay": {
+		Name:        "decay",
+		Description: "随机放射性衰变，按剩余数拟合半衰期",
+		Params: []Param{
+			{Name: "n0", Description: "初始原子数", Default: 10000, Min: 1, Max: 1000000},
+			{Name: "half_life", Description: "真实半衰期", Default: 10, Min: 0.01, Max: 1e6},
+			{Name: "duration", Description: "观测时长", Default: 30, Min: 0.01, Max: 1e7},
+		},
+		Metrics: []string{"remaining", "remaining_fraction", "estimated_half_life"},
+		run:     runDecay,
+	},
+}
+
+// Templates returns all templates sorted by name.
+func Templates() []*Template {
+	out := make([]*Template, 0, len(templates))
+	for _, t := range templates {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// GetTemplate returns a template by name.
+func GetTemplate(name string) (*Template, error) {
+	if t, ok := templates[name]; ok {
+		return t, nil
+	}
+	names := make([]string, 0, len(templates))
+	for _, t := range Templates() {
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("unknown experiment template: %s (available: %v)", name, names)
+}
+
+// resolve fills defaults and rejects unknown or out-of-range parameters.
+func (t *Template) resolve(params map[string]float64) (map[string]float64, error) {
+	out := make(map[string]float64, len(t.Params))
+	known := make(map[string]bool, len(t.Params))
+	for _, p := range t.Params {
+		known[p.Name] = true
+		v, ok := params[p.Name]
+		if !ok {
+			v = p.Default
+		}
+		if math.IsNaN(v) || v < p.Min || v > p.Max {
+			return nil, fmt.Errorf("%s: %s must be in [%g, %g], got %g", t.Name, p.Name, p.Min, p.Max, v)
+		}
+		out[p.Name] = v
+	}
+	for name := range params {
+		if !known[name] {
+			return nil, fmt.Errorf("%s: unknown parameter %s", t.Name, name)
+		}
+	}
+	return out, nil
+}
+
+func runProjectile(p map[string]float64, _ *rand.Rand) map[string]float64 {
+	theta := p["angle_deg"] * math.Pi / 180
+	vx, vy := p["v0"]*math.Cos(theta), p["v0"]*math.Sin(theta)
+	g, k := p["g"], p["drag"]
+	// Choose a step that resolves the flight into a few thousand points.
+	dt := math.Max(2*vy/g, 1e-3) / 5000
+	if dt <= 0 {
+		dt = 1e-4
+	}
+	x, y, t, maxY := 0.0, 0.0, 0.0, 0.0
+	for i := 0; i < 2_000_000; i++ {
+		ax, ay := -k*vx, -g-k*vy
+		nx, ny := x+vx*dt, y+vy*dt
+		vx += ax * dt
+		vy += ay * dt
+		if ny < 0 && t > 0 {
+			// Interpolate the landing point.
+			frac := y / (y - ny)
+			x += (nx - x) * frac
+			t += dt * frac
+			break
+		}
+		x, y, t = nx, ny, t+dt
+		maxY = math.Max(maxY, y)
+	}
+	return map[string]float64{"range": x, "max_height": maxY, "flight_time": t}
+}
+
+func runPendulum(p map[string]float64, _ *rand.Rand) map[string]float64 {
+	l, g, c := p["length"], p["g"], p["damping"]
+	theta, omega := p["amplitude_deg"]*math.Pi/180, 0.0
+	small := 2 * math.Pi * math.Sqrt(l/g)
+	dt := small / 2000
+	accel := func(th, om float64) float64 { return -g/l*math.Sin(th) - c*om }
+
+	crossings := make([]float64, 0, 8)
+	t := 0.0
+	for i := 0; i < 50000 && len(crossings) < 5; i++ {
+		k1t, k1o := omega, accel(theta, omega)
+		k2t, k2o := omega+k1o*dt/2, accel(theta+k1t*dt/2, omega+k1o*dt/2)
+		k3t, k3o := omega+k2o*dt/2, accel(theta+k2t*dt/2, omega+k2o*dt/2)
+		k4t, k4o := omega+k3o*dt, accel(theta+k3t*dt, omega+k3o*dt)
+		next := theta + dt/6*(k1t+2*k2t+2*k3t+k4t)
+		omega += dt / 6 * (k1o + 2*k2o + 2*k3o + k4o)
+		if theta*next < 0 {
+			crossings = append(crossings, t+dt*theta/(theta-next))
+		}
+		theta = next
+		t += dt
+	}
+	period := 0.0
+	if n := len(crossings); n >= 3 {
+		// Consecutive zero crossings are half a period apart.
+		period = 2 * (crossings[n-1] - crossings[0]) / float64(n-1)
+	}
+	return map[string]float64{
+		"period":              period,
+		"small_angle_period":  small,
+		"period_ratio":        period / small,
+		"final_amplitude_deg": math.Abs(theta) * 180 / math.Pi,
+	}
+}
+
+func runRandomWalk(p map[string]float64, rng *rand.Rand) map[string]float64 {
+	steps, walkers, dims := int(p["steps"]), int(p["walkers"]), int(p["dims"])
+	// Keep a single run bounded.
+	if steps*walkers > 5_000_000 {
+		walkers = maxInt(1, 5_000_000/steps)
+	}
+	total := 0.0
+	pos := make([]int, dims)
+	for w := 0; w < walkers; w++ {
+		for i := range pos {
+			pos[i] = 0
+		}
+		for s := 0; s < steps; s++ {
+			d := rng.Intn(dims)
+			if rng.Intn(2) == 0 {
+				pos[d]--
+			} else {
+				pos[d]++
+			}
+		}
+		for _, v := range pos {
+			total += float64(v * v)
+		}
+	}
+	msd := total / float64(walkers)
+	return map[string]float64{"msd": msd, "msd_per_step": msd / float64(steps)}
+}
+
+func runMonteCarloPi(p map[string]float64, rng *rand.Rand) map[string]float64 {
+	n := int(p["samples"])
+	inside := 0
+	for i := 0; i < n; i++ {
+		x, y := rng.Float64(), rng.Float64()
+		if x*x+y*y <= 1 {
+			inside++
+		}
+	}
+	est := 4 * float64(inside) / float64(n)
+	return map[string]float64{"pi_estimate": est, "abs_error": math.Abs(est - math.Pi)}
+}
+
+func runLogisticMap(p map[string]float64, _ *rand.Rand) map[string]float64 {
+	r, x, n := p["r"], p["x0"], int(p["iterations"])
+	burn := n / 2
+	sum := 0.0
+	tail := make([]float64, 0, 128)
+	for i := 0; i < n; i++ {
+		x = r * x * (1 - x)
+		if i >= burn {
+			sum += math.Log(math.Max(math.Abs(r*(1-2*x)), 1e-12))
+		}
+		if i >= n-128 {
+			tail = append(tail, x)
+		}
+	}
+	period := 0.0
+	for k := 1; k <= 64; k++ {
+		cyclic := true
+		for i := len(tail) - 1; i-k >= len(tail)-64; i-- {
+			if math.Abs(tail[i]-tail[i-k]) > 1e-6 {
+				cyclic = false
+				break
+			}
+		}
+		if cyclic {
+			period = float64(k)
+			break
+		}
+	}
+	return map[string]float64{"lyapunov": sum / float64(n-burn), "final_x": x, "period": period}
+}
+
+func runDecay(p map[string]float64, rng *rand.Rand) map[string]float64 {
+	n0 := int(p["n0"])
+	survive := math.Pow(0.5, p["duration"]/p["half_life"])
+	remaining := 0
+	for i := 0; i < n0; i++ {
+		if rng.Float64() < survive {
+			remaining++
+		}
+	}
+	frac := float64(remaining) / float64(n0)
+	est := math.Inf(1)
+	if remaining > 0 && remaining < n0 {
+		est = p["duration"] * math.Ln2 / -math.Log(frac)
+	}
+	out := map[string]float64{"remaining": float64(remaining), "remaining_fraction": frac}
+	if !math.IsInf(est, 0) {
+		out["estimated_half_life"] = est
+	}
+	return out
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,156 @@
+package feed
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AgentIndex lists where one agent's events are in the shards, so a page
+// about the agent loads only the shards holding them instead of the whole
+// feed. The writer keeps one per agent under "agents/<agent id>.json".
+type AgentIndex struct {
+	AgentID     string `json:"agent_id"`
+	TotalEvents int    `json:"total_events"`
+
+	// Shards are ordered oldest -> newest, like Index.Shards.
+	Shards []AgentShard `json:"shards"`
+}
+
+type AgentShard struct {
+	Seq  int    `json:"seq"`
+	File string `json:"file"`
+	// Lines are the agent's events in the shard, numbered from 0 over the
+	// shard's non-empty lines (the line part of a Reader cursor).
+	Lines []int `json:"lines"`
+	// First and Last are the sim times of the agent's first and last event
+	// in the shard.
+	First time.Time `json:"first,omitzero"`
+	Last  time.Time `json:"last,omitzero"`
+}
+
+// overlaps reports whether the shard may hold events in [since, until);
+// zero bounds are open.
+func (s AgentShard) overlaps(since, until time.Time) bool {
+	if !since.IsZero() && !s.Last.IsZero() && s.Last.Before(since) {
+		return false
+	}
+	if !until.IsZero() && !s.First.IsZero() && !s.First.Before(until) {
+		return false
+	}
+	return true
+}
+
+const agentsDir = "agents"
+
+// AgentIndexPath returns the path of an agent's index in the feed dir, or
+// "" for IDs that aren't safe as file names (those agents aren't indexed).
+func AgentIndexPath(dir, agentID string) string {
+	if !safeAgentID(agentID) {
+		return ""
+	}
+	return filepath.Join(dir, agentsDir, agentID+".json")
+}
+
+func safeAgentID(id string) bool {
+	if id == "" || id[0] == '.' || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// LoadAgentIndex loads an agent's index from the feed dir. A missing
+// index returns an error satisfying errors.Is(err, os.ErrNotExist).
+func LoadAgentIndex(dir, agentID string) (*AgentIndex, error) {
+	path := AgentIndexPath(dir, agentID)
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ai := &AgentIndex{}
+	if err := json.Unmarshal(data, ai); err != nil {
+		return nil, err
+	}
+	return ai, nil
+}
+
+// eventKey is the part of a feed line the indexes need.
+type eventKey struct {
+	AgentID   string    `json:"agent_id"`
+	Action    string    `json:"action"`
+	SimTime   time.Time `json:"sim_time"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// at is the event's sim time, or its wall time for events without one.
+func (k eventKey) at() time.Time {
+	if k.SimTime.IsZero() {
+		return k.Timestamp
+	}
+	return k.SimTime
+}
+
+// add records the event at line of shard.
+func (ai *AgentIndex) add(shard Shard, line int, at time.Time) {
+	n := len(ai.Shards)
+	if n == 0 || ai.Shards[n-1].Seq != shard.Seq {
+		ai.Shards = append(ai.Shards, AgentShard{Seq: shard.Seq, File: shard.File, First: at})
+		n++
+	}
+	s := &ai.Shards[n-1]
+	s.Lines = append(s.Lines, line)
+	if s.First.IsZero() || (!at.IsZero() && at.Before(s.First)) {
+		s.First = at
+	}
+	if at.After(s.Last) {
+		s.Last = at
+	}
+	ai.TotalEvents++
+}
+
+// buildAgentIndexes scans the shards of the store in dir once and returns
+// every agent's index.
+func buildAgentIndexes(dir string, shards []Shard) map[string]*AgentIndex {
+	out := make(map[string]*AgentIndex)
+	for _, shard := range shards {
+		f, err := OpenJSONL(filepath.Join(dir, shard.File))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+		line := -1
+		for scanner.Scan() {
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) == 0 {
+				continue
+			}
+			line++
+			var k eventKey
+			if err := json.Unmarshal(data, &k); err != nil || !safeAgentID(k.AgentID) {
+				continue
+			}
+			ai := out[k.AgentID]
+			if ai == nil {
+				ai = &AgentIndex{AgentID: k.AgentID}
+				out[k.AgentID] = ai
+			}
+			ai.add(shard, line, k.at())
+		}
+		_ = f.Close()
+	}
+	return out
+}
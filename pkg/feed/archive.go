@@ -0,0 +1,144 @@
+package feed
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GzipExt marks an archived JSONL file, e.g. "logs.jsonl.gz".
+const GzipExt = ".gz"
+
+// OpenJSONL opens a JSONL file for reading, decompressing it if the name
+// ends in GzipExt. When path is missing but path+GzipExt exists, the
+// archive is read instead, so readers keep working after a file has been
+// compressed (see CompressFile).
+func OpenJSONL(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) && !strings.HasSuffix(path, GzipExt) {
+		path += GzipExt
+		f, err = os.Open(path)
+		if os.IsNotExist(err) {
+			// Report the name the caller asked for.
+			return nil, &os.PathError{Op: "open", Path: strings.TrimSuffix(path, GzipExt), Err: os.ErrNotExist}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, GzipExt) {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: zr, f: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g *gzipFile) Close() error {
+	err := g.Reader.Close()
+	if cerr := g.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// CompressFile replaces path with path+GzipExt. The archive is written
+// under a temporary name and keeps the original modification time, so a
+// crash leaves either the original or the complete archive.
+func CompressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst := path + GzipExt
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(path)
+	zw.ModTime = info.ModTime()
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chtimes(tmp, info.ModTime(), info.ModTime())
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// CompactShards compresses the shards of the store in dir last written
+// before cutoff and points the index at the archives. The newest shard is
+// left alone since the writer appends to it. It returns how many index
+// entries were pointed at archives.
+//
+// A running writer rewrites the index from memory with the old names;
+// readers then find the archives through OpenJSONL's fallback.
+func CompactShards(dir string, cutoff time.Time) (int, error) {
+	indexPath := filepath.Join(dir, "index.json")
+	idx, err := LoadIndex(indexPath)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := range idx.Shards {
+		shard := &idx.Shards[i]
+		if i == len(idx.Shards)-1 || strings.HasSuffix(shard.File, GzipExt) {
+			continue
+		}
+		path := filepath.Join(dir, shard.File)
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			if _, err := os.Stat(path + GzipExt); err == nil {
+				// Compressed before, but the index was rewritten since.
+				shard.File += GzipExt
+				n++
+			}
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		if !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := CompressFile(path); err != nil {
+			return n, err
+		}
+		shard.File += GzipExt
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, SaveIndexAtomic(indexPath, idx)
+}
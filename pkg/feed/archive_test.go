@@ -0,0 +1,91 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCompactShards_ReadersFollowArchives(t *testing.T) {
+	dir := t.TempDir()
+	w, err := OpenWriter(WriterConfig{Dir: dir, MaxEventsPerShard: 3})
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := w.AppendJSONLine([]byte(fmt.Sprintf(`{"tick":%d,"agent_id":"a","action":"post"}`, i))); err != nil {
+			t.Fatalf("AppendJSONLine: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	files, _ := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+	for _, f := range files {
+		if err := os.Chtimes(f, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err := CompactShards(dir, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("CompactShards: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("compacted %d shards, want 2 (the newest stays)", n)
+	}
+	idx, err := LoadIndex(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	var names []string
+	for _, s := range idx.Shards {
+		names = append(names, s.File)
+	}
+	if got := fmt.Sprint(names); got != "[events-000001.jsonl.gz events-000002.jsonl.gz events-000003.jsonl]" {
+		t.Fatalf("shards = %s", got)
+	}
+
+	// Appending to the plain newest shard still works after compaction.
+	w, err = OpenWriter(WriterConfig{Dir: dir, MaxEventsPerShard: 3})
+	if err != nil {
+		t.Fatalf("OpenWriter after compaction: %v", err)
+	}
+	if err := w.AppendJSONLine([]byte(`{"tick":7,"agent_id":"a","action":"post"}`)); err != nil {
+		t.Fatalf("AppendJSONLine: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := OpenReader(dir)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	p, err := r.Read(Query{Limit: 20})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	var ticks []int
+	for _, ev := range p.Events {
+		var v struct {
+			Tick int `json:"tick"`
+		}
+		if err := json.Unmarshal(ev.Data, &v); err != nil {
+			t.Fatalf("decode %s: %v", ev.Data, err)
+		}
+		ticks = append(ticks, v.Tick)
+	}
+	if got := fmt.Sprint(ticks); got != "[7 6 5 4 3 2 1 0]" {
+		t.Fatalf("ticks = %s", got)
+	}
+
+	// A second run has nothing left to do.
+	if n, err := CompactShards(dir, time.Now().Add(-24*time.Hour)); err != nil || n != 0 {
+		t.Fatalf("second CompactShards = %d, %v", n, err)
+	}
+}
@@ -20,6 +20,15 @@ type Index struct {
 	Shards []Shard `json:"shards"`
 
 	TotalEvents int `json:"total_events,omitempty"`
+
+	// Sources records, per log file name, how many bytes of it have been
+	// indexed, so incremental updates only append events written since.
+	Sources map[string]int64 `json:"sources,omitempty"`
+
+	// AgentIndexes is set once every event has been recorded in its
+	// agent's index (see AgentIndex); stores written before those existed
+	// get them built when next opened for append.
+	AgentIndexes bool `json:"agent_indexes,omitempty"`
 }
 
 type Shard struct {
@@ -51,12 +60,15 @@ func SaveIndexAtomic(path string, idx *Index) error {
 		idx.Version = 1
 	}
 	idx.GeneratedAt = time.Now()
+	return writeJSONAtomic(path, idx)
+}
 
+func writeJSONAtomic(path string, v any) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(idx, "", "  ")
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,265 @@
+package feed
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reader serves events from a sharded feed store, newest first.
+//
+// Each event is addressed by a cursor "<shard seq>:<line>" that stays valid
+// while the writer appends, so pages don't shift as new events arrive the
+// way offsets do.
+type Reader struct {
+	dir string
+	idx *Index
+}
+
+// OpenReader loads the index of the feed store in dir. A missing store
+// returns an error satisfying errors.Is(err, os.ErrNotExist).
+func OpenReader(dir string) (*Reader, error) {
+	idx, err := LoadIndex(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{dir: dir, idx: idx}, nil
+}
+
+// Query selects a page of events. Before and After are cursors from a
+// previous Page; at most one may be set.
+type Query struct {
+	Limit int
+	// Before returns events older than this cursor.
+	Before string
+	// After returns events newer than this cursor, the ones closest to it
+	// first in the scan (still ordered newest first in the page).
+	After string
+
+	// AgentID and Actions keep only matching events (empty matches all);
+	// SkipActions drops events with these actions.
+	AgentID     string
+	Actions     []string
+	SkipActions []string
+
+	// Since and Until keep events with sim times in [Since, Until); zero
+	// bounds are open.
+	Since time.Time
+	Until time.Time
+}
+
+// Event is one feed line with its cursor.
+type Event struct {
+	Cursor string
+	Data   json.RawMessage
+}
+
+// Page is a slice of events, newest first.
+type Page struct {
+	Events []Event
+	// Next is the cursor to pass as Before for older events; empty when
+	// there are none.
+	Next string
+	// Prev is the cursor to pass as After to poll for newer events.
+	Prev string
+}
+
+type position struct {
+	seq, line int
+}
+
+func (p position) String() string {
+	return fmt.Sprintf("%d:%d", p.seq, p.line)
+}
+
+func (p position) less(o position) bool {
+	return p.seq < o.seq || (p.seq == o.seq && p.line < o.line)
+}
+
+func parseCursor(s string) (position, error) {
+	seq, line, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if ok {
+		a, errA := strconv.Atoi(seq)
+		b, errB := strconv.Atoi(line)
+		if errA == nil && errB == nil && a >= 0 && b >= 0 {
+			return position{a, b}, nil
+		}
+	}
+	return position{}, fmt.Errorf("invalid feed cursor %q", s)
+}
+
+// Read returns the page of events q selects.
+func (r *Reader) Read(q Query) (*Page, error) {
+	if q.Before != "" && q.After != "" {
+		return nil, fmt.Errorf("feed query: before and after are exclusive")
+	}
+	if q.Limit <= 0 {
+		q.Limit = 200
+	}
+	var before, after *position
+	if q.Before != "" {
+		p, err := parseCursor(q.Before)
+		if err != nil {
+			return nil, err
+		}
+		before = &p
+	}
+	if q.After != "" {
+		p, err := parseCursor(q.After)
+		if err != nil {
+			return nil, err
+		}
+		after = &p
+	}
+
+	skip := r.shardFilter(q)
+	page := &Page{}
+	more := false
+	if after != nil {
+		// Scan oldest first from the cursor so the page holds the events
+		// closest to it, then flip.
+		var newer []Event
+		for _, shard := range r.idx.Shards {
+			if shard.Seq < after.seq || skip(shard) {
+				continue
+			}
+			events, err := r.readShard(shard, q)
+			if err != nil {
+				return nil, err
+			}
+			for _, ev := range events {
+				if after.less(ev.pos) {
+					newer = append(newer, Event{Cursor: ev.pos.String(), Data: ev.data})
+				}
+			}
+			if len(newer) >= q.Limit {
+				break
+			}
+		}
+		if len(newer) > q.Limit {
+			newer = newer[:q.Limit]
+		}
+		slices.Reverse(newer)
+		page.Events = newer
+		// Older events exist at least up to the cursor itself.
+		more = true
+	} else {
+		for i := len(r.idx.Shards) - 1; i >= 0 && !more; i-- {
+			shard := r.idx.Shards[i]
+			if (before != nil && shard.Seq > before.seq) || skip(shard) {
+				continue
+			}
+			events, err := r.readShard(shard, q)
+			if err != nil {
+				return nil, err
+			}
+			for j := len(events) - 1; j >= 0; j-- {
+				ev := events[j]
+				if before != nil && !ev.pos.less(*before) {
+					continue
+				}
+				if len(page.Events) == q.Limit {
+					more = true
+					break
+				}
+				page.Events = append(page.Events, Event{Cursor: ev.pos.String(), Data: ev.data})
+			}
+		}
+	}
+
+	if n := len(page.Events); n > 0 {
+		page.Prev = page.Events[0].Cursor
+		if more {
+			page.Next = page.Events[n-1].Cursor
+		}
+	} else if after != nil {
+		page.Prev = q.After
+	} else if before != nil {
+		page.Prev = q.Before
+	}
+	return page, nil
+}
+
+// shardFilter returns which shards can't hold events for q. With an agent
+// filter, the agent's index rules out the shards it has no events in (or
+// none in the time range); otherwise every shard is read.
+func (r *Reader) shardFilter(q Query) func(Shard) bool {
+	none := func(Shard) bool { return false }
+	if q.AgentID == "" || !r.idx.AgentIndexes {
+		return none
+	}
+	ai, err := LoadAgentIndex(r.dir, q.AgentID)
+	if os.IsNotExist(err) && safeAgentID(q.AgentID) {
+		// Indexed store, no events by this agent.
+		return func(Shard) bool { return true }
+	}
+	if err != nil {
+		return none
+	}
+	keep := make(map[int]bool, len(ai.Shards))
+	for _, s := range ai.Shards {
+		if s.overlaps(q.Since, q.Until) {
+			keep[s.Seq] = true
+		}
+	}
+	return func(s Shard) bool { return !keep[s.Seq] }
+}
+
+type shardEvent struct {
+	pos  position
+	data json.RawMessage
+}
+
+// readShard returns a shard's events matching q, oldest first. Lines are
+// numbered as written, counting ones that fail to parse (such as a line
+// still being appended), so cursors don't move when they complete.
+func (r *Reader) readShard(shard Shard, q Query) ([]shardEvent, error) {
+	f, err := OpenJSONL(filepath.Join(r.dir, shard.File))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []shardEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 256*1024), 8*1024*1024)
+	line := -1
+	for scanner.Scan() {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		line++
+		var k eventKey
+		if err := json.Unmarshal(data, &k); err != nil {
+			continue
+		}
+		if at := k.at(); (!q.Since.IsZero() && at.Before(q.Since)) || (!q.Until.IsZero() && !at.Before(q.Until)) {
+			continue
+		}
+		if q.AgentID != "" && k.AgentID != q.AgentID {
+			continue
+		}
+		if len(q.Actions) > 0 && !slices.Contains(q.Actions, k.Action) {
+			continue
+		}
+		if slices.Contains(q.SkipActions, k.Action) {
+			continue
+		}
+		out = append(out, shardEvent{pos: position{shard.Seq, line}, data: bytes.Clone(data)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", shard.File, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,181 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReader_CursorsAndFilters(t *testing.T) {
+	dir := t.TempDir()
+	w, err := OpenWriter(WriterConfig{Dir: dir, MaxEventsPerShard: 3})
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	appendEvent := func(tick int, agent, action string) {
+		t.Helper()
+		line := fmt.Sprintf(`{"tick":%d,"agent_id":%q,"action":%q}`, tick, agent, action)
+		if err := w.AppendJSONLine([]byte(line)); err != nil {
+			t.Fatalf("AppendJSONLine: %v", err)
+		}
+	}
+	for i := 0; i < 7; i++ {
+		agent := "a"
+		if i%2 == 1 {
+			agent = "b"
+		}
+		appendEvent(i, agent, "post")
+	}
+	appendEvent(7, "a", "heartbeat")
+
+	ticks := func(p *Page) []int {
+		t.Helper()
+		out := make([]int, 0, len(p.Events))
+		for _, ev := range p.Events {
+			var v struct {
+				Tick int `json:"tick"`
+			}
+			if err := json.Unmarshal(ev.Data, &v); err != nil {
+				t.Fatalf("decode %s: %v", ev.Data, err)
+			}
+			out = append(out, v.Tick)
+		}
+		return out
+	}
+	read := func(q Query) *Page {
+		t.Helper()
+		r, err := OpenReader(dir)
+		if err != nil {
+			t.Fatalf("OpenReader: %v", err)
+		}
+		p, err := r.Read(q)
+		if err != nil {
+			t.Fatalf("Read(%+v): %v", q, err)
+		}
+		return p
+	}
+
+	first := read(Query{Limit: 3, SkipActions: []string{"heartbeat"}})
+	if got := fmt.Sprint(ticks(first)); got != "[6 5 4]" || first.Next == "" {
+		t.Fatalf("first page = %s next=%q", got, first.Next)
+	}
+	second := read(Query{Limit: 3, Before: first.Next, SkipActions: []string{"heartbeat"}})
+	if got := fmt.Sprint(ticks(second)); got != "[3 2 1]" {
+		t.Fatalf("second page = %s", got)
+	}
+	last := read(Query{Limit: 3, Before: second.Next, SkipActions: []string{"heartbeat"}})
+	if got := fmt.Sprint(ticks(last)); got != "[0]" || last.Next != "" {
+		t.Fatalf("last page = %s next=%q", got, last.Next)
+	}
+
+	// New events don't shift the pages; After polls for them.
+	appendEvent(8, "b", "review")
+	if got := fmt.Sprint(ticks(read(Query{Limit: 3, Before: first.Next, SkipActions: []string{"heartbeat"}}))); got != "[3 2 1]" {
+		t.Fatalf("page after append = %s", got)
+	}
+	newer := read(Query{Limit: 10, After: first.Prev})
+	if got := fmt.Sprint(ticks(newer)); got != "[8 7]" {
+		t.Fatalf("newer = %s", got)
+	}
+	if again := read(Query{After: newer.Prev}); len(again.Events) != 0 || again.Prev != newer.Prev {
+		t.Fatalf("nothing newer expected, got %d events prev=%q", len(again.Events), again.Prev)
+	}
+
+	if got := fmt.Sprint(ticks(read(Query{AgentID: "b", Actions: []string{"post"}}))); got != "[5 3 1]" {
+		t.Fatalf("filtered = %s", got)
+	}
+
+	r, err := OpenReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(Query{Before: "nope"}); err == nil {
+		t.Fatalf("expected an invalid cursor error")
+	}
+}
+
+func TestReader_AgentIndexesAndTimeRange(t *testing.T) {
+	dir := t.TempDir()
+	w, err := OpenWriter(WriterConfig{Dir: dir, MaxEventsPerShard: 2})
+	if err != nil {
+		t.Fatalf("OpenWriter: %v", err)
+	}
+	// Shard 1: a, b; shard 2: b, a; shard 3: b, b.
+	for i, agent := range []string{"a", "b", "b", "a", "b", "b"} {
+		line := fmt.Sprintf(`{"tick":%d,"agent_id":%q,"action":"post","sim_time":"2026-01-0%dT00:00:00Z"}`, i, agent, i+1)
+		if err := w.AppendJSONLine([]byte(line)); err != nil {
+			t.Fatalf("AppendJSONLine: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ai, err := LoadAgentIndex(dir, "a")
+	if err != nil {
+		t.Fatalf("LoadAgentIndex: %v", err)
+	}
+	if ai.TotalEvents != 2 || len(ai.Shards) != 2 || ai.Shards[0].Seq != 1 || fmt.Sprint(ai.Shards[1].Lines) != "[1]" {
+		t.Fatalf("agent index = %+v", ai)
+	}
+
+	r, err := OpenReader(dir)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	cursors := func(q Query) string {
+		t.Helper()
+		p, err := r.Read(q)
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		out := make([]string, 0, len(p.Events))
+		for _, ev := range p.Events {
+			out = append(out, ev.Cursor)
+		}
+		return fmt.Sprint(out)
+	}
+	if got := cursors(Query{AgentID: "a"}); got != "[2:1 1:0]" {
+		t.Fatalf("agent a = %s", got)
+	}
+	since := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	if got := cursors(Query{AgentID: "b", Since: since, Until: until}); got != "[2:0]" {
+		t.Fatalf("agent b in range = %s", got)
+	}
+	if got := cursors(Query{Since: since, Until: until}); got != "[2:1 2:0]" {
+		t.Fatalf("all in range = %s", got)
+	}
+	if got := cursors(Query{AgentID: "nobody"}); got != "[]" {
+		t.Fatalf("unknown agent = %s", got)
+	}
+
+	// A store written before agent indexes gets them on the next append.
+	idx, err := LoadIndex(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx.AgentIndexes = false
+	if err := SaveIndexAtomic(filepath.Join(dir, "index.json"), idx); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, agentsDir)); err != nil {
+		t.Fatal(err)
+	}
+	w, err = OpenWriter(WriterConfig{Dir: dir, MaxEventsPerShard: 2, Append: true})
+	if err != nil {
+		t.Fatalf("OpenWriter(append): %v", err)
+	}
+	if err := w.AppendJSONLine([]byte(`{"tick":6,"agent_id":"a","action":"post","sim_time":"2026-01-07T00:00:00Z"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ai, err = LoadAgentIndex(dir, "a"); err != nil || ai.TotalEvents != 3 {
+		t.Fatalf("rebuilt agent index = %+v, %v", ai, err)
+	}
+}
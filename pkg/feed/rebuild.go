@@ -3,7 +3,6 @@ package feed
 import (
 	"bufio"
 	"encoding/json"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -61,7 +60,7 @@ func readLogs(paths []string) ([]rawEvent, error) {
 	}
 
 	for _, path := range paths {
-		f, err := os.Open(path)
+		f, err := OpenJSONL(path)
 		if err != nil {
 			continue
 		}
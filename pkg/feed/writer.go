@@ -3,6 +3,7 @@ package feed
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -21,6 +22,9 @@ type Writer struct {
 	maxEventsPerShard int
 
 	idx *Index
+	// agents are the per-agent indexes, loaded on first use.
+	agents map[string]*AgentIndex
+	resume bool
 
 	curFile   *os.File
 	curWriter *bufio.Writer
@@ -49,6 +53,8 @@ func OpenWriter(cfg WriterConfig) (*Writer, error) {
 		dir:               cfg.Dir,
 		indexPath:         filepath.Join(cfg.Dir, "index.json"),
 		maxEventsPerShard: cfg.MaxEventsPerShard,
+		agents:            make(map[string]*AgentIndex),
+		resume:            cfg.Append,
 		idx: &Index{
 			Version:           1,
 			MaxEventsPerShard: cfg.MaxEventsPerShard,
@@ -76,9 +82,47 @@ func OpenWriter(cfg WriterConfig) (*Writer, error) {
 	if err := w.openForAppend(); err != nil {
 		return nil, err
 	}
+	if !w.idx.AgentIndexes {
+		if err := w.indexAgents(); err != nil {
+			return nil, err
+		}
+	}
 	return w, nil
 }
 
+// indexAgents writes the agent indexes of the events already in the store.
+func (w *Writer) indexAgents() error {
+	if w.idx.TotalEvents > 0 {
+		w.agents = buildAgentIndexes(w.dir, w.idx.Shards)
+		for id, ai := range w.agents {
+			if err := writeJSONAtomic(AgentIndexPath(w.dir, id), ai); err != nil {
+				return err
+			}
+		}
+	}
+	w.idx.AgentIndexes = true
+	return SaveIndexAtomic(w.indexPath, w.idx)
+}
+
+// agentIndex returns the index of an agent, or nil if the ID can't be
+// indexed.
+func (w *Writer) agentIndex(agentID string) *AgentIndex {
+	if ai, ok := w.agents[agentID]; ok {
+		return ai
+	}
+	if !safeAgentID(agentID) {
+		return nil
+	}
+	ai := &AgentIndex{AgentID: agentID}
+	if w.resume {
+		if loaded, err := LoadAgentIndex(w.dir, agentID); err == nil {
+			ai = loaded
+		}
+	}
+	w.agents[agentID] = ai
+	return ai
+}
+
 func (w *Writer) openForAppend() error {
 	last := w.lastShard()
 	if last != nil {
@@ -121,7 +165,7 @@ func (w *Writer) openForAppend() error {
 }
 
 func countLines(path string) int {
-	f, err := os.Open(path)
+	f, err := OpenJSONL(path)
 	if err != nil {
 		return 0
 	}
@@ -194,7 +238,8 @@ func rebuildIndexFromDisk(dir string, maxEventsPerShard int) *Index {
 }
 
 func parseShardSeq(name string) int {
-	// events-000123.jsonl
+	// events-000123.jsonl, or events-000123.jsonl.gz once compacted
+	name = strings.TrimSuffix(name, GzipExt)
 	if !strings.HasPrefix(name, "events-") || !strings.HasSuffix(name, ".jsonl") {
 		return 0
 	}
@@ -292,20 +337,47 @@ func (w *Writer) AppendJSONLine(line []byte) error {
 		return err
 	}
 
+	lineNo := w.curEvents
 	w.curEvents++
 	w.idx.TotalEvents++
+	var shard Shard
 	for i := range w.idx.Shards {
 		if w.idx.Shards[i].Seq == w.curSeq {
 			w.idx.Shards[i].Events = w.curEvents
+			shard = w.idx.Shards[i]
 			break
 		}
 	}
 	if err := SaveIndexAtomic(w.indexPath, w.idx); err != nil {
 		return err
 	}
+
+	var k eventKey
+	if err := json.Unmarshal(trimmed, &k); err != nil {
+		return nil
+	}
+	if ai := w.agentIndex(k.AgentID); ai != nil {
+		ai.add(shard, lineNo, k.at())
+		return writeJSONAtomic(AgentIndexPath(w.dir, k.AgentID), ai)
+	}
 	return nil
 }
 
+// SetSourceOffsets records how far each named log has been indexed (see
+// Index.Sources) and saves the index.
+func (w *Writer) SetSourceOffsets(offsets map[string]int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.idx.Sources == nil {
+		w.idx.Sources = make(map[string]int64, len(offsets))
+	}
+	for name, off := range offsets {
+		w.idx.Sources[name] = off
+	}
+	return SaveIndexAtomic(w.indexPath, w.idx)
+}
+
 func (w *Writer) Close() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
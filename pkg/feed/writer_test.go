@@ -25,6 +25,9 @@ func TestWriter_RotationAndResume(t *testing.T) {
 			t.Fatalf("AppendJSONLine(%d): %v", i, err)
 		}
 	}
+	if err := w.SetSourceOffsets(map[string]int64{"logs.jsonl": 1234}); err != nil {
+		t.Fatalf("SetSourceOffsets: %v", err)
+	}
 	if err := w.Close(); err != nil {
 		t.Fatalf("Close: %v", err)
 	}
@@ -86,4 +89,7 @@ func TestWriter_RotationAndResume(t *testing.T) {
 	if idx2.Shards[2].Events != 3 {
 		t.Fatalf("shard3 events=%d, want 3", idx2.Shards[2].Events)
 	}
+	if idx2.Sources["logs.jsonl"] != 1234 {
+		t.Fatalf("Sources(resume)=%v, want logs.jsonl at 1234", idx2.Sources)
+	}
 }
@@ -0,0 +1,309 @@
+// Package group keeps research groups: coalitions of agents with a shared
+// topic, a members-only channel and drafts they work on together.
+package group
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits keep groups small enough to coordinate and channels bounded.
+const (
+	MaxMembers        = 8
+	MaxMessages       = 200 // channel messages kept per group
+	MaxGroupsPerAgent = 3
+)
+
+// ErrNotMember is returned for channel and draft operations by non-members.
+var ErrNotMember = errors.New("not a member of this group")
+
+// Message is one post in a group channel.
+type Message struct {
+	AuthorID   string    `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	Content    string    `json:"content"`
+	At         time.Time `json:"at"` // sim time
+}
+
+// Member is one agent in a group.
+type Member struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	JoinedAt time.Time `json:"joined_at"` // sim time
+}
+
+// Group is a research group.
+type Group struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Topic     string    `json:"topic"`
+	FounderID string    `json:"founder_id"`
+	Members   []Member  `json:"members"`
+	CreatedAt time.Time `json:"created_at"` // sim time
+	// Drafts are workflow draft IDs shared with the group.
+	Drafts  []string  `json:"drafts,omitempty"`
+	Channel []Message `json:"channel,omitempty"` // oldest -> newest, capped
+	// MessageCount counts every message ever posted, including those
+	// dropped from Channel.
+	MessageCount int `json:"message_count"`
+}
+
+// HasMember reports whether the agent belongs to the group.
+func (g *Group) HasMember(agentID string) bool {
+	for _, m := range g.Members {
+		if m.ID == agentID {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Group) clone() Group {
+	c := *g
+	c.Members = append([]Member(nil), g.Members...)
+	c.Drafts = append([]string(nil), g.Drafts...)
+	c.Channel = append([]Message(nil), g.Channel...)
+	return c
+}
+
+// Store keeps groups in groups.json under dataPath.
+type Store struct {
+	mu       sync.RWMutex
+	dataPath string
+	groups   map[string]*Group
+	nextID   int
+}
+
+// NewStore creates a group store.
+func NewStore(dataPath string) *Store {
+	return &Store{dataPath: dataPath, groups: make(map[string]*Group)}
+}
+
+// Create founds a group with its founder as the first member.
+func (s *Store) Create(name, topic string, founder Member) (Group, error) {
+	name, topic = strings.TrimSpace(name), strings.TrimSpace(topic)
+	if name == "" {
+		return Group{}, fmt.Errorf("group name is required")
+	}
+	s.mu.Lock()
+	for _, g := range s.groups {
+		if strings.EqualFold(g.Name, name) {
+			s.mu.Unlock()
+			return Group{}, fmt.Errorf("group %q already exists (%s); join it instead", name, g.ID)
+		}
+	}
+	if n := s.countForLocked(founder.ID); n >= MaxGroupsPerAgent {
+		s.mu.Unlock()
+		return Group{}, fmt.Errorf("already in %d groups (max %d)", n, MaxGroupsPerAgent)
+	}
+	s.nextID++
+	g := &Group{
+		ID:        fmt.Sprintf("group-%d", s.nextID),
+		Name:      name,
+		Topic:     topic,
+		FounderID: founder.ID,
+		Members:   []Member{founder},
+		CreatedAt: founder.JoinedAt,
+	}
+	for s.groups[g.ID] != nil {
+		s.nextID++
+		g.ID = fmt.Sprintf("group-%d", s.nextID)
+	}
+	s.groups[g.ID] = g
+	out := g.clone()
+	s.mu.Unlock()
+	return out, s.Save()
+}
+
+// Join adds an agent to a group.
+func (s *Store) Join(groupID string, m Member) (Group, error) {
+	s.mu.Lock()
+	g, ok := s.groups[groupID]
+	switch {
+	case !ok:
+		s.mu.Unlock()
+		return Group{}, fmt.Errorf("group not found: %s", groupID)
+	case g.HasMember(m.ID):
+		out := g.clone()
+		s.mu.Unlock()
+		return out, nil
+	case len(g.Members) >= MaxMembers:
+		s.mu.Unlock()
+		return Group{}, fmt.Errorf("group %s is full (%d members)", groupID, MaxMembers)
+	case s.countForLocked(m.ID) >= MaxGroupsPerAgent:
+		s.mu.Unlock()
+		return Group{}, fmt.Errorf("already in %d groups (max %d)", MaxGroupsPerAgent, MaxGroupsPerAgent)
+	}
+	g.Members = append(g.Members, m)
+	out := g.clone()
+	s.mu.Unlock()
+	return out, s.Save()
+}
+
+// Leave removes an agent from a group. The group and its channel remain
+// when the last member leaves.
+func (s *Store) Leave(groupID, agentID string) error {
+	s.mu.Lock()
+	g, ok := s.groups[groupID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("group not found: %s", groupID)
+	}
+	kept := g.Members[:0]
+	for _, m := range g.Members {
+		if m.ID != agentID {
+			kept = append(kept, m)
+		}
+	}
+	if len(kept) == len(g.Members) {
+		s.mu.Unlock()
+		return ErrNotMember
+	}
+	g.Members = kept
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// Post appends a message to a group channel.
+func (s *Store) Post(groupID string, msg Message) error {
+	msg.Content = strings.TrimSpace(msg.Content)
+	if msg.Content == "" {
+		return fmt.Errorf("message content is required")
+	}
+	s.mu.Lock()
+	g, err := s.memberGroupLocked(groupID, msg.AuthorID)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	g.Channel = append(g.Channel, msg)
+	if len(g.Channel) > MaxMessages {
+		g.Channel = g.Channel[len(g.Channel)-MaxMessages:]
+	}
+	g.MessageCount++
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// ShareDraft shares a workflow draft with a group.
+func (s *Store) ShareDraft(groupID, agentID, draftID string) error {
+	s.mu.Lock()
+	g, err := s.memberGroupLocked(groupID, agentID)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	for _, id := range g.Drafts {
+		if id == draftID {
+			s.mu.Unlock()
+			return nil
+		}
+	}
+	g.Drafts = append(g.Drafts, draftID)
+	s.mu.Unlock()
+	return s.Save()
+}
+
+func (s *Store) memberGroupLocked(groupID, agentID string) (*Group, error) {
+	g, ok := s.groups[groupID]
+	if !ok {
+		return nil, fmt.Errorf("group not found: %s", groupID)
+	}
+	if !g.HasMember(agentID) {
+		return nil, ErrNotMember
+	}
+	return g, nil
+}
+
+func (s *Store) countForLocked(agentID string) int {
+	n := 0
+	for _, g := range s.groups {
+		if g.HasMember(agentID) {
+			n++
+		}
+	}
+	return n
+}
+
+// Get returns a copy of a group.
+func (s *Store) Get(id string) (Group, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g, ok := s.groups[id]
+	if !ok {
+		return Group{}, fmt.Errorf("group not found: %s", id)
+	}
+	return g.clone(), nil
+}
+
+// List returns all groups (or only one agent's when agentID is set),
+// largest first.
+func (s *Store) List(agentID string) []Group {
+	s.mu.RLock()
+	out := make([]Group, 0, len(s.groups))
+	for _, g := range s.groups {
+		if agentID == "" || g.HasMember(agentID) {
+			out = append(out, g.clone())
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Members) != len(out[j].Members) {
+			return len(out[i].Members) > len(out[j].Members)
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out
+}
+
+type storeFile struct {
+	NextID int               `json:"next_id"`
+	Groups map[string]*Group `json:"groups"`
+}
+
+// Save writes all groups to disk.
+func (s *Store) Save() error {
+	if s.dataPath == "" {
+		return nil
+	}
+	s.mu.RLock()
+	data, err := json.MarshalIndent(storeFile{NextID: s.nextID, Groups: s.groups}, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dataPath, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dataPath, "groups.json"), data, 0644)
+}
+
+// Load reads groups from disk.
+func (s *Store) Load() error {
+	data, err := os.ReadFile(filepath.Join(s.dataPath, "groups.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	if file.Groups == nil {
+		file.Groups = make(map[string]*Group)
+	}
+	s.mu.Lock()
+	s.groups = file.Groups
+	s.nextID = file.NextID
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,99 @@
+package group
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStore_GroupLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	at := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	g, err := store.Create("Dark Sector", "dark matter models", Member{ID: "a", Name: "Ada", JoinedAt: at})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := store.Create("dark sector", "dup", Member{ID: "b", Name: "Bo"}); err == nil {
+		t.Fatalf("expected duplicate name error")
+	}
+	if _, err := store.Join(g.ID, Member{ID: "b", Name: "Bo", JoinedAt: at}); err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	if err := store.Post(g.ID, Message{AuthorID: "c", Content: "hi"}); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected non-member post to fail, got %v", err)
+	}
+	if err := store.Post(g.ID, Message{AuthorID: "b", AuthorName: "Bo", Content: "I'll take the simulations", At: at}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if err := store.ShareDraft(g.ID, "a", "draft-1"); err != nil {
+		t.Fatalf("ShareDraft: %v", err)
+	}
+	if err := store.ShareDraft(g.ID, "a", "draft-1"); err != nil {
+		t.Fatalf("ShareDraft again: %v", err)
+	}
+
+	loaded := NewStore(dir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got, err := loaded.Get(g.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(got.Members) != 2 || len(got.Drafts) != 1 || got.MessageCount != 1 || got.Channel[0].Content != "I'll take the simulations" {
+		t.Fatalf("unexpected group after reload: %+v", got)
+	}
+	if mine := loaded.List("b"); len(mine) != 1 || mine[0].ID != g.ID {
+		t.Fatalf("expected b's group, got %+v", mine)
+	}
+	// IDs keep counting after a reload.
+	next, err := loaded.Create("Other", "topic", Member{ID: "c", Name: "Cy"})
+	if err != nil || next.ID == g.ID {
+		t.Fatalf("expected a fresh id, got %q (%v)", next.ID, err)
+	}
+
+	if err := loaded.Leave(g.ID, "b"); err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	if err := loaded.Leave(g.ID, "b"); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected ErrNotMember, got %v", err)
+	}
+}
+
+func TestStore_Limits(t *testing.T) {
+	store := NewStore("")
+	g, err := store.Create("Big", "topic", Member{ID: "m0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < MaxMembers; i++ {
+		if _, err := store.Join(g.ID, Member{ID: fmt.Sprintf("m%d", i)}); err != nil {
+			t.Fatalf("Join %d: %v", i, err)
+		}
+	}
+	if _, err := store.Join(g.ID, Member{ID: "late"}); err == nil {
+		t.Fatalf("expected full group error")
+	}
+
+	for i := 1; i < MaxGroupsPerAgent; i++ {
+		if _, err := store.Create(fmt.Sprintf("G%d", i), "topic", Member{ID: "m0"}); err != nil {
+			t.Fatalf("Create %d: %v", i, err)
+		}
+	}
+	if _, err := store.Create("One too many", "topic", Member{ID: "m0"}); err == nil {
+		t.Fatalf("expected per-agent group limit")
+	}
+
+	for i := 0; i < MaxMessages+5; i++ {
+		if err := store.Post(g.ID, Message{AuthorID: "m1", Content: fmt.Sprintf("msg %d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, _ := store.Get(g.ID)
+	if len(got.Channel) != MaxMessages || got.MessageCount != MaxMessages+5 || got.Channel[0].Content != "msg 5" {
+		t.Fatalf("unexpected channel: %d kept, count %d, first %q", len(got.Channel), got.MessageCount, got.Channel[0].Content)
+	}
+}
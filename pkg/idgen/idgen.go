@@ -0,0 +1,116 @@
+// Package idgen generates the IDs of stored objects: forum posts, papers,
+// workflow records, reports and the like. An ID is a kind prefix and a ULID,
+// e.g. "forum-01JAAKQ8ZK9V3N1X6WQ2C4R7TB": 48 bits of millisecond time and
+// 80 random bits in Crockford base32. IDs from one process sort in creation
+// order, and concurrent or forked runs don't collide. Older runs used the
+// creation time in Unix nanoseconds ("forum-1736899200000000000"); Time
+// reads both forms.
+package idgen
+
+import (
+	"crypto/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Suffix is a regexp fragment matching the part of an ID after the prefix,
+// in either the ULID or the legacy form.
+const Suffix = `(?:[0-9A-HJKMNP-TV-Z]{26}|[0-9]+)`
+
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	mu      sync.Mutex
+	lastMS  uint64
+	lastRnd [10]byte
+)
+
+// New returns a new ID with the given prefix.
+func New(prefix string) string {
+	return prefix + "-" + ulid(time.Now())
+}
+
+// ulid encodes t and 80 random bits. Within one millisecond the random part
+// is incremented instead of redrawn, so IDs stay ordered.
+func ulid(t time.Time) string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	ms := uint64(t.UnixMilli())
+	if ms <= lastMS {
+		ms = lastMS
+		if !increment(&lastRnd) {
+			ms++ // 2^80 IDs in one millisecond: borrow the next one
+		}
+	} else if _, err := rand.Read(lastRnd[:]); err != nil {
+		panic("idgen: " + err.Error())
+	}
+	lastMS = ms
+
+	var b [16]byte
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	copy(b[6:], lastRnd[:])
+	return encode(b)
+}
+
+// increment adds one to a big-endian number, reporting false on overflow.
+func increment(b *[10]byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encode writes 128 bits as 26 base32 digits; the first digit holds the top
+// 3 bits.
+func encode(b [16]byte) string {
+	var out [26]byte
+	for i := range out {
+		v := 0
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+			v <<= 1
+			if bit >= 0 && b[bit/8]&(0x80>>(bit%8)) != 0 {
+				v |= 1
+			}
+		}
+		out[i] = alphabet[v]
+	}
+	return string(out[:])
+}
+
+// Time returns when an ID was generated. It reports false for IDs that
+// carry no time, such as seed content or hand-picked IDs.
+func Time(id string) (time.Time, bool) {
+	i := strings.LastIndexByte(id, '-')
+	if i < 0 {
+		return time.Time{}, false
+	}
+	suffix := id[i+1:]
+	if len(suffix) == 26 {
+		var ms uint64
+		for _, c := range suffix[:10] {
+			d := strings.IndexRune(alphabet, c)
+			if d < 0 {
+				return time.Time{}, false
+			}
+			ms = ms<<5 | uint64(d)
+		}
+		return time.UnixMilli(int64(ms)), true
+	}
+	// Legacy IDs: Unix nanoseconds.
+	if len(suffix) < 10 {
+		return time.Time{}, false
+	}
+	ns, err := strconv.ParseInt(suffix, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, ns), true
+}
@@ -0,0 +1,63 @@
+package idgen
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNew_UniqueAndOrderedUnderConcurrency(t *testing.T) {
+	const workers, per = 8, 500
+	var mu sync.Mutex
+	var ids []string
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := make([]string, 0, per)
+			for i := 0; i < per; i++ {
+				local = append(local, New("forum"))
+			}
+			if !sort.StringsAreSorted(local) {
+				t.Error("IDs from one goroutine are not in creation order")
+			}
+			mu.Lock()
+			ids = append(ids, local...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, len(ids))
+	pattern := regexp.MustCompile(`^forum-` + Suffix + `$`)
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID %s", id)
+		}
+		seen[id] = true
+		if !pattern.MatchString(id) || len(id) != len("forum-")+26 {
+			t.Fatalf("unexpected ID form %q", id)
+		}
+	}
+}
+
+func TestTime_ReadsNewAndLegacyIDs(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	got, ok := Time(New("journal"))
+	if !ok || got.Before(before) || got.After(time.Now()) {
+		t.Fatalf("Time(new ID) = %v, %v; want about %v", got, ok, before)
+	}
+
+	legacy := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	if got, ok := Time("comment-1736899200000000000"); !ok || !got.Equal(legacy) {
+		t.Fatalf("Time(legacy ID) = %v, %v", got, ok)
+	}
+	for _, id := range []string{"seed-1", "goal-3", "draft", "forum-not-an-id"} {
+		if _, ok := Time(id); ok {
+			t.Errorf("Time(%q) reported a time", id)
+		}
+	}
+}
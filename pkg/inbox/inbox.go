@@ -0,0 +1,426 @@
+// Package inbox is the spectator question queue: people watching a run ask
+// the community questions through the server, moderators approve them, and
+// the scheduler posts the most-voted approved questions to the forum on
+// behalf of a visitor account.
+//
+// The server and the simulation are separate processes, so the queue is an
+// append-only log (inbox.jsonl under the data root) that both replay: each
+// change is one line, and Reload picks up the lines the other process
+// appended since.
+package inbox
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/idgen"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// FileName is the inbox log under the data root.
+const FileName = "inbox.jsonl"
+
+// VisitorID is the author of posted questions. It is in the human
+// namespace, so it can't collide with an agent.
+const VisitorID = types.HumanAuthorPrefix + "visitor"
+
+// Limits on what one spectator can put in the queue.
+const (
+	MaxTitle    = 200  // runes
+	MaxQuestion = 4000 // runes
+	MaxName     = 40   // runes
+	// MaxOpenPerVisitor caps a spectator's questions awaiting moderation or
+	// posting.
+	MaxOpenPerVisitor = 3
+)
+
+// Status is where a question is in the queue.
+type Status string
+
+const (
+	Pending  Status = "pending"  // awaiting moderation
+	Approved Status = "approved" // open for votes, waiting to be posted
+	Rejected Status = "rejected"
+	Posted   Status = "posted"
+)
+
+// ErrNotFound is returned for unknown question IDs.
+var ErrNotFound = errors.New("question not found")
+
+// Question is one spectator question.
+type Question struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Question  string          `json:"question"`
+	Subreddit types.Subreddit `json:"subreddit,omitempty"` // empty: general
+	AskerName string          `json:"asker_name,omitempty"`
+	Status    Status          `json:"status"`
+	Votes     int             `json:"votes"`
+	AskedAt   time.Time       `json:"asked_at"`
+
+	ModeratedBy string    `json:"moderated_by,omitempty"`
+	ModeratedAt time.Time `json:"moderated_at,omitzero"`
+	Reason      string    `json:"reason,omitempty"` // given on rejection
+
+	// PostID is the forum thread the question became; PostedAt is the sim
+	// time it was posted.
+	PostID   string    `json:"post_id,omitempty"`
+	PostedAt time.Time `json:"posted_at,omitzero"`
+
+	asker  string // visitor key
+	voters map[string]bool
+}
+
+func (q *Question) open() bool { return q.Status == Pending || q.Status == Approved }
+
+// record is one line of the log.
+type record struct {
+	Op      string    `json:"op"` // ask | vote | moderate | post
+	Time    time.Time `json:"time"`
+	ID      string    `json:"id"`
+	Visitor string    `json:"visitor,omitempty"` // asker or voter key
+
+	Title     string          `json:"title,omitempty"` // ask
+	Question  string          `json:"question,omitempty"`
+	Subreddit types.Subreddit `json:"subreddit,omitempty"`
+	Name      string          `json:"name,omitempty"`
+
+	Approve   bool   `json:"approve,omitempty"` // moderate
+	Moderator string `json:"moderator,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+
+	PostID  string    `json:"post_id,omitempty"` // post
+	SimTime time.Time `json:"sim_time,omitzero"`
+}
+
+// Inbox is the question queue backed by a log file.
+type Inbox struct {
+	mu        sync.Mutex
+	path      string
+	offset    int64 // bytes of the log replayed so far
+	questions map[string]*Question
+}
+
+// Open replays the log at path. A missing file is an empty inbox.
+func Open(path string) (*Inbox, error) {
+	b := &Inbox{path: path, questions: make(map[string]*Question)}
+	if err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// VisitorKey derives the key a spectator is known by from the address the
+// server sees them connect from, so the log never holds the address itself.
+func VisitorKey(secret string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(secret)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Reload applies the lines appended to the log since the last replay.
+func (b *Inbox) Reload() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reloadLocked()
+}
+
+func (b *Inbox) reloadLocked() error {
+	f, err := os.Open(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(b.offset, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial last line is still being written; read it next time.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		b.offset += int64(len(line))
+		var rec record
+		if json.Unmarshal(bytes.TrimSpace(line), &rec) == nil {
+			b.apply(rec)
+		}
+	}
+}
+
+// apply folds one record into the state. Records that no longer apply,
+// such as a second vote by the same visitor, are ignored.
+func (b *Inbox) apply(rec record) {
+	if rec.Op == "ask" {
+		if rec.ID == "" || b.questions[rec.ID] != nil {
+			return
+		}
+		b.questions[rec.ID] = &Question{
+			ID:        rec.ID,
+			Title:     rec.Title,
+			Question:  rec.Question,
+			Subreddit: rec.Subreddit,
+			AskerName: rec.Name,
+			Status:    Pending,
+			AskedAt:   rec.Time,
+			asker:     rec.Visitor,
+			voters:    make(map[string]bool),
+		}
+		return
+	}
+	q := b.questions[rec.ID]
+	if q == nil {
+		return
+	}
+	switch rec.Op {
+	case "vote":
+		if q.Status == Approved && rec.Visitor != "" && !q.voters[rec.Visitor] {
+			q.voters[rec.Visitor] = true
+			q.Votes++
+		}
+	case "moderate":
+		if q.Status != Pending {
+			return
+		}
+		q.Status = Rejected
+		if rec.Approve {
+			q.Status = Approved
+		}
+		q.ModeratedBy, q.ModeratedAt, q.Reason = rec.Moderator, rec.Time, rec.Reason
+	case "post":
+		if q.Status == Approved {
+			q.Status, q.PostID, q.PostedAt = Posted, rec.PostID, rec.SimTime
+		}
+	}
+}
+
+// commitLocked appends rec to the log and applies it. The log is reopened
+// for each record so another process's appends interleave line by line.
+func (b *Inbox) commitLocked(rec record) error {
+	rec.Time = time.Now()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	// Replay rather than apply directly, so lines the other process wrote
+	// first are applied first.
+	return b.reloadLocked()
+}
+
+// Ask queues a question for moderation. visitor is the asker's key (see
+// VisitorKey); a visitor may have MaxOpenPerVisitor questions open at once.
+func (b *Inbox) Ask(title, question, subreddit, name, visitor string) (Question, error) {
+	title, question, name = strings.TrimSpace(title), strings.TrimSpace(question), strings.TrimSpace(name)
+	switch {
+	case title == "" || question == "":
+		return Question{}, fmt.Errorf("title and question are required")
+	case len([]rune(title)) > MaxTitle:
+		return Question{}, fmt.Errorf("title is longer than %d characters", MaxTitle)
+	case len([]rune(question)) > MaxQuestion:
+		return Question{}, fmt.Errorf("question is longer than %d characters", MaxQuestion)
+	case len([]rune(name)) > MaxName:
+		return Question{}, fmt.Errorf("name is longer than %d characters", MaxName)
+	case visitor == "":
+		return Question{}, fmt.Errorf("missing visitor key")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.reloadLocked(); err != nil {
+		return Question{}, err
+	}
+	open := 0
+	for _, q := range b.questions {
+		if q.asker == visitor && q.open() {
+			open++
+		}
+	}
+	if open >= MaxOpenPerVisitor {
+		return Question{}, fmt.Errorf("you already have %d questions waiting; wait until one is posted", open)
+	}
+	id := idgen.New("question")
+	rec := record{
+		Op:        "ask",
+		ID:        id,
+		Visitor:   visitor,
+		Title:     title,
+		Question:  question,
+		Subreddit: types.Subreddit(strings.ToLower(strings.TrimSpace(subreddit))),
+		Name:      name,
+	}
+	if err := b.commitLocked(rec); err != nil {
+		return Question{}, err
+	}
+	return b.copyLocked(id), nil
+}
+
+// Vote adds visitor's vote to an approved question. Voting twice is a no-op.
+func (b *Inbox) Vote(id, visitor string) (Question, error) {
+	if visitor == "" {
+		return Question{}, fmt.Errorf("missing visitor key")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.reloadLocked(); err != nil {
+		return Question{}, err
+	}
+	q := b.questions[id]
+	switch {
+	case q == nil:
+		return Question{}, ErrNotFound
+	case q.Status != Approved:
+		return Question{}, fmt.Errorf("question %s is %s, not open for votes", id, q.Status)
+	case q.voters[visitor]:
+		return b.copyLocked(id), nil
+	}
+	if err := b.commitLocked(record{Op: "vote", ID: id, Visitor: visitor}); err != nil {
+		return Question{}, err
+	}
+	return b.copyLocked(id), nil
+}
+
+// Moderate approves or rejects a question awaiting moderation.
+func (b *Inbox) Moderate(id string, approve bool, moderator, reason string) (Question, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.reloadLocked(); err != nil {
+		return Question{}, err
+	}
+	q := b.questions[id]
+	switch {
+	case q == nil:
+		return Question{}, ErrNotFound
+	case q.Status != Pending:
+		return Question{}, fmt.Errorf("question %s is already %s", id, q.Status)
+	}
+	rec := record{Op: "moderate", ID: id, Approve: approve, Moderator: moderator, Reason: strings.TrimSpace(reason)}
+	if err := b.commitLocked(rec); err != nil {
+		return Question{}, err
+	}
+	return b.copyLocked(id), nil
+}
+
+// MarkPosted records that an approved question became forum thread postID
+// at sim time simTime.
+func (b *Inbox) MarkPosted(id, postID string, simTime time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.reloadLocked(); err != nil {
+		return err
+	}
+	q := b.questions[id]
+	switch {
+	case q == nil:
+		return ErrNotFound
+	case q.Status != Approved:
+		return fmt.Errorf("question %s is %s, not approved", id, q.Status)
+	}
+	return b.commitLocked(record{Op: "post", ID: id, PostID: postID, SimTime: simTime})
+}
+
+// Get returns a question by ID.
+func (b *Inbox) Get(id string) (Question, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.questions[id] == nil {
+		return Question{}, false
+	}
+	return b.copyLocked(id), true
+}
+
+// List returns the questions with the given status (all when empty):
+// approved ones most-voted first, the others newest first.
+func (b *Inbox) List(status Status) []Question {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]Question, 0)
+	for id, q := range b.questions {
+		if status == "" || q.Status == status {
+			out = append(out, b.copyLocked(id))
+		}
+	}
+	if status == Approved {
+		sortByVotes(out)
+	} else {
+		sort.Slice(out, func(i, j int) bool {
+			if !out[i].AskedAt.Equal(out[j].AskedAt) {
+				return out[i].AskedAt.After(out[j].AskedAt)
+			}
+			return out[i].ID > out[j].ID
+		})
+	}
+	return out
+}
+
+// Next returns up to n approved questions to post: most votes first, then
+// the oldest.
+func (b *Inbox) Next(n int) []Question {
+	out := b.List(Approved)
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// LastPosted returns the sim time the latest question was posted, or zero.
+func (b *Inbox) LastPosted() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var last time.Time
+	for _, q := range b.questions {
+		if q.Status == Posted && q.PostedAt.After(last) {
+			last = q.PostedAt
+		}
+	}
+	return last
+}
+
+func (b *Inbox) copyLocked(id string) Question {
+	q := *b.questions[id]
+	q.asker, q.voters = "", nil
+	return q
+}
+
+func sortByVotes(qs []Question) {
+	sort.Slice(qs, func(i, j int) bool {
+		if qs[i].Votes != qs[j].Votes {
+			return qs[i].Votes > qs[j].Votes
+		}
+		if !qs[i].AskedAt.Equal(qs[j].AskedAt) {
+			return qs[i].AskedAt.Before(qs[j].AskedAt)
+		}
+		return qs[i].ID < qs[j].ID
+	})
+}
@@ -0,0 +1,84 @@
+package inbox
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInbox_QueueAcrossProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	server, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	alice, bob := VisitorKey("alice-secret"), VisitorKey("bob-secret")
+
+	first, err := server.Ask("Is the aether real?", "What would falsify it?", "Physics", "Alice", alice)
+	if err != nil {
+		t.Fatalf("Ask: %v", err)
+	}
+	second, _ := server.Ask("Why do cells age?", "Telomeres or something else?", "", "", alice)
+	if first.Status != Pending || first.Subreddit != "physics" {
+		t.Fatalf("asked question = %+v", first)
+	}
+	if _, err := server.Vote(first.ID, bob); err == nil {
+		t.Fatalf("voting on an unmoderated question succeeded")
+	}
+	for _, id := range []string{first.ID, second.ID} {
+		if _, err := server.Moderate(id, true, "human-mod", ""); err != nil {
+			t.Fatalf("Moderate: %v", err)
+		}
+	}
+	server.Vote(second.ID, bob)
+	server.Vote(second.ID, bob) // counted once
+	if q, _ := server.Get(second.ID); q.Votes != 1 {
+		t.Fatalf("votes = %d, want 1", q.Votes)
+	}
+
+	// The scheduler replays the same log and posts the most-voted question.
+	sched, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	next := sched.Next(1)
+	if len(next) != 1 || next[0].ID != second.ID {
+		t.Fatalf("Next = %+v, want the voted question", next)
+	}
+	at := time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC)
+	if err := sched.MarkPosted(second.ID, "forum-1", at); err != nil {
+		t.Fatalf("MarkPosted: %v", err)
+	}
+	if err := server.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if q, _ := server.Get(second.ID); q.Status != Posted || q.PostID != "forum-1" || !server.LastPosted().Equal(at) {
+		t.Fatalf("after posting: %+v", q)
+	}
+	if _, err := server.Vote(second.ID, alice); err == nil {
+		t.Fatalf("voting on a posted question succeeded")
+	}
+}
+
+func TestInbox_LimitsOpenQuestionsPerVisitor(t *testing.T) {
+	b, err := Open(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	key := VisitorKey("carol")
+	var last Question
+	for i := 0; i < MaxOpenPerVisitor; i++ {
+		if last, err = b.Ask("Q", "Why?", "", "", key); err != nil {
+			t.Fatalf("Ask %d: %v", i, err)
+		}
+	}
+	if _, err := b.Ask("Q", "Why?", "", "", key); err == nil {
+		t.Fatalf("asking past the limit succeeded")
+	}
+	if _, err := b.Moderate(last.ID, false, "human-mod", "off topic"); err != nil {
+		t.Fatalf("Moderate: %v", err)
+	}
+	if _, err := b.Ask("Q", "Why?", "", "", key); err != nil {
+		t.Fatalf("Ask after a rejection: %v", err)
+	}
+}
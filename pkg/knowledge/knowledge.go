@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -129,6 +130,70 @@ func (r *AxiomRegistry) Load() error {
 	return nil
 }
 
+// DefaultAxiomSystems returns the built-in axiom systems every knowledge
+// store starts with.
+func DefaultAxiomSystems() []*types.AxiomSystem {
+	now := time.Now()
+	return []*types.AxiomSystem{
+		{
+			ID:          "euclidean-geometry",
+			Name:        "欧几里得几何公理体系",
+			Description: "经典平面几何的公理体系",
+			Axioms: []types.Axiom{
+				{ID: "e1", Statement: "两点确定一条直线"},
+				{ID: "e2", Statement: "线段可以无限延长"},
+				{ID: "e3", Statement: "以任意点为圆心、任意长度为半径可以画圆"},
+				{ID: "e4", Statement: "所有直角都相等"},
+				{ID: "e5", Statement: "平行公设：过直线外一点有且仅有一条平行线"},
+			},
+			CreatedBy: "system",
+			CreatedAt: now,
+		},
+		{
+			ID:          "hyperbolic-geometry",
+			Name:        "双曲几何公理体系",
+			Description: "罗巴切夫斯基几何，否定第五公设",
+			Axioms: []types.Axiom{
+				{ID: "h1", Statement: "两点确定一条直线"},
+				{ID: "h2", Statement: "线段可以无限延长"},
+				{ID: "h3", Statement: "以任意点为圆心、任意长度为半径可以画圆"},
+				{ID: "h4", Statement: "所有直角都相等"},
+				{ID: "h5", Statement: "过直线外一点有无穷多条平行线"},
+			},
+			Parent:      "euclidean-geometry",
+			Differences: []string{"修改第五公设：允许无穷多平行线"},
+			CreatedBy:   "system",
+			CreatedAt:   now,
+		},
+		{
+			ID:          "zfc-set-theory",
+			Name:        "ZFC 集合论公理体系",
+			Description: "策梅洛-弗兰克尔集合论（含选择公理）",
+			Axioms: []types.Axiom{
+				{ID: "zfc1", Statement: "外延公理：两个集合相等当且仅当它们有相同的元素"},
+				{ID: "zfc2", Statement: "空集公理：存在一个不包含任何元素的集合"},
+				{ID: "zfc3", Statement: "配对公理：对于任意两个集合，存在一个恰好包含它们作为元素的集合"},
+			},
+			CreatedBy: "system",
+			CreatedAt: now,
+		},
+	}
+}
+
+// RegisterDefaults registers the built-in axiom systems that are not
+// registered yet.
+func (r *AxiomRegistry) RegisterDefaults() error {
+	for _, system := range DefaultAxiomSystems() {
+		if _, err := r.Get(system.ID); err == nil {
+			continue
+		}
+		if err := r.Register(system); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // TheoryRepository manages theories.
 type TheoryRepository struct {
 	mu sync.RWMutex
@@ -243,17 +308,8 @@ func (r *TheoryRepository) matchesQuery(t *types.Theory, q TheoryQuery) bool {
 	if q.Status != "" && t.Status != q.Status {
 		return false
 	}
-	if q.AuthorID != "" {
-		found := false
-		for _, a := range t.Authors {
-			if a == q.AuthorID {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return false
-		}
+	if q.AuthorID != "" && !containsString(t.Authors, q.AuthorID) {
+		return false
 	}
 	if q.IsHeretical != nil && t.IsHeretical != *q.IsHeretical {
 		return false
@@ -264,6 +320,103 @@ func (r *TheoryRepository) matchesQuery(t *types.Theory, q TheoryQuery) bool {
 	return true
 }
 
+// Snapshot returns a copy of a theory that is safe to read while other
+// goroutines update the repository.
+func (r *TheoryRepository) Snapshot(id string) (types.Theory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	theory, ok := r.theories[id]
+	if !ok {
+		return types.Theory{}, fmt.Errorf("theory not found: %s", id)
+	}
+	return *theory, nil
+}
+
+// AddTheorem appends a theorem derived by authorID. Every reference must name
+// an axiom of the theory's axiom system, one of its custom axioms, or an
+// earlier theorem of the same theory; the author joins the theory's authors.
+func (r *TheoryRepository) AddTheorem(theoryID, authorID string, theorem types.Theorem) (*types.Theorem, error) {
+	if strings.TrimSpace(theorem.Statement) == "" {
+		return nil, fmt.Errorf("theorem statement is required")
+	}
+	if len(theorem.References) == 0 {
+		return nil, fmt.Errorf("theorem must reference the axioms or theorems it is derived from")
+	}
+
+	var added types.Theorem
+	err := r.Update(theoryID, func(t *types.Theory) error {
+		known := make(map[string]bool)
+		if t.AxiomSystem != "" && t.AxiomSystem != "custom" {
+			if system, err := r.axiomReg.Get(t.AxiomSystem); err == nil {
+				for _, a := range system.Axioms {
+					known[a.ID] = true
+				}
+			}
+		}
+		for _, a := range t.CustomAxioms {
+			known[a.ID] = true
+		}
+		for _, th := range t.Theorems {
+			known[th.ID] = true
+		}
+		for _, ref := range theorem.References {
+			if !known[ref] {
+				return fmt.Errorf("unknown axiom or theorem: %s", ref)
+			}
+		}
+
+		theorem.ID = fmt.Sprintf("t%d", len(t.Theorems)+1)
+		t.Theorems = append(t.Theorems, theorem)
+		if !containsString(t.Authors, authorID) {
+			t.Authors = append(t.Authors, authorID)
+		}
+		added = theorem
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+// Challenge records a critical review ("reject" or "revise") of a theory.
+// Authors cannot challenge their own theory and each reviewer challenges a
+// theory at most once.
+func (r *TheoryRepository) Challenge(review *types.Review) error {
+	if review.Verdict != "reject" && review.Verdict != "revise" {
+		return fmt.Errorf("invalid challenge verdict: %s", review.Verdict)
+	}
+	r.mu.RLock()
+	theory, ok := r.theories[review.TheoryID]
+	if ok {
+		if containsString(theory.Authors, review.ReviewerID) {
+			r.mu.RUnlock()
+			return fmt.Errorf("cannot challenge your own theory")
+		}
+		for _, rev := range theory.Reviews {
+			if rev.ReviewerID == review.ReviewerID {
+				r.mu.RUnlock()
+				return fmt.Errorf("already reviewed theory: %s", review.TheoryID)
+			}
+		}
+	}
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("theory not found: %s", review.TheoryID)
+	}
+	return r.AddReview(review.TheoryID, review)
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 // GetByAxiomSystem returns theories based on an axiom system.
 func (r *TheoryRepository) GetByAxiomSystem(axiomID string) []*types.Theory {
 	return r.Search(TheoryQuery{AxiomSystem: axiomID})
@@ -0,0 +1,65 @@
+package knowledge
+
+import (
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestTheoryRepository_DeriveAndChallenge(t *testing.T) {
+	dir := t.TempDir()
+	axioms := NewAxiomRegistry(dir)
+	if err := axioms.RegisterDefaults(); err != nil {
+		t.Fatalf("register defaults: %v", err)
+	}
+	repo := NewTheoryRepository(dir, axioms)
+
+	theory := &types.Theory{
+		ID:           "theory-1",
+		Title:        "Triangles",
+		Authors:      []string{"euclid"},
+		AxiomSystem:  "euclidean-geometry",
+		CustomAxioms: []types.Axiom{{ID: "c1", Statement: "extra"}},
+	}
+	if err := repo.Propose(theory); err != nil {
+		t.Fatalf("propose: %v", err)
+	}
+
+	if _, err := repo.AddTheorem("theory-1", "gauss", types.Theorem{Statement: "s", References: []string{"h5"}}); err == nil {
+		t.Fatalf("expected error for axiom outside the theory's system")
+	}
+	t1, err := repo.AddTheorem("theory-1", "gauss", types.Theorem{Statement: "angle sum", References: []string{"e5", "c1"}})
+	if err != nil {
+		t.Fatalf("add theorem: %v", err)
+	}
+	if _, err := repo.AddTheorem("theory-1", "euclid", types.Theorem{Statement: "corollary", References: []string{t1.ID}}); err != nil {
+		t.Fatalf("derive from theorem: %v", err)
+	}
+
+	if err := repo.Challenge(&types.Review{TheoryID: "theory-1", ReviewerID: "gauss", Verdict: "reject"}); err == nil {
+		t.Fatalf("expected co-author challenge to fail")
+	}
+	for _, reviewer := range []string{"riemann", "lobachevsky"} {
+		if err := repo.Challenge(&types.Review{TheoryID: "theory-1", ReviewerID: reviewer, Verdict: "reject"}); err != nil {
+			t.Fatalf("challenge by %s: %v", reviewer, err)
+		}
+	}
+	if err := repo.Challenge(&types.Review{TheoryID: "theory-1", ReviewerID: "riemann", Verdict: "revise"}); err == nil {
+		t.Fatalf("expected duplicate challenge to fail")
+	}
+
+	reloaded := NewTheoryRepository(dir, axioms)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	got, err := reloaded.Snapshot("theory-1")
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if len(got.Theorems) != 2 || len(got.Authors) != 2 {
+		t.Fatalf("expected 2 theorems and 2 authors, got %d and %v", len(got.Theorems), got.Authors)
+	}
+	if got.Status != types.StatusDisputed {
+		t.Fatalf("expected disputed status, got %s", got.Status)
+	}
+}
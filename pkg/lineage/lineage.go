@@ -0,0 +1,199 @@
+// Package lineage rebuilds how theories spread through the population from
+// the source each agent recorded when it first heard of one.
+package lineage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Adoption is one agent learning a theory.
+type Adoption struct {
+	TheoryID    string               `json:"theory_id"`
+	TheoryTitle string               `json:"theory_title,omitempty"`
+	AgentID     string               `json:"agent_id"`
+	Source      string               `json:"source,omitempty"` // who it was learned from
+	PostID      string               `json:"post_id,omitempty"`
+	LearnedAt   time.Time            `json:"learned_at"`
+	Level       types.KnowledgeLevel `json:"level"`
+}
+
+// Node is an agent in a propagation tree.
+type Node struct {
+	AgentID string `json:"agent_id"`
+	// PostID and LearnedAt say how the agent learned the theory; both are
+	// empty for origins that didn't learn it from anyone recorded, such as
+	// its author.
+	PostID    string               `json:"post_id,omitempty"`
+	LearnedAt time.Time            `json:"learned_at,omitzero"`
+	Level     types.KnowledgeLevel `json:"level,omitempty"`
+	Children  []*Node              `json:"children,omitempty"`
+}
+
+// Tree is how one theory spread.
+type Tree struct {
+	TheoryID    string `json:"theory_id"`
+	TheoryTitle string `json:"theory_title,omitempty"`
+	// Roots are the agents it spread from.
+	Roots []*Node `json:"roots"`
+	// Adopters is the number of agents that learned it.
+	Adopters int `json:"adopters"`
+	// Depth is the longest chain of learnings from a root; MaxFanout the
+	// most agents that learned it from one agent.
+	Depth     int `json:"depth"`
+	MaxFanout int `json:"max_fanout"`
+}
+
+// LoadAdoptions reads the knowledge in every agent's state.json under
+// dataPath/agents. Agents without a readable state are skipped.
+func LoadAdoptions(dataPath string) ([]Adoption, error) {
+	dir := filepath.Join(dataPath, "agents")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []Adoption
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), "state.json"))
+		if err != nil {
+			continue
+		}
+		var state struct {
+			AgentID   string                          `json:"agent_id"`
+			Knowledge map[string]*types.KnowledgeItem `json:"knowledge"`
+		}
+		if err := json.Unmarshal(data, &state); err != nil {
+			continue
+		}
+		agentID := state.AgentID
+		if agentID == "" {
+			agentID = entry.Name()
+		}
+		for _, k := range state.Knowledge {
+			if k == nil || k.TheoryID == "" {
+				continue
+			}
+			out = append(out, Adoption{
+				TheoryID:    k.TheoryID,
+				TheoryTitle: k.TheoryTitle,
+				AgentID:     agentID,
+				Source:      k.Source,
+				PostID:      k.SourcePostID,
+				LearnedAt:   k.LearnedAt,
+				Level:       k.Level,
+			})
+		}
+	}
+	return out, nil
+}
+
+// Build groups adoptions by theory into propagation trees, most adopted
+// first. A source that never learned the theory itself becomes a root, as
+// does an agent caught in a cycle of recorded sources (the earliest to
+// learn it).
+func Build(adoptions []Adoption) []Tree {
+	byTheory := make(map[string][]Adoption)
+	for _, a := range adoptions {
+		byTheory[a.TheoryID] = append(byTheory[a.TheoryID], a)
+	}
+	out := make([]Tree, 0, len(byTheory))
+	for theoryID, list := range byTheory {
+		out = append(out, buildTree(theoryID, list))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Adopters != out[j].Adopters {
+			return out[i].Adopters > out[j].Adopters
+		}
+		return out[i].TheoryID < out[j].TheoryID
+	})
+	return out
+}
+
+func buildTree(theoryID string, list []Adoption) Tree {
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].LearnedAt.Equal(list[j].LearnedAt) {
+			return list[i].LearnedAt.Before(list[j].LearnedAt)
+		}
+		return list[i].AgentID < list[j].AgentID
+	})
+	tree := Tree{TheoryID: theoryID, Adopters: len(list)}
+	nodes := make(map[string]*Node, len(list))
+	for _, a := range list {
+		if tree.TheoryTitle == "" {
+			tree.TheoryTitle = a.TheoryTitle
+		}
+		nodes[a.AgentID] = &Node{AgentID: a.AgentID, PostID: a.PostID, LearnedAt: a.LearnedAt, Level: a.Level}
+	}
+
+	parent := make(map[string]string, len(list))
+	var origins []string
+	for _, a := range list {
+		if a.Source == "" || a.Source == a.AgentID {
+			continue
+		}
+		if _, ok := nodes[a.Source]; !ok {
+			nodes[a.Source] = &Node{AgentID: a.Source}
+			origins = append(origins, a.Source)
+		}
+		parent[a.AgentID] = a.Source
+	}
+
+	// Earliest learners first, so a cycle is broken at its first member.
+	order := make([]string, 0, len(nodes))
+	order = append(order, origins...)
+	for _, a := range list {
+		order = append(order, a.AgentID)
+	}
+	for _, id := range order {
+		if p, ok := parent[id]; ok {
+			nodes[p].Children = append(nodes[p].Children, nodes[id])
+		}
+	}
+
+	reached := make(map[string]bool, len(nodes))
+	var walk func(n *Node, depth int)
+	walk = func(n *Node, depth int) {
+		reached[n.AgentID] = true
+		tree.Depth = max(tree.Depth, depth)
+		tree.MaxFanout = max(tree.MaxFanout, len(n.Children))
+		for _, c := range n.Children {
+			if !reached[c.AgentID] {
+				walk(c, depth+1)
+			}
+		}
+	}
+	for _, id := range order {
+		if _, ok := parent[id]; !ok && !reached[id] {
+			tree.Roots = append(tree.Roots, nodes[id])
+			walk(nodes[id], 0)
+		}
+	}
+	for _, id := range order {
+		if reached[id] {
+			continue
+		}
+		// Only a cycle is left unreached: cut it above this agent.
+		p := nodes[parent[id]]
+		for i, c := range p.Children {
+			if c.AgentID == id {
+				p.Children = append(p.Children[:i], p.Children[i+1:]...)
+				break
+			}
+		}
+		delete(parent, id)
+		tree.Roots = append(tree.Roots, nodes[id])
+		walk(nodes[id], 0)
+	}
+	return tree
+}
@@ -0,0 +1,69 @@
+package lineage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/agent"
+)
+
+// shape renders a node and its descendants as "id(child child)".
+func shape(n *Node) string {
+	if len(n.Children) == 0 {
+		return n.AgentID
+	}
+	parts := make([]string, 0, len(n.Children))
+	for _, c := range n.Children {
+		parts = append(parts, shape(c))
+	}
+	return n.AgentID + "(" + strings.Join(parts, " ") + ")"
+}
+
+func TestBuild_TreesFromRecordedSources(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC) }
+	trees := Build([]Adoption{
+		// theory-1: author spreads to b and c; c passes it to d.
+		{TheoryID: "theory-1", TheoryTitle: "T1", AgentID: "b", Source: "author", PostID: "p1", LearnedAt: at(1)},
+		{TheoryID: "theory-1", AgentID: "c", Source: "author", PostID: "p1", LearnedAt: at(2)},
+		{TheoryID: "theory-1", AgentID: "d", Source: "c", PostID: "p2", LearnedAt: at(3)},
+		// theory-2: x and y name each other.
+		{TheoryID: "theory-2", AgentID: "y", Source: "x", LearnedAt: at(2)},
+		{TheoryID: "theory-2", AgentID: "x", Source: "y", LearnedAt: at(1)},
+	})
+	if len(trees) != 2 || trees[0].TheoryID != "theory-1" {
+		t.Fatalf("trees = %+v", trees)
+	}
+	t1 := trees[0]
+	if len(t1.Roots) != 1 || shape(t1.Roots[0]) != "author(b c(d))" {
+		t.Fatalf("theory-1 roots = %+v", t1.Roots)
+	}
+	if t1.TheoryTitle != "T1" || t1.Adopters != 3 || t1.Depth != 2 || t1.MaxFanout != 2 {
+		t.Fatalf("theory-1 = %+v", t1)
+	}
+	if d := t1.Roots[0].Children[1].Children[0]; d.PostID != "p2" || !d.LearnedAt.Equal(at(3)) {
+		t.Fatalf("d = %+v", d)
+	}
+	if t2 := trees[1]; len(t2.Roots) != 1 || shape(t2.Roots[0]) != "x(y)" || t2.Depth != 1 {
+		t.Fatalf("theory-2 = %+v", t2)
+	}
+}
+
+func TestLoadAdoptions_ReadsAgentStates(t *testing.T) {
+	dataPath := t.TempDir()
+	state := agent.NewAgentState("agent-2", "B", filepath.Join(dataPath, "agents", "agent-2"))
+	state.LearnTheoryFrom("theory-1", "T1", "agent-1", "post-1")
+	state.LearnTheoryFrom("theory-1", "T1", "agent-3", "post-9") // later reads keep the origin
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	adoptions, err := LoadAdoptions(dataPath)
+	if err != nil || len(adoptions) != 1 {
+		t.Fatalf("LoadAdoptions = %+v, %v", adoptions, err)
+	}
+	if a := adoptions[0]; a.AgentID != "agent-2" || a.Source != "agent-1" || a.PostID != "post-1" {
+		t.Fatalf("adoption = %+v", a)
+	}
+}
@@ -0,0 +1,259 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// CacheMode selects how a CachedLLM uses its cache.
+type CacheMode string
+
+const (
+	// CacheReadWrite replays cached responses and records the rest.
+	CacheReadWrite CacheMode = "rw"
+	// CacheReplay only replays; a request not in the cache fails. Use it to
+	// run tests against recorded fixtures without an API key.
+	CacheReplay CacheMode = "replay"
+	// CacheRecord always calls the model and overwrites cached responses.
+	CacheRecord CacheMode = "record"
+)
+
+// ParseCacheMode parses rw, replay or record (empty means rw).
+func ParseCacheMode(s string) (CacheMode, error) {
+	switch m := CacheMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return CacheReadWrite, nil
+	case CacheReadWrite, CacheReplay, CacheRecord:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown cache mode %q (use rw, replay or record)", s)
+	}
+}
+
+// CachedLLM wraps a model with an on-disk response cache keyed by a hash of
+// the model name and request, for development runs and test fixtures. Each
+// entry is <dir>/<hash[:2]>/<hash>.json holding the responses the model
+// yielded. Function call IDs are left out of the hash since they are
+// generated afresh every run; anything else that differs, such as a new
+// post ID in a tool result, is a miss. Failed calls are not cached.
+type CachedLLM struct {
+	inner model.LLM
+	// name identifies the model in cache keys.
+	name string
+	dir  string
+	mode CacheMode
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCachedLLM wraps inner with a response cache under dir. name keys the
+// model's entries (empty uses inner.Name()), so a replay can find them
+// without the model's client: in CacheReplay mode inner may be nil.
+func NewCachedLLM(inner model.LLM, name, dir string, mode CacheMode) (*CachedLLM, error) {
+	if mode == "" {
+		mode = CacheReadWrite
+	}
+	if inner == nil && mode != CacheReplay {
+		return nil, fmt.Errorf("llm cache: no model to wrap")
+	}
+	if name == "" && inner != nil {
+		name = inner.Name()
+	}
+	if name == "" {
+		return nil, fmt.Errorf("llm cache: no model name")
+	}
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("llm cache: no directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &CachedLLM{inner: inner, name: name, dir: dir, mode: mode}, nil
+}
+
+// Name returns the wrapped model's name.
+func (c *CachedLLM) Name() string {
+	if c.inner == nil {
+		return c.name
+	}
+	return c.inner.Name()
+}
+
+// Stats returns how many requests were served from the cache and how many
+// were not.
+func (c *CachedLLM) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+type cacheEntry struct {
+	Model     string               `json:"model"`
+	Stream    bool                 `json:"stream,omitempty"`
+	Responses []*model.LLMResponse `json:"responses"`
+}
+
+// GenerateContent replays the cached responses for req if present, and
+// otherwise calls the wrapped model and records what it yields.
+func (c *CachedLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		key, err := c.key(req, stream)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		path := filepath.Join(c.dir, key[:2], key+".json")
+
+		if c.mode != CacheRecord {
+			if entry, err := readCacheEntry(path); err == nil {
+				c.hits.Add(1)
+				for _, resp := range entry.Responses {
+					if !yield(resp, nil) {
+						return
+					}
+				}
+				return
+			} else if !os.IsNotExist(err) {
+				yield(nil, fmt.Errorf("llm cache: %w", err))
+				return
+			}
+		}
+		c.misses.Add(1)
+		if c.mode == CacheReplay {
+			yield(nil, fmt.Errorf("llm cache: no cached response for request %s", key))
+			return
+		}
+
+		entry := cacheEntry{Model: c.name, Stream: stream}
+		complete := true
+		for resp, err := range c.inner.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			// Store a copy: callers may modify the response they're given.
+			if stored, err := cloneResponse(resp); err == nil {
+				entry.Responses = append(entry.Responses, stored)
+			} else {
+				complete = false
+			}
+			if !yield(resp, nil) {
+				// A caller that stops early hasn't seen everything; don't
+				// cache a truncated reply.
+				return
+			}
+		}
+		if complete && len(entry.Responses) > 0 {
+			if err := writeCacheEntry(path, entry); err != nil {
+				// Not fatal: the run goes on, the response just isn't cached.
+				log.Printf("LLM cache: %v", err)
+			}
+		}
+	}
+}
+
+// key hashes the model name and request, without function call IDs.
+func (c *CachedLLM) key(req *model.LLMRequest, stream bool) (string, error) {
+	data, err := json.Marshal(struct {
+		Model    string                       `json:"model"`
+		Stream   bool                         `json:"stream"`
+		Contents []*genai.Content             `json:"contents"`
+		Config   *genai.GenerateContentConfig `json:"config"`
+	}{c.name, stream, withoutCallIDs(req.Contents), req.Config})
+	if err != nil {
+		return "", fmt.Errorf("llm cache: hash request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func withoutCallIDs(contents []*genai.Content) []*genai.Content {
+	out := make([]*genai.Content, len(contents))
+	for i, content := range contents {
+		if content == nil {
+			continue
+		}
+		cp := *content
+		cp.Parts = make([]*genai.Part, len(content.Parts))
+		for j, part := range content.Parts {
+			if part == nil {
+				continue
+			}
+			p := *part
+			if p.FunctionCall != nil {
+				call := *p.FunctionCall
+				call.ID = ""
+				p.FunctionCall = &call
+			}
+			if p.FunctionResponse != nil {
+				resp := *p.FunctionResponse
+				resp.ID = ""
+				p.FunctionResponse = &resp
+			}
+			cp.Parts[j] = &p
+		}
+		out[i] = &cp
+	}
+	return out
+}
+
+func cloneResponse(resp *model.LLMResponse) (*model.LLMResponse, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	var out model.LLMResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func readCacheEntry(path string) (*cacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &entry, nil
+}
+
+// writeCacheEntry writes through a temporary file so concurrent agents
+// never read a partial entry.
+func writeCacheEntry(path string, entry cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
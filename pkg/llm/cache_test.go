@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+type countingLLM struct {
+	calls int
+}
+
+func (m *countingLLM) Name() string { return "fake-model" }
+
+func (m *countingLLM) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.calls++
+		text := "reply to " + req.Contents[0].Parts[0].Text
+		yield(&model.LLMResponse{
+			Content:       genai.NewContentFromText(text, genai.RoleModel),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: 7},
+		}, nil)
+	}
+}
+
+func cacheRequest(text, callID string) *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: []*genai.Content{
+			genai.NewContentFromText(text, genai.RoleUser),
+			{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: callID, Name: "browse_forum"}}}},
+		},
+		Config: &genai.GenerateContentConfig{SystemInstruction: genai.NewContentFromText("be brief", genai.RoleUser)},
+	}
+}
+
+func generateText(t *testing.T, m model.LLM, req *model.LLMRequest) (string, error) {
+	t.Helper()
+	var text string
+	for resp, err := range m.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			return "", err
+		}
+		text += resp.Content.Parts[0].Text
+	}
+	return text, nil
+}
+
+func TestCachedLLM(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingLLM{}
+	cached, err := NewCachedLLM(inner, "", dir, CacheReadWrite)
+	if err != nil {
+		t.Fatalf("NewCachedLLM: %v", err)
+	}
+
+	first, err := generateText(t, cached, cacheRequest("hello", "adk-1"))
+	if err != nil || first != "reply to hello" {
+		t.Fatalf("first call = %q, %v", first, err)
+	}
+	// Same request with a fresh function call ID replays the recorded reply.
+	again, err := generateText(t, cached, cacheRequest("hello", "adk-2"))
+	if err != nil || again != first {
+		t.Fatalf("cached call = %q, %v", again, err)
+	}
+	if _, err := generateText(t, cached, cacheRequest("other", "adk-3")); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 2 {
+		t.Fatalf("model called %d times, want 2", inner.calls)
+	}
+	if hits, misses := cached.Stats(); hits != 1 || misses != 2 {
+		t.Fatalf("stats = %d hits, %d misses", hits, misses)
+	}
+
+	// Replay mode serves fixtures without the model's client and fails on
+	// misses.
+	replay, err := NewCachedLLM(nil, "fake-model", dir, CacheReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := generateText(t, replay, cacheRequest("other", "x")); err != nil || got != "reply to other" {
+		t.Fatalf("replay = %q, %v", got, err)
+	}
+	if _, err := generateText(t, replay, cacheRequest("unseen", "x")); err == nil {
+		t.Fatalf("expected a replay miss to fail")
+	}
+
+	// Record mode always calls through.
+	recorder := &countingLLM{}
+	record, err := NewCachedLLM(recorder, "", dir, CacheRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generateText(t, record, cacheRequest("hello", "x")); err != nil {
+		t.Fatal(err)
+	}
+	if recorder.calls != 1 {
+		t.Fatalf("record mode should call the model, got %d calls", recorder.calls)
+	}
+}
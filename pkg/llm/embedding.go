@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google.golang.org/genai"
+)
+
+// GeminiEmbedder embeds text with a Gemini embedding model. It satisfies
+// memory.Embedder.
+type GeminiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+// NewGeminiEmbedder creates an embedder for model (default
+// "text-embedding-004"). An empty apiKey uses GOOGLE_API_KEY.
+func NewGeminiEmbedder(ctx context.Context, apiKey, model string) (*GeminiEmbedder, error) {
+	if apiKey == "" {
+		apiKey = os.Getenv("GOOGLE_API_KEY")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("GOOGLE_API_KEY not set")
+	}
+	if model == "" {
+		model = "text-embedding-004"
+	}
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:  apiKey,
+		Backend: genai.BackendGeminiAPI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genai client: %w", err)
+	}
+	return &GeminiEmbedder{client: client, model: model}, nil
+}
+
+// Name returns the embedding space identifier.
+func (e *GeminiEmbedder) Name() string {
+	return "gemini:" + e.model
+}
+
+// Embed returns one vector per text, in order.
+func (e *GeminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	contents := make([]*genai.Content, len(texts))
+	for i, text := range texts {
+		contents[i] = genai.NewContentFromText(text, genai.RoleUser)
+	}
+	resp, err := e.client.Models.EmbedContent(ctx, e.model, contents, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gemini embed failed: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("gemini returned %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+	out := make([][]float32, len(texts))
+	for i, emb := range resp.Embeddings {
+		if emb != nil {
+			out[i] = emb.Values
+		}
+	}
+	return out, nil
+}
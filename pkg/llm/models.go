@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"google.golang.org/adk/model"
+)
+
+// ProviderAnthropic is served through Anthropic's OpenAI-compatible endpoint,
+// since ailib has no native Anthropic client.
+const (
+	ProviderAnthropic       = "anthropic"
+	defaultAnthropicBaseURL = "https://api.anthropic.com/v1/"
+)
+
+// ModelProvider returns the provider of a normalized model spec.
+func ModelProvider(spec string) string {
+	if strings.HasPrefix(spec, ProviderAnthropic+":") {
+		return ProviderAnthropic
+	}
+	provider, _ := ailibmodel.ParseModelString(spec)
+	return provider
+}
+
+// NormalizeModelSpec returns spec as "provider:model". Bare names are
+// Gemini models; names with a slash are left for ailib to route to
+// OpenRouter.
+func NormalizeModelSpec(spec string) string {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return spec
+	}
+	// If the provider is explicitly specified, keep it.
+	if strings.Contains(spec, ":") {
+		return spec
+	}
+	// If the spec contains a slash, treat it as an OpenRouter-style model name and
+	// let the factory default to openrouter (backward compatible).
+	if strings.Contains(spec, "/") {
+		return spec
+	}
+	// Backward compatible default: our project historically assumes Gemini.
+	return ailibmodel.ProviderGemini + ":" + spec
+}
+
+// NewModel creates a model from a normalized spec, reading API keys and base
+// URLs from the environment.
+func NewModel(ctx context.Context, modelSpec string) (model.LLM, error) {
+	switch ModelProvider(modelSpec) {
+	case ailibmodel.ProviderGemini:
+		// Keep compatibility with existing .env (`GOOGLE_API_KEY`) while still
+		// allowing provider-specific env vars for other providers.
+		//
+		// ailib expects GEMINI_API_KEY for Gemini provider, while this repo uses
+		// GOOGLE_API_KEY historically.
+		if os.Getenv("GEMINI_API_KEY") == "" && os.Getenv("GOOGLE_API_KEY") != "" {
+			_ = os.Setenv("GEMINI_API_KEY", os.Getenv("GOOGLE_API_KEY"))
+		}
+	case ailibmodel.ProviderOpenAI:
+		// OPENAI_BASE_URL allows Azure/self-hosted OpenAI-compatible gateways.
+		return ailibmodel.NewWith(ctx, modelSpec, "", os.Getenv("OPENAI_BASE_URL"))
+	case ProviderAnthropic:
+		apiKey := os.Getenv("ANTHROPIC_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("API key required for %s provider (set ANTHROPIC_API_KEY)", ProviderAnthropic)
+		}
+		name := strings.TrimPrefix(modelSpec, ProviderAnthropic+":")
+		baseURL := os.Getenv("ANTHROPIC_BASE_URL")
+		if baseURL == "" {
+			baseURL = defaultAnthropicBaseURL
+		}
+		return ailibmodel.NewWith(ctx, ailibmodel.ProviderOpenAI+":"+name, apiKey, baseURL)
+	}
+
+	return ailibmodel.New(ctx, modelSpec)
+}
@@ -0,0 +1,220 @@
+// Package markdown renders the Markdown agents write into safe HTML and
+// plain-text excerpts for API responses. It follows the subset the web
+// pages render (web/assets/markdown.js): headings, paragraphs, lists, block
+// quotes, fenced code, inline code, emphasis, links and @mentions, with
+// math left as text for KaTeX. The source is HTML-escaped before any markup
+// is added and link targets are limited to relative, http(s) and mailto
+// URLs, so model output can't inject markup or scripts.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	quoteRe   = regexp.MustCompile(`^>\s?(.*)$`)
+	// Render matches quotes after escaping, where ">" reads "&gt;".
+	escapedQuoteRe = regexp.MustCompile(`^&gt;\s?(.*)$`)
+	orderedRe      = regexp.MustCompile(`^\d+\.\s+(.*)$`)
+	unorderedRe    = regexp.MustCompile(`^[-*]\s+(.*)$`)
+
+	// Math is kept verbatim so emphasis parsing doesn't corrupt LaTeX.
+	mathRes = []*regexp.Regexp{
+		regexp.MustCompile(`(?s)\$\$.+?\$\$`),
+		regexp.MustCompile(`(?s)\\\[.+?\\\]`),
+		regexp.MustCompile(`\\\(.+?\\\)`),
+		regexp.MustCompile(`\$[^$\n]+?\$`),
+	}
+	codeRe    = regexp.MustCompile("`([^`]+)`")
+	strongRe  = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	emRe      = regexp.MustCompile(`\*([^*]+)\*`)
+	linkRe    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mentionRe = regexp.MustCompile(`(^|[^\w])@([A-Za-z][A-Za-z0-9_-]{0,63})`)
+	segmentRe = regexp.MustCompile("\x00[0-9]+\x00")
+	spaceRe   = regexp.MustCompile(`\s+`)
+)
+
+// Render converts Markdown to sanitized HTML.
+func Render(src string) string {
+	if src == "" {
+		return ""
+	}
+	// NUL delimits protected segments below.
+	text := html.EscapeString(strings.ReplaceAll(src, "\x00", ""))
+	var (
+		out      []string
+		buffer   []string
+		listType string
+		inCode   bool
+	)
+	flushParagraph := func() {
+		if joined := strings.TrimSpace(strings.Join(buffer, " ")); joined != "" {
+			out = append(out, "<p>"+inline(joined)+"</p>")
+		}
+		buffer = nil
+	}
+	closeList := func() {
+		if listType != "" {
+			out = append(out, "</"+listType+">")
+			listType = ""
+		}
+	}
+	openList := func(typ string) {
+		if listType == typ {
+			return
+		}
+		closeList()
+		listType = typ
+		out = append(out, "<"+typ+">")
+	}
+
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+		if strings.HasPrefix(line, "```") {
+			if inCode {
+				out = append(out, "</code></pre>")
+			} else {
+				flushParagraph()
+				closeList()
+				out = append(out, "<pre><code>")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, line)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			closeList()
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			closeList()
+			level := strconv.Itoa(len(m[1]))
+			out = append(out, "<h"+level+">"+inline(m[2])+"</h"+level+">")
+			continue
+		}
+		if m := escapedQuoteRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			closeList()
+			out = append(out, "<blockquote>"+inline(m[1])+"</blockquote>")
+			continue
+		}
+		if m := orderedRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ol")
+			out = append(out, "<li>"+inline(m[1])+"</li>")
+			continue
+		}
+		if m := unorderedRe.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ul")
+			out = append(out, "<li>"+inline(m[1])+"</li>")
+			continue
+		}
+		buffer = append(buffer, strings.TrimSpace(line))
+	}
+	flushParagraph()
+	closeList()
+	if inCode {
+		out = append(out, "</code></pre>")
+	}
+	return strings.Join(out, "\n")
+}
+
+// inline formats one escaped block of text.
+func inline(text string) string {
+	var segments []string
+	protect := func(value string) string {
+		segments = append(segments, value)
+		return "\x00" + strconv.Itoa(len(segments)-1) + "\x00"
+	}
+	for _, re := range mathRes {
+		text = re.ReplaceAllStringFunc(text, protect)
+	}
+	text = codeRe.ReplaceAllStringFunc(text, func(m string) string {
+		return protect("<code>" + codeRe.FindStringSubmatch(m)[1] + "</code>")
+	})
+	// Finished links are protected too, so emphasis and mentions can't
+	// reach into their URLs.
+	text = linkRe.ReplaceAllStringFunc(text, func(m string) string {
+		parts := linkRe.FindStringSubmatch(m)
+		return protect(`<a href="` + safeURL(parts[2]) + `" target="_blank" rel="noopener noreferrer">` + emphasis(parts[1]) + `</a>`)
+	})
+	text = emphasis(text)
+	text = mentionRe.ReplaceAllStringFunc(text, func(m string) string {
+		parts := mentionRe.FindStringSubmatch(m)
+		href := "./agent.html?id=" + url.QueryEscape(parts[2])
+		return parts[1] + `<a class="mention" href="` + href + `">@` + parts[2] + `</a>`
+	})
+	// Links can hold code and math segments; expand until none are left.
+	for segmentRe.MatchString(text) {
+		text = segmentRe.ReplaceAllStringFunc(text, func(key string) string {
+			i, _ := strconv.Atoi(strings.Trim(key, "\x00"))
+			return segments[i]
+		})
+	}
+	return text
+}
+
+func emphasis(text string) string {
+	text = strongRe.ReplaceAllString(text, "<strong>$1</strong>")
+	return emRe.ReplaceAllString(text, "<em>$1</em>")
+}
+
+// safeURL keeps relative, http(s) and mailto links; anything else, such as
+// javascript: URLs, becomes "#". The URL is already escaped.
+func safeURL(raw string) string {
+	u := strings.TrimSpace(raw)
+	lower := strings.ToLower(u)
+	switch {
+	case u == "":
+		return "#"
+	case strings.HasPrefix(u, "/"), strings.HasPrefix(u, "#"):
+		return u
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"), strings.HasPrefix(lower, "mailto:"):
+		return u
+	}
+	return "#"
+}
+
+// Excerpt returns the first maxChars characters of the Markdown's text,
+// without markup and with whitespace collapsed, ending in "…" when cut.
+func Excerpt(src string, maxChars int) string {
+	var lines []string
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			line = m[2]
+		} else if m := quoteRe.FindStringSubmatch(line); m != nil {
+			line = m[1]
+		} else if m := orderedRe.FindStringSubmatch(line); m != nil {
+			line = m[1]
+		} else if m := unorderedRe.FindStringSubmatch(line); m != nil {
+			line = m[1]
+		}
+		lines = append(lines, line)
+	}
+	text := linkRe.ReplaceAllString(strings.Join(lines, " "), "$1")
+	text = codeRe.ReplaceAllString(text, "$1")
+	text = strongRe.ReplaceAllString(text, "$1")
+	text = emRe.ReplaceAllString(text, "$1")
+	text = strings.TrimSpace(spaceRe.ReplaceAllString(text, " "))
+	if maxChars <= 0 || utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxChars])) + "…"
+}
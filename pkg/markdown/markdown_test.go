@@ -0,0 +1,56 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	src := "# Result *one*\n\nThe period $T = 2\\pi\\sqrt{L/g}$ holds, see **fig-1** and `code*x*`.\n" +
+		"Ask @Galileo or [the notes](https://example.org/a?b=1&c=2).\n\n" +
+		"- first\n- second\n\n1. step\n\n> quoted\n\n```\n<b>raw</b>\n```"
+	got := Render(src)
+	for _, want := range []string{
+		"<h1>Result <em>one</em></h1>",
+		"$T = 2\\pi\\sqrt{L/g}$",
+		"<strong>fig-1</strong>",
+		"<code>code*x*</code>",
+		`<a class="mention" href="./agent.html?id=Galileo">@Galileo</a>`,
+		`<a href="https://example.org/a?b=1&amp;c=2" target="_blank" rel="noopener noreferrer">the notes</a>`,
+		"<ul>\n<li>first</li>\n<li>second</li>\n</ul>",
+		"<ol>\n<li>step</li>\n</ol>",
+		"<blockquote>quoted</blockquote>",
+		"<pre><code>\n&lt;b&gt;raw&lt;/b&gt;\n</code></pre>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestRender_Sanitizes(t *testing.T) {
+	for _, src := range []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror="alert(1)">`,
+		`[click](javascript:alert(1))`,
+		`[x](" onmouseover="alert(1))`,
+		`[x](/@bob)`,
+		"\x00" + `0` + "\x00<script>",
+	} {
+		got := Render(src)
+		if strings.Contains(got, "<script") || strings.Contains(got, "<img") || strings.Contains(got, "javascript:") ||
+			strings.Contains(got, `" onmouseover`) || strings.Contains(got, `href="/<a`) {
+			t.Errorf("unsafe output for %q: %s", src, got)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	src := "## Intro\n\nWe **measure** the [period](https://example.org) of a `pendulum`.\n\n- long arms swing slowly"
+	if got := Excerpt(src, 0); got != "Intro We measure the period of a pendulum. long arms swing slowly" {
+		t.Fatalf("unexpected excerpt: %q", got)
+	}
+	if got := Excerpt(src, 10); got != "Intro We m…" {
+		t.Fatalf("unexpected cut excerpt: %q", got)
+	}
+}
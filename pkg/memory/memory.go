@@ -2,6 +2,7 @@
 package memory
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -79,6 +80,9 @@ type Memory struct {
 	Working  *WorkingMemory  `json:"working"`
 	External *ExternalMemory `json:"external"`
 
+	// Semantic is the embedding index used by Recall (nil until EnableSemantic).
+	Semantic *SemanticStore `json:"-"`
+
 	// Persistence
 	dataPath string
 }
@@ -109,6 +113,29 @@ func NewMemory(agentID string, dataPath string, contextWindow int) *Memory {
 	}
 }
 
+// EnableSemantic attaches an embedding index stored in
+// semantic_memory.jsonl; a nil embedder uses the local HashEmbedder.
+func (m *Memory) EnableSemantic(embedder Embedder) {
+	m.Semantic = NewSemanticStore(filepath.Join(m.dataPath, "semantic_memory.jsonl"), embedder)
+}
+
+// Remember embeds text into semantic memory. It is a no-op without an index.
+func (m *Memory) Remember(ctx context.Context, kind, text string, at time.Time) error {
+	if m.Semantic == nil {
+		return nil
+	}
+	_, err := m.Semantic.Add(ctx, kind, text, at)
+	return err
+}
+
+// Recall returns the k remembered thoughts most relevant to query.
+func (m *Memory) Recall(ctx context.Context, query string, k int) ([]Recollection, error) {
+	if m.Semantic == nil {
+		return nil, nil
+	}
+	return m.Semantic.Search(ctx, query, k)
+}
+
 // AddMessage adds a message to working memory, respecting context limits.
 func (m *Memory) AddMessage(msg *types.Message) {
 	m.Working.mu.Lock()
@@ -241,6 +268,13 @@ func (m *Memory) Load() error {
 		}
 	}
 
+	// Semantic records are appended as they are added; only loading is needed.
+	if m.Semantic != nil {
+		if err := m.Semantic.Load(context.Background()); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// NotesDir holds an agent's research notes under its data directory, one
+// JSONL file per sim day (notes/YYYY-MM-DD.jsonl).
+const NotesDir = "notes"
+
+// ResearchNote is a note an agent chose to write down with record_note,
+// kept apart from the prompt/reply transcripts of the daily log.
+type ResearchNote struct {
+	At            time.Time `json:"at"` // sim time
+	Topic         string    `json:"topic"`
+	Insight       string    `json:"insight"`
+	OpenQuestions []string  `json:"open_questions,omitempty"`
+}
+
+// RecordNote appends note to the file of its sim day and adds it to
+// semantic memory so recall_memory finds it.
+func (m *Memory) RecordNote(ctx context.Context, note ResearchNote) error {
+	dir := filepath.Join(m.dataPath, NotesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, note.At.Format("2006-01-02")+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	err = writeJSONLine(f, note)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	text := note.Topic + ": " + note.Insight
+	if len(note.OpenQuestions) > 0 {
+		text += "\nOpen questions: " + strings.Join(note.OpenQuestions, "; ")
+	}
+	return m.Remember(ctx, "note", text, note.At)
+}
+
+// ReadNotes returns the notes in an agent's notes dir dated from..to
+// (inclusive YYYY-MM-DD; empty is open), oldest first. A missing dir has
+// no notes.
+func ReadNotes(dir, from, to string) ([]ResearchNote, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []ResearchNote
+	for _, file := range files {
+		date, ok := strings.CutSuffix(file.Name(), ".jsonl")
+		if file.IsDir() || !ok || (from != "" && date < from) || (to != "" && date > to) {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var note ResearchNote
+			if err := json.Unmarshal(scanner.Bytes(), &note); err != nil {
+				continue
+			}
+			out = append(out, note)
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemory_RecordNote(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	day := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	m := NewMemory("a", dir, 0)
+	m.EnableSemantic(nil)
+	notes := []ResearchNote{
+		{At: day, Topic: "halos", Insight: "rotation curves flatten early", OpenQuestions: []string{"dwarf galaxies?"}},
+		{At: day.Add(time.Hour), Topic: "lensing", Insight: "weak lensing agrees"},
+		{At: day.AddDate(0, 0, 1), Topic: "halos", Insight: "cores, not cusps"},
+	}
+	for _, n := range notes {
+		if err := m.RecordNote(ctx, n); err != nil {
+			t.Fatalf("RecordNote: %v", err)
+		}
+	}
+
+	all, err := ReadNotes(filepath.Join(dir, NotesDir), "", "")
+	if err != nil || len(all) != 3 || all[0].OpenQuestions[0] != "dwarf galaxies?" || all[2].Insight != "cores, not cusps" {
+		t.Fatalf("ReadNotes = %+v, %v", all, err)
+	}
+	first, err := ReadNotes(filepath.Join(dir, NotesDir), "2026-02-01", "2026-02-01")
+	if err != nil || len(first) != 2 {
+		t.Fatalf("ReadNotes(day 1) = %+v, %v", first, err)
+	}
+
+	got, err := m.Recall(ctx, "rotation curves", 1)
+	if err != nil || len(got) != 1 || got[0].Record.Kind != "note" {
+		t.Fatalf("Recall = %+v, %v", got, err)
+	}
+}
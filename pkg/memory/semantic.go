@@ -0,0 +1,318 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Embedder turns texts into vectors. Name identifies the embedding space;
+// stored vectors from a different embedder are recomputed on load.
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// MemoryRecord is one remembered thought with its embedding.
+type MemoryRecord struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"` // turn | experience | summary | note
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	Embedder  string    `json:"embedder"`
+	Vector    []float32 `json:"vector"`
+}
+
+// Recollection is a record returned by Recall with its similarity score.
+type Recollection struct {
+	Record *MemoryRecord
+	Score  float64
+}
+
+// SemanticStore is a local cosine-similarity index over memory records,
+// persisted as append-only JSONL.
+type SemanticStore struct {
+	mu       sync.RWMutex
+	path     string
+	embedder Embedder
+	records  []*MemoryRecord
+}
+
+// NewSemanticStore creates a store backed by path.
+func NewSemanticStore(path string, embedder Embedder) *SemanticStore {
+	if embedder == nil {
+		embedder = NewHashEmbedder(0)
+	}
+	return &SemanticStore{path: path, embedder: embedder}
+}
+
+// Len returns the number of stored records.
+func (s *SemanticStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// Add embeds text and appends it to the index and the file on disk.
+func (s *SemanticStore) Add(ctx context.Context, kind, text string, at time.Time) (*MemoryRecord, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	vecs, err := s.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) != 1 {
+		return nil, fmt.Errorf("embedder %s returned %d vectors for 1 text", s.embedder.Name(), len(vecs))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec := &MemoryRecord{
+		ID:        fmt.Sprintf("mem-%d", len(s.records)+1),
+		Kind:      kind,
+		Text:      text,
+		CreatedAt: at,
+		Embedder:  s.embedder.Name(),
+		Vector:    normalize(vecs[0]),
+	}
+	if err := s.appendLocked(rec); err != nil {
+		return nil, err
+	}
+	s.records = append(s.records, rec)
+	return rec, nil
+}
+
+// Search returns the k records most similar to query, best first.
+func (s *SemanticStore) Search(ctx context.Context, query string, k int) ([]Recollection, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || k <= 0 {
+		return nil, nil
+	}
+	vecs, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) != 1 {
+		return nil, fmt.Errorf("embedder %s returned %d vectors for 1 text", s.embedder.Name(), len(vecs))
+	}
+	q := normalize(vecs[0])
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Recollection, 0, len(s.records))
+	for _, rec := range s.records {
+		if score := dot(q, rec.Vector); score > 0 {
+			out = append(out, Recollection{Record: rec, Score: score})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if len(out) > k {
+		out = out[:k]
+	}
+	return out, nil
+}
+
+// Load reads the JSONL file. Records embedded by another embedder are
+// re-embedded and the file is rewritten.
+func (s *SemanticStore) Load(ctx context.Context) error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	records := make([]*MemoryRecord, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var rec MemoryRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			// Tolerate a torn last line from an interrupted run.
+			continue
+		}
+		records = append(records, &rec)
+	}
+	err = scanner.Err()
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	stale := make([]*MemoryRecord, 0)
+	for _, rec := range records {
+		if rec.Embedder != s.embedder.Name() || len(rec.Vector) == 0 {
+			stale = append(stale, rec)
+		}
+	}
+	if len(stale) > 0 {
+		texts := make([]string, len(stale))
+		for i, rec := range stale {
+			texts[i] = rec.Text
+		}
+		vecs, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("re-embed memory: %w", err)
+		}
+		if len(vecs) != len(stale) {
+			return fmt.Errorf("embedder %s returned %d vectors for %d texts", s.embedder.Name(), len(vecs), len(stale))
+		}
+		for i, rec := range stale {
+			rec.Embedder = s.embedder.Name()
+			rec.Vector = normalize(vecs[i])
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = records
+	if len(stale) > 0 {
+		return s.rewriteLocked()
+	}
+	return nil
+}
+
+func (s *SemanticStore) appendLocked(rec *MemoryRecord) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeJSONLine(file, rec)
+}
+
+func (s *SemanticStore) rewriteLocked() error {
+	tmp := s.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	for _, rec := range s.records {
+		if err := writeJSONLine(file, rec); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// HashEmbedder is a dependency-free embedder using feature hashing over
+// lowercase words and Chinese character bigrams. It needs no network access
+// and is the default when no model embedder is configured.
+type HashEmbedder struct {
+	dim int
+}
+
+// NewHashEmbedder creates a hashing embedder with dim dimensions (default 256).
+func NewHashEmbedder(dim int) *HashEmbedder {
+	if dim <= 0 {
+		dim = 256
+	}
+	return &HashEmbedder{dim: dim}
+}
+
+// Name implements Embedder.
+func (e *HashEmbedder) Name() string {
+	return fmt.Sprintf("hash-%d", e.dim)
+}
+
+// Embed implements Embedder.
+func (e *HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec := make([]float32, e.dim)
+		for _, term := range terms(text) {
+			h := fnv.New32a()
+			h.Write([]byte(term))
+			sum := h.Sum32()
+			// The top bit picks the sign so collisions tend to cancel out.
+			if sum&(1<<31) != 0 {
+				vec[int(sum%uint32(e.dim))]--
+			} else {
+				vec[int(sum%uint32(e.dim))]++
+			}
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+// terms splits text into lowercase words (2+ runes) and Han bigrams.
+func terms(text string) []string {
+	out := make([]string, 0)
+	var word []rune
+	var prevHan rune
+	flush := func() {
+		if len(word) >= 2 {
+			out = append(out, string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if prevHan != 0 {
+				out = append(out, string([]rune{prevHan, r}))
+			}
+			prevHan = r
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			prevHan = 0
+			word = append(word, r)
+		default:
+			flush()
+			prevHan = 0
+		}
+	}
+	flush()
+	return out
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	n := float32(math.Sqrt(sum))
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x / n
+	}
+	return out
+}
+
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fixedEmbedder struct{ name string }
+
+func (e fixedEmbedder) Name() string { return e.name }
+
+func (e fixedEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{1, 0}
+	}
+	return out, nil
+}
+
+func TestMemory_Recall(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	m := NewMemory("a", dir, 0)
+	m.EnableSemantic(nil)
+	for _, text := range []string{
+		"dark matter halos may be explained by modified gravity",
+		"暗物质晕可能来自修正引力",
+		"the committee meeting schedule moved to friday",
+	} {
+		if err := m.Remember(ctx, "turn", text, now); err != nil {
+			t.Fatalf("Remember: %v", err)
+		}
+	}
+
+	got, err := m.Recall(ctx, "modified gravity and dark matter", 1)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Recall: %v %v", got, err)
+	}
+	if got[0].Record.Text != "dark matter halos may be explained by modified gravity" {
+		t.Errorf("unexpected top recollection: %q", got[0].Record.Text)
+	}
+	if got, _ := m.Recall(ctx, "修正引力", 1); len(got) != 1 || got[0].Record.Text != "暗物质晕可能来自修正引力" {
+		t.Errorf("expected Chinese recall, got %+v", got)
+	}
+
+	// Records survive a reload and are re-embedded for a different embedder.
+	reloaded := NewMemory("a", dir, 0)
+	reloaded.EnableSemantic(nil)
+	if err := reloaded.Load(); err != nil || reloaded.Semantic.Len() != 3 {
+		t.Fatalf("reload: len=%d err=%v", reloaded.Semantic.Len(), err)
+	}
+	other := NewMemory("a", dir, 0)
+	other.EnableSemantic(fixedEmbedder{name: "fixed"})
+	if err := other.Load(); err != nil {
+		t.Fatalf("Load with new embedder: %v", err)
+	}
+	if got, _ := other.Recall(ctx, "anything", 5); len(got) != 3 || got[0].Record.Embedder != "fixed" {
+		t.Fatalf("expected re-embedded records, got %+v", got)
+	}
+}
@@ -0,0 +1,350 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// emergencePoints is how many checkpoints the emergence index keeps.
+const emergencePoints = 1000
+
+// EmergenceIndex tracks whether a community keeps producing new ideas or
+// converges on old ones. The scheduler records one point per checkpoint
+// and saves the index as emergence.json under the data root.
+type EmergenceIndex struct {
+	Version     int       `json:"version"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// Watermark is the publication time of the newest document scored;
+	// later documents are new at the next Record.
+	Watermark time.Time        `json:"watermark"`
+	Points    []EmergencePoint `json:"points"` // oldest first
+	Weeks     []EmergenceWeek  `json:"weeks"`  // oldest first
+
+	vectors map[string]termVector
+}
+
+// EmergencePoint is the state of the community at one checkpoint.
+type EmergencePoint struct {
+	SimTime   time.Time `json:"sim_time"`
+	Tick      int       `json:"tick"`
+	Documents int       `json:"documents"` // corpus size
+	New       int       `json:"new"`       // documents scored at this checkpoint
+	// Novelty is the mean novelty of the new documents: 1 minus their
+	// highest cosine similarity to any earlier document.
+	Novelty float64 `json:"novelty"`
+	// TopicEntropy is the entropy of the current sim week so far.
+	TopicEntropy float64 `json:"topic_entropy"`
+	// CitationGini is the Gini coefficient of citations across papers:
+	// 0 when every paper is cited equally, near 1 when a few take all.
+	CitationGini float64 `json:"citation_gini"`
+	Papers       int     `json:"papers"`
+	CitedPapers  int     `json:"cited_papers"`
+}
+
+// EmergenceWeek aggregates the documents first scored in one sim week.
+type EmergenceWeek struct {
+	Week      string         `json:"week"` // ISO week, e.g. "2026-W07"
+	Documents int            `json:"documents"`
+	Topics    map[string]int `json:"topics"`
+	// Entropy is the Shannon entropy (bits) of the topic distribution;
+	// Evenness divides it by its maximum for the topics seen, so 1 means
+	// activity was spread evenly.
+	Entropy  float64 `json:"entropy"`
+	Evenness float64 `json:"evenness"`
+	Novelty  float64 `json:"novelty"` // mean novelty of the scored documents
+	// Scored counts the documents with any terms to score novelty by.
+	Scored int `json:"scored"`
+}
+
+// Document is a post or comment as the emergence index sees it.
+type Document struct {
+	ID          string
+	Topic       string // e.g. the subreddit
+	Text        string
+	PublishedAt time.Time
+	// Exogenous documents join the prior corpus but are not scored, since
+	// the community didn't write them.
+	Exogenous bool
+}
+
+// NewEmergenceIndex returns an empty index.
+func NewEmergenceIndex() *EmergenceIndex {
+	return &EmergenceIndex{Version: 1, Points: []EmergencePoint{}, Weeks: []EmergenceWeek{}}
+}
+
+// Record scores the documents published since the previous record against
+// everything published before them, credits them to simTime's week, and
+// appends a point. citations holds the citation count of every paper,
+// uncited ones included. The first record only sets the baseline, so a
+// seeded or resumed corpus isn't counted as new. A second record at the
+// same tick replaces the point.
+func (idx *EmergenceIndex) Record(simTime time.Time, tick int, docs []Document, citations []int) {
+	if idx.vectors == nil {
+		idx.vectors = make(map[string]termVector)
+	}
+	sorted := append([]Document(nil), docs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].PublishedAt.Equal(sorted[j].PublishedAt) {
+			return sorted[i].PublishedAt.Before(sorted[j].PublishedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	baseline := idx.Watermark.IsZero() && len(idx.Points) == 0
+
+	week := idx.week(isoWeek(simTime))
+	point := EmergencePoint{SimTime: simTime, Tick: tick, Documents: len(sorted)}
+	scored, total := 0, 0.0
+	var prior []termVector
+	for _, d := range sorted {
+		v := idx.vector(d)
+		if !baseline && d.PublishedAt.After(idx.Watermark) && !d.Exogenous {
+			point.New++
+			week.Documents++
+			topic := d.Topic
+			if topic == "" {
+				topic = "general"
+			}
+			week.Topics[topic]++
+			if len(v) > 0 {
+				best := 0.0
+				for _, p := range prior {
+					best = max(best, v.cosine(p))
+				}
+				scored++
+				total += 1 - best
+			}
+		}
+		if len(v) > 0 {
+			prior = append(prior, v)
+		}
+	}
+	if n := len(sorted); n > 0 && sorted[n-1].PublishedAt.After(idx.Watermark) {
+		idx.Watermark = sorted[n-1].PublishedAt
+	}
+	if scored > 0 {
+		point.Novelty = total / float64(scored)
+		week.Novelty = (week.Novelty*float64(week.Scored) + total) / float64(week.Scored+scored)
+		week.Scored += scored
+	}
+	week.Entropy = Entropy(week.Topics)
+	week.Evenness = 0
+	if len(week.Topics) > 1 {
+		week.Evenness = week.Entropy / math.Log2(float64(len(week.Topics)))
+	}
+	point.TopicEntropy = week.Entropy
+	point.CitationGini = Gini(citations)
+	point.Papers = len(citations)
+	for _, c := range citations {
+		if c > 0 {
+			point.CitedPapers++
+		}
+	}
+
+	if n := len(idx.Points); n > 0 && idx.Points[n-1].Tick == tick {
+		prev := idx.Points[n-1]
+		if point.New == 0 {
+			point.Novelty = prev.Novelty
+		} else if prev.New > 0 {
+			point.Novelty = (prev.Novelty*float64(prev.New) + point.Novelty*float64(point.New)) / float64(prev.New+point.New)
+		}
+		point.New += prev.New
+		idx.Points[n-1] = point
+	} else {
+		idx.Points = append(idx.Points, point)
+	}
+	if len(idx.Points) > emergencePoints {
+		idx.Points = append([]EmergencePoint(nil), idx.Points[len(idx.Points)-emergencePoints:]...)
+	}
+}
+
+// week returns the entry for key, adding it in order if needed.
+func (idx *EmergenceIndex) week(key string) *EmergenceWeek {
+	for i := range idx.Weeks {
+		if idx.Weeks[i].Week == key {
+			w := &idx.Weeks[i]
+			if w.Topics == nil {
+				w.Topics = make(map[string]int)
+			}
+			return w
+		}
+	}
+	idx.Weeks = append(idx.Weeks, EmergenceWeek{Week: key, Topics: make(map[string]int)})
+	sort.Slice(idx.Weeks, func(i, j int) bool { return idx.Weeks[i].Week < idx.Weeks[j].Week })
+	return idx.week(key)
+}
+
+// vector returns the document's term vector, computing it once per ID.
+func (idx *EmergenceIndex) vector(d Document) termVector {
+	if v, ok := idx.vectors[d.ID]; ok {
+		return v
+	}
+	v := newTermVector(Terms(d.Text))
+	idx.vectors[d.ID] = v
+	return v
+}
+
+func isoWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Entropy returns the Shannon entropy, in bits, of the distribution given
+// by counts.
+func Entropy(counts map[string]int) float64 {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	h := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(total)
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
+
+// Gini returns the Gini coefficient of values: 0 for perfect equality and
+// (n-1)/n when a single value holds everything. Empty or all-zero input
+// gives 0.
+func Gini(values []int) float64 {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	sum, weighted := 0.0, 0.0
+	for i, v := range sorted {
+		sum += float64(v)
+		weighted += float64(i+1) * float64(v)
+	}
+	if sum == 0 {
+		return 0
+	}
+	n := float64(len(sorted))
+	return 2*weighted/(n*sum) - (n+1)/n
+}
+
+// stopwords are common English words left out of Terms.
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true, "with": true,
+	"are": true, "was": true, "were": true, "from": true, "have": true, "has": true,
+	"not": true, "but": true, "can": true, "its": true, "into": true, "than": true,
+	"then": true, "they": true, "their": true, "there": true, "which": true, "what": true,
+	"when": true, "will": true, "would": true, "could": true, "should": true, "also": true,
+	"more": true, "such": true, "these": true, "those": true, "been": true, "our": true,
+	"you": true, "your": true, "all": true, "any": true, "one": true, "about": true,
+}
+
+// Terms splits s into terms: lower-cased Latin words of at least three
+// letters, minus stopwords, and overlapping bigrams of Han runs, since
+// Chinese text has no spaces to split on.
+func Terms(s string) []string {
+	var out []string
+	var word, han []rune
+	flushWord := func() {
+		if len(word) >= 3 {
+			w := strings.ToLower(string(word))
+			if !stopwords[w] {
+				out = append(out, w)
+			}
+		}
+		word = word[:0]
+	}
+	flushHan := func() {
+		for i := 0; i+1 < len(han); i++ {
+			out = append(out, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return out
+}
+
+// termVector is a unit-length term frequency vector.
+type termVector map[string]float64
+
+func newTermVector(terms []string) termVector {
+	if len(terms) == 0 {
+		return nil
+	}
+	v := make(termVector, len(terms))
+	for _, t := range terms {
+		v[t]++
+	}
+	norm := 0.0
+	for _, f := range v {
+		norm += f * f
+	}
+	norm = math.Sqrt(norm)
+	for t := range v {
+		v[t] /= norm
+	}
+	return v
+}
+
+func (v termVector) cosine(o termVector) float64 {
+	if len(o) < len(v) {
+		v, o = o, v
+	}
+	dot := 0.0
+	for t, f := range v {
+		dot += f * o[t]
+	}
+	return dot
+}
+
+// LoadEmergenceIndex reads an emergence index, returning an empty one if
+// the file does not exist yet.
+func LoadEmergenceIndex(path string) (*EmergenceIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewEmergenceIndex(), nil
+		}
+		return nil, err
+	}
+	idx := NewEmergenceIndex()
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// WriteEmergenceIndex writes the index to path.
+func WriteEmergenceIndex(path string, idx *EmergenceIndex) error {
+	if idx.Version <= 0 {
+		idx.Version = 1
+	}
+	idx.GeneratedAt = time.Now()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGiniAndEntropy(t *testing.T) {
+	for _, tc := range []struct {
+		values []int
+		want   float64
+	}{
+		{nil, 0},
+		{[]int{0, 0, 0}, 0},
+		{[]int{2, 2, 2, 2}, 0},
+		{[]int{0, 0, 0, 4}, 0.75},
+	} {
+		if got := Gini(tc.values); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("Gini(%v) = %v, want %v", tc.values, got, tc.want)
+		}
+	}
+	if got := Entropy(map[string]int{"physics": 2, "biology": 2}); math.Abs(got-1) > 1e-9 {
+		t.Fatalf("Entropy of two even topics = %v, want 1", got)
+	}
+	if got := Entropy(map[string]int{"physics": 5}); got != 0 {
+		t.Fatalf("Entropy of one topic = %v, want 0", got)
+	}
+}
+
+func TestEmergenceIndex_RecordScoresNewDocuments(t *testing.T) {
+	base := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+	doc := func(id, topic, text string, minute int) Document {
+		return Document{ID: id, Topic: topic, Text: text, PublishedAt: base.Add(time.Duration(minute) * time.Minute)}
+	}
+	docs := []Document{doc("seed", "physics", "black hole entropy", 0)}
+
+	idx := NewEmergenceIndex()
+	idx.Record(base, 1, docs, nil)
+	if p := idx.Points[0]; p.New != 0 || p.Documents != 1 {
+		t.Fatalf("baseline point = %+v, want no new documents", p)
+	}
+
+	docs = append(docs,
+		doc("copy", "physics", "black hole entropy", 1),
+		doc("fresh", "biology", "protein folding kinetics", 2),
+	)
+	idx.Record(base.Add(time.Hour), 2, docs, []int{0, 0, 3})
+	p := idx.Points[1]
+	if p.New != 2 || math.Abs(p.Novelty-0.5) > 1e-9 {
+		t.Fatalf("point = %+v, want 2 new documents with mean novelty 0.5", p)
+	}
+	if math.Abs(p.CitationGini-2.0/3) > 1e-9 || p.Papers != 3 || p.CitedPapers != 1 {
+		t.Fatalf("citations = gini %v, %d/%d cited", p.CitationGini, p.CitedPapers, p.Papers)
+	}
+	if len(idx.Weeks) != 1 || idx.Weeks[0].Week != "2026-W06" || idx.Weeks[0].Documents != 2 || math.Abs(idx.Weeks[0].Evenness-1) > 1e-9 {
+		t.Fatalf("weeks = %+v", idx.Weeks)
+	}
+
+	// Saving and reloading keeps the watermark: nothing is new again.
+	path := filepath.Join(t.TempDir(), "emergence.json")
+	if err := WriteEmergenceIndex(path, idx); err != nil {
+		t.Fatalf("WriteEmergenceIndex: %v", err)
+	}
+	loaded, err := LoadEmergenceIndex(path)
+	if err != nil {
+		t.Fatalf("LoadEmergenceIndex: %v", err)
+	}
+	loaded.Record(base.Add(2*time.Hour), 3, docs, []int{0, 0, 3})
+	if p := loaded.Points[2]; p.New != 0 || loaded.Weeks[0].Documents != 2 {
+		t.Fatalf("after reload: point %+v, week %+v", p, loaded.Weeks[0])
+	}
+}
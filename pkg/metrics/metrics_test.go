@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WritesTextFormat(t *testing.T) {
+	reg := NewRegistry()
+	calls := reg.Counter("llm_calls_total", "LLM calls.", "model")
+	size := reg.Gauge("forum_threads", "Forum threads.")
+	latency := reg.Histogram("request_seconds", "Request latency.", []float64{0.1, 1}, "handler")
+
+	calls.Inc("gemini")
+	calls.Add(2, "gemini")
+	calls.Inc(`we"ird`)
+	size.Set(7)
+	latency.Observe(0.05, "/api/feed")
+	latency.Observe(0.5, "/api/feed")
+	latency.Observe(3, "/api/feed")
+
+	var b strings.Builder
+	if err := reg.Write(&b); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE llm_calls_total counter\n",
+		`llm_calls_total{model="gemini"} 3` + "\n",
+		`llm_calls_total{model="we\"ird"} 1` + "\n",
+		"# TYPE forum_threads gauge\nforum_threads 7\n",
+		`request_seconds_bucket{handler="/api/feed",le="0.1"} 1` + "\n",
+		`request_seconds_bucket{handler="/api/feed",le="1"} 2` + "\n",
+		`request_seconds_bucket{handler="/api/feed",le="+Inf"} 3` + "\n",
+		`request_seconds_sum{handler="/api/feed"} 3.55` + "\n",
+		`request_seconds_count{handler="/api/feed"} 3` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRegistry_NilRecordsNothing(t *testing.T) {
+	var reg *Registry
+	reg.Counter("c", "").Inc()
+	reg.Gauge("g", "").Set(1)
+	reg.Histogram("h", "", nil).Observe(1)
+	if err := reg.Write(&strings.Builder{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+}
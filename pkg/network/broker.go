@@ -1,8 +1,10 @@
 package network
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/cpunion/sci-bot/pkg/agent"
 	"github.com/cpunion/sci-bot/pkg/types"
@@ -15,6 +17,10 @@ type MessageBroker struct {
 	agents    map[string]*agent.Agent
 	topics    map[string][]string // topic -> subscriber agent IDs
 	diversity *DiversityEngine
+
+	// Forwarding loop (see Start)
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // NewMessageBroker creates a new message broker.
@@ -26,11 +32,18 @@ func NewMessageBroker() *MessageBroker {
 	}
 }
 
-// Register registers an agent with the broker.
+// Register registers an agent with the broker. The agent is unregistered,
+// dropping its topic subscriptions, when it stops.
 func (b *MessageBroker) Register(a *agent.Agent) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
+	_, known := b.agents[a.ID()]
 	b.agents[a.ID()] = a
+	b.mu.Unlock()
+
+	if !known {
+		id := a.ID()
+		a.OnStop(func() { b.Unregister(id) })
+	}
 }
 
 // Unregister removes an agent from the broker.
@@ -40,14 +53,8 @@ func (b *MessageBroker) Unregister(agentID string) {
 	delete(b.agents, agentID)
 
 	// Remove from all topic subscriptions
-	for topic, subs := range b.topics {
-		newSubs := make([]string, 0, len(subs))
-		for _, s := range subs {
-			if s != agentID {
-				newSubs = append(newSubs, s)
-			}
-		}
-		b.topics[topic] = newSubs
+	for topic := range b.topics {
+		b.unsubscribeLocked(agentID, topic)
 	}
 }
 
@@ -69,7 +76,12 @@ func (b *MessageBroker) Subscribe(agentID, topic string) {
 func (b *MessageBroker) Unsubscribe(agentID, topic string) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.unsubscribeLocked(agentID, topic)
+}
 
+// unsubscribeLocked removes agentID from topic, dropping topics left without
+// subscribers. Caller must hold b.mu.
+func (b *MessageBroker) unsubscribeLocked(agentID, topic string) {
 	subs := b.topics[topic]
 	newSubs := make([]string, 0, len(subs))
 	for _, s := range subs {
@@ -77,6 +89,10 @@ func (b *MessageBroker) Unsubscribe(agentID, topic string) {
 			newSubs = append(newSubs, s)
 		}
 	}
+	if len(newSubs) == 0 {
+		delete(b.topics, topic)
+		return
+	}
 	b.topics[topic] = newSubs
 }
 
@@ -196,3 +212,43 @@ func (b *MessageBroker) Forward() {
 		}
 	}
 }
+
+// Start forwards the agents' outgoing messages every interval until ctx is
+// done or Stop is called. Calling Start on a running broker is a no-op.
+func (b *MessageBroker) Start(ctx context.Context, interval time.Duration) {
+	b.mu.Lock()
+	if b.cancel != nil {
+		b.mu.Unlock()
+		return
+	}
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				b.Forward()
+			}
+		}
+	}()
+}
+
+// Stop halts the forwarding loop and waits for it to exit.
+func (b *MessageBroker) Stop() {
+	b.mu.Lock()
+	cancel := b.cancel
+	b.cancel = nil
+	b.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	b.wg.Wait()
+}
@@ -0,0 +1,132 @@
+package prompts
+
+import "github.com/cpunion/sci-bot/pkg/types"
+
+// Catalog is the text of one language, apart from the instruction and action
+// prompts, which are templates (see Templates). Fields ending in a format
+// verb are used with fmt; their arguments are noted where the order isn't
+// obvious.
+type Catalog struct {
+	// ProfileHeading introduces an agent's profile files in its instruction.
+	ProfileHeading string
+
+	// Action prompts.
+	Idle      string
+	BellFirst string // the bell rings
+	BellGrace string // grace turns after the bell
+	BellLate  string // grace turns used up
+
+	// Notifications prefixed to action prompts.
+	NoticeReplies   string // count
+	NoticeMentions  string // count
+	NoticeReviews   string // count
+	NoticeAgreed    string // count
+	NoticeSep       string
+	NoticeHeader    string // joined counts
+	NoticeMore      string // count of notifications not listed
+	NoticeReply     string // from, post ID, title
+	NoticeMention   string // from, post ID, title
+	NoticeReview    string // submission ID, title, detail
+	NoticeConsensus string // consensus ID, post title
+	NoticeDecisions string // count
+	NoticeDecision  string // submission ID, title, outcome, rationale
+	// Decisions names a submission's new status in NoticeDecision.
+	Decisions    map[types.SubmissionStatus]string
+	NoticeFooter string
+	ReviewDetail string // verdict, comments
+
+	// Review duty prompts.
+	ReviewDuty     string
+	ReviewAuthor   string // author name
+	ReviewBlind    string
+	ReviewItem     string // submission ID, title, author
+	ReviewDue      string // due date
+	ReviewItemEnd  string
+	ReviewReminded string
+	ReviewNote     string // reminder note
+
+	// Editor duty prompts.
+	EditorDuty        string
+	EditorQueue       string // count
+	EditorQueueMore   string // count
+	EditorQueueItem   string // submission ID, title, author
+	EditorOverdue     string // count
+	EditorOverdueMore string // count
+	EditorOverdueItem string // submission ID, title, reviewer ID, assigned date
+
+	// Research group check-ins.
+	GroupCheckIn string
+	GroupItem    string // group ID, name, topic, members, shared drafts
+	GroupUnread  string // count
+
+	Seminar string // post ID, title, author
+
+	// Nightly memory consolidation.
+	DreamAction  string // date
+	DreamIntro   string // name, date
+	DreamSummary string // previous summary
+	DreamLessons string
+	DreamToday   string
+	DreamEntry   string // time, prompt
+	DreamReply   string // reply
+	DreamError   string // error
+	DreamOutput  string // max summary length, max experiences
+	// SummaryLessons heads the lessons in an agent's memory summary.
+	SummaryLessons string
+
+	// Goal-directed plans (-planner).
+	PlanAction  string // goal
+	PlanIntro   string // name, domains
+	PlanSummary string // memory summary
+	PlanAgenda  string // open goals and projects
+	PlanPending string // pending notifications
+	PlanActions string // available actions
+	PlanOutput  string // max steps
+	PlanGoal    string // domains, for heuristic plans
+	PlanStep    string // goal, step, steps
+
+	// Out-of-band interviews (cmd/interview), appended to the instruction.
+	InterviewIntro    string
+	InterviewBeliefs  string
+	InterviewBelief   string // key, value
+	InterviewPeers    string
+	InterviewPeer     string // name, relationship state, trust, interactions
+	InterviewTheories string
+	InterviewTheory   string // title, knowledge level, confidence
+
+	// Background thread summaries by the scheduler's summarizer model.
+	ThreadSummaryIntro    string // title, author, subreddit
+	ThreadSummaryPrevious string // previous summary
+	ThreadSummaryPost     string // post content
+	ThreadSummaryComments string
+	ThreadSummaryComment  string // author, content
+	ThreadSummaryOutput   string // max summary length
+
+	// Weekly digests communicators post to r/meta.
+	DigestSubreddit string // r/meta description
+	DigestTitle     string // ISO week
+	DigestIntro     string // ISO week
+	DigestSection   string // subreddit
+	DigestThread    string // title, post ID, author, score, replies
+	DigestSummary   string // thread summary
+
+	// Spectator questions the scheduler posts from the inbox.
+	VisitorName   string
+	VisitorFooter string // asker, votes
+
+	// ExternalAuthor names scenario literature drops without an author.
+	ExternalAuthor string
+	SeedPosts      []SeedPost
+
+	// Tools maps tool names to descriptions; tools not listed keep their
+	// built-in (Chinese) description.
+	Tools map[string]string
+}
+
+// SeedPost is a built-in starting forum post.
+type SeedPost struct {
+	Title     string
+	Content   string
+	Abstract  string
+	Subreddit types.Subreddit
+}
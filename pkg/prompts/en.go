@@ -0,0 +1,212 @@
+package prompts
+
+import (
+	"fmt"
+
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// en is the English text.
+var en = Catalog{
+	ProfileHeading: "\n\n## Profile\nThis is your personal profile; where it conflicts with the above, the profile wins.\n\n",
+
+	Idle:      "Please stand by.",
+	BellFirst: "The evening bell rings: that's it for today. Wrap up briefly and rest.",
+	BellGrace: "The evening bell has rung. Please finish politely and go rest.",
+	BellLate:  "It's late. Rest now and don't open new topics.",
+
+	NoticeReplies:   "%d new replies",
+	NoticeMentions:  "%d @ mentions",
+	NoticeReviews:   "%d new reviews",
+	NoticeAgreed:    "%d consensus requests achieved",
+	NoticeSep:       ", ",
+	NoticeHeader:    "Notifications: you have %s.\n",
+	NoticeMore:      "- ...and %d more\n",
+	NoticeReply:     "- %s replied to you (%s, \"%s\")\n",
+	NoticeMention:   "- %s mentioned you in %s \"%s\"\n",
+	NoticeReview:    "- Your submission %s \"%s\" received a review (%s)\n",
+	NoticeDecisions: "%d editorial decisions",
+	NoticeDecision:  "- Your submission %s \"%s\" was %s. Editor's note: %s\n",
+	Decisions: map[types.SubmissionStatus]string{
+		types.SubmissionAccepted:      "accepted",
+		types.SubmissionRejected:      "rejected",
+		types.SubmissionMinorRevision: "sent back for minor revision",
+		types.SubmissionMajorRevision: "sent back for major revision",
+	},
+	NoticeConsensus: "- Your consensus request %[1]s on \"%[2]s\" was achieved; write it up as a collaborative draft with create_draft (consensus_id=%[1]s)\n",
+	NoticeFooter:    "Use browse_mentions or read_post to see them, and comment to reply to those worth answering.\n",
+	ReviewDetail:    "%s: %s",
+
+	ReviewDuty:     "Journal review duty: the submissions below are assigned to you. Read each in full with read_submission, then give scores and a verdict with review_paper before the deadline.\n",
+	ReviewAuthor:   "by %s",
+	ReviewBlind:    "double-blind, author hidden",
+	ReviewItem:     "- %s \"%s\" (%s",
+	ReviewDue:      ", due %s",
+	ReviewItemEnd:  ")",
+	ReviewReminded: " The editor sent a reminder",
+	ReviewNote:     ": %s",
+
+	EditorDuty:        "Editor duty: use view_editor_queue for details.\n",
+	EditorQueue:       "Submissions awaiting reviewers (%d): assign them with assign_reviewers; desk_reject those clearly out of scope, with a reason.\n",
+	EditorQueueMore:   "- ...and %d more\n",
+	EditorQueueItem:   "- %s \"%s\" (by %s)\n",
+	EditorOverdue:     "Overdue reviews (%d): send reminders with remind_reviewer.\n",
+	EditorOverdueMore: "- ...and %d more\n",
+	EditorOverdueItem: "- %s \"%s\" reviewer %s (assigned %s)\n",
+
+	GroupCheckIn: "Research group check-in: read the channel and shared drafts with read_group, then use group_message to share progress, claim tasks or propose next steps (such as co-writing a draft or asking members to review). If there's nothing new, say so briefly.\n",
+	GroupItem:    "- %s \"%s\" (%s): %d members, %d shared drafts",
+	GroupUnread:  ", %d new messages",
+
+	Seminar: "Weekly seminar: everyone discusses post %s \"%s\" (by %s) today. Read it and its comments with read_post, then comment on it with your views, questions or additions, and respond to what others have said.",
+
+	DreamAction:  "Nightly memory consolidation: %s",
+	DreamIntro:   "You are %s. Today (%s) is over; organize today's experiences into long-term memory.\n\n",
+	DreamSummary: "## Previous memory summary\n%s\n\n",
+	DreamLessons: "## Lessons so far\n",
+	DreamToday:   "## Today's log\n",
+	DreamEntry:   "- [%s] prompt: %s",
+	DreamReply:   " | reply: %s",
+	DreamError:   " | error: %s",
+	DreamOutput: `
+## Output
+Output a single JSON object and nothing else:
+{"summary": "a new summary merging the old one with today's experiences (at most %d characters; keep ongoing discussions, commitments, key people and post IDs)",
+ "experiences": [{"summary": "something important from today", "lesson": "what you learned from it"}],
+ "topics": ["topics you are currently following"]}
+At most %d experiences; record only what is truly worth remembering long term.`,
+	SummaryLessons: "\n\nLessons:",
+
+	PlanAction:  "Plan: %s",
+	PlanIntro:   "You are %s, a scientist working on %s. Plan your next few turns in the research community as one coherent arc toward a single goal.\n\n",
+	PlanSummary: "## What you remember\n%s\n\n",
+	PlanAgenda:  "## Your research agenda\n%s\n\n",
+	PlanPending: "You have %d unread notifications (replies, mentions, reviews).\n\n",
+	PlanActions: "## Actions you can take on a turn\n%s\n\n",
+	PlanOutput: `## Output
+Output a single JSON object and nothing else:
+{"goal": "what this plan should achieve", "steps": [{"action": "one of the actions above", "note": "what exactly to do on that turn"}]}
+At most %d steps, in the order you will take them.`,
+	PlanGoal: "make progress in %s",
+	PlanStep: "Your current plan: %s (step %d of %d).",
+
+	InterviewIntro:    "\n\n## Interview\nThe simulation is paused. A researcher studying the community is talking with you outside it: you have no tools, nothing you say is posted, and this conversation will not become part of your memory. Answer as yourself, candidly, from what you have experienced and believe; say so when you don't know or aren't sure.\n",
+	InterviewBeliefs:  "\n### Your beliefs\n",
+	InterviewBelief:   "- %s: %s\n",
+	InterviewPeers:    "\n### People you know\n",
+	InterviewPeer:     "- %s (%s): trust %.2f, %d interactions\n",
+	InterviewTheories: "\n### Theories you know\n",
+	InterviewTheory:   "- %s (%s, confidence %.2f)\n",
+
+	ThreadSummaryIntro:    "Summarize this forum thread for scientists who have not read it.\n\n# %s\nby %s in r/%s\n\n",
+	ThreadSummaryPrevious: "## Previous summary\n%s\n\n",
+	ThreadSummaryPost:     "## Post\n%s\n\n",
+	ThreadSummaryComments: "## Replies (oldest first)\n",
+	ThreadSummaryComment:  "- %s: %s\n",
+	ThreadSummaryOutput:   "\n## Output\nWrite only the summary, at most %d characters: the question or claim, the main positions and who holds them, evidence cited (post, paper and experiment IDs), points of agreement and what is still open.",
+
+	DigestSubreddit: "Community meta: weekly digests of the forum",
+	DigestTitle:     "Weekly digest %s",
+	DigestIntro:     "The top threads of each subreddit in %s, by score and replies. Open one with read_post to join in.\n\n",
+	DigestSection:   "## r/%s\n\n",
+	DigestThread:    "- **%s** (%s) by %s: score %d, %d replies\n",
+	DigestSummary:   "  > %s\n",
+
+	VisitorName:   "Visitor",
+	VisitorFooter: "\n\n---\n*A question from %s, a human watching the community (%d votes from other spectators). Replies are welcome.*",
+
+	ExternalAuthor: "External literature",
+	SeedPosts: []SeedPost{
+		{
+			Title:     "Thoughts on free fall",
+			Content:   "In a vacuum, would a feather and an iron ball fall at the same speed?",
+			Abstract:  "Questioning Aristotelian physics",
+			Subreddit: types.SubPhysics,
+		},
+		{
+			Title:     "First principles of geometry",
+			Content:   "Through two points there is exactly one straight line. This is a self-evident axiom.",
+			Abstract:  "The foundation of Euclidean geometry",
+			Subreddit: types.SubMathematics,
+		},
+		{
+			Title:     "Why theories must be falsifiable",
+			Content:   "Any scientific theory must allow for being overturned by experiment; otherwise it is mere metaphysics.",
+			Abstract:  "A core requirement of the scientific method",
+			Subreddit: types.SubPhilosophy,
+		},
+	},
+
+	Tools: map[string]string{
+		// Forum
+		"browse_forum":        "Browse forum posts. sort_by hot (default; score decays over time) or new gives recommendations personalized to your interests and relationships; top sorts by score and controversial by how evenly up and down votes are split, and window (day/week/all) limits the time range. Can filter by subreddit; also returns all available subreddits.",
+		"read_post":           "Read a post in full, with its comment tree (including parent_id and depth).",
+		"get_thread_digest":   "For summarizing several threads: returns the thread summary (if any) and the replies since it; long threads without a summary are marked needs_summary.",
+		"save_thread_summary": "Save a thread summary to the cache (for multi-thread summaries). Call only after you have summarized the thread.",
+		"browse_mentions":     "See @ mentions of and replies to you; handle these first. Returns only unread items by default and marks them read; include_read=true also shows ones you have seen.",
+		"create_post":         "Publish a new forum post. Title, content and subreddit are required; when presenting a formal theory, give its theory_id so readers learn it from you. attachments can carry equations (kind=math, latex), small data tables (kind=table, columns and rows) or experiment plots (kind=plot: y against x, parameter or metric names, over the run_experiment IDs in experiments); refer to them in the text as eq-1, table-1, fig-1 and so on. If a near-identical thread already exists, its ID is returned; comment there instead of reposting.",
+		"create_subreddit":    "Create a new subreddit (name of lowercase letters/digits/-/_, with a short description). Use only when no existing subreddit fits.",
+		"vote":                "Vote on a post: upvote or downvote.",
+		"comment":             "Reply to a post or comment. Pass parent_id to reply to a comment, otherwise post_id replies at the top level.",
+		"report_post":         "Report a low-quality, duplicate, spam or off-topic post/comment (category: spam/duplicate/low_quality/off_topic/hostile/other, with a reason). Don't report disagreements.",
+		"view_reports":        "Moderators only: see open reports (most reported first).",
+		"moderate_post":       "Moderators only: hide (still readable by ID), remove (no more votes or replies) or restore a post/comment, or dismiss its reports; a reason is required.",
+		// Publication
+		"create_draft":            "Create an academic draft (idea or collaborative, Markdown). Suggested sections: Abstract, Introduction, Method/Theory, Predictions & Verification Plan, Limitations, References (may cite forum-.../seed-...).",
+		"update_draft":            "Edit a draft you co-author: mode=append (default) adds to the end of a section, mode=replace rewrites it; section is a Markdown heading (e.g. Method), created if missing; leave it empty to edit the whole draft. Every edit is credited to you.",
+		"invite_coauthor":         "Invite another agent (agent_id) to co-author your draft; they can then edit it with update_draft and see it in list_my_drafts.",
+		"list_my_drafts":          "List drafts you author (created or invited to): sections, coauthors and how many characters each has written, most recently edited first.",
+		"request_consensus":       "Request consensus under a forum post (posts a comment automatically).",
+		"support_consensus":       "Support a consensus request (consensus_id from its comment or assess_consensus). Once enough agents support it the consensus is achieved and the requester is prompted to write a collaborative draft. Voting again switches sides.",
+		"oppose_consensus":        "Oppose a consensus request (the discussion has not converged yet). Opposition raises the bar for achieving it; voting again switches sides.",
+		"submit_paper":            "Submit a paper for journal review (Markdown, from draft_id or direct content). Be complete: Abstract, Introduction, Background/Related Work, Method/Theory, Experiments/Verification, Limitations, References. When resubmitting an improved rejected paper, cite the original submission ID in resubmission_of. Cite run_experiment experiment IDs as evidence in experiments. journal picks the target journal (ID or name, see list_journals); empty routes by domain. With preprint true the paper is also posted on the forum as a preprint (in subreddit, default general) so it can be discussed during review; journals with an embargo don't accept preprints. attachments carry equations, tables and experiment plots as in create_post.",
+		"assess_readiness":        "Assess whether your own research idea is mature enough to draft.",
+		"assess_consensus":        "Assess how mature the consensus in a forum thread is, to decide whether to start a collaborative draft.",
+		"review_paper":            "Review a submission (reviewer role). Each score is 0-10; journals may set an acceptance threshold, and accept becomes minor revision when the average falls short.",
+		"read_submission":         "Read a submission in full before reviewing it (reviewer role). Double-blind journals hide the author until the decision; judge only the content and don't guess who wrote it.",
+		"view_my_rejected_papers": "See my rejected submissions with their reviews and rejection reasons, to improve them and resubmit with submit_paper's resubmission_of.",
+		"list_journals":           "List the journals you can submit to: ID, name, domains, acceptance threshold (average review score), whether review is double-blind, embargo (no preprints) and paper counts.",
+		"challenge_claim": fmt.Sprintf("Formally dispute a published paper or forum post: claim is the disputed statement, counter_arguments your rebuttal, evidence experiment, paper or post IDs or outside sources. One dispute per target, never your own work; journal papers disputed by %d or more agents are marked disputed.",
+			types.DisputeThreshold),
+		"retract_paper": fmt.Sprintf("Vote to retract a published journal paper (reviewers and editors only); reason explains why, e.g. a fatal error or unreliable data. One vote per paper, never your own; the paper is retracted once %d agents vote and the retraction notice is shown publicly.",
+			types.RetractionQuorum),
+		// Social
+		"view_relationships": "See my relationships with other agents. Can filter by relationship status.",
+		"update_trust":       "Update how much I trust an agent. Positive values raise trust, negative values lower it.",
+		"view_knowledge":     "See the knowledge and theories I have mastered or know about. Can filter by mastery.",
+		"view_my_karma":      "See my karma: post/comment scores, votes received and cast, and my recent votes.",
+		// Memory
+		"recall_memory": "Search my past ideas, replies and lessons by meaning. query is the topic or question to recall, k the number of results (default 5).",
+		"record_note":   "Write down a structured research note: topic, insight (what I learned or concluded) and optional open_questions to follow up. Notes are kept per day apart from the conversation transcript, and recall_memory finds them.",
+		// Agenda
+		"view_agenda": "See my research agenda: the goals I'm pursuing and my ongoing projects. all=true also lists finished and abandoned ones.",
+		"set_goal": fmt.Sprintf("Set or change a research goal. Without goal_id, sets a new goal (text required); with goal_id, changes its text or sets status to active, paused, done or abandoned. At most %d open goals at a time.",
+			types.MaxOpenGoals),
+		"update_project": fmt.Sprintf("Start or update a research project. Without project_id, starts a new one (title required); with project_id, changes its description, status (active, paused, done, abandoned), collaborator IDs or latest progress. At most %d open projects at a time.",
+			types.MaxOpenProjects),
+		// Editor
+		"view_editor_queue": "Editors only: see submissions without reviewers (with the target journal's domains and recommended reviewers ranked by domain match and current load), and overdue reviews.",
+		"assign_reviewers":  "Editors only: assign reviewers to a submission (reviewer_ids, not the author), replacing earlier assignments; prefer reviewers matching the domain with a light load.",
+		"desk_reject":       "Editors only: reject a submission without review when it is clearly outside the journal's scope or not a paper; a reason is required. Never reject a paper for being novel or controversial.",
+		"remind_reviewer":   "Editors only: remind a reviewer whose review is overdue (optionally with a note); the reminder shows up in their review duty.",
+		// Knowledge
+		"list_axiom_systems": "List axiom systems (with each axiom's ID) and the theories built on them. Use system_id to see a single system.",
+		"propose_theory":     "Propose a new theory on an axiom system (or custom axioms), optionally with extra axioms, hypotheses and conjectures.",
+		"derive_from_axioms": "Add a theorem derived from axioms to a theory. references must list the IDs of the axioms or existing theorems used.",
+		"challenge_theory":   "Challenge someone else's theory: verdict reject (the theory is wrong) or revise (it needs fixing), stating the reason and the problem found.",
+		// Groups
+		"list_groups": "See research groups (name, topic, members, shared drafts). mine=true lists only the groups I belong to.",
+		"create_group": fmt.Sprintf("Create a research group and become its first member (name and research topic required). Each agent can be in at most %d groups and each group has at most %d members; check list_groups for a similar group first.",
+			group.MaxGroupsPerAgent, group.MaxMembers),
+		"join_group":    "Join a research group (leave=true leaves it).",
+		"read_group":    "Read my group's channel messages and shared drafts (members only).",
+		"group_message": "Post in a group channel to coordinate work (members only); attach draft_id to share a draft with the group.",
+		// Experiments
+		"run_experiment": "Run a virtual experiment to test a hypothesis. Templates: projectile(v0, angle_deg, g, drag)→range/max_height/flight_time; " +
+			"pendulum(length, amplitude_deg, g, damping)→period/small_angle_period/period_ratio; " +
+			"random_walk(steps, walkers, dims)→msd/msd_per_step; monte_carlo_pi(samples)→pi_estimate/abs_error; " +
+			"logistic_map(r, x0, iterations)→lyapunov/period/final_x; decay(n0, half_life, duration)→remaining_fraction/estimated_half_life. " +
+			"prediction is a falsifiable prediction {metric, op(<,<=,>,>=,≈), value, tolerance}; the result is supported/refuted/inconclusive.",
+	},
+}
@@ -0,0 +1,80 @@
+// Package prompts holds the text agents are given — system instructions,
+// action prompts, notifications and tool descriptions — for each supported
+// language, so a simulation can run in Chinese, English or a mix of both.
+package prompts
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Lang is a prompt language.
+type Lang string
+
+const (
+	Chinese Lang = "zh"
+	English Lang = "en"
+)
+
+// Mode is a run's language setting: a single language, or Mixed.
+type Mode string
+
+// Mixed gives each agent without a language of its own Chinese or English
+// prompts, split roughly evenly and stable across runs.
+const Mixed Mode = "mixed"
+
+// ParseMode parses a -lang value: zh, en or mixed (empty means zh).
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return Mode(Chinese), nil
+	case Mode(Chinese), Mode(English), Mixed:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown language %q (use zh, en or mixed)", s)
+	}
+}
+
+// Default is the language of run-level text such as seed content and
+// scenario drops; mixed runs default to Chinese.
+func (m Mode) Default() Lang {
+	if m == Mode(English) {
+		return English
+	}
+	return Chinese
+}
+
+// For returns an agent's prompt language: the persona's own language if
+// set and supported, otherwise the mode's.
+func (m Mode) For(p *types.Persona) Lang {
+	if p != nil {
+		if lang := Lang(strings.ToLower(strings.TrimSpace(p.Language))); catalogs[lang] != nil {
+			return lang
+		}
+	}
+	if m != Mixed || p == nil {
+		return m.Default()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(p.ID))
+	if h.Sum32()%2 == 1 {
+		return English
+	}
+	return Chinese
+}
+
+// For returns the catalog for a language, falling back to Chinese.
+func For(lang Lang) *Catalog {
+	if c := catalogs[lang]; c != nil {
+		return c
+	}
+	return catalogs[Chinese]
+}
+
+var catalogs = map[Lang]*Catalog{
+	Chinese: &zh,
+	English: &en,
+}
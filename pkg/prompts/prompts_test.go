@@ -0,0 +1,158 @@
+package prompts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestModeFor(t *testing.T) {
+	if _, err := ParseMode("fr"); err == nil {
+		t.Fatalf("expected error for unsupported language")
+	}
+	mode, err := ParseMode(" Mixed ")
+	if err != nil || mode != Mixed {
+		t.Fatalf("ParseMode(mixed) = %q, %v", mode, err)
+	}
+
+	counts := map[Lang]int{}
+	for i := range 40 {
+		p := &types.Persona{ID: fmt.Sprintf("agent-%d", i)}
+		lang := mode.For(p)
+		if again := mode.For(p); again != lang {
+			t.Fatalf("mixed language for %s not stable: %s then %s", p.ID, lang, again)
+		}
+		counts[lang]++
+	}
+	if counts[Chinese] == 0 || counts[English] == 0 {
+		t.Fatalf("mixed mode should use both languages, got %v", counts)
+	}
+
+	p := &types.Persona{ID: "agent-1", Language: "en"}
+	if got := Mode(Chinese).For(p); got != English {
+		t.Fatalf("persona language should win, got %s", got)
+	}
+	p.Language = "fr"
+	if got := Mode(Chinese).For(p); got != Chinese {
+		t.Fatalf("unsupported persona language should fall back to the mode, got %s", got)
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	p := &types.Persona{
+		Name:      "Ada",
+		Role:      types.RoleReviewer,
+		Domains:   []string{"physics"},
+		Moderator: true,
+	}
+	var none *Templates
+	for _, lang := range []Lang{Chinese, English} {
+		text := none.Instruction(lang, p)
+		if strings.Contains(text, "%!") || strings.Contains(text, "<no value>") {
+			t.Fatalf("%s instruction has bad placeholders:\n%s", lang, text)
+		}
+		if !strings.Contains(text, "Ada") || !strings.Contains(text, "{agent_summary?}") {
+			t.Fatalf("%s instruction missing name or summary slot", lang)
+		}
+		for _, action := range []string{"browse", "read", "post", "interact", "review", "observe"} {
+			if len(none.ActionTexts(lang, action, p)) == 0 {
+				t.Fatalf("%s: no prompts for action %s", lang, action)
+			}
+		}
+		if len(For(lang).SeedPosts) < 3 {
+			t.Fatalf("%s: want 3 seed posts, got %d", lang, len(For(lang).SeedPosts))
+		}
+	}
+}
+
+func TestLoadTemplates_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "en"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("vars.json", `{"lab": "Cavendish"}`)
+	write("en/instruction.tmpl", `{{define "instruction.reviewer"}}Referee {{.Persona.Name}} at {{.Vars.lab}}. {{.RoleGuidance}}{{end}}`)
+	write("en/actions.tmpl", `{{define "action.post"}}
+Post from {{.Vars.lab}}.
+
+Post again.
+{{end}}`)
+
+	tmpls, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	reviewer := &types.Persona{Name: "Ada", Role: types.RoleReviewer}
+	explorer := &types.Persona{Name: "Bo", Role: types.RoleExplorer}
+
+	got := tmpls.Instruction(English, reviewer)
+	if !strings.HasPrefix(got, "Referee Ada at Cavendish. As a professional reviewer") {
+		t.Fatalf("reviewer override not used: %q", got)
+	}
+	if got := tmpls.Instruction(English, explorer); got != builtin.Instruction(English, explorer) {
+		t.Fatalf("other roles should keep the built-in instruction")
+	}
+	if got := tmpls.Instruction(Chinese, reviewer); got != builtin.Instruction(Chinese, reviewer) {
+		t.Fatalf("other languages should keep the built-in instruction")
+	}
+	if got := tmpls.ActionTexts(English, "post", explorer); len(got) != 2 || got[0] != "Post from Cavendish." {
+		t.Fatalf("action override: %q", got)
+	}
+	if got := tmpls.ActionTexts(English, "browse", explorer); len(got) != 3 {
+		t.Fatalf("unchanged actions should keep the built-ins, got %q", got)
+	}
+
+	write("en/actions.tmpl", `{{define "action.post"}}{{.Persona.Nope}}{{end}}`)
+	if _, err := LoadTemplates(dir); err == nil {
+		t.Fatalf("expected a broken template to fail at load")
+	}
+}
+
+func TestLocalizeTools(t *testing.T) {
+	type args struct {
+		Query string `json:"query"`
+	}
+	inner, err := functiontool.New(functiontool.Config{
+		Name:        "recall_memory",
+		Description: "按语义检索我过去的想法",
+	}, func(tool.Context, args) (map[string]any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New: %v", err)
+	}
+
+	if got := LocalizeTools(Chinese, []tool.Tool{inner}); got[0] != inner {
+		t.Fatalf("Chinese tools should be returned as-is")
+	}
+	localized := LocalizeTools(English, []tool.Tool{inner})[0]
+	if localized.Name() != "recall_memory" || localized.Description() != en.Tools["recall_memory"] {
+		t.Fatalf("unexpected localized tool: %s %q", localized.Name(), localized.Description())
+	}
+
+	req := &model.LLMRequest{}
+	if err := localized.(functionTool).ProcessRequest(nil, req); err != nil {
+		t.Fatalf("ProcessRequest: %v", err)
+	}
+	decl := req.Config.Tools[0].FunctionDeclarations[0]
+	if decl.Description != en.Tools["recall_memory"] || decl.ParametersJsonSchema == nil {
+		t.Fatalf("declaration not localized: %+v", decl)
+	}
+	if req.Tools["recall_memory"] == nil {
+		t.Fatalf("tool not registered in request")
+	}
+}
@@ -0,0 +1,208 @@
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Built-in instruction and action templates, one directory per language.
+// Copy them into a prompts directory to start an override.
+//
+//go:embed templates
+var builtinFS embed.FS
+
+// Templates renders agent instructions and action prompts from text/template
+// files. Each language's templates are the built-in ones under
+// templates/<lang>/, with any <dir>/<lang>/*.tmpl from a prompts directory
+// parsed on top: a file that defines a template replaces the built-in one of
+// the same name. Per-role variants are templates named "<name>.<role>", e.g.
+// "instruction.reviewer" or "action.post.explorer", and win over "<name>"
+// for agents of that role. A nil *Templates renders the built-ins.
+type Templates struct {
+	sets map[Lang]*template.Template
+	// vars are injected as .Vars, from <dir>/vars.json.
+	vars map[string]string
+}
+
+// templateData is what instruction and action templates are executed with.
+type templateData struct {
+	Persona *types.Persona
+	Lang    Lang
+	Vars    map[string]string
+	// RoleGuidance is the rendered "role.<role>" template (instruction only).
+	RoleGuidance string
+}
+
+var templateFuncs = template.FuncMap{
+	// percent formats a 0-1 trait as a whole percentage without the sign.
+	"percent": func(v float64) string { return fmt.Sprintf("%.0f", v*100) },
+}
+
+var builtin = mustLoadTemplates("")
+
+func mustLoadTemplates(dir string) *Templates {
+	t, err := LoadTemplates(dir)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadTemplates loads the built-in templates overridden by those in dir
+// (empty loads the built-ins only). Every role's instruction and action
+// prompts are rendered once, so template errors surface here rather than
+// mid-run.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{sets: make(map[Lang]*template.Template, len(catalogs))}
+	for lang := range catalogs {
+		set, err := template.New(string(lang)).Funcs(templateFuncs).Option("missingkey=zero").
+			ParseFS(builtinFS, "templates/"+string(lang)+"/*.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("built-in %s templates: %w", lang, err)
+		}
+		if dir != "" {
+			files, err := filepath.Glob(filepath.Join(dir, string(lang), "*.tmpl"))
+			if err != nil {
+				return nil, err
+			}
+			if len(files) > 0 {
+				if set, err = set.ParseFiles(files...); err != nil {
+					return nil, fmt.Errorf("%s templates: %w", lang, err)
+				}
+			}
+		}
+		t.sets[lang] = set
+	}
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, "vars.json"))
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &t.vars); err != nil {
+				return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, "vars.json"), err)
+			}
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// check executes every template with a sample agent of each role.
+func (t *Templates) check() error {
+	roles := []types.AgentRole{types.RoleExplorer, types.RoleBuilder, types.RoleReviewer,
+		types.RoleSynthesizer, types.RoleCommunicator, types.RoleEditor}
+	for lang, set := range t.sets {
+		for _, role := range roles {
+			p := &types.Persona{ID: "check", Name: "check", Role: role, Domains: []string{"physics"}, Moderator: true}
+			if _, err := t.instruction(lang, p); err != nil {
+				return err
+			}
+			for _, tmpl := range set.Templates() {
+				if err := tmpl.Execute(io.Discard, t.data(lang, p)); err != nil {
+					return fmt.Errorf("%s template %s: %w", lang, tmpl.Name(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Instruction renders an agent's system instruction. The result keeps the
+// {agent_summary?} session state slot. A template that fails to render is
+// logged and the built-in instruction used instead.
+func (t *Templates) Instruction(lang Lang, p *types.Persona) string {
+	if t == nil {
+		t = builtin
+	}
+	text, err := t.instruction(lang, p)
+	if err != nil && t != builtin {
+		log.Printf("Prompt template: %v; using the built-in instruction", err)
+		text, err = builtin.instruction(lang, p)
+	}
+	if err != nil {
+		log.Printf("Prompt template: %v", err)
+	}
+	return text
+}
+
+// ActionTexts returns the prompt options for a weighted action such as
+// "browse": the non-blank lines of its rendered template.
+func (t *Templates) ActionTexts(lang Lang, action string, p *types.Persona) []string {
+	if t == nil {
+		t = builtin
+	}
+	texts, err := t.actionTexts(lang, action, p)
+	if err != nil && t != builtin {
+		log.Printf("Prompt template: %v; using the built-in prompts", err)
+		texts, err = builtin.actionTexts(lang, action, p)
+	}
+	if err != nil {
+		log.Printf("Prompt template: %v", err)
+	}
+	return texts
+}
+
+func (t *Templates) instruction(lang Lang, p *types.Persona) (string, error) {
+	data := t.data(lang, p)
+	guidance, err := t.render(lang, "role."+string(p.Role), "", data)
+	if err != nil {
+		return "", err
+	}
+	data.RoleGuidance = strings.TrimSpace(guidance)
+	return t.render(lang, "instruction", string(p.Role), data)
+}
+
+func (t *Templates) actionTexts(lang Lang, action string, p *types.Persona) ([]string, error) {
+	text, err := t.render(lang, "action."+action, string(p.Role), t.data(lang, p))
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+func (t *Templates) data(lang Lang, p *types.Persona) *templateData {
+	return &templateData{Persona: p, Lang: lang, Vars: t.vars}
+}
+
+// render executes "<name>.<role>" if defined, else name. A missing template
+// renders as empty.
+func (t *Templates) render(lang Lang, name, role string, data *templateData) (string, error) {
+	set := t.sets[lang]
+	if set == nil {
+		set = t.sets[Chinese]
+	}
+	var tmpl *template.Template
+	if role != "" {
+		tmpl = set.Lookup(name + "." + role)
+	}
+	if tmpl == nil {
+		tmpl = set.Lookup(name)
+	}
+	if tmpl == nil {
+		return "", nil
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%s template %s: %w", lang, tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
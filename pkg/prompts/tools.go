@@ -0,0 +1,78 @@
+package prompts
+
+import (
+	"fmt"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// LocalizeTools gives tools the catalog's description for lang. The tools
+// are built with Chinese descriptions, so for Chinese they are returned
+// as-is; tools without a catalog entry are kept unchanged.
+func LocalizeTools(lang Lang, tools []tool.Tool) []tool.Tool {
+	descs := For(lang).Tools
+	if len(descs) == 0 {
+		return tools
+	}
+	out := make([]tool.Tool, len(tools))
+	for i, t := range tools {
+		out[i] = t
+		if desc, ok := descs[t.Name()]; ok {
+			if inner, ok := t.(functionTool); ok {
+				out[i] = &localizedTool{functionTool: inner, desc: desc}
+			}
+		}
+	}
+	return out
+}
+
+// functionTool is the method set ADK uses to declare and call a function
+// tool.
+type functionTool interface {
+	tool.Tool
+	Declaration() *genai.FunctionDeclaration
+	Run(ctx tool.Context, args any) (map[string]any, error)
+	ProcessRequest(ctx tool.Context, req *model.LLMRequest) error
+}
+
+// localizedTool replaces a tool's description and leaves its schema and
+// behaviour to the wrapped tool.
+type localizedTool struct {
+	functionTool
+	desc string
+}
+
+func (t *localizedTool) Description() string { return t.desc }
+
+func (t *localizedTool) Declaration() *genai.FunctionDeclaration {
+	decl := t.functionTool.Declaration()
+	if decl != nil {
+		decl.Description = t.desc
+	}
+	return decl
+}
+
+// ProcessRequest lets the wrapped tool register itself, then swaps the
+// description in the declaration it added. Calls still reach the wrapped
+// tool, which is what the request's tool map holds.
+func (t *localizedTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if err := t.functionTool.ProcessRequest(ctx, req); err != nil {
+		return err
+	}
+	if req.Config == nil {
+		return fmt.Errorf("tool %s: no declarations in request", t.Name())
+	}
+	for _, gt := range req.Config.Tools {
+		if gt == nil {
+			continue
+		}
+		for _, decl := range gt.FunctionDeclarations {
+			if decl != nil && decl.Name == t.Name() {
+				decl.Description = t.desc
+			}
+		}
+	}
+	return nil
+}
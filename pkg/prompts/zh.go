@@ -0,0 +1,135 @@
+package prompts
+
+import "github.com/cpunion/sci-bot/pkg/types"
+
+// zh is the built-in Chinese text; tool descriptions live with the tools.
+var zh = Catalog{
+	ProfileHeading: "\n\n## 个人档案\n以下是你的个人档案，与上文冲突时以档案为准。\n\n",
+
+	Idle:      "请保持待命。",
+	BellFirst: "夜间敲钟：今天到此为止，请简短收尾并休息。",
+	BellGrace: "夜间敲钟已响，请礼貌结束并去休息。",
+	BellLate:  "夜已深，请立即休息，不再展开新话题。",
+
+	NoticeReplies:   "%d 条新回复",
+	NoticeMentions:  "%d 次 @ 提及",
+	NoticeReviews:   "%d 份新审稿意见",
+	NoticeAgreed:    "%d 项共识已达成",
+	NoticeSep:       "、",
+	NoticeHeader:    "通知：你有%s。\n",
+	NoticeMore:      "- ……另有 %d 条\n",
+	NoticeReply:     "- %s 回复了你（%s，《%s》）\n",
+	NoticeMention:   "- %s 在 %s《%s》中提到了你\n",
+	NoticeReview:    "- 你的投稿 %s《%s》收到审稿意见（%s）\n",
+	NoticeDecisions: "%d 项投稿结果",
+	NoticeDecision:  "- 你的投稿 %s《%s》%s。编辑意见：%s\n",
+	Decisions: map[types.SubmissionStatus]string{
+		types.SubmissionAccepted:      "已被录用",
+		types.SubmissionRejected:      "被拒稿",
+		types.SubmissionMinorRevision: "需小修后重投",
+		types.SubmissionMajorRevision: "需大修后重投",
+	},
+	NoticeConsensus: "- 你在《%[2]s》发起的共识请求 %[1]s 已达成，请用 create_draft（consensus_id=%[1]s）把它写成协作草案\n",
+	NoticeFooter:    "可用 browse_mentions 或 read_post 查看，值得回应的请用 comment 回复。\n",
+	ReviewDetail:    "%s：%s",
+
+	ReviewDuty:     "期刊审稿任务：以下投稿分配给你，先用 read_submission 阅读全文，再在截止前用 review_paper 给出评分与结论。\n",
+	ReviewAuthor:   "作者 %s",
+	ReviewBlind:    "双盲评审，作者已隐去",
+	ReviewItem:     "- %s《%s》（%s",
+	ReviewDue:      "，截止 %s",
+	ReviewItemEnd:  "）",
+	ReviewReminded: " 编辑已催审",
+	ReviewNote:     "：%s",
+
+	EditorDuty:        "编辑任务：请用 view_editor_queue 查看详情。\n",
+	EditorQueue:       "待分配审稿人的投稿（%d 篇）：用 assign_reviewers 分配；明显超出收稿范围的用 desk_reject 并写明理由。\n",
+	EditorQueueMore:   "- ……另有 %d 篇\n",
+	EditorQueueItem:   "- %s《%s》（作者 %s）\n",
+	EditorOverdue:     "超期未交的审稿（%d 份）：用 remind_reviewer 催审。\n",
+	EditorOverdueMore: "- ……另有 %d 份\n",
+	EditorOverdueItem: "- %s《%s》审稿人 %s（%s 分配）\n",
+
+	GroupCheckIn: "研究小组协调：请用 read_group 查看频道与共享草案，再用 group_message 同步进展、认领分工或提出下一步（如共同撰写草案、请组员审阅）。没有新进展时简短说明即可。\n",
+	GroupItem:    "- %s「%s」（%s）：%d 名成员，%d 份共享草案",
+	GroupUnread:  "，%d 条新消息",
+
+	Seminar: "每周研讨会：全体成员今天一起讨论帖子 %s《%s》（作者 %s）。请用 read_post 阅读全文与已有评论，再用 comment 在该帖下发表你的观点、质疑或补充，尽量回应其他人的发言。",
+
+	DreamAction:  "夜间记忆整理：%s",
+	DreamIntro:   "你是 %s。今天（%s）已经结束，请整理今天的经历，沉淀为长期记忆。\n\n",
+	DreamSummary: "## 之前的记忆摘要\n%s\n\n",
+	DreamLessons: "## 已有的经验教训\n",
+	DreamToday:   "## 今天的记录\n",
+	DreamEntry:   "- [%s] 提示: %s",
+	DreamReply:   " | 回复: %s",
+	DreamError:   " | 错误: %s",
+	DreamOutput: `
+## 输出要求
+只输出一个 JSON 对象，不要附加其他文字：
+{"summary": "融合旧摘要与今天经历的新摘要（不超过 %d 字，保留进行中的讨论、承诺、关键人物与帖子 ID）",
+ "experiences": [{"summary": "今天一件重要的事", "lesson": "从中得到的经验"}],
+ "topics": ["当前关注的主题"]}
+experiences 最多 %d 条，只记录真正值得长期记住的内容。`,
+	SummaryLessons: "\n\n经验教训:",
+
+	PlanAction:  "制定计划：%s",
+	PlanIntro:   "你是 %s，研究方向是 %s。请为接下来在研究社区中的几个回合制定计划，围绕一个目标形成连贯的研究进程。\n\n",
+	PlanSummary: "## 你的记忆\n%s\n\n",
+	PlanAgenda:  "## 你的研究议程\n%s\n\n",
+	PlanPending: "你有 %d 条未读通知（回复、提及、评审等）。\n\n",
+	PlanActions: "## 每个回合可选的行动\n%s\n\n",
+	PlanOutput: `## 输出要求
+只输出一个 JSON 对象，不要附加其他文字：
+{"goal": "这个计划要达成的目标", "steps": [{"action": "上面列出的某个行动", "note": "这一回合具体要做什么"}]}
+最多 %d 步，按执行顺序排列。`,
+	PlanGoal: "推进 %s 方面的研究",
+	PlanStep: "你当前的计划：%s（第 %d/%d 步）。",
+
+	InterviewIntro:    "\n\n## 访谈\n模拟已暂停。一位研究这个社区的研究者正在模拟之外与你交谈：你没有任何工具可用，你说的话不会被发布，这次对话也不会进入你的记忆。请以你自己的身份，根据你的经历和信念坦诚作答；不知道或不确定时直接说明。\n",
+	InterviewBeliefs:  "\n### 你的信念\n",
+	InterviewBelief:   "- %s：%s\n",
+	InterviewPeers:    "\n### 你认识的人\n",
+	InterviewPeer:     "- %s（%s）：信任度 %.2f，互动 %d 次\n",
+	InterviewTheories: "\n### 你了解的理论\n",
+	InterviewTheory:   "- %s（%s，置信度 %.2f）\n",
+
+	ThreadSummaryIntro:    "请为没有读过这个论坛线程的科学家写一份摘要。\n\n# %s\n作者 %s，板块 r/%s\n\n",
+	ThreadSummaryPrevious: "## 之前的摘要\n%s\n\n",
+	ThreadSummaryPost:     "## 帖子正文\n%s\n\n",
+	ThreadSummaryComments: "## 回复（按时间先后）\n",
+	ThreadSummaryComment:  "- %s: %s\n",
+	ThreadSummaryOutput:   "\n## 输出要求\n只输出摘要本身，不超过 %d 字：讨论的问题或论断、主要观点及其持有者、引用的证据（帖子、论文与实验 ID）、已达成的共识与尚未解决的问题。",
+
+	DigestSubreddit: "社区元讨论：论坛每周摘要",
+	DigestTitle:     "每周摘要 %s",
+	DigestIntro:     "%s 各板块按得分与回复数排名的热门讨论。可用 read_post 打开并参与讨论。\n\n",
+	DigestSection:   "## r/%s\n\n",
+	DigestThread:    "- **%s**（%s）作者 %s：得分 %d，%d 条回复\n",
+	DigestSummary:   "  > %s\n",
+
+	VisitorName:   "访客",
+	VisitorFooter: "\n\n---\n*这是旁观社区的人类访客 %s 提出的问题（获得其他旁观者 %d 票），欢迎回复讨论。*",
+
+	ExternalAuthor: "外部文献",
+	SeedPosts: []SeedPost{
+		{
+			Title:     "关于自由落体的思考",
+			Content:   "如果在真空中，羽毛和铁球会以相同速度下落吗？",
+			Abstract:  "对亚里士多德物理学的质疑",
+			Subreddit: types.SubPhysics,
+		},
+		{
+			Title:     "几何学第一原理",
+			Content:   "过两点有且仅有一条直线。这是不证自明的公理。",
+			Abstract:  "欧几里得几何的基础",
+			Subreddit: types.SubMathematics,
+		},
+		{
+			Title:     "理论可证伪性的重要性",
+			Content:   "任何科学理论都必须允许被实验推翻，否则只是形而上学。",
+			Abstract:  "科学方法的核心要求",
+			Subreddit: types.SubPhilosophy,
+		},
+	},
+}
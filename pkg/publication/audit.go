@@ -0,0 +1,48 @@
+package publication
+
+import "github.com/cpunion/sci-bot/pkg/audit"
+
+// SetAuditLog records the forum's mutating operations to l (nil disables).
+func (f *Forum) SetAuditLog(l *audit.Log) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.audit = l
+}
+
+// SetAuditLog records the journal's mutating operations to l (nil disables).
+func (j *Journal) SetAuditLog(l *audit.Log) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.audit = l
+}
+
+// SetAuditLog records the workflow's mutating operations to l (nil disables).
+func (w *Workflow) SetAuditLog(l *audit.Log) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.audit = l
+}
+
+// recordChange appends an audit entry for target; before is the target's
+// hash taken ahead of the change ("" for creations). Callers hold the
+// store's lock so the hashes describe a consistent object.
+func recordChange(l *audit.Log, store, op, actor, target, before string, after any) {
+	if l == nil {
+		return
+	}
+	l.Record(audit.Entry{
+		Actor:  actor,
+		Store:  store,
+		Op:     op,
+		Target: target,
+		Before: before,
+		After:  audit.Hash(after),
+	})
+}
+
+func firstAuthor(authors []string) string {
+	if len(authors) == 0 {
+		return ""
+	}
+	return authors[0]
+}
@@ -0,0 +1,55 @@
+package publication
+
+import "github.com/cpunion/sci-bot/pkg/types"
+
+// AnonymousAuthor replaces the author name of a blinded submission.
+const AnonymousAuthor = "Anonymous"
+
+// DoubleBlind reports whether the journal reviews submissions double-blind.
+// Empty selects the default journal.
+func (j *Journal) DoubleBlind(journalID string) bool {
+	info, ok := j.JournalInfo(journalID)
+	return ok && info.DoubleBlind
+}
+
+// BlindPending returns a copy of pub without its author when it awaits a
+// decision at a double-blind journal, and pub itself otherwise.
+func (j *Journal) BlindPending(pub *types.Publication) *types.Publication {
+	if pub == nil || !j.isPending(pub.ID) || !j.DoubleBlind(j.JournalOf(pub)) {
+		return pub
+	}
+	blind := *pub
+	blind.AuthorID = ""
+	blind.AuthorName = AnonymousAuthor
+	blind.PreprintID = ""
+	return &blind
+}
+
+// BlindSubmission returns a copy of sub without its author when it is still
+// undecided at a double-blind journal, and sub itself otherwise. Reviewers
+// only ever see blinded submissions; the author is revealed with the
+// decision.
+func (j *Journal) BlindSubmission(sub *types.Submission) *types.Submission {
+	if sub == nil || sub.Status != types.SubmissionPending || !sub.DecidedAt.IsZero() || !j.DoubleBlind(sub.JournalID) {
+		return sub
+	}
+	blind := *sub
+	blind.AuthorID = ""
+	blind.AuthorName = AnonymousAuthor
+	blind.PreprintID = ""
+	blind.History = make([]types.StatusTransition, len(sub.History))
+	for i, t := range sub.History {
+		if t.Actor == sub.AuthorID {
+			t.Actor = ""
+		}
+		blind.History[i] = t
+	}
+	return &blind
+}
+
+func (j *Journal) isPending(pubID string) bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	_, ok := j.Pending[pubID]
+	return ok
+}
@@ -0,0 +1,235 @@
+package publication
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cpunion/sci-bot/pkg/idgen"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+const (
+	// CivilityReporterID files reports for comments the civility filter flags.
+	CivilityReporterID = "civility-filter"
+	civilityMethod     = "lexicon-v1"
+
+	// A comment is flagged to the moderation queue when its score is at or
+	// below civilityFlagScore and it contains at least civilityFlagHits
+	// hostile terms.
+	civilityFlagScore = -0.5
+	civilityFlagHits  = 2
+)
+
+// hostileTerms are weighted insults and dismissals. ASCII entries match whole
+// words (or phrases); others match as substrings.
+var hostileTerms = map[string]float64{
+	"idiot": 2, "idiots": 2, "moron": 2, "stupid": 2, "dumb": 1.5, "fool": 1.5,
+	"liar": 2, "fraud": 1.5, "crackpot": 2, "clueless": 1.5, "incompetent": 1.5,
+	"pathetic": 1.5, "garbage": 1.5, "trash": 1.5, "worthless": 1.5, "laughable": 1,
+	"ridiculous": 1, "nonsense": 1, "ignorant": 1.5, "shut up": 2, "waste of time": 1,
+	"白痴": 2, "智障": 2, "脑残": 2, "愚蠢": 2, "蠢货": 2, "骗子": 2, "闭嘴": 2,
+	"狗屁": 2, "垃圾": 1.5, "胡说": 1.5, "胡扯": 1.5, "无知": 1.5, "可笑": 1,
+	"荒谬": 1, "废话": 1, "不学无术": 1.5,
+}
+
+var civilTerms = []string{
+	"thank", "thanks", "appreciate", "good point", "agree", "interesting", "please",
+	"perhaps", "i wonder", "fair point", "great question",
+	"谢谢", "感谢", "同意", "有道理", "赞同", "受教", "好问题", "也许", "或许", "不妨",
+}
+
+// ScoreCivility rates a comment with a small lexicon classifier: hostile
+// terms and shouting pull the score down, civil markers pull it up.
+func ScoreCivility(text string) types.CivilityScore {
+	lower := strings.ToLower(text)
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		words[w] = true
+	}
+	matches := func(term string) bool {
+		if isASCIIWord(term) {
+			return words[term]
+		}
+		return strings.Contains(lower, term)
+	}
+
+	hostile := 0.0
+	found := make([]string, 0)
+	for term, weight := range hostileTerms {
+		if matches(term) {
+			hostile += weight
+			found = append(found, term)
+		}
+	}
+	if shouting(text) {
+		hostile++
+		found = append(found, "<shouting>")
+	}
+	sort.Strings(found)
+
+	civil := 0
+	for _, term := range civilTerms {
+		if matches(term) {
+			civil++
+		}
+	}
+
+	score := (float64(civil) - 1.5*hostile) / (1 + float64(civil) + hostile)
+	score = math.Max(-1, math.Min(1, score))
+	return types.CivilityScore{
+		Score:   math.Round(score*1000) / 1000,
+		Hostile: found,
+		Civil:   civil,
+		Method:  civilityMethod,
+	}
+}
+
+func isASCIIWord(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && r != ' ') {
+			return false
+		}
+	}
+	return !strings.Contains(s, " ")
+}
+
+// shouting reports runs of exclamation marks or mostly upper-case Latin text.
+func shouting(text string) bool {
+	if strings.Contains(text, "!!!") || strings.Contains(text, "！！！") {
+		return true
+	}
+	upper, letters := 0, 0
+	for _, r := range text {
+		if r <= unicode.MaxASCII && unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 20 && float64(upper)/float64(letters) > 0.7
+}
+
+// scoreCommentLocked stores the comment's civility score and, for extreme
+// cases, files a report from the civility filter.
+func (f *Forum) scoreCommentLocked(comment *types.Publication, flag bool) {
+	score := ScoreCivility(comment.Content)
+	comment.Civility = &score
+	if !flag || score.Score > civilityFlagScore || len(score.Hostile) < civilityFlagHits {
+		return
+	}
+	if f.Reports == nil {
+		f.Reports = make(map[string]*types.PostReport)
+	}
+	key := CivilityReporterID + ":" + comment.ID
+	if _, exists := f.Reports[key]; exists {
+		return
+	}
+	f.Reports[key] = &types.PostReport{
+		ID:           idgen.New("report"),
+		PostID:       comment.ID,
+		ReporterID:   CivilityReporterID,
+		ReporterName: "Civility filter",
+		Category:     types.ReportHostile,
+		Reason:       fmt.Sprintf("civility %.2f; hostile terms: %s", score.Score, strings.Join(score.Hostile, ", ")),
+		CreatedAt:    time.Now(),
+	}
+}
+
+// CivilityStats aggregates civility scores of a group of comments.
+type CivilityStats struct {
+	Name     string             `json:"name,omitempty"`
+	Comments int                `json:"comments"`
+	Hostile  int                `json:"hostile"` // comments scoring below zero
+	Flagged  int                `json:"flagged"`
+	Mean     float64            `json:"mean"`
+	Daily    map[string]float64 `json:"daily,omitempty"` // date -> mean score
+	sum      float64
+	daySum   map[string]float64
+	dayCount map[string]int
+}
+
+func (c *CivilityStats) add(pub *types.Publication, flagged bool) {
+	score := pub.Civility.Score
+	c.Comments++
+	c.sum += score
+	if score < 0 {
+		c.Hostile++
+	}
+	if flagged {
+		c.Flagged++
+	}
+	if c.daySum == nil {
+		c.daySum = make(map[string]float64)
+		c.dayCount = make(map[string]int)
+	}
+	day := pub.PublishedAt.Format("2006-01-02")
+	c.daySum[day] += score
+	c.dayCount[day]++
+}
+
+func (c *CivilityStats) finish() {
+	if c.Comments == 0 {
+		return
+	}
+	c.Mean = math.Round(c.sum/float64(c.Comments)*1000) / 1000
+	c.Daily = make(map[string]float64, len(c.daySum))
+	for day, sum := range c.daySum {
+		c.Daily[day] = math.Round(sum/float64(c.dayCount[day])*1000) / 1000
+	}
+}
+
+// CivilityReport summarizes comment civility overall, per author and per
+// subreddit, with daily means for trends.
+type CivilityReport struct {
+	Overall     CivilityStats             `json:"overall"`
+	ByAgent     map[string]*CivilityStats `json:"by_agent"`     // key: author ID
+	BySubreddit map[string]*CivilityStats `json:"by_subreddit"` // key: subreddit
+}
+
+// CivilityReport aggregates the stored civility scores of all comments.
+func (f *Forum) CivilityReport() *CivilityReport {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	flagged := make(map[string]bool)
+	for _, r := range f.Reports {
+		if r.ReporterID == CivilityReporterID {
+			flagged[r.PostID] = true
+		}
+	}
+	report := &CivilityReport{
+		ByAgent:     make(map[string]*CivilityStats),
+		BySubreddit: make(map[string]*CivilityStats),
+	}
+	for _, pub := range f.Posts {
+		if !pub.IsComment || pub.Civility == nil {
+			continue
+		}
+		agent, ok := report.ByAgent[pub.AuthorID]
+		if !ok {
+			agent = &CivilityStats{Name: pub.AuthorName}
+			report.ByAgent[pub.AuthorID] = agent
+		}
+		sub, ok := report.BySubreddit[string(pub.Subreddit)]
+		if !ok {
+			sub = &CivilityStats{Name: string(pub.Subreddit)}
+			report.BySubreddit[string(pub.Subreddit)] = sub
+		}
+		for _, c := range []*CivilityStats{&report.Overall, agent, sub} {
+			c.add(pub, flagged[pub.ID])
+		}
+	}
+	report.Overall.finish()
+	for _, c := range report.ByAgent {
+		c.finish()
+	}
+	for _, c := range report.BySubreddit {
+		c.finish()
+	}
+	return report
+}
@@ -0,0 +1,120 @@
+package publication
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ConsensusThresholds decide when an open consensus request is achieved.
+// Zero fields take the defaults.
+type ConsensusThresholds struct {
+	// MinSupporters counts the requester too (default 3).
+	MinSupporters int
+	// MinShare is the supporters' share of all votes, requester included
+	// (default 2/3).
+	MinShare float64
+}
+
+func (t ConsensusThresholds) withDefaults() ConsensusThresholds {
+	if t.MinSupporters <= 0 {
+		t.MinSupporters = 3
+	}
+	if t.MinShare <= 0 {
+		t.MinShare = 2.0 / 3
+	}
+	return t
+}
+
+// Met reports whether req has enough support to be achieved.
+func (t ConsensusThresholds) Met(req *types.ConsensusRequest) bool {
+	t = t.withDefaults()
+	support := len(req.Supporters)
+	total := support + len(req.Opponents)
+	return support >= t.MinSupporters && float64(support) >= t.MinShare*float64(total)
+}
+
+// SetConsensusThresholds sets when consensus requests are achieved.
+func (w *Workflow) SetConsensusThresholds(t ConsensusThresholds) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.consensusRule = t
+}
+
+// ConsensusThresholds returns the thresholds in effect, defaults filled in.
+func (w *Workflow) ConsensusThresholds() ConsensusThresholds {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.consensusRule.withDefaults()
+}
+
+// OnConsensusAchieved registers a listener invoked when a consensus request
+// reaches its thresholds. Listeners run outside the workflow lock.
+func (w *Workflow) OnConsensusAchieved(fn func(*types.ConsensusRequest)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.consensusListeners = append(w.consensusListeners, fn)
+}
+
+// VoteConsensus records agentID supporting or opposing a consensus request;
+// voting again switches sides. The requester already supports its request
+// and cannot vote. An open request that meets the thresholds becomes
+// achieved, which is final, and the listeners are told.
+func (w *Workflow) VoteConsensus(id, agentID string, support bool) (*types.ConsensusRequest, error) {
+	req, achieved, listeners, err := w.voteConsensus(id, agentID, support)
+	if err != nil {
+		return nil, err
+	}
+	if achieved {
+		for _, fn := range listeners {
+			fn(req)
+		}
+	}
+	return req, nil
+}
+
+func (w *Workflow) voteConsensus(id, agentID string, support bool) (*types.ConsensusRequest, bool, []func(*types.ConsensusRequest), error) {
+	if agentID == "" {
+		return nil, false, nil, fmt.Errorf("missing voter")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	req, ok := w.Consensus[id]
+	if !ok {
+		return nil, false, nil, fmt.Errorf("consensus request not found: %s", id)
+	}
+	if req.Status == types.ConsensusClosed {
+		return nil, false, nil, fmt.Errorf("consensus request is closed: %s", id)
+	}
+	if agentID == req.RequesterID {
+		return nil, false, nil, fmt.Errorf("cannot vote on your own consensus request")
+	}
+
+	before := w.audit.Hash(req)
+	req.Supporters = slices.DeleteFunc(req.Supporters, func(s string) bool { return s == agentID })
+	req.Opponents = slices.DeleteFunc(req.Opponents, func(s string) bool { return s == agentID })
+	op := "oppose_consensus"
+	if support {
+		req.Supporters = append(req.Supporters, agentID)
+		op = "support_consensus"
+	} else {
+		req.Opponents = append(req.Opponents, agentID)
+	}
+	req.UpdatedAt = time.Now()
+
+	achieved := req.Status != types.ConsensusAchieved && w.consensusRule.Met(req)
+	if achieved {
+		// Open requests can always be achieved; closed ones were refused above.
+		if err := transitionConsensus(req, types.ConsensusAchieved, agentID, req.UpdatedAt); err != nil {
+			return nil, false, nil, err
+		}
+		req.AchievedAt = req.UpdatedAt
+	}
+	recordChange(w.audit, audit.StoreWorkflow, op, agentID, id, before, req)
+	return req, achieved, append([]func(*types.ConsensusRequest){}, w.consensusListeners...), nil
+}
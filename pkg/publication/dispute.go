@@ -0,0 +1,92 @@
+package publication
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/idgen"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// AddDispute records a formal challenge and returns how many agents now
+// dispute the target. Each agent may dispute a target once.
+func (w *Workflow) AddDispute(d *types.Dispute) (int, error) {
+	if d.TargetID == "" || d.ChallengerID == "" {
+		return 0, errors.New("dispute needs a target and a challenger")
+	}
+	if strings.TrimSpace(d.CounterArguments) == "" {
+		return 0, errors.New("counter-arguments are required")
+	}
+	if d.ChallengerID == d.TargetAuthorID {
+		return 0, errors.New("cannot dispute your own work")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.Disputes == nil {
+		w.Disputes = make(map[string]*types.Dispute)
+	}
+	challengers := 1
+	for _, other := range w.Disputes {
+		if other.TargetID != d.TargetID {
+			continue
+		}
+		if other.ChallengerID == d.ChallengerID {
+			return 0, fmt.Errorf("already disputed %s (%s)", d.TargetID, other.ID)
+		}
+		challengers++
+	}
+	if d.ID == "" {
+		d.ID = idgen.New("dispute")
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	w.Disputes[d.ID] = d
+	recordChange(w.audit, audit.StoreWorkflow, "dispute", d.ChallengerID, d.ID, "", d)
+	return challengers, nil
+}
+
+// DisputesOf returns the disputes of a publication, oldest first.
+func (w *Workflow) DisputesOf(targetID string) []*types.Dispute {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var out []*types.Dispute
+	for _, d := range w.Disputes {
+		if d.TargetID == targetID {
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out
+}
+
+// MarkDisputed flags a published paper as disputed. It reports whether the
+// paper was newly flagged.
+func (j *Journal) MarkDisputed(pubID, actorID string, at time.Time) (bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pub, ok := j.Publications[pubID]
+	if !ok {
+		return false, fmt.Errorf("publication not found: %s", pubID)
+	}
+	if pub.Disputed {
+		return false, nil
+	}
+	before := j.audit.Hash(pub)
+	pub.Disputed = true
+	pub.DisputedAt = at
+	recordChange(j.audit, audit.StoreJournal, "dispute", actorID, pubID, before, pub)
+	return true, nil
+}
@@ -0,0 +1,183 @@
+package publication
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Draft edit modes.
+const (
+	DraftAppend  = "append"
+	DraftReplace = "replace"
+)
+
+var headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// EditDraft changes an open draft on behalf of one of its authors and
+// records the edit as their contribution. With a section, append adds text
+// to the end of the "## section" part of the Markdown and replace swaps
+// that part's body; a missing section is added at the end. Without one,
+// append adds to the end of the draft and replace rewrites all of it.
+func (w *Workflow) EditDraft(id, authorID, mode, section, text string) (*types.Draft, error) {
+	text = strings.TrimSpace(text)
+	section = strings.TrimSpace(section)
+	if text == "" {
+		return nil, fmt.Errorf("missing text")
+	}
+	if mode != DraftAppend && mode != DraftReplace {
+		return nil, fmt.Errorf("invalid edit mode %q (want append or replace)", mode)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	draft, err := w.editableDraftLocked(id, authorID)
+	if err != nil {
+		return nil, err
+	}
+	before := w.audit.Hash(draft)
+	draft.Content = editSection(draft.Content, mode, section, text)
+	draft.UpdatedAt = time.Now()
+	draft.Contributions = append(draft.Contributions, types.DraftContribution{
+		AuthorID: authorID,
+		Mode:     mode,
+		Section:  section,
+		Chars:    utf8.RuneCountInString(text),
+		At:       draft.UpdatedAt,
+	})
+	recordChange(w.audit, audit.StoreWorkflow, "edit_draft", authorID, id, before, draft)
+	return draft, nil
+}
+
+// InviteCoauthor adds inviteeID to the authors of an open draft. Only an
+// author may invite.
+func (w *Workflow) InviteCoauthor(id, inviterID, inviteeID string) error {
+	if inviteeID == "" {
+		return fmt.Errorf("missing coauthor")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	draft, err := w.editableDraftLocked(id, inviterID)
+	if err != nil {
+		return err
+	}
+	for _, a := range draft.Authors {
+		if a == inviteeID {
+			return fmt.Errorf("%s is already an author of %s", inviteeID, id)
+		}
+	}
+	before := w.audit.Hash(draft)
+	draft.Authors = append(draft.Authors, inviteeID)
+	draft.UpdatedAt = time.Now()
+	recordChange(w.audit, audit.StoreWorkflow, "invite_coauthor", inviterID, id, before, draft)
+	return nil
+}
+
+// DraftsByAuthor returns the drafts authorID is an author of, most recently
+// updated first.
+func (w *Workflow) DraftsByAuthor(authorID string) []*types.Draft {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]*types.Draft, 0)
+	for _, d := range w.Drafts {
+		for _, a := range d.Authors {
+			if a == authorID {
+				out = append(out, d)
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.After(out[j].UpdatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func (w *Workflow) editableDraftLocked(id, authorID string) (*types.Draft, error) {
+	draft, ok := w.Drafts[id]
+	if !ok {
+		return nil, fmt.Errorf("draft not found: %s", id)
+	}
+	if draft.Status == types.DraftLocked {
+		return nil, fmt.Errorf("draft is locked: %s", id)
+	}
+	for _, a := range draft.Authors {
+		if a == authorID {
+			return draft, nil
+		}
+	}
+	return nil, fmt.Errorf("only authors can edit draft %s", id)
+}
+
+// editSection applies an append or replace to Markdown content; see
+// EditDraft.
+func editSection(content, mode, section, text string) string {
+	content = strings.TrimRight(content, "\n")
+	if section == "" {
+		if mode == DraftReplace || content == "" {
+			return text
+		}
+		return content + "\n\n" + text
+	}
+
+	lines := strings.Split(content, "\n")
+	start, level := -1, 0
+	for i, line := range lines {
+		m := headingPattern.FindStringSubmatch(line)
+		if m != nil && strings.EqualFold(m[2], section) {
+			start, level = i, len(m[1])
+			break
+		}
+	}
+	if start < 0 {
+		if content == "" {
+			return "## " + section + "\n\n" + text
+		}
+		return content + "\n\n## " + section + "\n\n" + text
+	}
+	// The section runs to the next heading of the same or a higher level.
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if m := headingPattern.FindStringSubmatch(lines[i]); m != nil && len(m[1]) <= level {
+			end = i
+			break
+		}
+	}
+	body := strings.Trim(strings.Join(lines[start+1:end], "\n"), "\n")
+	if mode == DraftAppend && body != "" {
+		body += "\n\n" + text
+	} else {
+		body = text
+	}
+	out := append([]string{}, lines[:start+1]...)
+	out = append(out, "", body)
+	if end < len(lines) {
+		out = append(out, "")
+		out = append(out, lines[end:]...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// DraftSections returns the Markdown headings of draft content, in order.
+func DraftSections(content string) []string {
+	var out []string
+	for _, line := range strings.Split(content, "\n") {
+		if m := headingPattern.FindStringSubmatch(line); m != nil && m[2] != "" {
+			out = append(out, m[2])
+		}
+	}
+	return out
+}
@@ -0,0 +1,198 @@
+package publication
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cpunion/sci-bot/pkg/idgen"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+const (
+	// DuplicateReporterID files reports for posts the duplicate filter flags.
+	DuplicateReporterID = "duplicate-filter"
+
+	// DuplicateThreshold is the estimated Jaccard similarity of two posts'
+	// shingles at or above which the newer post is flagged as a duplicate.
+	DuplicateThreshold = 0.6
+
+	shingleSize = 5  // runes per shingle
+	minShingles = 8  // shorter posts are never flagged
+	minHashSize = 64 // MinHash signature length
+)
+
+// signature is a MinHash signature of a post's shingles. The share of equal
+// slots in two signatures estimates the Jaccard similarity of their shingles.
+type signature [minHashSize]uint64
+
+// postSignature shingles a post's title and body. It reports false for posts
+// too short to compare.
+func postSignature(pub *types.Publication) (signature, bool) {
+	var sig signature
+	shingles := shingleHashes(pub.Title + "\n" + pub.Content)
+	if len(shingles) < minShingles {
+		return sig, false
+	}
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	for _, h := range shingles {
+		for i := range sig {
+			if v := mix64(h ^ minHashSeeds[i]); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig, true
+}
+
+// shingleHashes hashes each window of shingleSize runes of the normalized
+// text. Runes work for both English and Chinese, unlike word shingles.
+func shingleHashes(text string) []uint64 {
+	var runes []rune
+	space := true
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+			space = false
+		} else if !space {
+			runes = append(runes, ' ')
+			space = true
+		}
+	}
+	seen := make(map[uint64]bool)
+	var out []uint64
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(string(runes[i : i+shingleSize])))
+		sum := h.Sum64()
+		if !seen[sum] {
+			seen[sum] = true
+			out = append(out, sum)
+		}
+	}
+	return out
+}
+
+func (a signature) similarity(b signature) float64 {
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / minHashSize
+}
+
+// mix64 is the splitmix64 finalizer.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+var minHashSeeds = func() (seeds [minHashSize]uint64) {
+	for i := range seeds {
+		seeds[i] = mix64(uint64(i+1) * 0x9e3779b97f4a7c15)
+	}
+	return seeds
+}()
+
+// signatureLocked returns a post's cached signature, computing it on first use.
+func (f *Forum) signatureLocked(pub *types.Publication) (signature, bool) {
+	if sig, ok := f.index.signatures[pub.ID]; ok {
+		return sig, true
+	}
+	sig, ok := postSignature(pub)
+	if ok {
+		if f.index.signatures == nil {
+			f.index.signatures = make(map[string]signature)
+		}
+		f.index.signatures[pub.ID] = sig
+	}
+	return sig, ok
+}
+
+// findDuplicateLocked returns the most similar visible top-level post at or
+// above DuplicateThreshold, or nil.
+func (f *Forum) findDuplicateLocked(pub *types.Publication) (*types.Publication, float64) {
+	sig, ok := postSignature(pub)
+	if !ok {
+		return nil, 0
+	}
+	var best *types.Publication
+	bestScore := 0.0
+	for _, other := range f.Posts {
+		if other == nil || other.IsComment || other.ID == pub.ID || !other.Visible() {
+			continue
+		}
+		otherSig, ok := f.signatureLocked(other)
+		if !ok {
+			continue
+		}
+		score := sig.similarity(otherSig)
+		if score < DuplicateThreshold {
+			continue
+		}
+		if best == nil || score > bestScore || (score == bestScore && other.PublishedAt.Before(best.PublishedAt)) {
+			best, bestScore = other, score
+		}
+	}
+	return best, bestScore
+}
+
+// flagDuplicateLocked marks a new top-level post that closely matches an
+// existing thread and files a report for moderators.
+func (f *Forum) flagDuplicateLocked(pub *types.Publication) {
+	pub.DuplicateOf = ""
+	pub.DuplicateScore = 0
+	original, score := f.findDuplicateLocked(pub)
+	if original == nil {
+		return
+	}
+	pub.DuplicateOf = original.ID
+	pub.DuplicateScore = float64(int(score*1000+0.5)) / 1000
+	if f.Reports == nil {
+		f.Reports = make(map[string]*types.PostReport)
+	}
+	key := DuplicateReporterID + ":" + pub.ID
+	if _, exists := f.Reports[key]; exists {
+		return
+	}
+	f.Reports[key] = &types.PostReport{
+		ID:           idgen.New("report"),
+		PostID:       pub.ID,
+		ReporterID:   DuplicateReporterID,
+		ReporterName: "Duplicate filter",
+		Category:     types.ReportDuplicate,
+		Reason:       fmt.Sprintf("similar to %s (%.2f)", original.ID, pub.DuplicateScore),
+		CreatedAt:    time.Now(),
+	}
+}
+
+// GetDuplicates returns top-level posts flagged as likely duplicates, newest
+// first.
+func (f *Forum) GetDuplicates() []*types.Publication {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var out []*types.Publication
+	for _, p := range f.Posts {
+		if p != nil && p.DuplicateOf != "" {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PublishedAt.Equal(out[j].PublishedAt) {
+			return out[i].ID > out[j].ID
+		}
+		return out[i].PublishedAt.After(out[j].PublishedAt)
+	})
+	return out
+}
@@ -0,0 +1,274 @@
+package publication
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ReviewerCandidate is a reviewer suggested for a submission.
+type ReviewerCandidate struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Domains []string `json:"domains,omitempty"`
+	Matched []string `json:"matched_domains,omitempty"`
+	Load    int      `json:"load"` // assigned reviews still owed
+}
+
+// OverdueReview is an assigned review still missing after the nag interval.
+type OverdueReview struct {
+	SubmissionID string    `json:"submission_id"`
+	Title        string    `json:"title"`
+	ReviewerID   string    `json:"reviewer_id"`
+	AssignedAt   time.Time `json:"assigned_at"`
+	LastReminder time.Time `json:"last_reminder,omitempty"`
+}
+
+// EditorQueue returns pending submissions that have no reviewers assigned
+// yet, oldest first.
+func (w *Workflow) EditorQueue() []*types.Submission {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]*types.Submission, 0)
+	for _, sub := range w.Submissions {
+		if sub.Status != types.SubmissionPending || !sub.DecidedAt.IsZero() {
+			continue
+		}
+		if len(sub.AssignedReviewers) > 0 {
+			continue
+		}
+		out = append(out, sub)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out
+}
+
+// SuggestReviewers ranks reviewers for a submission: most domains mentioned
+// in the paper first, then the lightest review load. The author is excluded.
+func (w *Workflow) SuggestReviewers(sub *types.Submission, reviewers []*types.Persona, n int) []ReviewerCandidate {
+	if sub == nil {
+		return nil
+	}
+	text := strings.ToLower(sub.Title + "\n" + sub.Abstract + "\n" + sub.Content)
+
+	out := make([]ReviewerCandidate, 0, len(reviewers))
+	for _, p := range reviewers {
+		if p == nil || p.ID == sub.AuthorID {
+			continue
+		}
+		c := ReviewerCandidate{
+			ID:      p.ID,
+			Name:    p.Name,
+			Domains: p.Domains,
+			Load:    len(w.PendingAssignments(p.ID)),
+		}
+		for _, d := range p.Domains {
+			if d = strings.ToLower(strings.TrimSpace(d)); d != "" && strings.Contains(text, d) {
+				c.Matched = append(c.Matched, d)
+			}
+		}
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if len(out[i].Matched) != len(out[j].Matched) {
+			return len(out[i].Matched) > len(out[j].Matched)
+		}
+		if out[i].Load != out[j].Load {
+			return out[i].Load < out[j].Load
+		}
+		return out[i].ID < out[j].ID
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// AssignReviewers records an editor's reviewer assignment for a pending
+// submission, replacing earlier assignments. Review cycles keep these
+// reviewers instead of assigning their own.
+func (w *Workflow) AssignReviewers(submissionID string, reviewerIDs []string, editorID string, at time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sub, err := w.openSubmissionLocked(submissionID)
+	if err != nil {
+		return err
+	}
+	assigned := make([]string, 0, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || containsID(assigned, id) {
+			continue
+		}
+		if id == sub.AuthorID {
+			return fmt.Errorf("the author cannot review their own submission: %s", id)
+		}
+		assigned = append(assigned, id)
+	}
+	if len(assigned) == 0 {
+		return fmt.Errorf("no reviewers given")
+	}
+	before := w.audit.Hash(sub)
+
+	sub.AssignedReviewers = assigned
+	sub.AssignedBy = editorID
+	sub.AssignedAt = at
+	sub.UpdatedAt = time.Now()
+	recordChange(w.audit, audit.StoreWorkflow, "assign_reviewers", editorID, submissionID, before, sub)
+	return nil
+}
+
+// DeskReject rejects a pending submission without peer review, e.g. when it
+// is out of scope for its journal.
+func (w *Workflow) DeskReject(journal *Journal, submissionID, editorID, reason string, at time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("desk rejection requires a reason")
+	}
+	w.mu.RLock()
+	_, err := w.openSubmissionLocked(submissionID)
+	w.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	rationale := "Desk rejected by the editor without review: " + reason
+	if journal != nil {
+		if err := journal.RejectWithReason(submissionID, editorID, rationale); err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	sub := w.Submissions[submissionID]
+	before := w.audit.Hash(sub)
+	if err := transitionSubmission(sub, types.SubmissionRejected, editorID, at); err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	sub.DeskRejected = true
+	sub.DecidedAt = at
+	sub.DecidedBy = editorID
+	sub.DecisionRationale = rationale
+	sub.UpdatedAt = time.Now()
+	recordChange(w.audit, audit.StoreWorkflow, "desk_reject", editorID, submissionID, before, sub)
+	listeners := append([]func(*types.Submission){}, w.decisionListeners...)
+	w.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(sub)
+	}
+	return nil
+}
+
+// RemindReviewer records an editor reminder to an assigned reviewer who has
+// not reviewed the submission yet.
+func (w *Workflow) RemindReviewer(submissionID, reviewerID, editorID, note string, at time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sub, err := w.openSubmissionLocked(submissionID)
+	if err != nil {
+		return err
+	}
+	if !containsID(sub.AssignedReviewers, reviewerID) {
+		return fmt.Errorf("%s is not assigned to %s", reviewerID, submissionID)
+	}
+	if w.hasReviewLocked(submissionID, reviewerID) {
+		return fmt.Errorf("%s already reviewed %s", reviewerID, submissionID)
+	}
+	before := w.audit.Hash(sub)
+	sub.Reminders = append(sub.Reminders, types.ReviewReminder{
+		ReviewerID: reviewerID,
+		EditorID:   editorID,
+		Note:       strings.TrimSpace(note),
+		At:         at,
+	})
+	sub.UpdatedAt = time.Now()
+	recordChange(w.audit, audit.StoreWorkflow, "remind_reviewer", editorID, submissionID, before, sub)
+	return nil
+}
+
+// OverdueReviews returns assigned reviews still missing after more than
+// after since the assignment or the reviewer's last reminder. after <= 0
+// disables nagging.
+func (w *Workflow) OverdueReviews(now time.Time, after time.Duration) []OverdueReview {
+	if after <= 0 {
+		return nil
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]OverdueReview, 0)
+	for _, sub := range w.Submissions {
+		if sub.Status != types.SubmissionPending || !sub.DecidedAt.IsZero() || sub.AssignedAt.IsZero() {
+			continue
+		}
+		for _, id := range sub.AssignedReviewers {
+			if w.hasReviewLocked(sub.ID, id) {
+				continue
+			}
+			since := sub.AssignedAt
+			last, reminded := LastReminder(sub, id)
+			if reminded && last.At.After(since) {
+				since = last.At
+			}
+			if now.Sub(since) < after {
+				continue
+			}
+			item := OverdueReview{
+				SubmissionID: sub.ID,
+				Title:        sub.Title,
+				ReviewerID:   id,
+				AssignedAt:   sub.AssignedAt,
+			}
+			if reminded {
+				item.LastReminder = last.At
+			}
+			out = append(out, item)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].SubmissionID != out[j].SubmissionID {
+			return out[i].SubmissionID < out[j].SubmissionID
+		}
+		return out[i].ReviewerID < out[j].ReviewerID
+	})
+	return out
+}
+
+// LastReminder returns the most recent reminder to reviewerID about sub.
+func LastReminder(sub *types.Submission, reviewerID string) (types.ReviewReminder, bool) {
+	var last types.ReviewReminder
+	found := false
+	for _, r := range sub.Reminders {
+		if r.ReviewerID == reviewerID && (!found || r.At.After(last.At)) {
+			last, found = r, true
+		}
+	}
+	return last, found
+}
+
+// openSubmissionLocked returns a submission that still awaits a decision.
+// Caller must hold w.mu.
+func (w *Workflow) openSubmissionLocked(submissionID string) (*types.Submission, error) {
+	sub, ok := w.Submissions[submissionID]
+	if !ok {
+		return nil, fmt.Errorf("submission not found: %s", submissionID)
+	}
+	if sub.Status != types.SubmissionPending || !sub.DecidedAt.IsZero() {
+		return nil, fmt.Errorf("submission already decided: %s (%s)", submissionID, sub.Status)
+	}
+	return sub, nil
+}
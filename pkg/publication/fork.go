@@ -0,0 +1,109 @@
+package publication
+
+import (
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// TruncateAfter drops everything created after cutoff: posts and their
+// replies, votes, reports and moderation actions. Vote counts and comment
+// counts are adjusted to match, and summaries of dropped threads are removed.
+// cmd/fork_world uses it to rewind a copied forum; call Save afterwards to
+// persist the result. It returns the number of posts dropped.
+func (f *Forum) TruncateAfter(cutoff time.Time) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dropped := make(map[string]bool)
+	for id, p := range f.Posts {
+		if p != nil && p.PublishedAt.After(cutoff) {
+			dropped[id] = true
+		}
+	}
+	// Replies to dropped posts go too, however old their timestamps.
+	for changed := true; changed; {
+		changed = false
+		for id, p := range f.Posts {
+			if p != nil && !dropped[id] && p.IsComment && dropped[p.ParentID] {
+				dropped[id] = true
+				changed = true
+			}
+		}
+	}
+	for id := range dropped {
+		p := f.Posts[id]
+		delete(f.Posts, id)
+		delete(f.Summaries, id)
+		if p.IsComment {
+			if parent := f.Posts[p.ParentID]; parent != nil && !dropped[parent.ID] && parent.Comments > 0 {
+				parent.Comments--
+			}
+		}
+	}
+
+	for key, v := range f.Votes {
+		if !dropped[v.PostID] && !v.VotedAt.After(cutoff) {
+			continue
+		}
+		delete(f.Votes, key)
+		if post := f.Posts[v.PostID]; post != nil {
+			if v.IsUpvote {
+				post.Upvotes--
+			} else {
+				post.Downvotes--
+			}
+			post.Score = post.Upvotes - post.Downvotes
+		}
+	}
+
+	for id, r := range f.Reports {
+		if dropped[r.PostID] || r.CreatedAt.After(cutoff) {
+			delete(f.Reports, id)
+		}
+	}
+	kept := f.ModerationLog[:0]
+	for _, a := range f.ModerationLog {
+		if !dropped[a.PostID] && !a.At.After(cutoff) {
+			kept = append(kept, a)
+		}
+	}
+	f.ModerationLog = kept
+
+	f.rebuildIndexLocked()
+	return len(dropped)
+}
+
+// TruncateAfter drops papers submitted or accepted after cutoff (acceptance
+// overwrites the submission time). Papers rejected after cutoff but submitted
+// before it return to the pending queue. It returns the number of papers
+// dropped.
+func (j *Journal) TruncateAfter(cutoff time.Time) int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	dropped := 0
+	drop := func(m map[string]*types.Publication) {
+		for id, p := range m {
+			if p.PublishedAt.After(cutoff) {
+				delete(m, id)
+				dropped++
+			}
+		}
+	}
+	drop(j.Publications)
+	drop(j.Pending)
+	drop(j.Rejected)
+	if j.Pending == nil {
+		j.Pending = make(map[string]*types.Publication)
+	}
+	for id, p := range j.Rejected {
+		if p.RejectedAt.After(cutoff) {
+			delete(j.Rejected, id)
+			p.RejectedAt = time.Time{}
+			p.DecisionRationale = ""
+			j.Pending[id] = p
+		}
+	}
+	return dropped
+}
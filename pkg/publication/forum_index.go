@@ -0,0 +1,256 @@
+package publication
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// forumIndex holds secondary lookups over Forum.Posts. It is not persisted:
+// Load rebuilds it and Post/Comment keep it current. Guarded by Forum.mu.
+type forumIndex struct {
+	byAuthor map[string][]string // authorID -> post/comment IDs, oldest first
+	children map[string][]string // parentID -> direct reply IDs, oldest first
+	rootOf   map[string]string   // commentID -> root post ID ("" if the chain is broken)
+	mentions map[string][]string // lowercase @ token -> IDs of publications mentioning it, oldest first
+	replies  map[string][]string // authorID -> IDs of comments replying to their posts/comments, oldest first
+	// signatures caches duplicate-detection signatures of top-level posts,
+	// filled on first comparison.
+	signatures map[string]signature
+}
+
+func newForumIndex() forumIndex {
+	return forumIndex{
+		byAuthor: make(map[string][]string),
+		children: make(map[string][]string),
+		rootOf:   make(map[string]string),
+		mentions: make(map[string][]string),
+		replies:  make(map[string][]string),
+
+		signatures: make(map[string]signature),
+	}
+}
+
+// rebuildIndexLocked recomputes the index from Posts. Posts is a map, so
+// each list is sorted by publish time afterwards; Post and Comment then
+// append in publish order.
+func (f *Forum) rebuildIndexLocked() {
+	f.index = newForumIndex()
+	for _, p := range f.Posts {
+		if p == nil {
+			continue
+		}
+		f.index.byAuthor[p.AuthorID] = append(f.index.byAuthor[p.AuthorID], p.ID)
+		f.indexMentionsLocked(p)
+		if p.IsComment {
+			f.index.children[p.ParentID] = append(f.index.children[p.ParentID], p.ID)
+		}
+	}
+	// Parents may be visited after their replies above, so roots and reply
+	// targets are resolved in a second pass.
+	for _, p := range f.Posts {
+		if p != nil && p.IsComment {
+			f.index.rootOf[p.ID] = f.walkRootLocked(p)
+			f.indexReplyLocked(p)
+		}
+	}
+	for _, lists := range []map[string][]string{f.index.byAuthor, f.index.children, f.index.mentions, f.index.replies} {
+		for _, ids := range lists {
+			f.sortByPublishedLocked(ids)
+		}
+	}
+}
+
+// sortByPublishedLocked orders ids oldest first, ties by ID.
+func (f *Forum) sortByPublishedLocked(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := f.Posts[ids[i]], f.Posts[ids[j]]
+		if !a.PublishedAt.Equal(b.PublishedAt) {
+			return a.PublishedAt.Before(b.PublishedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// indexLocked adds a newly stored publication. Replacing an existing ID
+// falls back to a full rebuild so stale entries don't linger.
+func (f *Forum) indexLocked(pub *types.Publication, replaced bool) {
+	if replaced || f.index.byAuthor == nil {
+		f.rebuildIndexLocked()
+		return
+	}
+	f.index.byAuthor[pub.AuthorID] = append(f.index.byAuthor[pub.AuthorID], pub.ID)
+	f.indexMentionsLocked(pub)
+	if !pub.IsComment {
+		return
+	}
+	f.index.children[pub.ParentID] = append(f.index.children[pub.ParentID], pub.ID)
+	f.indexReplyLocked(pub)
+	if parent, ok := f.Posts[pub.ParentID]; ok && !parent.IsComment {
+		f.index.rootOf[pub.ID] = parent.ID
+	} else {
+		f.index.rootOf[pub.ID] = f.index.rootOf[pub.ParentID]
+	}
+}
+
+// indexMentionsLocked records the @ tokens of pub: its Mentions, or those
+// in its text for publications stored without them.
+func (f *Forum) indexMentionsLocked(pub *types.Publication) {
+	tokens := pub.Mentions
+	if len(tokens) == 0 {
+		tokens = ExtractMentions(pub.Title + "\n" + pub.Abstract + "\n" + pub.Content)
+	}
+	seen := make(map[string]struct{}, len(tokens))
+	for _, t := range tokens {
+		t = strings.ToLower(t)
+		if _, ok := seen[t]; ok || t == "" {
+			continue
+		}
+		seen[t] = struct{}{}
+		f.index.mentions[t] = append(f.index.mentions[t], pub.ID)
+	}
+}
+
+// indexReplyLocked records comment as a reply to its parent's author.
+func (f *Forum) indexReplyLocked(comment *types.Publication) {
+	if parent, ok := f.Posts[comment.ParentID]; ok && parent != nil && parent.AuthorID != "" {
+		f.index.replies[parent.AuthorID] = append(f.index.replies[parent.AuthorID], comment.ID)
+	}
+}
+
+// walkRootLocked follows ParentID links up to the top-level post.
+func (f *Forum) walkRootLocked(pub *types.Publication) string {
+	seen := map[string]struct{}{pub.ID: {}}
+	parentID := pub.ParentID
+	for parentID != "" {
+		if _, ok := seen[parentID]; ok {
+			break
+		}
+		seen[parentID] = struct{}{}
+		parent, ok := f.Posts[parentID]
+		if !ok || parent == nil {
+			break
+		}
+		if !parent.IsComment {
+			return parent.ID
+		}
+		parentID = parent.ParentID
+	}
+	return ""
+}
+
+// lookupLocked resolves indexed IDs to publications.
+func (f *Forum) lookupLocked(ids []string) []*types.Publication {
+	out := make([]*types.Publication, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := f.Posts[id]; ok {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// CommentSort orders sibling comments within a thread.
+type CommentSort string
+
+const (
+	SortTop CommentSort = "top" // highest score first, ties oldest first
+	SortNew CommentSort = "new"
+	SortOld CommentSort = "old"
+)
+
+// ParseCommentSort reads a sort option; empty means SortTop.
+func ParseCommentSort(s string) (CommentSort, error) {
+	switch order := CommentSort(strings.ToLower(strings.TrimSpace(s))); order {
+	case "":
+		return SortTop, nil
+	case SortTop, SortNew, SortOld:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid comment sort %q (want top, new or old)", s)
+	}
+}
+
+// CollapseScore is the score at or below which a comment starts collapsed.
+const CollapseScore = -3
+
+// ThreadComment is a comment placed in its thread's tree.
+type ThreadComment struct {
+	*types.Publication
+	// Depth is 1 for direct replies to the post.
+	Depth int `json:"depth"`
+	// Replies and Descendants count visible direct and nested replies, so a
+	// UI can label a collapsed subtree.
+	Replies     int  `json:"replies"`
+	Descendants int  `json:"descendants"`
+	Collapsed   bool `json:"collapsed,omitempty"`
+}
+
+// CommentTree returns the comments under rootID depth-first, each followed
+// by its replies, with siblings ordered by order. Moderated comments are
+// left out; their replies are kept at their own depth.
+func (f *Forum) CommentTree(rootID string, order CommentSort) []ThreadComment {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if _, ok := f.Posts[rootID]; !ok {
+		return nil
+	}
+	out := make([]ThreadComment, 0)
+	at := map[string]int{} // comment ID -> index in out
+	seen := map[string]struct{}{rootID: {}}
+	var walk func(parentID string, depth int) int
+	walk = func(parentID string, depth int) int {
+		kids := f.lookupLocked(f.index.children[parentID])
+		sortComments(kids, order)
+		total := 0
+		for _, c := range kids {
+			if _, ok := seen[c.ID]; ok || !c.IsComment {
+				continue
+			}
+			seen[c.ID] = struct{}{}
+			if !c.Visible() {
+				total += walk(c.ID, depth+1)
+				continue
+			}
+			i := len(out)
+			at[c.ID] = i
+			out = append(out, ThreadComment{Publication: c, Depth: depth, Collapsed: c.Score <= CollapseScore})
+			if p, ok := at[c.ParentID]; ok {
+				out[p].Replies++
+			}
+			n := walk(c.ID, depth+1)
+			out[i].Descendants = n
+			total += 1 + n
+		}
+		return total
+	}
+	walk(rootID, 1)
+	return out
+}
+
+func sortComments(comments []*types.Publication, order CommentSort) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		a, b := comments[i], comments[j]
+		switch order {
+		case SortNew:
+			if !a.PublishedAt.Equal(b.PublishedAt) {
+				return a.PublishedAt.After(b.PublishedAt)
+			}
+		case SortOld:
+			if !a.PublishedAt.Equal(b.PublishedAt) {
+				return a.PublishedAt.Before(b.PublishedAt)
+			}
+		default:
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+			if !a.PublishedAt.Equal(b.PublishedAt) {
+				return a.PublishedAt.Before(b.PublishedAt)
+			}
+		}
+		return a.ID < b.ID
+	})
+}
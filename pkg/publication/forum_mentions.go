@@ -0,0 +1,96 @@
+package publication
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+var mentionPattern = regexp.MustCompile(`(?i)@([a-z0-9][a-z0-9_./-]{0,63})`)
+
+// ExtractMentions returns the distinct @ tokens in text, lowercased, in
+// order of first appearance.
+func ExtractMentions(text string) []string {
+	if text == "" {
+		return nil
+	}
+	matches := mentionPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(matches))
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		token := strings.ToLower(strings.TrimSpace(m[1]))
+		if _, ok := seen[token]; ok || token == "" {
+			continue
+		}
+		seen[token] = struct{}{}
+		out = append(out, token)
+	}
+	return out
+}
+
+// Reasons a publication shows up in an agent's mentions.
+const (
+	MentionReasonMention = "mention" // it @-mentions the agent
+	MentionReasonReply   = "reply"   // it replies to one of the agent's posts or comments
+)
+
+// Mention is a publication addressed to an agent.
+type Mention struct {
+	Pub    *types.Publication
+	Reason string
+	// RootID is the thread's top-level post ("" if the chain is broken).
+	RootID string
+}
+
+// GetMentions returns the posts and comments that mention agentID, or
+// aliases such as its display name, and the comments replying to it,
+// published after since (zero for all), newest first. A publication that
+// both mentions and replies is reported once, as a mention. It reads the
+// forum's index, so it costs the size of the answer rather than the forum.
+func (f *Forum) GetMentions(agentID string, since time.Time, aliases ...string) []Mention {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	reasons := make(map[string]string)
+	for _, key := range append([]string{agentID}, aliases...) {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		for _, id := range f.index.mentions[key] {
+			reasons[id] = MentionReasonMention
+		}
+	}
+	for _, id := range f.index.replies[agentID] {
+		if _, ok := reasons[id]; !ok {
+			reasons[id] = MentionReasonReply
+		}
+	}
+
+	out := make([]Mention, 0, len(reasons))
+	for id, reason := range reasons {
+		pub, ok := f.Posts[id]
+		if !ok || pub == nil || (!since.IsZero() && !pub.PublishedAt.After(since)) {
+			continue
+		}
+		root := pub.ID
+		if pub.IsComment {
+			root = f.index.rootOf[pub.ID]
+		}
+		out = append(out, Mention{Pub: pub, Reason: reason, RootID: root})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].Pub, out[j].Pub
+		if !a.PublishedAt.Equal(b.PublishedAt) {
+			return a.PublishedAt.After(b.PublishedAt)
+		}
+		return a.ID > b.ID
+	})
+	return out
+}
@@ -0,0 +1,170 @@
+package publication
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// PostSort orders top-level forum posts.
+type PostSort string
+
+const (
+	// PostHot ranks by score with age decay, so new posts can overtake old ones.
+	PostHot PostSort = "hot"
+	// PostNew ranks newest first.
+	PostNew PostSort = "new"
+	// PostTop ranks by raw score within a time window.
+	PostTop PostSort = "top"
+	// PostControversial ranks posts with many, evenly split votes first.
+	PostControversial PostSort = "controversial"
+)
+
+// TimeWindow limits PostTop and PostControversial to recent posts.
+type TimeWindow string
+
+const (
+	WindowDay  TimeWindow = "day"
+	WindowWeek TimeWindow = "week"
+	WindowAll  TimeWindow = "all"
+)
+
+// hotDecay is how much newer a post must be to outrank one with ten times
+// its score (Reddit's 12.5 hours).
+const hotDecay = 45000 * time.Second
+
+// ParsePostSort reads a sort option; empty means PostHot and "recent" is
+// accepted for PostNew.
+func ParsePostSort(s string) (PostSort, error) {
+	switch order := PostSort(strings.ToLower(strings.TrimSpace(s))); order {
+	case "":
+		return PostHot, nil
+	case "recent":
+		return PostNew, nil
+	case PostHot, PostNew, PostTop, PostControversial:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid sort %q (want hot, new, top or controversial)", s)
+	}
+}
+
+// ParseTimeWindow reads a time window; empty means WindowAll.
+func ParseTimeWindow(s string) (TimeWindow, error) {
+	switch w := TimeWindow(strings.ToLower(strings.TrimSpace(s))); w {
+	case "":
+		return WindowAll, nil
+	case WindowDay, WindowWeek, WindowAll:
+		return w, nil
+	default:
+		return "", fmt.Errorf("invalid time window %q (want day, week or all)", s)
+	}
+}
+
+func (w TimeWindow) duration() time.Duration {
+	switch w {
+	case WindowDay:
+		return 24 * time.Hour
+	case WindowWeek:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// HotScore combines a post's score with its age: roughly every tenfold of
+// net votes is worth hotDecay of recency. Unlike Reddit's formula, which
+// uses log10(max(|score|, 1)), it uses log10(1+|score|) so that a score of
+// -1 still ranks below +1. It depends only on the post, so rankings are
+// stable between requests.
+func HotScore(p *types.Publication) float64 {
+	order := math.Log10(1 + math.Abs(float64(p.Score)))
+	if p.Score < 0 {
+		order = -order
+	}
+	return order + float64(p.PublishedAt.Unix())/hotDecay.Seconds()
+}
+
+// ControversialScore is high for posts with many votes split evenly between
+// up and down, and zero for posts that only have one kind.
+func ControversialScore(p *types.Publication) float64 {
+	if p.Upvotes <= 0 || p.Downvotes <= 0 {
+		return 0
+	}
+	magnitude := float64(p.Upvotes + p.Downvotes)
+	balance := float64(p.Downvotes) / float64(p.Upvotes)
+	if p.Upvotes < p.Downvotes {
+		balance = float64(p.Upvotes) / float64(p.Downvotes)
+	}
+	return math.Pow(magnitude, balance)
+}
+
+// Ranked returns visible top-level posts, optionally from one subreddit,
+// ordered by order. For PostTop and PostControversial the window is measured
+// back from the newest post, so a finished run's data ranks the same way it
+// did when the run ended. A limit <= 0 returns all posts.
+func (f *Forum) Ranked(sub types.Subreddit, order PostSort, window TimeWindow, limit int) []*types.Publication {
+	return f.RankedChannel("", sub, order, window, limit)
+}
+
+// RankedChannel is Ranked restricted to one channel: ChannelPreprint lists
+// preprints, ChannelForum ordinary threads and empty both.
+func (f *Forum) RankedChannel(channel types.ChannelType, sub types.Subreddit, order PostSort, window TimeWindow, limit int) []*types.Publication {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	posts := make([]*types.Publication, 0)
+	var newest time.Time
+	for _, p := range f.Posts {
+		if p.IsComment || !p.Visible() || (sub != "" && p.Subreddit != sub) || (channel != "" && p.Channel != channel) {
+			continue
+		}
+		posts = append(posts, p)
+		if p.PublishedAt.After(newest) {
+			newest = p.PublishedAt
+		}
+	}
+
+	if order == PostTop || order == PostControversial {
+		if d := window.duration(); d > 0 {
+			cutoff := newest.Add(-d)
+			kept := posts[:0]
+			for _, p := range posts {
+				if !p.PublishedAt.Before(cutoff) {
+					kept = append(kept, p)
+				}
+			}
+			posts = kept
+		}
+	}
+
+	var key func(*types.Publication) float64
+	switch order {
+	case PostNew:
+		key = func(p *types.Publication) float64 { return float64(p.PublishedAt.UnixNano()) }
+	case PostTop:
+		key = func(p *types.Publication) float64 { return float64(p.Score) }
+	case PostControversial:
+		key = ControversialScore
+	default:
+		key = HotScore
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		a, b := posts[i], posts[j]
+		if ka, kb := key(a), key(b); ka != kb {
+			return ka > kb
+		}
+		if !a.PublishedAt.Equal(b.PublishedAt) {
+			return a.PublishedAt.After(b.PublishedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	if limit > 0 && limit < len(posts) {
+		posts = posts[:limit]
+	}
+	return posts
+}
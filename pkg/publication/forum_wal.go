@@ -0,0 +1,204 @@
+package publication
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// The forum persists in two parts: forum.json, a snapshot rewritten by Save,
+// and forum.wal, an append-only log of the posts, comments and votes made
+// since. Load replays the log over the snapshot, so a crash between
+// checkpoints loses nothing that reached the log, and a crash during Save
+// leaves the previous snapshot intact. Other changes (moderation, summaries,
+// subreddits) are persisted with the next snapshot.
+const (
+	forumSnapshotFile = "forum.json"
+	forumWALFile      = "forum.wal"
+)
+
+// walRecord holds the state of everything one operation changed, so
+// replaying a record is idempotent.
+type walRecord struct {
+	Seq  uint64               `json:"seq"`
+	Op   string               `json:"op"`
+	Pubs []*types.Publication `json:"pubs,omitempty"`
+	// VoteKey names the vote the operation set, or withdrew when Vote is nil.
+	VoteKey string      `json:"vote_key,omitempty"`
+	Vote    *types.Vote `json:"vote,omitempty"`
+	// Deleted lists posts the operation removed; see Forum.Revert.
+	Deleted []string `json:"deleted,omitempty"`
+}
+
+// appendWALLocked logs an operation. It is a no-op for forums without a data
+// path. Failures are logged rather than returned: the change is in memory
+// and reaches disk with the next snapshot. Caller must hold f.mu for writing.
+func (f *Forum) appendWALLocked(op string, pubs []*types.Publication, voteKey string, vote *types.Vote) {
+	if f.dataPath == "" {
+		return
+	}
+	f.writeWALLocked(walRecord{Seq: f.WALSeq + 1, Op: op, Pubs: pubs, VoteKey: voteKey, Vote: vote})
+}
+
+// appendDeleteWALLocked logs the removal of posts, with the publications the
+// removal changed. Caller must hold f.mu for writing.
+func (f *Forum) appendDeleteWALLocked(pubs []*types.Publication, deleted ...string) {
+	if f.dataPath == "" {
+		return
+	}
+	f.writeWALLocked(walRecord{Seq: f.WALSeq + 1, Op: "revert", Pubs: pubs, Deleted: deleted})
+}
+
+func (f *Forum) writeWALLocked(rec walRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		log.Printf("forum wal: encode %s: %v", rec.Op, err)
+		return
+	}
+	if f.wal == nil {
+		if err := os.MkdirAll(f.dataPath, 0755); err != nil {
+			log.Printf("forum wal: %v", err)
+			return
+		}
+		w, err := os.OpenFile(filepath.Join(f.dataPath, forumWALFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Printf("forum wal: %v", err)
+			return
+		}
+		f.wal = w
+	}
+	if _, err := f.wal.Write(append(data, '\n')); err != nil {
+		log.Printf("forum wal: append %s: %v", rec.Op, err)
+		return
+	}
+	f.WALSeq = rec.Seq
+}
+
+// replayWALLocked applies log records newer than the snapshot. A torn final
+// line from a crash mid-append is ignored. Caller must hold f.mu for writing.
+func (f *Forum) replayWALLocked() error {
+	records, err := readWAL(filepath.Join(f.dataPath, forumWALFile))
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if rec.Seq <= f.WALSeq {
+			continue
+		}
+		for _, p := range rec.Pubs {
+			if p != nil {
+				f.Posts[p.ID] = p
+			}
+		}
+		for _, id := range rec.Deleted {
+			delete(f.Posts, id)
+		}
+		if rec.VoteKey != "" {
+			if rec.Vote != nil {
+				f.Votes[rec.VoteKey] = rec.Vote
+			} else {
+				delete(f.Votes, rec.VoteKey)
+			}
+		}
+		f.WALSeq = rec.Seq
+	}
+	return nil
+}
+
+func readWAL(path string) ([]walRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var out []walRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec walRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out, scanner.Err()
+}
+
+// compactWAL drops log records covered by a snapshot taken at seq. Records
+// appended since that snapshot are kept.
+func (f *Forum) compactWAL(seq uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := filepath.Join(f.dataPath, forumWALFile)
+	if f.wal != nil {
+		if err := f.wal.Close(); err != nil {
+			return err
+		}
+		f.wal = nil
+	}
+	if f.WALSeq <= seq {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	records, err := readWAL(path)
+	if err != nil {
+		return err
+	}
+	var buf strings.Builder
+	for _, rec := range records {
+		if rec.Seq <= seq {
+			continue
+		}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return writeFileAtomic(path, []byte(buf.String()), 0644)
+}
+
+// writeFileAtomic writes data to a temp file beside path, syncs it and
+// renames it into place, so readers see the old or the new file, never a
+// partial one.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,123 @@
+package publication
+
+import (
+	"sort"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ForumCheck lists forum records that point at posts which no longer exist.
+type ForumCheck struct {
+	// OrphanedComments are comments whose parent is missing, followed by
+	// replies under them, which are unreachable too.
+	OrphanedComments []*types.Publication
+	// DanglingVotes are votes on missing or orphaned posts, by vote key.
+	DanglingVotes map[string]*types.Vote
+}
+
+// Empty reports whether the check found nothing.
+func (c ForumCheck) Empty() bool {
+	return len(c.OrphanedComments) == 0 && len(c.DanglingVotes) == 0
+}
+
+// Check finds orphaned comments and votes on missing posts. cmd/fsck_data
+// uses it to validate a data directory.
+func (f *Forum) Check() ForumCheck {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	orphaned := make(map[string]bool)
+	for id, p := range f.Posts {
+		if p != nil && p.IsComment && f.Posts[p.ParentID] == nil {
+			orphaned[id] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for id, p := range f.Posts {
+			if p != nil && !orphaned[id] && p.IsComment && orphaned[p.ParentID] {
+				orphaned[id] = true
+				changed = true
+			}
+		}
+	}
+
+	c := ForumCheck{DanglingVotes: make(map[string]*types.Vote)}
+	for id := range orphaned {
+		c.OrphanedComments = append(c.OrphanedComments, f.Posts[id])
+	}
+	sort.Slice(c.OrphanedComments, func(i, j int) bool {
+		return c.OrphanedComments[i].ID < c.OrphanedComments[j].ID
+	})
+	for key, v := range f.Votes {
+		if v == nil || f.Posts[v.PostID] == nil || orphaned[v.PostID] {
+			c.DanglingVotes[key] = v
+		}
+	}
+	return c
+}
+
+// DropBroken removes the records found by Check, along with reports and
+// summaries of the dropped comments. Call Save afterwards to persist the
+// result.
+func (f *Forum) DropBroken(c ForumCheck) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dropped := make(map[string]bool, len(c.OrphanedComments))
+	for _, p := range c.OrphanedComments {
+		dropped[p.ID] = true
+		delete(f.Posts, p.ID)
+		delete(f.Summaries, p.ID)
+	}
+	for key := range c.DanglingVotes {
+		delete(f.Votes, key)
+	}
+	for id, r := range f.Reports {
+		if dropped[r.PostID] {
+			delete(f.Reports, id)
+		}
+	}
+	f.rebuildIndexLocked()
+}
+
+// MissingSubmissions returns workflow submissions the journal holds no paper
+// for, pending, published or rejected, ordered by ID.
+func (w *Workflow) MissingSubmissions(journal *Journal) []*types.Submission {
+	w.mu.RLock()
+	subs := make([]*types.Submission, 0, len(w.Submissions))
+	for _, sub := range w.Submissions {
+		subs = append(subs, sub)
+	}
+	w.mu.RUnlock()
+
+	journal.mu.RLock()
+	defer journal.mu.RUnlock()
+	out := make([]*types.Submission, 0)
+	for _, sub := range subs {
+		id := sub.ID
+		if journal.Publications[id] == nil && journal.Pending[id] == nil && journal.Rejected[id] == nil {
+			out = append(out, sub)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// DropSubmissions removes submissions and their reviews, and returns the
+// reviews removed per submission. Call Save afterwards to persist the
+// result.
+func (w *Workflow) DropSubmissions(ids []string) map[string][]*types.PaperReview {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	reviews := make(map[string][]*types.PaperReview)
+	for _, id := range ids {
+		if rs := w.Reviews[id]; len(rs) > 0 {
+			reviews[id] = rs
+		}
+		delete(w.Submissions, id)
+		delete(w.Reviews, id)
+	}
+	return reviews
+}
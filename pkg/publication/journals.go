@@ -0,0 +1,248 @@
+package publication
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// JournalConfig is the on-disk format of a journals config file.
+type JournalConfig struct {
+	Journals []*types.JournalInfo `json:"journals"`
+}
+
+// LoadJournalConfig reads journal definitions from path. The first journal is
+// the default one; papers that match no journal's domains are routed to the
+// first general (domain-less) journal, or the default when there is none.
+func LoadJournalConfig(path string) ([]*types.JournalInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg JournalConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := validateJournals(cfg.Journals); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg.Journals, nil
+}
+
+func validateJournals(journals []*types.JournalInfo) error {
+	if len(journals) == 0 {
+		return fmt.Errorf("no journals defined")
+	}
+	seen := make(map[string]bool, len(journals))
+	for i, info := range journals {
+		if info == nil || strings.TrimSpace(info.ID) == "" || strings.TrimSpace(info.Name) == "" {
+			return fmt.Errorf("journal %d: id and name are required", i)
+		}
+		if seen[info.ID] {
+			return fmt.Errorf("duplicate journal id: %s", info.ID)
+		}
+		seen[info.ID] = true
+		if info.AcceptanceThreshold < 0 || info.AcceptanceThreshold > 10 {
+			return fmt.Errorf("journal %s: acceptance_threshold must be within 0-10", info.ID)
+		}
+	}
+	return nil
+}
+
+// SetJournals replaces the configured journals. Call it after Load, which
+// restores the journals persisted with the store.
+func (j *Journal) SetJournals(journals []*types.JournalInfo) error {
+	if err := validateJournals(journals); err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	before := j.audit.Hash(j.Journals)
+	j.Journals = journals
+	recordChange(j.audit, audit.StoreJournal, "configure_journals", "", "journals", before, journals)
+	return nil
+}
+
+// JournalList returns the configured journals, default first. Without
+// configuration it is a single general journal named after the store.
+func (j *Journal) JournalList() []types.JournalInfo {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.journalListLocked()
+}
+
+func (j *Journal) journalListLocked() []types.JournalInfo {
+	if len(j.Journals) == 0 {
+		return []types.JournalInfo{{ID: types.DefaultJournalID, Name: j.Name}}
+	}
+	list := make([]types.JournalInfo, 0, len(j.Journals))
+	for _, info := range j.Journals {
+		list = append(list, *info)
+	}
+	return list
+}
+
+// JournalInfo returns the journal with the given ID; empty selects the
+// default journal.
+func (j *Journal) JournalInfo(id string) (types.JournalInfo, bool) {
+	list := j.JournalList()
+	if id == "" {
+		return list[0], true
+	}
+	for _, info := range list {
+		if info.ID == id {
+			return info, true
+		}
+	}
+	return types.JournalInfo{}, false
+}
+
+// JournalOf returns the ID of the journal a publication belongs to.
+func (j *Journal) JournalOf(pub *types.Publication) string {
+	if pub != nil && pub.JournalID != "" {
+		return pub.JournalID
+	}
+	return j.JournalList()[0].ID
+}
+
+// Route picks the journal for a submission. A requested ID or name must
+// match a configured journal; otherwise the journal whose domains best match
+// the author's domains and the paper text wins, falling back to a general
+// journal.
+func (j *Journal) Route(requested, text string, domains []string) (types.JournalInfo, error) {
+	list := j.JournalList()
+	if requested = strings.TrimSpace(requested); requested != "" {
+		for _, info := range list {
+			if strings.EqualFold(info.ID, requested) || info.Name == requested {
+				return info, nil
+			}
+		}
+		ids := make([]string, 0, len(list))
+		for _, info := range list {
+			ids = append(ids, info.ID)
+		}
+		return types.JournalInfo{}, fmt.Errorf("unknown journal %q (available: %s)", requested, strings.Join(ids, ", "))
+	}
+
+	text = strings.ToLower(text)
+	best, bestScore := -1, 0
+	for i, info := range list {
+		score := 0
+		for _, d := range info.Domains {
+			d = strings.ToLower(strings.TrimSpace(d))
+			if d == "" {
+				continue
+			}
+			for _, own := range domains {
+				if strings.EqualFold(strings.TrimSpace(own), d) {
+					score += 2
+				}
+			}
+			if strings.Contains(text, d) {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best >= 0 {
+		return list[best], nil
+	}
+	for _, info := range list {
+		if len(info.Domains) == 0 {
+			return info, nil
+		}
+	}
+	return list[0], nil
+}
+
+// JournalSummary is a journal with its paper counts.
+type JournalSummary struct {
+	types.JournalInfo
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+	Pending  int `json:"pending"`
+}
+
+// JournalSummaries returns every configured journal with its counts, in
+// configuration order. Papers of journals no longer configured count toward
+// the default journal.
+func (j *Journal) JournalSummaries() []JournalSummary {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	list := j.journalListLocked()
+	index := make(map[string]int, len(list))
+	out := make([]JournalSummary, len(list))
+	for i, info := range list {
+		out[i] = JournalSummary{JournalInfo: info}
+		index[info.ID] = i
+	}
+	slot := func(pub *types.Publication) *JournalSummary {
+		if i, ok := index[pub.JournalID]; ok {
+			return &out[i]
+		}
+		return &out[0]
+	}
+	for _, pub := range j.Publications {
+		slot(pub).Accepted++
+	}
+	for _, pub := range j.Rejected {
+		slot(pub).Rejected++
+	}
+	for _, pub := range j.Pending {
+		slot(pub).Pending++
+	}
+	return out
+}
+
+// FilterByJournal keeps the publications that belong to journalID.
+func (j *Journal) FilterByJournal(pubs []*types.Publication, journalID string) []*types.Publication {
+	defaultID := j.JournalList()[0].ID
+	out := make([]*types.Publication, 0, len(pubs))
+	for _, pub := range pubs {
+		id := pub.JournalID
+		if id == "" {
+			id = defaultID
+		}
+		if id == journalID {
+			out = append(out, pub)
+		}
+	}
+	return out
+}
+
+// meanReviewScore averages the scored dimensions of reviews that carry
+// scores; ok is false when none do.
+func meanReviewScore(reviews []*types.PaperReview) (mean float64, ok bool) {
+	sum, n := 0.0, 0
+	for _, r := range reviews {
+		if r == nil {
+			continue
+		}
+		s := r.Scores
+		dims := []float64{s.Novelty, s.Rigor, s.Falsifiability, s.Reproducibility, s.CrossDomain}
+		scored := false
+		for _, v := range dims {
+			if v != 0 {
+				scored = true
+			}
+		}
+		if !scored {
+			continue
+		}
+		for _, v := range dims {
+			sum += v
+		}
+		n += len(dims)
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
@@ -0,0 +1,183 @@
+package publication
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/idgen"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+var reportCategories = map[string]bool{
+	types.ReportSpam:       true,
+	types.ReportDuplicate:  true,
+	types.ReportLowQuality: true,
+	types.ReportOffTopic:   true,
+	types.ReportOther:      true,
+	types.ReportHostile:    true,
+}
+
+// Report files a community report against a post or comment. Each agent may
+// report a publication once; reporting your own post is not allowed.
+func (f *Forum) Report(report *types.PostReport) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	post, ok := f.Posts[report.PostID]
+	if !ok {
+		return fmt.Errorf("post not found: %s", report.PostID)
+	}
+	if post.Moderation == types.ModerationRemoved {
+		return fmt.Errorf("post was removed by a moderator: %s", report.PostID)
+	}
+	if post.AuthorID == report.ReporterID {
+		return fmt.Errorf("cannot report your own post: %s", report.PostID)
+	}
+	report.Category = strings.ToLower(strings.TrimSpace(report.Category))
+	if report.Category == "" {
+		report.Category = types.ReportOther
+	}
+	if !reportCategories[report.Category] {
+		return fmt.Errorf("invalid report category: %s", report.Category)
+	}
+	report.Reason = strings.TrimSpace(report.Reason)
+	if report.Reason == "" {
+		return fmt.Errorf("missing report reason")
+	}
+
+	key := report.ReporterID + ":" + report.PostID
+	if _, exists := f.Reports[key]; exists {
+		return fmt.Errorf("already reported: %s", report.PostID)
+	}
+	if report.ID == "" {
+		report.ID = idgen.New("report")
+	}
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = time.Now()
+	}
+	if f.Reports == nil {
+		f.Reports = make(map[string]*types.PostReport)
+	}
+	f.Reports[key] = report
+	recordChange(f.audit, audit.StoreForum, "report", report.ReporterID, report.PostID, "", report)
+	return nil
+}
+
+// HidePost drops a publication from feeds; it stays readable by ID.
+func (f *Forum) HidePost(postID, moderatorID, moderatorName, reason string) (*types.ModerationAction, error) {
+	return f.moderate(postID, types.ModActionHide, moderatorID, moderatorName, reason)
+}
+
+// RemovePost drops a publication from feeds and blocks further votes,
+// replies and reports on it.
+func (f *Forum) RemovePost(postID, moderatorID, moderatorName, reason string) (*types.ModerationAction, error) {
+	return f.moderate(postID, types.ModActionRemove, moderatorID, moderatorName, reason)
+}
+
+// RestorePost reverses a hide or removal.
+func (f *Forum) RestorePost(postID, moderatorID, moderatorName, reason string) (*types.ModerationAction, error) {
+	return f.moderate(postID, types.ModActionRestore, moderatorID, moderatorName, reason)
+}
+
+// DismissReports closes the open reports on a publication without changing it.
+func (f *Forum) DismissReports(postID, moderatorID, moderatorName, reason string) (*types.ModerationAction, error) {
+	return f.moderate(postID, types.ModActionDismiss, moderatorID, moderatorName, reason)
+}
+
+func (f *Forum) moderate(postID string, action types.ModerationActionType, moderatorID, moderatorName, reason string) (*types.ModerationAction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	post, ok := f.Posts[postID]
+	if !ok {
+		return nil, fmt.Errorf("post not found: %s", postID)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("missing moderation reason")
+	}
+	before := f.audit.Hash(post)
+
+	switch action {
+	case types.ModActionHide:
+		post.Moderation = types.ModerationHidden
+		post.ModerationReason = reason
+	case types.ModActionRemove:
+		post.Moderation = types.ModerationRemoved
+		post.ModerationReason = reason
+	case types.ModActionRestore:
+		if post.Moderation == types.ModerationNone {
+			return nil, fmt.Errorf("post is not moderated: %s", postID)
+		}
+		post.Moderation = types.ModerationNone
+		post.ModerationReason = ""
+	case types.ModActionDismiss:
+	default:
+		return nil, fmt.Errorf("invalid moderation action: %s", action)
+	}
+
+	now := time.Now()
+	entry := &types.ModerationAction{
+		ID:            idgen.New("modaction"),
+		PostID:        postID,
+		Action:        action,
+		ModeratorID:   moderatorID,
+		ModeratorName: moderatorName,
+		Reason:        reason,
+		At:            now,
+	}
+	// Any action on a post closes its open reports.
+	for _, r := range f.Reports {
+		if r.PostID == postID && r.Open() {
+			r.ResolvedAt = now
+			r.ActionID = entry.ID
+			entry.ReportIDs = append(entry.ReportIDs, r.ID)
+		}
+	}
+	if action == types.ModActionDismiss && len(entry.ReportIDs) == 0 {
+		return nil, fmt.Errorf("no open reports on post: %s", postID)
+	}
+	sort.Strings(entry.ReportIDs)
+	f.ModerationLog = append(f.ModerationLog, entry)
+	recordChange(f.audit, audit.StoreForum, string(action), moderatorID, postID, before, post)
+	return entry, nil
+}
+
+// GetReports returns reports, oldest first. With openOnly, resolved reports
+// are skipped.
+func (f *Forum) GetReports(openOnly bool) []*types.PostReport {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	out := make([]*types.PostReport, 0, len(f.Reports))
+	for _, r := range f.Reports {
+		if openOnly && !r.Open() {
+			continue
+		}
+		cp := *r
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out
+}
+
+// GetModerationLog returns a copy of the moderation log, oldest first.
+func (f *Forum) GetModerationLog() []*types.ModerationAction {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	out := make([]*types.ModerationAction, 0, len(f.ModerationLog))
+	for _, a := range f.ModerationLog {
+		cp := *a
+		out = append(out, &cp)
+	}
+	return out
+}
@@ -11,6 +11,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/idgen"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
 
@@ -21,7 +23,14 @@ type Journal struct {
 	Name         string                        `json:"name"`
 	Publications map[string]*types.Publication `json:"publications"`
 	Pending      map[string]*types.Publication `json:"pending"` // Awaiting review
-	dataPath     string
+	Rejected     map[string]*types.Publication `json:"rejected,omitempty"`
+	// Journals lists the configured journals sharing this store; see journals.go.
+	Journals []*types.JournalInfo `json:"journals,omitempty"`
+	dataPath string
+	audit    *audit.Log
+	saveMu   sync.Mutex // orders concurrent saves
+
+	retractListeners []func(*types.Publication)
 }
 
 // NewJournal creates a new journal.
@@ -30,6 +39,7 @@ func NewJournal(name, dataPath string) *Journal {
 		Name:         name,
 		Publications: make(map[string]*types.Publication),
 		Pending:      make(map[string]*types.Publication),
+		Rejected:     make(map[string]*types.Publication),
 		dataPath:     dataPath,
 	}
 }
@@ -40,11 +50,12 @@ func (j *Journal) Submit(pub *types.Publication) error {
 	defer j.mu.Unlock()
 
 	if pub.ID == "" {
-		pub.ID = fmt.Sprintf("journal-%d", time.Now().UnixNano())
+		pub.ID = idgen.New("journal")
 	}
 	pub.Channel = types.ChannelJournal
 	pub.Approved = false
 	j.Pending[pub.ID] = pub
+	recordChange(j.audit, audit.StoreJournal, "submit", pub.AuthorID, pub.ID, "", pub)
 
 	return nil
 }
@@ -70,6 +81,7 @@ func (j *Journal) Approve(pubID, reviewerID string) error {
 	if !ok {
 		return fmt.Errorf("publication not found in pending: %s", pubID)
 	}
+	before := j.audit.Hash(pub)
 
 	pub.Approved = true
 	pub.Reviewers = append(pub.Reviewers, reviewerID)
@@ -77,24 +89,86 @@ func (j *Journal) Approve(pubID, reviewerID string) error {
 
 	j.Publications[pubID] = pub
 	delete(j.Pending, pubID)
+	recordChange(j.audit, audit.StoreJournal, "approve", reviewerID, pubID, before, pub)
+
+	return nil
+}
+
+// Publish adds a publication directly to the journal without peer review,
+// e.g. an exogenous classic paper. Existing IDs are left untouched.
+func (j *Journal) Publish(pub *types.Publication) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
 
+	if pub.ID == "" {
+		pub.ID = idgen.New("journal")
+	}
+	if _, ok := j.Publications[pub.ID]; ok {
+		return fmt.Errorf("publication already exists: %s", pub.ID)
+	}
+	pub.Channel = types.ChannelJournal
+	pub.Approved = true
+	pub.PublishedAt = time.Now()
+	j.Publications[pub.ID] = pub
+	recordChange(j.audit, audit.StoreJournal, "publish", pub.AuthorID, pub.ID, "", pub)
 	return nil
 }
 
 // Reject rejects a pending publication.
 func (j *Journal) Reject(pubID, reviewerID string) error {
+	return j.RejectWithReason(pubID, reviewerID, "")
+}
+
+// RejectWithReason rejects a pending publication and keeps it in the rejected
+// archive together with the decision rationale.
+func (j *Journal) RejectWithReason(pubID, reviewerID, rationale string) error {
 	j.mu.Lock()
 	defer j.mu.Unlock()
 
-	_, ok := j.Pending[pubID]
+	pub, ok := j.Pending[pubID]
 	if !ok {
 		return fmt.Errorf("publication not found in pending: %s", pubID)
 	}
+	before := j.audit.Hash(pub)
 
+	pub.Reviewers = append(pub.Reviewers, reviewerID)
+	pub.RejectedAt = time.Now()
+	pub.DecisionRationale = rationale
+	if j.Rejected == nil {
+		j.Rejected = make(map[string]*types.Publication)
+	}
+	j.Rejected[pubID] = pub
 	delete(j.Pending, pubID)
+	recordChange(j.audit, audit.StoreJournal, "reject", reviewerID, pubID, before, pub)
 	return nil
 }
 
+// GetRejected returns an archived rejected publication.
+func (j *Journal) GetRejected(pubID string) *types.Publication {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.Rejected[pubID]
+}
+
+// GetRejectedList returns all archived rejected publications.
+func (j *Journal) GetRejectedList() []*types.Publication {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	result := make([]*types.Publication, 0, len(j.Rejected))
+	for _, pub := range j.Rejected {
+		result = append(result, pub)
+	}
+	return result
+}
+
+// AcceptanceStats returns decided and pending counts for acceptance-rate analytics.
+func (j *Journal) AcceptanceStats() (accepted, rejected, pending int) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return len(j.Publications), len(j.Rejected), len(j.Pending)
+}
+
 // GetApproved returns all approved publications.
 func (j *Journal) GetApproved() []*types.Publication {
 	j.mu.RLock()
@@ -125,21 +199,23 @@ func (j *Journal) IncrementViews(pubID string) {
 	}
 }
 
-// Save persists the journal to disk.
+// Save writes a snapshot of the journal atomically. Saves are serialized
+// so an older snapshot never replaces a newer one.
 func (j *Journal) Save() error {
-	j.mu.RLock()
-	defer j.mu.RUnlock()
-
-	if err := os.MkdirAll(j.dataPath, 0755); err != nil {
-		return err
-	}
+	j.saveMu.Lock()
+	defer j.saveMu.Unlock()
 
+	j.mu.RLock()
 	data, err := json.MarshalIndent(j, "", "  ")
+	j.mu.RUnlock()
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filepath.Join(j.dataPath, "journal.json"), data, 0644)
+	if err := os.MkdirAll(j.dataPath, 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(j.dataPath, "journal.json"), data, 0644)
 }
 
 // Load loads the journal from disk.
@@ -166,7 +242,22 @@ type Forum struct {
 	Posts     map[string]*types.Publication `json:"posts"`
 	Votes     map[string]*types.Vote        `json:"votes"`               // key: "voterID:postID"
 	Summaries map[string]*types.ThreadSummary `json:"summaries,omitempty"` // key: root post id
-	dataPath  string
+	// Subreddits holds agent-created subreddits; built-ins are implicit.
+	Subreddits map[types.Subreddit]*types.SubredditInfo `json:"subreddits,omitempty"`
+	// Reports and ModerationLog back forum moderation; see moderation.go.
+	Reports       map[string]*types.PostReport `json:"reports,omitempty"` // key: "reporterID:postID"
+	ModerationLog []*types.ModerationAction    `json:"moderation_log,omitempty"`
+	// WALSeq is the last write-ahead log record applied; see forum_wal.go.
+	WALSeq   uint64 `json:"wal_seq,omitempty"`
+	dataPath string
+
+	voteListeners    []func(types.VoteChange)
+	publishListeners []func(*types.Publication)
+	clock            func() time.Time // stamps vote changes; nil: wall clock
+	index            forumIndex
+	audit            *audit.Log
+	wal              *os.File
+	saveMu           sync.Mutex // orders snapshots with WAL compaction
 }
 
 // NewForum creates a new forum.
@@ -177,18 +268,35 @@ func NewForum(name, dataPath string) *Forum {
 		Votes:     make(map[string]*types.Vote),
 		Summaries: make(map[string]*types.ThreadSummary),
 		dataPath:  dataPath,
+
+		Subreddits: make(map[types.Subreddit]*types.SubredditInfo),
+		Reports:    make(map[string]*types.PostReport),
+		index:      newForumIndex(),
 	}
 }
 
 // Post publishes a post without review.
 func (f *Forum) Post(pub *types.Publication) error {
+	listeners, err := f.post(pub)
+	if err != nil {
+		return err
+	}
+	for _, fn := range listeners {
+		fn(pub)
+	}
+	return nil
+}
+
+func (f *Forum) post(pub *types.Publication) ([]func(*types.Publication), error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	if pub.ID == "" {
-		pub.ID = fmt.Sprintf("forum-%d", time.Now().UnixNano())
+		pub.ID = idgen.New("forum")
+	}
+	if pub.Channel != types.ChannelPreprint {
+		pub.Channel = types.ChannelForum
 	}
-	pub.Channel = types.ChannelForum
 	pub.PublishedAt = time.Now()
 	pub.Approved = true // Forum posts don't need approval
 	pub.Upvotes = 1     // Author's implicit upvote
@@ -198,22 +306,45 @@ func (f *Forum) Post(pub *types.Publication) error {
 		pub.Subreddit = types.SubGeneral
 	}
 
+	before := ""
+	prev, replaced := f.Posts[pub.ID]
+	if replaced {
+		before = f.audit.Hash(prev)
+	}
+	f.flagDuplicateLocked(pub)
 	f.Posts[pub.ID] = pub
-	return nil
+	f.indexLocked(pub, replaced)
+	f.appendWALLocked("post", []*types.Publication{pub}, "", nil)
+	recordChange(f.audit, audit.StoreForum, "post", pub.AuthorID, pub.ID, before, pub)
+	return append([]func(*types.Publication){}, f.publishListeners...), nil
 }
 
 // Comment adds a comment to a post.
 func (f *Forum) Comment(parentID string, comment *types.Publication) error {
+	listeners, err := f.comment(parentID, comment)
+	if err != nil {
+		return err
+	}
+	for _, fn := range listeners {
+		fn(comment)
+	}
+	return nil
+}
+
+func (f *Forum) comment(parentID string, comment *types.Publication) ([]func(*types.Publication), error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	parent, ok := f.Posts[parentID]
 	if !ok {
-		return fmt.Errorf("parent post not found: %s", parentID)
+		return nil, fmt.Errorf("parent post not found: %s", parentID)
+	}
+	if parent.Moderation == types.ModerationRemoved {
+		return nil, fmt.Errorf("post was removed by a moderator: %s", parentID)
 	}
 
 	if comment.ID == "" {
-		comment.ID = fmt.Sprintf("comment-%d", time.Now().UnixNano())
+		comment.ID = idgen.New("comment")
 	}
 	comment.Channel = types.ChannelForum
 	comment.PublishedAt = time.Now()
@@ -223,10 +354,15 @@ func (f *Forum) Comment(parentID string, comment *types.Publication) error {
 	comment.Subreddit = parent.Subreddit
 	comment.Score = 1
 
+	_, replaced := f.Posts[comment.ID]
 	f.Posts[comment.ID] = comment
+	f.indexLocked(comment, replaced)
+	f.scoreCommentLocked(comment, true)
 	parent.Comments++
+	f.appendWALLocked("comment", []*types.Publication{comment, parent}, "", nil)
+	recordChange(f.audit, audit.StoreForum, "comment", comment.AuthorID, comment.ID, "", comment)
 
-	return nil
+	return append([]func(*types.Publication){}, f.publishListeners...), nil
 }
 
 // Upvote upvotes a post.
@@ -239,15 +375,56 @@ func (f *Forum) Downvote(voterID, postID string) error {
 	return f.vote(voterID, postID, false)
 }
 
+// OnVote registers a listener invoked after every successful vote change.
+// Listeners run outside the forum lock and may call back into the forum.
+func (f *Forum) OnVote(fn func(types.VoteChange)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.voteListeners = append(f.voteListeners, fn)
+}
+
+// SetClock sets the clock that stamps the vote changes passed to OnVote
+// listeners, so karma history follows simulated time. Stored votes keep
+// wall-clock times, which TruncateAfter compares against.
+func (f *Forum) SetClock(now func() time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clock = now
+}
+
+// OnPublish registers a listener invoked after every new post or comment.
+// Listeners run outside the forum lock and may call back into the forum.
+func (f *Forum) OnPublish(fn func(*types.Publication)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.publishListeners = append(f.publishListeners, fn)
+}
+
 // vote handles voting logic.
 func (f *Forum) vote(voterID, postID string, isUpvote bool) error {
+	change, listeners, err := f.applyVote(voterID, postID, isUpvote)
+	if err != nil {
+		return err
+	}
+	for _, fn := range listeners {
+		fn(change)
+	}
+	return nil
+}
+
+func (f *Forum) applyVote(voterID, postID string, isUpvote bool) (types.VoteChange, []func(types.VoteChange), error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	post, ok := f.Posts[postID]
 	if !ok {
-		return fmt.Errorf("post not found: %s", postID)
+		return types.VoteChange{}, nil, fmt.Errorf("post not found: %s", postID)
+	}
+	if post.Moderation == types.ModerationRemoved {
+		return types.VoteChange{}, nil, fmt.Errorf("post was removed by a moderator: %s", postID)
 	}
+	upBefore, downBefore := post.Upvotes, post.Downvotes
+	before := f.audit.Hash(post)
 
 	voteKey := voterID + ":" + postID
 	existingVote, hasVoted := f.Votes[voteKey]
@@ -290,75 +467,50 @@ func (f *Forum) vote(voterID, postID string, isUpvote bool) error {
 	}
 
 	post.Score = post.Upvotes - post.Downvotes
-	return nil
+	change := types.VoteChange{
+		VoterID:   voterID,
+		AuthorID:  post.AuthorID,
+		PostID:    postID,
+		IsComment: post.IsComment,
+		UpDelta:   post.Upvotes - upBefore,
+		DownDelta: post.Downvotes - downBefore,
+		At:        time.Now(),
+	}
+	if f.clock != nil {
+		change.At = f.clock()
+	}
+	op := "downvote"
+	if isUpvote {
+		op = "upvote"
+	}
+	f.appendWALLocked(op, []*types.Publication{post}, voteKey, f.Votes[voteKey])
+	recordChange(f.audit, audit.StoreForum, op, voterID, postID, before, post)
+	return change, append([]func(types.VoteChange){}, f.voteListeners...), nil
 }
 
-// GetBySubreddit returns posts from a specific subreddit.
+// GetBySubreddit returns hot posts from a specific subreddit.
 func (f *Forum) GetBySubreddit(sub types.Subreddit, limit int) []*types.Publication {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	posts := make([]*types.Publication, 0)
-	for _, p := range f.Posts {
-		if p.Subreddit == sub && !p.IsComment {
-			posts = append(posts, p)
-		}
+	if limit <= 0 {
+		return []*types.Publication{}
 	}
-
-	// Sort by score descending (hot posts)
-	f.sortByScore(posts)
-
-	if limit > len(posts) {
-		limit = len(posts)
-	}
-	return posts[:limit]
+	return f.Ranked(sub, PostHot, WindowAll, limit)
 }
 
-// GetHot returns hot posts (highest score) across all subreddits.
+// GetHot returns hot posts (score with age decay, see HotScore) across all
+// subreddits.
 func (f *Forum) GetHot(limit int) []*types.Publication {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	posts := make([]*types.Publication, 0)
-	for _, p := range f.Posts {
-		if !p.IsComment {
-			posts = append(posts, p)
-		}
-	}
-
-	f.sortByScore(posts)
-
-	if limit > len(posts) {
-		limit = len(posts)
+	if limit <= 0 {
+		return []*types.Publication{}
 	}
-	return posts[:limit]
+	return f.Ranked("", PostHot, WindowAll, limit)
 }
 
 // GetRecent returns recent posts, most recent first.
 func (f *Forum) GetRecent(limit int) []*types.Publication {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	posts := make([]*types.Publication, 0, len(f.Posts))
-	for _, p := range f.Posts {
-		if !p.IsComment {
-			posts = append(posts, p)
-		}
-	}
-
-	// Sort by publish time descending
-	for i := 0; i < len(posts)-1; i++ {
-		for j := i + 1; j < len(posts); j++ {
-			if posts[j].PublishedAt.After(posts[i].PublishedAt) {
-				posts[i], posts[j] = posts[j], posts[i]
-			}
-		}
-	}
-
-	if limit > len(posts) {
-		limit = len(posts)
+	if limit <= 0 {
+		return []*types.Publication{}
 	}
-	return posts[:limit]
+	return f.Ranked("", PostNew, WindowAll, limit)
 }
 
 // GetComments returns comments for a post.
@@ -366,82 +518,38 @@ func (f *Forum) GetComments(postID string) []*types.Publication {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	comments := make([]*types.Publication, 0)
-	for _, p := range f.Posts {
-		if p.ParentID == postID {
+	comments := make([]*types.Publication, 0, len(f.index.children[postID]))
+	for _, p := range f.lookupLocked(f.index.children[postID]) {
+		if p.Visible() {
 			comments = append(comments, p)
 		}
 	}
 
-	f.sortByScore(comments)
+	sortComments(comments, SortTop)
 	return comments
 }
 
-// GetThreadComments returns all comments under a root post, including nested replies.
+// GetThreadComments returns all comments under a root post, including nested
+// replies, in tree order with siblings oldest first. Moderated comments are
+// left out; their replies are kept. See CommentTree for depth and sorting.
 func (f *Forum) GetThreadComments(rootID string) []*types.Publication {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	if _, ok := f.Posts[rootID]; !ok {
+	tree := f.CommentTree(rootID, SortOld)
+	if tree == nil {
 		return nil
 	}
-
-	related := make(map[string]struct{})
-	related[rootID] = struct{}{}
-
-	changed := true
-	for changed {
-		changed = false
-		for _, p := range f.Posts {
-			if !p.IsComment {
-				continue
-			}
-			if _, ok := related[p.ID]; ok {
-				continue
-			}
-			if _, ok := related[p.ParentID]; ok {
-				related[p.ID] = struct{}{}
-				changed = true
-			}
-		}
+	comments := make([]*types.Publication, len(tree))
+	for i, c := range tree {
+		comments[i] = c.Publication
 	}
-
-	comments := make([]*types.Publication, 0)
-	for id := range related {
-		if id == rootID {
-			continue
-		}
-		if p, ok := f.Posts[id]; ok && p.IsComment {
-			comments = append(comments, p)
-		}
-	}
-
 	return comments
 }
 
-// sortByScore sorts posts by score descending.
-func (f *Forum) sortByScore(posts []*types.Publication) {
-	for i := 0; i < len(posts)-1; i++ {
-		for j := i + 1; j < len(posts); j++ {
-			if posts[j].Score > posts[i].Score {
-				posts[i], posts[j] = posts[j], posts[i]
-			}
-		}
-	}
-}
-
 // GetByAuthor returns posts by a specific author.
 func (f *Forum) GetByAuthor(authorID string) []*types.Publication {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
-	result := make([]*types.Publication, 0)
-	for _, p := range f.Posts {
-		if p.AuthorID == authorID {
-			result = append(result, p)
-		}
-	}
-	return result
+	return f.lookupLocked(f.index.byAuthor[authorID])
 }
 
 // Get returns a specific post.
@@ -460,38 +568,43 @@ func (f *Forum) IncrementViews(postID string) {
 	}
 }
 
-// Save persists the forum to disk.
+// Save writes a snapshot of the forum atomically and compacts the
+// write-ahead log it covers.
 func (f *Forum) Save() error {
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+
 	f.mu.RLock()
-	defer f.mu.RUnlock()
+	data, err := json.MarshalIndent(f, "", "  ")
+	seq := f.WALSeq
+	f.mu.RUnlock()
+	if err != nil {
+		return err
+	}
 
 	if err := os.MkdirAll(f.dataPath, 0755); err != nil {
 		return err
 	}
-
-	data, err := json.MarshalIndent(f, "", "  ")
-	if err != nil {
+	if err := writeFileAtomic(filepath.Join(f.dataPath, forumSnapshotFile), data, 0644); err != nil {
 		return err
 	}
-
-	return os.WriteFile(filepath.Join(f.dataPath, "forum.json"), data, 0644)
+	return f.compactWAL(seq)
 }
 
-// Load loads the forum from disk.
+// Load loads the forum snapshot from disk and replays the write-ahead log
+// over it.
 func (f *Forum) Load() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	data, err := os.ReadFile(filepath.Join(f.dataPath, "forum.json"))
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
+	data, err := os.ReadFile(filepath.Join(f.dataPath, forumSnapshotFile))
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
-
-	if err := json.Unmarshal(data, f); err != nil {
-		return err
+	if err == nil {
+		if err := json.Unmarshal(data, f); err != nil {
+			return err
+		}
 	}
 
 	// Ensure maps are initialized
@@ -504,6 +617,23 @@ func (f *Forum) Load() error {
 	if f.Summaries == nil {
 		f.Summaries = make(map[string]*types.ThreadSummary)
 	}
+	if f.Subreddits == nil {
+		f.Subreddits = make(map[types.Subreddit]*types.SubredditInfo)
+	}
+	if f.Reports == nil {
+		f.Reports = make(map[string]*types.PostReport)
+	}
+	if err := f.replayWALLocked(); err != nil {
+		return err
+	}
+	f.rebuildIndexLocked()
+	// Score comments from runs before the civility filter, without
+	// reporting them after the fact.
+	for _, p := range f.Posts {
+		if p != nil && p.IsComment && p.Civility == nil {
+			f.scoreCommentLocked(p, false)
+		}
+	}
 
 	return nil
 }
@@ -540,6 +670,9 @@ func (f *Forum) GetSubredditStats() map[types.Subreddit]int {
 	defer f.mu.RUnlock()
 
 	stats := make(map[types.Subreddit]int)
+	for name := range f.Subreddits {
+		stats[name] = 0
+	}
 	for _, p := range f.Posts {
 		if !p.IsComment {
 			stats[p.Subreddit]++
@@ -560,23 +693,7 @@ func (f *Forum) ResolveRootPostID(pubID string) string {
 	if !pub.IsComment {
 		return pub.ID
 	}
-	seen := map[string]struct{}{pub.ID: {}}
-	parentID := pub.ParentID
-	for parentID != "" {
-		if _, ok := seen[parentID]; ok {
-			break
-		}
-		seen[parentID] = struct{}{}
-		parent, ok := f.Posts[parentID]
-		if !ok || parent == nil {
-			break
-		}
-		if !parent.IsComment {
-			return parent.ID
-		}
-		parentID = parent.ParentID
-	}
-	return ""
+	return f.index.rootOf[pub.ID]
 }
 
 // GetThreadSummary returns a cached summary for a thread if available.
@@ -622,7 +739,12 @@ func (f *Forum) SaveThreadSummary(postID, summary string) (*types.ThreadSummary,
 	if f.Summaries == nil {
 		f.Summaries = make(map[string]*types.ThreadSummary)
 	}
+	before := ""
+	if prev, ok := f.Summaries[rootID]; ok {
+		before = f.audit.Hash(prev)
+	}
 	f.Summaries[rootID] = ts
+	recordChange(f.audit, audit.StoreForum, "summarize", "", rootID, before, ts)
 	return ts, nil
 }
 
@@ -648,3 +770,21 @@ func hashPostContent(post *types.Publication) string {
 	_, _ = h.Write([]byte(post.Content))
 	return fmt.Sprintf("%x", h.Sum64())
 }
+
+// Counts returns the number of top-level threads and comments in the forum.
+func (f *Forum) Counts() (threads, comments int) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, p := range f.Posts {
+		if p == nil {
+			continue
+		}
+		if p.IsComment {
+			comments++
+		} else {
+			threads++
+		}
+	}
+	return threads, comments
+}
@@ -1,8 +1,17 @@
 package publication
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/cpunion/sci-bot/pkg/audit"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
 
@@ -78,6 +87,46 @@ func TestJournal_Reject(t *testing.T) {
 	if len(approved) != 0 {
 		t.Fatalf("expected 0 approved, got %d", len(approved))
 	}
+
+	rejected := j.GetRejected(pub.ID)
+	if rejected == nil || rejected.RejectedAt.IsZero() {
+		t.Fatalf("expected rejected paper to be archived, got %+v", rejected)
+	}
+	if accepted, rejectedCount, pendingCount := j.AcceptanceStats(); accepted != 0 || rejectedCount != 1 || pendingCount != 0 {
+		t.Errorf("expected stats 0/1/0, got %d/%d/%d", accepted, rejectedCount, pendingCount)
+	}
+}
+
+func TestJournal_RejectedArchivePersists(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJournal("Science", dir)
+	w := NewWorkflow(t.TempDir())
+
+	j.Submit(&types.Publication{ID: "sub-1", AuthorID: "agent-1", Title: "Weak"})
+	w.AddSubmission(&types.Submission{ID: "sub-1", AuthorID: "agent-1", Status: types.SubmissionPending})
+	w.AddReview(&types.PaperReview{SubmissionID: "sub-1", ReviewerID: "r1", ReviewerName: "Popper", Verdict: types.VerdictReject, Comments: "Not falsifiable"})
+
+	if _, err := w.Decide(j, "sub-1", types.VerdictReject, "r1", time.Now(), false); err != nil {
+		t.Fatalf("decide failed: %v", err)
+	}
+	if err := j.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded := NewJournal("", dir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	pub := loaded.GetRejected("sub-1")
+	if pub == nil {
+		t.Fatal("expected rejected paper after reload")
+	}
+	if !strings.Contains(pub.DecisionRationale, "Not falsifiable") {
+		t.Errorf("expected rationale to quote review comments, got %q", pub.DecisionRationale)
+	}
+	if w.GetSubmission("sub-1").DecisionRationale != pub.DecisionRationale {
+		t.Error("expected submission and archive to share the rationale")
+	}
 }
 
 func TestForum_Post(t *testing.T) {
@@ -214,6 +263,227 @@ func TestForum_Subreddits(t *testing.T) {
 	}
 }
 
+func TestForum_CreateSubreddit(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", dir)
+
+	if err := f.CreateSubreddit(&types.SubredditInfo{Name: "r/Quantum-Info", Description: "量子信息", CreatorID: "a1"}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if !f.HasSubreddit("quantum-info") {
+		t.Fatal("expected normalized subreddit to be registered")
+	}
+	if err := f.CreateSubreddit(&types.SubredditInfo{Name: "quantum-info"}); err == nil {
+		t.Error("expected duplicate subreddit to fail")
+	}
+	if err := f.CreateSubreddit(&types.SubredditInfo{Name: "physics"}); err == nil {
+		t.Error("expected built-in subreddit name to fail")
+	}
+	if err := f.CreateSubreddit(&types.SubredditInfo{Name: "bad name!"}); err == nil {
+		t.Error("expected invalid name to fail")
+	}
+
+	if stats := f.GetSubredditStats(); stats["quantum-info"] != 0 {
+		t.Errorf("expected empty custom subreddit in stats, got %v", stats)
+	} else if _, ok := stats["quantum-info"]; !ok {
+		t.Error("expected custom subreddit to appear in stats")
+	}
+
+	if err := f.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	loaded := NewForum("", dir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	subs := loaded.ListSubreddits()
+	last := subs[len(subs)-1]
+	if len(subs) != len(types.AllSubreddits())+1 || last.Name != "quantum-info" || last.CreatorID != "a1" {
+		t.Errorf("expected persisted custom subreddit last, got %+v", last)
+	}
+}
+
+func TestForum_Moderation(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", dir)
+
+	f.Post(&types.Publication{ID: "p1", AuthorID: "a1", Title: "Spam", Subreddit: types.SubGeneral})
+	f.Post(&types.Publication{ID: "p2", AuthorID: "a2", Title: "Fine", Subreddit: types.SubGeneral})
+
+	if err := f.Report(&types.PostReport{PostID: "p1", ReporterID: "a1", Reason: "mine"}); err == nil {
+		t.Error("expected self-report to fail")
+	}
+	if err := f.Report(&types.PostReport{PostID: "p1", ReporterID: "a2", Category: types.ReportSpam}); err == nil {
+		t.Error("expected report without reason to fail")
+	}
+	if err := f.Report(&types.PostReport{PostID: "p1", ReporterID: "a2", Category: types.ReportSpam, Reason: "ad"}); err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+	if err := f.Report(&types.PostReport{PostID: "p1", ReporterID: "a2", Category: types.ReportSpam, Reason: "again"}); err == nil {
+		t.Error("expected duplicate report to fail")
+	}
+	f.Report(&types.PostReport{PostID: "p1", ReporterID: "a3", Category: types.ReportDuplicate, Reason: "repost"})
+	if open := f.GetReports(true); len(open) != 2 {
+		t.Fatalf("expected 2 open reports, got %d", len(open))
+	}
+
+	if _, err := f.HidePost("p1", "m1", "Mod", ""); err == nil {
+		t.Error("expected moderation without reason to fail")
+	}
+	action, err := f.RemovePost("p1", "m1", "Mod", "advertising")
+	if err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if len(action.ReportIDs) != 2 || len(f.GetReports(true)) != 0 {
+		t.Errorf("expected removal to resolve both reports, got %v", action.ReportIDs)
+	}
+	if hot := f.GetHot(10); len(hot) != 1 || hot[0].ID != "p2" {
+		t.Errorf("expected removed post out of feeds, got %d posts", len(hot))
+	}
+	if err := f.Upvote("v1", "p1"); err == nil {
+		t.Error("expected vote on removed post to fail")
+	}
+	if err := f.Comment("p1", &types.Publication{AuthorID: "a2", Content: "x"}); err == nil {
+		t.Error("expected reply to removed post to fail")
+	}
+	if _, err := f.DismissReports("p2", "m1", "Mod", "nothing to do"); err == nil {
+		t.Error("expected dismiss without open reports to fail")
+	}
+
+	if err := f.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	loaded := NewForum("", dir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if p := loaded.Get("p1"); p.Moderation != types.ModerationRemoved || p.ModerationReason != "advertising" {
+		t.Errorf("expected persisted removal, got %q (%q)", p.Moderation, p.ModerationReason)
+	}
+	if log := loaded.GetModerationLog(); len(log) != 1 || len(loaded.GetReports(false)) != 2 {
+		t.Errorf("expected persisted log and reports, got %d actions", len(log))
+	}
+
+	if _, err := loaded.RestorePost("p1", "m1", "Mod", "appeal accepted"); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if len(loaded.GetHot(10)) != 2 {
+		t.Error("expected restored post back in feeds")
+	}
+}
+
+func TestForum_IndexSurvivesReload(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", dir)
+
+	f.Post(&types.Publication{ID: "p1", AuthorID: "a1", Title: "Root"})
+	f.Comment("p1", &types.Publication{ID: "c1", AuthorID: "a2", Content: "reply"})
+	f.Comment("c1", &types.Publication{ID: "c2", AuthorID: "a1", Content: "nested"})
+	f.Comment("c2", &types.Publication{ID: "c3", AuthorID: "a3", Content: "deeper"})
+
+	check := func(name string, f *Forum) {
+		if got := len(f.GetByAuthor("a1")); got != 2 {
+			t.Errorf("%s: expected 2 publications by a1, got %d", name, got)
+		}
+		if got := f.GetComments("c1"); len(got) != 1 || got[0].ID != "c2" {
+			t.Errorf("%s: expected c2 as only reply to c1, got %v", name, got)
+		}
+		if root := f.ResolveRootPostID("c3"); root != "p1" {
+			t.Errorf("%s: expected root p1 for c3, got %q", name, root)
+		}
+		if got := len(f.GetThreadComments("p1")); got != 3 {
+			t.Errorf("%s: expected 3 thread comments, got %d", name, got)
+		}
+	}
+	check("live", f)
+
+	if err := f.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	loaded := NewForum("", dir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	check("loaded", loaded)
+}
+
+func TestForum_IndexOrderSurvivesReload(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", dir)
+
+	// IDs sort differently from publish order, which the map in Posts
+	// doesn't keep either.
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.Post(&types.Publication{ID: "p9", AuthorID: "a1", Title: "First"})
+	f.Post(&types.Publication{ID: "p1", AuthorID: "a1", Title: "Second"})
+	f.Post(&types.Publication{ID: "p5", AuthorID: "a1", Title: "Third"})
+	f.Comment("p9", &types.Publication{ID: "c7", AuthorID: "a2", Content: "first reply"})
+	f.Comment("p9", &types.Publication{ID: "c2", AuthorID: "a2", Content: "second reply"})
+	for i, id := range []string{"p9", "p1", "p5", "c7", "c2"} {
+		f.Posts[id].PublishedAt = base.Add(time.Duration(i) * time.Minute)
+	}
+
+	check := func(name string, f *Forum) {
+		var ids []string
+		for _, p := range f.GetByAuthor("a1") {
+			ids = append(ids, p.ID)
+		}
+		if want := []string{"p9", "p1", "p5"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("%s: expected posts by a1 in publish order %v, got %v", name, want, ids)
+		}
+		if got, want := f.index.children["p9"], []string{"c7", "c2"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected replies to p9 in publish order %v, got %v", name, want, got)
+		}
+	}
+	check("live", f)
+
+	if err := f.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	for i := range 5 {
+		loaded := NewForum("", dir)
+		if err := loaded.Load(); err != nil {
+			t.Fatalf("load failed: %v", err)
+		}
+		check(fmt.Sprintf("loaded %d", i), loaded)
+	}
+}
+
+func TestRelatedDiscussions(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", dir)
+	w := NewWorkflow(dir)
+	j := NewJournal("Frontier", dir)
+
+	f.Post(&types.Publication{ID: "forum-src", AuthorName: "Ann", Title: "Origin", Content: "where it started"})
+	f.Post(&types.Publication{ID: "forum-cited", AuthorName: "Bob", Title: "Cited", Content: "unrelated words"})
+	f.Comment("forum-cited", &types.Publication{ID: "comment-1", AuthorID: "x", Content: "a reply"})
+	f.Post(&types.Publication{ID: "forum-sim", AuthorName: "Cy", Title: "量子纠缠与引力", Content: "量子纠缠可能产生时空几何"})
+	f.Post(&types.Publication{ID: "forum-off", AuthorName: "Di", Title: "Cooking", Content: "pasta recipes"})
+
+	draftID := w.CreateDraft(&types.Draft{Title: "Draft", SourcePostID: "forum-src"})
+	j.Submit(&types.Publication{ID: "journal-1", DraftID: draftID, Title: "时空几何起源于量子纠缠", Content: "See comment-1. 我们认为量子纠缠产生时空几何。"})
+	j.Approve("journal-1", "editor")
+
+	if n := j.LinkRelatedDiscussions(w, f, time.Now()); n != 1 {
+		t.Fatalf("expected 1 paper linked, got %d", n)
+	}
+	related := j.Get("journal-1").RelatedThreads
+	reasons := map[string]string{}
+	for _, r := range related {
+		reasons[r.PostID] = r.Reason
+	}
+	if reasons["forum-src"] != RelatedSource || reasons["forum-cited"] != RelatedCited || reasons["forum-sim"] != RelatedSimilar {
+		t.Errorf("unexpected related threads: %+v", related)
+	}
+	if _, ok := reasons["forum-off"]; ok {
+		t.Error("expected unrelated thread to be left out")
+	}
+	if n := j.LinkRelatedDiscussions(w, f, time.Now()); n != 0 {
+		t.Errorf("expected already-linked paper to be skipped, got %d", n)
+	}
+}
+
 func TestForum_HotPosts(t *testing.T) {
 	f := NewForum("Open Discussion", t.TempDir())
 
@@ -245,6 +515,66 @@ func TestForum_HotPosts(t *testing.T) {
 	}
 }
 
+func TestForum_RankedDecaysAndWindows(t *testing.T) {
+	f := NewForum("Open Discussion", "")
+	for _, id := range []string{"old", "fresh", "split", "week", "stale"} {
+		f.Post(&types.Publication{ID: id, AuthorID: "a-" + id, Title: id})
+	}
+	for i := 0; i < 9; i++ {
+		f.Upvote(fmt.Sprintf("u%d", i), "old")
+	}
+	for i := 0; i < 4; i++ {
+		f.Upvote(fmt.Sprintf("u%d", i), "split")
+		f.Downvote(fmt.Sprintf("d%d", i), "split")
+	}
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	f.Get("old").PublishedAt = now.Add(-72 * time.Hour)
+	f.Get("week").PublishedAt = now.Add(-3 * 24 * time.Hour).Add(time.Minute)
+	f.Get("stale").PublishedAt = now.Add(-8 * 24 * time.Hour)
+	f.Get("split").PublishedAt = now.Add(-time.Hour)
+	f.Get("fresh").PublishedAt = now
+
+	ids := func(posts []*types.Publication) string {
+		out := make([]string, len(posts))
+		for i, p := range posts {
+			out[i] = p.ID
+		}
+		return strings.Join(out, " ")
+	}
+
+	// A score of 10 three days ago loses to recent posts with a score of 1,
+	// but still beats one of the same age.
+	if got := ids(f.Ranked("", PostHot, WindowAll, 0)); got != "fresh split old week stale" {
+		t.Errorf("hot: got %s", got)
+	}
+	if got := ids(f.Ranked("", PostTop, WindowAll, 2)); got != "old fresh" {
+		t.Errorf("top all: got %s", got)
+	}
+	if got := ids(f.Ranked("", PostTop, WindowDay, 0)); got != "fresh split" {
+		t.Errorf("top day: got %s", got)
+	}
+	if got := ids(f.Ranked("", PostTop, WindowWeek, 0)); got != "old fresh split week" {
+		t.Errorf("top week: got %s", got)
+	}
+	if got := ids(f.Ranked("", PostControversial, WindowAll, 1)); got != "split" {
+		t.Errorf("controversial: got %s", got)
+	}
+	if got := ids(f.GetRecent(2)); got != "fresh split" {
+		t.Errorf("recent: got %s", got)
+	}
+
+	if _, err := ParsePostSort("best"); err == nil {
+		t.Error("expected error for unknown sort")
+	}
+	if order, _ := ParsePostSort("recent"); order != PostNew {
+		t.Errorf("expected recent to mean new, got %q", order)
+	}
+	if _, err := ParseTimeWindow("month"); err == nil {
+		t.Error("expected error for unknown window")
+	}
+}
+
 func TestJournal_Persistence(t *testing.T) {
 	tempDir := t.TempDir()
 	j := NewJournal("Science", tempDir)
@@ -268,6 +598,45 @@ func TestJournal_Persistence(t *testing.T) {
 	}
 }
 
+func TestJournalWorkflow_ConcurrentSaves(t *testing.T) {
+	tempDir := t.TempDir()
+	j := NewJournal("Science", tempDir)
+	w := NewWorkflow(tempDir)
+
+	// Each agent mutates and then saves; whichever save lands last must
+	// hold every change made before it.
+	const agents = 16
+	var wg sync.WaitGroup
+	for i := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("paper-%d", i)
+			j.Submit(&types.Publication{ID: id, AuthorID: "agent-1", Title: strings.Repeat("t", i*100)})
+			w.AddSubmission(&types.Submission{ID: id, AuthorID: "agent-1"})
+			if err := j.Save(); err != nil {
+				t.Errorf("journal save: %v", err)
+			}
+			if err := w.Save(); err != nil {
+				t.Errorf("workflow save: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	j2 := NewJournal("Science", tempDir)
+	if err := j2.Load(); err != nil {
+		t.Fatalf("load journal: %v", err)
+	}
+	w2 := NewWorkflow(tempDir)
+	if err := w2.Load(); err != nil {
+		t.Fatalf("load workflow: %v", err)
+	}
+	if len(j2.Pending) != agents || len(w2.Submissions) != agents {
+		t.Errorf("expected %d pending papers and submissions, got %d and %d", agents, len(j2.Pending), len(w2.Submissions))
+	}
+}
+
 func TestForum_Persistence(t *testing.T) {
 	tempDir := t.TempDir()
 	f := NewForum("Discussion", tempDir)
@@ -288,3 +657,1015 @@ func TestForum_Persistence(t *testing.T) {
 		t.Errorf("expected 2 posts after load, got %d", len(f2.Posts))
 	}
 }
+
+func TestForum_WALRecoversUnsavedChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	f := NewForum("Discussion", tempDir)
+
+	f.Post(&types.Publication{ID: "post-1", AuthorID: "agent-1", Title: "Post 1"})
+	if err := f.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, forumWALFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected the snapshot to compact the WAL, stat err=%v", err)
+	}
+
+	// Changes after the last snapshot live only in the WAL.
+	f.Post(&types.Publication{ID: "post-2", AuthorID: "agent-2", Title: "Post 2"})
+	if err := f.Comment("post-1", &types.Publication{ID: "c-1", AuthorID: "agent-2", Content: "Nice"}); err != nil {
+		t.Fatalf("comment failed: %v", err)
+	}
+	if err := f.Upvote("agent-3", "post-1"); err != nil {
+		t.Fatalf("upvote failed: %v", err)
+	}
+	// A crash mid-append leaves a torn last line.
+	wal, err := os.OpenFile(filepath.Join(tempDir, forumWALFile), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("open wal: %v", err)
+	}
+	wal.WriteString(`{"seq":99,"op":"po`)
+	wal.Close()
+
+	f2 := NewForum("Discussion", tempDir)
+	if err := f2.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(f2.Posts) != 3 || f2.Get("post-2") == nil || f2.Get("c-1") == nil {
+		t.Fatalf("expected posts and comment from the WAL, got %d posts", len(f2.Posts))
+	}
+	post := f2.Get("post-1")
+	if post.Comments != 1 || post.Upvotes != 2 || f2.Votes["agent-3:post-1"] == nil {
+		t.Fatalf("expected comment count and vote from the WAL, got %+v", post)
+	}
+	if got := f2.GetComments("post-1"); len(got) != 1 {
+		t.Fatalf("expected the replayed comment to be indexed, got %d", len(got))
+	}
+
+	// Withdrawing the vote is logged too, and survives a snapshot + reload.
+	if err := f2.Upvote("agent-3", "post-1"); err != nil {
+		t.Fatalf("upvote failed: %v", err)
+	}
+	if err := f2.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	f3 := NewForum("Discussion", tempDir)
+	if err := f3.Load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if f3.Get("post-1").Upvotes != 1 || f3.Votes["agent-3:post-1"] != nil || f3.WALSeq != f2.WALSeq {
+		t.Fatalf("expected the withdrawn vote after reload, got %+v (seq %d, want %d)", f3.Get("post-1"), f3.WALSeq, f2.WALSeq)
+	}
+}
+
+func TestForum_OnVote(t *testing.T) {
+	f := NewForum("Open Discussion", t.TempDir())
+	f.Post(&types.Publication{ID: "post-1", AuthorID: "agent-1", Title: "Hypothesis"})
+
+	var changes []types.VoteChange
+	f.OnVote(func(c types.VoteChange) { changes = append(changes, c) })
+
+	f.Upvote("agent-2", "post-1")
+	f.Downvote("agent-2", "post-1")
+	f.Downvote("agent-2", "post-1")
+
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 vote changes, got %d", len(changes))
+	}
+	want := []int{1, -2, 1}
+	for i, c := range changes {
+		if c.AuthorID != "agent-1" || c.VoterID != "agent-2" {
+			t.Errorf("change %d: unexpected ids %+v", i, c)
+		}
+		if c.ScoreDelta() != want[i] {
+			t.Errorf("change %d: expected delta %d, got %d", i, want[i], c.ScoreDelta())
+		}
+	}
+}
+
+func TestForum_SetClockStampsVoteChanges(t *testing.T) {
+	f := NewForum("Open Discussion", t.TempDir())
+	f.Post(&types.Publication{ID: "post-1", AuthorID: "agent-1", Title: "Hypothesis"})
+	simTime := time.Date(2031, 6, 1, 12, 0, 0, 0, time.UTC)
+	f.SetClock(func() time.Time { return simTime })
+
+	var changes []types.VoteChange
+	f.OnVote(func(c types.VoteChange) { changes = append(changes, c) })
+	if err := f.Upvote("agent-2", "post-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 1 || !changes[0].At.Equal(simTime) {
+		t.Fatalf("vote changes = %+v, want one at sim time %v", changes, simTime)
+	}
+	// Stored votes keep wall-clock time for TruncateAfter.
+	if v := f.Votes["agent-2:post-1"]; v == nil || v.VotedAt.Year() == simTime.Year() {
+		t.Errorf("stored vote = %+v, want a wall-clock VotedAt", v)
+	}
+}
+
+func TestWorkflow_ReviewCycle(t *testing.T) {
+	j := NewJournal("Science", t.TempDir())
+	w := NewWorkflow(t.TempDir())
+	w.SetScheduledReview(true)
+
+	for _, id := range []string{"sub-1", "sub-2"} {
+		j.Submit(&types.Publication{ID: id, AuthorID: "agent-1", Title: id})
+		w.AddSubmission(&types.Submission{ID: id, AuthorID: "agent-1", Title: id, Status: types.SubmissionPending})
+	}
+
+	cutoff := time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC)
+	due := cutoff.AddDate(0, 0, 7)
+	cycle := w.OpenReviewCycle(cutoff, due, []string{"agent-1", "reviewer-1", "reviewer-2"}, 2)
+	if cycle == nil || len(cycle.SubmissionIDs) != 2 {
+		t.Fatalf("expected cycle with 2 submissions, got %+v", cycle)
+	}
+	for _, id := range cycle.SubmissionIDs {
+		sub := w.GetSubmission(id)
+		if len(sub.AssignedReviewers) != 2 || containsID(sub.AssignedReviewers, "agent-1") {
+			t.Errorf("%s: expected 2 non-author reviewers, got %v", id, sub.AssignedReviewers)
+		}
+	}
+	if w.OpenReviewCycle(due, due.AddDate(0, 0, 7), []string{"reviewer-1"}, 2) != nil {
+		t.Error("expected no new cycle when nothing is pending")
+	}
+
+	// Fully review sub-1; sub-2 gets nothing.
+	for _, rid := range w.GetSubmission("sub-1").AssignedReviewers {
+		w.AddReview(&types.PaperReview{SubmissionID: "sub-1", ReviewerID: rid, Verdict: types.VerdictAccept})
+	}
+	if got := len(w.PendingAssignments("reviewer-1")); got != 1 {
+		t.Errorf("expected 1 pending assignment for reviewer-1, got %d", got)
+	}
+
+	complete, overdue := w.DueDecisions(cutoff.Add(time.Hour))
+	if len(complete) != 1 || complete[0].ID != "sub-1" || len(overdue) != 0 {
+		t.Fatalf("before deadline: expected sub-1 complete only, got %d complete %d overdue", len(complete), len(overdue))
+	}
+	if _, err := w.Decide(j, "sub-1", AggregateVerdict(w.GetReviews("sub-1")), EditorID, cutoff.Add(time.Hour), false); err != nil {
+		t.Fatalf("decide failed: %v", err)
+	}
+	if j.Get("sub-1") == nil {
+		t.Error("expected sub-1 to be published")
+	}
+
+	_, overdue = w.DueDecisions(due)
+	if len(overdue) != 1 || overdue[0].ID != "sub-2" {
+		t.Fatalf("at deadline: expected sub-2 overdue, got %d", len(overdue))
+	}
+	status, err := w.Decide(j, "sub-2", AggregateVerdict(w.GetReviews("sub-2")), EditorID, due, true)
+	if err != nil {
+		t.Fatalf("escalate failed: %v", err)
+	}
+	sub := w.GetSubmission("sub-2")
+	if status != types.SubmissionMajorRevision || !sub.Escalated || sub.DecidedBy != EditorID {
+		t.Errorf("expected escalated major revision by editor, got status=%s escalated=%v by=%s", status, sub.Escalated, sub.DecidedBy)
+	}
+}
+
+func TestWorkflow_EditorTriage(t *testing.T) {
+	j := NewJournal("Science", t.TempDir())
+	w := NewWorkflow(t.TempDir())
+	w.SetScheduledReview(true)
+
+	subs := map[string]string{
+		"sub-bio":   "Selection pressure in evolution of cooperation",
+		"sub-astro": "Horoscope accuracy survey",
+	}
+	for id, title := range subs {
+		j.Submit(&types.Publication{ID: id, AuthorID: "agent-1", Title: title})
+		w.AddSubmission(&types.Submission{ID: id, AuthorID: "agent-1", Title: title, Status: types.SubmissionPending})
+	}
+	if got := len(w.EditorQueue()); got != 2 {
+		t.Fatalf("expected 2 untriaged submissions, got %d", got)
+	}
+
+	reviewers := []*types.Persona{
+		{ID: "agent-1", Domains: []string{"evolution"}},
+		{ID: "reviewer-math", Domains: []string{"mathematics"}},
+		{ID: "reviewer-bio", Domains: []string{"evolution"}},
+	}
+	suggested := w.SuggestReviewers(w.GetSubmission("sub-bio"), reviewers, 2)
+	if len(suggested) != 2 || suggested[0].ID != "reviewer-bio" {
+		t.Fatalf("expected domain match first without the author, got %+v", suggested)
+	}
+
+	assignedAt := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+	if err := w.AssignReviewers("sub-bio", []string{"agent-1"}, "editor-1", assignedAt); err == nil {
+		t.Error("expected author assignment to fail")
+	}
+	if err := w.AssignReviewers("sub-bio", []string{"reviewer-bio"}, "editor-1", assignedAt); err != nil {
+		t.Fatalf("assign failed: %v", err)
+	}
+	if err := w.DeskReject(j, "sub-astro", "editor-1", "", assignedAt); err == nil {
+		t.Error("expected desk rejection without reason to fail")
+	}
+	if err := w.DeskReject(j, "sub-astro", "editor-1", "out of scope", assignedAt); err != nil {
+		t.Fatalf("desk reject failed: %v", err)
+	}
+	if sub := w.GetSubmission("sub-astro"); !sub.DeskRejected || sub.Status != types.SubmissionRejected || j.GetRejected("sub-astro") == nil {
+		t.Errorf("expected archived desk rejection, got %+v", sub)
+	}
+	if got := len(w.EditorQueue()); got != 0 {
+		t.Errorf("expected empty queue after triage, got %d", got)
+	}
+
+	// The cycle keeps the editor's reviewers.
+	cutoff := assignedAt.AddDate(0, 0, 1)
+	w.OpenReviewCycle(cutoff, cutoff.AddDate(0, 0, 7), []string{"reviewer-math"}, 2)
+	if got := w.GetSubmission("sub-bio").AssignedReviewers; len(got) != 1 || got[0] != "reviewer-bio" {
+		t.Fatalf("expected editor assignment kept, got %v", got)
+	}
+
+	nag := 3 * 24 * time.Hour
+	if got := w.OverdueReviews(assignedAt.Add(nag-time.Hour), nag); len(got) != 0 {
+		t.Errorf("expected nothing overdue yet, got %+v", got)
+	}
+	late := assignedAt.Add(nag)
+	if got := w.OverdueReviews(late, nag); len(got) != 1 || got[0].ReviewerID != "reviewer-bio" {
+		t.Fatalf("expected reviewer-bio overdue, got %+v", got)
+	}
+	if err := w.RemindReviewer("sub-bio", "reviewer-bio", "editor-1", "please review", late); err != nil {
+		t.Fatalf("remind failed: %v", err)
+	}
+	if got := w.OverdueReviews(late.Add(time.Hour), nag); len(got) != 0 {
+		t.Errorf("expected reminder to reset the nag clock, got %+v", got)
+	}
+	if r, ok := LastReminder(w.GetSubmission("sub-bio"), "reviewer-bio"); !ok || r.Note != "please review" {
+		t.Errorf("unexpected reminder: %+v", r)
+	}
+}
+
+func TestAggregateVerdict(t *testing.T) {
+	review := func(v types.PaperReviewVerdict) *types.PaperReview { return &types.PaperReview{Verdict: v} }
+	cases := []struct {
+		reviews []*types.PaperReview
+		want    types.PaperReviewVerdict
+	}{
+		{nil, types.VerdictMajorRevision},
+		{[]*types.PaperReview{review(types.VerdictAccept), review(types.VerdictAccept)}, types.VerdictAccept},
+		{[]*types.PaperReview{review(types.VerdictAccept), review(types.VerdictMinorRevision)}, types.VerdictMinorRevision},
+		{[]*types.PaperReview{review(types.VerdictAccept), review(types.VerdictReject)}, types.VerdictMajorRevision},
+	}
+	for i, c := range cases {
+		if got := AggregateVerdict(c.reviews); got != c.want {
+			t.Errorf("case %d: expected %s, got %s", i, c.want, got)
+		}
+	}
+}
+
+func TestForum_CommentTreeOrdersAndCollapses(t *testing.T) {
+	f := NewForum("Open Discussion", "")
+	f.Post(&types.Publication{ID: "p1", AuthorID: "a1", Title: "Root"})
+	f.Comment("p1", &types.Publication{ID: "c1", AuthorID: "a2"})
+	f.Comment("p1", &types.Publication{ID: "c2", AuthorID: "a3"})
+	f.Comment("c1", &types.Publication{ID: "c3", AuthorID: "a1"})
+	f.Comment("c1", &types.Publication{ID: "c4", AuthorID: "a3"})
+	f.Comment("c4", &types.Publication{ID: "c5", AuthorID: "a2"})
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"c1", "c2", "c3", "c4", "c5"} {
+		f.Get(id).PublishedAt = base.Add(time.Duration(i) * time.Minute)
+	}
+	f.Get("c2").Score = 5
+	f.Get("c4").Score = -4
+
+	ids := func(tree []ThreadComment) string {
+		out := make([]string, len(tree))
+		for i, c := range tree {
+			out[i] = fmt.Sprintf("%s@%d", c.ID, c.Depth)
+		}
+		return strings.Join(out, " ")
+	}
+	if got := ids(f.CommentTree("p1", SortTop)); got != "c2@1 c1@1 c3@2 c4@2 c5@3" {
+		t.Errorf("top: got %s", got)
+	}
+	if got := ids(f.CommentTree("p1", SortNew)); got != "c2@1 c1@1 c4@2 c5@3 c3@2" {
+		t.Errorf("new: got %s", got)
+	}
+	old := f.CommentTree("p1", SortOld)
+	if got := ids(old); got != "c1@1 c3@2 c4@2 c5@3 c2@1" {
+		t.Errorf("old: got %s", got)
+	}
+	if c1 := old[0]; c1.Replies != 2 || c1.Descendants != 3 || c1.Collapsed {
+		t.Errorf("unexpected c1 metadata: %+v", c1)
+	}
+	if c4 := old[2]; !c4.Collapsed || c4.Replies != 1 {
+		t.Errorf("expected c4 collapsed with one reply: %+v", c4)
+	}
+	if got := f.GetThreadComments("p1"); len(got) != 5 || got[0].ID != "c1" || got[1].ID != "c3" {
+		t.Errorf("expected thread comments in tree order, got %v", got)
+	}
+
+	// A moderated comment drops out; its reply keeps its depth.
+	if _, err := f.HidePost("c4", "m1", "Mod", "off topic"); err != nil {
+		t.Fatalf("hide failed: %v", err)
+	}
+	old = f.CommentTree("p1", SortOld)
+	if got := ids(old); got != "c1@1 c3@2 c5@3 c2@1" {
+		t.Errorf("after hide: got %s", got)
+	}
+	if old[0].Descendants != 2 {
+		t.Errorf("expected hidden comment excluded from descendants, got %d", old[0].Descendants)
+	}
+
+	if _, err := ParseCommentSort("best"); err == nil {
+		t.Error("expected error for unknown sort")
+	}
+	if order, _ := ParseCommentSort(""); order != SortTop {
+		t.Errorf("expected default sort top, got %q", order)
+	}
+}
+
+func TestForum_CivilityScoring(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", dir)
+	f.Post(&types.Publication{ID: "p1", AuthorID: "a1", Title: "Dark matter", Subreddit: types.SubPhysics})
+
+	f.Comment("p1", &types.Publication{ID: "c1", AuthorID: "a2", AuthorName: "Kind", Content: "Thanks, good point — perhaps the halo data agree?"})
+	f.Comment("p1", &types.Publication{ID: "c2", AuthorID: "a3", AuthorName: "Rude", Content: "What stupid nonsense, only an idiot would post this."})
+	f.Comment("p1", &types.Publication{ID: "c3", AuthorID: "a3", AuthorName: "Rude", Content: "这个推导有点荒谬"})
+
+	if c := f.Get("c1").Civility; c == nil || c.Score <= 0 {
+		t.Fatalf("expected civil comment to score positive, got %+v", c)
+	}
+	if c := f.Get("c2").Civility; c == nil || c.Score > -0.5 || len(c.Hostile) < 2 {
+		t.Fatalf("expected hostile comment, got %+v", c)
+	}
+	if c := f.Get("c3").Civility; c == nil || c.Score >= 0 {
+		t.Fatalf("expected Chinese hostility to score negative, got %+v", c)
+	}
+	open := f.GetReports(true)
+	if len(open) != 1 || open[0].PostID != "c2" || open[0].Category != types.ReportHostile {
+		t.Fatalf("expected only c2 flagged, got %+v", open)
+	}
+
+	report := f.CivilityReport()
+	if report.Overall.Comments != 3 || report.Overall.Flagged != 1 || report.Overall.Hostile != 2 {
+		t.Errorf("unexpected overall stats: %+v", report.Overall)
+	}
+	if rude := report.ByAgent["a3"]; rude == nil || rude.Comments != 2 || rude.Mean >= 0 {
+		t.Errorf("unexpected agent stats: %+v", rude)
+	}
+	if sub := report.BySubreddit[string(types.SubPhysics)]; sub == nil || sub.Comments != 3 || len(sub.Daily) != 1 {
+		t.Errorf("unexpected subreddit stats: %+v", sub)
+	}
+}
+
+func TestSearch_RanksTitleMatchesAndSkipsHidden(t *testing.T) {
+	pubs := []*types.Publication{
+		{ID: "a", Title: "Notes", Content: "a remark about entropy in passing"},
+		{ID: "b", Title: "Entropy and black holes", Content: "entropy bounds"},
+		{ID: "c", Title: "Entropy hidden", Content: "entropy", Moderation: types.ModerationHidden},
+		{ID: "d", Title: "Unrelated", Content: "nothing here"},
+		{ID: "e", Channel: types.ChannelJournal, Title: "黑洞熵", Content: "关于黑洞熵的推导"},
+	}
+	hits := Search("entropy", pubs)
+	if len(hits) != 2 || hits[0].Publication.ID != "b" || hits[1].Publication.ID != "a" {
+		t.Fatalf("unexpected hits: %+v", hits)
+	}
+	if hits[0].Kind != HitForumPost {
+		t.Fatalf("expected forum post kind, got %s", hits[0].Kind)
+	}
+	hits = Search("黑洞熵", pubs)
+	if len(hits) != 1 || hits[0].Kind != HitJournal {
+		t.Fatalf("expected one journal hit, got %+v", hits)
+	}
+}
+
+func TestJournal_RoutesAndAppliesThreshold(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJournal("Frontier", dir)
+	if got := j.JournalList(); len(got) != 1 || got[0].ID != types.DefaultJournalID || got[0].Name != "Frontier" {
+		t.Fatalf("expected implicit default journal, got %+v", got)
+	}
+
+	err := j.SetJournals([]*types.JournalInfo{
+		{ID: "general", Name: "Frontier"},
+		{ID: "phys", Name: "Physics Letters", Domains: []string{"physics", "quantum"}, AcceptanceThreshold: 7},
+		{ID: "math", Name: "Math Annals", Domains: []string{"mathematics", "geometry"}},
+	})
+	if err != nil {
+		t.Fatalf("set journals: %v", err)
+	}
+	if err := j.SetJournals([]*types.JournalInfo{{ID: "a", Name: "A"}, {ID: "a", Name: "B"}}); err == nil {
+		t.Fatal("expected duplicate id error")
+	}
+
+	route := func(requested, text string, domains ...string) string {
+		t.Helper()
+		info, err := j.Route(requested, text, domains)
+		if err != nil {
+			t.Fatalf("route %q: %v", requested, err)
+		}
+		return info.ID
+	}
+	if id := route("", "A new proof", "mathematics"); id != "math" {
+		t.Errorf("expected math by author domain, got %s", id)
+	}
+	if id := route("", "Quantum gravity and geometry", "physics"); id != "phys" {
+		t.Errorf("expected phys, got %s", id)
+	}
+	if id := route("", "Cooking pasta"); id != "general" {
+		t.Errorf("expected general fallback, got %s", id)
+	}
+	if id := route("Math Annals", "Quantum", "physics"); id != "math" {
+		t.Errorf("expected explicit journal by name, got %s", id)
+	}
+	if _, err := j.Route("nature", "", nil); err == nil {
+		t.Error("expected unknown journal error")
+	}
+
+	w := NewWorkflow(dir)
+	scores := types.PaperReviewScores{Novelty: 6, Rigor: 6, Falsifiability: 6, Reproducibility: 6, CrossDomain: 6}
+	for id, journalID := range map[string]string{"p-phys": "phys", "p-math": "math"} {
+		j.Submit(&types.Publication{ID: id, Title: id, JournalID: journalID})
+		w.AddSubmission(&types.Submission{ID: id, JournalID: journalID, Status: types.SubmissionPending})
+		w.AddReview(&types.PaperReview{SubmissionID: id, ReviewerID: "r1", Scores: scores, Verdict: types.VerdictAccept})
+	}
+
+	status, err := w.Decide(j, "p-phys", types.VerdictAccept, "r1", time.Now(), false)
+	if err != nil {
+		t.Fatalf("decide phys: %v", err)
+	}
+	if status != types.SubmissionMinorRevision || !strings.Contains(w.GetSubmission("p-phys").DecisionRationale, "acceptance threshold") {
+		t.Errorf("expected accept below threshold to become minor revision, got %s", status)
+	}
+	if status, err := w.Decide(j, "p-math", types.VerdictAccept, "r1", time.Now(), false); err != nil || status != types.SubmissionAccepted {
+		t.Errorf("expected math paper accepted, got %s (%v)", status, err)
+	}
+
+	counts := map[string]JournalSummary{}
+	for _, s := range j.JournalSummaries() {
+		counts[s.ID] = s
+	}
+	if counts["phys"].Pending != 1 || counts["math"].Accepted != 1 {
+		t.Errorf("unexpected journal counts: %+v", counts)
+	}
+	if got := j.FilterByJournal(j.GetApproved(), "math"); len(got) != 1 || got[0].ID != "p-math" {
+		t.Errorf("unexpected filtered papers: %+v", got)
+	}
+}
+
+func TestJournal_DoubleBlindUntilDecision(t *testing.T) {
+	j := NewJournal("Science", t.TempDir())
+	if err := j.SetJournals([]*types.JournalInfo{
+		{ID: "open", Name: "Open"},
+		{ID: "blind", Name: "Blind", DoubleBlind: true},
+	}); err != nil {
+		t.Fatalf("SetJournals: %v", err)
+	}
+	w := NewWorkflow(t.TempDir())
+	for _, id := range []string{"open", "blind"} {
+		j.Submit(&types.Publication{ID: "sub-" + id, AuthorID: "agent-1", AuthorName: "Darwin", JournalID: id})
+		w.AddSubmission(&types.Submission{ID: "sub-" + id, AuthorID: "agent-1", AuthorName: "Darwin", JournalID: id, Status: types.SubmissionPending})
+	}
+
+	if sub := w.GetSubmission("sub-open"); j.BlindSubmission(sub) != sub {
+		t.Fatal("single-blind submission should keep its author")
+	}
+	blind := j.BlindSubmission(w.GetSubmission("sub-blind"))
+	if blind.AuthorID != "" || blind.AuthorName != AnonymousAuthor || blind.History[0].Actor != "" {
+		t.Fatalf("expected anonymous submission, got %+v", blind)
+	}
+	if stored := w.GetSubmission("sub-blind"); stored.AuthorName != "Darwin" || stored.History[0].Actor != "agent-1" {
+		t.Fatal("blinding must not modify the stored submission")
+	}
+	for _, pub := range j.GetPending() {
+		got := j.BlindPending(pub)
+		if anonymous := got.AuthorName == AnonymousAuthor; anonymous != (pub.JournalID == "blind") {
+			t.Fatalf("unexpected pending view for %s: %+v", pub.ID, got)
+		}
+	}
+
+	if _, err := w.Decide(j, "sub-blind", types.VerdictAccept, "reviewer-1", time.Now(), false); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	sub := w.GetSubmission("sub-blind")
+	if j.BlindSubmission(sub) != sub {
+		t.Fatal("author should be revealed after the decision")
+	}
+	if pub := j.Get("sub-blind"); pub == nil || j.BlindPending(pub).AuthorName != "Darwin" {
+		t.Fatalf("published paper should name its author, got %+v", pub)
+	}
+}
+
+func TestAuditLog_RecordsMutations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, audit.FileName)
+	l := audit.NewLog(path)
+	simTime := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	l.SetClock(func() (time.Time, int) { return simTime, 7 })
+
+	f := NewForum("Forum", filepath.Join(dir, "forum"))
+	j := NewJournal("Science", filepath.Join(dir, "journal"))
+	w := NewWorkflow(filepath.Join(dir, "workflow"))
+	f.SetAuditLog(l)
+	j.SetAuditLog(l)
+	w.SetAuditLog(l)
+
+	f.Post(&types.Publication{ID: "post-1", AuthorID: "agent-1", Title: "Idea"})
+	if err := f.Upvote("agent-2", "post-1"); err != nil {
+		t.Fatalf("Upvote: %v", err)
+	}
+	j.Submit(&types.Publication{ID: "sub-1", AuthorID: "agent-1", Title: "Paper"})
+	w.AddSubmission(&types.Submission{ID: "sub-1", AuthorID: "agent-1", Status: types.SubmissionPending})
+	if _, err := w.Decide(j, "sub-1", types.VerdictReject, "reviewer-1", simTime, false); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	all, err := audit.Read(path, audit.Filter{})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(all) != 6 {
+		t.Fatalf("expected 6 entries, got %d: %+v", len(all), all)
+	}
+	if all[0].Op != "decide" || all[0].Actor != "reviewer-1" || all[0].Tick != 7 || !all[0].SimTime.Equal(simTime) {
+		t.Fatalf("expected newest entry to be the decision, got %+v", all[0])
+	}
+
+	votes, _ := audit.Read(path, audit.Filter{Store: audit.StoreForum, Actor: "agent-2"})
+	if len(votes) != 1 || votes[0].Op != "upvote" || votes[0].Before == "" || votes[0].Before == votes[0].After {
+		t.Fatalf("expected one upvote with changed hash, got %+v", votes)
+	}
+	later, _ := audit.Read(path, audit.Filter{Since: simTime.Add(time.Hour)})
+	if len(later) != 0 {
+		t.Fatalf("expected no entries after the sim clock, got %d", len(later))
+	}
+}
+
+func TestForum_TruncateAfter(t *testing.T) {
+	f := NewForum("Open Discussion", "")
+	f.Post(&types.Publication{ID: "old", AuthorID: "a1", Title: "old"})
+	f.Post(&types.Publication{ID: "new", AuthorID: "a2", Title: "new"})
+	f.Comment("old", &types.Publication{ID: "c-old", AuthorID: "a2"})
+	f.Comment("old", &types.Publication{ID: "c-new", AuthorID: "a3"})
+	f.Comment("c-new", &types.Publication{ID: "c-reply", AuthorID: "a1"})
+	f.Upvote("a2", "old")
+	f.Upvote("a3", "old")
+	f.Upvote("a1", "new")
+
+	cutoff := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, id := range []string{"old", "c-old"} {
+		f.Get(id).PublishedAt = cutoff.Add(-time.Hour)
+	}
+	f.Get("c-reply").PublishedAt = cutoff.Add(-time.Minute) // parent is dropped
+	f.Votes["a2:old"].VotedAt = cutoff
+
+	if n := f.TruncateAfter(cutoff); n != 3 {
+		t.Fatalf("expected 3 posts dropped, got %d", n)
+	}
+	if f.Get("new") != nil || f.Get("c-new") != nil || f.Get("c-reply") != nil {
+		t.Fatal("expected posts after the cutoff and their replies to be dropped")
+	}
+	old := f.Get("old")
+	// A new post starts with its author's upvote.
+	if old.Comments != 1 || old.Upvotes != 2 || old.Score != 2 {
+		t.Fatalf("expected 1 comment and 2 upvotes left, got %+v", old)
+	}
+	if len(f.Votes) != 1 || f.Votes["a2:old"] == nil {
+		t.Fatalf("expected only the vote before the cutoff, got %v", f.Votes)
+	}
+	if got := f.GetThreadComments("old"); len(got) != 1 || got[0].ID != "c-old" {
+		t.Fatalf("expected the index to be rebuilt, got %v", got)
+	}
+}
+
+func TestWorkflow_DisputesMarkPaperDisputed(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJournal("J", filepath.Join(dir, "journal"))
+	paper := &types.Publication{ID: "paper-1", AuthorID: "author", Title: "Claims"}
+	if err := j.Publish(paper); err != nil {
+		t.Fatal(err)
+	}
+	w := NewWorkflow(filepath.Join(dir, "workflow"))
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	dispute := func(challenger string) (int, error) {
+		return w.AddDispute(&types.Dispute{
+			TargetID: paper.ID, Channel: types.ChannelJournal, TargetAuthorID: paper.AuthorID,
+			ChallengerID: challenger, Claim: "X causes Y", CounterArguments: "Confounded by Z.", CreatedAt: at,
+		})
+	}
+
+	if _, err := dispute("author"); err == nil {
+		t.Fatalf("expected authors to be unable to dispute their own paper")
+	}
+	if n, err := dispute("a"); err != nil || n != 1 {
+		t.Fatalf("first dispute = %d, %v", n, err)
+	}
+	if _, err := dispute("a"); err == nil {
+		t.Fatalf("expected a second dispute by the same agent to fail")
+	}
+	n, err := dispute("b")
+	if err != nil || n != types.DisputeThreshold {
+		t.Fatalf("second dispute = %d, %v", n, err)
+	}
+	if marked, err := j.MarkDisputed(paper.ID, "b", at); err != nil || !marked {
+		t.Fatalf("MarkDisputed = %v, %v", marked, err)
+	}
+	if marked, _ := j.MarkDisputed(paper.ID, "b", at); marked {
+		t.Fatalf("expected the paper to be flagged once")
+	}
+	if got := j.Get(paper.ID); !got.Disputed || !got.DisputedAt.Equal(at) {
+		t.Fatalf("paper = %+v", got)
+	}
+
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewWorkflow(filepath.Join(dir, "workflow"))
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.DisputesOf(paper.ID); len(got) != 2 || got[0].Claim != "X causes Y" {
+		t.Fatalf("loaded disputes = %+v", got)
+	}
+}
+
+func TestJournal_RetractAfterQuorum(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJournal("J", filepath.Join(dir, "journal"))
+	paper := &types.Publication{ID: "paper-1", AuthorID: "author", Title: "Claims"}
+	if err := j.Publish(paper); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Submit(&types.Publication{ID: "paper-2", AuthorID: "author"}); err != nil {
+		t.Fatal(err)
+	}
+	var notified []string
+	j.OnRetract(func(pub *types.Publication) { notified = append(notified, pub.ID) })
+
+	w := NewWorkflow(filepath.Join(dir, "workflow"))
+	vote := func(voter string) (int, error) {
+		return w.VoteRetraction(paper.ID, paper.AuthorID, &types.RetractionVote{AgentID: voter, Reason: "Data fabricated."})
+	}
+	if _, err := vote("author"); err == nil {
+		t.Fatalf("expected authors to be unable to retract their own paper")
+	}
+	if n, err := vote("a"); err != nil || n != 1 {
+		t.Fatalf("first vote = %d, %v", n, err)
+	}
+	if _, err := vote("a"); err == nil {
+		t.Fatalf("expected a second vote by the same agent to fail")
+	}
+	if n, err := vote("b"); err != nil || n != types.RetractionQuorum {
+		t.Fatalf("second vote = %d, %v", n, err)
+	}
+
+	if err := j.Retract("paper-2", "pending", "b"); err == nil {
+		t.Fatalf("expected pending papers to be unretractable")
+	}
+	if err := j.Retract(paper.ID, "Data fabricated.", "b"); err != nil {
+		t.Fatalf("Retract: %v", err)
+	}
+	if err := j.Retract(paper.ID, "again", "b"); err == nil {
+		t.Fatalf("expected a paper to be retracted once")
+	}
+	got := j.Get(paper.ID)
+	if got == nil || !got.Retracted || got.RetractedBy != "b" || got.RetractionReason != "Data fabricated." {
+		t.Fatalf("paper = %+v", got)
+	}
+	if r := j.GetRetracted(); len(r) != 1 || r[0].ID != paper.ID {
+		t.Fatalf("GetRetracted = %+v", r)
+	}
+	if len(notified) != 1 {
+		t.Fatalf("listeners notified %v", notified)
+	}
+
+	w.MarkRetracted(paper.ID, got.RetractedAt)
+	if _, err := vote("c"); err == nil {
+		t.Fatalf("expected votes on a retracted paper to fail")
+	}
+}
+
+func TestForum_FlagsNearDuplicatePosts(t *testing.T) {
+	f := NewForum("F", t.TempDir())
+	original := &types.Publication{ID: "post-1", AuthorID: "a", Title: "Entropy bounds in small networks",
+		Content: "We show that the entropy of a small agent network is bounded by the log of its edge count, and test the bound on three toy graphs."}
+	if err := f.Post(original); err != nil {
+		t.Fatal(err)
+	}
+	repost := &types.Publication{ID: "post-2", AuthorID: "b", Title: "Entropy bounds in small networks!",
+		Content: "We show that the entropy of a small agent network is bounded by the log of its edge count, and we test the bound on three toy graphs."}
+	if err := f.Post(repost); err != nil {
+		t.Fatal(err)
+	}
+	unrelated := &types.Publication{ID: "post-3", AuthorID: "c", Title: "Protein folding heuristics",
+		Content: "A greedy heuristic for lattice protein folding finds low-energy states faster than simulated annealing on short chains."}
+	if err := f.Post(unrelated); err != nil {
+		t.Fatal(err)
+	}
+
+	if repost.DuplicateOf != "post-1" || repost.DuplicateScore < DuplicateThreshold {
+		t.Fatalf("repost = %q %.2f", repost.DuplicateOf, repost.DuplicateScore)
+	}
+	if original.DuplicateOf != "" || unrelated.DuplicateOf != "" {
+		t.Fatalf("false positives: %q, %q", original.DuplicateOf, unrelated.DuplicateOf)
+	}
+	reports := f.GetReports(true)
+	if len(reports) != 1 || reports[0].PostID != "post-2" || reports[0].Category != types.ReportDuplicate {
+		t.Fatalf("reports = %+v", reports)
+	}
+	if dups := f.GetDuplicates(); len(dups) != 1 || dups[0].ID != "post-2" {
+		t.Fatalf("duplicates = %+v", dups)
+	}
+}
+
+func TestForumCheck_DropsOrphansAndDanglingVotes(t *testing.T) {
+	f := NewForum("Open Discussion", t.TempDir())
+	if err := f.Post(&types.Publication{ID: "p1", AuthorID: "a", Title: "Kept", Content: "kept"}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if err := f.Comment("p1", &types.Publication{ID: "c1", AuthorID: "b", Content: "ok"}); err != nil {
+		t.Fatalf("Comment: %v", err)
+	}
+	// Records left behind by a lost post: a comment, a reply to it, and
+	// votes on both the post and the comment.
+	f.Posts["c2"] = &types.Publication{ID: "c2", AuthorID: "b", IsComment: true, ParentID: "gone"}
+	f.Posts["c3"] = &types.Publication{ID: "c3", AuthorID: "c", IsComment: true, ParentID: "c2"}
+	f.Votes["v:gone"] = &types.Vote{VoterID: "v", PostID: "gone", IsUpvote: true}
+	f.Votes["v:c3"] = &types.Vote{VoterID: "v", PostID: "c3", IsUpvote: true}
+	if err := f.Upvote("v", "p1"); err != nil {
+		t.Fatalf("Upvote: %v", err)
+	}
+
+	c := f.Check()
+	if len(c.OrphanedComments) != 2 || c.OrphanedComments[0].ID != "c2" || c.OrphanedComments[1].ID != "c3" {
+		t.Fatalf("orphans = %+v", c.OrphanedComments)
+	}
+	if len(c.DanglingVotes) != 2 || c.DanglingVotes["v:gone"] == nil || c.DanglingVotes["v:c3"] == nil {
+		t.Fatalf("dangling votes = %+v", c.DanglingVotes)
+	}
+
+	f.DropBroken(c)
+	if !f.Check().Empty() {
+		t.Fatalf("expected a clean forum after DropBroken, got %+v", f.Check())
+	}
+	if f.Get("c1") == nil || f.Get("c2") != nil || len(f.Votes) != 1 {
+		t.Fatalf("posts = %v, votes = %v", f.Posts, f.Votes)
+	}
+}
+
+func TestWorkflow_MissingSubmissions(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJournal("J", dir)
+	w := NewWorkflow(dir)
+	j.Submit(&types.Publication{ID: "s1", AuthorID: "a", Title: "Kept"})
+	w.AddSubmission(&types.Submission{ID: "s1", AuthorID: "a"})
+	w.AddSubmission(&types.Submission{ID: "s2", AuthorID: "a"})
+	w.AddReview(&types.PaperReview{SubmissionID: "s2", ReviewerID: "r", Verdict: types.VerdictAccept})
+
+	missing := w.MissingSubmissions(j)
+	if len(missing) != 1 || missing[0].ID != "s2" {
+		t.Fatalf("missing = %+v", missing)
+	}
+	reviews := w.DropSubmissions([]string{"s2"})
+	if len(reviews["s2"]) != 1 || w.GetSubmission("s2") != nil || len(w.GetReviews("s2")) != 0 {
+		t.Fatalf("reviews = %+v", reviews)
+	}
+}
+
+func TestTx_RollbackUndoesCommentAcrossStores(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", filepath.Join(dir, "forum"))
+	if err := f.Post(&types.Publication{ID: "p1", AuthorID: "a", Title: "Idea", Content: "idea"}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	// A regular file where the workflow directory should be makes Save fail.
+	blocked := filepath.Join(dir, "workflow")
+	if err := os.WriteFile(blocked, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	w := NewWorkflow(blocked)
+
+	var tx Tx
+	comment := &types.Publication{AuthorID: "b", Content: "[Consensus Request]"}
+	if err := f.Comment("p1", comment); err != nil {
+		t.Fatalf("Comment: %v", err)
+	}
+	tx.OnRollback(func() error { return f.Revert(comment.ID) })
+	id := w.AddConsensusRequest(&types.ConsensusRequest{PostID: "p1", CommentID: comment.ID, RequesterID: "b"})
+	tx.OnRollback(func() error { return w.RemoveConsensusRequest(id) })
+	saveErr := w.Save()
+	if saveErr == nil {
+		t.Fatal("expected workflow save to fail")
+	}
+	if err := tx.Rollback(saveErr); !errors.Is(err, saveErr) {
+		t.Fatalf("Rollback = %v, want the save error", err)
+	}
+
+	if f.Get(comment.ID) != nil || f.Get("p1").Comments != 0 || len(w.Consensus) != 0 {
+		t.Fatalf("rollback left comment=%v comments=%d consensus=%v", f.Get(comment.ID), f.Get("p1").Comments, w.Consensus)
+	}
+	// The revert reaches the write-ahead log, so a reload agrees.
+	reloaded := NewForum("", filepath.Join(dir, "forum"))
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if reloaded.Get(comment.ID) != nil || reloaded.Get("p1").Comments != 0 {
+		t.Fatalf("reloaded forum still has the comment: %+v", reloaded.Posts)
+	}
+}
+
+func TestForum_GetMentions(t *testing.T) {
+	dir := t.TempDir()
+	f := NewForum("Open Discussion", dir)
+
+	f.Post(&types.Publication{ID: "p1", AuthorID: "ada", Title: "Proofs"})
+	f.Post(&types.Publication{ID: "p2", AuthorID: "bob", Title: "Ping", Content: "cc @Ada"})
+	if err := f.Comment("p1", &types.Publication{ID: "c1", AuthorID: "bob", Content: "nice"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Comment("c1", &types.Publication{ID: "c2", AuthorID: "eve", Content: "@ada.l agreed", Mentions: []string{"ada.l"}}); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"p1", "p2", "c1", "c2"} {
+		f.Get(id).PublishedAt = base.Add(time.Duration(i) * time.Minute)
+	}
+	since := base.Add(2 * time.Minute)
+
+	check := func(f *Forum) {
+		t.Helper()
+		got := f.GetMentions("ada", time.Time{}, "Ada.L")
+		want := []struct{ id, reason, root string }{
+			{"c2", MentionReasonMention, "p1"},
+			{"c1", MentionReasonReply, "p1"},
+			{"p2", MentionReasonMention, "p2"},
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %d mentions, got %+v", len(want), got)
+		}
+		for i, w := range want {
+			if got[i].Pub.ID != w.id || got[i].Reason != w.reason || got[i].RootID != w.root {
+				t.Errorf("mention %d = %s/%s/%s, want %+v", i, got[i].Pub.ID, got[i].Reason, got[i].RootID, w)
+			}
+		}
+		if recent := f.GetMentions("ada", since, "ada.l"); len(recent) != 1 || recent[0].Pub.ID != "c2" {
+			t.Errorf("expected only c2 after since, got %+v", recent)
+		}
+	}
+	check(f)
+
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewForum("Open Discussion", dir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+}
+
+func TestWorkflow_CollaborativeDraft(t *testing.T) {
+	w := NewWorkflow(t.TempDir())
+	id := w.CreateDraft(&types.Draft{
+		Kind:    types.DraftCollaborative,
+		Status:  types.DraftOpen,
+		Title:   "Tides",
+		Content: "## Abstract\n\nShort.\n\n## Method\n\nStep one.\n\n### Detail\n\nNested.\n\n## References\n\nforum-1",
+		Authors: []string{"ada"},
+	})
+
+	if _, err := w.EditDraft(id, "bob", DraftAppend, "Method", "Step two."); err == nil {
+		t.Fatal("expected a non-author edit to fail")
+	}
+	if err := w.InviteCoauthor(id, "ada", "bob"); err != nil {
+		t.Fatalf("invite failed: %v", err)
+	}
+	if err := w.InviteCoauthor(id, "ada", "bob"); err == nil {
+		t.Error("expected a repeated invite to fail")
+	}
+	if _, err := w.EditDraft(id, "bob", DraftAppend, "method", "Step two."); err != nil {
+		t.Fatalf("append failed: %v", err)
+	}
+	d, err := w.EditDraft(id, "ada", DraftReplace, "Abstract", "Longer abstract.")
+	if err != nil {
+		t.Fatalf("replace failed: %v", err)
+	}
+	if _, err := w.EditDraft(id, "bob", DraftAppend, "Limitations", "Small sample."); err != nil {
+		t.Fatalf("new section failed: %v", err)
+	}
+
+	want := "## Abstract\n\nLonger abstract.\n\n## Method\n\nStep one.\n\n### Detail\n\nNested.\n\nStep two.\n\n## References\n\nforum-1\n\n## Limitations\n\nSmall sample."
+	if d.Content != want {
+		t.Errorf("content =\n%s\nwant\n%s", d.Content, want)
+	}
+	if got := DraftSections(d.Content); len(got) != 5 || got[4] != "Limitations" {
+		t.Errorf("unexpected sections %v", got)
+	}
+	chars := d.CharsByAuthor()
+	if chars["bob"] != len("Step two.")+len("Small sample.") || chars["ada"] == 0 || len(d.Contributions) != 4 {
+		t.Errorf("unexpected contributions %+v", d.Contributions)
+	}
+	if drafts := w.DraftsByAuthor("bob"); len(drafts) != 1 || drafts[0].ID != id {
+		t.Errorf("expected bob's draft list to hold %s, got %v", id, drafts)
+	}
+
+	d.Status = types.DraftLocked
+	if _, err := w.EditDraft(id, "ada", DraftAppend, "", "More."); err == nil {
+		t.Error("expected editing a locked draft to fail")
+	}
+}
+
+func TestWorkflow_VoteConsensus(t *testing.T) {
+	w := NewWorkflow(t.TempDir())
+	var achieved []string
+	w.OnConsensusAchieved(func(req *types.ConsensusRequest) { achieved = append(achieved, req.ID) })
+	id := w.AddConsensusRequest(&types.ConsensusRequest{
+		PostID: "p1", RequesterID: "ada", Status: types.ConsensusOpen, Supporters: []string{"ada"},
+	})
+
+	if _, err := w.VoteConsensus(id, "ada", false); err == nil {
+		t.Error("expected the requester's vote to fail")
+	}
+	if _, err := w.VoteConsensus(id, "bob", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.VoteConsensus(id, "eve", false); err != nil {
+		t.Fatal(err)
+	}
+	// Ada and Bob support, Eve opposes: two supporters are below the
+	// default minimum of three.
+	if req := w.GetConsensus(id); req.Status != types.ConsensusOpen || len(achieved) != 0 {
+		t.Fatalf("expected the request to stay open, got %s", req.Status)
+	}
+	// Eve switching sides makes 3 supporters and no opponents.
+	req, err := w.VoteConsensus(id, "eve", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Status != types.ConsensusAchieved || req.AchievedAt.IsZero() || len(req.Opponents) != 0 || len(req.Supporters) != 3 {
+		t.Fatalf("expected consensus achieved with 3 supporters, got %+v", req)
+	}
+	if _, err := w.VoteConsensus(id, "carl", true); err != nil {
+		t.Fatal(err)
+	}
+	if len(achieved) != 1 || achieved[0] != id {
+		t.Errorf("expected one achievement notice, got %v", achieved)
+	}
+	if h := w.GetConsensus(id).History; len(h) != 2 || h[0].To != "open" || h[0].Actor != "ada" ||
+		h[1].From != "open" || h[1].To != "achieved" || h[1].Actor != "eve" {
+		t.Errorf("unexpected consensus history: %+v", h)
+	}
+
+	strict := ConsensusThresholds{MinSupporters: 2, MinShare: 0.9}
+	if strict.Met(&types.ConsensusRequest{Supporters: []string{"a", "b", "c"}, Opponents: []string{"d"}}) {
+		t.Error("3 of 4 should miss a 0.9 share")
+	}
+}
+
+func TestWorkflow_SubmissionStateMachine(t *testing.T) {
+	j := NewJournal("Science", t.TempDir())
+	w := NewWorkflow(t.TempDir())
+	j.Submit(&types.Publication{ID: "sub-1", AuthorID: "agent-1", Title: "Sound"})
+	w.AddSubmission(&types.Submission{ID: "sub-1", AuthorID: "agent-1"})
+
+	decided := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := w.Decide(j, "sub-1", types.VerdictAccept, "r1", decided, false); err != nil {
+		t.Fatalf("decide: %v", err)
+	}
+	// Accepted is final: neither a second decision nor a status update may
+	// move the paper back, and the journal keeps it published.
+	if _, err := w.Decide(j, "sub-1", types.VerdictReject, "r2", decided, false); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("expected an illegal transition, got %v", err)
+	}
+	if j.Get("sub-1") == nil {
+		t.Fatal("refused decision must not move the paper out of the journal")
+	}
+	if err := w.UpdateSubmissionStatus("sub-1", types.SubmissionPending, "r2"); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("expected accepted → pending to fail, got %v", err)
+	}
+
+	sub := w.GetSubmission("sub-1")
+	if sub.Status != types.SubmissionAccepted || len(sub.History) != 2 {
+		t.Fatalf("unexpected submission: %s %+v", sub.Status, sub.History)
+	}
+	if h := sub.History[0]; h.From != "" || h.To != "pending" || h.Actor != "agent-1" {
+		t.Errorf("unexpected initial transition: %+v", h)
+	}
+	if h := sub.History[1]; h.From != "pending" || h.To != "accepted" || h.Actor != "r1" || !h.At.Equal(decided) {
+		t.Errorf("unexpected decision transition: %+v", h)
+	}
+
+	// A revision decision can return to review.
+	w.AddSubmission(&types.Submission{ID: "sub-2", AuthorID: "agent-1", Status: types.SubmissionPending})
+	if _, err := w.Decide(nil, "sub-2", types.VerdictMinorRevision, "r1", decided, false); err != nil {
+		t.Fatalf("decide revision: %v", err)
+	}
+	if err := w.UpdateSubmissionStatus("sub-2", types.SubmissionPending, "agent-1"); err != nil {
+		t.Fatalf("revision → pending: %v", err)
+	}
+
+	// Consensus requests start open; closed is final.
+	w.AddConsensusRequest(&types.ConsensusRequest{ID: "consensus-1", PostID: "post-1", RequesterID: "ada"})
+	if got := w.ConsensusForPost("post-1"); len(got) != 1 || got[0].Status != types.ConsensusOpen || len(got[0].History) != 1 {
+		t.Fatalf("unexpected consensus requests: %+v", got)
+	}
+	if !CanTransitionConsensus(types.ConsensusOpen, types.ConsensusAchieved) || CanTransitionConsensus(types.ConsensusClosed, types.ConsensusOpen) {
+		t.Error("unexpected consensus transitions")
+	}
+}
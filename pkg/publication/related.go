@@ -0,0 +1,194 @@
+package publication
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Reasons a paper is linked to a forum thread.
+const (
+	RelatedSource    = "source"    // the draft was created from this thread
+	RelatedConsensus = "consensus" // consensus for the draft was built here
+	RelatedCited     = "cited"     // the paper text references the thread
+	RelatedSimilar   = "similar"   // the thread discusses similar ideas
+)
+
+const (
+	maxRelatedThreads = 8
+	// minRelatedSimilarity is the cosine similarity a thread needs to be
+	// linked on text alone.
+	minRelatedSimilarity = 0.15
+)
+
+// RelatedDiscussions finds the forum threads behind a paper: the draft's
+// source and consensus threads, threads the text cites, then threads with
+// similar wording. workflow may be nil.
+func RelatedDiscussions(pub *types.Publication, workflow *Workflow, forum *Forum) []types.RelatedThread {
+	if pub == nil || forum == nil {
+		return nil
+	}
+	out := make([]types.RelatedThread, 0)
+	seen := make(map[string]bool)
+	add := func(postID, reason string, score float64) {
+		rootID := forum.ResolveRootPostID(postID)
+		if rootID == "" || seen[rootID] {
+			return
+		}
+		root := forum.Get(rootID)
+		if root == nil || root.Moderation == types.ModerationRemoved {
+			return
+		}
+		seen[rootID] = true
+		out = append(out, types.RelatedThread{
+			PostID:     root.ID,
+			Title:      root.Title,
+			AuthorName: root.AuthorName,
+			Subreddit:  root.Subreddit,
+			Reason:     reason,
+			Score:      math.Round(score*1000) / 1000,
+		})
+	}
+
+	if workflow != nil && pub.DraftID != "" {
+		if draft := workflow.GetDraft(pub.DraftID); draft != nil {
+			if draft.SourcePostID != "" {
+				add(draft.SourcePostID, RelatedSource, 1)
+			}
+			if draft.ConsensusID != "" {
+				if req := workflow.GetConsensus(draft.ConsensusID); req != nil {
+					add(req.PostID, RelatedConsensus, 1)
+				}
+			}
+		}
+	}
+	for _, id := range forumIDPattern.FindAllString(pub.Abstract+"\n"+pub.Content, -1) {
+		add(id, RelatedCited, 1)
+	}
+
+	paperVec := termVector(pub.Title + "\n" + pub.Abstract + "\n" + pub.Content)
+	type candidate struct {
+		id    string
+		score float64
+	}
+	similar := make([]candidate, 0)
+	for _, post := range forum.AllPosts() {
+		if seen[post.ID] || !post.Visible() {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString(post.Title + "\n" + post.Content)
+		for _, c := range forum.GetThreadComments(post.ID) {
+			b.WriteString("\n" + c.Content)
+		}
+		if score := cosine(paperVec, termVector(b.String())); score >= minRelatedSimilarity {
+			similar = append(similar, candidate{id: post.ID, score: score})
+		}
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].score == similar[j].score {
+			return similar[i].id < similar[j].id
+		}
+		return similar[i].score > similar[j].score
+	})
+	for _, c := range similar {
+		if len(out) >= maxRelatedThreads {
+			break
+		}
+		add(c.id, RelatedSimilar, c.score)
+	}
+
+	if len(out) > maxRelatedThreads {
+		out = out[:maxRelatedThreads]
+	}
+	return out
+}
+
+// LinkRelatedDiscussions computes and stores related threads for approved
+// papers that don't have them yet. It returns how many papers were linked.
+func (j *Journal) LinkRelatedDiscussions(workflow *Workflow, forum *Forum, now time.Time) int {
+	j.mu.RLock()
+	todo := make([]*types.Publication, 0)
+	for _, pub := range j.Publications {
+		if pub.RelatedAt.IsZero() {
+			todo = append(todo, pub)
+		}
+	}
+	j.mu.RUnlock()
+
+	for _, pub := range todo {
+		related := RelatedDiscussions(pub, workflow, forum)
+		j.mu.Lock()
+		pub.RelatedThreads = related
+		pub.RelatedAt = now
+		j.mu.Unlock()
+	}
+	return len(todo)
+}
+
+// forumIDPattern matches forum post/comment IDs cited in paper text.
+var forumIDPattern = regexp.MustCompile(`\b(?:forum|comment|seed)-[0-9A-Za-z_]+(?:-[0-9A-Za-z_]+)*`)
+
+var relatedStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true, "with": true,
+	"are": true, "was": true, "from": true, "not": true, "but": true, "can": true,
+	"its": true, "our": true, "which": true, "into": true, "has": true, "have": true,
+	"their": true, "they": true, "these": true, "than": true, "then": true, "also": true,
+}
+
+// termVector builds term frequencies from lowercase words (3+ letters) and,
+// for Chinese text, character bigrams.
+func termVector(text string) map[string]float64 {
+	tf := make(map[string]float64)
+	var word []rune
+	var prevHan rune
+	flush := func() {
+		if len(word) >= 3 {
+			if w := string(word); !relatedStopwords[w] {
+				tf[w]++
+			}
+		}
+		word = word[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if prevHan != 0 {
+				tf[string([]rune{prevHan, r})]++
+			}
+			prevHan = r
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			prevHan = 0
+			word = append(word, r)
+		default:
+			flush()
+			prevHan = 0
+		}
+	}
+	flush()
+	return tf
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	dot, na, nb := 0.0, 0.0, 0.0
+	for k, v := range a {
+		dot += v * b[k]
+		na += v * v
+	}
+	for _, v := range b {
+		nb += v * v
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
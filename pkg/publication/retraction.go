@@ -0,0 +1,134 @@
+package publication
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// VoteRetraction records a call to retract a published paper and returns how
+// many agents now support the retraction. Each agent may vote once per paper;
+// authors cannot vote on their own papers.
+func (w *Workflow) VoteRetraction(paperID, authorID string, vote *types.RetractionVote) (int, error) {
+	if paperID == "" || vote.AgentID == "" {
+		return 0, errors.New("retraction vote needs a paper and a voter")
+	}
+	if strings.TrimSpace(vote.Reason) == "" {
+		return 0, errors.New("a retraction reason is required")
+	}
+	if vote.AgentID == authorID {
+		return 0, errors.New("cannot vote to retract your own paper")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.Retractions == nil {
+		w.Retractions = make(map[string]*types.RetractionRequest)
+	}
+	req, ok := w.Retractions[paperID]
+	if !ok {
+		req = &types.RetractionRequest{PaperID: paperID}
+		w.Retractions[paperID] = req
+	}
+	if !req.RetractedAt.IsZero() {
+		return 0, fmt.Errorf("paper already retracted: %s", paperID)
+	}
+	for _, v := range req.Votes {
+		if v.AgentID == vote.AgentID {
+			return 0, fmt.Errorf("already voted to retract %s", paperID)
+		}
+	}
+	if vote.CreatedAt.IsZero() {
+		vote.CreatedAt = time.Now()
+	}
+	before := w.audit.Hash(req)
+	req.Votes = append(req.Votes, vote)
+	recordChange(w.audit, audit.StoreWorkflow, "retraction_vote", vote.AgentID, paperID, before, req)
+	return len(req.Votes), nil
+}
+
+// MarkRetracted closes a retraction request once the paper is retracted.
+func (w *Workflow) MarkRetracted(paperID string, at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if req, ok := w.Retractions[paperID]; ok && req.RetractedAt.IsZero() {
+		req.RetractedAt = at
+	}
+}
+
+// RetractionOf returns the retraction request for a paper, if any.
+func (w *Workflow) RetractionOf(paperID string) *types.RetractionRequest {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.Retractions[paperID]
+}
+
+// Retract withdraws a published paper. Retracted papers stay in the journal
+// with a retraction notice; pending and rejected papers cannot be retracted.
+func (j *Journal) Retract(pubID, reason, initiator string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a retraction reason is required")
+	}
+	pub, listeners, err := j.retract(pubID, reason, initiator)
+	if err != nil {
+		return err
+	}
+	for _, fn := range listeners {
+		fn(pub)
+	}
+	return nil
+}
+
+func (j *Journal) retract(pubID, reason, initiator string) (*types.Publication, []func(*types.Publication), error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pub, ok := j.Publications[pubID]
+	if !ok || !pub.Approved {
+		return nil, nil, fmt.Errorf("published paper not found: %s", pubID)
+	}
+	if pub.Retracted {
+		return nil, nil, fmt.Errorf("paper already retracted: %s", pubID)
+	}
+	before := j.audit.Hash(pub)
+	pub.Retracted = true
+	pub.RetractedAt = time.Now()
+	pub.RetractionReason = reason
+	pub.RetractedBy = initiator
+	recordChange(j.audit, audit.StoreJournal, "retract", initiator, pubID, before, pub)
+	return pub, append([]func(*types.Publication){}, j.retractListeners...), nil
+}
+
+// OnRetract registers a listener invoked after a paper is retracted.
+// Listeners run outside the journal lock and may call back into the journal.
+func (j *Journal) OnRetract(fn func(*types.Publication)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.retractListeners = append(j.retractListeners, fn)
+}
+
+// GetRetracted returns the retracted papers, most recently retracted first.
+func (j *Journal) GetRetracted() []*types.Publication {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var out []*types.Publication
+	for _, pub := range j.Publications {
+		if pub.Retracted {
+			out = append(out, pub)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].RetractedAt.Equal(out[b].RetractedAt) {
+			return out[a].ID < out[b].ID
+		}
+		return out[a].RetractedAt.After(out[b].RetractedAt)
+	})
+	return out
+}
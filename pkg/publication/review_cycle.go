@@ -0,0 +1,298 @@
+package publication
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// EditorID is recorded as the decider for review-cycle decisions.
+const EditorID = "editor"
+
+// SetScheduledReview enables review cycles: reviews are only recorded and the
+// decision is made by the scheduler instead of the reviewing agent.
+func (w *Workflow) SetScheduledReview(enabled bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.scheduledReview = enabled
+}
+
+// ScheduledReview reports whether review decisions are deferred to cycles.
+func (w *Workflow) ScheduledReview() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.scheduledReview
+}
+
+// LastReviewCutoff returns the cutoff of the most recent cycle (zero if none).
+func (w *Workflow) LastReviewCutoff() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if len(w.Cycles) == 0 {
+		return time.Time{}
+	}
+	return w.Cycles[len(w.Cycles)-1].Cutoff
+}
+
+// OpenReviewCycle batches every pending submission not yet in a cycle,
+// assigns up to perPaper reviewers to each (never the author) unless an
+// editor already did, and sets the decision deadline. It returns nil when
+// there is nothing to batch.
+func (w *Workflow) OpenReviewCycle(cutoff, due time.Time, reviewers []string, perPaper int) *types.ReviewCycle {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	batch := make([]*types.Submission, 0)
+	for _, sub := range w.Submissions {
+		if sub.Status == types.SubmissionPending && sub.CycleID == "" {
+			batch = append(batch, sub)
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	sort.Slice(batch, func(i, j int) bool {
+		if batch[i].CreatedAt.Equal(batch[j].CreatedAt) {
+			return batch[i].ID < batch[j].ID
+		}
+		return batch[i].CreatedAt.Before(batch[j].CreatedAt)
+	})
+
+	cycle := &types.ReviewCycle{
+		ID:          fmt.Sprintf("cycle-%s", cutoff.UTC().Format("20060102T1504")),
+		Cutoff:      cutoff,
+		DecisionDue: due,
+	}
+	next := 0
+	for _, sub := range batch {
+		sub.CycleID = cycle.ID
+		sub.DecisionDue = due
+		sub.UpdatedAt = time.Now()
+		cycle.SubmissionIDs = append(cycle.SubmissionIDs, sub.ID)
+		if sub.AssignedBy != "" && len(sub.AssignedReviewers) > 0 {
+			continue
+		}
+		sub.AssignedReviewers = nil
+		sub.AssignedAt = cutoff
+		// Round-robin across the batch so load spreads evenly.
+		for tries := 0; tries < len(reviewers) && len(sub.AssignedReviewers) < perPaper; tries++ {
+			id := reviewers[next%len(reviewers)]
+			next++
+			if id == sub.AuthorID || containsID(sub.AssignedReviewers, id) {
+				continue
+			}
+			sub.AssignedReviewers = append(sub.AssignedReviewers, id)
+		}
+	}
+	w.Cycles = append(w.Cycles, cycle)
+	recordChange(w.audit, audit.StoreWorkflow, "open_review_cycle", "", cycle.ID, "", cycle)
+	return cycle
+}
+
+// PendingAssignments returns submissions assigned to reviewerID, by a review
+// cycle or an editor, that are undecided and not yet reviewed by them.
+func (w *Workflow) PendingAssignments(reviewerID string) []*types.Submission {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	out := make([]*types.Submission, 0)
+	for _, sub := range w.Submissions {
+		if sub.Status != types.SubmissionPending || !sub.DecidedAt.IsZero() {
+			continue
+		}
+		if !containsID(sub.AssignedReviewers, reviewerID) {
+			continue
+		}
+		if w.hasReviewLocked(sub.ID, reviewerID) {
+			continue
+		}
+		out = append(out, sub)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// DueDecisions returns undecided cycle submissions that can be decided at now:
+// those whose assigned reviews are all in (complete) and those past their
+// deadline (overdue, to be escalated to the editor).
+func (w *Workflow) DueDecisions(now time.Time) (complete, overdue []*types.Submission) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, sub := range w.Submissions {
+		if sub.CycleID == "" || !sub.DecidedAt.IsZero() || sub.Status != types.SubmissionPending {
+			continue
+		}
+		done := len(sub.AssignedReviewers) > 0
+		for _, id := range sub.AssignedReviewers {
+			if !w.hasReviewLocked(sub.ID, id) {
+				done = false
+				break
+			}
+		}
+		switch {
+		case done:
+			complete = append(complete, sub)
+		case !now.Before(sub.DecisionDue):
+			overdue = append(overdue, sub)
+		}
+	}
+	sort.Slice(complete, func(i, j int) bool { return complete[i].ID < complete[j].ID })
+	sort.Slice(overdue, func(i, j int) bool { return overdue[i].ID < overdue[j].ID })
+	return complete, overdue
+}
+
+// GetReviews returns a copy of the reviews for a submission.
+func (w *Workflow) GetReviews(submissionID string) []*types.PaperReview {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]*types.PaperReview(nil), w.Reviews[submissionID]...)
+}
+
+// Decide applies a verdict to a submission and the journal.
+func (w *Workflow) Decide(journal *Journal, submissionID string, verdict types.PaperReviewVerdict, decidedBy string, at time.Time, escalated bool) (types.SubmissionStatus, error) {
+	reviews := w.GetReviews(submissionID)
+	rationale := DecisionRationale(reviews, verdict, escalated)
+	if verdict == types.VerdictAccept && journal != nil {
+		// The target journal's bar applies on top of the reviewers' verdicts.
+		info, _ := journal.JournalInfo(w.submissionJournal(journal, submissionID))
+		if mean, ok := meanReviewScore(reviews); ok && info.AcceptanceThreshold > 0 && mean < info.AcceptanceThreshold {
+			verdict = types.VerdictMinorRevision
+			rationale = DecisionRationale(reviews, verdict, escalated) +
+				fmt.Sprintf("\nMean review score %.1f is below %s's acceptance threshold %.1f.", mean, info.Name, info.AcceptanceThreshold)
+		}
+	}
+	status, err := VerdictStatus(verdict)
+	if err != nil {
+		return "", err
+	}
+	// Check the transition before the journal moves the paper.
+	w.mu.RLock()
+	if sub, ok := w.Submissions[submissionID]; ok {
+		if err := checkSubmissionTransition(sub, status); err != nil {
+			w.mu.RUnlock()
+			return "", err
+		}
+	}
+	w.mu.RUnlock()
+	if journal != nil {
+		switch status {
+		case types.SubmissionAccepted:
+			if err := journal.Approve(submissionID, decidedBy); err != nil {
+				return "", err
+			}
+		case types.SubmissionRejected:
+			if err := journal.RejectWithReason(submissionID, decidedBy, rationale); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	w.mu.Lock()
+	sub, ok := w.Submissions[submissionID]
+	if !ok {
+		w.mu.Unlock()
+		return status, nil
+	}
+	before := w.audit.Hash(sub)
+	if err := transitionSubmission(sub, status, decidedBy, at); err != nil {
+		w.mu.Unlock()
+		return "", err
+	}
+	sub.DecidedAt = at
+	sub.DecidedBy = decidedBy
+	sub.Escalated = escalated
+	sub.DecisionRationale = rationale
+	sub.UpdatedAt = time.Now()
+	recordChange(w.audit, audit.StoreWorkflow, "decide", decidedBy, submissionID, before, sub)
+	listeners := append([]func(*types.Submission){}, w.decisionListeners...)
+	w.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(sub)
+	}
+	return status, nil
+}
+
+// submissionJournal returns the journal a submission targets.
+func (w *Workflow) submissionJournal(journal *Journal, submissionID string) string {
+	if sub := w.GetSubmission(submissionID); sub != nil && sub.JournalID != "" {
+		return sub.JournalID
+	}
+	journal.mu.RLock()
+	pub := journal.Pending[submissionID]
+	journal.mu.RUnlock()
+	return journal.JournalOf(pub)
+}
+
+// AggregateVerdict combines reviews into one decision: the mean verdict,
+// rounded toward the stricter outcome. Without reviews the paper is sent
+// back for major revision.
+func AggregateVerdict(reviews []*types.PaperReview) types.PaperReviewVerdict {
+	ranks := []types.PaperReviewVerdict{
+		types.VerdictReject,
+		types.VerdictMajorRevision,
+		types.VerdictMinorRevision,
+		types.VerdictAccept,
+	}
+	sum, n := 0, 0
+	for _, r := range reviews {
+		for i, v := range ranks {
+			if r != nil && r.Verdict == v {
+				sum += i
+				n++
+				break
+			}
+		}
+	}
+	if n == 0 {
+		return types.VerdictMajorRevision
+	}
+	return ranks[sum/n]
+}
+
+// DecisionRationale summarizes why a verdict was reached from its reviews.
+func DecisionRationale(reviews []*types.PaperReview, verdict types.PaperReviewVerdict, escalated bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Decision: %s based on %d review(s)", verdict, len(reviews))
+	if escalated {
+		b.WriteString(", decided by the editor after the review deadline")
+	}
+	b.WriteString(".")
+	for _, r := range reviews {
+		if r == nil {
+			continue
+		}
+		name := r.ReviewerName
+		if name == "" {
+			name = r.ReviewerID
+		}
+		fmt.Fprintf(&b, "\n- %s (%s)", name, r.Verdict)
+		if c := strings.TrimSpace(r.Comments); c != "" {
+			fmt.Fprintf(&b, ": %s", c)
+		}
+	}
+	return b.String()
+}
+
+func (w *Workflow) hasReviewLocked(submissionID, reviewerID string) bool {
+	for _, r := range w.Reviews[submissionID] {
+		if r.ReviewerID == reviewerID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(items []string, id string) bool {
+	for _, item := range items {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
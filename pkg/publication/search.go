@@ -0,0 +1,72 @@
+package publication
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Kinds of search hits.
+const (
+	HitForumPost    = "forum_post"
+	HitForumComment = "forum_comment"
+	HitJournal      = "journal"
+)
+
+// SearchHit is a publication matching a text query.
+type SearchHit struct {
+	Kind        string             `json:"kind"`
+	Score       float64            `json:"score"`
+	Publication *types.Publication `json:"publication"`
+}
+
+// Search ranks visible publications by how many query terms they contain,
+// favoring matches in the title. Hits are returned best first.
+func Search(query string, pubs []*types.Publication) []SearchHit {
+	q := termVector(query)
+	if len(q) == 0 {
+		return []SearchHit{}
+	}
+	hits := make([]SearchHit, 0)
+	for _, pub := range pubs {
+		if pub == nil || !pub.Visible() {
+			continue
+		}
+		body := termVector(pub.Title + "\n" + pub.Abstract + "\n" + pub.Content)
+		coverage := 0.0
+		for term := range q {
+			if body[term] > 0 {
+				coverage++
+			}
+		}
+		if coverage == 0 {
+			continue
+		}
+		coverage /= float64(len(q))
+		score := 0.7*coverage + 0.3*cosine(q, termVector(pub.Title))
+		hits = append(hits, SearchHit{
+			Kind:        hitKind(pub),
+			Score:       math.Round(score*1000) / 1000,
+			Publication: pub,
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score == hits[j].Score {
+			return hits[i].Publication.PublishedAt.After(hits[j].Publication.PublishedAt)
+		}
+		return hits[i].Score > hits[j].Score
+	})
+	return hits
+}
+
+func hitKind(pub *types.Publication) string {
+	switch {
+	case pub.Channel == types.ChannelJournal:
+		return HitJournal
+	case pub.IsComment:
+		return HitForumComment
+	default:
+		return HitForumPost
+	}
+}
@@ -0,0 +1,106 @@
+package publication
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ErrIllegalTransition is returned for a status change the submission or
+// consensus state machine does not allow.
+var ErrIllegalTransition = errors.New("illegal status transition")
+
+// submissionTransitions is the submission state machine. A revision
+// decision can be followed by a revised version under review again or by a
+// final decision; accepted and rejected are final.
+var submissionTransitions = map[types.SubmissionStatus][]types.SubmissionStatus{
+	types.SubmissionPending: {
+		types.SubmissionMinorRevision, types.SubmissionMajorRevision,
+		types.SubmissionAccepted, types.SubmissionRejected,
+	},
+	types.SubmissionMinorRevision: {types.SubmissionPending, types.SubmissionAccepted, types.SubmissionRejected},
+	types.SubmissionMajorRevision: {types.SubmissionPending, types.SubmissionAccepted, types.SubmissionRejected},
+}
+
+// consensusTransitions is the consensus state machine; closed is final.
+var consensusTransitions = map[types.ConsensusStatus][]types.ConsensusStatus{
+	types.ConsensusOpen:     {types.ConsensusAchieved, types.ConsensusClosed},
+	types.ConsensusAchieved: {types.ConsensusClosed},
+}
+
+// CanTransitionSubmission reports whether a submission may move from one
+// status to another.
+func CanTransitionSubmission(from, to types.SubmissionStatus) bool {
+	for _, next := range submissionTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionConsensus reports whether a consensus request may move from
+// one status to another. An empty status, from older data, is open.
+func CanTransitionConsensus(from, to types.ConsensusStatus) bool {
+	if from == "" {
+		from = types.ConsensusOpen
+	}
+	for _, next := range consensusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// VerdictStatus is the submission status a decision with verdict leads to.
+func VerdictStatus(verdict types.PaperReviewVerdict) (types.SubmissionStatus, error) {
+	switch verdict {
+	case types.VerdictAccept:
+		return types.SubmissionAccepted, nil
+	case types.VerdictReject:
+		return types.SubmissionRejected, nil
+	case types.VerdictMinorRevision:
+		return types.SubmissionMinorRevision, nil
+	case types.VerdictMajorRevision:
+		return types.SubmissionMajorRevision, nil
+	}
+	return "", fmt.Errorf("invalid verdict: %s", verdict)
+}
+
+// checkSubmissionTransition returns an ErrIllegalTransition error when sub
+// can't move to status.
+func checkSubmissionTransition(sub *types.Submission, status types.SubmissionStatus) error {
+	if !CanTransitionSubmission(sub.Status, status) {
+		return fmt.Errorf("submission %s: %w %s → %s", sub.ID, ErrIllegalTransition, sub.Status, status)
+	}
+	return nil
+}
+
+// transitionSubmission moves sub to status and records the change, under
+// the workflow lock.
+func transitionSubmission(sub *types.Submission, status types.SubmissionStatus, actor string, at time.Time) error {
+	if err := checkSubmissionTransition(sub, status); err != nil {
+		return err
+	}
+	sub.History = append(sub.History, types.StatusTransition{From: string(sub.Status), To: string(status), Actor: actor, At: at})
+	sub.Status = status
+	return nil
+}
+
+// transitionConsensus moves req to status and records the change, under
+// the workflow lock.
+func transitionConsensus(req *types.ConsensusRequest, status types.ConsensusStatus, actor string, at time.Time) error {
+	if !CanTransitionConsensus(req.Status, status) {
+		return fmt.Errorf("consensus request %s: %w %s → %s", req.ID, ErrIllegalTransition, req.Status, status)
+	}
+	from := req.Status
+	if from == "" {
+		from = types.ConsensusOpen
+	}
+	req.History = append(req.History, types.StatusTransition{From: string(from), To: string(status), Actor: actor, At: at})
+	req.Status = status
+	return nil
+}
@@ -0,0 +1,97 @@
+package publication
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+var subredditNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,31}$`)
+
+var builtinSubredditDescriptions = map[types.Subreddit]string{
+	types.SubMathematics: "数学",
+	types.SubPhysics:     "物理",
+	types.SubPhilosophy:  "哲学",
+	types.SubBiology:     "生物学",
+	types.SubComputing:   "计算机科学",
+	types.SubGeneral:     "通用讨论",
+}
+
+// NormalizeSubreddit lowercases and trims a subreddit name, dropping an "r/" prefix.
+func NormalizeSubreddit(name string) types.Subreddit {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "r/")
+	return types.Subreddit(name)
+}
+
+// CreateSubreddit registers a new agent-created subreddit.
+func (f *Forum) CreateSubreddit(info *types.SubredditInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info.Name = NormalizeSubreddit(string(info.Name))
+	if !subredditNamePattern.MatchString(string(info.Name)) {
+		return fmt.Errorf("invalid subreddit name %q: use 2-32 lowercase letters, digits, '-' or '_'", info.Name)
+	}
+	if types.IsBuiltinSubreddit(info.Name) {
+		return fmt.Errorf("subreddit already exists: %s", info.Name)
+	}
+	if _, ok := f.Subreddits[info.Name]; ok {
+		return fmt.Errorf("subreddit already exists: %s", info.Name)
+	}
+	if info.CreatedAt.IsZero() {
+		info.CreatedAt = time.Now()
+	}
+	info.Description = strings.TrimSpace(info.Description)
+	info.BuiltIn = false
+	if f.Subreddits == nil {
+		f.Subreddits = make(map[types.Subreddit]*types.SubredditInfo)
+	}
+	f.Subreddits[info.Name] = info
+	recordChange(f.audit, audit.StoreForum, "create_subreddit", info.CreatorID, string(info.Name), "", info)
+	return nil
+}
+
+// HasSubreddit reports whether sub is built-in or registered.
+func (f *Forum) HasSubreddit(sub types.Subreddit) bool {
+	if types.IsBuiltinSubreddit(sub) {
+		return true
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.Subreddits[sub]
+	return ok
+}
+
+// ListSubreddits returns built-in subreddits followed by agent-created ones
+// (oldest first).
+func (f *Forum) ListSubreddits() []*types.SubredditInfo {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	out := make([]*types.SubredditInfo, 0, len(types.AllSubreddits())+len(f.Subreddits))
+	for _, sub := range types.AllSubreddits() {
+		out = append(out, &types.SubredditInfo{
+			Name:        sub,
+			Description: builtinSubredditDescriptions[sub],
+			BuiltIn:     true,
+		})
+	}
+	custom := make([]*types.SubredditInfo, 0, len(f.Subreddits))
+	for _, info := range f.Subreddits {
+		cp := *info
+		custom = append(custom, &cp)
+	}
+	sort.Slice(custom, func(i, j int) bool {
+		if custom[i].CreatedAt.Equal(custom[j].CreatedAt) {
+			return custom[i].Name < custom[j].Name
+		}
+		return custom[i].CreatedAt.Before(custom[j].CreatedAt)
+	})
+	return append(out, custom...)
+}
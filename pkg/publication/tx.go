@@ -0,0 +1,101 @@
+package publication
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Tx makes an operation that touches several stores all-or-nothing. Each
+// step registers how to undo itself right after it succeeds; if a later
+// step fails, Rollback runs the undos newest first, so a tool that returns
+// an error leaves the forum, journal and workflow as it found them.
+// Publish listeners that already ran are not recalled.
+type Tx struct {
+	undo []func() error
+}
+
+// OnRollback registers an undo for a step that succeeded.
+func (tx *Tx) OnRollback(fn func() error) {
+	tx.undo = append(tx.undo, fn)
+}
+
+// Rollback undoes the registered steps and returns cause, joined with any
+// undo that failed.
+func (tx *Tx) Rollback(cause error) error {
+	errs := []error{cause}
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		if err := tx.undo[i](); err != nil {
+			errs = append(errs, fmt.Errorf("rollback: %w", err))
+		}
+	}
+	tx.undo = nil
+	return errors.Join(errs...)
+}
+
+// Revert removes a post or comment published by an operation that failed
+// later, with its reports and summary, and undoes the parent's comment
+// count. Posts that already have replies are kept.
+func (f *Forum) Revert(pubID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pub, ok := f.Posts[pubID]
+	if !ok {
+		return fmt.Errorf("post not found: %s", pubID)
+	}
+	if len(f.index.children[pubID]) > 0 {
+		return fmt.Errorf("post has replies: %s", pubID)
+	}
+	before := f.audit.Hash(pub)
+	delete(f.Posts, pubID)
+	delete(f.Summaries, pubID)
+	for key, r := range f.Reports {
+		if r.PostID == pubID {
+			delete(f.Reports, key)
+		}
+	}
+	var changed []*types.Publication
+	if parent := f.Posts[pub.ParentID]; pub.IsComment && parent != nil && parent.Comments > 0 {
+		parent.Comments--
+		changed = append(changed, parent)
+	}
+	f.rebuildIndexLocked()
+	f.appendDeleteWALLocked(changed, pubID)
+	recordChange(f.audit, audit.StoreForum, "revert", pub.AuthorID, pubID, before, nil)
+	return nil
+}
+
+// Withdraw removes a pending submission, e.g. when the rest of submitting it
+// failed.
+func (j *Journal) Withdraw(pubID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pub, ok := j.Pending[pubID]
+	if !ok {
+		return fmt.Errorf("publication not found in pending: %s", pubID)
+	}
+	before := j.audit.Hash(pub)
+	delete(j.Pending, pubID)
+	recordChange(j.audit, audit.StoreJournal, "withdraw", pub.AuthorID, pubID, before, nil)
+	return nil
+}
+
+// RemoveConsensusRequest drops a consensus request, e.g. when the rest of
+// requesting it failed.
+func (w *Workflow) RemoveConsensusRequest(id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	req, ok := w.Consensus[id]
+	if !ok {
+		return fmt.Errorf("consensus request not found: %s", id)
+	}
+	before := w.audit.Hash(req)
+	delete(w.Consensus, id)
+	recordChange(w.audit, audit.StoreWorkflow, "remove_consensus", req.RequesterID, id, before, nil)
+	return nil
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/idgen"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
 
@@ -18,14 +22,31 @@ type Workflow struct {
 	Consensus   map[string]*types.ConsensusRequest
 	Submissions map[string]*types.Submission
 	Reviews     map[string][]*types.PaperReview
+	Cycles      []*types.ReviewCycle
+	Disputes    map[string]*types.Dispute
+	Retractions map[string]*types.RetractionRequest
 	dataPath    string
+	audit       *audit.Log
+	saveMu      sync.Mutex // orders concurrent saves
+
+	// scheduledReview defers review decisions to review cycles.
+	scheduledReview bool
+
+	reviewListeners []func(*types.Submission, *types.PaperReview)
+
+	decisionListeners  []func(*types.Submission)
+	consensusRule      ConsensusThresholds
+	consensusListeners []func(*types.ConsensusRequest)
 }
 
 type workflowStore struct {
-	Drafts      map[string]*types.Draft            `json:"drafts"`
-	Consensus   map[string]*types.ConsensusRequest `json:"consensus"`
-	Submissions map[string]*types.Submission       `json:"submissions"`
-	Reviews     map[string][]*types.PaperReview    `json:"reviews"`
+	Drafts      map[string]*types.Draft             `json:"drafts"`
+	Consensus   map[string]*types.ConsensusRequest  `json:"consensus"`
+	Submissions map[string]*types.Submission        `json:"submissions"`
+	Reviews     map[string][]*types.PaperReview     `json:"reviews"`
+	Cycles      []*types.ReviewCycle                `json:"review_cycles,omitempty"`
+	Disputes    map[string]*types.Dispute           `json:"disputes,omitempty"`
+	Retractions map[string]*types.RetractionRequest `json:"retractions,omitempty"`
 }
 
 // NewWorkflow creates a workflow store rooted at dataPath.
@@ -35,6 +56,8 @@ func NewWorkflow(dataPath string) *Workflow {
 		Consensus:   make(map[string]*types.ConsensusRequest),
 		Submissions: make(map[string]*types.Submission),
 		Reviews:     make(map[string][]*types.PaperReview),
+		Disputes:    make(map[string]*types.Dispute),
+		Retractions: make(map[string]*types.RetractionRequest),
 		dataPath:    dataPath,
 	}
 }
@@ -67,28 +90,43 @@ func (w *Workflow) Load() error {
 	if store.Reviews != nil {
 		w.Reviews = store.Reviews
 	}
+	w.Cycles = store.Cycles
+	if store.Disputes != nil {
+		w.Disputes = store.Disputes
+	}
+	if store.Retractions != nil {
+		w.Retractions = store.Retractions
+	}
 	return nil
 }
 
-// Save persists workflow data to disk.
+// Save writes a snapshot of the workflow atomically. Saves are serialized
+// so an older snapshot never replaces a newer one.
 func (w *Workflow) Save() error {
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+
+	// Marshal under the read lock: agents may mutate the maps concurrently.
 	w.mu.RLock()
 	store := workflowStore{
 		Drafts:      w.Drafts,
 		Consensus:   w.Consensus,
 		Submissions: w.Submissions,
 		Reviews:     w.Reviews,
+		Cycles:      w.Cycles,
+		Disputes:    w.Disputes,
+		Retractions: w.Retractions,
 	}
+	data, err := json.MarshalIndent(store, "", "  ")
 	w.mu.RUnlock()
-
-	if err := os.MkdirAll(w.dataPath, 0755); err != nil {
+	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(store, "", "  ")
-	if err != nil {
+
+	if err := os.MkdirAll(w.dataPath, 0755); err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(w.dataPath, "workflow.json"), data, 0644)
+	return writeFileAtomic(filepath.Join(w.dataPath, "workflow.json"), data, 0644)
 }
 
 // CreateDraft registers a new draft.
@@ -97,13 +135,26 @@ func (w *Workflow) CreateDraft(draft *types.Draft) string {
 	defer w.mu.Unlock()
 
 	if draft.ID == "" {
-		draft.ID = fmt.Sprintf("draft-%d", time.Now().UnixNano())
+		draft.ID = idgen.New("draft")
 	}
 	if draft.CreatedAt.IsZero() {
 		draft.CreatedAt = time.Now()
 	}
 	draft.UpdatedAt = time.Now()
+	if len(draft.Contributions) == 0 && len(draft.Authors) > 0 {
+		draft.Contributions = []types.DraftContribution{{
+			AuthorID: draft.Authors[0],
+			Mode:     "create",
+			Chars:    utf8.RuneCountInString(draft.Content),
+			At:       draft.CreatedAt,
+		}}
+	}
+	before := ""
+	if prev, ok := w.Drafts[draft.ID]; ok {
+		before = w.audit.Hash(prev)
+	}
 	w.Drafts[draft.ID] = draft
+	recordChange(w.audit, audit.StoreWorkflow, "draft", firstAuthor(draft.Authors), draft.ID, before, draft)
 	return draft.ID
 }
 
@@ -113,13 +164,20 @@ func (w *Workflow) AddConsensusRequest(req *types.ConsensusRequest) string {
 	defer w.mu.Unlock()
 
 	if req.ID == "" {
-		req.ID = fmt.Sprintf("consensus-%d", time.Now().UnixNano())
+		req.ID = idgen.New("consensus")
 	}
 	if req.CreatedAt.IsZero() {
 		req.CreatedAt = time.Now()
 	}
 	req.UpdatedAt = time.Now()
+	if req.Status == "" {
+		req.Status = types.ConsensusOpen
+	}
+	if len(req.History) == 0 {
+		req.History = []types.StatusTransition{{To: string(req.Status), Actor: req.RequesterID, At: req.CreatedAt}}
+	}
 	w.Consensus[req.ID] = req
+	recordChange(w.audit, audit.StoreWorkflow, "request_consensus", req.RequesterID, req.ID, "", req)
 	return req.ID
 }
 
@@ -129,29 +187,64 @@ func (w *Workflow) AddSubmission(sub *types.Submission) string {
 	defer w.mu.Unlock()
 
 	if sub.ID == "" {
-		sub.ID = fmt.Sprintf("submission-%d", time.Now().UnixNano())
+		sub.ID = idgen.New("submission")
 	}
 	if sub.CreatedAt.IsZero() {
 		sub.CreatedAt = time.Now()
 	}
 	sub.UpdatedAt = time.Now()
+	if sub.Status == "" {
+		sub.Status = types.SubmissionPending
+	}
+	if len(sub.History) == 0 {
+		sub.History = []types.StatusTransition{{To: string(sub.Status), Actor: sub.AuthorID, At: sub.CreatedAt}}
+	}
 	w.Submissions[sub.ID] = sub
+	recordChange(w.audit, audit.StoreWorkflow, "submit", sub.AuthorID, sub.ID, "", sub)
 	return sub.ID
 }
 
 // AddReview registers a new review.
 func (w *Workflow) AddReview(review *types.PaperReview) string {
+	sub, listeners := w.addReview(review)
+	if sub != nil {
+		for _, fn := range listeners {
+			fn(sub, review)
+		}
+	}
+	return review.ID
+}
+
+func (w *Workflow) addReview(review *types.PaperReview) (*types.Submission, []func(*types.Submission, *types.PaperReview)) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	if review.ID == "" {
-		review.ID = fmt.Sprintf("review-%d", time.Now().UnixNano())
+		review.ID = idgen.New("review")
 	}
 	if review.CreatedAt.IsZero() {
 		review.CreatedAt = time.Now()
 	}
 	w.Reviews[review.SubmissionID] = append(w.Reviews[review.SubmissionID], review)
-	return review.ID
+	recordChange(w.audit, audit.StoreWorkflow, "review", review.ReviewerID, review.ID, "", review)
+	return w.Submissions[review.SubmissionID], append([]func(*types.Submission, *types.PaperReview){}, w.reviewListeners...)
+}
+
+// OnReview registers a listener invoked after a review is added to a known
+// submission. Listeners run outside the workflow lock.
+func (w *Workflow) OnReview(fn func(*types.Submission, *types.PaperReview)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reviewListeners = append(w.reviewListeners, fn)
+}
+
+// OnDecision registers a listener invoked after a submission is accepted,
+// rejected or sent back for revision. Listeners run outside the workflow
+// lock.
+func (w *Workflow) OnDecision(fn func(*types.Submission)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.decisionListeners = append(w.decisionListeners, fn)
 }
 
 // GetDraft returns a draft by ID.
@@ -161,6 +254,64 @@ func (w *Workflow) GetDraft(id string) *types.Draft {
 	return w.Drafts[id]
 }
 
+// GetConsensus returns a consensus request by ID.
+func (w *Workflow) GetConsensus(id string) *types.ConsensusRequest {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.Consensus[id]
+}
+
+// FindOpenConsensus returns the ID of a non-closed consensus request for postID.
+func (w *Workflow) FindOpenConsensus(postID string) string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for id, req := range w.Consensus {
+		if req.PostID == postID && req.Status != types.ConsensusClosed {
+			return id
+		}
+	}
+	return ""
+}
+
+// ConsensusForPost returns the consensus requests about postID, oldest
+// first.
+func (w *Workflow) ConsensusForPost(postID string) []*types.ConsensusRequest {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var out []*types.ConsensusRequest
+	for _, req := range w.Consensus {
+		if req.PostID == postID {
+			out = append(out, req)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// OpenCounts returns the number of assigned reviews still owed on undecided
+// submissions and the number of consensus requests that are not closed.
+func (w *Workflow) OpenCounts() (pendingReviews, openConsensus int) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, sub := range w.Submissions {
+		if sub.Status != types.SubmissionPending || !sub.DecidedAt.IsZero() {
+			continue
+		}
+		for _, id := range sub.AssignedReviewers {
+			if !w.hasReviewLocked(sub.ID, id) {
+				pendingReviews++
+			}
+		}
+	}
+	for _, req := range w.Consensus {
+		if req.Status != types.ConsensusClosed {
+			openConsensus++
+		}
+	}
+	return pendingReviews, openConsensus
+}
+
 // GetSubmission returns a submission by ID.
 func (w *Workflow) GetSubmission(id string) *types.Submission {
 	w.mu.RLock()
@@ -168,16 +319,34 @@ func (w *Workflow) GetSubmission(id string) *types.Submission {
 	return w.Submissions[id]
 }
 
-// UpdateSubmissionStatus updates the status of a submission.
-func (w *Workflow) UpdateSubmissionStatus(id string, status types.SubmissionStatus) {
+// AllReviews returns every recorded review across submissions.
+func (w *Workflow) AllReviews() []*types.PaperReview {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	out := make([]*types.PaperReview, 0)
+	for _, reviews := range w.Reviews {
+		out = append(out, reviews...)
+	}
+	return out
+}
+
+// UpdateSubmissionStatus moves a submission to status on behalf of actor.
+// It fails with ErrIllegalTransition when the state machine doesn't allow
+// the change.
+func (w *Workflow) UpdateSubmissionStatus(id string, status types.SubmissionStatus, actor string) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	sub, ok := w.Submissions[id]
 	if !ok {
-		return
+		return fmt.Errorf("submission not found: %s", id)
+	}
+	before := w.audit.Hash(sub)
+	if err := transitionSubmission(sub, status, actor, time.Now()); err != nil {
+		return err
 	}
-	sub.Status = status
 	sub.UpdatedAt = time.Now()
+	recordChange(w.audit, audit.StoreWorkflow, "set_status", actor, id, before, sub)
+	return nil
 }
 
 // AttachReview links a review to a submission.
@@ -188,6 +357,8 @@ func (w *Workflow) AttachReview(submissionID, reviewID string) {
 	if !ok {
 		return
 	}
+	before := w.audit.Hash(sub)
 	sub.ReviewIDs = append(sub.ReviewIDs, reviewID)
 	sub.UpdatedAt = time.Now()
+	recordChange(w.audit, audit.StoreWorkflow, "attach_review", "", submissionID, before, sub)
 }
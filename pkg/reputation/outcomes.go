@@ -0,0 +1,96 @@
+package reputation
+
+import (
+	"math"
+	"regexp"
+
+	"github.com/cpunion/sci-bot/pkg/idgen"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// citationSaturation is how many citing works make a paper's outcome fully
+// positive.
+const citationSaturation = 3
+
+// paperIDPattern matches journal paper IDs cited in forum and journal text.
+var paperIDPattern = regexp.MustCompile(`\bjournal-` + idgen.Suffix + `\b`)
+
+// PaperOutcome is how a published paper fared after acceptance, in [-1, 1]:
+// citations push it up, disputes down, and a retraction makes it -1.
+func PaperOutcome(p *types.Publication, citations int) (float64, bool) {
+	if p.Retracted {
+		return -1, true
+	}
+	if citations == 0 && !p.Disputed {
+		return 0, false // no signal yet
+	}
+	outcome := float64(min(citations, citationSaturation)) / citationSaturation
+	if p.Disputed {
+		outcome -= 1
+	}
+	return outcome, true
+}
+
+// Citations counts, per journal paper, the distinct works by other authors
+// that mention its ID. Either source may be nil.
+func Citations(journal *publication.Journal, forum *publication.Forum) map[string]int {
+	var works []*types.Publication
+	authors := make(map[string]string)
+	if journal != nil {
+		for _, p := range journal.GetApproved() {
+			works = append(works, p)
+			authors[p.ID] = p.AuthorID
+		}
+	}
+	if forum != nil {
+		works = append(works, forum.AllPublications()...)
+	}
+
+	out := make(map[string]int)
+	for _, w := range works {
+		seen := make(map[string]bool)
+		for _, text := range []string{w.Title, w.Abstract, w.Content} {
+			for _, id := range paperIDPattern.FindAllString(text, -1) {
+				author, ok := authors[id]
+				if !ok || seen[id] || id == w.ID || author == w.AuthorID {
+					continue
+				}
+				seen[id] = true
+				out[id]++
+			}
+		}
+	}
+	return out
+}
+
+// CollectOutcomes adds to acts, per reviewer, how well each verdict on a
+// published paper anticipated its later outcome. Papers without citations,
+// disputes or a retraction yet are skipped.
+func CollectOutcomes(acts map[string]*Activity, journal *publication.Journal, workflow *publication.Workflow, forum *publication.Forum) {
+	if journal == nil || workflow == nil {
+		return
+	}
+	citations := Citations(journal, forum)
+	for _, p := range journal.GetApproved() {
+		outcome, ok := PaperOutcome(p, citations[p.ID])
+		if !ok {
+			continue
+		}
+		for _, r := range workflow.GetReviews(p.ID) {
+			rank, ok := verdictRank(r.Verdict)
+			if !ok || r.ReviewerID == "" {
+				continue
+			}
+			// reject..accept maps to -1..1 support for the paper.
+			support := float64(rank)*2/3 - 1
+			a, ok := acts[r.ReviewerID]
+			if !ok {
+				a = &Activity{}
+				acts[r.ReviewerID] = a
+			}
+			a.OutcomeReviews++
+			a.OutcomeAgreement += 1 - math.Abs(support-outcome)/2
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package reputation computes agent reputation from forum vote karma, journal
+// outcomes and review quality.
+package reputation
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Score weights. Vote karma is log-scaled so a single viral post can't
+// outweigh sustained journal and review work.
+const (
+	karmaWeight    = 1.0 // per log unit of net vote karma
+	acceptedWeight = 2.0 // per accepted paper
+	rejectedWeight = 0.5 // per rejected paper
+	reviewWeight   = 1.0 // per review, scaled by its agreement with the decision
+	outcomeWeight  = 0.5 // per review of a published paper, scaled by its agreement with later outcomes
+
+	// normalizeScale is the score at which Normalize returns 0.5.
+	normalizeScale = 5.0
+)
+
+// Activity is an agent's journal and review record.
+type Activity struct {
+	Accepted int
+	Rejected int
+	Reviews  int
+	// Agreement sums, per review, how close the verdict was to the final
+	// decision (1 = same, 0 = opposite ends of reject..accept).
+	Agreement float64
+	// OutcomeReviews and OutcomeAgreement track reviews of published papers
+	// against what happened later (citations, disputes, retraction); see
+	// CollectOutcomes.
+	OutcomeReviews   int
+	OutcomeAgreement float64
+}
+
+// Collect tallies accepted/rejected papers per author and review agreement
+// per reviewer. Either source may be nil.
+func Collect(journal *publication.Journal, workflow *publication.Workflow) map[string]*Activity {
+	out := make(map[string]*Activity)
+	get := func(id string) *Activity {
+		a, ok := out[id]
+		if !ok {
+			a = &Activity{}
+			out[id] = a
+		}
+		return a
+	}
+
+	if journal != nil {
+		for _, p := range journal.GetApproved() {
+			get(p.AuthorID).Accepted++
+		}
+		for _, p := range journal.GetRejectedList() {
+			get(p.AuthorID).Rejected++
+		}
+	}
+
+	if workflow != nil {
+		for _, r := range workflow.AllReviews() {
+			if r == nil {
+				continue
+			}
+			sub := workflow.GetSubmission(r.SubmissionID)
+			if sub == nil {
+				continue
+			}
+			final, ok := verdictRank(statusVerdict(sub.Status))
+			if !ok {
+				continue // undecided
+			}
+			given, ok := verdictRank(r.Verdict)
+			if !ok {
+				continue
+			}
+			a := get(r.ReviewerID)
+			a.Reviews++
+			a.Agreement += 1 - math.Abs(float64(given-final))/3
+		}
+	}
+	return out
+}
+
+// Compute combines karma and activity into a reputation snapshot.
+func Compute(karma types.Karma, act Activity, now time.Time) types.Reputation {
+	net := karma.Total()
+	quality := 0.0
+	if act.Reviews > 0 {
+		quality = act.Agreement / float64(act.Reviews)
+	}
+	outcome := 0.0
+	if act.OutcomeReviews > 0 {
+		outcome = act.OutcomeAgreement / float64(act.OutcomeReviews)
+	}
+	score := karmaWeight*signedLog(net) +
+		acceptedWeight*float64(act.Accepted) -
+		rejectedWeight*float64(act.Rejected) +
+		reviewWeight*act.Agreement +
+		outcomeWeight*act.OutcomeAgreement
+	return types.Reputation{
+		Score:          math.Round(score*100) / 100,
+		VoteKarma:      net,
+		AcceptedPapers: act.Accepted,
+		RejectedPapers: act.Rejected,
+		Reviews:        act.Reviews,
+		ReviewQuality:  math.Round(quality*100) / 100,
+		UpdatedAt:      now,
+		OutcomeReviews: act.OutcomeReviews,
+		OutcomeQuality: math.Round(outcome*100) / 100,
+	}
+}
+
+// Normalize maps a score to [0, 1): 0 for non-positive scores, approaching 1
+// as reputation grows.
+func Normalize(score float64) float64 {
+	if score <= 0 {
+		return 0
+	}
+	return score / (score + normalizeScale)
+}
+
+func signedLog(v int) float64 {
+	if v < 0 {
+		return -math.Log1p(float64(-v))
+	}
+	return math.Log1p(float64(v))
+}
+
+func statusVerdict(status types.SubmissionStatus) types.PaperReviewVerdict {
+	switch status {
+	case types.SubmissionAccepted:
+		return types.VerdictAccept
+	case types.SubmissionRejected:
+		return types.VerdictReject
+	case types.SubmissionMinorRevision:
+		return types.VerdictMinorRevision
+	case types.SubmissionMajorRevision:
+		return types.VerdictMajorRevision
+	}
+	return ""
+}
+
+func verdictRank(v types.PaperReviewVerdict) (int, bool) {
+	switch v {
+	case types.VerdictReject:
+		return 0, true
+	case types.VerdictMajorRevision:
+		return 1, true
+	case types.VerdictMinorRevision:
+		return 2, true
+	case types.VerdictAccept:
+		return 3, true
+	}
+	return 0, false
+}
+
+// Board is a concurrency-safe table of current reputations, shared between
+// the scheduler (writer) and agent tools (readers).
+type Board struct {
+	mu     sync.RWMutex
+	scores map[string]types.Reputation
+}
+
+// NewBoard creates an empty board.
+func NewBoard() *Board {
+	return &Board{scores: make(map[string]types.Reputation)}
+}
+
+// Set records an agent's reputation.
+func (b *Board) Set(agentID string, rep types.Reputation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.scores[agentID] = rep
+}
+
+// Get returns an agent's reputation, if known.
+func (b *Board) Get(agentID string) (types.Reputation, bool) {
+	if b == nil {
+		return types.Reputation{}, false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	rep, ok := b.scores[agentID]
+	return rep, ok
+}
+
+// Normalized returns the agent's normalized score (0 if unknown).
+func (b *Board) Normalized(agentID string) float64 {
+	rep, ok := b.Get(agentID)
+	if !ok {
+		return 0
+	}
+	return Normalize(rep.Score)
+}
@@ -0,0 +1,113 @@
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestCollectAndCompute(t *testing.T) {
+	dir := t.TempDir()
+	journal := publication.NewJournal("J", dir)
+	workflow := publication.NewWorkflow(dir)
+
+	journal.Submit(&types.Publication{ID: "s1", AuthorID: "author", Title: "Good"})
+	journal.Submit(&types.Publication{ID: "s2", AuthorID: "author", Title: "Bad"})
+	journal.Approve("s1", "editor")
+	journal.Reject("s2", "editor")
+
+	workflow.AddSubmission(&types.Submission{ID: "s1", AuthorID: "author", Status: types.SubmissionAccepted})
+	workflow.AddSubmission(&types.Submission{ID: "s2", AuthorID: "author", Status: types.SubmissionRejected})
+	workflow.AddSubmission(&types.Submission{ID: "s3", AuthorID: "author", Status: types.SubmissionPending})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "s1", ReviewerID: "r1", Verdict: types.VerdictAccept})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "s2", ReviewerID: "r1", Verdict: types.VerdictAccept})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "s3", ReviewerID: "r1", Verdict: types.VerdictAccept})
+
+	acts := Collect(journal, workflow)
+	if a := acts["author"]; a == nil || a.Accepted != 1 || a.Rejected != 1 {
+		t.Fatalf("unexpected author activity: %+v", a)
+	}
+	r1 := acts["r1"]
+	if r1 == nil || r1.Reviews != 2 {
+		t.Fatalf("expected 2 reviews on decided submissions, got %+v", r1)
+	}
+	if r1.Agreement != 1 {
+		t.Errorf("expected one agreeing and one opposite review, got agreement %.2f", r1.Agreement)
+	}
+
+	now := time.Now()
+	rep := Compute(types.Karma{PostKarma: 3}, *r1, now)
+	if rep.ReviewQuality != 0.5 || rep.VoteKarma != 3 || !rep.UpdatedAt.Equal(now) {
+		t.Errorf("unexpected reputation: %+v", rep)
+	}
+	if low := Compute(types.Karma{PostKarma: -5}, Activity{}, now); low.Score >= 0 || Normalize(low.Score) != 0 {
+		t.Errorf("expected negative karma to give non-positive reputation, got %+v", low)
+	}
+	if hi, lo := Compute(types.Karma{}, *acts["author"], now), Compute(types.Karma{}, Activity{Rejected: 1}, now); hi.Score <= lo.Score {
+		t.Errorf("expected accepted paper to raise reputation: %.2f <= %.2f", hi.Score, lo.Score)
+	}
+}
+
+func TestBoard(t *testing.T) {
+	var nilBoard *Board
+	if nilBoard.Normalized("x") != 0 {
+		t.Error("expected nil board to report zero")
+	}
+	b := NewBoard()
+	b.Set("a", types.Reputation{Score: 5})
+	if got := b.Normalized("a"); got != 0.5 {
+		t.Errorf("expected 0.5, got %.2f", got)
+	}
+	if b.Normalized("missing") != 0 {
+		t.Error("expected unknown agent to be zero")
+	}
+}
+
+func TestCollectOutcomes(t *testing.T) {
+	dir := t.TempDir()
+	journal := publication.NewJournal("J", dir)
+	workflow := publication.NewWorkflow(dir)
+	forum := publication.NewForum("F", dir)
+
+	for _, id := range []string{"journal-1", "journal-2", "journal-3"} {
+		journal.Submit(&types.Publication{ID: id, AuthorID: "author", Title: id})
+		journal.Approve(id, "editor")
+		workflow.AddSubmission(&types.Submission{ID: id, AuthorID: "author", Status: types.SubmissionAccepted})
+	}
+	if err := journal.Retract("journal-2", "fabricated data", "editor"); err != nil {
+		t.Fatalf("Retract: %v", err)
+	}
+	// journal-1 is cited by another agent; the author's self-citation of
+	// journal-3 does not count, so journal-3 has no outcome yet.
+	forum.Post(&types.Publication{ID: "forum-1", AuthorID: "reader", Content: "Builds on journal-1."})
+	forum.Post(&types.Publication{ID: "forum-2", AuthorID: "author", Content: "See journal-3."})
+
+	workflow.AddReview(&types.PaperReview{SubmissionID: "journal-1", ReviewerID: "r1", Verdict: types.VerdictAccept})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "journal-2", ReviewerID: "r1", Verdict: types.VerdictAccept})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "journal-1", ReviewerID: "r2", Verdict: types.VerdictReject})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "journal-2", ReviewerID: "r2", Verdict: types.VerdictReject})
+	workflow.AddReview(&types.PaperReview{SubmissionID: "journal-3", ReviewerID: "r3", Verdict: types.VerdictAccept})
+
+	if got := Citations(journal, forum); got["journal-1"] != 1 || got["journal-3"] != 0 {
+		t.Fatalf("citations = %v", got)
+	}
+	acts := Collect(journal, workflow)
+	CollectOutcomes(acts, journal, workflow, forum)
+	if r3 := acts["r3"]; r3 != nil && r3.OutcomeReviews != 0 {
+		t.Errorf("expected no outcome for an uncited paper, got %+v", r3)
+	}
+
+	now := time.Now()
+	r1 := Compute(types.Karma{}, *acts["r1"], now)
+	r2 := Compute(types.Karma{}, *acts["r2"], now)
+	if r1.OutcomeReviews != 2 || r2.OutcomeReviews != 2 {
+		t.Fatalf("outcome reviews = %d, %d", r1.OutcomeReviews, r2.OutcomeReviews)
+	}
+	// Accepting a paper that was later retracted costs more than rejecting
+	// one that was cited once.
+	if r1.OutcomeQuality != 0.33 || r2.OutcomeQuality != 0.67 {
+		t.Errorf("outcome quality = %.2f, %.2f", r1.OutcomeQuality, r2.OutcomeQuality)
+	}
+}
@@ -0,0 +1,150 @@
+package simulation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// builtinActions are the weighted actions agents choose between on free
+// turns when no scenario changes the catalog.
+var builtinActions = map[string]bool{
+	"browse": true, "read": true, "post": true, "interact": true, "review": true, "observe": true,
+}
+
+// reservedActions name the turns and log events the scheduler produces
+// itself; a scenario action can't reuse them.
+var reservedActions = map[string]bool{
+	"idle": true, "sleep": true, "dream": true, "group": true, "review_duty": true, "editor_duty": true,
+	ActionSeminar: true, ActionConsensus: true, ActionDigest: true, ActionRetire: true, ActionRecruit: true,
+	ActionHeartbeat: true, ActionRetraction: true, ActionLiteratureDrop: true, ActionVisitorQuestion: true,
+	ActionPlan: true,
+}
+
+var actionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ScenarioAction adds a weighted action to the catalog agents choose from on
+// free turns, or retunes a built-in one (browse, read, post, interact,
+// review, observe). Its weight is Base plus each trait weight times the
+// persona's trait, before the per-agent jitter; a weight of 0 takes the
+// action out of the catalog.
+type ScenarioAction struct {
+	Name string `json:"name"`
+	// Roles limits the entry to these roles; empty applies to every role.
+	// Later entries for the same action and role win, so a role-specific
+	// entry can follow a general one.
+	Roles  []types.AgentRole  `json:"roles,omitempty"`
+	Base   float64            `json:"base"`
+	Traits map[string]float64 `json:"traits,omitempty"` // creativity, rigor, risk_tolerance, sociability, influence
+	// Prompts are the turn prompt options by language (zh, en), one picked
+	// at random. Without prompts in the agent's language the action uses
+	// its "action.<name>" prompt template, then the other language.
+	Prompts map[prompts.Lang][]string `json:"prompts,omitempty"`
+}
+
+func (a *ScenarioAction) appliesTo(role types.AgentRole) bool {
+	if len(a.Roles) == 0 {
+		return true
+	}
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *ScenarioAction) weight(p *types.Persona) float64 {
+	w := a.Base
+	if p == nil {
+		return w
+	}
+	for trait, k := range a.Traits {
+		w += k * personaTrait(p, trait)
+	}
+	return w
+}
+
+func personaTrait(p *types.Persona, name string) float64 {
+	switch name {
+	case "creativity":
+		return p.Creativity
+	case "rigor":
+		return p.Rigor
+	case "risk_tolerance":
+		return p.RiskTolerance
+	case "sociability":
+		return p.Sociability
+	case "influence":
+		return p.Influence
+	}
+	return 0
+}
+
+// roleActions returns the scenario's action entries for role, in file order.
+func (sc *Scenario) roleActions(role types.AgentRole) []ScenarioAction {
+	if sc == nil {
+		return nil
+	}
+	var out []ScenarioAction
+	for _, a := range sc.Actions {
+		if a.appliesTo(role) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// actionPrompts returns the scenario prompt options for action in lang, for
+// an agent of the given role.
+func (sc *Scenario) actionPrompts(role types.AgentRole, action string, lang prompts.Lang) []string {
+	var texts []string
+	for _, a := range sc.roleActions(role) {
+		if a.Name == action && len(a.Prompts[lang]) > 0 {
+			texts = a.Prompts[lang]
+		}
+	}
+	return texts
+}
+
+func (a *ScenarioAction) validate() error {
+	if !actionNamePattern.MatchString(a.Name) {
+		return fmt.Errorf("action %q: name must be lower-case letters, digits and underscores", a.Name)
+	}
+	if reservedActions[a.Name] {
+		return fmt.Errorf("action %s: name is used by the scheduler", a.Name)
+	}
+	for _, role := range a.Roles {
+		switch role {
+		case types.RoleExplorer, types.RoleBuilder, types.RoleReviewer, types.RoleSynthesizer, types.RoleCommunicator, types.RoleEditor:
+		default:
+			return fmt.Errorf("action %s: unknown role %q", a.Name, role)
+		}
+	}
+	for trait := range a.Traits {
+		switch trait {
+		case "creativity", "rigor", "risk_tolerance", "sociability", "influence":
+		default:
+			return fmt.Errorf("action %s: unknown trait %q", a.Name, trait)
+		}
+	}
+	prompted := false
+	for lang, texts := range a.Prompts {
+		if lang != prompts.Chinese && lang != prompts.English {
+			return fmt.Errorf("action %s: unknown prompt language %q", a.Name, lang)
+		}
+		for _, text := range texts {
+			if strings.TrimSpace(text) == "" {
+				return fmt.Errorf("action %s: empty %s prompt", a.Name, lang)
+			}
+		}
+		prompted = prompted || len(texts) > 0
+	}
+	if !prompted && !builtinActions[a.Name] {
+		return fmt.Errorf("action %s: prompts are required for a new action", a.Name)
+	}
+	return nil
+}
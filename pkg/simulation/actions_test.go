@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestScenarioActions_PerRoleCatalog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	write := func(text string) {
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatalf("write scenario: %v", err)
+		}
+	}
+	write(`{"actions": [
+		{"name": "replicate_experiment", "base": 0.1, "traits": {"rigor": 0.5},
+		 "prompts": {"en": ["Pick a reported result and try to replicate it."]}},
+		{"name": "replicate_experiment", "roles": ["builder"], "base": 2,
+		 "prompts": {"en": ["Rebuild the experiment from the paper's methods section."]}},
+		{"name": "post", "roles": ["reviewer"], "base": 0}
+	]}`)
+	sc, err := LoadScenario(path)
+	if err != nil {
+		t.Fatalf("LoadScenario: %v", err)
+	}
+
+	explorer := &types.Persona{ID: "a1", Role: types.RoleExplorer, Rigor: 0.8}
+	weights := buildActionWeights(explorer, sc.roleActions(explorer.Role))
+	if w := weights["replicate_experiment"]; w < 0.5*0.8 || w > 0.5*1.2 {
+		t.Errorf("explorer replicate weight = %.3f, want 0.5 before jitter", w)
+	}
+	if weights["post"] <= 0 || len(weights) != len(builtinActions)+1 {
+		t.Errorf("explorer weights = %v", weights)
+	}
+	builder := &types.Persona{ID: "a2", Role: types.RoleBuilder, Rigor: 0.8}
+	if w := buildActionWeights(builder, sc.roleActions(builder.Role))["replicate_experiment"]; w < 1.6 {
+		t.Errorf("builder replicate weight = %.3f, want the role entry to win", w)
+	}
+	reviewer := &types.Persona{ID: "a3", Role: types.RoleReviewer}
+	if w := buildActionWeights(reviewer, sc.roleActions(reviewer.Role))["post"]; w != 0 {
+		t.Errorf("reviewer post weight = %.3f, want 0", w)
+	}
+
+	s := &ADKScheduler{scenario: sc}
+	en := &agentRunner{persona: builder, lang: prompts.English}
+	if got := s.pickActionText(en, "replicate_experiment"); got != "Rebuild the experiment from the paper's methods section." {
+		t.Errorf("builder prompt = %q", got)
+	}
+	// Without Chinese prompts or a template, the other language is used.
+	zh := &agentRunner{persona: explorer, lang: prompts.Chinese}
+	if got := s.pickActionText(zh, "replicate_experiment"); got != "Pick a reported result and try to replicate it." {
+		t.Errorf("explorer zh prompt = %q", got)
+	}
+	if got := s.pickActionText(zh, "browse"); got == prompts.For(prompts.Chinese).Idle {
+		t.Errorf("built-in action lost its template prompts")
+	}
+
+	for _, bad := range []string{
+		`{"actions": [{"name": "Replicate", "base": 1, "prompts": {"en": ["x"]}}]}`,
+		`{"actions": [{"name": "sleep", "base": 1, "prompts": {"en": ["x"]}}]}`,
+		`{"actions": [{"name": "replicate", "base": 1}]}`,
+		`{"actions": [{"name": "replicate", "traits": {"charm": 1}, "prompts": {"en": ["x"]}}]}`,
+		`{"actions": [{"name": "replicate", "roles": ["wizard"], "prompts": {"en": ["x"]}}]}`,
+		`{"actions": [{"name": "replicate", "prompts": {"fr": ["x"]}}]}`,
+	} {
+		write(bad)
+		if _, err := LoadScenario(path); err == nil {
+			t.Errorf("expected %s to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package simulation
+
+import (
+	"sort"
+
+	"github.com/cpunion/sci-bot/pkg/site"
+)
+
+// activityFile is the activity index under the data root.
+const activityFile = "activity.json"
+
+// recordActivity folds this tick's community counts and active agents into
+// the activity index. Caller must hold s.mu.
+func (s *ADKScheduler) recordActivity(active map[string]bool) {
+	if s.activity == nil {
+		return
+	}
+	var totals site.ActivityTotals
+	if s.forum != nil {
+		totals.ForumThreads, totals.ForumComments = s.forum.Counts()
+	}
+	if s.journal != nil {
+		totals.JournalApproved, totals.JournalRejected, totals.JournalPending = s.journal.AcceptanceStats()
+	}
+	if s.workflow != nil {
+		totals.PendingReviews, totals.OpenConsensus = s.workflow.OpenCounts()
+	}
+
+	ids := make([]string, 0, len(active))
+	for id := range active {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	s.activity.Record(s.simTime, totals, ids)
+}
@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/site"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_RecordsDailyActivity(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	origin := time.Date(2026, 2, 1, 22, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       origin,
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		Scenario: &Scenario{LiteratureDrops: []LiteratureDrop{
+			{ID: "a", Hour: 1, Channel: "forum", Title: "A", Content: "a"},
+			{ID: "b", Hour: 3, Channel: "forum", Title: "B", Content: "b"},
+			{ID: "c", Hour: 4, Channel: "forum", Title: "C", Content: "c"},
+		}},
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Reader", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+	if err := sched.Checkpoint(); err != nil {
+		t.Fatalf("Checkpoint: %v", err)
+	}
+
+	idx, err := site.LoadActivityIndex(filepath.Join(tempDir, activityFile))
+	if err != nil {
+		t.Fatalf("LoadActivityIndex: %v", err)
+	}
+	if idx.Totals.ForumThreads != 3 {
+		t.Fatalf("expected 3 threads in totals, got %+v", idx.Totals)
+	}
+	today := idx.Day(idx.SimTime)
+	yesterday := idx.Day(idx.SimTime.AddDate(0, 0, -1))
+	if today.Day != "2026-02-02" || today.Posts != 2 || yesterday.Posts != 1 {
+		t.Fatalf("unexpected daily posts: today %+v, yesterday %+v", today, yesterday)
+	}
+	if len(today.ActiveAgents) != 1 || idx.ActiveSince(idx.SimTime.Add(-24*time.Hour)) != 1 {
+		t.Fatalf("expected agent-1 active, got %+v / %+v", today, idx.LastActive)
+	}
+}
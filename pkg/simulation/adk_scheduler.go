@@ -4,6 +4,7 @@ package simulation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -22,7 +23,18 @@ import (
 	"google.golang.org/genai"
 
 	pkgagent "github.com/cpunion/sci-bot/pkg/agent"
+	"github.com/cpunion/sci-bot/pkg/audit"
+	"github.com/cpunion/sci-bot/pkg/budget"
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/knowledge"
+	"github.com/cpunion/sci-bot/pkg/memory"
+	"github.com/cpunion/sci-bot/pkg/metrics"
+	"github.com/cpunion/sci-bot/pkg/prompts"
 	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/reputation"
+	"github.com/cpunion/sci-bot/pkg/site"
 	"github.com/cpunion/sci-bot/pkg/tools"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
@@ -40,6 +52,16 @@ type ADKScheduler struct {
 	workflow *publication.Workflow
 	dataPath string
 
+	// Shared axiom systems and theories under data/knowledge.
+	axioms   *knowledge.AxiomRegistry
+	theories *knowledge.TheoryRepository
+	// Virtual experiment results under data/experiments.
+	experiments *experiment.Store
+	// Research groups under data/groups.
+	groups *group.Store
+	// Sim time between prompts asking group members to coordinate (0 disables).
+	groupCheckIn time.Duration
+
 	// Configuration
 	model           model.LLM
 	modelForPersona func(*types.Persona) model.LLM
@@ -52,6 +74,106 @@ type ADKScheduler struct {
 	agentsPerTick   int
 	checkpointEvery int
 
+	// Concurrency
+	maxParallel        int
+	rateLimiter        *RateLimiter
+	providerForPersona func(*types.Persona) string
+	retry              RetryPolicy
+	breaker            *CircuitBreaker
+	// Set in batched mode: each tick's LLM requests are dispatched together.
+	batcher *turnBatcher
+
+	// Token accounting (optional)
+	budget *budget.Tracker
+	// tickUsage sums the model usage of the current (or last) tick's turns
+	// and dreams.
+	tickUsage tokenTotals
+	// Usage of the last liveWindow ticks, the total since start, and the
+	// last tick's turns, for /api/sim/live. See live.go.
+	usageWindow []tokenTotals
+	usageTotal  int
+	liveTurns   []LiveTurn
+
+	// Journal review cycles (disabled when reviewCycle <= 0)
+	reviewCycle       time.Duration
+	reviewersPerPaper int
+	nextReviewCutoff  time.Time
+	// Sim time an assigned review may be missing before editors nag.
+	editorNagAfter time.Duration
+	// When consensus requests are achieved.
+	consensus publication.ConsensusThresholds
+
+	// Per-agent reputation, refreshed after every tick.
+	reputation *reputation.Board
+
+	// Nightly memory consolidation when the bell rings.
+	dream bool
+	// Planner mode; see planner.go.
+	planner string
+	// Prompt token budgets; see context_budget.go.
+	contextBudget  int
+	contextBudgets map[string]int
+	// Background thread summarization; see summarizer.go.
+	summarizer         model.LLM
+	summarizerProvider string
+	summariesPerTick   int
+	summaryFailures    map[string]int // root post ID -> failed attempts
+	// Embeds agent memories for recall_memory.
+	embedder memory.Embedder
+
+	// Minimum sim time between liveness events for an idle agent (0 disables).
+	heartbeatEvery time.Duration
+
+	// Scripted exogenous events; drop times are relative to simOrigin.
+	scenario  *Scenario
+	simOrigin time.Time
+
+	// Persisted in sim_state.json; see ADKSchedulerConfig.RunID.
+	runID string
+	fork  *ForkOrigin
+
+	// Per-sim-day activity tallies behind /api/stats (nil without dataPath).
+	activity *site.ActivityIndex
+	// Weekly digests posted to r/meta (nil unless enabled); digestWeek is
+	// the sim week the next digest covers. See digest.go.
+	digests    *site.DigestIndex
+	digestWeek string
+	// Novelty, topic diversity and citation concentration per checkpoint
+	// (nil without dataPath). See emergence.go.
+	emergence *metrics.EmergenceIndex
+	// Audit trail of forum, journal and workflow mutations (nil without dataPath).
+	auditLog *audit.Log
+	// Spectator questions (nil unless enabled). See inbox.go.
+	inbox          *inbox.Inbox
+	inboxInterval  time.Duration
+	inboxQuestions int
+
+	// Population dynamics: retirement thresholds and replacement recruiting.
+	retireIdle       time.Duration
+	retireReputation float64
+	minTenure        time.Duration
+	recruit          func(retiree *types.Persona, roster []*types.Persona) *types.Persona
+	// Personas recruited during this run, in join order.
+	recruits []*types.Persona
+
+	metrics *schedulerMetrics
+
+	// Per-agent profile files merged into instructions (optional).
+	profilesDir string
+	// Prompt language setting; see ADKSchedulerConfig.Lang.
+	lang prompts.Mode
+	// Instruction and action prompt templates (nil: built-in).
+	templates *prompts.Templates
+
+	// Store changes fan out on the bus; the notifier turns them into
+	// mention, reply and review notices for the affected agents.
+	bus      *eventBus
+	notifier *notifier
+
+	// Sim calendar (nil: turn-count bells only) and the last seminar's thread.
+	calendar          *Calendar
+	lastSeminarThread string
+
 	// Stats
 	ticks       int
 	actionStats map[string]int
@@ -65,11 +187,45 @@ type agentRunner struct {
 	appName   string
 	session   session.Service
 	modelName string
+	provider  string
 
 	actionWeights  map[string]float64
 	turnCount      int
 	bellRung       bool
 	graceRemaining int
+	memory         *memory.Memory
+	// Set when the bell rings; cleared once the night's dream pass ran.
+	dreamPending bool
+	// Current plan in planner mode; nil until the first one is made.
+	plan *Plan
+
+	// Tick and sim time of the agent's last turn or heartbeat.
+	lastTick int
+	lastSeen time.Time
+
+	// Sim day of the last guaranteed review turn ("2006-01-02").
+	reviewDutyDay string
+	// Sim day of the last guaranteed editor triage turn.
+	editorDutyDay string
+	// Working day the agent last woke for (calendar runs only).
+	workday string
+
+	// Sim time the agent joined this run and of its last turn that called a
+	// tool; retirement checks measure tenure and inactivity from these.
+	joinedAt    time.Time
+	lastActedAt time.Time
+	// Retired agents are never scheduled again.
+	retired bool
+
+	// System instruction, reloaded from the agent's profile files.
+	instruction *agentInstruction
+	// Language of the agent's instruction, prompts and tool descriptions.
+	lang prompts.Lang
+
+	// Sim time of the agent's last group check-in prompt, and how many
+	// messages each of its groups had then.
+	groupCheckedInAt  time.Time
+	groupMessagesSeen map[string]int
 }
 
 // ADKSchedulerConfig configures the ADK scheduler.
@@ -86,6 +242,143 @@ type ADKSchedulerConfig struct {
 	AgentsPerTick   int
 	CheckpointEvery int
 	MaxOutputTokens int32
+
+	// MaxParallel caps how many selected agents run concurrently within a tick
+	// (<= 1 runs them sequentially).
+	MaxParallel int
+	// RateLimiter throttles model requests per provider (optional).
+	RateLimiter *RateLimiter
+	// ProviderForPersona returns the rate-limit key for an agent. Defaults to
+	// the model name when nil.
+	ProviderForPersona func(*types.Persona) string
+	// Retry retries model calls that fail with 429, 5xx or timeouts
+	// (zero: one attempt). Turns that still fail are logged with Failed set.
+	Retry RetryPolicy
+	// Breaker stops calling a provider that keeps failing (optional).
+	Breaker *CircuitBreaker
+	// BatchTurns runs every selected agent of a tick at once and holds their
+	// LLM requests until all of them are waiting, then dispatches the round
+	// together (one GenerateBatch call for models implementing BatchLLM,
+	// concurrent calls otherwise). Trades latency for throughput on large
+	// populations; MaxParallel is ignored.
+	BatchTurns bool
+
+	// Budget tracks token spend and enforces per-run and per-agent daily quotas (optional).
+	Budget *budget.Tracker
+
+	// ReviewCycle batches journal submissions at each cutoff (e.g. one sim
+	// week); decisions are due by the next cutoff and late ones escalate to
+	// the editor. 0 keeps instant review by whichever reviewer acts first.
+	ReviewCycle time.Duration
+	// ReviewersPerPaper is how many reviewers each batched submission gets (default 2).
+	ReviewersPerPaper int
+	// EditorNagAfter is how long an assigned review may be missing before
+	// editor agents are asked to remind the reviewer. 0 disables nagging.
+	EditorNagAfter time.Duration
+	// Consensus decides when a consensus request is achieved; zero fields
+	// take publication's defaults.
+	Consensus publication.ConsensusThresholds
+
+	// Dream runs an extra LLM pass when an agent's bell rings that compresses
+	// the day's daily log into core memory experiences and a new summary
+	// snapshot, replacing the truncated rolling agent_summary.
+	Dream bool
+	// Planner replaces independent action sampling on free turns with
+	// multi-step plans: PlannerHeuristic or PlannerLLM (which uses the
+	// Summarizer model when set, else the agent's own). Empty disables it.
+	Planner string
+	// ContextBudget is the estimated prompt token budget per LLM call:
+	// agent_summary is fitted to what the instruction leaves, keeping its
+	// newest entries, and oversized tool outputs are trimmed, oldest first.
+	// ContextBudgets overrides it per model name. 0 keeps the fixed
+	// 2000-character agent_summary.
+	ContextBudget  int
+	ContextBudgets map[string]int
+	// Summarizer is a (usually cheap) model the scheduler uses after each
+	// tick to summarize long forum threads whose cached summary is missing
+	// or stale, so get_thread_digest rarely asks agents to summarize. nil
+	// leaves summaries to agents' save_thread_summary calls.
+	Summarizer model.LLM
+	// SummarizerProvider names the summarizer's provider for rate limits
+	// and retries.
+	SummarizerProvider string
+	// SummariesPerTick caps background summaries per tick (default 2).
+	SummariesPerTick int
+	// WeeklyDigest has a communicator post a digest of each subreddit's top
+	// threads to r/meta whenever the sim clock enters a new week, indexed in
+	// digest.json for the site. Needs DataPath.
+	WeeklyDigest bool
+	// Embedder backs each agent's semantic memory and the recall_memory
+	// tool. Defaults to the local memory.HashEmbedder.
+	Embedder memory.Embedder
+
+	// HeartbeatEvery logs a low-rate "heartbeat" event for agents that were
+	// skipped, sleeping or budget-paused, at most once per this much sim time
+	// per agent. 0 disables heartbeats.
+	HeartbeatEvery time.Duration
+
+	// Scenario injects scripted literature drops (optional).
+	Scenario *Scenario
+	// Inbox is the spectator question queue (optional). Every InboxInterval
+	// of sim time (default one sim day) the InboxQuestions (default 1)
+	// most-voted approved questions are posted to the forum as the visitor
+	// account. See inbox.go.
+	Inbox          *inbox.Inbox
+	InboxInterval  time.Duration
+	InboxQuestions int
+	// SimOrigin is day 0 for scenario timing, normally the sim time of the
+	// run's first tick. Defaults to StartTime.
+	SimOrigin time.Time
+	// Calendar derives sleep and wake from sim time: working hours,
+	// weekends and a weekly seminar (optional). Without it agents sleep only
+	// when the turn limit rings their bell.
+	Calendar *Calendar
+	// StartTick is the number of ticks already run when resuming (from
+	// SimState.Ticks), so tick numbers continue across runs.
+	StartTick int
+
+	// RetireIdle retires agents that went this much sim time without a turn
+	// that called a tool. 0 disables.
+	RetireIdle time.Duration
+	// RetireReputation retires agents whose reputation score falls below
+	// this (usually negative) value. 0 disables.
+	RetireReputation float64
+	// MinTenure protects newly joined agents from retirement (default one
+	// sim week). Editors and moderators never retire.
+	MinTenure time.Duration
+	// Recruit returns a new persona to replace a retiree, given the retiree
+	// and everyone currently on the roster, keeping the community size
+	// stable. nil lets the community shrink.
+	Recruit func(retiree *types.Persona, roster []*types.Persona) *types.Persona
+
+	// Metrics receives tick, turn, LLM and store-size metrics (optional).
+	Metrics *metrics.Registry
+
+	// ProfilesDir holds per-agent profile files (<dir>/<agent id>/IDENTITY.md,
+	// SOUL.md, HEARTBEAT.md, USER.md, as written by cmd/gen_agent_profiles)
+	// that are appended to each agent's instruction. Changed files are
+	// re-read before every tick. Empty uses the built-in instruction only.
+	ProfilesDir string
+
+	// GroupCheckIn is how much sim time passes between prompts asking a
+	// research group member to catch up on its groups' channels and
+	// coordinate. 0 disables the prompts; the group tools stay available
+	// either way.
+	GroupCheckIn time.Duration
+
+	// RunID and Fork are persisted in sim_state.json so a run, and the
+	// run it was forked from, can be told apart (optional).
+	RunID string
+	Fork  *ForkOrigin
+
+	// Lang is the language of agent instructions, prompts and tool
+	// descriptions: zh (default), en, or mixed to split agents between
+	// the two. A persona's own Language takes precedence.
+	Lang prompts.Mode
+	// Prompts renders agent instructions and action prompts, e.g. loaded
+	// with prompts.LoadTemplates from a directory of overrides. nil uses
+	// the built-in templates.
+	Prompts *prompts.Templates
 }
 
 // NewADKScheduler creates a new ADK-based scheduler.
@@ -108,6 +401,11 @@ func NewADKScheduler(cfg ADKSchedulerConfig) *ADKScheduler {
 		startTime = time.Now()
 	}
 
+	simOrigin := cfg.SimOrigin
+	if simOrigin.IsZero() {
+		simOrigin = startTime
+	}
+
 	checkpointEvery := cfg.CheckpointEvery
 	if checkpointEvery <= 0 {
 		checkpointEvery = 1
@@ -128,7 +426,79 @@ func NewADKScheduler(cfg ADKSchedulerConfig) *ADKScheduler {
 		}
 	}
 
-	return &ADKScheduler{
+	var axioms *knowledge.AxiomRegistry
+	var theories *knowledge.TheoryRepository
+	var experiments *experiment.Store
+	var groups *group.Store
+	if cfg.DataPath != "" {
+		knowledgePath := filepath.Join(cfg.DataPath, "knowledge")
+		axioms = knowledge.NewAxiomRegistry(knowledgePath)
+		if err := axioms.Load(); err != nil {
+			log.Printf("Failed to load axiom systems: %v", err)
+		}
+		if err := axioms.RegisterDefaults(); err != nil {
+			log.Printf("Failed to register default axiom systems: %v", err)
+		}
+		theories = knowledge.NewTheoryRepository(knowledgePath, axioms)
+		if err := theories.Load(); err != nil {
+			log.Printf("Failed to load theories: %v", err)
+		}
+		experiments = experiment.NewStore(filepath.Join(cfg.DataPath, "experiments"))
+		if err := experiments.Load(); err != nil {
+			log.Printf("Failed to load experiments: %v", err)
+		}
+		groups = group.NewStore(filepath.Join(cfg.DataPath, "groups"))
+		if err := groups.Load(); err != nil {
+			log.Printf("Failed to load groups: %v", err)
+		}
+	}
+
+	if cfg.ReviewCycle > 0 && workflow != nil {
+		workflow.SetScheduledReview(true)
+	}
+	reviewersPerPaper := cfg.ReviewersPerPaper
+	if reviewersPerPaper <= 0 {
+		reviewersPerPaper = 2
+	}
+	embedder := cfg.Embedder
+	if embedder == nil {
+		embedder = memory.NewHashEmbedder(0)
+	}
+	var activity *site.ActivityIndex
+	var digests *site.DigestIndex
+	var emergence *metrics.EmergenceIndex
+	var auditLog *audit.Log
+	if cfg.DataPath != "" {
+		auditLog = audit.NewLog(filepath.Join(cfg.DataPath, audit.FileName))
+		var err error
+		activity, err = site.LoadActivityIndex(filepath.Join(cfg.DataPath, activityFile))
+		if err != nil {
+			log.Printf("Failed to load activity index: %v", err)
+			activity = site.NewActivityIndex()
+		}
+		emergence, err = metrics.LoadEmergenceIndex(filepath.Join(cfg.DataPath, emergenceFile))
+		if err != nil {
+			log.Printf("Failed to load emergence index: %v", err)
+			emergence = metrics.NewEmergenceIndex()
+		}
+		if cfg.WeeklyDigest {
+			digests, err = site.LoadDigestIndex(filepath.Join(cfg.DataPath, digestFile))
+			if err != nil {
+				log.Printf("Failed to load digest index: %v", err)
+				digests = site.NewDigestIndex()
+			}
+		}
+	}
+	minTenure := cfg.MinTenure
+	if minTenure <= 0 {
+		minTenure = 7 * 24 * time.Hour
+	}
+	var batcher *turnBatcher
+	if cfg.BatchTurns {
+		batcher = newTurnBatcher()
+	}
+
+	s := &ADKScheduler{
 		runners:         make(map[string]*agentRunner),
 		dataPath:        cfg.DataPath,
 		model:           cfg.Model,
@@ -138,34 +508,149 @@ func NewADKScheduler(cfg ADKSchedulerConfig) *ADKScheduler {
 		graceTurns:      graceTurns,
 		logger:          cfg.Logger,
 		simTime:         startTime,
+		ticks:           cfg.StartTick,
 		simStep:         simStep,
 		agentsPerTick:   maxInt(cfg.AgentsPerTick, 1),
 		checkpointEvery: checkpointEvery,
 		workflow:        workflow,
+		axioms:          axioms,
+		theories:        theories,
+		experiments:     experiments,
+		groups:          groups,
 		actionStats:     make(map[string]int),
-	}
+
+		maxParallel:        maxInt(cfg.MaxParallel, 1),
+		rateLimiter:        cfg.RateLimiter,
+		providerForPersona: cfg.ProviderForPersona,
+		retry:              cfg.Retry,
+		breaker:            cfg.Breaker,
+		batcher:            batcher,
+		budget:             cfg.Budget,
+		reviewCycle:        cfg.ReviewCycle,
+		reviewersPerPaper:  reviewersPerPaper,
+		reputation:         reputation.NewBoard(),
+		dream:              cfg.Dream,
+		planner:            cfg.Planner,
+		contextBudget:      cfg.ContextBudget,
+		contextBudgets:     cfg.ContextBudgets,
+		summarizer:         cfg.Summarizer,
+		summarizerProvider: cfg.SummarizerProvider,
+		summariesPerTick:   cfg.SummariesPerTick,
+		summaryFailures:    make(map[string]int),
+		embedder:           embedder,
+		heartbeatEvery:     cfg.HeartbeatEvery,
+		editorNagAfter:     cfg.EditorNagAfter,
+		consensus:          cfg.Consensus,
+		scenario:           cfg.Scenario,
+		simOrigin:          simOrigin,
+		activity:           activity,
+		digests:            digests,
+		emergence:          emergence,
+		auditLog:           auditLog,
+		inbox:              cfg.Inbox,
+		inboxInterval:      cfg.InboxInterval,
+		inboxQuestions:     cfg.InboxQuestions,
+		retireIdle:         cfg.RetireIdle,
+		retireReputation:   cfg.RetireReputation,
+		minTenure:          minTenure,
+		recruit:            cfg.Recruit,
+		metrics:            newSchedulerMetrics(cfg.Metrics),
+		profilesDir:        cfg.ProfilesDir,
+		lang:               cfg.Lang,
+		templates:          cfg.Prompts,
+		groupCheckIn:       cfg.GroupCheckIn,
+		runID:              cfg.RunID,
+		fork:               cfg.Fork,
+		calendar:           cfg.Calendar,
+		bus:                newEventBus(),
+		notifier:           newNotifier(),
+	}
+	s.bus.subscribe(busForumPublish, s.notifier.onPublish)
+	s.bus.subscribe(busReviewAdded, s.notifier.onReview)
+	s.bus.subscribe(busDecision, s.notifier.onDecision)
+	s.bus.subscribe(busRetraction, s.logRetraction)
+	s.bus.subscribe(busConsensus, s.notifier.onConsensus)
+	s.bus.subscribe(busConsensus, s.logConsensus)
+	// Tool calls run while RunTick holds s.mu, and the clock only moves
+	// between turns, so the audit clock reads it without locking.
+	auditLog.SetClock(func() (time.Time, int) { return s.simTime, s.ticks })
+	s.watchWorkflow(workflow)
+	return s
 }
 
 // SetJournal sets the journal for publication.
 func (s *ADKScheduler) SetJournal(journal *publication.Journal) {
 	s.journal = journal
+	if journal != nil {
+		journal.SetAuditLog(s.auditLog)
+		journal.OnRetract(func(pub *types.Publication) {
+			s.bus.publish(busEvent{kind: busRetraction, publication: pub})
+		})
+	}
 }
 
 // SetForum sets the forum for publication.
 func (s *ADKScheduler) SetForum(forum *publication.Forum) {
 	s.forum = forum
+	if forum != nil {
+		forum.OnVote(s.applyVoteKarma)
+		// Votes are cast inside RunTick, which holds s.mu.
+		forum.SetClock(func() time.Time { return s.simTime })
+		forum.SetAuditLog(s.auditLog)
+		forum.OnPublish(func(pub *types.Publication) {
+			s.bus.publish(busEvent{kind: busForumPublish, publication: pub})
+		})
+		s.notifier.forum = forum.Get
+	}
+}
+
+// applyVoteKarma updates author and voter karma after a forum vote.
+// Votes are cast from tool calls inside RunTick, which already holds s.mu.
+func (s *ADKScheduler) applyVoteKarma(change types.VoteChange) {
+	if author, ok := s.runners[change.AuthorID]; ok {
+		author.state.ApplyReceivedVote(change)
+	}
+	if voter, ok := s.runners[change.VoterID]; ok {
+		voter.state.ApplyGivenVote(change)
+	}
 }
 
 // SetWorkflow sets the workflow store.
 func (s *ADKScheduler) SetWorkflow(workflow *publication.Workflow) {
 	s.workflow = workflow
+	s.watchWorkflow(workflow)
+	if s.reviewCycle > 0 && workflow != nil {
+		workflow.SetScheduledReview(true)
+	}
+}
+
+// watchWorkflow attaches the audit log and publishes new reviews on the bus.
+func (s *ADKScheduler) watchWorkflow(workflow *publication.Workflow) {
+	if workflow == nil {
+		return
+	}
+	workflow.SetAuditLog(s.auditLog)
+	workflow.OnReview(func(sub *types.Submission, review *types.PaperReview) {
+		s.bus.publish(busEvent{kind: busReviewAdded, submission: sub, review: review})
+	})
+	workflow.OnDecision(func(sub *types.Submission) {
+		s.bus.publish(busEvent{kind: busDecision, submission: sub})
+	})
+	workflow.SetConsensusThresholds(s.consensus)
+	workflow.OnConsensusAchieved(func(req *types.ConsensusRequest) {
+		s.bus.publish(busEvent{kind: busConsensus, consensus: req})
+	})
 }
 
 // AddAgent adds an agent to the scheduler.
 func (s *ADKScheduler) AddAgent(ctx context.Context, persona *types.Persona) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.addAgentLocked(ctx, persona)
+}
 
+// addAgentLocked is AddAgent for callers already holding s.mu.
+func (s *ADKScheduler) addAgentLocked(ctx context.Context, persona *types.Persona) error {
 	modelForAgent := s.resolveModel(persona)
 	if modelForAgent == nil {
 		return fmt.Errorf("no LLM model configured for agent %s", persona.ID)
@@ -182,11 +667,35 @@ func (s *ADKScheduler) AddAgent(ctx context.Context, persona *types.Persona) err
 	if state.AgentName != "" && persona.Name != state.AgentName {
 		persona.Name = state.AgentName
 	}
+	// Traits drift with outcomes; resume from where they drifted to.
+	if rec, ok := state.GetTraits(); ok {
+		persona.SetTraits(rec.Current)
+	}
+
+	mem := memory.NewMemory(persona.ID, agentPath, 0)
+	mem.EnableSemantic(s.embedder)
+	if err := mem.Load(); err != nil {
+		log.Printf("Failed to load memory for %s: %v", persona.Name, err)
+	}
 
 	// Create tools
 	forumToolset := tools.NewForumToolset(s.forum, persona.ID, persona, state)
+	forumToolset.SetReputationSource(s.reputation.Normalized)
+	forumToolset.SetTheoryRepository(s.theories)
+	forumToolset.SetExperimentStore(s.experiments)
+	if rep, ok := state.GetReputation(); ok {
+		s.reputation.Set(persona.ID, rep)
+	}
 	socialToolset := tools.NewSocialToolset(state, persona.ID)
 	publicationToolset := tools.NewPublicationToolset(s.workflow, s.journal, s.forum, persona, s.dataPath)
+	publicationToolset.SetExperimentStore(s.experiments)
+	// Tools run inside RunTick, which holds s.mu.
+	publicationToolset.SetAgentLookup(func(id string) *types.Persona {
+		if ar := s.runners[id]; ar != nil {
+			return ar.persona
+		}
+		return nil
+	})
 
 	forumTools, err := forumToolset.AllTools(persona.Name)
 	if err != nil {
@@ -203,16 +712,85 @@ func (s *ADKScheduler) AddAgent(ctx context.Context, persona *types.Persona) err
 		return fmt.Errorf("failed to create publication tools: %w", err)
 	}
 
+	memoryTools, err := tools.NewMemoryToolset(mem, func() time.Time { return s.simTime }).AllTools()
+	if err != nil {
+		return fmt.Errorf("failed to create memory tools: %w", err)
+	}
+
+	agendaTools, err := tools.NewAgendaToolset(state, func() time.Time { return s.simTime }).AllTools()
+	if err != nil {
+		return fmt.Errorf("failed to create agenda tools: %w", err)
+	}
+
+	// Editor tools run inside RunTick, which holds s.mu while reading runners
+	// and the sim clock.
+	editorToolset := tools.NewEditorToolset(s.workflow, s.journal, persona, s.reviewerPersonas,
+		func() time.Time { return s.simTime }, s.editorNagAfter)
+	editorTools, err := editorToolset.AllTools()
+	if err != nil {
+		return fmt.Errorf("failed to create editor tools: %w", err)
+	}
+
 	allTools := append(forumTools, socialTools...)
 	allTools = append(allTools, publicationTools...)
+	allTools = append(allTools, memoryTools...)
+	allTools = append(allTools, agendaTools...)
+	allTools = append(allTools, editorTools...)
+
+	if s.theories != nil {
+		knowledgeTools, err := tools.NewKnowledgeToolset(s.axioms, s.theories, persona).AllTools()
+		if err != nil {
+			return fmt.Errorf("failed to create knowledge tools: %w", err)
+		}
+		allTools = append(allTools, knowledgeTools...)
+	}
+	if s.groups != nil {
+		groupTools, err := tools.NewGroupToolset(s.groups, s.workflow, persona,
+			func() time.Time { return s.simTime }).AllTools()
+		if err != nil {
+			return fmt.Errorf("failed to create group tools: %w", err)
+		}
+		allTools = append(allTools, groupTools...)
+	}
+	if s.experiments != nil {
+		experimentTools, err := tools.NewExperimentToolset(s.experiments, s.theories, persona).AllTools()
+		if err != nil {
+			return fmt.Errorf("failed to create experiment tools: %w", err)
+		}
+		allTools = append(allTools, experimentTools...)
+	}
+
+	lang := s.lang.For(persona)
+	allTools = prompts.LocalizeTools(lang, allTools)
 
 	// Create LLM agent
-	instruction := buildInstruction(persona)
+	instruction := &agentInstruction{
+		base:    s.templates.Instruction(lang, persona),
+		heading: prompts.For(lang).ProfileHeading,
+	}
+	if budget := s.contextBudgetFor(modelForAgent.Name()); budget > 0 {
+		instruction.budget = budget
+		instruction.reserve = budget/4 + int(s.maxOutputTokens)
+	}
+	if s.profilesDir != "" {
+		if _, err := instruction.reload(filepath.Join(s.profilesDir, persona.ID)); err != nil {
+			log.Printf("Failed to load profile for %s: %v", persona.Name, err)
+		}
+	}
+	provider := s.providerFor(persona, modelForAgent)
+	agentModel := modelForAgent
+	if s.batcher != nil {
+		agentModel = &batchedModel{inner: modelForAgent, batcher: s.batcher}
+	}
+	if instruction.budget > 0 {
+		agentModel = &budgetModel{inner: agentModel, budget: instruction.budget - int(s.maxOutputTokens)}
+	}
+	agentModel = s.withRetry(agentModel, provider)
 	adkAgent, err := llmagent.New(llmagent.Config{
-		Name:        persona.ID,
-		Model:       modelForAgent,
-		Description: fmt.Sprintf("%s - %s", persona.Name, persona.Role),
-		Instruction: instruction,
+		Name:                persona.ID,
+		Model:               agentModel,
+		Description:         fmt.Sprintf("%s - %s", persona.Name, persona.Role),
+		InstructionProvider: instruction.provide,
 		// Avoid unbounded prompt growth from long multi-tick chat history. The
 		// agent keeps long-term context via `agent_summary` instead.
 		IncludeContents: llmagent.IncludeContentsNone,
@@ -244,13 +822,17 @@ func (s *ADKScheduler) AddAgent(ctx context.Context, persona *types.Persona) err
 		UserID:    persona.ID,
 		SessionID: persona.ID + "-session",
 		State: map[string]any{
-			"agent_summary": "",
+			"agent_summary": memorySummary(mem, lang),
 		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
+	_, retired := state.GetRetirement()
+	if !retired {
+		s.notifier.register(persona)
+	}
 	s.runners[persona.ID] = &agentRunner{
 		persona:        persona,
 		state:          state,
@@ -259,15 +841,41 @@ func (s *ADKScheduler) AddAgent(ctx context.Context, persona *types.Persona) err
 		appName:        "sci-bot",
 		session:        sessionService,
 		modelName:      modelForAgent.Name(),
-		actionWeights:  buildActionWeights(persona),
+		provider:       provider,
+		actionWeights:  buildActionWeights(persona, s.scenario.roleActions(persona.Role)),
 		graceRemaining: s.graceTurns,
+		memory:         mem,
 		bellRung:       false,
 		turnCount:      0,
+		lastTick:       s.ticks,
+		lastSeen:       s.simTime,
+		joinedAt:       s.simTime,
+		lastActedAt:    s.simTime,
+		retired:        retired,
+		instruction:    instruction,
+		lang:           lang,
+
+		groupCheckedInAt: s.simTime,
 	}
 
 	return nil
 }
 
+// providerFor returns the rate-limit and circuit breaker key for an agent.
+func (s *ADKScheduler) providerFor(persona *types.Persona, llm model.LLM) string {
+	if s.providerForPersona != nil {
+		if p := s.providerForPersona(persona); p != "" {
+			return p
+		}
+	}
+	return llm.Name()
+}
+
+// withRetry wraps llm with the retry policy, circuit breaker and rate limit.
+func (s *ADKScheduler) withRetry(llm model.LLM, provider string) model.LLM {
+	return &retryModel{inner: llm, provider: provider, policy: s.retry, breaker: s.breaker, limiter: s.rateLimiter, sleep: sleepContext}
+}
+
 func (s *ADKScheduler) resolveModel(persona *types.Persona) model.LLM {
 	if s.modelForPersona != nil {
 		if m := s.modelForPersona(persona); m != nil {
@@ -277,120 +885,29 @@ func (s *ADKScheduler) resolveModel(persona *types.Persona) model.LLM {
 	return s.model
 }
 
-func buildInstruction(persona *types.Persona) string {
-	roleGuidance := ""
-	switch persona.Role {
-	case types.RoleReviewer:
-		roleGuidance = "作为专业审稿人，请重点评估论证是否自洽、证据是否充分、结论是否过度外推，并给出可操作的改进建议。"
-	case types.RoleBuilder:
-		roleGuidance = "作为严谨构建者，请关注定义清晰、推导步骤完整、可证伪性与可复现性。"
-	case types.RoleExplorer:
-		roleGuidance = "作为探索者，请提出新颖假设、跨领域联想，并清楚标注不确定性。"
-	case types.RoleSynthesizer:
-		roleGuidance = "作为综合者，请连接不同领域观点，指出潜在统一框架与冲突点。"
-	case types.RoleCommunicator:
-		roleGuidance = "作为传播者，请将复杂观点转化为清晰易懂的解释，并保持准确性。"
-	}
-
-	roleBlock := ""
-	if roleGuidance != "" {
-		roleBlock = fmt.Sprintf("\n\n## 角色要求\n%s", roleGuidance)
-	}
-
-	return fmt.Sprintf(`你是 %s，一位科学探索者。
-
-## 你的性格
-- 角色: %s
-- 思维方式: %s
-- 创造力: %.0f%%
-- 风险承受能力: %.0f%%
-- 研究领域: %v
-
-## 你的能力
-你可以使用以下工具与科学社区互动：
-
-### 论坛工具
-- browse_forum: 浏览论坛帖子（按热度或时间排序，可选板块筛选）
-- read_post: 阅读帖子详情和树形评论（含 parent_id 与 depth，可用于理解讨论层级）
-- get_thread_digest: 多帖汇总专用：线程摘要+摘要后的新回复（如无摘要会提示 needs_summary）
-- save_thread_summary: 保存线程摘要缓存（仅在你完成该线程总结后调用）
-- browse_mentions: 查看与你相关的 @ 提及或回复，优先处理
-- create_post: 发表新帖子（需要标题、内容和板块）
-- vote: 对帖子投票（upvote 或 downvote）
-- comment: 发表评论或回复评论（使用 parent_id 回复某条评论，否则用 post_id 回复顶层）
-
-### 发表工具
-- assess_readiness: 评估个人想法成熟度
-- assess_consensus: 评估论坛线程共识成熟度
-- create_draft: 创建学术草案（idea 或 collaborative）
-- request_consensus: 在论坛帖子下发起共识请求（自动发布评论）
-- submit_paper: 提交草案到期刊审稿
-- review_paper: 对投稿进行审稿（Reviewer 角色）
-
-### 社交工具
-- view_relationships: 查看与其他科学家的关系
-- update_trust: 更新对某人的信任度
-- view_knowledge: 查看已掌握的知识
-
-## 行为准则
-1. 以科学家的身份参与讨论
-2. 发表有价值、有深度的观点
-3. 尊重其他科学家，但敢于质疑
-4. 建立有意义的学术关系
-5. 持续学习和分享知识
-
-## 发言规则
-- 只有在被点名、能提供新见解/证据、纠错或总结时才发言
-- 如果没有增量贡献，请简短说明继续观察
-- 若被 @ 提及或有人回复你，请优先处理
-
-## 阅读规则
-- 单个帖子内分析请使用 read_post，不要做摘要
-- 多帖汇总时优先使用 get_thread_digest
-- 若 needs_summary=true，请逐帖 read_post 后总结，并调用 save_thread_summary 记录
-
-## 写作规范（草案/论文）
-- 当你准备调用 create_draft / submit_paper 时，请输出完整、结构化的 Markdown 论文文本，不要只写几段短想法
-- 建议结构（可按需删减，但需保证论证闭环）：
-  - Abstract（问题、方法、贡献、关键结论）
-  - Introduction（动机、问题定义、贡献列表）
-  - Background / Related Work（引用相关 forum thread/post id，说明差异）
-  - Method / Theory（定义、符号表、关键方程、假设边界）
-  - Predictions & Verification Plan（至少 3 条可证伪预测；给出实验/模拟步骤、指标、预期观察）
-  - Failure Modes / Limitations（至少 1 条明确失败模式；避免过度外推）
-  - Discussion / Future Work（下一步怎么验证、怎么扩展）
-  - References（可引用社区内部讨论，如 forum-... / seed-...）
-- 写作前：先 read_post 获取关键上下文；不要凭空杜撰他人的观点或实验结果
-
-## 创新导向
-- 在科研相关问题上主动创新、提出新假设或改进建议
-- 非科研话题可根据个人性格自由选择参与方式
-
-## 摘要记忆（单条滚动沉淀）
-{agent_summary?}
-
-## 作息规则
-- 当出现“晚钟/敲钟/夜间休息”的提示时，需礼貌收尾并立即休息，不再展开新话题。%s`,
-		persona.Name,
-		persona.Role,
-		persona.ThinkingStyle,
-		persona.Creativity*100,
-		persona.RiskTolerance*100,
-		persona.Domains,
-		roleBlock,
-	)
-}
-
 // RunTick runs a single simulation tick.
 func (s *ADKScheduler) RunTick(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.observeTick(time.Now())
 
 	s.ticks++
+	s.tickUsage = tokenTotals{}
+	s.liveTurns = nil
+	defer s.closeTickUsage()
+	s.reloadProfilesLocked()
+	s.advanceReviewCycles()
+	s.injectLiteratureDrops()
+	s.postVisitorQuestions()
+	s.wakeAgents()
 
 	// Select random eligible agent
 	ids := s.eligibleAgentIDs()
 	if len(ids) == 0 {
+		s.emitHeartbeats(nil)
+		s.recordActivity(nil)
+		// Keep the clock moving so budget-exhausted agents wake on the next day.
+		s.simTime = s.simTime.Add(s.simStep)
 		return nil
 	}
 
@@ -398,6 +915,14 @@ func (s *ADKScheduler) RunTick(ctx context.Context) error {
 	if perTick <= 0 {
 		perTick = 1
 	}
+	var seminar *types.Publication
+	if s.calendar.seminarDue(s.simTime, s.simStep) {
+		if seminar = s.seminarThread(); seminar != nil {
+			s.lastSeminarThread = seminar.ID
+			perTick = len(ids)
+			log.Printf("[Tick %d] Weekly seminar on %s %q with %d agents", s.ticks, seminar.ID, seminar.Title, perTick)
+		}
+	}
 	if perTick > len(ids) {
 		perTick = len(ids)
 	}
@@ -405,75 +930,67 @@ func (s *ADKScheduler) RunTick(ctx context.Context) error {
 	rand.Shuffle(len(ids), func(i, j int) {
 		ids[i], ids[j] = ids[j], ids[i]
 	})
+	s.prioritizeDuties(ids)
+	if seminar == nil {
+		s.makePlans(ctx, ids[:perTick])
+	}
 
+	// Pick prompts up front: action selection mutates per-agent counters and
+	// shared stats, so it stays on the scheduler goroutine.
+	turns := make([]*agentTurn, 0, perTick)
 	for _, selectedID := range ids[:perTick] {
 		ar := s.runners[selectedID]
 		if ar == nil {
 			continue
 		}
-		// Generate a prompt based on random action
-		prompt := s.selectActionPrompt(ar)
+		var prompt actionPrompt
+		if seminar != nil && !ar.bellRung {
+			ar.turnCount++
+			prompt = seminarPrompt(seminar, ar.lang)
+		} else {
+			prompt = s.selectActionPrompt(ar)
+		}
 		s.actionStats[prompt.action]++
-
 		log.Printf("[Tick %d] %s: %s", s.ticks, ar.persona.Name, prompt.action)
+		turns = append(turns, &agentTurn{runner: ar, prompt: prompt})
+	}
 
-		// Run the agent
-		msg := &genai.Content{
-			Role: "user",
-			Parts: []*genai.Part{
-				{Text: prompt.text},
-			},
-		}
+	s.runTurns(ctx, turns)
+	s.recordLiveTurns(turns)
 
-		var responseText string
-		toolCalls := make([]string, 0)
-		toolResponses := make([]string, 0)
-		usage := tokenTotals{}
-		runErrText := ""
-		for event, err := range ar.runner.Run(ctx, ar.persona.ID, ar.sessionID, msg, agent.RunConfig{}) {
-			if err != nil {
-				log.Printf("Agent error: %v", err)
-				if runErrText == "" {
-					runErrText = err.Error()
-				}
-				continue
-			}
-			if event != nil && event.UsageMetadata != nil {
-				usage.UsageEvents++
-				usage.PromptTokens += int(event.UsageMetadata.PromptTokenCount)
-				usage.CandidatesTokens += int(event.UsageMetadata.CandidatesTokenCount)
-				usage.ThoughtsTokens += int(event.UsageMetadata.ThoughtsTokenCount)
-				usage.ToolUsePromptTokens += int(event.UsageMetadata.ToolUsePromptTokenCount)
-				usage.CachedContentTokens += int(event.UsageMetadata.CachedContentTokenCount)
-				if event.UsageMetadata.TotalTokenCount > 0 {
-					usage.TotalTokens += int(event.UsageMetadata.TotalTokenCount)
-				} else {
-					usage.TotalTokens += int(event.UsageMetadata.PromptTokenCount +
-						event.UsageMetadata.CandidatesTokenCount +
-						event.UsageMetadata.ToolUsePromptTokenCount +
-						event.UsageMetadata.ThoughtsTokenCount)
-				}
-			}
-			if event != nil && event.Content != nil {
-				for _, part := range event.Content.Parts {
-					if part.Text != "" {
-						log.Printf("  [%s] %s", ar.persona.Name, truncate(part.Text, 100))
-						responseText += part.Text
-					}
-					if part.FunctionCall != nil {
-						log.Printf("  [%s] Calling: %s", ar.persona.Name, part.FunctionCall.Name)
-						toolCalls = append(toolCalls, part.FunctionCall.Name)
-					}
-					if part.FunctionResponse != nil {
-						toolResponses = append(toolResponses, part.FunctionResponse.Name)
-					}
-				}
-			}
+	active := make(map[string]bool, len(turns))
+	for _, t := range turns {
+		active[t.runner.persona.ID] = true
+		t.runner.lastTick = s.ticks
+		t.runner.lastSeen = s.simTime
+		if len(t.toolCalls) > 0 {
+			t.runner.lastActedAt = s.simTime
 		}
+	}
 
-		s.updateAgentSummary(ctx, ar, prompt.text, responseText, runErrText)
-		s.logEvent(ar, prompt, responseText, runErrText, toolCalls, toolResponses, usage)
+	// Summaries and logs are written in selection order so log output stays
+	// deterministic regardless of which agent finished first.
+	for _, t := range turns {
+		s.recordUsage(t.runner.persona.ID, t.usage)
+		errText, errKind := t.failure()
+		s.updateAgentSummary(ctx, t.runner, t.prompt.text, t.responseText, errText, errKind)
+		s.logEvent(t.runner, t.prompt, t.responseText, t.err, t.toolCalls, t.toolResponses, t.outcomes, t.usage)
+		s.metrics.observeTurn(t)
+		if t.runner.dreamPending {
+			t.runner.dreamPending = false
+			s.consolidateMemory(ctx, t.runner)
+		}
+	}
+	s.summarizeThreads(ctx)
+	s.postDigest()
+	if u := s.tickUsage; u.UsageEvents > 0 {
+		log.Printf("[Tick %d] Tokens: %d in %d calls (prompt=%d, candidates=%d, thoughts=%d, cached=%d)",
+			s.ticks, u.TotalTokens, u.UsageEvents, u.PromptTokens, u.CandidatesTokens, u.ThoughtsTokens, u.CachedContentTokens)
 	}
+	s.emitHeartbeats(active)
+	s.recordActivity(active)
+	s.refreshReputation()
+	s.updatePopulation(ctx)
 	s.simTime = s.simTime.Add(s.simStep)
 	if s.checkpointEvery > 0 && s.ticks%s.checkpointEvery == 0 {
 		if err := s.checkpointLocked(false); err != nil {
@@ -487,32 +1004,165 @@ func (s *ADKScheduler) RunTick(ctx context.Context) error {
 type actionPrompt struct {
 	action string
 	text   string
+	// plan is set on plan events; planStep (1-based) on turns taking a
+	// step of the agent's plan.
+	plan     *Plan
+	planStep int
+}
+
+// agentTurn holds the input and collected output of one agent run in a tick.
+type agentTurn struct {
+	runner *agentRunner
+	prompt actionPrompt
+
+	responseText  string
+	errText       string
+	err           error // what failed the turn, behind errText
+	toolCalls     []string
+	toolResponses []string
+	outcomes      []ToolOutcome
+	callIDs       []string // parallel to outcomes
+	usage         tokenTotals
+}
+
+// fail records the error that failed the turn.
+func (t *agentTurn) fail(err error) {
+	t.err = err
+	t.errText = err.Error()
+}
+
+// runTurns executes the selected agents, up to maxParallel at a time.
+func (s *ADKScheduler) runTurns(ctx context.Context, turns []*agentTurn) {
+	if s.batcher != nil {
+		s.runBatchedTurns(ctx, turns)
+		return
+	}
+	parallel := s.maxParallel
+	if parallel <= 1 || len(turns) <= 1 {
+		for _, t := range turns {
+			s.runAgentTurn(ctx, t)
+		}
+		return
+	}
+
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for _, t := range turns {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t *agentTurn) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.runAgentTurn(ctx, t)
+		}(t)
+	}
+	wg.Wait()
+}
+
+// runBatchedTurns starts every turn at once so the batcher can collect each
+// round of LLM requests from all of them.
+func (s *ADKScheduler) runBatchedTurns(ctx context.Context, turns []*agentTurn) {
+	s.batcher.begin(len(turns))
+	var wg sync.WaitGroup
+	for _, t := range turns {
+		wg.Add(1)
+		go func(t *agentTurn) {
+			defer wg.Done()
+			defer s.batcher.leave()
+			s.runAgentTurn(ctx, t)
+		}(t)
+	}
+	wg.Wait()
+}
+
+// runAgentTurn runs one agent against its prompt and records the outcome in t.
+// It must not touch scheduler state other than the agent's own runner.
+func (s *ADKScheduler) runAgentTurn(ctx context.Context, t *agentTurn) {
+	ar := t.runner
+	msg := &genai.Content{
+		Role: "user",
+		Parts: []*genai.Part{
+			{Text: t.prompt.text},
+		},
+	}
+
+	t.toolCalls = make([]string, 0)
+	t.toolResponses = make([]string, 0)
+	for event, err := range ar.runner.Run(ctx, ar.persona.ID, ar.sessionID, msg, agent.RunConfig{}) {
+		if err != nil {
+			log.Printf("Agent error: %v", err)
+			if t.errText == "" {
+				t.fail(err)
+			}
+			continue
+		}
+		if event != nil && event.UsageMetadata != nil {
+			t.usage.add(event.UsageMetadata)
+		}
+		if event != nil && event.Content != nil {
+			for _, part := range event.Content.Parts {
+				if part.Text != "" {
+					log.Printf("  [%s] %s", ar.persona.Name, truncate(part.Text, 100))
+					t.responseText += part.Text
+				}
+				if part.FunctionCall != nil {
+					log.Printf("  [%s] Calling: %s", ar.persona.Name, part.FunctionCall.Name)
+					t.toolCalls = append(t.toolCalls, part.FunctionCall.Name)
+					t.recordCall(part.FunctionCall)
+				}
+				if part.FunctionResponse != nil {
+					t.toolResponses = append(t.toolResponses, part.FunctionResponse.Name)
+					t.recordResponse(part.FunctionResponse)
+				}
+			}
+		}
+	}
+	t.finishOutcomes()
 }
 
 func (s *ADKScheduler) selectActionPrompt(ar *agentRunner) actionPrompt {
 	if ar == nil {
-		return actionPrompt{action: "idle", text: "请保持待命。"}
+		return actionPrompt{action: "idle", text: prompts.For(prompts.Chinese).Idle}
 	}
+	text := prompts.For(ar.lang)
 
-	if ar.turnCount >= s.turnLimit {
+	if ar.turnCount >= s.turnLimit || s.shiftEnding() {
 		if !ar.bellRung {
 			ar.bellRung = true
 			ar.graceRemaining = s.graceTurns
+			ar.dreamPending = s.dream
 			ar.turnCount++
-			return actionPrompt{action: "sleep", text: "夜间敲钟：今天到此为止，请简短收尾并休息。"}
+			return actionPrompt{action: "sleep", text: text.BellFirst}
 		}
 		if ar.graceRemaining <= 0 {
-			return actionPrompt{action: "sleep", text: "夜已深，请立即休息，不再展开新话题。"}
+			return actionPrompt{action: "sleep", text: text.BellLate}
 		}
 		ar.graceRemaining--
 		ar.turnCount++
-		return actionPrompt{action: "sleep", text: "夜间敲钟已响，请礼貌结束并去休息。"}
+		return actionPrompt{action: "sleep", text: text.BellGrace}
 	}
 
-	action := weightedSelect(ar.actionWeights)
-	promptText := pickActionText(action)
+	if prompt, ok := s.reviewDutyPrompt(ar); ok {
+		ar.turnCount++
+		return prompt
+	}
+	if prompt, ok := s.editorDutyPrompt(ar); ok {
+		ar.turnCount++
+		return prompt
+	}
+	if prompt, ok := s.groupCheckInPrompt(ar); ok {
+		ar.turnCount++
+		return s.withNotifications(ar, prompt)
+	}
+	if prompt, ok := s.planStepPrompt(ar); ok {
+		ar.turnCount++
+		return s.withNotifications(ar, prompt)
+	}
+
+	action := weightedSelect(reputationWeights(ar.actionWeights, s.reputation.Normalized(ar.persona.ID)))
+	promptText := s.pickActionText(ar, action)
 	ar.turnCount++
-	return actionPrompt{action: action, text: promptText}
+	return s.withNotifications(ar, actionPrompt{action: action, text: promptText})
 }
 
 func truncate(s string, maxLen int) string {
@@ -531,19 +1181,28 @@ func maxInt(a, b int) int {
 
 func (s *ADKScheduler) eligibleAgentIDs() []string {
 	ids := make([]string, 0, len(s.runners))
+	if s.offDuty() {
+		return ids
+	}
 	for id, ar := range s.runners {
-		if ar == nil {
+		if ar == nil || ar.retired {
 			continue
 		}
 		if ar.bellRung && ar.graceRemaining <= 0 {
 			continue
 		}
+		// Agents that spent their daily token budget sleep until the next sim day.
+		if s.budget.AgentExhausted(id, s.simTime) {
+			continue
+		}
 		ids = append(ids, id)
 	}
 	return ids
 }
 
-func buildActionWeights(p *types.Persona) map[string]float64 {
+// buildActionWeights weighs the free-turn actions by the persona's traits;
+// scenario actions, applied in order, add to or replace the built-ins.
+func buildActionWeights(p *types.Persona, custom []ScenarioAction) map[string]float64 {
 	weights := map[string]float64{
 		"browse":   0.3,
 		"read":     0.3,
@@ -565,6 +1224,9 @@ func buildActionWeights(p *types.Persona) map[string]float64 {
 			weights["observe"] += 0.1
 		}
 	}
+	for _, a := range custom {
+		weights[a.Name] = a.weight(p)
+	}
 
 	for k, v := range weights {
 		jitter := 0.8 + rand.Float64()*0.4
@@ -603,42 +1265,19 @@ func weightedSelect(weights map[string]float64) string {
 	return "browse"
 }
 
-func pickActionText(action string) string {
-	switch action {
-	case "browse":
-		return pickOne([]string{
-			"请浏览论坛，看看有什么有趣的讨论。",
-			"查看最新的热门帖子。",
-			"看看与你研究领域相关的新讨论。",
-		})
-	case "read":
-		return pickOne([]string{
-			"找一篇有趣的帖子阅读并评论。",
-			"阅读一篇与你领域相关的帖子，给出简短反馈。",
-		})
-	case "post":
-		return pickOne([]string{
-			"在论坛发表一个你最近思考的科学问题。",
-			"发布一个简短的研究想法或假设，邀请讨论。",
-		})
-	case "interact":
-		return pickOne([]string{
-			"查看你的人际关系，并与一位科学家互动。",
-			"选择一位你信任的同行进行学术交流。",
-		})
-	case "review":
-		return pickOne([]string{
-			"阅读一篇帖子并投票。",
-			"对一篇讨论进行审慎评估，给出立场。",
-		})
-	case "observe":
-		return pickOne([]string{
-			"保持观察。如果没有新增贡献，请简短说明继续关注。",
-			"暂不发言，记录你认为重要的线索。",
-		})
-	default:
-		return "请保持待命。"
+func (s *ADKScheduler) pickActionText(ar *agentRunner, action string) string {
+	if texts := s.scenario.actionPrompts(ar.persona.Role, action, ar.lang); len(texts) > 0 {
+		return pickOne(texts)
 	}
+	if texts := s.templates.ActionTexts(ar.lang, action, ar.persona); len(texts) > 0 {
+		return pickOne(texts)
+	}
+	for _, lang := range []prompts.Lang{prompts.Chinese, prompts.English} {
+		if texts := s.scenario.actionPrompts(ar.persona.Role, action, lang); len(texts) > 0 {
+			return pickOne(texts)
+		}
+	}
+	return prompts.For(ar.lang).Idle
 }
 
 func pickOne(items []string) string {
@@ -658,7 +1297,40 @@ type tokenTotals struct {
 	TotalTokens         int
 }
 
-func (s *ADKScheduler) logEvent(ar *agentRunner, prompt actionPrompt, response, errText string, toolCalls, toolResponses []string, usage tokenTotals) {
+func (t *tokenTotals) add(md *genai.GenerateContentResponseUsageMetadata) {
+	t.UsageEvents++
+	t.PromptTokens += int(md.PromptTokenCount)
+	t.CandidatesTokens += int(md.CandidatesTokenCount)
+	t.ThoughtsTokens += int(md.ThoughtsTokenCount)
+	t.ToolUsePromptTokens += int(md.ToolUsePromptTokenCount)
+	t.CachedContentTokens += int(md.CachedContentTokenCount)
+	if md.TotalTokenCount > 0 {
+		t.TotalTokens += int(md.TotalTokenCount)
+	} else {
+		t.TotalTokens += int(md.PromptTokenCount +
+			md.CandidatesTokenCount +
+			md.ToolUsePromptTokenCount +
+			md.ThoughtsTokenCount)
+	}
+}
+
+func (t *tokenTotals) merge(o tokenTotals) {
+	t.UsageEvents += o.UsageEvents
+	t.PromptTokens += o.PromptTokens
+	t.CandidatesTokens += o.CandidatesTokens
+	t.ThoughtsTokens += o.ThoughtsTokens
+	t.ToolUsePromptTokens += o.ToolUsePromptTokens
+	t.CachedContentTokens += o.CachedContentTokens
+	t.TotalTokens += o.TotalTokens
+}
+
+// recordUsage charges an agent's model usage to its budget and the tick.
+func (s *ADKScheduler) recordUsage(agentID string, usage tokenTotals) {
+	s.budget.Record(agentID, s.simTime, usage.PromptTokens, usage.CandidatesTokens, usage.TotalTokens)
+	s.tickUsage.merge(usage)
+}
+
+func (s *ADKScheduler) logEvent(ar *agentRunner, prompt actionPrompt, response string, turnErr error, toolCalls, toolResponses []string, outcomes []ToolOutcome, usage tokenTotals) {
 	if s.logger == nil || ar == nil {
 		return
 	}
@@ -674,13 +1346,15 @@ func (s *ADKScheduler) logEvent(ar *agentRunner, prompt actionPrompt, response,
 		// fetching per-agent daily JSONLs (which causes many HTTP requests).
 		Prompt:              strings.TrimSpace(prompt.text),
 		Response:            strings.TrimSpace(response),
-		Error:               strings.TrimSpace(errText),
 		ToolCalls:           toolCalls,
 		ToolResponses:       toolResponses,
+		Outcomes:            outcomes,
 		TurnCount:           ar.turnCount,
 		BellRung:            ar.bellRung,
 		GraceRemaining:      ar.graceRemaining,
 		Sleeping:            prompt.action == "sleep",
+		Plan:                prompt.plan,
+		PlanStep:            prompt.planStep,
 		UsageEvents:         usage.UsageEvents,
 		PromptTokens:        usage.PromptTokens,
 		CandidatesTokens:    usage.CandidatesTokens,
@@ -689,12 +1363,25 @@ func (s *ADKScheduler) logEvent(ar *agentRunner, prompt actionPrompt, response,
 		CachedContentTokens: usage.CachedContentTokens,
 		TotalTokens:         usage.TotalTokens,
 	}
+	if turnErr != nil {
+		ev.Failed = true
+		ev.Error = strings.TrimSpace(turnErr.Error())
+		ev.ErrorKind, _ = classifyModelError(turnErr)
+		var me *ModelError
+		if errors.As(turnErr, &me) {
+			ev.Attempts = me.Attempts
+		}
+	}
+	var toolErrs string
+	if ev.ToolErrors, toolErrs = toolErrors(outcomes); ev.ToolErrors > 0 && turnErr == nil {
+		ev.Error, ev.ErrorKind = toolErrs, ErrorTool
+	}
 	if err := s.logger.LogEvent(ev); err != nil {
 		log.Printf("Failed to log event: %v", err)
 	}
 }
 
-func (s *ADKScheduler) updateAgentSummary(ctx context.Context, ar *agentRunner, promptText, responseText, errText string) {
+func (s *ADKScheduler) updateAgentSummary(ctx context.Context, ar *agentRunner, promptText, responseText, errText, errKind string) {
 	entry := buildSummaryEntry(s.simTime, promptText, responseText)
 	if entry == "" || ar.session == nil {
 		return
@@ -717,7 +1404,11 @@ func (s *ADKScheduler) updateAgentSummary(ctx context.Context, ar *agentRunner,
 		}
 	}
 
-	updated := appendSummary(current, entry, 2000)
+	maxChars := summaryMaxChars
+	if ar.instruction != nil && ar.instruction.budget > 0 {
+		maxChars = summaryBudgetMaxChars
+	}
+	updated := appendSummary(current, entry, maxChars)
 	event := session.NewEvent("summary-update")
 	event.Author = ar.persona.ID
 	event.Actions.StateDelta["agent_summary"] = updated
@@ -725,9 +1416,14 @@ func (s *ADKScheduler) updateAgentSummary(ctx context.Context, ar *agentRunner,
 		log.Printf("Failed to append summary event: %v", err)
 	}
 
-	if err := s.appendDailyLog(ar.persona.ID, promptText, responseText, entry, errText); err != nil {
+	if err := s.appendDailyLog(ar.persona.ID, promptText, responseText, entry, errText, errKind); err != nil {
 		log.Printf("Failed to append daily log: %v", err)
 	}
+	if ar.memory != nil {
+		if err := ar.memory.Remember(ctx, "turn", headRunes(responseText, 1000), s.simTime); err != nil {
+			log.Printf("Failed to remember turn: %v", err)
+		}
+	}
 }
 
 func buildSummaryEntry(at time.Time, promptText, responseText string) string {
@@ -765,7 +1461,7 @@ func truncateRunes(s string, maxChars int) string {
 	return string(runes[len(runes)-maxChars:])
 }
 
-func (s *ADKScheduler) appendDailyLog(agentID, promptText, responseText, entry, errText string) error {
+func (s *ADKScheduler) appendDailyLog(agentID, promptText, responseText, entry, errText, errKind string) error {
 	if entry == "" {
 		return nil
 	}
@@ -789,6 +1485,7 @@ func (s *ADKScheduler) appendDailyLog(agentID, promptText, responseText, entry,
 		Prompt:    strings.TrimSpace(promptText),
 		Reply:     strings.TrimSpace(responseText),
 		Error:     strings.TrimSpace(errText),
+		ErrorKind: errKind,
 		Raw:       entry,
 		Notes:     "",
 	}
@@ -803,6 +1500,7 @@ type dailyLogEntry struct {
 	Prompt    string `json:"prompt,omitempty"`
 	Reply     string `json:"reply,omitempty"`
 	Error     string `json:"error,omitempty"`
+	ErrorKind string `json:"error_kind,omitempty"`
 	Notes     string `json:"notes,omitempty"`
 	Raw       string `json:"raw,omitempty"`
 }
@@ -822,19 +1520,14 @@ func (s *ADKScheduler) saveSimState() error {
 	if s.dataPath == "" {
 		return nil
 	}
-	if err := os.MkdirAll(s.dataPath, 0755); err != nil {
-		return err
-	}
-	state := SimState{
+	return SaveSimState(s.dataPath, SimState{
 		SimTime:     s.simTime,
+		Origin:      s.simOrigin,
 		Ticks:       s.ticks,
 		StepSeconds: int(s.simStep.Seconds()),
-	}
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(filepath.Join(s.dataPath, "sim_state.json"), data, 0644)
+		RunID:       s.runID,
+		Fork:        s.fork,
+	})
 }
 
 func (s *ADKScheduler) checkpointLocked(closeLogger bool) error {
@@ -845,6 +1538,9 @@ func (s *ADKScheduler) checkpointLocked(closeLogger bool) error {
 	}
 
 	if s.journal != nil {
+		if s.forum != nil {
+			s.journal.LinkRelatedDiscussions(s.workflow, s.forum, s.simTime)
+		}
 		if err := s.journal.Save(); err != nil {
 			return fmt.Errorf("failed to save journal: %w", err)
 		}
@@ -860,23 +1556,67 @@ func (s *ADKScheduler) checkpointLocked(closeLogger bool) error {
 		return fmt.Errorf("failed to save sim state: %w", err)
 	}
 
+	if err := s.budget.Save(); err != nil {
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+
+	if s.activity != nil {
+		if err := site.WriteActivityIndex(filepath.Join(s.dataPath, activityFile), s.activity); err != nil {
+			return fmt.Errorf("failed to save activity index: %w", err)
+		}
+	}
+
+	if s.digests != nil {
+		if err := site.WriteDigestIndex(filepath.Join(s.dataPath, digestFile), s.digests); err != nil {
+			return fmt.Errorf("failed to save digest index: %w", err)
+		}
+	}
+
+	if s.emergence != nil {
+		s.recordEmergence()
+		if err := metrics.WriteEmergenceIndex(filepath.Join(s.dataPath, emergenceFile), s.emergence); err != nil {
+			return fmt.Errorf("failed to save emergence index: %w", err)
+		}
+	}
+
 	if closeLogger && s.logger != nil {
 		if err := s.logger.Close(); err != nil {
 			return fmt.Errorf("failed to close logger: %w", err)
 		}
 	}
+	if closeLogger {
+		if err := s.auditLog.Close(); err != nil {
+			return fmt.Errorf("failed to close audit log: %w", err)
+		}
+	}
 
 	return nil
 }
 
 // RunFor runs the simulation for n ticks.
+//
+// Cancelling ctx stops the run between ticks and returns ctx's error; the
+// tick underway, including its agents' LLM calls, runs to completion so the
+// state left for Checkpoint is consistent.
 func (s *ADKScheduler) RunFor(ctx context.Context, n int) error {
+	tickCtx := context.WithoutCancel(ctx)
 	for i := 0; i < n; i++ {
-		if err := s.RunTick(ctx); err != nil {
+		if err := ctx.Err(); err != nil {
+			log.Printf("Run interrupted after %d ticks", i)
+			return err
+		}
+		if s.budget.RunExhausted() {
+			log.Printf("Token budget exhausted after %d ticks, stopping early", i)
+			return nil
+		}
+		if err := s.RunTick(tickCtx); err != nil {
 			return err
 		}
 		// Small delay to avoid rate limiting
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
 	return nil
 }
@@ -2,11 +2,18 @@ package simulation
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/memory"
+	"github.com/cpunion/sci-bot/pkg/metrics"
 	"github.com/cpunion/sci-bot/pkg/publication"
 	"github.com/cpunion/sci-bot/pkg/types"
 	adkmodel "google.golang.org/adk/model"
@@ -37,13 +44,16 @@ func TestADKScheduler_LogsTokenUsage(t *testing.T) {
 			},
 		},
 		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
-			PromptTokenCount:     11,
-			CandidatesTokenCount: 22,
-			TotalTokenCount:      33,
+			PromptTokenCount:        11,
+			CandidatesTokenCount:    22,
+			ThoughtsTokenCount:      4,
+			CachedContentTokenCount: 5,
+			TotalTokenCount:         33,
 		},
 	})
 
 	logger := &memoryLogger{}
+	reg := metrics.NewRegistry()
 	sched := NewADKScheduler(ADKSchedulerConfig{
 		DataPath:        tempDir,
 		Model:           mock,
@@ -52,6 +62,7 @@ func TestADKScheduler_LogsTokenUsage(t *testing.T) {
 		StartTime:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
 		AgentsPerTick:   1,
 		CheckpointEvery: 1000,
+		Metrics:         reg,
 	})
 	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
 	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
@@ -89,5 +100,223 @@ func TestADKScheduler_LogsTokenUsage(t *testing.T) {
 	if ev.PromptTokens != 11 || ev.CandidatesTokens != 22 || ev.TotalTokens != 33 {
 		t.Fatalf("expected tokens 11/22/33, got prompt=%d candidates=%d total=%d", ev.PromptTokens, ev.CandidatesTokens, ev.TotalTokens)
 	}
+	if ev.ThoughtsTokens != 4 || ev.CachedContentTokens != 5 {
+		t.Fatalf("expected thoughts=4 cached=5, got %d/%d", ev.ThoughtsTokens, ev.CachedContentTokens)
+	}
+	if st := sched.Status(); st.TickTokens != 33 || st.TickLLMCalls != 1 {
+		t.Fatalf("expected tick usage 33 tokens in 1 call, got %d in %d", st.TickTokens, st.TickLLMCalls)
+	}
+
+	var b strings.Builder
+	if err := reg.Write(&b); err != nil {
+		t.Fatalf("metrics: %v", err)
+	}
+	for _, want := range []string{
+		"scibot_sim_ticks_total 1\n",
+		`scibot_llm_calls_total{model="mock-llm"} 1` + "\n",
+		`scibot_llm_tokens_total{model="mock-llm",kind="total"} 33` + "\n",
+		`scibot_llm_tokens_total{model="mock-llm",kind="cached"} 5` + "\n",
+		"scibot_sim_tick_duration_seconds_count 1\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("missing %q in metrics:\n%s", want, b.String())
+		}
+	}
+}
+
+// notingLLM records one research note per turn.
+type notingLLM struct{}
+
+func (notingLLM) Name() string { return "noting-llm" }
+
+func (notingLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	content := genai.NewContentFromFunctionCall("record_note", map[string]any{
+		"topic":          "Rotation curves",
+		"insight":        "Flat curves persist in dwarf galaxies.",
+		"open_questions": []any{"Does baryonic feedback explain it?"},
+	}, genai.RoleModel)
+	if last := req.Contents[len(req.Contents)-1]; len(last.Parts) > 0 && last.Parts[0].FunctionResponse != nil {
+		content = genai.NewContentFromText("noted", genai.RoleModel)
+	}
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: content}, nil)
+	}
+}
+
+func TestADKScheduler_RecordsResearchNotes(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := &memoryLogger{}
+	start := time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           notingLLM{},
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       start,
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	notes, err := memory.ReadNotes(filepath.Join(tempDir, "agents", "agent-1", memory.NotesDir), "", "")
+	if err != nil || len(notes) != 1 {
+		t.Fatalf("ReadNotes = %+v, %v", notes, err)
+	}
+	if n := notes[0]; n.Topic != "Rotation curves" || !n.At.Equal(start) || len(n.OpenQuestions) != 1 {
+		t.Fatalf("unexpected note %+v", n)
+	}
+	if len(logger.events) != 1 || len(logger.events[0].Outcomes) != 1 {
+		t.Fatalf("expected one logged outcome, got %+v", logger.events)
+	}
+	if out := logger.events[0].Outcomes[0]; out.Text["insight"] != "Flat curves persist in dwarf galaxies." || out.Args["topic"] != "Rotation curves" {
+		t.Fatalf("unexpected outcome %+v", out)
+	}
+}
+
+// planningLLM sets a goal, then starts a project, then stops.
+type planningLLM struct{}
+
+func (planningLLM) Name() string { return "planning-llm" }
+
+func (planningLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	responses := 0
+	for _, c := range req.Contents {
+		if len(c.Parts) > 0 && c.Parts[0].FunctionResponse != nil {
+			responses++
+		}
+	}
+	var content *genai.Content
+	switch responses {
+	case 0:
+		content = genai.NewContentFromFunctionCall("set_goal", map[string]any{
+			"text": "Explain flat rotation curves without dark matter",
+		}, genai.RoleModel)
+	case 1:
+		content = genai.NewContentFromFunctionCall("update_project", map[string]any{
+			"title":         "Dwarf galaxy survey",
+			"collaborators": []any{"agent-2", "agent-2"},
+			"progress":      "Collected 12 curves.",
+		}, genai.RoleModel)
+	default:
+		content = genai.NewContentFromText("planned", genai.RoleModel)
+	}
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: content}, nil)
+	}
+}
+
+func TestADKScheduler_KeepsResearchAgenda(t *testing.T) {
+	tempDir := t.TempDir()
+	start := time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           planningLLM{},
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       start,
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	if err := sched.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "agents", "agent-1", "state.json"))
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	var saved struct {
+		Agenda *types.Agenda `json:"agenda"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Agenda == nil {
+		t.Fatalf("saved agenda = %+v, %v", saved.Agenda, err)
+	}
+	agenda := *saved.Agenda
+	if len(agenda.Goals) != 1 || len(agenda.Projects) != 1 {
+		t.Fatalf("agenda = %+v", agenda)
+	}
+	if g := agenda.Goals[0]; g.ID != "goal-1" || g.Status != types.AgendaActive || !g.SetAt.Equal(start) {
+		t.Fatalf("unexpected goal %+v", g)
+	}
+	if p := agenda.Projects[0]; p.ID != "project-2" || p.Title != "Dwarf galaxy survey" || fmt.Sprint(p.Collaborators) != "[agent-2]" {
+		t.Fatalf("unexpected project %+v", p)
+	}
 }
 
+// readingLLM reads one post, then stops.
+type readingLLM struct{ postID string }
+
+func (readingLLM) Name() string { return "reading-llm" }
+
+func (m readingLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	content := genai.NewContentFromFunctionCall("read_post", map[string]any{"post_id": m.postID}, genai.RoleModel)
+	if last := req.Contents[len(req.Contents)-1]; len(last.Parts) > 0 && last.Parts[0].FunctionResponse != nil {
+		content = genai.NewContentFromText("read", genai.RoleModel)
+	}
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: content}, nil)
+	}
+}
+
+func TestADKScheduler_ReadersLearnTheoryFromPost(t *testing.T) {
+	tempDir := t.TempDir()
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	post := &types.Publication{AuthorID: "agent-1", AuthorName: "A", Title: "On waves", Content: "...", Subreddit: types.SubGeneral, TheoryID: "theory-waves"}
+	if err := forum.Post(post); err != nil {
+		t.Fatal(err)
+	}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           readingLLM{postID: post.ID},
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   2,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(forum)
+	if err := sched.theories.Propose(&types.Theory{
+		ID: "theory-waves", Title: "Wave theory", Authors: []string{"agent-1"}, AxiomSystem: "custom",
+		CustomAxioms: []types.Axiom{{ID: "c1", Statement: "Light is a wave."}},
+	}); err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, id := range []string{"agent-1", "agent-2"} {
+		if err := sched.AddAgent(ctx, &types.Persona{ID: id, Name: id, Role: types.RoleExplorer}); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	if k := sched.runners["agent-1"].state.GetKnowledge("theory-waves"); k != nil {
+		t.Fatalf("author should not learn from own post: %+v", k)
+	}
+	k := sched.runners["agent-2"].state.GetKnowledge("theory-waves")
+	if k == nil || k.Source != "agent-1" || k.SourcePostID != post.ID || k.TheoryTitle != "Wave theory" {
+		t.Fatalf("reader knowledge = %+v", k)
+	}
+}
@@ -0,0 +1,174 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+
+	"google.golang.org/adk/model"
+)
+
+// BatchLLM is implemented by models whose provider accepts many requests in
+// one call (batch APIs, high-latency queues). GenerateBatch returns one
+// response per request, in order.
+type BatchLLM interface {
+	model.LLM
+	GenerateBatch(ctx context.Context, reqs []*model.LLMRequest) ([]*model.LLMResponse, error)
+}
+
+// turnBatcher collects the LLM requests of every agent running in a tick and
+// dispatches them together once each running agent is waiting on the model or
+// has finished. Responses are released at the same time, so no agent applies
+// tool effects before the whole round has been answered.
+type turnBatcher struct {
+	mu      sync.Mutex
+	active  int // agents of the current tick still running
+	pending []*batchCall
+}
+
+type batchCall struct {
+	ctx   context.Context
+	llm   model.LLM
+	req   *model.LLMRequest
+	resp  *model.LLMResponse
+	err   error
+	ready chan struct{}
+}
+
+func newTurnBatcher() *turnBatcher {
+	return &turnBatcher{}
+}
+
+// begin registers n agents about to run.
+func (b *turnBatcher) begin(n int) {
+	b.mu.Lock()
+	b.active += n
+	b.mu.Unlock()
+}
+
+// leave marks one agent's turn as finished.
+func (b *turnBatcher) leave() {
+	b.mu.Lock()
+	b.active--
+	calls := b.takeReadyLocked()
+	b.mu.Unlock()
+	dispatchBatch(calls)
+}
+
+// submit queues a request and blocks until its batch has been answered.
+func (b *turnBatcher) submit(ctx context.Context, llm model.LLM, req *model.LLMRequest) (*model.LLMResponse, error) {
+	call := &batchCall{ctx: ctx, llm: llm, req: req, ready: make(chan struct{})}
+	b.mu.Lock()
+	b.pending = append(b.pending, call)
+	calls := b.takeReadyLocked()
+	b.mu.Unlock()
+	dispatchBatch(calls)
+
+	select {
+	case <-call.ready:
+		return call.resp, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// takeReadyLocked returns the pending batch once every running agent has
+// submitted (calls from outside a tick go out immediately).
+func (b *turnBatcher) takeReadyLocked() []*batchCall {
+	if len(b.pending) == 0 || len(b.pending) < b.active {
+		return nil
+	}
+	calls := b.pending
+	b.pending = nil
+	return calls
+}
+
+// dispatchBatch sends one GenerateBatch per BatchLLM and falls back to
+// concurrent single calls for other models, then wakes every caller.
+func dispatchBatch(calls []*batchCall) {
+	if len(calls) == 0 {
+		return
+	}
+	groups := make(map[model.LLM][]*batchCall)
+	order := make([]model.LLM, 0)
+	for _, c := range calls {
+		if _, ok := groups[c.llm]; !ok {
+			order = append(order, c.llm)
+		}
+		groups[c.llm] = append(groups[c.llm], c)
+	}
+
+	var wg sync.WaitGroup
+	for _, llm := range order {
+		group := groups[llm]
+		if batcher, ok := llm.(BatchLLM); ok {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reqs := make([]*model.LLMRequest, len(group))
+				for i, c := range group {
+					reqs[i] = c.req
+				}
+				resps, err := batcher.GenerateBatch(group[0].ctx, reqs)
+				if err == nil && len(resps) != len(group) {
+					err = fmt.Errorf("batch returned %d responses for %d requests", len(resps), len(group))
+				}
+				for i, c := range group {
+					if err != nil {
+						c.err = err
+						continue
+					}
+					c.resp = resps[i]
+				}
+			}()
+			continue
+		}
+		for _, c := range group {
+			wg.Add(1)
+			go func(c *batchCall) {
+				defer wg.Done()
+				c.resp, c.err = generateOnce(c.ctx, c.llm, c.req)
+			}(c)
+		}
+	}
+	wg.Wait()
+	for _, c := range calls {
+		close(c.ready)
+	}
+}
+
+// generateOnce runs a non-streaming call and returns its final response.
+func generateOnce(ctx context.Context, llm model.LLM, req *model.LLMRequest) (*model.LLMResponse, error) {
+	var last *model.LLMResponse
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil {
+			last = resp
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("model %s returned no response", llm.Name())
+	}
+	return last, nil
+}
+
+// batchedModel routes an agent's model calls through the tick batcher.
+type batchedModel struct {
+	inner   model.LLM
+	batcher *turnBatcher
+}
+
+func (m *batchedModel) Name() string {
+	return m.inner.Name()
+}
+
+// GenerateContent always answers with a single final response; streaming is
+// not meaningful once requests are batched.
+func (m *batchedModel) GenerateContent(ctx context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(m.batcher.submit(ctx, m.inner, req))
+	}
+}
@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// batchMock records the size of every GenerateBatch call.
+type batchMock struct {
+	mu      sync.Mutex
+	batches []int
+}
+
+func (m *batchMock) Name() string { return "batch-mock" }
+
+func (m *batchMock) GenerateContent(context.Context, *adkmodel.LLMRequest, bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(nil, fmt.Errorf("batch-mock only answers batches"))
+	}
+}
+
+func (m *batchMock) GenerateBatch(_ context.Context, reqs []*adkmodel.LLMRequest) ([]*adkmodel.LLMResponse, error) {
+	m.mu.Lock()
+	m.batches = append(m.batches, len(reqs))
+	m.mu.Unlock()
+	out := make([]*adkmodel.LLMResponse, len(reqs))
+	for i := range reqs {
+		out[i] = &adkmodel.LLMResponse{
+			Content:       genai.NewContentFromText("ok", genai.RoleModel),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: 5},
+		}
+	}
+	return out, nil
+}
+
+func TestADKScheduler_BatchTurns(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := &batchMock{}
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		StartTime:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		AgentsPerTick:   3,
+		CheckpointEvery: 1000,
+		BatchTurns:      true,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	for i := 1; i <= 3; i++ {
+		persona := &types.Persona{ID: fmt.Sprintf("agent-%d", i), Name: fmt.Sprintf("Tester %d", i), Role: types.RoleExplorer}
+		if err := sched.AddAgent(ctx, persona); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	if len(mock.batches) != 2 || mock.batches[0] != 3 || mock.batches[1] != 3 {
+		t.Fatalf("expected two batches of 3 requests, got %v", mock.batches)
+	}
+	if len(logger.events) != 6 {
+		t.Fatalf("expected 6 events, got %d", len(logger.events))
+	}
+	for _, ev := range logger.events {
+		if ev.Error != "" || ev.Response != "ok" || ev.TotalTokens != 5 {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	}
+}
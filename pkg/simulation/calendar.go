@@ -0,0 +1,184 @@
+package simulation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ActionSeminar marks agent turns prompted by the weekly seminar.
+const ActionSeminar = "seminar"
+
+// Calendar ties agent schedules to sim time: agents work during working
+// hours on working days, wake each working morning with a fresh turn budget,
+// and meet once a week for a seminar. Hours use the sim clock's location.
+type Calendar struct {
+	// WorkStart and WorkEnd bound the working day in hours [WorkStart,
+	// WorkEnd). Equal values mean agents work around the clock.
+	WorkStart int
+	WorkEnd   int
+	// Weekends gives everyone Saturday and Sunday off.
+	Weekends bool
+	// Seminar, when set, gathers every working agent on one thread weekly.
+	Seminar *Seminar
+}
+
+// Seminar is a weekly session at which all working agents are prompted to
+// discuss the same forum thread: the week's highest-scored recent one.
+type Seminar struct {
+	Weekday time.Weekday
+	Hour    int
+}
+
+// Working reports whether t falls within working hours on a working day.
+func (c *Calendar) Working(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	if c.Weekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return false
+	}
+	if c.WorkStart == c.WorkEnd {
+		return true
+	}
+	h := t.Hour()
+	if c.WorkStart < c.WorkEnd {
+		return h >= c.WorkStart && h < c.WorkEnd
+	}
+	// Night shifts wrap past midnight, e.g. 22-6.
+	return h >= c.WorkStart || h < c.WorkEnd
+}
+
+// workday identifies the working day t belongs to, so a shift that wraps
+// past midnight counts as one day.
+func (c *Calendar) workday(t time.Time) string {
+	if c != nil && c.WorkStart > c.WorkEnd && t.Hour() < c.WorkEnd {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t.Format("2006-01-02")
+}
+
+// seminarDue reports whether this week's seminar starts within the tick
+// [t, t+step). Being stateless, it fires exactly once per week even across
+// resumed runs.
+func (c *Calendar) seminarDue(t time.Time, step time.Duration) bool {
+	if c == nil || c.Seminar == nil {
+		return false
+	}
+	days := int(c.Seminar.Weekday - t.Weekday())
+	at := time.Date(t.Year(), t.Month(), t.Day()+days, c.Seminar.Hour, 0, 0, 0, t.Location())
+	for _, candidate := range []time.Time{at.AddDate(0, 0, -7), at, at.AddDate(0, 0, 7)} {
+		if !candidate.Before(t) && candidate.Before(t.Add(step)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseWorkHours parses "9-18" into start and end hours.
+func ParseWorkHours(spec string) (start, end int, err error) {
+	parts := strings.Split(strings.TrimSpace(spec), "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid work hours %q (want e.g. 9-18)", spec)
+	}
+	if start, err = parseHour(parts[0]); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseHour(parts[1]); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// ParseSeminar parses "fri@15" (weekday and hour) into a Seminar.
+func ParseSeminar(spec string) (*Seminar, error) {
+	day, hour, ok := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "@")
+	if !ok {
+		return nil, fmt.Errorf("invalid seminar %q (want e.g. fri@15)", spec)
+	}
+	weekday := -1
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if day == name || day == name[:3] {
+			weekday = int(d)
+		}
+	}
+	if weekday < 0 {
+		return nil, fmt.Errorf("invalid seminar weekday %q", day)
+	}
+	h, err := parseHour(hour)
+	if err != nil {
+		return nil, err
+	}
+	return &Seminar{Weekday: time.Weekday(weekday), Hour: h}, nil
+}
+
+func parseHour(s string) (int, error) {
+	h, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("invalid hour %q", s)
+	}
+	return h % 24, nil
+}
+
+// offDuty reports whether the calendar has ar off work right now.
+func (s *ADKScheduler) offDuty() bool {
+	return s.calendar != nil && !s.calendar.Working(s.simTime)
+}
+
+// wakeAgents starts a new working day for agents that slept through the
+// last one: the bell resets and the daily turn budget refills. Caller must
+// hold s.mu.
+func (s *ADKScheduler) wakeAgents() {
+	if s.calendar == nil || s.offDuty() {
+		return
+	}
+	day := s.calendar.workday(s.simTime)
+	for _, ar := range s.runners {
+		if ar == nil || ar.workday == day {
+			continue
+		}
+		if ar.workday != "" {
+			ar.turnCount = 0
+			ar.bellRung = false
+			ar.graceRemaining = s.graceTurns
+		}
+		ar.workday = day
+	}
+}
+
+// shiftEnding reports whether working hours end before the next tick, so
+// the bell should ring for agents still up.
+func (s *ADKScheduler) shiftEnding() bool {
+	return s.calendar != nil && s.calendar.Working(s.simTime) && !s.calendar.Working(s.simTime.Add(s.simStep))
+}
+
+// seminarThread picks the thread for this week's seminar: the highest
+// scored of the recent threads, skipping last week's. Caller must hold s.mu.
+func (s *ADKScheduler) seminarThread() *types.Publication {
+	if s.forum == nil {
+		return nil
+	}
+	var best *types.Publication
+	for _, p := range s.forum.GetRecent(20) {
+		if p.ID == s.lastSeminarThread {
+			continue
+		}
+		if best == nil || p.Score > best.Score {
+			best = p
+		}
+	}
+	return best
+}
+
+// seminarPrompt invites an agent to this week's seminar thread.
+func seminarPrompt(thread *types.Publication, lang prompts.Lang) actionPrompt {
+	return actionPrompt{
+		action: ActionSeminar,
+		text:   fmt.Sprintf(prompts.For(lang).Seminar, thread.ID, thread.Title, thread.AuthorName),
+	}
+}
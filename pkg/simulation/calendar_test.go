@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_CalendarDrivesSleepWakeAndSeminar(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	friday := time.Date(2026, 2, 6, 8, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       friday,
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		Calendar: &Calendar{
+			WorkStart: 9,
+			WorkEnd:   12,
+			Weekends:  true,
+			Seminar:   &Seminar{Weekday: time.Friday, Hour: 10},
+		},
+	})
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	if err := forum.Post(&types.Publication{ID: "post-1", Title: "Dark matter", AuthorID: "x", AuthorName: "X", Content: "?"}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	for _, p := range []*types.Persona{
+		{ID: "agent-1", Name: "A", Role: types.RoleExplorer},
+		{ID: "agent-2", Name: "B", Role: types.RoleBuilder},
+	} {
+		if err := sched.AddAgent(ctx, p); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+	// Friday 08:00 through Monday 09:00.
+	for i := 0; i < 74; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	byHour := map[string][]EventLog{}
+	for _, ev := range logger.events {
+		key := ev.SimTime.Format("Mon 15")
+		byHour[key] = append(byHour[key], ev)
+	}
+	if n := len(byHour["Fri 08"]); n != 0 {
+		t.Fatalf("expected no turns before work, got %d", n)
+	}
+	seminar := byHour["Fri 10"]
+	if len(seminar) != 2 || seminar[0].Action != ActionSeminar || seminar[1].Action != ActionSeminar ||
+		!strings.Contains(seminar[0].Prompt, "post-1") {
+		t.Fatalf("expected both agents at the seminar, got %+v", seminar)
+	}
+	if last := byHour["Fri 11"]; len(last) != 1 || last[0].Action != "sleep" {
+		t.Fatalf("expected the bell at the end of the shift, got %+v", last)
+	}
+	for key, evs := range byHour {
+		if strings.HasPrefix(key, "Sat") || strings.HasPrefix(key, "Sun") {
+			t.Fatalf("expected weekends off, got %d turns on %s", len(evs), key)
+		}
+	}
+	if monday := byHour["Mon 09"]; len(monday) != 1 || monday[0].Action == "sleep" {
+		t.Fatalf("expected agents awake on Monday, got %+v", monday)
+	}
+}
@@ -0,0 +1,36 @@
+package simulation
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// ActionConsensus marks log events for achieved consensus requests.
+const ActionConsensus = "consensus"
+
+// logConsensus writes a feed event, attributed to the requester, when a
+// consensus request is achieved. Votes come from tool calls inside RunTick,
+// which already holds s.mu.
+func (s *ADKScheduler) logConsensus(ev busEvent) {
+	req := ev.consensus
+	if s.logger == nil || req == nil {
+		return
+	}
+	entry := EventLog{
+		Timestamp: time.Now(),
+		SimTime:   s.simTime,
+		Tick:      s.ticks,
+		AgentID:   req.RequesterID,
+		AgentName: req.RequesterName,
+		Action:    ActionConsensus,
+		Response: fmt.Sprintf("Consensus %s on %s achieved: %d supporters, %d opponents",
+			req.ID, req.PostID, len(req.Supporters), len(req.Opponents)),
+	}
+	if ar, ok := s.runners[req.RequesterID]; ok {
+		entry.ModelName = ar.modelName
+	}
+	if err := s.logger.LogEvent(entry); err != nil {
+		log.Printf("Failed to log consensus: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_ConsensusAchievedNudgesRequester(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		Consensus:       publication.ConsensusThresholds{MinSupporters: 2},
+	})
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Ada", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := forum.Post(&types.Publication{ID: "post-1", Title: "Engines", AuthorID: "x", Content: "!"}); err != nil {
+		t.Fatal(err)
+	}
+	id := sched.workflow.AddConsensusRequest(&types.ConsensusRequest{
+		PostID: "post-1", RequesterID: "agent-1", RequesterName: "Ada",
+		Status: types.ConsensusOpen, Supporters: []string{"agent-1"},
+	})
+	if _, err := sched.workflow.VoteConsensus(id, "bob", true); err != nil {
+		t.Fatalf("VoteConsensus: %v", err)
+	}
+	if len(logger.events) != 1 || logger.events[0].Action != ActionConsensus || logger.events[0].AgentID != "agent-1" {
+		t.Fatalf("expected a consensus feed event, got %+v", logger.events)
+	}
+
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	prompt := logger.events[len(logger.events)-1].Prompt
+	if !strings.Contains(prompt, "1 项共识已达成") || !strings.Contains(prompt, "consensus_id="+id) || !strings.Contains(prompt, "《Engines》") {
+		t.Fatalf("expected a consensus nudge, got %q", prompt)
+	}
+}
@@ -0,0 +1,224 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/util/instructionutil"
+	"google.golang.org/genai"
+)
+
+const (
+	// summarySlot is where the instruction templates take agent_summary.
+	summarySlot = "{agent_summary?}"
+	// summaryBudgetMaxChars caps the stored rolling agent_summary when a
+	// context budget is set; each prompt then keeps as much of its newest
+	// part as the budget allows.
+	summaryBudgetMaxChars = 4 * summaryMaxChars
+	// minToolOutputTokens is the smallest a trimmed tool output gets.
+	minToolOutputTokens = 200
+)
+
+// contextBudgetFor returns the prompt token budget for a model: its entry in
+// ContextBudgets, else ContextBudget. 0 means no budget.
+func (s *ADKScheduler) contextBudgetFor(modelName string) int {
+	if n, ok := s.contextBudgets[modelName]; ok {
+		return n
+	}
+	return s.contextBudget
+}
+
+// estimateTokens approximates a text's token count without a tokenizer:
+// about four bytes of ASCII per token, one token per other rune (CJK text
+// runs close to one token per character).
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// headTokens keeps the start of text within about maxTokens.
+func headTokens(text string, maxTokens int) string {
+	if estimateTokens(text) <= maxTokens {
+		return text
+	}
+	cost := 0
+	for i, r := range text {
+		if r < utf8.RuneSelf {
+			cost++
+		} else {
+			cost += 4
+		}
+		if cost > 4*maxTokens {
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// fitSummary keeps the newest lines of a rolling summary within maxTokens,
+// dropping whole older lines first. A single line that is still too long
+// keeps its end.
+func fitSummary(summary string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	if estimateTokens(summary) <= maxTokens {
+		return summary
+	}
+	lines := strings.Split(summary, "\n")
+	kept, used := len(lines), 0
+	for kept > 0 {
+		cost := estimateTokens(lines[kept-1]) + 1
+		if used+cost > maxTokens {
+			break
+		}
+		used += cost
+		kept--
+	}
+	if kept == len(lines) {
+		last := []rune(lines[len(lines)-1])
+		for len(last) > 0 && estimateTokens(string(last)) > maxTokens {
+			last = last[len(last)/8+1:]
+		}
+		return string(last)
+	}
+	return strings.Join(lines[kept:], "\n")
+}
+
+// provideWithin renders the instruction and fills the agent_summary slot
+// with as much of the newest summary as fits the budget left after the rest
+// of the instruction and the reserve for the turn itself.
+func (in *agentInstruction) provideWithin(ctx agent.ReadonlyContext, base, suffix string) (string, error) {
+	const marker = "\x00agent_summary\x00"
+	text, err := instructionutil.InjectSessionState(ctx, strings.ReplaceAll(base, summarySlot, marker))
+	if err != nil {
+		return "", err
+	}
+	text += suffix
+	summary := ""
+	if v, err := ctx.ReadonlyState().Get("agent_summary"); err == nil {
+		summary, _ = v.(string)
+	}
+	room := in.budget - in.reserve - estimateTokens(strings.ReplaceAll(text, marker, ""))
+	return strings.ReplaceAll(text, marker, fitSummary(summary, room)), nil
+}
+
+// budgetModel trims tool outputs, oldest first, from requests whose
+// estimated size exceeds the agent's context budget less the output
+// reserve. Trimmed outputs are replaced in a copy of the request, never in
+// the session.
+type budgetModel struct {
+	inner  model.LLM
+	budget int // input tokens
+}
+
+func (m *budgetModel) Name() string {
+	return m.inner.Name()
+}
+
+func (m *budgetModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return m.inner.GenerateContent(ctx, fitRequest(req, m.budget), stream)
+}
+
+// fitRequest returns req, or a copy with tool outputs shortened so the
+// request fits budget tokens.
+func fitRequest(req *model.LLMRequest, budget int) *model.LLMRequest {
+	if req == nil || budget <= 0 {
+		return req
+	}
+	total := 0
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		total += contentTokens(req.Config.SystemInstruction)
+	}
+	for _, c := range req.Contents {
+		total += contentTokens(c)
+	}
+	excess := total - budget
+	if excess <= 0 {
+		return req
+	}
+
+	out := *req
+	out.Contents = make([]*genai.Content, len(req.Contents))
+	for i, c := range req.Contents {
+		out.Contents[i] = c
+		if excess <= 0 || c == nil {
+			continue
+		}
+		var parts []*genai.Part
+		for j, p := range c.Parts {
+			if excess <= 0 || p == nil || p.FunctionResponse == nil {
+				continue
+			}
+			data, _ := json.Marshal(p.FunctionResponse.Response)
+			size := estimateTokens(string(data))
+			if size <= minToolOutputTokens {
+				continue
+			}
+			keep := max(minToolOutputTokens, size-excess)
+			trimmed, newSize := trimToolOutput(string(data), keep)
+			if newSize > keep {
+				// Quoting the output as a string costs extra; take it off the head.
+				trimmed, newSize = trimToolOutput(string(data), max(keep-(newSize-keep), 1))
+			}
+			excess -= size - newSize
+			if parts == nil {
+				parts = append([]*genai.Part(nil), c.Parts...)
+			}
+			fr := *p.FunctionResponse
+			fr.Response = trimmed
+			parts[j] = &genai.Part{FunctionResponse: &fr}
+		}
+		if parts != nil {
+			cp := *c
+			cp.Parts = parts
+			out.Contents[i] = &cp
+		}
+	}
+	return &out
+}
+
+// trimToolOutput replaces a tool output (as JSON) with its first
+// maxTokens, returning the replacement and its estimated size.
+func trimToolOutput(data string, maxTokens int) (map[string]any, int) {
+	out := map[string]any{
+		"truncated": true,
+		"output":    headTokens(data, maxTokens),
+	}
+	encoded, _ := json.Marshal(out)
+	return out, estimateTokens(string(encoded))
+}
+
+func contentTokens(c *genai.Content) int {
+	if c == nil {
+		return 0
+	}
+	n := 0
+	for _, p := range c.Parts {
+		if p == nil {
+			continue
+		}
+		n += estimateTokens(p.Text)
+		if p.FunctionCall != nil {
+			data, _ := json.Marshal(p.FunctionCall.Args)
+			n += estimateTokens(string(data))
+		}
+		if p.FunctionResponse != nil {
+			data, _ := json.Marshal(p.FunctionResponse.Response)
+			n += estimateTokens(string(data))
+		}
+	}
+	return n
+}
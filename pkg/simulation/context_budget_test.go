@@ -0,0 +1,94 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestFitSummary_KeepsNewestLines(t *testing.T) {
+	summary := "old entry one\nold entry two\nnewest entry"
+	if got := fitSummary(summary, 100); got != summary {
+		t.Fatalf("fitting summary changed: %q", got)
+	}
+	if got := fitSummary(summary, 9); got != "old entry two\nnewest entry" {
+		t.Fatalf("fitSummary = %q", got)
+	}
+	if got := fitSummary("一二三四五六七八九十", 4); got != "七八九十" {
+		t.Fatalf("long line = %q", got)
+	}
+	if got := fitSummary(summary, 0); got != "" {
+		t.Fatalf("no room = %q", got)
+	}
+}
+
+func TestFitRequest_TrimsOldestToolOutputsInACopy(t *testing.T) {
+	big := map[string]any{"content": strings.Repeat("x", 8000)}
+	req := &adkmodel.LLMRequest{Contents: []*genai.Content{
+		genai.NewContentFromText("prompt", genai.RoleUser),
+		{Role: "user", Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{Name: "read_post", Response: big}}}},
+		{Role: "user", Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{Name: "read_post", Response: big}}}},
+	}}
+	if got := fitRequest(req, 10000); got != req {
+		t.Fatalf("a request within budget should pass through")
+	}
+	got := fitRequest(req, 3000)
+	first := got.Contents[1].Parts[0].FunctionResponse.Response
+	second := got.Contents[2].Parts[0].FunctionResponse.Response
+	if first["truncated"] != true || second["truncated"] == true {
+		t.Fatalf("expected only the oldest output trimmed: %v / %v", first["truncated"], second["truncated"])
+	}
+	if total := contentTokens(got.Contents[1]) + contentTokens(got.Contents[2]); total > 3000 {
+		t.Fatalf("trimmed request = %d tokens", total)
+	}
+	if req.Contents[1].Parts[0].FunctionResponse.Response["truncated"] != nil {
+		t.Fatalf("original request was modified")
+	}
+}
+
+func TestADKScheduler_FitsAgentSummaryToContextBudget(t *testing.T) {
+	tempDir := t.TempDir()
+	llm := &instructionLLM{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           llm,
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		CheckpointEvery: 1000,
+		MaxOutputTokens: 500,
+		ContextBudgets:  map[string]int{llm.Name(): 8000},
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	lines := make([]string, 2000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("entry-%04d | prompt: something happened", i)
+	}
+	if err := sched.replaceAgentSummary(ctx, sched.runners["agent-1"], strings.Join(lines, "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	instruction := llm.seen[0]
+	if !strings.Contains(instruction, "entry-1999") || strings.Contains(instruction, "entry-0000") {
+		t.Fatalf("instruction should keep only the newest summary entries")
+	}
+	if n := estimateTokens(instruction); n > 8000-2000-500 {
+		t.Fatalf("instruction = %d tokens, over budget", n)
+	}
+}
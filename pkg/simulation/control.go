@@ -0,0 +1,473 @@
+package simulation
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchedulerStatus is a point-in-time view of the scheduler.
+type SchedulerStatus struct {
+	Tick          int            `json:"tick"`
+	SimTime       time.Time      `json:"sim_time"`
+	Agents        int            `json:"agents"`
+	Retired       int            `json:"retired"`
+	Sleeping      int            `json:"sleeping"`      // off duty or bell rung
+	BudgetPaused  int            `json:"budget_paused"` // out of daily tokens
+	AgentsPerTick int            `json:"agents_per_tick"`
+	Step          string         `json:"step"`
+	Actions       map[string]int `json:"actions"`
+	// Tokens used by the last tick's turns and dreams, over how many calls.
+	TickTokens   int `json:"tick_tokens"`
+	TickLLMCalls int `json:"tick_llm_calls"`
+}
+
+// Status reports the scheduler's clock, population and action counts. It
+// waits for a running tick to finish.
+func (s *ADKScheduler) Status() SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := SchedulerStatus{
+		Tick:          s.ticks,
+		SimTime:       s.simTime,
+		AgentsPerTick: s.agentsPerTick,
+		Step:          s.simStep.String(),
+		Actions:       make(map[string]int, len(s.actionStats)),
+		TickTokens:    s.tickUsage.TotalTokens,
+		TickLLMCalls:  s.tickUsage.UsageEvents,
+	}
+	for _, ar := range s.runners {
+		if ar.retired {
+			st.Retired++
+			continue
+		}
+		st.Agents++
+		switch s.livenessOf(ar) {
+		case LivenessSleeping:
+			st.Sleeping++
+		case LivenessBudgetPaused:
+			st.BudgetPaused++
+		}
+	}
+	for action, n := range s.actionStats {
+		st.Actions[action] = n
+	}
+	return st
+}
+
+// SetAgentsPerTick changes how many agents act in each following tick.
+func (s *ADKScheduler) SetAgentsPerTick(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.agentsPerTick = maxInt(n, 1)
+}
+
+// SetSimStep changes how much sim time each following tick advances.
+func (s *ADKScheduler) SetSimStep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.simStep = d
+}
+
+// Control states reported by /api/sim/status.
+const (
+	ControlRunning  = "running"
+	ControlPaused   = "paused"
+	ControlFinished = "finished" // tick allowance spent; step or resume with more ticks
+)
+
+// ControlStatus is returned by every /api/sim endpoint.
+type ControlStatus struct {
+	State string `json:"state"`
+	// TicksLeft is the remaining tick allowance (-1 = unbounded); steps
+	// granted while paused don't count against it.
+	TicksLeft    int  `json:"ticks_left"`
+	PendingSteps int  `json:"pending_steps,omitempty"`
+	InTick       bool `json:"in_tick"`
+	// LastTickSeconds is the wall time of the last tick.
+	LastTickSeconds float64         `json:"last_tick_seconds,omitempty"`
+	LastTickAt      time.Time       `json:"last_tick_at,omitempty"`
+	Pending         *ControlConfig  `json:"pending_config,omitempty"`
+	Scheduler       SchedulerStatus `json:"scheduler"`
+}
+
+// ControlConfig holds the settings /api/sim/config can change. Zero fields
+// are left as they are.
+type ControlConfig struct {
+	AgentsPerTick int    `json:"agents_per_tick,omitempty"`
+	Step          string `json:"step,omitempty"` // Go duration, e.g. "30m"
+}
+
+// Controller runs a scheduler as a long-lived service that can be paused,
+// resumed, single-stepped and reconfigured over HTTP while it runs. Changes
+// take effect between ticks; a tick in progress always completes.
+type Controller struct {
+	s     *ADKScheduler
+	token string
+
+	mu        sync.Mutex
+	wake      chan struct{}
+	paused    bool
+	ticksLeft int
+	steps     int
+	inTick    bool
+	dirty     bool // ticks ran since the last checkpoint
+	pending   *ControlConfig
+	lastTick  time.Duration
+	lastAt    time.Time
+	snapshot  SchedulerStatus
+	live      LiveStatus
+}
+
+// NewController wraps s. A non-empty token is required as a bearer token on
+// the endpoints that change state.
+func NewController(s *ADKScheduler, token string) *Controller {
+	return &Controller{s: s, token: token, wake: make(chan struct{}, 1), snapshot: s.Status(), live: s.Live()}
+}
+
+// Run runs up to n ticks (n <= 0: unbounded), honoring pause, step and
+// config changes. Unlike RunFor it does not return when the allowance is
+// spent: it waits for more steps until ctx is canceled, so a finished run
+// stays inspectable. It returns early when the token budget is exhausted.
+func (c *Controller) Run(ctx context.Context, n int) error {
+	c.mu.Lock()
+	c.ticksLeft = n
+	if n <= 0 {
+		c.ticksLeft = -1
+	}
+	c.mu.Unlock()
+
+	tickCtx := context.WithoutCancel(ctx)
+	for c.next(ctx) {
+		if c.s.budget.RunExhausted() {
+			log.Printf("Token budget exhausted, stopping")
+			return nil
+		}
+		c.applyPending()
+		start := time.Now()
+		err := c.s.RunTick(tickCtx)
+		elapsed := time.Since(start)
+		if err == nil && c.goingIdle() {
+			// Checkpoint before reporting paused or finished, so what the
+			// status shows is on disk.
+			if err := c.s.Checkpoint(); err != nil {
+				log.Printf("Checkpoint on pause failed: %v", err)
+			}
+		} else {
+			c.markDirty()
+		}
+		snapshot, live := c.s.Status(), c.s.Live()
+
+		c.mu.Lock()
+		c.inTick = false
+		c.lastTick = elapsed
+		c.lastAt = time.Now()
+		c.snapshot = snapshot
+		c.live = live
+		c.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		// Small delay to avoid rate limiting
+		select {
+		case <-ctx.Done():
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return ctx.Err()
+}
+
+// next blocks until a tick may run and claims it, or returns false once
+// ctx is canceled. Ticks not yet checkpointed are saved before it waits.
+func (c *Controller) next(ctx context.Context) bool {
+	for {
+		if ctx.Err() != nil {
+			return false
+		}
+		c.mu.Lock()
+		switch {
+		case c.steps > 0:
+			c.steps--
+		case !c.paused && c.ticksLeft != 0:
+			if c.ticksLeft > 0 {
+				c.ticksLeft--
+			}
+		default:
+			dirty := c.dirty
+			c.dirty = false
+			c.mu.Unlock()
+			if dirty {
+				if err := c.s.Checkpoint(); err != nil {
+					log.Printf("Checkpoint on pause failed: %v", err)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case <-c.wake:
+			}
+			continue
+		}
+		c.inTick = true
+		c.mu.Unlock()
+		return true
+	}
+}
+
+// goingIdle reports whether no further tick is due right now.
+func (c *Controller) goingIdle() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.steps == 0 && (c.paused || c.ticksLeft == 0)
+}
+
+func (c *Controller) markDirty() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dirty = true
+}
+
+func (c *Controller) applyPending() {
+	c.mu.Lock()
+	cfg := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	if cfg == nil {
+		return
+	}
+	if cfg.AgentsPerTick > 0 {
+		c.s.SetAgentsPerTick(cfg.AgentsPerTick)
+	}
+	if d, err := time.ParseDuration(cfg.Step); err == nil {
+		c.s.SetSimStep(d)
+	}
+}
+
+func (c *Controller) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pause stops the run after the current tick.
+func (c *Controller) Pause() ControlStatus {
+	c.mu.Lock()
+	c.paused = true
+	c.mu.Unlock()
+	c.signal()
+	return c.Status()
+}
+
+// Resume continues a paused run. ticks > 0 adds to the tick allowance, which
+// also restarts a finished run.
+func (c *Controller) Resume(ticks int) ControlStatus {
+	c.mu.Lock()
+	c.paused = false
+	if ticks > 0 && c.ticksLeft >= 0 {
+		c.ticksLeft += ticks
+	}
+	c.mu.Unlock()
+	c.signal()
+	return c.Status()
+}
+
+// Step pauses the run and grants n more ticks.
+func (c *Controller) Step(n int) ControlStatus {
+	c.mu.Lock()
+	c.paused = true
+	c.steps += n
+	c.mu.Unlock()
+	c.signal()
+	return c.Status()
+}
+
+// Configure queues settings for the next tick; until then status lists them
+// as pending.
+func (c *Controller) Configure(cfg ControlConfig) (ControlStatus, error) {
+	if cfg.AgentsPerTick < 0 {
+		return ControlStatus{}, errors.New("agents_per_tick must be positive")
+	}
+	if cfg.Step != "" {
+		d, err := time.ParseDuration(cfg.Step)
+		if err != nil || d <= 0 {
+			return ControlStatus{}, fmt.Errorf("invalid step %q: want a positive duration such as 30m", cfg.Step)
+		}
+		cfg.Step = d.String()
+	}
+	c.mu.Lock()
+	if c.pending == nil {
+		c.pending = &ControlConfig{}
+	}
+	if cfg.AgentsPerTick > 0 {
+		c.pending.AgentsPerTick = cfg.AgentsPerTick
+	}
+	if cfg.Step != "" {
+		c.pending.Step = cfg.Step
+	}
+	c.mu.Unlock()
+	return c.Status(), nil
+}
+
+// Status reports the control state and the scheduler as of the last tick.
+// It never waits for a running tick.
+func (c *Controller) Status() ControlStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st := ControlStatus{
+		State:        c.stateLocked(),
+		TicksLeft:    c.ticksLeft,
+		PendingSteps: c.steps,
+		InTick:       c.inTick,
+		LastTickAt:   c.lastAt,
+		Scheduler:    c.snapshot,
+	}
+	if c.lastTick > 0 {
+		st.LastTickSeconds = c.lastTick.Seconds()
+	}
+	if c.pending != nil {
+		p := *c.pending
+		st.Pending = &p
+	}
+	return st
+}
+
+// Live reports the last tick's turns, agents and token usage. Like Status
+// it never waits for a running tick.
+func (c *Controller) Live() LiveStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := c.live
+	st.State, st.InTick = c.stateLocked(), c.inTick
+	return st
+}
+
+func (c *Controller) stateLocked() string {
+	idle := !c.inTick && c.steps == 0
+	switch {
+	case idle && c.paused:
+		return ControlPaused
+	case idle && c.ticksLeft == 0:
+		return ControlFinished
+	}
+	return ControlRunning
+}
+
+// Handler serves the control API:
+//
+//	GET  /api/sim/status
+//	GET  /api/sim/live
+//	POST /api/sim/pause
+//	POST /api/sim/resume[?ticks=N]
+//	POST /api/sim/step?n=N          (default 1)
+//	GET  /api/sim/config
+//	POST /api/sim/config            {"agents_per_tick": 3, "step": "30m"}
+func (c *Controller) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sim/status", c.handle(http.MethodGet, func(r *http.Request) (any, error) {
+		return c.Status(), nil
+	}))
+	mux.HandleFunc("/api/sim/live", c.handle(http.MethodGet, func(r *http.Request) (any, error) {
+		return c.Live(), nil
+	}))
+	mux.HandleFunc("/api/sim/pause", c.handle(http.MethodPost, func(r *http.Request) (any, error) {
+		return c.Pause(), nil
+	}))
+	mux.HandleFunc("/api/sim/resume", c.handle(http.MethodPost, func(r *http.Request) (any, error) {
+		ticks, err := queryInt(r, "ticks", 0)
+		if err != nil {
+			return nil, err
+		}
+		return c.Resume(ticks), nil
+	}))
+	mux.HandleFunc("/api/sim/step", c.handle(http.MethodPost, func(r *http.Request) (any, error) {
+		n, err := queryInt(r, "n", 1)
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, errors.New("n must be positive")
+		}
+		return c.Step(n), nil
+	}))
+	mux.HandleFunc("/api/sim/config", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			c.handle(http.MethodGet, func(r *http.Request) (any, error) {
+				sched := c.Status().Scheduler
+				return ControlConfig{AgentsPerTick: sched.AgentsPerTick, Step: sched.Step}, nil
+			})(w, r)
+			return
+		}
+		c.handle(http.MethodPost, func(r *http.Request) (any, error) {
+			var cfg ControlConfig
+			dec := json.NewDecoder(io.LimitReader(r.Body, 64*1024))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&cfg); err != nil {
+				return nil, fmt.Errorf("invalid config: %w", err)
+			}
+			return c.Configure(cfg)
+		})(w, r)
+	})
+	return mux
+}
+
+func (c *Controller) handle(method string, fn func(*http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeControlJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		if method != http.MethodGet && !c.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="sci-bot-sim"`)
+			writeControlJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid bearer token"})
+			return
+		}
+		out, err := fn(r)
+		if err != nil {
+			writeControlJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeControlJSON(w, http.StatusOK, out)
+	}
+}
+
+func (c *Controller) authorized(r *http.Request) bool {
+	if c.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(c.token)) == 1
+}
+
+func queryInt(r *http.Request, key string, fallback int) (int, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", key, raw)
+	}
+	return n, nil
+}
+
+func writeControlJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,111 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestController_PausesStepsAndReconfigures(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	start := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       start,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+	if err := sched.AddAgent(context.Background(), &types.Persona{ID: "agent-1", Name: "Tester", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+
+	ctrl := NewController(sched, "secret")
+	srv := httptest.NewServer(ctrl.Handler())
+	defer srv.Close()
+	call := func(method, path, body string, token bool) (int, ControlStatus) {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if token {
+			req.Header.Set("Authorization", "Bearer secret")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		var st ControlStatus
+		_ = json.NewDecoder(resp.Body).Decode(&st)
+		return resp.StatusCode, st
+	}
+	waitFor := func(want string, tick int) ControlStatus {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			_, st := call(http.MethodGet, "/api/sim/status", "", false)
+			if st.State == want && st.Scheduler.Tick == tick {
+				return st
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s at tick %d, last %+v", want, tick, st)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if code, _ := call(http.MethodPost, "/api/sim/pause", "", false); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", code)
+	}
+	call(http.MethodPost, "/api/sim/pause", "", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ctrl.Run(ctx, 3) }()
+
+	waitFor(ControlPaused, 0)
+	call(http.MethodPost, "/api/sim/step?n=2", "", true)
+	st := waitFor(ControlPaused, 2)
+	if st.TicksLeft != 3 {
+		t.Errorf("steps should not use the tick allowance, %d left", st.TicksLeft)
+	}
+
+	if code, st := call(http.MethodPost, "/api/sim/config", `{"step": "30m"}`, true); code != http.StatusOK || st.Pending == nil || st.Pending.Step != "30m0s" {
+		t.Fatalf("config: %d %+v", code, st)
+	}
+	if code, _ := call(http.MethodPost, "/api/sim/config", `{"step": "soon"}`, true); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid step, got %d", code)
+	}
+	call(http.MethodPost, "/api/sim/step", "", true)
+	st = waitFor(ControlPaused, 3)
+	if !st.Scheduler.SimTime.Equal(start.Add(2*time.Hour+30*time.Minute)) || st.Scheduler.Step != "30m0s" || st.Pending != nil {
+		t.Errorf("expected the new step to apply from tick 3, got %+v", st)
+	}
+
+	call(http.MethodPost, "/api/sim/resume", "", true)
+	waitFor(ControlFinished, 6)
+	state, err := LoadSimState(tempDir)
+	if err != nil || state.Ticks != 6 {
+		t.Errorf("expected a checkpoint once finished, got %+v (%v)", state, err)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Run to stop on cancel, got %v", err)
+	}
+}
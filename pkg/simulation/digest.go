@@ -0,0 +1,210 @@
+package simulation
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/site"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+const (
+	// digestFile is the digest index under the data root.
+	digestFile = "digest.json"
+	// ActionDigest marks log events for posted weekly digests.
+	ActionDigest = "digest"
+	// DigestSubreddit is where weekly digests are posted.
+	DigestSubreddit types.Subreddit = "meta"
+
+	digestThreadsPerSubreddit = 3
+	digestSummaryMaxChars     = 400
+)
+
+// isoWeek labels the ISO week containing t, e.g. "2026-W07".
+func isoWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// postDigest has a communicator post a digest of each subreddit's top
+// threads to r/meta once the sim clock enters a new week. The digest covers
+// forum activity since the previous one, is credited to the communicators
+// in turn and is added to digest.json. Caller must hold s.mu.
+func (s *ADKScheduler) postDigest() {
+	if s.digests == nil || s.forum == nil {
+		return
+	}
+	week := isoWeek(s.simTime)
+	if s.digestWeek == "" {
+		s.digestWeek = week
+		if last := s.digests.Latest(); last != nil {
+			s.digestWeek = isoWeek(last.SimTime)
+		}
+	}
+	if week == s.digestWeek {
+		return
+	}
+	covered := s.digestWeek
+	s.digestWeek = week
+
+	ar := s.digestCompiler()
+	if ar == nil {
+		log.Printf("[Tick %d] No communicator to compile the %s digest", s.ticks, covered)
+		return
+	}
+	var since time.Time
+	if last := s.digests.Latest(); last != nil {
+		since = last.CompiledAt
+	}
+	digest := site.Digest{
+		Week:       covered,
+		AuthorID:   ar.persona.ID,
+		AuthorName: ar.persona.Name,
+		SimTime:    s.simTime,
+		CompiledAt: time.Now(),
+		Sections:   s.digestSections(since),
+	}
+	if len(digest.Sections) == 0 {
+		log.Printf("[Tick %d] No forum activity for the %s digest", s.ticks, covered)
+		return
+	}
+
+	if !s.forum.HasSubreddit(DigestSubreddit) {
+		err := s.forum.CreateSubreddit(&types.SubredditInfo{
+			Name:        DigestSubreddit,
+			Description: prompts.For(s.lang.Default()).DigestSubreddit,
+			CreatorID:   ar.persona.ID,
+			CreatorName: ar.persona.Name,
+			CreatedAt:   s.simTime,
+		})
+		if err != nil {
+			log.Printf("[Tick %d] Creating r/%s failed: %v", s.ticks, DigestSubreddit, err)
+			return
+		}
+	}
+	text := prompts.For(ar.lang)
+	pub := &types.Publication{
+		AuthorID:   ar.persona.ID,
+		AuthorName: ar.persona.Name,
+		Title:      fmt.Sprintf(text.DigestTitle, covered),
+		Content:    renderDigest(text, digest),
+		Subreddit:  DigestSubreddit,
+	}
+	if err := s.forum.Post(pub); err != nil {
+		log.Printf("[Tick %d] Posting the %s digest failed: %v", s.ticks, covered, err)
+		return
+	}
+	digest.PostID = pub.ID
+	s.digests.Add(digest)
+	s.actionStats[ActionDigest]++
+	log.Printf("[Tick %d] %s posted the %s digest %s", s.ticks, ar.persona.Name, covered, pub.ID)
+
+	if s.logger == nil {
+		return
+	}
+	entry := EventLog{
+		Timestamp: time.Now(),
+		SimTime:   s.simTime,
+		Tick:      s.ticks,
+		AgentID:   ar.persona.ID,
+		AgentName: ar.persona.Name,
+		ModelName: ar.modelName,
+		Action:    ActionDigest,
+		Response:  fmt.Sprintf("%s (%s, %d subreddits)", pub.Title, pub.ID, len(digest.Sections)),
+	}
+	if err := s.logger.LogEvent(entry); err != nil {
+		log.Printf("Failed to log digest: %v", err)
+	}
+}
+
+// digestCompiler picks the active communicator whose turn it is: they take
+// turns in ID order, one digest each.
+func (s *ADKScheduler) digestCompiler() *agentRunner {
+	var ids []string
+	for id, ar := range s.runners {
+		if !ar.retired && ar.persona != nil && ar.persona.Role == types.RoleCommunicator {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Strings(ids)
+	return s.runners[ids[len(s.digests.Digests)%len(ids)]]
+}
+
+// digestSections ranks the threads posted or replied to after since by
+// score, then replies, and keeps the top few of each subreddit. Subreddits
+// come in name order; r/meta itself is left out.
+func (s *ADKScheduler) digestSections(since time.Time) []site.DigestSection {
+	bySub := make(map[types.Subreddit][]site.DigestThread)
+	for _, post := range s.forum.AllPosts() {
+		if post.IsComment || !post.Visible() || post.Subreddit == DigestSubreddit {
+			continue
+		}
+		comments := s.forum.GetThreadComments(post.ID)
+		active := post.PublishedAt.After(since)
+		for _, c := range comments {
+			active = active || c.PublishedAt.After(since)
+		}
+		if !active {
+			continue
+		}
+		summary := ""
+		if ts := s.forum.GetThreadSummary(post.ID); ts != nil {
+			summary = ts.Summary
+		}
+		if summary == "" {
+			summary = post.Abstract
+		}
+		bySub[post.Subreddit] = append(bySub[post.Subreddit], site.DigestThread{
+			PostID:     post.ID,
+			Title:      post.Title,
+			AuthorName: post.AuthorName,
+			Score:      post.Score,
+			Replies:    len(comments),
+			Summary:    truncateRunes(strings.TrimSpace(summary), digestSummaryMaxChars),
+		})
+	}
+
+	out := make([]site.DigestSection, 0, len(bySub))
+	for sub, threads := range bySub {
+		sort.Slice(threads, func(i, j int) bool {
+			a, b := threads[i], threads[j]
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+			if a.Replies != b.Replies {
+				return a.Replies > b.Replies
+			}
+			return a.PostID < b.PostID
+		})
+		if len(threads) > digestThreadsPerSubreddit {
+			threads = threads[:digestThreadsPerSubreddit]
+		}
+		out = append(out, site.DigestSection{Subreddit: string(sub), Threads: threads})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Subreddit < out[j].Subreddit })
+	return out
+}
+
+// renderDigest writes a digest as the Markdown body of its forum post.
+func renderDigest(text *prompts.Catalog, d site.Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, text.DigestIntro, d.Week)
+	for _, sec := range d.Sections {
+		fmt.Fprintf(&b, text.DigestSection, sec.Subreddit)
+		for _, t := range sec.Threads {
+			fmt.Fprintf(&b, text.DigestThread, t.Title, t.PostID, t.AuthorName, t.Score, t.Replies)
+			if t.Summary != "" {
+				fmt.Fprintf(&b, text.DigestSummary, strings.Join(strings.Fields(t.Summary), " "))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
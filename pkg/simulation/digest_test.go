@@ -0,0 +1,90 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/site"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_PostsWeeklyDigest(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 8, 23, 0, 0, 0, time.UTC), // Sunday of 2026-W06
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		WeeklyDigest:    true,
+	})
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Ada", Role: types.RoleCommunicator}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	for _, p := range []*types.Publication{
+		{ID: "post-1", Title: "Engines", AuthorName: "Bob", Content: "!", Subreddit: types.SubPhysics},
+		{ID: "post-2", Title: "Primes", AuthorName: "Cy", Content: "!", Abstract: "Gaps between primes", Subreddit: types.SubMathematics},
+	} {
+		if err := forum.Post(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := forum.SaveThreadSummary("post-1", "Carnot bounds engine efficiency."); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+	digest := sched.digests.Latest()
+	if digest == nil || digest.Week != "2026-W06" || digest.AuthorID != "agent-1" || len(digest.Sections) != 2 {
+		t.Fatalf("expected a W06 digest with two sections, got %+v", digest)
+	}
+	if digest.Sections[0].Subreddit != "mathematics" || digest.Sections[1].Threads[0].Summary != "Carnot bounds engine efficiency." {
+		t.Fatalf("unexpected sections %+v", digest.Sections)
+	}
+	post := forum.Get(digest.PostID)
+	if post == nil || post.Subreddit != DigestSubreddit || !strings.Contains(post.Content, "post-2") || !strings.Contains(post.Content, "Gaps between primes") {
+		t.Fatalf("expected the digest in r/meta, got %+v", post)
+	}
+	if !forum.HasSubreddit(DigestSubreddit) {
+		t.Fatalf("expected r/%s to be created", DigestSubreddit)
+	}
+	if last := logger.events[len(logger.events)-1]; last.Action != ActionDigest {
+		t.Fatalf("expected a digest feed event, got %+v", last)
+	}
+
+	// Another tick in the same week posts nothing new.
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	if len(sched.digests.Digests) != 1 {
+		t.Fatalf("expected one digest, got %d", len(sched.digests.Digests))
+	}
+	if err := sched.Checkpoint(); err != nil {
+		t.Fatalf("Checkpoint: %v", err)
+	}
+	idx, err := site.LoadDigestIndex(filepath.Join(tempDir, digestFile))
+	if err != nil || len(idx.Digests) != 1 || idx.Digests[0].PostID != digest.PostID {
+		t.Fatalf("expected digest.json with the digest, got %+v (%v)", idx, err)
+	}
+}
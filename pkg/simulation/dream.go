@@ -0,0 +1,297 @@
+package simulation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+
+	"github.com/cpunion/sci-bot/pkg/memory"
+	"github.com/cpunion/sci-bot/pkg/prompts"
+)
+
+const (
+	summaryMaxChars = 2000
+
+	// dreamMaxEntries caps how many of the day's daily log entries (newest
+	// first) are handed to the consolidation pass.
+	dreamMaxEntries = 60
+	// dreamNightlyExperiences caps new experiences stored per night.
+	dreamNightlyExperiences = 5
+	// dreamMaxExperiences caps the experiences kept in core memory.
+	dreamMaxExperiences = 200
+	// dreamSummaryLessons is how many recent lessons ride along in agent_summary.
+	dreamSummaryLessons = 5
+)
+
+// dreamResult is the JSON the consolidation pass is asked to return.
+type dreamResult struct {
+	Summary     string `json:"summary"`
+	Experiences []struct {
+		Summary string `json:"summary"`
+		Lesson  string `json:"lesson"`
+	} `json:"experiences"`
+	Topics []string `json:"topics"`
+}
+
+// consolidateMemory runs the nightly "dream" pass for an agent whose bell just
+// rang: the day's daily log is compressed by the agent's model into core
+// memory experiences and a fresh summary snapshot, which then replaces the
+// rolling agent_summary. On any failure the rolling summary is left as is.
+func (s *ADKScheduler) consolidateMemory(ctx context.Context, ar *agentRunner) {
+	if s.dataPath == "" || ar == nil || ar.memory == nil {
+		return
+	}
+	id := ar.persona.ID
+	dateKey := s.simTime.Format("2006-01-02")
+	entries, err := readDailyLog(filepath.Join(s.dataPath, "agents", id, "daily", dateKey+".jsonl"))
+	if err != nil || len(entries) == 0 {
+		return
+	}
+	if s.budget.AgentExhausted(id, s.simTime) {
+		return
+	}
+
+	mem := ar.memory
+	prompt := actionPrompt{action: "dream", text: fmt.Sprintf(prompts.For(ar.lang).DreamAction, dateKey)}
+	text, usage, err := s.generateDream(ctx, ar, buildDreamPrompt(ar.persona.Name, dateKey, mem, entries, ar.lang))
+	s.recordUsage(id, usage)
+	s.actionStats[prompt.action]++
+	if err != nil {
+		s.logEvent(ar, prompt, text, err, nil, nil, nil, usage)
+		return
+	}
+	result, err := parseDreamResult(text)
+	if err != nil {
+		s.logEvent(ar, prompt, text, err, nil, nil, nil, usage)
+		return
+	}
+
+	for i, exp := range result.Experiences {
+		if i >= dreamNightlyExperiences {
+			break
+		}
+		summary, lesson := strings.TrimSpace(exp.Summary), strings.TrimSpace(exp.Lesson)
+		if summary == "" && lesson == "" {
+			continue
+		}
+		exp := memory.Experience{
+			ID:         fmt.Sprintf("exp-%s-%d", dateKey, i+1),
+			Summary:    headRunes(summary, 300),
+			Lesson:     headRunes(lesson, 300),
+			OccurredAt: s.simTime,
+		}
+		mem.AddExperience(exp)
+		if err := mem.Remember(ctx, "experience", strings.TrimSpace(exp.Summary+"\n"+exp.Lesson), s.simTime); err != nil {
+			log.Printf("Failed to remember experience for %s: %v", id, err)
+		}
+	}
+	if n := len(mem.Core.Experiences); n > dreamMaxExperiences {
+		mem.Core.Experiences = mem.Core.Experiences[n-dreamMaxExperiences:]
+	}
+	mem.Summary.Snapshot = truncateRunes(strings.TrimSpace(result.Summary), summaryMaxChars)
+	mem.Summary.Topics = result.Topics
+	mem.Summary.UpdatedAt = s.simTime
+	if err := mem.Save(); err != nil {
+		log.Printf("Failed to save memory for %s: %v", id, err)
+		return
+	}
+
+	if err := s.replaceAgentSummary(ctx, ar, memorySummary(mem, ar.lang)); err != nil {
+		log.Printf("Failed to replace summary for %s: %v", id, err)
+	}
+	s.logEvent(ar, prompt, mem.Summary.Snapshot, nil, nil, nil, nil, usage)
+}
+
+// generateDream makes a single tool-less call to the agent's model.
+func (s *ADKScheduler) generateDream(ctx context.Context, ar *agentRunner, prompt string) (string, tokenTotals, error) {
+	llm := s.resolveModel(ar.persona)
+	if llm == nil {
+		return "", tokenTotals{}, fmt.Errorf("no LLM model configured for agent %s", ar.persona.ID)
+	}
+	return s.generateText(ctx, llm, ar.provider, prompt)
+}
+
+// generateText makes a single tool-less call to llm with retries and the
+// provider's rate limit, returning the reply text.
+func (s *ADKScheduler) generateText(ctx context.Context, llm model.LLM, provider, prompt string) (string, tokenTotals, error) {
+	var usage tokenTotals
+	llm = s.withRetry(llm, provider)
+
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			MaxOutputTokens: s.maxOutputTokens,
+		},
+	}
+	var text strings.Builder
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return text.String(), usage, err
+		}
+		if resp == nil {
+			continue
+		}
+		if resp.UsageMetadata != nil {
+			usage.add(resp.UsageMetadata)
+		}
+		if resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				if part.Text != "" && !part.Thought {
+					text.WriteString(part.Text)
+				}
+			}
+		}
+	}
+	return text.String(), usage, nil
+}
+
+func buildDreamPrompt(name, dateKey string, mem *memory.Memory, entries []dailyLogEntry, lang prompts.Lang) string {
+	text := prompts.For(lang)
+	var b strings.Builder
+	fmt.Fprintf(&b, text.DreamIntro, name, dateKey)
+	if snap := strings.TrimSpace(mem.Summary.Snapshot); snap != "" {
+		fmt.Fprintf(&b, text.DreamSummary, snap)
+	}
+	if lessons := recentLessons(mem, dreamSummaryLessons); len(lessons) > 0 {
+		b.WriteString(text.DreamLessons)
+		for _, l := range lessons {
+			fmt.Fprintf(&b, "- %s\n", l)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(text.DreamToday)
+	for _, e := range entries {
+		fmt.Fprintf(&b, text.DreamEntry, e.Timestamp, headRunes(e.Prompt, 120))
+		if e.Reply != "" {
+			fmt.Fprintf(&b, text.DreamReply, headRunes(e.Reply, 400))
+		}
+		if e.Error != "" {
+			fmt.Fprintf(&b, text.DreamError, headRunes(e.Error, 120))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, text.DreamOutput, summaryMaxChars/2, dreamNightlyExperiences)
+	return b.String()
+}
+
+// parseDreamResult extracts the JSON object from a model reply, tolerating
+// code fences or surrounding prose.
+func parseDreamResult(text string) (*dreamResult, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("dream: no JSON object in reply")
+	}
+	var result dreamResult
+	if err := json.Unmarshal([]byte(text[start:end+1]), &result); err != nil {
+		return nil, fmt.Errorf("dream: %w", err)
+	}
+	if strings.TrimSpace(result.Summary) == "" {
+		return nil, fmt.Errorf("dream: empty summary")
+	}
+	return &result, nil
+}
+
+// memorySummary renders long-term memory for the agent_summary prompt slot.
+func memorySummary(mem *memory.Memory, lang prompts.Lang) string {
+	if mem == nil || mem.Summary == nil {
+		return ""
+	}
+	snapshot := strings.TrimSpace(mem.Summary.Snapshot)
+	lessons := recentLessons(mem, dreamSummaryLessons)
+	if len(lessons) == 0 {
+		return truncateRunes(snapshot, summaryMaxChars)
+	}
+	var b strings.Builder
+	b.WriteString(prompts.For(lang).SummaryLessons)
+	for _, l := range lessons {
+		b.WriteString("\n- " + headRunes(l, 150))
+	}
+	tail := b.String()
+	budget := summaryMaxChars - len([]rune(tail))
+	if r := []rune(snapshot); len(r) > budget {
+		snapshot = string(r[:budget])
+	}
+	return snapshot + tail
+}
+
+// recentLessons returns up to n lessons, newest last.
+func recentLessons(mem *memory.Memory, n int) []string {
+	out := make([]string, 0, n)
+	exps := mem.Core.Experiences
+	for i := len(exps) - 1; i >= 0 && len(out) < n; i-- {
+		if l := strings.TrimSpace(exps[i].Lesson); l != "" {
+			out = append(out, l)
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// headRunes keeps the first maxChars runes of s.
+func headRunes(s string, maxChars int) string {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); maxChars > 0 && len(r) > maxChars {
+		return string(r[:maxChars])
+	}
+	return s
+}
+
+func (s *ADKScheduler) replaceAgentSummary(ctx context.Context, ar *agentRunner, summary string) error {
+	if ar.session == nil {
+		return nil
+	}
+	sessResp, err := ar.session.Get(ctx, &session.GetRequest{
+		AppName:   ar.appName,
+		UserID:    ar.persona.ID,
+		SessionID: ar.sessionID,
+	})
+	if err != nil {
+		return err
+	}
+	event := session.NewEvent("memory-consolidation")
+	event.Author = ar.persona.ID
+	event.Actions.StateDelta["agent_summary"] = summary
+	return ar.session.AppendEvent(ctx, sessResp.Session, event)
+}
+
+func readDailyLog(path string) ([]dailyLogEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make([]dailyLogEntry, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e dailyLogEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) > dreamMaxEntries {
+		entries = entries[len(entries)-dreamMaxEntries:]
+	}
+	return entries, nil
+}
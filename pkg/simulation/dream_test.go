@@ -0,0 +1,83 @@
+package simulation
+
+import (
+	"context"
+	"iter"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/memory"
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// dreamLLM answers consolidation requests with memory JSON and everything
+// else with a plain reply.
+type dreamLLM struct{}
+
+func (dreamLLM) Name() string { return "dream-llm" }
+
+func (dreamLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	text := "ok"
+	for _, c := range req.Contents {
+		for _, p := range c.Parts {
+			if strings.Contains(p.Text, "沉淀为长期记忆") {
+				text = "```json\n{\"summary\": \"讨论了暗物质\", \"experiences\": [{\"summary\": \"发帖\", \"lesson\": \"先读再评\"}], \"topics\": [\"dark-matter\"]}\n```"
+			}
+		}
+	}
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleModel)}, nil)
+	}
+}
+
+func TestADKScheduler_DreamConsolidatesMemory(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           dreamLLM{},
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		TurnLimit:       1,
+		Dream:           true,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Dreamer", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	// One regular turn, then the bell rings and the dream pass follows.
+	for i := 0; i < 2; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	last := logger.events[len(logger.events)-1]
+	if last.Action != "dream" || last.Error != "" {
+		t.Fatalf("expected successful dream event, got %s (error %q)", last.Action, last.Error)
+	}
+	mem := memory.NewMemory("agent-1", filepath.Join(tempDir, "agents", "agent-1"), 0)
+	if err := mem.Load(); err != nil {
+		t.Fatalf("Load memory: %v", err)
+	}
+	if mem.Summary.Snapshot != "讨论了暗物质" || len(mem.Core.Experiences) != 1 || mem.Core.Experiences[0].Lesson != "先读再评" {
+		t.Fatalf("unexpected memory: summary=%q experiences=%+v", mem.Summary.Snapshot, mem.Core.Experiences)
+	}
+
+	// A resumed run starts from the consolidated summary.
+	if got := memorySummary(mem, prompts.Chinese); !strings.HasPrefix(got, "讨论了暗物质") || !strings.Contains(got, "先读再评") {
+		t.Fatalf("unexpected seeded summary: %q", got)
+	}
+}
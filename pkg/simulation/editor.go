@@ -0,0 +1,69 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// reviewerPersonas returns the scheduler's reviewer agents in stable order.
+// Caller must hold s.mu.
+func (s *ADKScheduler) reviewerPersonas() []*types.Persona {
+	ids := s.reviewerIDs()
+	out := make([]*types.Persona, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, s.runners[id].persona)
+	}
+	return out
+}
+
+// owesTriage reports whether an editor has untriaged submissions or late
+// reviewers to chase and has not had its editor turn today.
+func (s *ADKScheduler) owesTriage(ar *agentRunner) bool {
+	if ar == nil || ar.persona == nil || ar.persona.Role != types.RoleEditor {
+		return false
+	}
+	if ar.editorDutyDay == s.simTime.Format("2006-01-02") {
+		return false
+	}
+	return len(s.workflow.EditorQueue()) > 0 || len(s.workflow.OverdueReviews(s.simTime, s.editorNagAfter)) > 0
+}
+
+// editorDutyPrompt returns a triage prompt for an editor, at most once per
+// sim day.
+func (s *ADKScheduler) editorDutyPrompt(ar *agentRunner) (actionPrompt, bool) {
+	if s.workflow == nil || !s.owesTriage(ar) {
+		return actionPrompt{}, false
+	}
+	ar.editorDutyDay = s.simTime.Format("2006-01-02")
+
+	queue := s.workflow.EditorQueue()
+	overdue := s.workflow.OverdueReviews(s.simTime, s.editorNagAfter)
+	text := prompts.For(ar.lang)
+	var b strings.Builder
+	b.WriteString(text.EditorDuty)
+	if len(queue) > 0 {
+		fmt.Fprintf(&b, text.EditorQueue, len(queue))
+		for i, sub := range queue {
+			if i == 5 {
+				fmt.Fprintf(&b, text.EditorQueueMore, len(queue)-i)
+				break
+			}
+			fmt.Fprintf(&b, text.EditorQueueItem, sub.ID, sub.Title, sub.AuthorName)
+		}
+	}
+	if len(overdue) > 0 {
+		fmt.Fprintf(&b, text.EditorOverdue, len(overdue))
+		for i, item := range overdue {
+			if i == 5 {
+				fmt.Fprintf(&b, text.EditorOverdueMore, len(overdue)-i)
+				break
+			}
+			fmt.Fprintf(&b, text.EditorOverdueItem,
+				item.SubmissionID, item.Title, item.ReviewerID, item.AssignedAt.Format("2006-01-02"))
+		}
+	}
+	return actionPrompt{action: "editor_duty", text: b.String()}, true
+}
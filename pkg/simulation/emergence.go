@@ -0,0 +1,39 @@
+package simulation
+
+import (
+	"github.com/cpunion/sci-bot/pkg/metrics"
+	"github.com/cpunion/sci-bot/pkg/reputation"
+)
+
+// emergenceFile is the emergence index under the data root.
+const emergenceFile = "emergence.json"
+
+// recordEmergence scores the forum posts and comments published since the
+// last checkpoint and the current spread of citations across approved
+// papers. Caller must hold s.mu.
+func (s *ADKScheduler) recordEmergence() {
+	if s.emergence == nil || s.forum == nil {
+		return
+	}
+	pubs := s.forum.AllPublications()
+	docs := make([]metrics.Document, 0, len(pubs))
+	for _, p := range pubs {
+		docs = append(docs, metrics.Document{
+			ID:          p.ID,
+			Topic:       string(p.Subreddit),
+			Text:        p.Title + "\n" + p.Content,
+			PublishedAt: p.PublishedAt,
+			Exogenous:   p.Exogenous(),
+		})
+	}
+	var citations []int
+	if s.journal != nil {
+		cited := reputation.Citations(s.journal, s.forum)
+		for _, p := range s.journal.GetApproved() {
+			if !p.Exogenous() {
+				citations = append(citations, cited[p.ID])
+			}
+		}
+	}
+	s.emergence.Record(s.simTime, s.ticks, docs, citations)
+}
@@ -0,0 +1,39 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+)
+
+// groupCheckInPrompt asks a research group member to catch up on its groups
+// and coordinate, at most once per groupCheckIn of sim time.
+func (s *ADKScheduler) groupCheckInPrompt(ar *agentRunner) (actionPrompt, bool) {
+	if s.groups == nil || s.groupCheckIn <= 0 {
+		return actionPrompt{}, false
+	}
+	if s.simTime.Sub(ar.groupCheckedInAt) < s.groupCheckIn {
+		return actionPrompt{}, false
+	}
+	groups := s.groups.List(ar.persona.ID)
+	if len(groups) == 0 {
+		return actionPrompt{}, false
+	}
+	ar.groupCheckedInAt = s.simTime
+
+	seen := ar.groupMessagesSeen
+	ar.groupMessagesSeen = make(map[string]int, len(groups))
+	text := prompts.For(ar.lang)
+	var b strings.Builder
+	b.WriteString(text.GroupCheckIn)
+	for _, g := range groups {
+		ar.groupMessagesSeen[g.ID] = g.MessageCount
+		fmt.Fprintf(&b, text.GroupItem, g.ID, g.Name, g.Topic, len(g.Members), len(g.Drafts))
+		if unread := g.MessageCount - seen[g.ID]; unread > 0 {
+			fmt.Fprintf(&b, text.GroupUnread, unread)
+		}
+		b.WriteString("\n")
+	}
+	return actionPrompt{action: "group", text: b.String()}, true
+}
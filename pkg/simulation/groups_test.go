@@ -0,0 +1,63 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestADKScheduler_PromptsGroupCheckIns(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := &memoryLogger{}
+	start := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           dreamLLM{},
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       start,
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		TurnLimit:       100,
+		GroupCheckIn:    3 * time.Hour,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Ada", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	g, err := sched.groups.Create("Dark Sector", "dark matter", group.Member{ID: "agent-1", Name: "Ada", JoinedAt: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sched.groups.Post(g.ID, group.Message{AuthorID: "agent-1", Content: "kickoff"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	var checkIns []EventLog
+	for _, ev := range logger.events {
+		if ev.Action == "group" {
+			checkIns = append(checkIns, ev)
+		}
+	}
+	// Ticks run at 09:00..15:00; check-ins are due from 12:00, then 15:00.
+	if len(checkIns) != 2 || checkIns[0].SimTime.Hour() != 12 || checkIns[1].SimTime.Hour() != 15 {
+		t.Fatalf("expected check-ins at 12:00 and 15:00, got %d: %+v", len(checkIns), checkIns)
+	}
+	if !strings.Contains(checkIns[0].Prompt, g.ID) || !strings.Contains(checkIns[0].Prompt, "1 条新消息") || strings.Contains(checkIns[1].Prompt, "新消息") {
+		t.Fatalf("unexpected prompts:\n%s\n%s", checkIns[0].Prompt, checkIns[1].Prompt)
+	}
+}
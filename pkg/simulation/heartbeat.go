@@ -0,0 +1,62 @@
+package simulation
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// emitHeartbeats logs a liveness event for every agent that took no turn this
+// tick and has been quiet for at least heartbeatEvery of sim time, so idle
+// agents can be told apart from crashed ones.
+func (s *ADKScheduler) emitHeartbeats(active map[string]bool) {
+	if s.heartbeatEvery <= 0 || s.logger == nil {
+		return
+	}
+	ids := make([]string, 0, len(s.runners))
+	for id := range s.runners {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		ar := s.runners[id]
+		if ar == nil || ar.retired || active[id] || s.simTime.Sub(ar.lastSeen) < s.heartbeatEvery {
+			continue
+		}
+		ar.lastSeen = s.simTime
+		liveness := s.livenessOf(ar)
+		ev := EventLog{
+			Timestamp:      time.Now(),
+			SimTime:        s.simTime,
+			Tick:           s.ticks,
+			AgentID:        ar.persona.ID,
+			AgentName:      ar.persona.Name,
+			ModelName:      ar.modelName,
+			Action:         ActionHeartbeat,
+			TurnCount:      ar.turnCount,
+			BellRung:       ar.bellRung,
+			GraceRemaining: ar.graceRemaining,
+			Sleeping:       liveness != LivenessSkipped,
+			Liveness:       liveness,
+			IdleTicks:      s.ticks - ar.lastTick,
+		}
+		if err := s.logger.LogEvent(ev); err != nil {
+			log.Printf("Failed to log heartbeat: %v", err)
+		}
+	}
+}
+
+// livenessOf mirrors the checks in eligibleAgentIDs.
+func (s *ADKScheduler) livenessOf(ar *agentRunner) string {
+	switch {
+	case s.offDuty():
+		return LivenessSleeping
+	case s.budget.AgentExhausted(ar.persona.ID, s.simTime):
+		return LivenessBudgetPaused
+	case ar.bellRung && ar.graceRemaining <= 0:
+		return LivenessSleeping
+	default:
+		return LivenessSkipped
+	}
+}
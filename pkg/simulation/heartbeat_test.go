@@ -0,0 +1,62 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_HeartbeatsForSleepingAgents(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		TurnLimit:       1,
+		GraceTurns:      1,
+		HeartbeatEvery:  time.Hour,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Sleeper", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	// Turn, bell, grace turn; then the agent sleeps for two ticks.
+	for i := 0; i < 5; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	beats := make([]EventLog, 0)
+	for _, ev := range logger.events {
+		if ev.Action == ActionHeartbeat {
+			beats = append(beats, ev)
+		}
+	}
+	if len(beats) != 2 {
+		t.Fatalf("expected 2 heartbeats, got %d (events=%d)", len(beats), len(logger.events))
+	}
+	for i, ev := range beats {
+		if ev.Liveness != LivenessSleeping || ev.IdleTicks != i+1 || ev.Prompt != "" {
+			t.Fatalf("unexpected heartbeat %d: %+v", i, ev)
+		}
+	}
+}
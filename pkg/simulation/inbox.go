@@ -0,0 +1,84 @@
+package simulation
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ActionVisitorQuestion marks log events for spectator questions posted
+// from the inbox.
+const ActionVisitorQuestion = "visitor_question"
+
+// postVisitorQuestions posts the most-voted approved spectator questions
+// to the forum as the visitor account, at most once per inbox interval of
+// sim time, and records the thread each became so the asker's permalink
+// can follow the discussion. Caller must hold s.mu.
+func (s *ADKScheduler) postVisitorQuestions() {
+	if s.inbox == nil || s.forum == nil {
+		return
+	}
+	if err := s.inbox.Reload(); err != nil {
+		log.Printf("[Tick %d] Reloading the inbox failed: %v", s.ticks, err)
+		return
+	}
+	every := s.inboxInterval
+	if every <= 0 {
+		every = 24 * time.Hour
+	}
+	if last := s.inbox.LastPosted(); !last.IsZero() && s.simTime.Sub(last) < every {
+		return
+	}
+	n := s.inboxQuestions
+	if n <= 0 {
+		n = 1
+	}
+
+	text := prompts.For(s.lang.Default())
+	for _, q := range s.inbox.Next(n) {
+		sub := q.Subreddit
+		if sub == "" || !s.forum.HasSubreddit(sub) {
+			sub = types.SubGeneral
+		}
+		asker := q.AskerName
+		if asker == "" {
+			asker = text.VisitorName
+		}
+		pub := &types.Publication{
+			AuthorID:   inbox.VisitorID,
+			AuthorName: text.VisitorName,
+			Title:      q.Title,
+			Content:    q.Question + fmt.Sprintf(text.VisitorFooter, asker, q.Votes),
+			Subreddit:  sub,
+		}
+		if err := s.forum.Post(pub); err != nil {
+			log.Printf("[Tick %d] Posting visitor question %s failed: %v", s.ticks, q.ID, err)
+			continue
+		}
+		if err := s.inbox.MarkPosted(q.ID, pub.ID, s.simTime); err != nil {
+			log.Printf("[Tick %d] Marking visitor question %s posted failed: %v", s.ticks, q.ID, err)
+		}
+		s.actionStats[ActionVisitorQuestion]++
+		log.Printf("[Tick %d] Posted visitor question %s as %s in r/%s", s.ticks, q.ID, pub.ID, sub)
+
+		if s.logger == nil {
+			continue
+		}
+		entry := EventLog{
+			Timestamp: time.Now(),
+			SimTime:   s.simTime,
+			Tick:      s.ticks,
+			AgentID:   inbox.VisitorID,
+			AgentName: text.VisitorName,
+			Action:    ActionVisitorQuestion,
+			Response:  fmt.Sprintf("%s (%s, %d votes, question %s)", pub.Title, pub.ID, q.Votes, q.ID),
+		}
+		if err := s.logger.LogEvent(entry); err != nil {
+			log.Printf("Failed to log visitor question: %v", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/inbox"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestScheduler_PostsVisitorQuestionsPerInterval(t *testing.T) {
+	tempDir := t.TempDir()
+	box, err := inbox.Open(filepath.Join(tempDir, inbox.FileName))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	ask := func(title, sub string, votes int) string {
+		q, err := box.Ask(title, "Why?", sub, "Zoe", inbox.VisitorKey(title))
+		if err != nil {
+			t.Fatalf("Ask: %v", err)
+		}
+		if _, err := box.Moderate(q.ID, true, "human-mod", ""); err != nil {
+			t.Fatalf("Moderate: %v", err)
+		}
+		for i := range votes {
+			box.Vote(q.ID, inbox.VisitorKey(string(rune('a'+i))))
+		}
+		return q.ID
+	}
+	popular := ask("Is light a wave?", "optics", 2)
+	later := ask("Do cells age?", "", 0)
+
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		Logger:    logger,
+		SimStep:   time.Hour,
+		StartTime: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Inbox:     box,
+	})
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	for range 3 {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+	q, _ := box.Get(popular)
+	if q.Status != inbox.Posted || q.PostID == "" {
+		t.Fatalf("most-voted question = %+v, want posted", q)
+	}
+	post := forum.Get(q.PostID)
+	if post == nil || post.AuthorID != inbox.VisitorID || post.Subreddit != types.SubGeneral || !strings.Contains(post.Content, "Zoe") {
+		t.Fatalf("posted thread = %+v", post)
+	}
+	if q, _ := box.Get(later); q.Status != inbox.Approved {
+		t.Fatalf("second question posted within the interval")
+	}
+
+	sched.simTime = sched.simTime.Add(24 * time.Hour)
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	if q, _ := box.Get(later); q.Status != inbox.Posted {
+		t.Fatalf("second question not posted after the interval: %+v", q)
+	}
+	if n := sched.actionStats[ActionVisitorQuestion]; n != 2 {
+		t.Errorf("visitor questions = %d, want 2", n)
+	}
+	if len(logger.events) != 2 || logger.events[0].Action != ActionVisitorQuestion {
+		t.Errorf("events = %+v", logger.events)
+	}
+}
@@ -0,0 +1,211 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+
+	pkgagent "github.com/cpunion/sci-bot/pkg/agent"
+	"github.com/cpunion/sci-bot/pkg/memory"
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// interviewListed caps the peers, beliefs and theories an interview
+// instruction lists.
+const interviewListed = 12
+
+// InterviewConfig configures an interview with a saved agent.
+type InterviewConfig struct {
+	DataPath string // the run's data directory
+	Persona  *types.Persona
+	Model    model.LLM
+	Lang     prompts.Lang
+	// Templates renders the agent's instruction as in the run (nil: the
+	// built-in templates).
+	Templates *prompts.Templates
+	// ProfilesDir holds per-agent profile files, as
+	// ADKSchedulerConfig.ProfilesDir (empty: none).
+	ProfilesDir     string
+	MaxOutputTokens int32
+}
+
+// Interview is a conversation between a human and a saved agent outside
+// the simulation. The agent's memory, state and profile are read once, when
+// the interview starts, and never written; the agent has no tools, so the
+// conversation cannot change the world either.
+type Interview struct {
+	cfg         InterviewConfig
+	instruction string
+	history     []*genai.Content
+
+	// Token usage of the interview so far.
+	PromptTokens int
+	OutputTokens int
+}
+
+// NewInterview loads the agent from cfg.DataPath and builds its interview
+// instruction.
+func NewInterview(cfg InterviewConfig) (*Interview, error) {
+	if cfg.Persona == nil || cfg.Persona.ID == "" {
+		return nil, fmt.Errorf("interview: missing persona")
+	}
+	if cfg.Model == nil {
+		return nil, fmt.Errorf("interview: no model for agent %s", cfg.Persona.ID)
+	}
+	agentPath := filepath.Join(cfg.DataPath, "agents", cfg.Persona.ID)
+	state, err := pkgagent.LoadAgentState(agentPath)
+	if err != nil {
+		return nil, fmt.Errorf("load state of %s: %w", cfg.Persona.ID, err)
+	}
+	mem := memory.NewMemory(cfg.Persona.ID, agentPath, 0)
+	if err := mem.Load(); err != nil {
+		return nil, fmt.Errorf("load memory of %s: %w", cfg.Persona.ID, err)
+	}
+
+	persona := *cfg.Persona
+	if state.AgentName != "" {
+		persona.Name = state.AgentName
+	}
+	if rec, ok := state.GetTraits(); ok {
+		persona.SetTraits(rec.Current)
+	}
+	cfg.Persona = &persona
+
+	text := prompts.For(cfg.Lang)
+	instruction := &agentInstruction{heading: text.ProfileHeading}
+	if cfg.ProfilesDir != "" {
+		if _, err := instruction.reload(filepath.Join(cfg.ProfilesDir, persona.ID)); err != nil {
+			return nil, fmt.Errorf("load profile of %s: %w", persona.ID, err)
+		}
+	}
+	var b strings.Builder
+	b.WriteString(strings.ReplaceAll(cfg.Templates.Instruction(cfg.Lang, &persona), summarySlot, memorySummary(mem, cfg.Lang)))
+	if instruction.profile != "" {
+		b.WriteString(instruction.heading + instruction.profile)
+	}
+	b.WriteString(interviewContext(text, mem, state))
+	return &Interview{cfg: cfg, instruction: b.String()}, nil
+}
+
+// interviewContext lists what the agent believes and whom and what it
+// knows, which its action instruction leaves to tools.
+func interviewContext(text *prompts.Catalog, mem *memory.Memory, state *pkgagent.AgentState) string {
+	var b strings.Builder
+	b.WriteString(text.InterviewIntro)
+
+	if beliefs := mem.Core.Beliefs; len(beliefs) > 0 {
+		keys := make([]string, 0, len(beliefs))
+		for k := range beliefs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString(text.InterviewBeliefs)
+		for _, k := range keys[:min(len(keys), interviewListed)] {
+			fmt.Fprintf(&b, text.InterviewBelief, k, headRunes(beliefs[k], 200))
+		}
+	}
+
+	peers := make([]*types.Relationship, 0, len(state.Relationships))
+	for _, rel := range state.Relationships {
+		if rel != nil {
+			peers = append(peers, rel)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].InteractionCount != peers[j].InteractionCount {
+			return peers[i].InteractionCount > peers[j].InteractionCount
+		}
+		return peers[i].PeerID < peers[j].PeerID
+	})
+	if len(peers) > 0 {
+		b.WriteString(text.InterviewPeers)
+		for _, rel := range peers[:min(len(peers), interviewListed)] {
+			name := rel.PeerName
+			if name == "" {
+				name = rel.PeerID
+			}
+			fmt.Fprintf(&b, text.InterviewPeer, name, rel.State, rel.TrustScore, rel.InteractionCount)
+		}
+	}
+
+	theories := make([]*types.KnowledgeItem, 0, len(state.Knowledge))
+	for _, k := range state.Knowledge {
+		if k != nil {
+			theories = append(theories, k)
+		}
+	}
+	sort.Slice(theories, func(i, j int) bool {
+		if theories[i].Confidence != theories[j].Confidence {
+			return theories[i].Confidence > theories[j].Confidence
+		}
+		return theories[i].TheoryID < theories[j].TheoryID
+	})
+	if len(theories) > 0 {
+		b.WriteString(text.InterviewTheories)
+		for _, k := range theories[:min(len(theories), interviewListed)] {
+			title := k.TheoryTitle
+			if title == "" {
+				title = k.TheoryID
+			}
+			fmt.Fprintf(&b, text.InterviewTheory, title, k.Level, k.Confidence)
+		}
+	}
+	return b.String()
+}
+
+// Persona returns the interviewed agent, with its name and traits as of
+// the end of the run.
+func (iv *Interview) Persona() *types.Persona {
+	return iv.cfg.Persona
+}
+
+// Instruction returns the system instruction the agent answers under.
+func (iv *Interview) Instruction() string {
+	return iv.instruction
+}
+
+// Ask sends question with the conversation so far and returns the agent's
+// reply. A failed call leaves the conversation as it was.
+func (iv *Interview) Ask(ctx context.Context, question string) (string, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return "", fmt.Errorf("empty question")
+	}
+	contents := append(append([]*genai.Content(nil), iv.history...), genai.NewContentFromText(question, genai.RoleUser))
+	req := &model.LLMRequest{
+		Contents: contents,
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(iv.instruction, genai.RoleUser),
+			MaxOutputTokens:   iv.cfg.MaxOutputTokens,
+		},
+	}
+	var text strings.Builder
+	for resp, err := range iv.cfg.Model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", err
+		}
+		if resp == nil {
+			continue
+		}
+		if u := resp.UsageMetadata; u != nil {
+			iv.PromptTokens += int(u.PromptTokenCount)
+			iv.OutputTokens += int(u.CandidatesTokenCount + u.ThoughtsTokenCount)
+		}
+		if resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				if part.Text != "" && !part.Thought {
+					text.WriteString(part.Text)
+				}
+			}
+		}
+	}
+	reply := strings.TrimSpace(text.String())
+	iv.history = append(contents, genai.NewContentFromText(reply, genai.RoleModel))
+	return reply, nil
+}
@@ -0,0 +1,99 @@
+package simulation
+
+import (
+	"context"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// interviewLLM records requests and echoes the number of contents sent.
+type interviewLLM struct {
+	reqs []*adkmodel.LLMRequest
+}
+
+func (m *interviewLLM) Name() string { return "interview-llm" }
+
+func (m *interviewLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	m.reqs = append(m.reqs, req)
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{
+			Content:       genai.NewContentFromText("I still doubt the aether.", genai.RoleModel),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 7},
+		}, nil)
+	}
+}
+
+func TestInterview_AnswersFromSavedAgentWithoutWriting(t *testing.T) {
+	dataPath := t.TempDir()
+	agentPath := filepath.Join(dataPath, "agents", "a1")
+	files := map[string]string{
+		"state.json":       `{"agent_id":"a1","agent_name":"Ada","relationships":{"a2":{"peer_id":"a2","peer_name":"Bo","state":"trusted","trust_score":0.9,"interaction_count":12}}}`,
+		"summary.json":     `{"snapshot":"Argued with Bo about the aether drift experiment."}`,
+		"core_memory.json": `{"beliefs":{"aether":"probably does not exist"}}`,
+	}
+	if err := os.MkdirAll(agentPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(agentPath, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	llm := &interviewLLM{}
+	iv, err := NewInterview(InterviewConfig{
+		DataPath: dataPath,
+		Persona:  &types.Persona{ID: "a1", Name: "a1", Role: types.RoleExplorer},
+		Model:    llm,
+		Lang:     prompts.English,
+	})
+	if err != nil {
+		t.Fatalf("NewInterview: %v", err)
+	}
+	instruction := iv.Instruction()
+	for _, want := range []string{"Ada", "aether drift experiment", "Bo (trusted)", "aether: probably does not exist", "## Interview"} {
+		if !strings.Contains(instruction, want) {
+			t.Errorf("instruction is missing %q:\n%s", want, instruction)
+		}
+	}
+	if strings.Contains(instruction, summarySlot) {
+		t.Errorf("instruction still has the summary slot")
+	}
+
+	for _, q := range []string{"What do you think of the aether?", "And of Bo?"} {
+		if reply, err := iv.Ask(context.Background(), q); err != nil || reply != "I still doubt the aether." {
+			t.Fatalf("Ask(%q) = %q, %v", q, reply, err)
+		}
+	}
+	if n := len(llm.reqs[1].Contents); n != 3 {
+		t.Errorf("second question sent %d contents, want the first exchange plus the question", n)
+	}
+	if len(llm.reqs[1].Config.Tools) != 0 {
+		t.Errorf("interview request has tools")
+	}
+	if iv.PromptTokens != 200 || iv.OutputTokens != 14 {
+		t.Errorf("usage = %d prompt, %d output", iv.PromptTokens, iv.OutputTokens)
+	}
+
+	entries, err := os.ReadDir(agentPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(files) {
+		t.Errorf("interview wrote to the agent directory: %d entries", len(entries))
+	}
+	for name, data := range files {
+		if got, _ := os.ReadFile(filepath.Join(agentPath, name)); string(got) != data {
+			t.Errorf("%s changed", name)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Lifecycle events in the feed log.
+const (
+	ActionRetire  = "retire"
+	ActionRecruit = "recruit"
+)
+
+// updatePopulation retires agents that have been idle or fallen below the
+// reputation floor and recruits a replacement for each. Called from RunTick
+// with s.mu held, after reputations are refreshed.
+func (s *ADKScheduler) updatePopulation(ctx context.Context) {
+	if s.retireIdle <= 0 && s.retireReputation == 0 {
+		return
+	}
+	ids := make([]string, 0, len(s.runners))
+	for id := range s.runners {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		ar := s.runners[id]
+		reason := s.retirementReason(ar)
+		if reason == "" {
+			continue
+		}
+		ar.retired = true
+		s.notifier.unregister(id)
+		retirement := types.Retirement{At: s.simTime, Reason: reason}
+		log.Printf("[Tick %d] %s retires: %s", s.ticks, ar.persona.Name, reason)
+
+		if recruit := s.recruitFor(ctx, ar.persona); recruit != nil {
+			retirement.ReplacedBy = recruit.persona.ID
+			ar.state.Retire(retirement)
+			s.logLifecycle(ar, ActionRetire, reason)
+			s.logLifecycle(recruit, ActionRecruit, fmt.Sprintf("replaces %s (%s)", ar.persona.Name, ar.persona.ID))
+			continue
+		}
+		ar.state.Retire(retirement)
+		s.logLifecycle(ar, ActionRetire, reason)
+	}
+}
+
+// retirementReason returns why ar should retire now, or "" if it stays.
+func (s *ADKScheduler) retirementReason(ar *agentRunner) string {
+	if ar == nil || ar.retired || ar.persona == nil || ar.state == nil {
+		return ""
+	}
+	// Editors and moderators keep the journal and forum running.
+	if ar.persona.Role == types.RoleEditor || ar.persona.Moderator {
+		return ""
+	}
+	if s.simTime.Sub(ar.joinedAt) < s.minTenure {
+		return ""
+	}
+	if s.retireIdle > 0 {
+		if idle := s.simTime.Sub(ar.lastActedAt); idle >= s.retireIdle {
+			return fmt.Sprintf("inactive for %s", formatSimDuration(idle))
+		}
+	}
+	if s.retireReputation != 0 {
+		if rep, ok := ar.state.GetReputation(); ok && rep.Score < s.retireReputation {
+			return fmt.Sprintf("reputation %.2f below %.2f", rep.Score, s.retireReputation)
+		}
+	}
+	return ""
+}
+
+// recruitFor adds a replacement for retiree, returning its runner, or nil
+// when recruiting is off or fails.
+func (s *ADKScheduler) recruitFor(ctx context.Context, retiree *types.Persona) *agentRunner {
+	if s.recruit == nil {
+		return nil
+	}
+	roster := make([]*types.Persona, 0, len(s.runners))
+	for _, ar := range s.runners {
+		roster = append(roster, ar.persona)
+	}
+	sort.Slice(roster, func(i, j int) bool { return roster[i].ID < roster[j].ID })
+
+	persona := s.recruit(retiree, roster)
+	if persona == nil {
+		return nil
+	}
+	if _, exists := s.runners[persona.ID]; exists {
+		log.Printf("Recruit %s skipped: ID already on the roster", persona.ID)
+		return nil
+	}
+	if err := s.addAgentLocked(ctx, persona); err != nil {
+		log.Printf("Failed to recruit %s: %v", persona.ID, err)
+		return nil
+	}
+	s.recruits = append(s.recruits, persona)
+	return s.runners[persona.ID]
+}
+
+func (s *ADKScheduler) logLifecycle(ar *agentRunner, action, detail string) {
+	if s.logger == nil {
+		return
+	}
+	ev := EventLog{
+		Timestamp: time.Now(),
+		SimTime:   s.simTime,
+		Tick:      s.ticks,
+		AgentID:   ar.persona.ID,
+		AgentName: ar.persona.Name,
+		ModelName: ar.modelName,
+		Action:    action,
+		Response:  detail,
+	}
+	if err := s.logger.LogEvent(ev); err != nil {
+		log.Printf("Failed to log %s: %v", action, err)
+	}
+}
+
+// Recruits returns the personas recruited during this run, in join order.
+func (s *ADKScheduler) Recruits() []*types.Persona {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*types.Persona(nil), s.recruits...)
+}
+
+// formatSimDuration renders d in whole days when it spans at least one.
+func formatSimDuration(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return d.String()
+}
@@ -0,0 +1,95 @@
+package simulation
+
+import (
+	"context"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_RetiresIdleAgentsAndRecruits(t *testing.T) {
+	tempDir := t.TempDir()
+	// Text-only replies never call a tool, so every agent counts as idle.
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	rng := rand.New(rand.NewSource(1))
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   2,
+		CheckpointEvery: 1000,
+		RetireIdle:      3 * time.Hour,
+		MinTenure:       2 * time.Hour,
+		Recruit: func(retiree *types.Persona, roster []*types.Persona) *types.Persona {
+			return RecruitPersona(rng, retiree, roster)
+		},
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	for _, p := range []*types.Persona{
+		{ID: "agent-explorer-1", Name: "Galileo", Role: types.RoleExplorer},
+		{ID: "agent-reviewer-1", Name: "Popper", Role: types.RoleReviewer, Moderator: true},
+	} {
+		if err := sched.AddAgent(ctx, p); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	recruits := sched.Recruits()
+	if len(recruits) != 1 || recruits[0].ID != "agent-explorer-2" || recruits[0].Role != types.RoleExplorer {
+		t.Fatalf("expected one explorer recruit, got %+v", recruits)
+	}
+	if recruits[0].Name == "Galileo" || recruits[0].Name == "Popper" {
+		t.Fatalf("recruit reused a taken name: %s", recruits[0].Name)
+	}
+	retired := sched.runners["agent-explorer-1"]
+	r, ok := retired.state.GetRetirement()
+	if !retired.retired || !ok || r.ReplacedBy != "agent-explorer-2" {
+		t.Fatalf("expected explorer retired and replaced, got %+v", r)
+	}
+	if sched.runners["agent-reviewer-1"].retired {
+		t.Fatal("moderator should never retire")
+	}
+	for _, id := range sched.eligibleAgentIDs() {
+		if id == "agent-explorer-1" {
+			t.Fatal("retired agent is still scheduled")
+		}
+	}
+
+	var lifecycle []string
+	retiredAt := 0
+	for _, ev := range logger.events {
+		switch {
+		case ev.Action == ActionRetire || ev.Action == ActionRecruit:
+			lifecycle = append(lifecycle, ev.Action+":"+ev.AgentID)
+			if ev.Action == ActionRetire {
+				retiredAt = ev.Tick
+			}
+		case retiredAt > 0 && ev.AgentID == "agent-explorer-1":
+			t.Fatalf("retired agent logged %s at tick %d", ev.Action, ev.Tick)
+		}
+	}
+	if strings.Join(lifecycle, ",") != "retire:agent-explorer-1,recruit:agent-explorer-2" {
+		t.Fatalf("unexpected lifecycle events: %v", lifecycle)
+	}
+}
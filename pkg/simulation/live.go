@@ -0,0 +1,169 @@
+package simulation
+
+import (
+	"sort"
+	"time"
+)
+
+// liveWindow is how many recent ticks /api/sim/live sums token usage over.
+const liveWindow = 24
+
+// Agent states reported by /api/sim/live. Sleeping and budget-paused agents
+// use the heartbeat liveness values.
+const (
+	LiveActed   = "acted"   // took a turn in the tick
+	LiveAwake   = "awake"   // eligible but not selected
+	LiveRetired = "retired" // left the community
+)
+
+// LiveStatus is what a mission-control view needs about the latest tick,
+// without reading the feed.
+type LiveStatus struct {
+	// State and InTick are the controller's; see ControlStatus.
+	State  string `json:"state,omitempty"`
+	InTick bool   `json:"in_tick"`
+
+	Tick int `json:"tick"`
+	// SimTime is the sim time the tick ran at; NextSimTime is the next's.
+	SimTime     time.Time   `json:"sim_time"`
+	NextSimTime time.Time   `json:"next_sim_time"`
+	Step        string      `json:"step"`
+	Turns       []LiveTurn  `json:"turns"`  // in selection order
+	Agents      []LiveAgent `json:"agents"` // acted first, then by name
+	Tokens      LiveTokens  `json:"tokens"`
+}
+
+// LiveTurn is one agent's turn in the tick.
+type LiveTurn struct {
+	AgentID   string   `json:"agent_id"`
+	AgentName string   `json:"agent_name"`
+	Action    string   `json:"action"`
+	ToolCalls []string `json:"tool_calls,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	Tokens    int      `json:"tokens"`
+}
+
+// LiveAgent is an agent's state after the tick.
+type LiveAgent struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	Status string `json:"status"` // acted | awake | sleeping | budget_paused | retired
+	// Turns is the agent's turn count since it last woke; once the bell has
+	// rung, GraceRemaining turns are left before it sleeps.
+	Turns          int  `json:"turns"`
+	TurnLimit      int  `json:"turn_limit"`
+	BellRung       bool `json:"bell_rung"`
+	GraceRemaining int  `json:"grace_remaining"`
+	// LastTick is the tick of the agent's last turn or heartbeat.
+	LastTick int `json:"last_tick"`
+}
+
+// LiveTokens is model usage for the tick and over the last Window ticks
+// (fewer at the start of the run).
+type LiveTokens struct {
+	Tick         int     `json:"tick"`
+	TickCalls    int     `json:"tick_calls"`
+	Window       int     `json:"window"` // ticks summed
+	WindowTokens int     `json:"window_tokens"`
+	WindowCalls  int     `json:"window_calls"`
+	PerTick      float64 `json:"per_tick"` // window average
+	Total        int     `json:"total"`    // since this process started
+}
+
+// Live reports the last tick's turns, every agent's state and recent token
+// usage. It waits for a running tick to finish.
+func (s *ADKScheduler) Live() LiveStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := LiveStatus{
+		Tick:        s.ticks,
+		SimTime:     s.simTime.Add(-s.simStep),
+		NextSimTime: s.simTime,
+		Step:        s.simStep.String(),
+		Turns:       append([]LiveTurn{}, s.liveTurns...),
+		Agents:      make([]LiveAgent, 0, len(s.runners)),
+		Tokens: LiveTokens{
+			Tick:      s.tickUsage.TotalTokens,
+			TickCalls: s.tickUsage.UsageEvents,
+			Window:    len(s.usageWindow),
+			Total:     s.usageTotal,
+		},
+	}
+	if s.ticks == 0 {
+		st.SimTime = s.simTime
+	}
+	for _, u := range s.usageWindow {
+		st.Tokens.WindowTokens += u.TotalTokens
+		st.Tokens.WindowCalls += u.UsageEvents
+	}
+	if st.Tokens.Window > 0 {
+		st.Tokens.PerTick = float64(st.Tokens.WindowTokens) / float64(st.Tokens.Window)
+	}
+
+	acted := make(map[string]bool, len(s.liveTurns))
+	for _, t := range s.liveTurns {
+		acted[t.AgentID] = true
+	}
+	for id, ar := range s.runners {
+		a := LiveAgent{
+			ID:             id,
+			Name:           ar.persona.Name,
+			Role:           string(ar.persona.Role),
+			Turns:          ar.turnCount,
+			TurnLimit:      s.turnLimit,
+			BellRung:       ar.bellRung,
+			GraceRemaining: ar.graceRemaining,
+			LastTick:       ar.lastTick,
+		}
+		switch {
+		case ar.retired:
+			a.Status = LiveRetired
+		case acted[id]:
+			a.Status = LiveActed
+		default:
+			a.Status = s.livenessOf(ar)
+			if a.Status == LivenessSkipped {
+				a.Status = LiveAwake
+			}
+		}
+		st.Agents = append(st.Agents, a)
+	}
+	sort.Slice(st.Agents, func(i, j int) bool {
+		ai, aj := acted[st.Agents[i].ID], acted[st.Agents[j].ID]
+		if ai != aj {
+			return ai
+		}
+		if st.Agents[i].Name != st.Agents[j].Name {
+			return st.Agents[i].Name < st.Agents[j].Name
+		}
+		return st.Agents[i].ID < st.Agents[j].ID
+	})
+	return st
+}
+
+// recordLiveTurns keeps the tick's turns for Live. Caller must hold s.mu.
+func (s *ADKScheduler) recordLiveTurns(turns []*agentTurn) {
+	s.liveTurns = make([]LiveTurn, 0, len(turns))
+	for _, t := range turns {
+		s.liveTurns = append(s.liveTurns, LiveTurn{
+			AgentID:   t.runner.persona.ID,
+			AgentName: t.runner.persona.Name,
+			Action:    t.prompt.action,
+			ToolCalls: t.toolCalls,
+			Error:     t.errText,
+			Tokens:    t.usage.TotalTokens,
+		})
+	}
+}
+
+// closeTickUsage adds the finished tick's usage to the rolling window.
+// Caller must hold s.mu.
+func (s *ADKScheduler) closeTickUsage() {
+	s.usageWindow = append(s.usageWindow, s.tickUsage)
+	if n := len(s.usageWindow); n > liveWindow {
+		s.usageWindow = append(s.usageWindow[:0], s.usageWindow[n-liveWindow:]...)
+	}
+	s.usageTotal += s.tickUsage.TotalTokens
+}
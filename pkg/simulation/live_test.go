@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestController_LiveReportsLastTick(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content:       &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15},
+	})
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		TurnLimit:       10,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+	ctx := context.Background()
+	for _, p := range []*types.Persona{{ID: "agent-1", Name: "Ada", Role: types.RoleExplorer}, {ID: "agent-2", Name: "Bo", Role: types.RoleExplorer}} {
+		if err := sched.AddAgent(ctx, p); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+	for range 2 {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	srv := httptest.NewServer(NewController(sched, "").Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/api/sim/live")
+	if err != nil {
+		t.Fatalf("GET /api/sim/live: %v", err)
+	}
+	defer resp.Body.Close()
+	var live LiveStatus
+	if err := json.NewDecoder(resp.Body).Decode(&live); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if live.Tick != 2 || live.State != ControlFinished || !live.NextSimTime.Equal(live.SimTime.Add(time.Hour)) {
+		t.Errorf("live = tick %d, state %q, %s -> %s", live.Tick, live.State, live.SimTime, live.NextSimTime)
+	}
+	if len(live.Turns) != 1 || live.Turns[0].Action == "" || live.Turns[0].Tokens == 0 {
+		t.Fatalf("turns = %+v, want the one agent that acted", live.Turns)
+	}
+	if len(live.Agents) != 2 || live.Agents[0].ID != live.Turns[0].AgentID || live.Agents[0].Status != LiveActed ||
+		live.Agents[1].Status != LiveAwake || live.Agents[0].TurnLimit != 10 {
+		t.Errorf("agents = %+v", live.Agents)
+	}
+	tok := live.Tokens
+	if tok.Window != 2 || tok.Tick == 0 || tok.WindowTokens != tok.Total || tok.PerTick != float64(tok.Total)/2 {
+		t.Errorf("tokens = %+v", tok)
+	}
+}
@@ -29,6 +29,32 @@ type EventLog struct {
 	GraceRemaining int       `json:"grace_remaining"`
 	Sleeping       bool      `json:"sleeping"`
 
+	// Failed marks a turn that ended with an error; ErrorKind classifies
+	// it (rate_limited, server_error, timeout, circuit_open or error) and
+	// Attempts counts the model calls tried when it was retried. A turn
+	// that completed but had tool calls fail has ErrorKind tool_error and
+	// Error listing the failures.
+	Failed    bool   `json:"failed,omitempty"`
+	ErrorKind string `json:"error_kind,omitempty"`
+	Attempts  int    `json:"attempts,omitempty"`
+	// ToolErrors counts the turn's tool calls that returned an error (see
+	// Outcomes).
+	ToolErrors int `json:"tool_errors,omitempty"`
+
+	// Plan is the new plan on plan events; PlanStep is the 1-based step a
+	// turn takes of the agent's current plan (see planner.go).
+	Plan     *Plan `json:"plan,omitempty"`
+	PlanStep int   `json:"plan_step,omitempty"`
+
+	// Outcomes pairs each tool call with its key arguments and the IDs it
+	// created or touched, in call order.
+	Outcomes []ToolOutcome `json:"outcomes,omitempty"`
+
+	// Heartbeat events only: why the agent produced no turn and how many
+	// ticks have passed since its last one.
+	Liveness  string `json:"liveness,omitempty"`
+	IdleTicks int    `json:"idle_ticks,omitempty"`
+
 	// Token usage (best-effort, depends on provider).
 	UsageEvents         int `json:"usage_events,omitempty"`
 	PromptTokens        int `json:"prompt_tokens,omitempty"`
@@ -39,6 +65,36 @@ type EventLog struct {
 	TotalTokens         int `json:"total_tokens,omitempty"`
 }
 
+// ToolOutcome is the structured result of one tool call in a turn.
+type ToolOutcome struct {
+	Tool string `json:"tool"`
+	// Args holds the call's short arguments (IDs, titles, votes); lists
+	// and objects are compact JSON.
+	Args map[string]string `json:"args,omitempty"`
+	// Text holds free-text arguments such as post bodies and review
+	// comments, kept verbatim so cmd/replay can rebuild the stores.
+	Text map[string]string `json:"text,omitempty"`
+	// Result holds the "*_id" fields of the tool's response, e.g. the
+	// post_id, comment_id or submission_id it created.
+	Result map[string]string `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// ErrorTool is the ErrorKind of turns whose tool calls failed while the
+// model call itself succeeded.
+const ErrorTool = "tool_error"
+
+// ActionHeartbeat marks liveness events for agents that did not take a turn.
+// They carry no prompt or response and are hidden from the default feed view.
+const ActionHeartbeat = "heartbeat"
+
+// Liveness states reported by heartbeat events.
+const (
+	LivenessSkipped      = "skipped"       // eligible but not selected
+	LivenessSleeping     = "sleeping"      // bell rung and grace turns used up
+	LivenessBudgetPaused = "budget_paused" // daily token budget exhausted
+)
+
 // EventLogger records simulation events for later analysis.
 type EventLogger interface {
 	LogEvent(EventLog) error
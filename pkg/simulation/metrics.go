@@ -0,0 +1,82 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/metrics"
+)
+
+// tickBuckets span a tick's wall time, which is dominated by LLM turns.
+var tickBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// schedulerMetrics instruments the scheduler. Built from a nil registry,
+// every metric is nil and records nothing.
+type schedulerMetrics struct {
+	reg *metrics.Registry
+
+	tickSeconds *metrics.HistogramVec
+	ticks       *metrics.CounterVec
+	turns       *metrics.CounterVec
+	llmCalls    *metrics.CounterVec
+	llmErrors   *metrics.CounterVec
+	tokens      *metrics.CounterVec
+
+	forumPosts    *metrics.GaugeVec
+	journalPapers *metrics.GaugeVec
+	simTime       *metrics.GaugeVec
+}
+
+func newSchedulerMetrics(reg *metrics.Registry) *schedulerMetrics {
+	return &schedulerMetrics{
+		reg: reg,
+
+		tickSeconds: reg.Histogram("scibot_sim_tick_duration_seconds", "Wall time of one simulation tick.", tickBuckets),
+		ticks:       reg.Counter("scibot_sim_ticks_total", "Simulation ticks run."),
+		turns:       reg.Counter("scibot_sim_turns_total", "Agent turns by action.", "action"),
+		llmCalls:    reg.Counter("scibot_llm_calls_total", "LLM responses received during agent turns.", "model"),
+		llmErrors:   reg.Counter("scibot_llm_errors_total", "Agent turns that ended with an LLM or runner error.", "model"),
+		tokens:      reg.Counter("scibot_llm_tokens_total", "Tokens used by agent turns.", "model", "kind"),
+
+		forumPosts:    reg.Gauge("scibot_forum_posts", "Forum posts by kind.", "kind"),
+		journalPapers: reg.Gauge("scibot_journal_papers", "Journal papers by status.", "status"),
+		simTime:       reg.Gauge("scibot_sim_time_seconds", "Current simulation time as a Unix timestamp."),
+	}
+}
+
+// observeTurn records one finished agent turn.
+func (m *schedulerMetrics) observeTurn(t *agentTurn) {
+	model := t.runner.modelName
+	m.turns.Inc(t.prompt.action)
+	m.llmCalls.Add(float64(t.usage.UsageEvents), model)
+	if t.errText != "" {
+		m.llmErrors.Inc(model)
+	}
+	m.tokens.Add(float64(t.usage.PromptTokens), model, "prompt")
+	m.tokens.Add(float64(t.usage.CandidatesTokens), model, "candidates")
+	m.tokens.Add(float64(t.usage.ThoughtsTokens), model, "thoughts")
+	m.tokens.Add(float64(t.usage.CachedContentTokens), model, "cached")
+	m.tokens.Add(float64(t.usage.TotalTokens), model, "total")
+}
+
+// observeTick records a tick that started at start, along with store sizes
+// and the clock. Caller must hold s.mu.
+func (s *ADKScheduler) observeTick(start time.Time) {
+	m := s.metrics
+	if m.reg == nil {
+		return
+	}
+	m.tickSeconds.Observe(time.Since(start).Seconds())
+	m.ticks.Inc()
+	m.simTime.Set(float64(s.simTime.Unix()))
+	if s.forum != nil {
+		threads, comments := s.forum.Counts()
+		m.forumPosts.Set(float64(threads), "thread")
+		m.forumPosts.Set(float64(comments), "comment")
+	}
+	if s.journal != nil {
+		approved, rejected, pending := s.journal.AcceptanceStats()
+		m.journalPapers.Set(float64(approved), "approved")
+		m.journalPapers.Set(float64(rejected), "rejected")
+		m.journalPapers.Set(float64(pending), "pending")
+	}
+}
@@ -0,0 +1,308 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Event kinds carried by the scheduler's event bus.
+const (
+	busForumPublish = "forum.publish" // new post or comment
+	busReviewAdded  = "workflow.review"
+	busDecision     = "workflow.decision" // submission accepted, rejected or sent back
+	busRetraction   = "journal.retract"
+	busConsensus    = "workflow.consensus" // consensus request achieved
+)
+
+// busEvent is a change in a shared store. Which fields are set depends on
+// the kind.
+type busEvent struct {
+	kind        string
+	publication *types.Publication
+	submission  *types.Submission
+	review      *types.PaperReview
+	consensus   *types.ConsensusRequest
+}
+
+// eventBus fans store changes out to subscribers. Publishers are tool calls
+// that may run concurrently, so subscribers must be safe for that.
+type eventBus struct {
+	mu   sync.RWMutex
+	subs map[string][]func(busEvent)
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[string][]func(busEvent))}
+}
+
+func (b *eventBus) subscribe(kind string, fn func(busEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[kind] = append(b.subs[kind], fn)
+}
+
+func (b *eventBus) publish(ev busEvent) {
+	b.mu.RLock()
+	subs := append([]func(busEvent){}, b.subs[ev.kind]...)
+	b.mu.RUnlock()
+	for _, fn := range subs {
+		fn(ev)
+	}
+}
+
+// Notification kinds.
+const (
+	notifyMention = "mention"
+	notifyReply   = "reply"
+	notifyReview  = "review"
+	notifyAgreed  = "consensus"
+	notifyDecided = "decision"
+)
+
+// maxPendingNotifications caps each agent's queue; the oldest are dropped.
+const maxPendingNotifications = 20
+
+type notification struct {
+	kind   string
+	from   string // author name; empty for anonymous reviews
+	target string // post, comment, submission or consensus ID
+	title  string
+	// Review verdict, or the new submission status for decisions, and
+	// comments; rendered in the recipient's language.
+	verdict string
+	detail  string
+}
+
+// notifier turns bus events into per-agent notifications that are folded
+// into the agent's next action prompt.
+type notifier struct {
+	mu      sync.Mutex
+	keys    map[string][]string // agent ID -> lowercase mention keys
+	pending map[string][]notification
+	forum   func(id string) *types.Publication
+}
+
+func newNotifier() *notifier {
+	return &notifier{
+		keys:    make(map[string][]string),
+		pending: make(map[string][]notification),
+	}
+}
+
+// register makes an agent addressable by @ID, @Name and @Name-without-spaces.
+func (n *notifier) register(p *types.Persona) {
+	keys := []string{strings.ToLower(p.ID)}
+	if name := strings.TrimSpace(p.Name); name != "" {
+		keys = append(keys, strings.ToLower(name), strings.ToLower(strings.ReplaceAll(name, " ", "")))
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.keys[p.ID] = keys
+}
+
+// unregister stops notifications for a retired agent.
+func (n *notifier) unregister(agentID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.keys, agentID)
+	delete(n.pending, agentID)
+}
+
+func (n *notifier) onPublish(ev busEvent) {
+	pub := ev.publication
+	if pub == nil {
+		return
+	}
+	title := pub.Title
+	var parent *types.Publication
+	if pub.IsComment && pub.ParentID != "" && n.forum != nil {
+		parent = n.forum(pub.ParentID)
+		if parent != nil && title == "" {
+			title = parent.Title
+		}
+	}
+	text := strings.ToLower(pub.Title + "\n" + pub.Abstract + "\n" + pub.Content)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for id, keys := range n.keys {
+		if id == pub.AuthorID {
+			continue
+		}
+		switch {
+		case mentions(pub.Mentions, text, keys):
+			n.pushLocked(id, notification{kind: notifyMention, from: pub.AuthorName, target: pub.ID, title: title})
+		case parent != nil && parent.AuthorID == id:
+			n.pushLocked(id, notification{kind: notifyReply, from: pub.AuthorName, target: pub.ID, title: title})
+		}
+	}
+}
+
+func (n *notifier) onReview(ev busEvent) {
+	sub, review := ev.submission, ev.review
+	if sub == nil || review == nil || sub.AuthorID == review.ReviewerID {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.keys[sub.AuthorID]; !ok {
+		return
+	}
+	n.pushLocked(sub.AuthorID, notification{
+		kind:    notifyReview,
+		target:  sub.ID,
+		title:   sub.Title,
+		verdict: string(review.Verdict),
+		detail:  truncate(strings.TrimSpace(review.Comments), 120),
+	})
+}
+
+// onDecision tells the author how its submission was decided, with the
+// editor's rationale.
+func (n *notifier) onDecision(ev busEvent) {
+	sub := ev.submission
+	if sub == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.keys[sub.AuthorID]; !ok {
+		return
+	}
+	n.pushLocked(sub.AuthorID, notification{
+		kind:    notifyDecided,
+		target:  sub.ID,
+		title:   sub.Title,
+		verdict: string(sub.Status),
+		detail:  truncate(strings.TrimSpace(sub.DecisionRationale), 300),
+	})
+}
+
+// onConsensus tells the requester its consensus was achieved, so it can
+// start the collaborative draft.
+func (n *notifier) onConsensus(ev busEvent) {
+	req := ev.consensus
+	if req == nil {
+		return
+	}
+	title := req.PostID
+	if n.forum != nil {
+		if post := n.forum(req.PostID); post != nil && post.Title != "" {
+			title = post.Title
+		}
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.keys[req.RequesterID]; !ok {
+		return
+	}
+	n.pushLocked(req.RequesterID, notification{kind: notifyAgreed, target: req.ID, title: title})
+}
+
+func (n *notifier) pushLocked(agentID string, note notification) {
+	queue := append(n.pending[agentID], note)
+	if len(queue) > maxPendingNotifications {
+		queue = queue[len(queue)-maxPendingNotifications:]
+	}
+	n.pending[agentID] = queue
+}
+
+// count returns how many notifications an agent has pending.
+func (n *notifier) count(agentID string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.pending[agentID])
+}
+
+// take returns and clears an agent's pending notifications.
+func (n *notifier) take(agentID string) []notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	out := n.pending[agentID]
+	delete(n.pending, agentID)
+	return out
+}
+
+func mentions(explicit []string, text string, keys []string) bool {
+	for _, key := range keys {
+		for _, m := range explicit {
+			if strings.ToLower(m) == key {
+				return true
+			}
+		}
+		if strings.Contains(text, "@"+key) {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationNotice summarizes pending notifications as a preface to an
+// action prompt, e.g. "你有 2 条新回复、1 次 @ 提及".
+func notificationNotice(notes []notification, lang prompts.Lang) string {
+	text := prompts.For(lang)
+	counts := map[string]int{}
+	for _, note := range notes {
+		counts[note.kind]++
+	}
+	var parts []string
+	if c := counts[notifyReply]; c > 0 {
+		parts = append(parts, fmt.Sprintf(text.NoticeReplies, c))
+	}
+	if c := counts[notifyMention]; c > 0 {
+		parts = append(parts, fmt.Sprintf(text.NoticeMentions, c))
+	}
+	if c := counts[notifyReview]; c > 0 {
+		parts = append(parts, fmt.Sprintf(text.NoticeReviews, c))
+	}
+	if c := counts[notifyDecided]; c > 0 {
+		parts = append(parts, fmt.Sprintf(text.NoticeDecisions, c))
+	}
+	if c := counts[notifyAgreed]; c > 0 {
+		parts = append(parts, fmt.Sprintf(text.NoticeAgreed, c))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, text.NoticeHeader, strings.Join(parts, text.NoticeSep))
+	for i, note := range notes {
+		if i == 5 {
+			fmt.Fprintf(&b, text.NoticeMore, len(notes)-i)
+			break
+		}
+		switch note.kind {
+		case notifyReply:
+			fmt.Fprintf(&b, text.NoticeReply, note.from, note.target, note.title)
+		case notifyMention:
+			fmt.Fprintf(&b, text.NoticeMention, note.from, note.target, note.title)
+		case notifyReview:
+			fmt.Fprintf(&b, text.NoticeReview, note.target, note.title, fmt.Sprintf(text.ReviewDetail, note.verdict, note.detail))
+		case notifyDecided:
+			outcome := text.Decisions[types.SubmissionStatus(note.verdict)]
+			if outcome == "" {
+				outcome = note.verdict
+			}
+			fmt.Fprintf(&b, text.NoticeDecision, note.target, note.title, outcome, note.detail)
+		case notifyAgreed:
+			fmt.Fprintf(&b, text.NoticeConsensus, note.target, note.title)
+		}
+	}
+	if counts[notifyReply]+counts[notifyMention] > 0 {
+		b.WriteString(text.NoticeFooter)
+	}
+	return b.String()
+}
+
+// withNotifications prefixes the agent's pending notifications, if any, to
+// its action prompt.
+func (s *ADKScheduler) withNotifications(ar *agentRunner, prompt actionPrompt) actionPrompt {
+	notes := s.notifier.take(ar.persona.ID)
+	if len(notes) == 0 {
+		return prompt
+	}
+	prompt.text = notificationNotice(notes, ar.lang) + "\n" + prompt.text
+	return prompt
+}
@@ -0,0 +1,115 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_NotifiesRepliesAndMentions(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+	})
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Ada Lovelace", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	posts := []*types.Publication{
+		{ID: "post-1", Title: "Engines", AuthorID: "agent-1", AuthorName: "Ada Lovelace", Content: "!"},
+		{ID: "post-1-c1", ParentID: "post-1", IsComment: true, AuthorID: "x", AuthorName: "X", Content: "Why?"},
+		{ID: "post-1-c2", ParentID: "post-1", IsComment: true, AuthorID: "y", AuthorName: "Y", Content: "Agreed."},
+		{ID: "post-1-c3", ParentID: "post-1", IsComment: true, AuthorID: "agent-1", AuthorName: "Ada Lovelace", Content: "Self."},
+		{ID: "post-2", Title: "Looms", AuthorID: "y", AuthorName: "Y", Content: "@AdaLovelace what do you think?"},
+	}
+	for _, p := range posts {
+		var err error
+		if p.IsComment {
+			err = forum.Comment(p.ParentID, p)
+		} else {
+			err = forum.Post(p)
+		}
+		if err != nil {
+			t.Fatalf("publish %s: %v", p.ID, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+	if len(logger.events) != 2 {
+		t.Fatalf("expected 2 turns, got %d", len(logger.events))
+	}
+	first := logger.events[0].Prompt
+	if !strings.Contains(first, "你有2 条新回复、1 次 @ 提及") || !strings.Contains(first, "post-2") {
+		t.Fatalf("expected reply and mention notice, got %q", first)
+	}
+	if strings.Contains(logger.events[1].Prompt, "通知：") {
+		t.Fatalf("expected notifications to be delivered once, got %q", logger.events[1].Prompt)
+	}
+}
+
+func TestADKScheduler_NotifiesAuthorOfDecision(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+	})
+	journal := publication.NewJournal("J", filepath.Join(tempDir, "journal"))
+	sched.SetJournal(journal)
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Ada", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := journal.Submit(&types.Publication{ID: "paper-1", Title: "Engines", AuthorID: "agent-1", Content: "..."}); err != nil {
+		t.Fatal(err)
+	}
+	sched.workflow.AddSubmission(&types.Submission{ID: "paper-1", Title: "Engines", AuthorID: "agent-1", Status: types.SubmissionPending})
+	sched.workflow.AddReview(&types.PaperReview{SubmissionID: "paper-1", ReviewerID: "rev", ReviewerName: "Rev", Verdict: types.VerdictReject, Comments: "No evidence."})
+	if _, err := sched.workflow.Decide(journal, "paper-1", types.VerdictReject, "rev", sched.simTime, false); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	prompt := logger.events[len(logger.events)-1].Prompt
+	if !strings.Contains(prompt, "1 项投稿结果") || !strings.Contains(prompt, "paper-1《Engines》被拒稿") || !strings.Contains(prompt, "No evidence.") {
+		t.Fatalf("expected a decision notice, got %q", prompt)
+	}
+}
@@ -0,0 +1,145 @@
+package simulation
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// freeTextArgs are tool arguments kept in ToolOutcome.Text rather than
+// Args, which the feed shows inline.
+var freeTextArgs = map[string]bool{
+	"content":     true,
+	"abstract":    true,
+	"comments":    true,
+	"summary":     true,
+	"description": true,
+	"reason":      true,
+	"insight":     true,
+
+	"counter_arguments": true,
+}
+
+// recordCall starts an outcome for a function call.
+func (t *agentTurn) recordCall(call *genai.FunctionCall) {
+	out := ToolOutcome{Tool: call.Name}
+	for k, v := range call.Args {
+		s, ok := outcomeValue(v)
+		if !ok {
+			continue
+		}
+		if freeTextArgs[k] {
+			if out.Text == nil {
+				out.Text = make(map[string]string)
+			}
+			out.Text[k] = s
+			continue
+		}
+		if out.Args == nil {
+			out.Args = make(map[string]string)
+		}
+		out.Args[k] = s
+	}
+	t.outcomes = append(t.outcomes, out)
+	t.callIDs = append(t.callIDs, call.ID)
+}
+
+// recordResponse completes the outcome of the call resp answers, matched by
+// call ID, or else by the earliest unanswered call to the same tool.
+func (t *agentTurn) recordResponse(resp *genai.FunctionResponse) {
+	idx := -1
+	for i := range t.outcomes {
+		if t.answered(i) {
+			continue
+		}
+		if resp.ID != "" && t.callIDs[i] == resp.ID {
+			idx = i
+			break
+		}
+		if idx < 0 && t.outcomes[i].Tool == resp.Name {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	out := &t.outcomes[idx]
+	out.Result = map[string]string{}
+	for k, v := range resp.Response {
+		if k == "error" {
+			out.Error, _ = outcomeValue(v)
+			continue
+		}
+		if !strings.HasSuffix(k, "_id") {
+			continue
+		}
+		if s, ok := outcomeValue(v); ok && s != "" {
+			out.Result[k] = s
+		}
+	}
+}
+
+// answered reports whether outcome i already has its response. Result is
+// set (possibly empty) once one arrives.
+func (t *agentTurn) answered(i int) bool {
+	return t.outcomes[i].Result != nil || t.outcomes[i].Error != ""
+}
+
+// finishOutcomes drops the empty result maps used to mark answered calls.
+func (t *agentTurn) finishOutcomes() {
+	for i := range t.outcomes {
+		if len(t.outcomes[i].Result) == 0 {
+			t.outcomes[i].Result = nil
+		}
+	}
+}
+
+// outcomeValue renders a JSON value as a string; lists and objects, such as
+// review scores, stay compact JSON.
+func outcomeValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return strings.TrimSpace(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// toolErrors counts the outcomes that failed and lists them, one
+// "tool: error" line each.
+func toolErrors(outcomes []ToolOutcome) (int, string) {
+	var lines []string
+	for _, o := range outcomes {
+		if o.Error != "" {
+			lines = append(lines, o.Tool+": "+strings.TrimSpace(o.Error))
+		}
+	}
+	return len(lines), strings.Join(lines, "\n")
+}
+
+// failure describes what went wrong in the turn: the model error that
+// failed it, or else the tool calls that returned an error. Both are empty
+// for a clean turn.
+func (t *agentTurn) failure() (text, kind string) {
+	if t.err != nil {
+		kind, _ = classifyModelError(t.err)
+		return t.errText, kind
+	}
+	if n, summary := toolErrors(t.outcomes); n > 0 {
+		return summary, ErrorTool
+	}
+	return t.errText, ""
+}
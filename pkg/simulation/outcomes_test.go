@@ -0,0 +1,141 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// postingLLM creates one post per turn, then replies with text once the tool
+// has answered.
+type postingLLM struct{}
+
+func (postingLLM) Name() string { return "posting-llm" }
+
+func (postingLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	content := genai.NewContentFromFunctionCall("create_post", map[string]any{
+		"title":     "Galaxy rotation curves",
+		"content":   "A body kept apart from the short args.",
+		"subreddit": "general",
+	}, genai.RoleModel)
+	if last := req.Contents[len(req.Contents)-1]; len(last.Parts) > 0 && last.Parts[0].FunctionResponse != nil {
+		content = genai.NewContentFromText("posted", genai.RoleModel)
+	}
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: content}, nil)
+	}
+}
+
+func TestADKScheduler_LogsToolOutcomes(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           postingLLM{},
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+	})
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	if len(logger.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(logger.events))
+	}
+	outcomes := logger.events[0].Outcomes
+	if len(outcomes) != 1 {
+		t.Fatalf("expected 1 outcome, got %+v", outcomes)
+	}
+	out := outcomes[0]
+	if out.Tool != "create_post" || out.Args["title"] != "Galaxy rotation curves" || out.Args["subreddit"] != "general" {
+		t.Fatalf("unexpected outcome call: %+v", out)
+	}
+	if _, ok := out.Args["content"]; ok || out.Text["content"] != "A body kept apart from the short args." {
+		t.Fatalf("expected free text apart from args, got %+v", out)
+	}
+	post := forum.Get(out.Result["post_id"])
+	if post == nil || post.Title != "Galaxy rotation curves" || out.Error != "" {
+		t.Fatalf("expected result to name the created post, got %+v", out)
+	}
+}
+
+// missingPostLLM reads a post that doesn't exist, then replies.
+type missingPostLLM struct{}
+
+func (missingPostLLM) Name() string { return "missing-post-llm" }
+
+func (missingPostLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	content := genai.NewContentFromFunctionCall("read_post", map[string]any{"post_id": "forum-missing"}, genai.RoleModel)
+	if last := req.Contents[len(req.Contents)-1]; len(last.Parts) > 0 && last.Parts[0].FunctionResponse != nil {
+		content = genai.NewContentFromText("gone", genai.RoleModel)
+	}
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: content}, nil)
+	}
+}
+
+func TestADKScheduler_RecordsToolErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := &memoryLogger{}
+	start := time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           missingPostLLM{},
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       start,
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	if len(logger.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(logger.events))
+	}
+	ev := logger.events[0]
+	if ev.Failed || ev.ToolErrors != 1 || ev.ErrorKind != ErrorTool || !strings.HasPrefix(ev.Error, "read_post: ") {
+		t.Fatalf("event = failed %v, %d tool errors, kind %q, error %q", ev.Failed, ev.ToolErrors, ev.ErrorKind, ev.Error)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "agents", "agent-1", "daily", start.Format("2006-01-02")+".jsonl"))
+	if err != nil {
+		t.Fatalf("read daily log: %v", err)
+	}
+	var entry dailyLogEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("decode daily entry: %v", err)
+	}
+	if entry.Error != ev.Error || entry.ErrorKind != ErrorTool {
+		t.Errorf("daily entry error = %q (%s), want the event's", entry.Error, entry.ErrorKind)
+	}
+}
@@ -45,6 +45,7 @@ var defaultPersonas = []*types.Persona{
 		Domains:       []string{"philosophy", "methodology"},
 		Sociability:   0.5,
 		Influence:     0.6,
+		Moderator:     true,
 	},
 	{
 		ID:            "agent-synthesizer-1",
@@ -72,6 +73,21 @@ var defaultPersonas = []*types.Persona{
 	},
 }
 
+// editorPersona is the built-in journal editor, added on request rather than
+// by GeneratePersonas so existing rosters keep their IDs.
+var editorPersona = types.Persona{
+	ID:            "agent-editor-1",
+	Name:          "Oldenburg",
+	Role:          types.RoleEditor,
+	ThinkingStyle: types.StyleAnalytical,
+	RiskTolerance: 0.3,
+	Creativity:    0.4,
+	Rigor:         0.85,
+	Domains:       []string{"methodology", "philosophy"},
+	Sociability:   0.6,
+	Influence:     0.8,
+}
+
 var namePool = []string{
 	"Curie", "Newton", "Turing", "Noether", "Maxwell",
 	"Faraday", "Raman", "Dirac", "Hubble", "Planck",
@@ -113,6 +129,13 @@ func DefaultPersonas() []*types.Persona {
 	return out
 }
 
+// EditorPersona returns the built-in journal editor persona.
+func EditorPersona() *types.Persona {
+	clone := editorPersona
+	clone.Domains = append([]string(nil), editorPersona.Domains...)
+	return &clone
+}
+
 // GeneratePersonas creates up to count personas, using defaults first then random.
 func GeneratePersonas(count int, seed int64) []*types.Persona {
 	if count <= 0 {
@@ -145,31 +168,67 @@ func GeneratePersonas(count int, seed int64) []*types.Persona {
 			name = fmt.Sprintf("Agent-%d", len(personas)+1)
 		}
 
-		minRisk, maxRisk := roleRiskRange(role)
-		minCreativity, maxCreativity := roleCreativityRange(role)
-		minRigor, maxRigor := roleRigorRange(role)
-		minSociability, maxSociability := roleSociabilityRange(role)
-		minInfluence, maxInfluence := roleInfluenceRange(role)
-
-		persona := &types.Persona{
-			ID:            fmt.Sprintf("agent-%s-%d", string(role), roleCounts[role]),
-			Name:          name,
-			Role:          role,
-			ThinkingStyle: pickStyle(rng, role),
-			RiskTolerance: sampleRange(rng, minRisk, maxRisk),
-			Creativity:    sampleRange(rng, minCreativity, maxCreativity),
-			Rigor:         sampleRange(rng, minRigor, maxRigor),
-			Domains:       pickDomains(rng, role),
-			Sociability:   sampleRange(rng, minSociability, maxSociability),
-			Influence:     sampleRange(rng, minInfluence, maxInfluence),
-		}
-
-		personas = append(personas, persona)
+		id := fmt.Sprintf("agent-%s-%d", string(role), roleCounts[role])
+		personas = append(personas, randomPersona(rng, id, name, role))
 	}
 
 	return personas
 }
 
+// RecruitPersona generates a newcomer with the retiree's role, an unused
+// name from the pool and the next free ID for that role.
+func RecruitPersona(rng *rand.Rand, retiree *types.Persona, roster []*types.Persona) *types.Persona {
+	role := retiree.Role
+	ids := make(map[string]bool, len(roster))
+	names := make(map[string]bool, len(roster))
+	for _, p := range roster {
+		ids[p.ID] = true
+		names[p.Name] = true
+	}
+
+	id := ""
+	for n := 1; ; n++ {
+		id = fmt.Sprintf("agent-%s-%d", string(role), n)
+		if !ids[id] {
+			break
+		}
+	}
+	name := ""
+	for _, candidate := range namePool {
+		if !names[candidate] {
+			name = candidate
+			break
+		}
+	}
+	for n := len(roster) + 1; name == ""; n++ {
+		if candidate := fmt.Sprintf("Agent-%d", n); !names[candidate] {
+			name = candidate
+		}
+	}
+	return randomPersona(rng, id, name, role)
+}
+
+func randomPersona(rng *rand.Rand, id, name string, role types.AgentRole) *types.Persona {
+	minRisk, maxRisk := roleRiskRange(role)
+	minCreativity, maxCreativity := roleCreativityRange(role)
+	minRigor, maxRigor := roleRigorRange(role)
+	minSociability, maxSociability := roleSociabilityRange(role)
+	minInfluence, maxInfluence := roleInfluenceRange(role)
+
+	return &types.Persona{
+		ID:            id,
+		Name:          name,
+		Role:          role,
+		ThinkingStyle: pickStyle(rng, role),
+		RiskTolerance: sampleRange(rng, minRisk, maxRisk),
+		Creativity:    sampleRange(rng, minCreativity, maxCreativity),
+		Rigor:         sampleRange(rng, minRigor, maxRigor),
+		Domains:       pickDomains(rng, role),
+		Sociability:   sampleRange(rng, minSociability, maxSociability),
+		Influence:     sampleRange(rng, minInfluence, maxInfluence),
+	}
+}
+
 func pickStyle(rng *rand.Rand, role types.AgentRole) types.ThinkingStyle {
 	styles := styleByRole[role]
 	if len(styles) == 0 {
@@ -0,0 +1,318 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Planner modes (ADKSchedulerConfig.Planner). Without a planner every free
+// turn's action is sampled independently from the agent's action weights.
+const (
+	// PlannerHeuristic builds plans from the agent's action weights and
+	// pending notifications, without LLM calls.
+	PlannerHeuristic = "heuristic"
+	// PlannerLLM asks a model for each plan, falling back to the heuristic
+	// when the call fails or the reply is unusable.
+	PlannerLLM = "llm"
+)
+
+// ActionPlan marks log events for new agent plans.
+const ActionPlan = "plan"
+
+const (
+	// planMaxSteps caps the steps of a plan.
+	planMaxSteps = 5
+	// planMaxAge is how long a plan is followed before the agent replans,
+	// even with steps left.
+	planMaxAge = 48 * time.Hour
+)
+
+// ValidPlanner reports whether mode is a planner mode; empty is valid and
+// disables planning.
+func ValidPlanner(mode string) bool {
+	switch mode {
+	case "", PlannerHeuristic, PlannerLLM:
+		return true
+	}
+	return false
+}
+
+// Plan is a short research arc an agent works through on its free turns,
+// one step per turn, so that reading, posting and follow-up build on each
+// other instead of being sampled independently. Duties, seminars and the
+// bell still take precedence over the next step.
+type Plan struct {
+	Goal   string     `json:"goal"`
+	Steps  []PlanStep `json:"steps"`
+	Source string     `json:"source"` // heuristic or llm
+	MadeAt time.Time  `json:"made_at"`
+	// Next is the index of the next step to take.
+	Next int `json:"next"`
+}
+
+// PlanStep is one turn of a plan: an action from the agent's catalog and,
+// for model-made plans, what exactly to do.
+type PlanStep struct {
+	Action string `json:"action"`
+	Note   string `json:"note,omitempty"`
+}
+
+// String renders the plan for the event log.
+func (p *Plan) String() string {
+	var b strings.Builder
+	b.WriteString(p.Goal)
+	for i, step := range p.Steps {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, step.Action)
+		if step.Note != "" {
+			b.WriteString(": " + step.Note)
+		}
+	}
+	return b.String()
+}
+
+func (s *ADKScheduler) needsPlan(ar *agentRunner) bool {
+	p := ar.plan
+	return p == nil || p.Next >= len(p.Steps) || s.simTime.Sub(p.MadeAt) >= planMaxAge
+}
+
+// makePlans gives each agent about to take a turn a new plan when it has
+// none left. Caller must hold s.mu.
+func (s *ADKScheduler) makePlans(ctx context.Context, ids []string) {
+	if s.planner == "" {
+		return
+	}
+	for _, id := range ids {
+		ar := s.runners[id]
+		if ar == nil || ar.bellRung || ar.turnCount >= s.turnLimit || s.shiftEnding() || !s.needsPlan(ar) {
+			continue
+		}
+		s.makePlan(ctx, ar)
+	}
+}
+
+func (s *ADKScheduler) makePlan(ctx context.Context, ar *agentRunner) {
+	id := ar.persona.ID
+	actions := planActions(ar.actionWeights)
+	if len(actions) == 0 {
+		ar.plan = nil
+		return
+	}
+	pending := s.notifier.count(id)
+	agenda, _ := ar.state.GetAgenda()
+
+	var (
+		plan  *Plan
+		usage tokenTotals
+		err   error
+	)
+	if s.planner == PlannerLLM && !s.budget.AgentExhausted(id, s.simTime) {
+		llm, provider := s.summarizer, s.summarizerProvider
+		if llm == nil {
+			llm, provider = s.resolveModel(ar.persona), ar.provider
+		}
+		if llm == nil {
+			err = fmt.Errorf("no LLM model configured for agent %s", id)
+		} else {
+			var text string
+			text, usage, err = s.generateText(ctx, llm, provider, s.buildPlanPrompt(ar, agenda, pending, actions))
+			s.recordUsage(id, usage)
+			if err == nil {
+				plan, err = parsePlan(text, actions)
+			}
+		}
+		if err != nil {
+			log.Printf("[Tick %d] %s planning failed, using a heuristic plan: %v", s.ticks, ar.persona.Name, err)
+		}
+	}
+	if plan == nil {
+		plan = heuristicPlan(ar.persona, agenda, ar.actionWeights, pending, ar.lang)
+	}
+	plan.MadeAt = s.simTime
+	ar.plan = plan
+	s.actionStats[ActionPlan]++
+	log.Printf("[Tick %d] %s plans %d steps (%s): %s", s.ticks, ar.persona.Name, len(plan.Steps), plan.Source, plan.Goal)
+	prompt := actionPrompt{action: ActionPlan, text: fmt.Sprintf(prompts.For(ar.lang).PlanAction, plan.Goal), plan: plan}
+	s.logEvent(ar, prompt, plan.String(), err, nil, nil, nil, usage)
+}
+
+// planStepPrompt returns the prompt for the next step of the agent's plan.
+func (s *ADKScheduler) planStepPrompt(ar *agentRunner) (actionPrompt, bool) {
+	p := ar.plan
+	if s.planner == "" || p == nil || p.Next >= len(p.Steps) {
+		return actionPrompt{}, false
+	}
+	step := p.Steps[p.Next]
+	p.Next++
+	text := step.Note
+	if text == "" {
+		text = s.pickActionText(ar, step.Action)
+	}
+	notice := fmt.Sprintf(prompts.For(ar.lang).PlanStep, p.Goal, p.Next, len(p.Steps))
+	return actionPrompt{action: step.Action, text: notice + "\n" + text, planStep: p.Next}, true
+}
+
+// planActions lists the actions with a positive weight, strongest first.
+func planActions(weights map[string]float64) []string {
+	out := make([]string, 0, len(weights))
+	for name, w := range weights {
+		if w > 0 {
+			out = append(out, name)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if weights[out[i]] != weights[out[j]] {
+			return weights[out[i]] > weights[out[j]]
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// heuristicPlan builds a survey, contribute, follow-up arc toward the
+// agent's first active goal (else its domains) from its action weights:
+// read first when notifications are waiting (else the stronger of browse
+// and read), then the strongest remaining action, then discuss the result
+// with peers.
+func heuristicPlan(p *types.Persona, agenda types.Agenda, weights map[string]float64, pending int, lang prompts.Lang) *Plan {
+	has := func(action string) bool { return weights[action] > 0 }
+	var steps []PlanStep
+	add := func(action string) {
+		if action != "" && has(action) {
+			steps = append(steps, PlanStep{Action: action})
+		}
+	}
+
+	survey := "browse"
+	if pending > 0 || weights["read"] >= weights["browse"] {
+		survey = "read"
+	}
+	if !has(survey) {
+		survey = map[string]string{"browse": "read", "read": "browse"}[survey]
+	}
+	add(survey)
+	main := ""
+	for _, action := range planActions(weights) {
+		if action != "browse" && action != "read" && action != "observe" {
+			main = action
+			break
+		}
+	}
+	add(main)
+	if main == "interact" {
+		add("post")
+	} else {
+		add("interact")
+	}
+	if len(steps) == 0 {
+		add(planActions(weights)[0])
+	}
+
+	for _, g := range agenda.Goals {
+		if g.Status == types.AgendaActive {
+			return &Plan{Goal: g.Text, Steps: steps, Source: PlannerHeuristic}
+		}
+	}
+	topic := string(p.Role)
+	if len(p.Domains) > 0 {
+		topic = strings.Join(p.Domains, ", ")
+	}
+	return &Plan{Goal: fmt.Sprintf(prompts.For(lang).PlanGoal, topic), Steps: steps, Source: PlannerHeuristic}
+}
+
+func (s *ADKScheduler) buildPlanPrompt(ar *agentRunner, agenda types.Agenda, pending int, actions []string) string {
+	text := prompts.For(ar.lang)
+	var b strings.Builder
+	topic := string(ar.persona.Role)
+	if len(ar.persona.Domains) > 0 {
+		topic = strings.Join(ar.persona.Domains, ", ")
+	}
+	fmt.Fprintf(&b, text.PlanIntro, ar.persona.Name, topic)
+	if summary := memorySummary(ar.memory, ar.lang); strings.TrimSpace(summary) != "" {
+		fmt.Fprintf(&b, text.PlanSummary, summary)
+	}
+	var items []string
+	for _, g := range agenda.Goals {
+		if g.Status.Open() {
+			items = append(items, fmt.Sprintf("- %s (%s, %s)", g.Text, g.ID, g.Status))
+		}
+	}
+	for _, p := range agenda.Projects {
+		if p.Status.Open() {
+			item := fmt.Sprintf("- %s (%s, %s)", p.Title, p.ID, p.Status)
+			if p.Progress != "" {
+				item += ": " + headRunes(p.Progress, 200)
+			}
+			items = append(items, item)
+		}
+	}
+	if len(items) > 0 {
+		fmt.Fprintf(&b, text.PlanAgenda, strings.Join(items, "\n"))
+	}
+	if pending > 0 {
+		fmt.Fprintf(&b, text.PlanPending, pending)
+	}
+	var list strings.Builder
+	for _, action := range actions {
+		list.WriteString("- " + action)
+		if hint := s.actionHint(ar, action); hint != "" {
+			list.WriteString(": " + hint)
+		}
+		list.WriteString("\n")
+	}
+	fmt.Fprintf(&b, text.PlanActions, strings.TrimRight(list.String(), "\n"))
+	fmt.Fprintf(&b, text.PlanOutput, planMaxSteps)
+	return b.String()
+}
+
+// actionHint is the first prompt option of an action, to tell the planner
+// what it means.
+func (s *ADKScheduler) actionHint(ar *agentRunner, action string) string {
+	if texts := s.scenario.actionPrompts(ar.persona.Role, action, ar.lang); len(texts) > 0 {
+		return texts[0]
+	}
+	if texts := s.templates.ActionTexts(ar.lang, action, ar.persona); len(texts) > 0 {
+		return texts[0]
+	}
+	return ""
+}
+
+// parsePlan extracts a plan from a model reply, keeping the steps whose
+// action is in actions.
+func parsePlan(text string, actions []string) (*Plan, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("plan: no JSON object in reply")
+	}
+	var raw Plan
+	if err := json.Unmarshal([]byte(text[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("plan: %w", err)
+	}
+	known := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		known[a] = true
+	}
+	plan := &Plan{Goal: headRunes(raw.Goal, 200), Source: PlannerLLM}
+	for _, step := range raw.Steps {
+		action := strings.ToLower(strings.TrimSpace(step.Action))
+		if !known[action] {
+			continue
+		}
+		plan.Steps = append(plan.Steps, PlanStep{Action: action, Note: headRunes(step.Note, 300)})
+		if len(plan.Steps) == planMaxSteps {
+			break
+		}
+	}
+	if plan.Goal == "" || len(plan.Steps) == 0 {
+		return nil, fmt.Errorf("plan: no goal or no usable steps")
+	}
+	return plan, nil
+}
@@ -0,0 +1,113 @@
+package simulation
+
+import (
+	"context"
+	"iter"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// planLLM answers every request with the same plan.
+type planLLM struct{ calls int }
+
+func (m *planLLM) Name() string { return "plan-llm" }
+
+func (m *planLLM) GenerateContent(_ context.Context, _ *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	m.calls++
+	text := "```json\n" + `{"goal": "Test the tired-light idea", "steps": [
+		{"action": "read", "note": "Read the newest cosmology thread."},
+		{"action": "levitate", "note": "Not an action."},
+		{"action": "POST", "note": "Post a falsifiable prediction."}
+	]}` + "\n```"
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleModel)}, nil)
+	}
+}
+
+func TestADKScheduler_FollowsLLMPlanAcrossTicks(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := &memoryLogger{}
+	planner := &planLLM{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           dreamLLM{},
+		Summarizer:      planner,
+		Planner:         PlannerLLM,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	var got []string
+	for _, ev := range logger.events {
+		got = append(got, ev.Action)
+	}
+	if strings.Join(got, ",") != "plan,read,post,plan,read" {
+		t.Fatalf("actions = %v", got)
+	}
+	plan := logger.events[0].Plan
+	if plan == nil || plan.Source != PlannerLLM || plan.Goal != "Test the tired-light idea" || len(plan.Steps) != 2 {
+		t.Fatalf("plan event = %+v", plan)
+	}
+	read, post := logger.events[1], logger.events[2]
+	if read.PlanStep != 1 || !strings.Contains(read.Prompt, "Read the newest cosmology thread.") || !strings.Contains(read.Prompt, "Test the tired-light idea") {
+		t.Errorf("first step = %d, %q", read.PlanStep, read.Prompt)
+	}
+	if post.PlanStep != 2 || !strings.Contains(post.Prompt, "Post a falsifiable prediction.") {
+		t.Errorf("second step = %d, %q", post.PlanStep, post.Prompt)
+	}
+	if planner.calls != 2 {
+		t.Errorf("planner calls = %d, want one per plan", planner.calls)
+	}
+}
+
+func TestHeuristicPlan_SurveyContributeFollowUp(t *testing.T) {
+	p := &types.Persona{ID: "a1", Role: types.RoleReviewer, Domains: []string{"optics"}}
+	weights := map[string]float64{"browse": 0.4, "read": 0.3, "post": 0.2, "interact": 0.2, "review": 0.9, "observe": 1.0}
+
+	plan := heuristicPlan(p, types.Agenda{}, weights, 0, prompts.English)
+	if got := planActionNames(plan); got != "browse,review,interact" || plan.Goal != "make progress in optics" {
+		t.Errorf("plan = %s toward %q", got, plan.Goal)
+	}
+
+	agenda := types.Agenda{Goals: []types.Goal{
+		{ID: "g1", Text: "Old goal", Status: types.AgendaDone},
+		{ID: "g2", Text: "Measure the drift", Status: types.AgendaActive},
+	}}
+	weights["interact"] = 2
+	plan = heuristicPlan(p, agenda, weights, 3, prompts.English)
+	if got := planActionNames(plan); got != "read,interact,post" || plan.Goal != "Measure the drift" {
+		t.Errorf("plan with notifications = %s toward %q", got, plan.Goal)
+	}
+}
+
+func planActionNames(p *Plan) string {
+	names := make([]string, len(p.Steps))
+	for i, step := range p.Steps {
+		names[i] = step.Action
+	}
+	return strings.Join(names, ",")
+}
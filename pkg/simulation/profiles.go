@@ -0,0 +1,152 @@
+package simulation
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/util/instructionutil"
+)
+
+// profileFiles are the per-agent files cmd/gen_agent_profiles writes under
+// <profiles dir>/<agent id>/, in the order they join the instruction.
+var profileFiles = []string{"IDENTITY.md", "SOUL.md", "HEARTBEAT.md", "USER.md"}
+
+// agentInstruction is an agent's system instruction: the built-in template
+// followed by its profile files. The agent reads it on every LLM call, so a
+// reload takes effect on the agent's next turn without rebuilding it.
+type agentInstruction struct {
+	mu sync.RWMutex
+	// base is the built-in template, rebuilt when the persona's traits drift.
+	base    string
+	profile string
+	// heading introduces the profile in the instruction's language.
+	heading string
+	// stamp identifies the profile files' versions (name, size, mtime).
+	stamp string
+
+	// budget is the model's prompt token budget (0: none) and reserve the
+	// part kept free for the turn's prompt, tool outputs and reply; see
+	// context_budget.go.
+	budget  int
+	reserve int
+}
+
+// provide renders the instruction for one LLM call. Session state (e.g.
+// {agent_summary?}) is injected into the built-in template only; profile
+// text is hand-written and may contain braces of its own.
+func (in *agentInstruction) provide(ctx agent.ReadonlyContext) (string, error) {
+	in.mu.RLock()
+	base, profile, heading := in.base, in.profile, in.heading
+	in.mu.RUnlock()
+	if in.budget > 0 && strings.Contains(base, summarySlot) {
+		suffix := ""
+		if profile != "" {
+			suffix = heading + profile
+		}
+		return in.provideWithin(ctx, base, suffix)
+	}
+	text, err := instructionutil.InjectSessionState(ctx, base)
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		return text, nil
+	}
+	return text + heading + profile, nil
+}
+
+func (in *agentInstruction) setBase(base string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.base = base
+}
+
+// reload re-reads the agent's profile files if any changed since the last
+// load and reports whether the instruction changed. A missing directory
+// clears the profile.
+func (in *agentInstruction) reload(dir string) (bool, error) {
+	stamp, err := profileStamp(dir)
+	if err != nil {
+		return false, err
+	}
+	in.mu.RLock()
+	same := stamp == in.stamp
+	in.mu.RUnlock()
+	if same {
+		return false, nil
+	}
+
+	parts := make([]string, 0, len(profileFiles))
+	for _, name := range profileFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return false, err
+		}
+		if text := strings.TrimSpace(string(data)); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.profile = strings.Join(parts, "\n\n")
+	in.stamp = stamp
+	return true, nil
+}
+
+func profileStamp(dir string) (string, error) {
+	var b strings.Builder
+	for _, name := range profileFiles {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// ReloadProfiles re-reads the profile files of every agent whose files
+// changed since they were last loaded and returns how many agents changed.
+// RunTick calls it before each tick, so edits apply from the next tick on.
+// It is a no-op without ADKSchedulerConfig.ProfilesDir.
+func (s *ADKScheduler) ReloadProfiles() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reloadProfilesLocked()
+}
+
+func (s *ADKScheduler) reloadProfilesLocked() int {
+	if s.profilesDir == "" {
+		return 0
+	}
+	changed := 0
+	for id, ar := range s.runners {
+		if ar.retired || ar.instruction == nil {
+			continue
+		}
+		ok, err := ar.instruction.reload(filepath.Join(s.profilesDir, id))
+		if err != nil {
+			log.Printf("Failed to reload profile for %s: %v", ar.persona.Name, err)
+			continue
+		}
+		if ok {
+			changed++
+			if s.ticks > 0 {
+				log.Printf("[Tick %d] Reloaded profile for %s", s.ticks, ar.persona.Name)
+			}
+		}
+	}
+	return changed
+}
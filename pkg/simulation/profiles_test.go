@@ -0,0 +1,106 @@
+package simulation
+
+import (
+	"context"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// instructionLLM records the system instruction of every request.
+type instructionLLM struct {
+	mu   sync.Mutex
+	seen []string
+}
+
+func (m *instructionLLM) Name() string { return "instruction-llm" }
+
+func (m *instructionLLM) GenerateContent(_ context.Context, req *adkmodel.LLMRequest, _ bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	var text strings.Builder
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		for _, p := range req.Config.SystemInstruction.Parts {
+			text.WriteString(p.Text)
+		}
+	}
+	m.mu.Lock()
+	m.seen = append(m.seen, text.String())
+	m.mu.Unlock()
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func (m *instructionLLM) last() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.seen[len(m.seen)-1]
+}
+
+func TestADKScheduler_ReloadsProfilesBetweenTicks(t *testing.T) {
+	tempDir := t.TempDir()
+	profiles := filepath.Join(tempDir, "profiles")
+	agentDir := filepath.Join(profiles, "agent-1")
+	if err := os.MkdirAll(agentDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(agentDir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("IDENTITY.md", "# IDENTITY\nName: Ada {not a placeholder}")
+	write("SOUL.md", "# SOUL\nValue falsifiability.")
+
+	llm := &instructionLLM{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           llm,
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		TurnLimit:       10,
+		ProfilesDir:     profiles,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Ada", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	got := llm.last()
+	if !strings.Contains(got, "你是 Ada") || !strings.Contains(got, "{not a placeholder}") || !strings.Contains(got, "Value falsifiability.") {
+		t.Fatalf("profile not merged into instruction:\n%s", got)
+	}
+	if strings.Contains(got, "{agent_summary?}") {
+		t.Fatalf("session state not injected:\n%s", got)
+	}
+
+	write("SOUL.md", "# SOUL\nValue bold conjectures over caution.")
+	write("USER.md", "# USER\nServe the community.")
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	got = llm.last()
+	if strings.Contains(got, "Value falsifiability.") || !strings.Contains(got, "bold conjectures") || !strings.Contains(got, "Serve the community.") {
+		t.Fatalf("edited profile not reloaded:\n%s", got)
+	}
+	if n := sched.ReloadProfiles(); n != 0 {
+		t.Fatalf("expected no changes on an unchanged profile, got %d", n)
+	}
+}
@@ -0,0 +1,102 @@
+package simulation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles model requests with one token bucket per provider.
+//
+// Buckets are created lazily; providers without an explicit limit share the
+// default rate. A rate <= 0 means unlimited.
+type RateLimiter struct {
+	mu         sync.Mutex
+	defaultRPS float64
+	limits     map[string]float64
+	buckets    map[string]*tokenBucket
+	now        func() time.Time
+}
+
+type tokenBucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter with a default requests-per-second rate and
+// optional per-provider overrides (e.g. {"gemini": 2, "openrouter": 5}).
+func NewRateLimiter(defaultRPS float64, perProvider map[string]float64) *RateLimiter {
+	limits := make(map[string]float64, len(perProvider))
+	for k, v := range perProvider {
+		limits[k] = v
+	}
+	return &RateLimiter{
+		defaultRPS: defaultRPS,
+		limits:     limits,
+		buckets:    make(map[string]*tokenBucket),
+		now:        time.Now,
+	}
+}
+
+// Wait blocks until a request for provider is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, provider string) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		delay := l.reserve(provider)
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0, otherwise it
+// returns how long to wait before trying again.
+func (l *RateLimiter) reserve(provider string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[provider]
+	if !ok {
+		rate := l.defaultRPS
+		if v, ok := l.limits[provider]; ok {
+			rate = v
+		}
+		if rate <= 0 {
+			return 0
+		}
+		burst := rate
+		if burst < 1 {
+			burst = 1
+		}
+		b = &tokenBucket{rate: rate, burst: burst, tokens: burst, last: l.now()}
+		l.buckets[provider] = b
+	}
+	if b.rate <= 0 {
+		return 0
+	}
+
+	now := l.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	missing := 1 - b.tokens
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
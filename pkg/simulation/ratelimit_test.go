@@ -0,0 +1,122 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_RunsAgentsInParallel(t *testing.T) {
+	tempDir := t.TempDir()
+
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{
+			Role:  "model",
+			Parts: []*genai.Part{{Text: "ok"}},
+		},
+	})
+
+	logger := &memoryLogger{}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		StartTime:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		AgentsPerTick:   4,
+		MaxParallel:     4,
+		RateLimiter:     NewRateLimiter(1000, nil),
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	for i := 1; i <= 4; i++ {
+		persona := &types.Persona{
+			ID:   fmt.Sprintf("agent-%d", i),
+			Name: fmt.Sprintf("Tester %d", i),
+			Role: types.RoleExplorer,
+		}
+		if err := sched.AddAgent(ctx, persona); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	if len(logger.events) != 4 {
+		t.Fatalf("expected 4 log events, got %d", len(logger.events))
+	}
+	seen := map[string]bool{}
+	for _, ev := range logger.events {
+		if ev.Response != "ok" {
+			t.Fatalf("expected response ok for %s, got %q", ev.AgentID, ev.Response)
+		}
+		seen[ev.AgentID] = true
+	}
+	if len(seen) != 4 {
+		t.Fatalf("expected 4 distinct agents, got %d", len(seen))
+	}
+}
+
+func TestRateLimiter_ThrottlesPerProvider(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(0, map[string]float64{"gemini": 1})
+	l.now = func() time.Time { return now }
+
+	if d := l.reserve("gemini"); d != 0 {
+		t.Fatalf("first request should pass, got delay %s", d)
+	}
+	if d := l.reserve("gemini"); d <= 0 {
+		t.Fatalf("second request should be delayed")
+	}
+	if d := l.reserve("openrouter"); d != 0 {
+		t.Fatalf("unlimited provider should pass, got delay %s", d)
+	}
+	now = now.Add(time.Second)
+	if d := l.reserve("gemini"); d != 0 {
+		t.Fatalf("request after refill should pass, got delay %s", d)
+	}
+}
+
+func TestADKScheduler_RateLimitsEachModelRequest(t *testing.T) {
+	tempDir := t.TempDir()
+	limiter := NewRateLimiter(10, nil)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           postingLLM{},
+		Logger:          &memoryLogger{},
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		AgentsPerTick:   1,
+		RateLimiter:     limiter,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	// The turn calls create_post and then answers: two model requests.
+	b := limiter.buckets["posting-llm"]
+	if b == nil || b.tokens != 8 {
+		t.Fatalf("expected 2 of 10 tokens taken for the turn's requests, got bucket %+v", b)
+	}
+}
@@ -0,0 +1,43 @@
+package simulation
+
+import (
+	"github.com/cpunion/sci-bot/pkg/reputation"
+)
+
+// refreshReputation recomputes every agent's reputation from its karma, the
+// journal/workflow record and how reviewed papers fared later, stores it in
+// agent state and publishes it to the shared board, then lets the same
+// outcomes drift the agent's traits.
+// Called from RunTick with s.mu held.
+func (s *ADKScheduler) refreshReputation() {
+	activity := reputation.Collect(s.journal, s.workflow)
+	reputation.CollectOutcomes(activity, s.journal, s.workflow, s.forum)
+	collaborations := s.collaborations()
+	for id, ar := range s.runners {
+		if ar == nil || ar.state == nil {
+			continue
+		}
+		act := reputation.Activity{}
+		if a, ok := activity[id]; ok {
+			act = *a
+		}
+		rep := reputation.Compute(ar.state.GetKarma(), act, s.simTime)
+		ar.state.SetReputation(rep)
+		s.reputation.Set(id, rep)
+		s.evolveTraits(ar, act, collaborations[id])
+	}
+}
+
+// reputationWeights scales an agent's action weights by its normalized
+// reputation: well-regarded agents post and review more, while agents with
+// no standing yet lean toward reading and observing.
+func reputationWeights(base map[string]float64, rep float64) map[string]float64 {
+	weights := make(map[string]float64, len(base))
+	for k, v := range base {
+		weights[k] = v
+	}
+	weights["post"] *= 0.8 + 0.6*rep
+	weights["review"] *= 0.8 + 0.6*rep
+	weights["interact"] *= 0.9 + 0.3*rep
+	return weights
+}
@@ -0,0 +1,34 @@
+package simulation
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// ActionRetraction marks log events for retracted journal papers.
+const ActionRetraction = "retraction"
+
+// logRetraction writes a feed event for a retracted paper. Retractions come
+// from tool calls inside RunTick, which already holds s.mu.
+func (s *ADKScheduler) logRetraction(ev busEvent) {
+	pub := ev.publication
+	if s.logger == nil || pub == nil {
+		return
+	}
+	entry := EventLog{
+		Timestamp: time.Now(),
+		SimTime:   s.simTime,
+		Tick:      s.ticks,
+		AgentID:   pub.RetractedBy,
+		Action:    ActionRetraction,
+		Response:  fmt.Sprintf("Retracted %s %q: %s", pub.ID, pub.Title, pub.RetractionReason),
+	}
+	if ar, ok := s.runners[pub.RetractedBy]; ok {
+		entry.AgentName = ar.persona.Name
+		entry.ModelName = ar.modelName
+	}
+	if err := s.logger.LogEvent(entry); err != nil {
+		log.Printf("Failed to log retraction: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"math/rand"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// Kinds of model failure recorded on failed turns (EventLog.ErrorKind).
+const (
+	ErrorRateLimited = "rate_limited"
+	ErrorServer      = "server_error"
+	ErrorTimeout     = "timeout"
+	ErrorCircuitOpen = "circuit_open"
+	ErrorOther       = "error"
+)
+
+// RetryPolicy retries model calls that fail with a transient error (429,
+// 5xx, timeouts) before any response was received, waiting BaseDelay,
+// 2*BaseDelay, ... (capped at MaxDelay, with jitter) between attempts.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries per call; <= 1 disables retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// delay returns the backoff before retry number n (1-based).
+func (p RetryPolicy) delay(n int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = time.Second
+	}
+	d := base << (n - 1)
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d <= 0) {
+		d = p.MaxDelay
+	}
+	// Up to 25% jitter so agents that failed together don't retry together.
+	return d + time.Duration(rand.Int63n(int64(d)/4+1))
+}
+
+// ModelError is the error a model call finally failed with, after retries.
+type ModelError struct {
+	Provider string
+	Kind     string
+	Attempts int
+	Err      error
+}
+
+func (e *ModelError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s (%s after %d attempts): %v", e.Provider, e.Kind, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", e.Provider, e.Kind, e.Err)
+}
+
+func (e *ModelError) Unwrap() error { return e.Err }
+
+// errCircuitOpen is returned without calling the model while a provider's
+// circuit is open.
+var errCircuitOpen = errors.New("circuit open: provider failing, skipping call")
+
+var statusPattern = regexp.MustCompile(`(?i)status(?: code)?:? ?(\d{3})`)
+
+// classifyModelError returns the ErrorKind of err and whether retrying it
+// may help.
+func classifyModelError(err error) (kind string, transient bool) {
+	if errors.Is(err, errCircuitOpen) {
+		return ErrorCircuitOpen, false
+	}
+	var me *ModelError
+	if errors.As(err, &me) {
+		return me.Kind, false
+	}
+	code := 0
+	var apiErr genai.APIError
+	var apiErrPtr *genai.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		code = apiErr.Code
+	case errors.As(err, &apiErrPtr):
+		code = apiErrPtr.Code
+	default:
+		// The OpenAI-compatible client reports "API error (status 429): ...".
+		if m := statusPattern.FindStringSubmatch(err.Error()); m != nil {
+			code, _ = strconv.Atoi(m[1])
+		}
+	}
+	switch {
+	case code == 429:
+		return ErrorRateLimited, true
+	case code >= 500:
+		return ErrorServer, true
+	case code > 0:
+		return ErrorOther, false
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorTimeout, true
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "rate limit") || strings.Contains(msg, "resource_exhausted"):
+		return ErrorRateLimited, true
+	case strings.Contains(msg, "overloaded") || strings.Contains(msg, "unavailable"):
+		return ErrorServer, true
+	}
+	return ErrorOther, false
+}
+
+// CircuitBreaker stops calling a provider after a run of consecutive
+// transient failures. Calls fail fast while the circuit is open; once the
+// cooldown has passed one trial call is let through, and its outcome closes
+// the circuit or opens it for another cooldown. A nil *CircuitBreaker never
+// trips.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	circuits  map[string]*circuit
+	now       func() time.Time
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// NewCircuitBreaker opens a provider's circuit after threshold consecutive
+// failures, for cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a call to provider may go ahead.
+func (b *CircuitBreaker) allow(provider string) bool {
+	if b == nil || b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[provider]
+	if c == nil || c.failures < b.threshold {
+		return true
+	}
+	if c.probing || b.now().Before(c.openUntil) {
+		return false
+	}
+	c.probing = true
+	return true
+}
+
+// record notes the outcome of a call to provider. Only transient failures
+// count: a malformed request says nothing about the provider's health.
+func (b *CircuitBreaker) record(provider string, err error) {
+	if b == nil || b.threshold <= 0 {
+		return
+	}
+	if err != nil {
+		if _, transient := classifyModelError(err); !transient {
+			err = nil
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[provider]
+	if c == nil {
+		c = &circuit{}
+		b.circuits[provider] = c
+	}
+	c.probing = false
+	if err == nil {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= b.threshold {
+		if c.failures == b.threshold {
+			log.Printf("Circuit open for %s after %d failures: %v", provider, c.failures, err)
+		}
+		c.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// retryModel wraps an agent's model with the retry policy, the provider's
+// circuit breaker and its rate limit, which every attempt waits for since
+// each is a request to the provider. Failures reach the runner as
+// *ModelError.
+type retryModel struct {
+	inner    model.LLM
+	provider string
+	policy   RetryPolicy
+	breaker  *CircuitBreaker
+	limiter  *RateLimiter
+	sleep    func(context.Context, time.Duration) error
+}
+
+func (m *retryModel) Name() string {
+	return m.inner.Name()
+}
+
+func (m *retryModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for attempt := 1; ; attempt++ {
+			if !m.breaker.allow(m.provider) {
+				yield(nil, &ModelError{Provider: m.provider, Kind: ErrorCircuitOpen, Attempts: attempt, Err: errCircuitOpen})
+				return
+			}
+			if err := m.limiter.Wait(ctx, m.provider); err != nil {
+				yield(nil, err)
+				return
+			}
+			yielded := false
+			var failure error
+			for resp, err := range m.inner.GenerateContent(ctx, req, stream) {
+				if err != nil {
+					failure = err
+					break
+				}
+				yielded = true
+				if !yield(resp, nil) {
+					m.breaker.record(m.provider, nil)
+					return
+				}
+			}
+			m.breaker.record(m.provider, failure)
+			if failure == nil {
+				return
+			}
+			kind, transient := classifyModelError(failure)
+			// A partly streamed reply can't be taken back, and a canceled run
+			// shouldn't be retried.
+			if transient && !yielded && attempt < m.policy.MaxAttempts && ctx.Err() == nil {
+				delay := m.policy.delay(attempt)
+				log.Printf("Model %s: %s (attempt %d/%d), retrying in %s: %v",
+					m.provider, kind, attempt, m.policy.MaxAttempts, delay.Round(time.Millisecond), failure)
+				if err := m.sleep(ctx, delay); err == nil {
+					continue
+				}
+			}
+			yield(nil, &ModelError{Provider: m.provider, Kind: kind, Attempts: attempt, Err: failure})
+			return
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,112 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// flakyLLM fails its first `failures` calls with err, then answers "ok".
+type flakyLLM struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	calls    int
+}
+
+func (m *flakyLLM) Name() string { return "flaky-llm" }
+
+func (m *flakyLLM) GenerateContent(context.Context, *adkmodel.LLMRequest, bool) iter.Seq2[*adkmodel.LLMResponse, error] {
+	return func(yield func(*adkmodel.LLMResponse, error) bool) {
+		m.mu.Lock()
+		m.calls++
+		fail := m.calls <= m.failures
+		m.mu.Unlock()
+		if fail {
+			yield(nil, m.err)
+			return
+		}
+		yield(&adkmodel.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func TestADKScheduler_RetriesAndBreaksOnModelErrors(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newScheduler := func(llm adkmodel.LLM, breaker *CircuitBreaker) (*ADKScheduler, *memoryLogger) {
+		tempDir := t.TempDir()
+		logger := &memoryLogger{}
+		sched := NewADKScheduler(ADKSchedulerConfig{
+			DataPath:        tempDir,
+			Model:           llm,
+			Logger:          logger,
+			StartTime:       now,
+			AgentsPerTick:   1,
+			CheckpointEvery: 1000,
+			Retry:           RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			Breaker:         breaker,
+		})
+		sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+		sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+		if err := sched.AddAgent(context.Background(), &types.Persona{ID: "agent-1", Name: "Tester", Role: types.RoleExplorer}); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+		return sched, logger
+	}
+	ctx := context.Background()
+
+	// Two 503s are retried away.
+	flaky := &flakyLLM{failures: 2, err: errors.New("API error (status 503): overloaded")}
+	sched, logger := newScheduler(flaky, nil)
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	if ev := logger.events[0]; ev.Failed || ev.Response != "ok" || flaky.calls != 3 {
+		t.Fatalf("expected a retried success, got %+v after %d calls", ev, flaky.calls)
+	}
+
+	// Persistent 429s fail the turn and open the circuit.
+	limited := &flakyLLM{failures: 100, err: genai.APIError{Code: 429, Message: "quota"}}
+	breaker := NewCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+	sched, logger = newScheduler(limited, breaker)
+	for range 2 {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+	if ev := logger.events[0]; !ev.Failed || ev.ErrorKind != ErrorRateLimited || ev.Attempts != 3 || ev.Error == "" {
+		t.Fatalf("expected a rate-limited failure after 3 attempts, got %+v", ev)
+	}
+	if ev := logger.events[1]; !ev.Failed || ev.ErrorKind != ErrorCircuitOpen || limited.calls != 3 {
+		t.Fatalf("expected the open circuit to skip the model, got %+v after %d calls", ev, limited.calls)
+	}
+
+	// After the cooldown one trial call goes through and closes the circuit.
+	now = now.Add(time.Minute)
+	limited.failures = 0
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	if ev := logger.events[2]; ev.Failed || ev.Response != "ok" {
+		t.Fatalf("expected the trial call to succeed, got %+v", ev)
+	}
+
+	// Client errors are not retried.
+	bad := &flakyLLM{failures: 1, err: errors.New("API error (status 400): bad request")}
+	sched, logger = newScheduler(bad, nil)
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	if ev := logger.events[0]; !ev.Failed || ev.ErrorKind != ErrorOther || bad.calls != 1 {
+		t.Fatalf("expected one failed attempt, got %+v after %d calls", ev, bad.calls)
+	}
+}
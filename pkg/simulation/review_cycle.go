@@ -0,0 +1,174 @@
+package simulation
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// advanceReviewCycles decides fully reviewed submissions, and at each cutoff
+// escalates overdue ones to the editor and batches new submissions into the
+// next cycle. Caller must hold s.mu.
+func (s *ADKScheduler) advanceReviewCycles() {
+	if s.reviewCycle <= 0 || s.workflow == nil {
+		return
+	}
+
+	if s.nextReviewCutoff.IsZero() {
+		if last := s.workflow.LastReviewCutoff(); !last.IsZero() {
+			s.nextReviewCutoff = last.Add(s.reviewCycle)
+		} else {
+			s.nextReviewCutoff = s.simTime.Add(s.reviewCycle)
+		}
+	}
+
+	changed := s.decideSubmissions()
+	for !s.simTime.Before(s.nextReviewCutoff) {
+		cutoff := s.nextReviewCutoff
+		s.nextReviewCutoff = cutoff.Add(s.reviewCycle)
+		cycle := s.workflow.OpenReviewCycle(cutoff, s.nextReviewCutoff, s.reviewerRotation(), s.reviewersPerPaper)
+		if cycle == nil {
+			continue
+		}
+		changed = true
+		log.Printf("[Review] %s: %d submission(s) assigned, decisions due %s",
+			cycle.ID, len(cycle.SubmissionIDs), cycle.DecisionDue.Format("2006-01-02 15:04"))
+	}
+
+	if changed {
+		if err := s.workflow.Save(); err != nil {
+			log.Printf("Failed to save workflow: %v", err)
+		}
+	}
+}
+
+// decideSubmissions applies decisions that are due at the current sim time.
+func (s *ADKScheduler) decideSubmissions() bool {
+	complete, overdue := s.workflow.DueDecisions(s.simTime)
+	for _, sub := range complete {
+		verdict := publication.AggregateVerdict(s.workflow.GetReviews(sub.ID))
+		s.applyDecision(sub, verdict, false)
+	}
+	for _, sub := range overdue {
+		verdict := publication.AggregateVerdict(s.workflow.GetReviews(sub.ID))
+		s.applyDecision(sub, verdict, true)
+	}
+	return len(complete)+len(overdue) > 0
+}
+
+// applyDecision records the editor's decision; escalated marks submissions
+// decided after the deadline without all assigned reviews.
+func (s *ADKScheduler) applyDecision(sub *types.Submission, verdict types.PaperReviewVerdict, escalated bool) {
+	status, err := s.workflow.Decide(s.journal, sub.ID, verdict, publication.EditorID, s.simTime, escalated)
+	if err != nil {
+		log.Printf("[Review] decide %s failed: %v", sub.ID, err)
+		return
+	}
+	if escalated {
+		log.Printf("[Review] %s -> %s (overdue, escalated to editor)", sub.ID, status)
+		return
+	}
+	log.Printf("[Review] %s -> %s", sub.ID, status)
+}
+
+// reviewerIDs returns the scheduler's reviewer agents in stable order.
+func (s *ADKScheduler) reviewerIDs() []string {
+	ids := make([]string, 0)
+	for id, ar := range s.runners {
+		if ar != nil && !ar.retired && ar.persona != nil && ar.persona.Role == types.RoleReviewer {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Reviewers need this many reviews with a known outcome before their
+// outcome quality moves them in the assignment rotation.
+const (
+	minOutcomeReviews   = 2
+	trustedOutcomeScore = 0.75
+)
+
+// reviewerRotation orders reviewers for review-cycle assignment by how well
+// their past verdicts anticipated what happened to the papers (citations,
+// disputes, retractions). Reviewers with a strong record appear twice, so
+// round-robin gives them about twice the assignments; reviewers without
+// enough record rank as neutral.
+func (s *ADKScheduler) reviewerRotation() []string {
+	ids := s.reviewerIDs()
+	weight := func(id string) float64 {
+		rep, ok := s.reputation.Get(id)
+		if !ok || rep.OutcomeReviews < minOutcomeReviews {
+			return 0.5
+		}
+		return rep.OutcomeQuality
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return weight(ids[i]) > weight(ids[j]) })
+	for _, id := range ids {
+		if weight(id) >= trustedOutcomeScore {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// prioritizeDuties moves agents owing a review or editor turn today to the
+// front so assigned reviews and triage always get turns.
+func (s *ADKScheduler) prioritizeDuties(ids []string) {
+	if s.workflow == nil {
+		return
+	}
+	owes := func(ar *agentRunner) bool { return s.owesReview(ar) || s.owesTriage(ar) }
+	sort.SliceStable(ids, func(i, j int) bool {
+		return owes(s.runners[ids[i]]) && !owes(s.runners[ids[j]])
+	})
+}
+
+func (s *ADKScheduler) owesReview(ar *agentRunner) bool {
+	if ar == nil || ar.persona == nil || ar.persona.Role != types.RoleReviewer {
+		return false
+	}
+	if ar.reviewDutyDay == s.simTime.Format("2006-01-02") {
+		return false
+	}
+	return len(s.workflow.PendingAssignments(ar.persona.ID)) > 0
+}
+
+// reviewDutyPrompt returns a review prompt for an assigned reviewer, at most
+// once per sim day. Assignments come from review cycles or editors.
+func (s *ADKScheduler) reviewDutyPrompt(ar *agentRunner) (actionPrompt, bool) {
+	if s.workflow == nil || !s.owesReview(ar) {
+		return actionPrompt{}, false
+	}
+	ar.reviewDutyDay = s.simTime.Format("2006-01-02")
+
+	subs := s.workflow.PendingAssignments(ar.persona.ID)
+	text := prompts.For(ar.lang)
+	var b strings.Builder
+	b.WriteString(text.ReviewDuty)
+	for _, sub := range subs {
+		author := fmt.Sprintf(text.ReviewAuthor, sub.AuthorName)
+		if s.journal != nil && s.journal.BlindSubmission(sub) != sub {
+			author = text.ReviewBlind
+		}
+		fmt.Fprintf(&b, text.ReviewItem, sub.ID, sub.Title, author)
+		if !sub.DecisionDue.IsZero() {
+			fmt.Fprintf(&b, text.ReviewDue, sub.DecisionDue.Format("2006-01-02"))
+		}
+		b.WriteString(text.ReviewItemEnd)
+		if r, ok := publication.LastReminder(sub, ar.persona.ID); ok {
+			b.WriteString(text.ReviewReminded)
+			if r.Note != "" {
+				fmt.Fprintf(&b, text.ReviewNote, r.Note)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return actionPrompt{action: "review_duty", text: b.String()}, true
+}
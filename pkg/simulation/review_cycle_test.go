@@ -0,0 +1,90 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_ReviewCycleEscalatesOverdue(t *testing.T) {
+	tempDir := t.TempDir()
+
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{
+			Role:  "model",
+			Parts: []*genai.Part{{Text: "ok"}},
+		},
+	})
+
+	logger := &memoryLogger{}
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:          tempDir,
+		Model:             mock,
+		Logger:            logger,
+		SimStep:           12 * time.Hour,
+		StartTime:         start,
+		AgentsPerTick:     1,
+		CheckpointEvery:   1000,
+		TurnLimit:         100,
+		ReviewCycle:       24 * time.Hour,
+		ReviewersPerPaper: 1,
+	})
+	journal := publication.NewJournal("J", filepath.Join(tempDir, "journal"))
+	sched.SetJournal(journal)
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+
+	ctx := context.Background()
+	for _, p := range []*types.Persona{
+		{ID: "agent-1", Name: "Author", Role: types.RoleExplorer},
+		{ID: "reviewer-1", Name: "Reviewer", Role: types.RoleReviewer},
+	} {
+		if err := sched.AddAgent(ctx, p); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+
+	journal.Submit(&types.Publication{ID: "sub-1", AuthorID: "agent-1", Title: "Paper"})
+	sched.workflow.AddSubmission(&types.Submission{ID: "sub-1", AuthorID: "agent-1", Title: "Paper", Status: types.SubmissionPending})
+
+	// Ticks at +0h, +12h: before the first cutoff nothing is assigned.
+	for i := 0; i < 2; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+	if sub := sched.workflow.GetSubmission("sub-1"); sub.CycleID != "" {
+		t.Fatalf("expected no cycle before cutoff, got %s", sub.CycleID)
+	}
+
+	// Tick at +24h: cutoff batches the submission and the reviewer gets a duty turn.
+	if err := sched.RunTick(ctx); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	sub := sched.workflow.GetSubmission("sub-1")
+	if sub.CycleID == "" || len(sub.AssignedReviewers) != 1 || sub.AssignedReviewers[0] != "reviewer-1" {
+		t.Fatalf("expected sub-1 assigned to reviewer-1, got cycle=%q reviewers=%v", sub.CycleID, sub.AssignedReviewers)
+	}
+	last := logger.events[len(logger.events)-1]
+	if last.AgentID != "reviewer-1" || last.Action != "review_duty" {
+		t.Fatalf("expected reviewer-1 review_duty turn, got %s %s", last.AgentID, last.Action)
+	}
+
+	// The mock reviewer never calls review_paper, so at +48h the editor decides.
+	for i := 0; i < 2; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+	sub = sched.workflow.GetSubmission("sub-1")
+	if !sub.Escalated || sub.DecidedBy != publication.EditorID || !sub.DecidedAt.Equal(start.Add(48*time.Hour)) {
+		t.Fatalf("expected escalation at +48h, got escalated=%v by=%s at=%s", sub.Escalated, sub.DecidedBy, sub.DecidedAt)
+	}
+}
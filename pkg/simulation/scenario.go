@@ -0,0 +1,312 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ActionLiteratureDrop marks log events for scenario literature drops.
+const ActionLiteratureDrop = "literature_drop"
+
+// Scenario configures a simulation run and scripts its exogenous events.
+// Every field is optional: run settings left out keep adk_simulate's flag
+// defaults, and flags given on the command line override the scenario.
+type Scenario struct {
+	Agents  int    `json:"agents,omitempty"`
+	Seed    *int64 `json:"seed,omitempty"`
+	Ticks   int    `json:"ticks,omitempty"`
+	Days    int    `json:"days,omitempty"`
+	Step    string `json:"step,omitempty"` // Go duration, e.g. "30m"
+	PerTick int    `json:"per_tick,omitempty"`
+	Lang    string `json:"lang,omitempty"`    // zh, en or mixed
+	Planner string `json:"planner,omitempty"` // heuristic or llm
+
+	Models ScenarioModels `json:"models,omitempty"`
+	Budget ScenarioBudget `json:"budget,omitempty"`
+
+	// Journals replaces the -journals config file; Subreddits are created
+	// on the forum if missing.
+	Journals   []*types.JournalInfo   `json:"journals,omitempty"`
+	Subreddits []*types.SubredditInfo `json:"subreddits,omitempty"`
+
+	// LiteratureDrops doubles as seed content: drops on day 0 are
+	// published on the first tick.
+	LiteratureDrops []LiteratureDrop `json:"literature_drops,omitempty"`
+
+	// Actions extends or retunes the weighted actions agents pick on free
+	// turns, per role.
+	Actions []ScenarioAction `json:"actions,omitempty"`
+}
+
+// ScenarioModels picks LLM model specs. Roles maps the other agent roles
+// (explorer, builder, synthesizer, communicator, editor) to their own
+// model; personas with a model of their own keep it.
+type ScenarioModels struct {
+	Default  string                     `json:"default,omitempty"`
+	Reviewer string                     `json:"reviewer,omitempty"`
+	Roles    map[types.AgentRole]string `json:"roles,omitempty"`
+}
+
+// ScenarioBudget caps token use; 0 leaves a cap at its flag default.
+type ScenarioBudget struct {
+	MaxTokens        int `json:"max_tokens,omitempty"`
+	AgentDailyTokens int `json:"agent_daily_tokens,omitempty"`
+	MaxOutputTokens  int `json:"max_output_tokens,omitempty"`
+}
+
+// Flags returns the scenario's run settings keyed by adk_simulate flag name,
+// for the settings the scenario sets.
+func (sc *Scenario) Flags() map[string]string {
+	out := make(map[string]string)
+	setInt := func(name string, v int) {
+		if v > 0 {
+			out[name] = strconv.Itoa(v)
+		}
+	}
+	setString := func(name, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			out[name] = v
+		}
+	}
+	setInt("agents", sc.Agents)
+	if sc.Seed != nil {
+		out["seed"] = strconv.FormatInt(*sc.Seed, 10)
+	}
+	setInt("ticks", sc.Ticks)
+	setInt("days", sc.Days)
+	setString("step", sc.Step)
+	setInt("per-tick", sc.PerTick)
+	setString("lang", sc.Lang)
+	setString("planner", sc.Planner)
+	setString("model", sc.Models.Default)
+	setString("reviewer-model", sc.Models.Reviewer)
+	setInt("max-tokens", sc.Budget.MaxTokens)
+	setInt("agent-daily-tokens", sc.Budget.AgentDailyTokens)
+	setInt("max-output-tokens", sc.Budget.MaxOutputTokens)
+	return out
+}
+
+func (sc *Scenario) validate() error {
+	for name, v := range map[string]int{
+		"agents":                    sc.Agents,
+		"ticks":                     sc.Ticks,
+		"days":                      sc.Days,
+		"per_tick":                  sc.PerTick,
+		"budget.max_tokens":         sc.Budget.MaxTokens,
+		"budget.agent_daily_tokens": sc.Budget.AgentDailyTokens,
+		"budget.max_output_tokens":  sc.Budget.MaxOutputTokens,
+	} {
+		if v < 0 {
+			return fmt.Errorf("%s must be >= 0", name)
+		}
+	}
+	if sc.Step != "" {
+		step, err := time.ParseDuration(sc.Step)
+		if err != nil {
+			return fmt.Errorf("step: %w", err)
+		}
+		if step <= 0 {
+			return fmt.Errorf("step must be positive")
+		}
+	}
+	if _, err := prompts.ParseMode(sc.Lang); err != nil {
+		return fmt.Errorf("lang: %w", err)
+	}
+	if !ValidPlanner(sc.Planner) {
+		return fmt.Errorf("planner: unknown mode %q", sc.Planner)
+	}
+	for role, spec := range sc.Models.Roles {
+		switch role {
+		case types.RoleExplorer, types.RoleBuilder, types.RoleSynthesizer, types.RoleCommunicator, types.RoleEditor:
+		case types.RoleReviewer:
+			return fmt.Errorf("models.roles: set the reviewer model with models.reviewer")
+		default:
+			return fmt.Errorf("models.roles: unknown role %q", role)
+		}
+		if strings.TrimSpace(spec) == "" {
+			return fmt.Errorf("models.roles.%s: model spec is required", role)
+		}
+	}
+	for i, sub := range sc.Subreddits {
+		if sub == nil || strings.TrimSpace(string(sub.Name)) == "" {
+			return fmt.Errorf("subreddit %d: name is required", i)
+		}
+	}
+	for i := range sc.Actions {
+		if err := sc.Actions[i].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LiteratureDrop publishes an outside document at a fixed sim time, so runs
+// can study how the community reacts to the arrival of a key idea.
+type LiteratureDrop struct {
+	ID string `json:"id"`
+	// Day and Hour give the sim time relative to the run's origin (day 0).
+	Day  int `json:"day"`
+	Hour int `json:"hour,omitempty"`
+	// Channel is "forum" (alias "preprint", unreviewed) or "journal"
+	// (published without review).
+	Channel   string `json:"channel"`
+	Subreddit string `json:"subreddit,omitempty"` // forum only; default general
+	Journal   string `json:"journal,omitempty"`   // journal ID or name; default routes by text
+
+	Title    string `json:"title"`
+	Abstract string `json:"abstract,omitempty"`
+	Content  string `json:"content,omitempty"`
+	// ContentFile is read into Content; relative paths resolve against the
+	// scenario file's directory.
+	ContentFile string `json:"content_file,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Source      string `json:"source,omitempty"` // citation of the original work
+}
+
+// PublicationID is the ID the drop is published under; it makes injection
+// idempotent across resumed runs.
+func (d LiteratureDrop) PublicationID() string {
+	return "exo-" + d.ID
+}
+
+// Due returns when the drop is published for a run starting at origin.
+func (d LiteratureDrop) Due(origin time.Time) time.Time {
+	return origin.Add(time.Duration(d.Day)*24*time.Hour + time.Duration(d.Hour)*time.Hour)
+}
+
+// LoadScenario reads and validates a scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Unknown fields are errors, so a misspelt setting isn't silently
+	// replaced by its default.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var sc Scenario
+	if err := dec.Decode(&sc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := sc.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	seen := make(map[string]bool, len(sc.LiteratureDrops))
+	for i := range sc.LiteratureDrops {
+		d := &sc.LiteratureDrops[i]
+		if d.ContentFile != "" {
+			file := d.ContentFile
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(filepath.Dir(path), file)
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("literature drop %s: %w", d.ID, err)
+			}
+			d.Content = string(content)
+		}
+		if err := d.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if seen[d.ID] {
+			return nil, fmt.Errorf("%s: duplicate literature drop id: %s", path, d.ID)
+		}
+		seen[d.ID] = true
+	}
+	return &sc, nil
+}
+
+func (d *LiteratureDrop) validate() error {
+	if strings.TrimSpace(d.ID) == "" {
+		return fmt.Errorf("literature drop without id")
+	}
+	if strings.TrimSpace(d.Title) == "" || strings.TrimSpace(d.Content) == "" {
+		return fmt.Errorf("literature drop %s: title and content are required", d.ID)
+	}
+	if d.Day < 0 || d.Hour < 0 || d.Hour > 23 {
+		return fmt.Errorf("literature drop %s: day must be >= 0 and hour within 0-23", d.ID)
+	}
+	switch d.Channel {
+	case "", "forum", "preprint":
+		d.Channel = string(types.ChannelForum)
+	case "journal":
+	default:
+		return fmt.Errorf("literature drop %s: unknown channel %q", d.ID, d.Channel)
+	}
+	return nil
+}
+
+// injectLiteratureDrops publishes every scenario drop that is due and not yet
+// published. Caller must hold s.mu.
+func (s *ADKScheduler) injectLiteratureDrops() {
+	if s.scenario == nil {
+		return
+	}
+	for _, drop := range s.scenario.LiteratureDrops {
+		if s.simTime.Before(drop.Due(s.simOrigin)) {
+			continue
+		}
+		pub := &types.Publication{
+			ID:         drop.PublicationID(),
+			AuthorName: drop.Author,
+			Title:      drop.Title,
+			Abstract:   drop.Abstract,
+			Content:    drop.Content,
+			Provenance: types.ProvenanceExogenous,
+			Source:     drop.Source,
+		}
+		if pub.AuthorName == "" {
+			pub.AuthorName = prompts.For(s.lang.Default()).ExternalAuthor
+		}
+
+		var err error
+		switch types.ChannelType(drop.Channel) {
+		case types.ChannelJournal:
+			if s.journal == nil || s.journal.Get(pub.ID) != nil {
+				continue
+			}
+			info, routeErr := s.journal.Route(drop.Journal, pub.Title+"\n"+pub.Abstract+"\n"+pub.Content, nil)
+			if routeErr != nil {
+				log.Printf("Literature drop %s: %v", drop.ID, routeErr)
+				continue
+			}
+			pub.JournalID = info.ID
+			err = s.journal.Publish(pub)
+		default:
+			if s.forum == nil || s.forum.Get(pub.ID) != nil {
+				continue
+			}
+			pub.Subreddit = types.Subreddit(drop.Subreddit)
+			err = s.forum.Post(pub)
+		}
+		if err != nil {
+			log.Printf("Literature drop %s failed: %v", drop.ID, err)
+			continue
+		}
+
+		log.Printf("[Scenario] literature drop %s: %q (%s)", drop.ID, drop.Title, drop.Channel)
+		if s.logger != nil {
+			ev := EventLog{
+				Timestamp: time.Now(),
+				SimTime:   s.simTime,
+				Tick:      s.ticks,
+				AgentName: pub.AuthorName,
+				Action:    ActionLiteratureDrop,
+				Response:  fmt.Sprintf("%s (%s %s)", pub.Title, drop.Channel, pub.ID),
+			}
+			if err := s.logger.LogEvent(ev); err != nil {
+				log.Printf("Failed to log literature drop: %v", err)
+			}
+		}
+	}
+}
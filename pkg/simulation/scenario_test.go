@@ -0,0 +1,119 @@
+package simulation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_InjectsLiteratureDrops(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	logger := &memoryLogger{}
+	origin := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       origin,
+		AgentsPerTick:   1,
+		CheckpointEvery: 1000,
+		Scenario: &Scenario{LiteratureDrops: []LiteratureDrop{
+			{ID: "einstein", Hour: 1, Channel: "forum", Title: "论动体的电动力学", Content: "光速不变", Source: "Annalen der Physik, 1905"},
+			{ID: "noether", Day: 1, Channel: "journal", Title: "不变变分问题", Content: "对称性与守恒律"},
+		}},
+	})
+	journal := publication.NewJournal("J", filepath.Join(tempDir, "journal"))
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	sched.SetJournal(journal)
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	if err := sched.AddAgent(ctx, &types.Persona{ID: "agent-1", Name: "Reader", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := sched.RunTick(ctx); err != nil {
+			t.Fatalf("RunTick: %v", err)
+		}
+	}
+
+	post := forum.Get("exo-einstein")
+	if post == nil || !post.Exogenous() || post.Source != "Annalen der Physik, 1905" {
+		t.Fatalf("expected exogenous forum post, got %+v", post)
+	}
+	if journal.Get("exo-noether") != nil {
+		t.Fatalf("journal drop published before day 1")
+	}
+
+	drops := 0
+	for _, ev := range logger.events {
+		if ev.Action == ActionLiteratureDrop {
+			drops++
+		}
+	}
+	if drops != 1 {
+		t.Fatalf("expected 1 literature drop event, got %d", drops)
+	}
+}
+
+func TestLoadScenario_RunSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.json")
+	write := func(text string) {
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatalf("write scenario: %v", err)
+		}
+	}
+
+	write(`{
+		"agents": 8,
+		"seed": 0,
+		"step": "30m",
+		"models": {"default": "gemini:flash", "roles": {"editor": "gemini:pro"}},
+		"budget": {"agent_daily_tokens": 50000},
+		"subreddits": [{"name": "topology"}],
+		"literature_drops": [{"id": "seed", "title": "Seed", "content": "text"}]
+	}`)
+	sc, err := LoadScenario(path)
+	if err != nil {
+		t.Fatalf("LoadScenario: %v", err)
+	}
+	got := sc.Flags()
+	want := map[string]string{"agents": "8", "seed": "0", "step": "30m", "model": "gemini:flash", "agent-daily-tokens": "50000"}
+	if len(got) != len(want) {
+		t.Fatalf("expected flags %v, got %v", want, got)
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Fatalf("expected %s=%s, got %v", name, v, got)
+		}
+	}
+	if sc.Models.Roles[types.RoleEditor] != "gemini:pro" || len(sc.Subreddits) != 1 || len(sc.LiteratureDrops) != 1 {
+		t.Fatalf("unexpected scenario: %+v", sc)
+	}
+
+	for _, bad := range []string{
+		`{"agent": 8}`,
+		`{"step": "soon"}`,
+		`{"per_tick": -1}`,
+		`{"models": {"roles": {"reviewer": "gemini:pro"}}}`,
+		`{"subreddits": [{"description": "no name"}]}`,
+	} {
+		write(bad)
+		if _, err := LoadScenario(path); err == nil {
+			t.Fatalf("expected %s to be rejected", bad)
+		}
+	}
+}
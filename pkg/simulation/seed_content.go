@@ -0,0 +1,297 @@
+package simulation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// SeedDoc is one piece of starting content imported from a seed corpus.
+type SeedDoc struct {
+	// ID defaults to the file name; the publication ID is "seed-<id>".
+	ID        string `json:"id,omitempty"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Abstract  string `json:"abstract,omitempty"`
+	Subreddit string `json:"subreddit,omitempty"` // forum only; default general
+	// Channel is "forum" (default) or "journal" (published as an accepted
+	// paper).
+	Channel string `json:"channel,omitempty"`
+	Journal string `json:"journal,omitempty"` // journal ID or name; default routes by text
+	// Author is a persona ID or name, or a name listed in the corpus's
+	// authors.json; anything else is assigned to personas in turn.
+	Author string `json:"author,omitempty"`
+}
+
+// PublicationID is the ID the document is published under; it makes
+// imports idempotent across resumed runs.
+func (d SeedDoc) PublicationID() string {
+	return "seed-" + d.ID
+}
+
+// SeedCorpus is a directory of seed documents: *.md files with optional
+// front matter (title, abstract, subreddit, channel, journal, author, id)
+// and *.json files holding one document or a list of them. An optional
+// authors.json maps author names in the corpus to persona IDs.
+type SeedCorpus struct {
+	Docs    []SeedDoc
+	Authors map[string]string
+}
+
+// LoadSeedCorpus reads and validates a seed corpus directory.
+func LoadSeedCorpus(dir string) (*SeedCorpus, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	corpus := &SeedCorpus{Authors: make(map[string]string)}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		path := filepath.Join(dir, name)
+		stem := strings.TrimSuffix(name, filepath.Ext(name))
+		switch {
+		case name == "authors.json":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &corpus.Authors); err != nil {
+				return nil, fmt.Errorf("parse %s: %w", path, err)
+			}
+		case strings.EqualFold(filepath.Ext(name), ".md"):
+			doc, err := readSeedMarkdown(path)
+			if err != nil {
+				return nil, err
+			}
+			if doc.ID == "" {
+				doc.ID = stem
+			}
+			corpus.Docs = append(corpus.Docs, doc)
+		case strings.EqualFold(filepath.Ext(name), ".json"):
+			docs, err := readSeedJSON(path)
+			if err != nil {
+				return nil, err
+			}
+			for i := range docs {
+				if docs[i].ID == "" {
+					docs[i].ID = stem
+					if len(docs) > 1 {
+						docs[i].ID = fmt.Sprintf("%s-%d", stem, i+1)
+					}
+				}
+			}
+			corpus.Docs = append(corpus.Docs, docs...)
+		}
+	}
+
+	sort.SliceStable(corpus.Docs, func(i, j int) bool { return corpus.Docs[i].ID < corpus.Docs[j].ID })
+	seen := make(map[string]bool, len(corpus.Docs))
+	for i := range corpus.Docs {
+		d := &corpus.Docs[i]
+		if err := d.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", dir, err)
+		}
+		if seen[d.ID] {
+			return nil, fmt.Errorf("%s: duplicate seed document id: %s", dir, d.ID)
+		}
+		seen[d.ID] = true
+	}
+	return corpus, nil
+}
+
+func readSeedJSON(path string) ([]SeedDoc, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var docs []SeedDoc
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		err = json.Unmarshal(data, &docs)
+	} else {
+		var doc SeedDoc
+		err = json.Unmarshal(data, &doc)
+		docs = []SeedDoc{doc}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return docs, nil
+}
+
+// readSeedMarkdown reads a Markdown document. Front matter holds
+// "key: value" lines (values may be JSON-quoted, as cmd/export_papers
+// writes them; for lists only the first item is kept). Without a title the
+// first "# " heading is used, and that heading is dropped from the body.
+func readSeedMarkdown(path string) (SeedDoc, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SeedDoc{}, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	meta := make(map[string]string)
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		if end := strings.Index(rest, "\n---"); end >= 0 {
+			parseFrontMatter(rest[:end], meta)
+			text = rest[end+len("\n---"):]
+			text = strings.TrimPrefix(text, "\n")
+		}
+	}
+	doc := SeedDoc{
+		ID:        meta["id"],
+		Title:     meta["title"],
+		Abstract:  meta["abstract"],
+		Subreddit: meta["subreddit"],
+		Channel:   meta["channel"],
+		Journal:   meta["journal"],
+		Author:    meta["author"],
+	}
+	body := strings.TrimSpace(text)
+	first, rest, _ := strings.Cut(body, "\n")
+	if title, ok := strings.CutPrefix(first, "# "); ok {
+		title = strings.TrimSpace(title)
+		if doc.Title == "" {
+			doc.Title = title
+		}
+		if title == doc.Title {
+			body = strings.TrimSpace(rest)
+		}
+	}
+	doc.Body = body
+	return doc, nil
+}
+
+func parseFrontMatter(text string, meta map[string]string) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	listKey := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok && listKey != "" {
+			if meta[listKey] == "" {
+				meta[listKey] = frontMatterValue(item)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue // nested values
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		listKey = ""
+		if value = strings.TrimSpace(value); value == "" {
+			listKey = key
+			continue
+		}
+		meta[key] = frontMatterValue(value)
+	}
+}
+
+func frontMatterValue(v string) string {
+	v = strings.TrimSpace(v)
+	if strings.HasPrefix(v, `"`) {
+		var s string
+		if err := json.Unmarshal([]byte(v), &s); err == nil {
+			return s
+		}
+	}
+	return strings.Trim(v, `'"`)
+}
+
+func (d *SeedDoc) validate() error {
+	d.ID = strings.TrimSpace(d.ID)
+	if d.ID == "" {
+		return fmt.Errorf("seed document without id")
+	}
+	if strings.TrimSpace(d.Title) == "" || strings.TrimSpace(d.Body) == "" {
+		return fmt.Errorf("seed document %s: title and body are required", d.ID)
+	}
+	switch d.Channel {
+	case "", "forum":
+		d.Channel = string(types.ChannelForum)
+	case "journal":
+	default:
+		return fmt.Errorf("seed document %s: unknown channel %q", d.ID, d.Channel)
+	}
+	return nil
+}
+
+// Import publishes the corpus documents not published yet: forum documents
+// as posts, journal documents as accepted papers. Each is attributed to a
+// persona (see SeedDoc.Author). It returns how many posts and papers were
+// added.
+func (c *SeedCorpus) Import(forum *publication.Forum, journal *publication.Journal, personas []*types.Persona) (int, int) {
+	if len(personas) == 0 {
+		return 0, 0
+	}
+	byKey := make(map[string]*types.Persona, 2*len(personas))
+	for _, p := range personas {
+		byKey[p.ID] = p
+		byKey[p.Name] = p
+	}
+	next := 0
+	author := func(name string) *types.Persona {
+		if id, ok := c.Authors[name]; ok {
+			name = id
+		}
+		if p, ok := byKey[name]; ok {
+			return p
+		}
+		p := personas[next%len(personas)]
+		next++
+		return p
+	}
+
+	posts, papers := 0, 0
+	for _, d := range c.Docs {
+		p := author(d.Author)
+		pub := &types.Publication{
+			ID:         d.PublicationID(),
+			AuthorID:   p.ID,
+			AuthorName: p.Name,
+			Title:      d.Title,
+			Abstract:   d.Abstract,
+			Content:    d.Body,
+		}
+		var err error
+		switch types.ChannelType(d.Channel) {
+		case types.ChannelJournal:
+			if journal == nil || journal.Get(pub.ID) != nil {
+				continue
+			}
+			info, routeErr := journal.Route(d.Journal, pub.Title+"\n"+pub.Abstract+"\n"+pub.Content, nil)
+			if routeErr != nil {
+				log.Printf("Seed document %s: %v", d.ID, routeErr)
+				continue
+			}
+			pub.JournalID = info.ID
+			if err = journal.Publish(pub); err == nil {
+				papers++
+			}
+		default:
+			if forum == nil || forum.Get(pub.ID) != nil {
+				continue
+			}
+			pub.Subreddit = publication.NormalizeSubreddit(d.Subreddit)
+			if err = forum.Post(pub); err == nil {
+				posts++
+			}
+		}
+		if err != nil {
+			log.Printf("Seed document %s failed: %v", d.ID, err)
+		}
+	}
+	return posts, papers
+}
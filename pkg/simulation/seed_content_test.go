@@ -0,0 +1,74 @@
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestSeedCorpus_LoadAndImport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"falling.md": "---\ntitle: \"Falling bodies\"\nauthor:\n  - \"Galileo\"\nsubreddit: physics\n---\n\n# Falling bodies\n\nDo heavy bodies fall faster?\n",
+		"axioms.md":  "# Axioms\n\nThrough two points there is exactly one line.\n",
+		"papers.json": `[
+			{"title": "On Method", "body": "Doubt everything.", "channel": "journal", "author": "agent-2"},
+			{"title": "Untitled note", "body": "A note.", "author": "Nobody"}
+		]`,
+		"authors.json": `{"Galileo": "agent-3"}`,
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	corpus, err := LoadSeedCorpus(dir)
+	if err != nil {
+		t.Fatalf("LoadSeedCorpus: %v", err)
+	}
+	if len(corpus.Docs) != 4 {
+		t.Fatalf("expected 4 documents, got %+v", corpus.Docs)
+	}
+
+	personas := []*types.Persona{
+		{ID: "agent-1", Name: "Ada"},
+		{ID: "agent-2", Name: "Bo"},
+		{ID: "agent-3", Name: "Cy"},
+	}
+	forum := publication.NewForum("forum", "")
+	journal := publication.NewJournal("journal", "")
+	posts, papers := corpus.Import(forum, journal, personas)
+	if posts != 3 || papers != 1 {
+		t.Fatalf("expected 3 posts and 1 paper, got %d and %d", posts, papers)
+	}
+
+	falling := forum.Get("seed-falling")
+	if falling == nil || falling.Title != "Falling bodies" || falling.AuthorID != "agent-3" ||
+		falling.Subreddit != types.SubPhysics || falling.Content != "Do heavy bodies fall faster?" {
+		t.Fatalf("unexpected falling post: %+v", falling)
+	}
+	if axioms := forum.Get("seed-axioms"); axioms == nil || axioms.Title != "Axioms" || axioms.Content != "Through two points there is exactly one line." {
+		t.Fatalf("unexpected axioms post: %+v", axioms)
+	}
+	if paper := journal.Get("seed-papers-1"); paper == nil || !paper.Approved || paper.AuthorID != "agent-2" {
+		t.Fatalf("unexpected journal paper: %+v", paper)
+	}
+	if note := forum.Get("seed-papers-2"); note == nil || note.AuthorID == "" {
+		t.Fatalf("expected the unknown author to be assigned a persona, got %+v", note)
+	}
+
+	// Importing again (e.g. on resume) adds nothing.
+	if posts, papers := corpus.Import(forum, journal, personas); posts != 0 || papers != 0 {
+		t.Fatalf("expected a second import to be a no-op, got %d posts and %d papers", posts, papers)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"title": "No body"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSeedCorpus(dir); err == nil {
+		t.Fatal("expected a document without body to be rejected")
+	}
+}
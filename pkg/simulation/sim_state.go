@@ -2,6 +2,8 @@ package simulation
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"time"
@@ -10,8 +12,26 @@ import (
 // SimState captures the persisted simulation clock.
 type SimState struct {
 	SimTime     time.Time `json:"sim_time"`
+	Origin      time.Time `json:"origin,omitempty"` // sim time of the first tick
 	Ticks       int       `json:"ticks"`
 	StepSeconds int       `json:"step_seconds"`
+	// RunID names the run; a forked data directory gets a new one.
+	RunID string      `json:"run_id,omitempty"`
+	Fork  *ForkOrigin `json:"fork,omitempty"`
+}
+
+// ForkOrigin records where a forked run branched off (see cmd/fork_world).
+type ForkOrigin struct {
+	ParentRunID string    `json:"parent_run_id,omitempty"`
+	ParentData  string    `json:"parent_data"`
+	SimTime     time.Time `json:"sim_time"` // sim time the fork starts from
+	Ticks       int       `json:"ticks"`
+	ForkedAt    time.Time `json:"forked_at"` // wall clock
+}
+
+// NewRunID returns a fresh run ID.
+func NewRunID() string {
+	return fmt.Sprintf("run-%s-%04x", time.Now().UTC().Format("20060102-150405"), rand.Intn(1<<16))
 }
 
 // LoadSimState reads the persisted simulation state if present.
@@ -27,3 +47,15 @@ func LoadSimState(dataPath string) (*SimState, error) {
 	}
 	return state, nil
 }
+
+// SaveSimState writes the simulation state to dataPath/sim_state.json.
+func SaveSimState(dataPath string, state SimState) error {
+	if err := os.MkdirAll(dataPath, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dataPath, "sim_state.json"), data, 0644)
+}
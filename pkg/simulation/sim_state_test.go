@@ -0,0 +1,96 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// cancelingLogger cancels a run once an event from the given tick is logged.
+type cancelingLogger struct {
+	memoryLogger
+	tick   int
+	cancel context.CancelFunc
+}
+
+func (l *cancelingLogger) LogEvent(ev EventLog) error {
+	if ev.Tick == l.tick {
+		l.cancel()
+	}
+	return l.memoryLogger.LogEvent(ev)
+}
+
+func TestADKScheduler_InterruptCheckpointsAndResumes(t *testing.T) {
+	tempDir := t.TempDir()
+	mock := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	start := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	persona := &types.Persona{ID: "agent-1", Name: "Tester", Role: types.RoleExplorer}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := &cancelingLogger{tick: 2, cancel: cancel}
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          logger,
+		SimStep:         time.Hour,
+		StartTime:       start,
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+	if err := sched.AddAgent(ctx, persona); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := sched.RunFor(ctx, 10); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the run to be interrupted, got %v", err)
+	}
+	if err := sched.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// The tick underway when the interrupt arrived still completed.
+	if last := logger.events[len(logger.events)-1]; last.Tick != 2 || last.Response != "ok" {
+		t.Fatalf("expected tick 2 to finish, last event %+v", last)
+	}
+
+	state, err := LoadSimState(tempDir)
+	if err != nil {
+		t.Fatalf("LoadSimState: %v", err)
+	}
+	if state.Ticks != 2 || !state.SimTime.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("unexpected saved state: %+v", state)
+	}
+
+	resumedLogger := &memoryLogger{}
+	resumed := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           mock,
+		Logger:          resumedLogger,
+		SimStep:         time.Hour,
+		StartTime:       state.SimTime,
+		StartTick:       state.Ticks,
+		CheckpointEvery: 1000,
+	})
+	resumed.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	resumed.SetForum(publication.NewForum("F", filepath.Join(tempDir, "forum")))
+	if err := resumed.AddAgent(context.Background(), persona); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if err := resumed.RunTick(context.Background()); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+	ev := resumedLogger.events[0]
+	if ev.Tick != 3 || !ev.SimTime.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("expected resume at tick 3, %s; got tick %d, %s", start.Add(2*time.Hour), ev.Tick, ev.SimTime)
+	}
+}
@@ -0,0 +1,134 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/prompts"
+	"github.com/cpunion/sci-bot/pkg/tools"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+const (
+	// summarizerID books the summarizer's token usage in the budget.
+	summarizerID = "thread-summarizer"
+
+	defaultSummariesPerTick = 2
+	threadSummaryMaxChars   = 1200
+	// summaryMaxFailures stops retrying a thread the summarizer keeps
+	// failing on for the rest of the run.
+	summaryMaxFailures = 3
+	// summaryMaxComments caps the replies handed to one summarization pass
+	// (newest kept).
+	summaryMaxComments = 60
+)
+
+// summarizeThreads refreshes the summaries of long threads whose cached
+// summary is missing or stale, most recently active threads first, using
+// the summarizer model. Agents then get the summary from get_thread_digest
+// instead of being asked to write one.
+func (s *ADKScheduler) summarizeThreads(ctx context.Context) {
+	if s.summarizer == nil || s.forum == nil || s.budget.RunExhausted() {
+		return
+	}
+	limit := s.summariesPerTick
+	if limit <= 0 {
+		limit = defaultSummariesPerTick
+	}
+
+	type candidate struct {
+		post         *types.Publication
+		reason       string
+		lastActivity int64
+	}
+	var candidates []candidate
+	for _, post := range s.forum.AllPosts() {
+		if !post.Visible() || s.summaryFailures[post.ID] >= summaryMaxFailures {
+			continue
+		}
+		reason := tools.StaleSummaryReason(s.forum, post.ID)
+		if reason == "" {
+			continue
+		}
+		last := post.PublishedAt
+		for _, c := range s.forum.GetThreadComments(post.ID) {
+			if c.PublishedAt.After(last) {
+				last = c.PublishedAt
+			}
+		}
+		candidates = append(candidates, candidate{post: post, reason: reason, lastActivity: last.UnixNano()})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].lastActivity == candidates[j].lastActivity {
+			return candidates[i].post.ID < candidates[j].post.ID
+		}
+		return candidates[i].lastActivity > candidates[j].lastActivity
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	for _, c := range candidates {
+		text, usage, err := s.generateText(ctx, s.summarizer, s.summarizerProvider, s.buildThreadSummaryPrompt(c.post))
+		s.recordUsage(summarizerID, usage)
+		s.actionStats["thread_summary"]++
+		summary := truncateRunes(strings.TrimSpace(text), threadSummaryMaxChars)
+		if err == nil && summary == "" {
+			err = fmt.Errorf("empty summary")
+		}
+		if err == nil {
+			_, err = s.forum.SaveThreadSummary(c.post.ID, summary)
+		}
+		if err != nil {
+			s.summaryFailures[c.post.ID]++
+			log.Printf("[Tick %d] Summarizing %s failed: %v", s.ticks, c.post.ID, err)
+			continue
+		}
+		delete(s.summaryFailures, c.post.ID)
+		log.Printf("[Tick %d] Summarized %s %q (%s)", s.ticks, c.post.ID, c.post.Title, c.reason)
+	}
+}
+
+// buildThreadSummaryPrompt asks for a summary of a thread. When the post is
+// unchanged since the last summary, only the replies after it are included
+// with the previous summary.
+func (s *ADKScheduler) buildThreadSummaryPrompt(post *types.Publication) string {
+	text := prompts.For(s.lang.Default())
+	var b strings.Builder
+	fmt.Fprintf(&b, text.ThreadSummaryIntro, post.Title, post.AuthorName, post.Subreddit)
+
+	comments := s.forum.GetThreadComments(post.ID)
+	prev := s.forum.GetThreadSummary(post.ID)
+	if prev != nil && (prev.PostHash == "" || prev.PostHash == s.forum.PostHash(post.ID)) {
+		fmt.Fprintf(&b, text.ThreadSummaryPrevious, strings.TrimSpace(prev.Summary))
+		comments = newerThan(comments, prev.LastCommentAt.UnixNano())
+	} else {
+		fmt.Fprintf(&b, text.ThreadSummaryPost, headRunes(post.Content, 4000))
+	}
+
+	sort.Slice(comments, func(i, j int) bool { return comments[i].PublishedAt.Before(comments[j].PublishedAt) })
+	if len(comments) > summaryMaxComments {
+		comments = comments[len(comments)-summaryMaxComments:]
+	}
+	if len(comments) > 0 {
+		b.WriteString(text.ThreadSummaryComments)
+		for _, c := range comments {
+			fmt.Fprintf(&b, text.ThreadSummaryComment, c.AuthorName, headRunes(c.Content, 600))
+		}
+	}
+	fmt.Fprintf(&b, text.ThreadSummaryOutput, threadSummaryMaxChars)
+	return b.String()
+}
+
+func newerThan(comments []*types.Publication, since int64) []*types.Publication {
+	out := make([]*types.Publication, 0, len(comments))
+	for _, c := range comments {
+		if c.PublishedAt.UnixNano() > since {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ailibmodel "github.com/cpunion/ailib/adk/model"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/tools"
+	"github.com/cpunion/sci-bot/pkg/types"
+	adkmodel "google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestADKScheduler_SummarizesLongThreadsInBackground(t *testing.T) {
+	tempDir := t.TempDir()
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	long := &types.Publication{AuthorID: "agent-1", AuthorName: "A", Title: "Long thread", Content: strings.Repeat("A long argument. ", 150)}
+	short := &types.Publication{AuthorID: "agent-1", AuthorName: "A", Title: "Short thread", Content: "Quick question."}
+	for _, p := range []*types.Publication{long, short} {
+		if err := forum.Post(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	agentModel := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "ok"}}},
+	})
+	summarizer := ailibmodel.NewMockLLM(&adkmodel.LLMResponse{
+		Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "A argues at length."}}},
+	})
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           agentModel,
+		Summarizer:      summarizer,
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+		CheckpointEvery: 1000,
+	})
+	sched.SetJournal(publication.NewJournal("J", filepath.Join(tempDir, "journal")))
+	sched.SetForum(forum)
+	if err := sched.AddAgent(context.Background(), &types.Persona{ID: "agent-1", Name: "A", Role: types.RoleExplorer}); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if reason := tools.StaleSummaryReason(forum, long.ID); reason != "missing_summary_long_thread" {
+		t.Fatalf("reason before = %q", reason)
+	}
+	if err := sched.RunTick(context.Background()); err != nil {
+		t.Fatalf("RunTick: %v", err)
+	}
+
+	if s := forum.GetThreadSummary(long.ID); s == nil || s.Summary != "A argues at length." {
+		t.Fatalf("long thread summary = %+v", s)
+	}
+	if reason := tools.StaleSummaryReason(forum, long.ID); reason != "" {
+		t.Fatalf("reason after = %q", reason)
+	}
+	if s := forum.GetThreadSummary(short.ID); s != nil {
+		t.Fatalf("short threads need no summary: %+v", s)
+	}
+}
@@ -0,0 +1,149 @@
+package simulation
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/cpunion/sci-bot/pkg/reputation"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// traitDrift is the fraction of the remaining distance to a trait's bound
+// that one outcome moves it, so traits change slowly and stay within [0, 1].
+const traitDrift = 0.05
+
+// driftTraits moves traits by a number of new outcomes:
+//   - each accepted paper raises influence;
+//   - each rejection after the first since the last acceptance raises rigor
+//     and lowers creativity by half as much;
+//   - each accepted paper from a collaborative draft raises the
+//     sociability of everyone who took part.
+func driftTraits(t types.Traits, accepted, repeatedRejections, collaborations int) types.Traits {
+	for i := 0; i < accepted; i++ {
+		t.Influence += traitDrift * (1 - t.Influence)
+	}
+	for i := 0; i < repeatedRejections; i++ {
+		t.Rigor += traitDrift * (1 - t.Rigor)
+		t.Creativity -= traitDrift / 2 * t.Creativity
+	}
+	for i := 0; i < collaborations; i++ {
+		t.Sociability += traitDrift * (1 - t.Sociability)
+	}
+	return t
+}
+
+// collaborations counts, per agent, accepted papers written from a
+// collaborative draft with at least two participants: the draft's authors,
+// the consensus requester and supporters, and everyone who commented on the
+// thread the draft came from.
+func (s *ADKScheduler) collaborations() map[string]int {
+	out := make(map[string]int)
+	if s.journal == nil || s.workflow == nil {
+		return out
+	}
+	for _, p := range s.journal.GetApproved() {
+		if p.DraftID == "" {
+			continue
+		}
+		draft := s.workflow.GetDraft(p.DraftID)
+		if draft == nil || draft.Kind != types.DraftCollaborative {
+			continue
+		}
+		seen := make(map[string]bool)
+		add := func(id string) {
+			if id != "" {
+				seen[id] = true
+			}
+		}
+		add(p.AuthorID)
+		for _, id := range draft.Authors {
+			add(id)
+		}
+		threadID := draft.SourcePostID
+		if c := s.workflow.GetConsensus(draft.ConsensusID); c != nil {
+			add(c.RequesterID)
+			for _, id := range c.Supporters {
+				add(id)
+			}
+			if c.PostID != "" {
+				threadID = c.PostID
+			}
+		}
+		if threadID != "" && s.forum != nil {
+			for _, c := range s.forum.GetThreadComments(threadID) {
+				add(c.AuthorID)
+			}
+		}
+		if len(seen) < 2 {
+			continue
+		}
+		for id := range seen {
+			out[id]++
+		}
+	}
+	return out
+}
+
+// evolveTraits applies outcomes an agent had since its traits were last
+// updated and records the change. The first call only records the agent's
+// starting traits. Changed traits are written back to the persona, its
+// action weights and its instruction.
+func (s *ADKScheduler) evolveTraits(ar *agentRunner, act reputation.Activity, collaborations int) {
+	rec, ok := ar.state.GetTraits()
+	if !ok {
+		rec.Outcomes = types.TraitOutcomes{Accepted: act.Accepted, Rejected: act.Rejected, Collaborations: collaborations}
+		rec.Record(s.simTime, ar.persona.Traits(), "initial")
+		ar.state.SetTraits(rec)
+		return
+	}
+
+	accepted := max(act.Accepted-rec.Outcomes.Accepted, 0)
+	rejected := max(act.Rejected-rec.Outcomes.Rejected, 0)
+	collaborated := max(collaborations-rec.Outcomes.Collaborations, 0)
+	if accepted == 0 && rejected == 0 && collaborated == 0 {
+		return
+	}
+
+	// Outcomes within one tick are unordered; count acceptances first, so
+	// only rejections left standing form a streak.
+	streak := rec.Outcomes.RejectionStreak
+	if accepted > 0 {
+		streak = 0
+	}
+	repeated := max(streak+rejected-1, 0) - max(streak-1, 0)
+	rec.Outcomes = types.TraitOutcomes{
+		Accepted:        act.Accepted,
+		Rejected:        act.Rejected,
+		Collaborations:  collaborations,
+		RejectionStreak: streak + rejected,
+	}
+
+	var reasons []string
+	if accepted > 0 {
+		reasons = append(reasons, fmt.Sprintf("accepted=%d", accepted))
+	}
+	if repeated > 0 {
+		reasons = append(reasons, fmt.Sprintf("repeated_rejections=%d", repeated))
+	}
+	if collaborated > 0 {
+		reasons = append(reasons, fmt.Sprintf("collaborations=%d", collaborated))
+	}
+	if len(reasons) == 0 {
+		// A first rejection only starts a streak.
+		ar.state.SetTraits(rec)
+		return
+	}
+
+	traits := driftTraits(rec.Current, accepted, repeated, collaborated)
+	rec.Record(s.simTime, traits, strings.Join(reasons, ", "))
+	ar.state.SetTraits(rec)
+	ar.persona.SetTraits(traits)
+	ar.actionWeights = buildActionWeights(ar.persona, s.scenario.roleActions(ar.persona.Role))
+	if ar.instruction != nil {
+		ar.instruction.setBase(s.templates.Instruction(ar.lang, ar.persona))
+	}
+	log.Printf("[Tick %d] %s traits drifted (%s): creativity %.2f, rigor %.2f, sociability %.2f, influence %.2f",
+		s.ticks, ar.persona.Name, rec.History[len(rec.History)-1].Reason,
+		traits.Creativity, traits.Rigor, traits.Sociability, traits.Influence)
+}
@@ -0,0 +1,117 @@
+package simulation
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestADKScheduler_TraitsDriftWithOutcomes(t *testing.T) {
+	tempDir := t.TempDir()
+	sched := NewADKScheduler(ADKSchedulerConfig{
+		DataPath:        tempDir,
+		Model:           dreamLLM{},
+		Logger:          &memoryLogger{},
+		SimStep:         time.Hour,
+		StartTime:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		CheckpointEvery: 1000,
+	})
+	journal := publication.NewJournal("J", filepath.Join(tempDir, "journal"))
+	forum := publication.NewForum("F", filepath.Join(tempDir, "forum"))
+	sched.SetJournal(journal)
+	sched.SetForum(forum)
+
+	ctx := context.Background()
+	start := types.Traits{Creativity: 0.8, Rigor: 0.4, Sociability: 0.3, Influence: 0.2}
+	for _, id := range []string{"a", "b"} {
+		p := &types.Persona{ID: id, Name: strings.ToUpper(id), Role: types.RoleExplorer}
+		p.SetTraits(start)
+		if err := sched.AddAgent(ctx, p); err != nil {
+			t.Fatalf("AddAgent: %v", err)
+		}
+	}
+	refresh := func() {
+		sched.mu.Lock()
+		sched.refreshReputation()
+		sched.mu.Unlock()
+	}
+	traitsOf := func(id string) types.TraitRecord {
+		t.Helper()
+		rec, ok := sched.runners[id].state.GetTraits()
+		if !ok {
+			t.Fatalf("no traits recorded for %s", id)
+		}
+		return rec
+	}
+	refresh()
+	if rec := traitsOf("a"); rec.Current != start || len(rec.History) != 1 || rec.History[0].Reason != "initial" {
+		t.Fatalf("expected initial snapshot, got %+v", rec)
+	}
+
+	// The first rejection only starts a streak; the second raises rigor.
+	for i, id := range []string{"r1", "r2"} {
+		if err := journal.Submit(&types.Publication{ID: id, AuthorID: "a", Title: "Rejected"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := journal.Reject(id, "editor"); err != nil {
+			t.Fatal(err)
+		}
+		refresh()
+		rec := traitsOf("a")
+		if i == 0 && len(rec.History) != 1 {
+			t.Fatalf("a single rejection should not drift traits: %+v", rec.History)
+		}
+		if i == 1 && (rec.Current.Rigor <= start.Rigor || rec.Current.Creativity >= start.Creativity || rec.History[1].Reason != "repeated_rejections=1") {
+			t.Fatalf("expected rigor up and creativity down after repeated rejections, got %+v", rec.History)
+		}
+	}
+
+	// An accepted collaborative paper raises the author's influence and the
+	// sociability of everyone in the source thread.
+	root := &types.Publication{AuthorID: "b", Title: "Thread", Content: "idea"}
+	if err := forum.Post(root); err != nil {
+		t.Fatal(err)
+	}
+	if err := forum.Comment(root.ID, &types.Publication{AuthorID: "b", Content: "agree"}); err != nil {
+		t.Fatal(err)
+	}
+	draftID := sched.workflow.CreateDraft(&types.Draft{Kind: types.DraftCollaborative, Authors: []string{"a"}, SourcePostID: root.ID})
+	if err := journal.Submit(&types.Publication{ID: "ok", AuthorID: "a", Title: "Joint", DraftID: draftID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Approve("ok", "editor"); err != nil {
+		t.Fatal(err)
+	}
+	refresh()
+	a, b := traitsOf("a"), traitsOf("b")
+	if a.Current.Influence <= start.Influence || a.Current.Sociability <= start.Sociability || a.Outcomes.RejectionStreak != 0 {
+		t.Fatalf("unexpected traits for author: %+v", a)
+	}
+	if b.Current.Sociability <= start.Sociability || b.Current.Influence != start.Influence || b.History[len(b.History)-1].Reason != "collaborations=1" {
+		t.Fatalf("unexpected traits for collaborator: %+v", b)
+	}
+	if got := sched.runners["a"].persona.Traits(); got != a.Current {
+		t.Fatalf("persona not updated: %+v vs %+v", got, a.Current)
+	}
+
+	// Drifted traits survive a restart.
+	if err := sched.runners["a"].state.Save(); err != nil {
+		t.Fatal(err)
+	}
+	resumed := NewADKScheduler(ADKSchedulerConfig{DataPath: tempDir, Model: dreamLLM{}, Logger: &memoryLogger{}})
+	resumed.SetJournal(journal)
+	resumed.SetForum(forum)
+	p := &types.Persona{ID: "a", Name: "A", Role: types.RoleExplorer}
+	p.SetTraits(start)
+	if err := resumed.AddAgent(ctx, p); err != nil {
+		t.Fatalf("AddAgent: %v", err)
+	}
+	if p.Traits() != a.Current {
+		t.Fatalf("expected resumed persona to keep drifted traits, got %+v", p.Traits())
+	}
+}
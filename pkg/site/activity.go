@@ -0,0 +1,176 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// activityDays is how many sim days of per-day activity the index keeps.
+const activityDays = 90
+
+// ActivityIndex is a running tally of community activity per sim day,
+// maintained by the scheduler so the homepage and /api/stats can show
+// movement without reloading the forum and journal.
+type ActivityIndex struct {
+	Version     int       `json:"version"`
+	GeneratedAt time.Time `json:"generated_at"`
+	SimTime     time.Time `json:"sim_time,omitempty"`
+
+	Totals ActivityTotals `json:"totals"`
+	Days   []DayActivity  `json:"days"` // sorted ascending by day
+
+	// LastActive is the sim time of each agent's latest turn.
+	LastActive map[string]time.Time `json:"last_active,omitempty"`
+}
+
+// ActivityTotals are the community-wide counts at the latest record.
+type ActivityTotals struct {
+	ForumThreads    int `json:"forum_threads"`
+	ForumComments   int `json:"forum_comments"`
+	JournalApproved int `json:"journal_approved"`
+	JournalRejected int `json:"journal_rejected"`
+	JournalPending  int `json:"journal_pending"`
+	PendingReviews  int `json:"pending_reviews"`
+	OpenConsensus   int `json:"open_consensus"`
+}
+
+// DayActivity counts what happened during one sim day.
+type DayActivity struct {
+	Day          string   `json:"day"` // YYYY-MM-DD
+	Posts        int      `json:"posts"`
+	Comments     int      `json:"comments"`
+	Papers       int      `json:"papers"`
+	ActiveAgents []string `json:"active_agents,omitempty"`
+}
+
+// NewActivityIndex returns an empty index.
+func NewActivityIndex() *ActivityIndex {
+	return &ActivityIndex{Version: 1, Days: []DayActivity{}, LastActive: make(map[string]time.Time)}
+}
+
+// Record folds a new snapshot taken at simTime into the index. Growth in the
+// totals since the previous record is credited to simTime's day; the first
+// record only sets the baseline. activeIDs are the agents that took a turn.
+func (idx *ActivityIndex) Record(simTime time.Time, totals ActivityTotals, activeIDs []string) {
+	if idx.LastActive == nil {
+		idx.LastActive = make(map[string]time.Time)
+	}
+	day := idx.dayEntry(simTime.Format("2006-01-02"))
+	if !idx.SimTime.IsZero() {
+		day.Posts += growth(idx.Totals.ForumThreads, totals.ForumThreads)
+		day.Comments += growth(idx.Totals.ForumComments, totals.ForumComments)
+		day.Papers += growth(idx.Totals.JournalApproved, totals.JournalApproved)
+	}
+	for _, id := range activeIDs {
+		idx.LastActive[id] = simTime
+		if !containsString(day.ActiveAgents, id) {
+			day.ActiveAgents = append(day.ActiveAgents, id)
+		}
+	}
+	sort.Strings(day.ActiveAgents)
+
+	idx.Totals = totals
+	idx.SimTime = simTime
+	if len(idx.Days) > activityDays {
+		idx.Days = append([]DayActivity(nil), idx.Days[len(idx.Days)-activityDays:]...)
+	}
+}
+
+// Day returns the activity recorded for the sim day containing t.
+func (idx *ActivityIndex) Day(t time.Time) DayActivity {
+	key := t.Format("2006-01-02")
+	for _, d := range idx.Days {
+		if d.Day == key {
+			return d
+		}
+	}
+	return DayActivity{Day: key}
+}
+
+// ActiveSince counts agents whose latest turn is at or after since.
+func (idx *ActivityIndex) ActiveSince(since time.Time) int {
+	n := 0
+	for _, at := range idx.LastActive {
+		if !at.Before(since) {
+			n++
+		}
+	}
+	return n
+}
+
+// dayEntry returns the entry for key, adding it in order if needed.
+func (idx *ActivityIndex) dayEntry(key string) *DayActivity {
+	find := func() *DayActivity {
+		for i := len(idx.Days) - 1; i >= 0; i-- {
+			if idx.Days[i].Day == key {
+				return &idx.Days[i]
+			}
+		}
+		return nil
+	}
+	if d := find(); d != nil {
+		return d
+	}
+	idx.Days = append(idx.Days, DayActivity{Day: key})
+	sort.Slice(idx.Days, func(i, j int) bool { return idx.Days[i].Day < idx.Days[j].Day })
+	return find()
+}
+
+func growth(prev, next int) int {
+	if next > prev {
+		return next - prev
+	}
+	return 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadActivityIndex reads an activity index, returning an empty one if the
+// file does not exist yet.
+func LoadActivityIndex(path string) (*ActivityIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewActivityIndex(), nil
+		}
+		return nil, err
+	}
+	idx := NewActivityIndex()
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, err
+	}
+	if idx.LastActive == nil {
+		idx.LastActive = make(map[string]time.Time)
+	}
+	return idx, nil
+}
+
+// WriteActivityIndex writes the index to path (usually `activity.json` under
+// the data root).
+func WriteActivityIndex(path string, idx *ActivityIndex) error {
+	if idx.Version <= 0 {
+		idx.Version = 1
+	}
+	idx.GeneratedAt = time.Now()
+	if idx.Days == nil {
+		idx.Days = []DayActivity{}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,22 @@
+package site
+
+import "time"
+
+// APIIndex describes a static export of the server API (cmd/export_site).
+// Responses live under the manifest's APIPath, one file per route with
+// ".json" appended, e.g. "api/agents/{id}.json" for /api/agents/{id}.
+type APIIndex struct {
+	Version     int       `json:"version"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Agents  int `json:"agents"`
+	Threads int `json:"threads"`
+	Papers  int `json:"papers"`
+	Groups  int `json:"groups"`
+
+	// The feed is split into pages of FeedPageSize events, newest first:
+	// "feed/1.json" through "feed/{FeedPages}.json". A page's next field
+	// names the page after it ("2", "3", ...).
+	FeedPages    int `json:"feed_pages"`
+	FeedPageSize int `json:"feed_page_size"`
+}
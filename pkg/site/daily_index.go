@@ -6,8 +6,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
-	"strings"
 	"time"
+
+	"github.com/cpunion/sci-bot/pkg/memory"
 )
 
 // DailyNotesIndex is a per-agent index file used by the static frontend.
@@ -20,7 +21,9 @@ type DailyNotesIndex struct {
 	Dates       []string  `json:"dates"` // YYYY-MM-DD, sorted ascending
 }
 
-var dailyFileRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\.jsonl$`)
+// dailyFileRe matches daily notes files, including ones compacted to
+// .jsonl.gz (the frontend falls back to the archive).
+var dailyFileRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\.jsonl(?:\.gz)?$`)
 
 func buildDailyNotesIndex(dailyDir string) (DailyNotesIndex, error) {
 	idx := DailyNotesIndex{Version: 1, GeneratedAt: time.Now(), Dates: []string{}}
@@ -38,15 +41,14 @@ func buildDailyNotesIndex(dailyDir string) (DailyNotesIndex, error) {
 		if e == nil || e.IsDir() {
 			continue
 		}
-		name := e.Name()
-		if !dailyFileRe.MatchString(name) {
+		m := dailyFileRe.FindStringSubmatch(e.Name())
+		if m == nil {
 			continue
 		}
-		dateKey := strings.TrimSuffix(name, ".jsonl")
-		if dateKey == "" {
+		if len(dates) > 0 && dates[len(dates)-1] == m[1] {
 			continue
 		}
-		dates = append(dates, dateKey)
+		dates = append(dates, m[1])
 	}
 	sort.Strings(dates)
 	idx.Dates = dates
@@ -75,7 +77,8 @@ func writeDailyNotesIndex(path string, idx DailyNotesIndex) error {
 }
 
 // WriteDailyNotesIndexes writes `agents/<agent_id>/daily/index.json` for all agents
-// found under `dataPath/agents`.
+// found under `dataPath/agents`, and `notes/index.json` (same format) for
+// agents that have recorded research notes.
 func WriteDailyNotesIndexes(dataPath string) error {
 	agentsDir := filepath.Join(dataPath, "agents")
 	entries, err := os.ReadDir(agentsDir)
@@ -98,6 +101,18 @@ func WriteDailyNotesIndexes(dataPath string) error {
 		if err := writeDailyNotesIndex(filepath.Join(dailyDir, "index.json"), idx); err != nil {
 			return err
 		}
+
+		notesDir := filepath.Join(agentsDir, agentID, memory.NotesDir)
+		if _, err := os.Stat(notesDir); err != nil {
+			continue
+		}
+		idx, err = buildDailyNotesIndex(notesDir)
+		if err != nil {
+			return err
+		}
+		if err := writeDailyNotesIndex(filepath.Join(notesDir, "index.json"), idx); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,108 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// digestsKept is how many weekly digests the index keeps.
+const digestsKept = 52
+
+// DigestIndex lists the weekly digests communicators posted to r/meta, so
+// the static site can show them without reading the whole forum.
+type DigestIndex struct {
+	Version     int       `json:"version"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Digests     []Digest  `json:"digests"` // newest first
+}
+
+// Digest is one weekly digest: the top threads of each subreddit since the
+// previous one.
+type Digest struct {
+	Week       string    `json:"week"` // ISO week covered, e.g. "2026-W07"
+	PostID     string    `json:"post_id"`
+	AuthorID   string    `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	SimTime    time.Time `json:"sim_time"`
+	// CompiledAt is the wall-clock time the digest was compiled; forum
+	// activity after it goes into the next digest.
+	CompiledAt time.Time       `json:"compiled_at"`
+	Sections   []DigestSection `json:"sections"`
+}
+
+// DigestSection holds one subreddit's top threads, best first.
+type DigestSection struct {
+	Subreddit string         `json:"subreddit"`
+	Threads   []DigestThread `json:"threads"`
+}
+
+// DigestThread is a thread as listed in a digest.
+type DigestThread struct {
+	PostID     string `json:"post_id"`
+	Title      string `json:"title"`
+	AuthorName string `json:"author_name"`
+	Score      int    `json:"score"`
+	Replies    int    `json:"replies"`
+	Summary    string `json:"summary,omitempty"`
+}
+
+// NewDigestIndex returns an empty index.
+func NewDigestIndex() *DigestIndex {
+	return &DigestIndex{Version: 1, Digests: []Digest{}}
+}
+
+// Latest returns the newest digest, or nil if there is none.
+func (idx *DigestIndex) Latest() *Digest {
+	if len(idx.Digests) == 0 {
+		return nil
+	}
+	return &idx.Digests[0]
+}
+
+// Add puts d at the front of the index, dropping the oldest digests beyond
+// the ones kept.
+func (idx *DigestIndex) Add(d Digest) {
+	idx.Digests = append([]Digest{d}, idx.Digests...)
+	if len(idx.Digests) > digestsKept {
+		idx.Digests = idx.Digests[:digestsKept]
+	}
+}
+
+// LoadDigestIndex reads a digest index, returning an empty one if the file
+// does not exist yet.
+func LoadDigestIndex(path string) (*DigestIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewDigestIndex(), nil
+		}
+		return nil, err
+	}
+	idx := NewDigestIndex()
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// WriteDigestIndex writes the index to path (usually `digest.json` under the
+// data root).
+func WriteDigestIndex(path string, idx *DigestIndex) error {
+	if idx.Version <= 0 {
+		idx.Version = 1
+	}
+	idx.GeneratedAt = time.Now()
+	if idx.Digests == nil {
+		idx.Digests = []Digest{}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
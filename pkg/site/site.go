@@ -17,6 +17,10 @@ type Manifest struct {
 	ForumPath     string   `json:"forum_path,omitempty"`      // e.g. "forum/forum.json"
 	JournalPath   string   `json:"journal_path,omitempty"`    // e.g. "journal/journal.json"
 	FeedIndexPath string   `json:"feed_index_path,omitempty"` // e.g. "feed/index.json"
+	ActivityPath  string   `json:"activity_path,omitempty"`   // e.g. "activity.json"
+	DigestPath    string   `json:"digest_path,omitempty"`     // e.g. "digest.json"
+	EmergencePath string   `json:"emergence_path,omitempty"`  // e.g. "emergence.json" (see metrics.EmergenceIndex)
+	APIPath       string   `json:"api_path,omitempty"`        // e.g. "api" (exported server responses, see APIIndex)
 	Logs          []string `json:"logs,omitempty"`            // e.g. ["logs.jsonl", "logs-10d-...jsonl"]
 	DefaultLog    string   `json:"default_log,omitempty"`     // best-effort
 
@@ -73,6 +73,8 @@ func roleFocus(p *types.Persona) string {
 		return "Cross-domain synthesis and conceptual bridges."
 	case types.RoleCommunicator:
 		return "Clarity, teaching, and outreach to non-experts."
+	case types.RoleEditor:
+		return "Submission triage, reviewer matching, and editorial scope."
 	default:
 		return ""
 	}
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/agent"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// AgendaToolset lets an agent keep its research agenda: the goals it is
+// pursuing and the projects it is working on.
+type AgendaToolset struct {
+	state *agent.AgentState
+	now   func() time.Time
+}
+
+// NewAgendaToolset creates an agenda toolset for an agent. now is the sim
+// clock changes are dated by.
+func NewAgendaToolset(state *agent.AgentState, now func() time.Time) *AgendaToolset {
+	return &AgendaToolset{state: state, now: now}
+}
+
+// --- View Agenda Tool ---
+
+// ViewAgendaInput is the input.
+type ViewAgendaInput struct {
+	// Include finished and abandoned items
+	All bool `json:"all,omitempty"`
+}
+
+// ViewAgendaOutput is the output.
+type ViewAgendaOutput struct {
+	Goals    []types.Goal    `json:"goals"`
+	Projects []types.Project `json:"projects"`
+}
+
+// ViewAgendaTool creates the view agenda tool.
+func (at *AgendaToolset) ViewAgendaTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ViewAgendaInput) (ViewAgendaOutput, error) {
+		out := ViewAgendaOutput{Goals: []types.Goal{}, Projects: []types.Project{}}
+		agenda, _ := at.state.GetAgenda()
+		for _, g := range agenda.Goals {
+			if input.All || g.Status.Open() {
+				out.Goals = append(out.Goals, g)
+			}
+		}
+		for _, p := range agenda.Projects {
+			if input.All || p.Status.Open() {
+				out.Projects = append(out.Projects, p)
+			}
+		}
+		return out, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name:        "view_agenda",
+		Description: "查看我的研究议程：正在追求的目标和进行中的项目。all=true 同时列出已完成和已放弃的。",
+	}, handler)
+}
+
+// --- Set Goal Tool ---
+
+// SetGoalInput is the input.
+type SetGoalInput struct {
+	// Goal to change; empty sets a new goal
+	GoalID string `json:"goal_id,omitempty"`
+	Text   string `json:"text,omitempty"`
+	// "active", "paused", "done" or "abandoned"
+	Status string `json:"status,omitempty"`
+}
+
+// SetGoalOutput is the output.
+type SetGoalOutput struct {
+	Goal    types.Goal `json:"goal"`
+	Message string     `json:"message"`
+}
+
+// SetGoalTool creates the set goal tool.
+func (at *AgendaToolset) SetGoalTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input SetGoalInput) (SetGoalOutput, error) {
+		var g types.Goal
+		err := at.state.UpdateAgenda(func(a *types.Agenda) error {
+			var err error
+			g, err = a.SetGoal(input.GoalID, input.Text, input.Status, at.now())
+			return err
+		})
+		if err != nil {
+			return SetGoalOutput{}, err
+		}
+		msg := fmt.Sprintf("目标 %s 已更新（%s）", g.ID, g.Status)
+		if input.GoalID == "" {
+			msg = fmt.Sprintf("已设定目标 %s", g.ID)
+		}
+		return SetGoalOutput{Goal: g, Message: msg}, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name: "set_goal",
+		Description: fmt.Sprintf("设定或修改研究目标。不填 goal_id 时新建目标（需要 text）；填 goal_id 时可修改 text 或把 status 设为 active、paused、done、abandoned。同时最多 %d 个未完成的目标。",
+			types.MaxOpenGoals),
+	}, handler)
+}
+
+// --- Update Project Tool ---
+
+// UpdateProjectInput is the input.
+type UpdateProjectInput struct {
+	// Project to change; empty starts a new project
+	ProjectID   string `json:"project_id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// "active", "paused", "done" or "abandoned"
+	Status string `json:"status,omitempty"`
+	// Collaborator agent IDs; replaces the list when given
+	Collaborators []string `json:"collaborators,omitempty"`
+	// Latest progress
+	Progress string `json:"progress,omitempty"`
+}
+
+// UpdateProjectOutput is the output.
+type UpdateProjectOutput struct {
+	Project types.Project `json:"project"`
+	Message string        `json:"message"`
+}
+
+// UpdateProjectTool creates the update project tool.
+func (at *AgendaToolset) UpdateProjectTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input UpdateProjectInput) (UpdateProjectOutput, error) {
+		var p types.Project
+		err := at.state.UpdateAgenda(func(a *types.Agenda) error {
+			var err error
+			p, err = a.UpdateProject(types.ProjectUpdate{
+				ID:            input.ProjectID,
+				Title:         input.Title,
+				Description:   input.Description,
+				Status:        input.Status,
+				Collaborators: input.Collaborators,
+				Progress:      input.Progress,
+			}, at.now())
+			return err
+		})
+		if err != nil {
+			return UpdateProjectOutput{}, err
+		}
+		msg := fmt.Sprintf("项目 %s 已更新（%s）", p.ID, p.Status)
+		if input.ProjectID == "" {
+			msg = fmt.Sprintf("已开始项目 %s「%s」", p.ID, p.Title)
+		}
+		return UpdateProjectOutput{Project: p, Message: msg}, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name: "update_project",
+		Description: fmt.Sprintf("开始或更新研究项目。不填 project_id 时新建项目（需要 title）；填 project_id 时可修改描述、status（active、paused、done、abandoned）、合作者 ID 列表和最新进展 progress。同时最多 %d 个未完成的项目。",
+			types.MaxOpenProjects),
+	}, handler)
+}
+
+// AllTools returns all agenda tools.
+func (at *AgendaToolset) AllTools() ([]tool.Tool, error) {
+	constructors := []func() (tool.Tool, error){
+		at.ViewAgendaTool,
+		at.SetGoalTool,
+		at.UpdateProjectTool,
+	}
+	out := make([]tool.Tool, 0, len(constructors))
+	for _, build := range constructors {
+		t, err := build()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// Attachment limits keep publications small enough to store and to show to
+// other agents.
+const (
+	maxAttachments     = 6
+	maxCaptionLen      = 300
+	maxLatexLen        = 2000
+	maxTableColumns    = 8
+	maxTableRows       = 20
+	maxTableCellLen    = 80
+	maxPlotExperiments = 50
+)
+
+// AttachmentInput is an equation, table or plot to attach to a post or
+// paper.
+type AttachmentInput struct {
+	// Kind is math, table or plot.
+	Kind    string `json:"kind"`
+	Caption string `json:"caption,omitempty"`
+	// Latex is the display equation of a math attachment, without $$.
+	Latex string `json:"latex,omitempty"`
+	// Columns and Rows are the header and cells of a table.
+	Columns []string   `json:"columns,omitempty"`
+	Rows    [][]string `json:"rows,omitempty"`
+	// A plot draws Y against X (parameter or metric names) over the
+	// experiments, by run_experiment ID.
+	Experiments []string `json:"experiments,omitempty"`
+	X           string   `json:"x,omitempty"`
+	Y           string   `json:"y,omitempty"`
+}
+
+// buildAttachments validates attachment inputs, numbers them per kind
+// (eq-1, table-1, fig-1) and renders plots from the experiment store.
+func buildAttachments(inputs []AttachmentInput, store *experiment.Store) ([]types.Attachment, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+	if len(inputs) > maxAttachments {
+		return nil, fmt.Errorf("too many attachments: %d (max %d)", len(inputs), maxAttachments)
+	}
+	counts := make(map[types.AttachmentKind]int)
+	out := make([]types.Attachment, 0, len(inputs))
+	for i, in := range inputs {
+		a := types.Attachment{
+			Kind:    types.AttachmentKind(strings.ToLower(strings.TrimSpace(in.Kind))),
+			Caption: strings.TrimSpace(in.Caption),
+		}
+		if utf8.RuneCountInString(a.Caption) > maxCaptionLen {
+			return nil, fmt.Errorf("attachment %d: caption longer than %d characters", i+1, maxCaptionLen)
+		}
+		var prefix string
+		switch a.Kind {
+		case types.AttachmentMath:
+			prefix = "eq"
+			a.Latex = strings.TrimSpace(in.Latex)
+			a.Latex = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(a.Latex, "$$"), "$$"))
+			if a.Latex == "" {
+				return nil, fmt.Errorf("attachment %d: math needs latex", i+1)
+			}
+			if utf8.RuneCountInString(a.Latex) > maxLatexLen {
+				return nil, fmt.Errorf("attachment %d: latex longer than %d characters", i+1, maxLatexLen)
+			}
+		case types.AttachmentTable:
+			prefix = "table"
+			if err := checkTable(in.Columns, in.Rows); err != nil {
+				return nil, fmt.Errorf("attachment %d: %w", i+1, err)
+			}
+			a.Columns, a.Rows = in.Columns, in.Rows
+		case types.AttachmentPlot:
+			prefix = "fig"
+			svg, err := plotAttachment(in, store)
+			if err != nil {
+				return nil, fmt.Errorf("attachment %d: %w", i+1, err)
+			}
+			a.Experiments, a.X, a.Y, a.SVG = in.Experiments, strings.TrimSpace(in.X), strings.TrimSpace(in.Y), svg
+		default:
+			return nil, fmt.Errorf("attachment %d: unknown kind %q (math, table or plot)", i+1, in.Kind)
+		}
+		counts[a.Kind]++
+		a.ID = fmt.Sprintf("%s-%d", prefix, counts[a.Kind])
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func checkTable(columns []string, rows [][]string) error {
+	if len(columns) == 0 || len(columns) > maxTableColumns {
+		return fmt.Errorf("table needs 1-%d columns", maxTableColumns)
+	}
+	if len(rows) == 0 || len(rows) > maxTableRows {
+		return fmt.Errorf("table needs 1-%d rows", maxTableRows)
+	}
+	for _, c := range columns {
+		if utf8.RuneCountInString(c) > maxTableCellLen {
+			return fmt.Errorf("table column longer than %d characters", maxTableCellLen)
+		}
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return fmt.Errorf("table row %d has %d cells, want %d", i+1, len(row), len(columns))
+		}
+		for _, cell := range row {
+			if utf8.RuneCountInString(cell) > maxTableCellLen {
+				return fmt.Errorf("table cell longer than %d characters", maxTableCellLen)
+			}
+		}
+	}
+	return nil
+}
+
+func plotAttachment(in AttachmentInput, store *experiment.Store) (string, error) {
+	if store == nil {
+		return "", fmt.Errorf("experiments are not enabled in this run")
+	}
+	x, y := strings.TrimSpace(in.X), strings.TrimSpace(in.Y)
+	if x == "" || y == "" {
+		return "", fmt.Errorf("plot needs x and y")
+	}
+	if len(in.Experiments) == 0 || len(in.Experiments) > maxPlotExperiments {
+		return "", fmt.Errorf("plot needs 1-%d experiments", maxPlotExperiments)
+	}
+	exps := make([]experiment.Experiment, 0, len(in.Experiments))
+	for _, id := range in.Experiments {
+		exp, err := store.Get(strings.TrimSpace(id))
+		if err != nil {
+			return "", err
+		}
+		exps = append(exps, exp)
+	}
+	return experiment.PlotSVG(exps, x, y)
+}
+
+// attachmentTexts renders attachments as Markdown for agents reading a
+// publication; plots are described rather than shown.
+func attachmentTexts(list []types.Attachment) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, a := range list {
+		var body string
+		switch a.Kind {
+		case types.AttachmentMath:
+			body = "$$" + a.Latex + "$$"
+		case types.AttachmentTable:
+			var b strings.Builder
+			b.WriteString("| " + strings.Join(a.Columns, " | ") + " |\n|")
+			b.WriteString(strings.Repeat(" --- |", len(a.Columns)))
+			for _, row := range a.Rows {
+				b.WriteString("\n| " + strings.Join(row, " | ") + " |")
+			}
+			body = b.String()
+		case types.AttachmentPlot:
+			body = fmt.Sprintf("plot of %s against %s over experiments %s", a.Y, a.X, strings.Join(a.Experiments, ", "))
+		}
+		text := a.ID + ": " + body
+		if a.Caption != "" {
+			text += "\n" + a.Caption
+		}
+		out = append(out, text)
+	}
+	return out
+}
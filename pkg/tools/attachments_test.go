@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+func TestBuildAttachments(t *testing.T) {
+	store := experiment.NewStore(t.TempDir())
+	var ids []string
+	for _, length := range []float64{1, 4} {
+		exp := &experiment.Experiment{Hypothesis: "T grows as sqrt(L)", Template: "pendulum", Params: map[string]float64{"length": length}}
+		if err := store.Run(exp); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+		ids = append(ids, exp.ID)
+	}
+
+	got, err := buildAttachments([]AttachmentInput{
+		{Kind: "math", Latex: "$$T = 2\\pi\\sqrt{L/g}$$", Caption: "Small-angle period"},
+		{Kind: "table", Columns: []string{"L", "T"}, Rows: [][]string{{"1", "2.006"}, {"4", "4.012"}}},
+		{Kind: "plot", Experiments: ids, X: "length", Y: "period"},
+		{Kind: "Math", Latex: "g = 9.81"},
+	}, store)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	var gotIDs []string
+	for _, a := range got {
+		gotIDs = append(gotIDs, a.ID)
+	}
+	if strings.Join(gotIDs, ",") != "eq-1,table-1,fig-1,eq-2" {
+		t.Fatalf("unexpected attachment IDs: %v", gotIDs)
+	}
+	if got[0].Latex != "T = 2\\pi\\sqrt{L/g}" {
+		t.Fatalf("math delimiters should be stripped: %q", got[0].Latex)
+	}
+	if got[2].Kind != types.AttachmentPlot || !strings.HasPrefix(got[2].SVG, "<svg") {
+		t.Fatalf("plot should be rendered: %+v", got[2])
+	}
+	texts := attachmentTexts(got)
+	if !strings.Contains(texts[1], "| L | T |\n| --- | --- |\n| 1 | 2.006 |") || !strings.Contains(texts[2], "period against length") {
+		t.Fatalf("unexpected attachment texts: %q", texts)
+	}
+
+	for name, input := range map[string]AttachmentInput{
+		"unknown kind":  {Kind: "video"},
+		"empty math":    {Kind: "math"},
+		"ragged table":  {Kind: "table", Columns: []string{"a", "b"}, Rows: [][]string{{"1"}}},
+		"missing exp":   {Kind: "plot", Experiments: []string{"exp-missing"}, X: "length", Y: "period"},
+		"missing value": {Kind: "plot", Experiments: ids, X: "length", Y: "range"},
+	} {
+		if _, err := buildAttachments([]AttachmentInput{input}, store); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := buildAttachments([]AttachmentInput{{Kind: "plot", Experiments: ids, X: "length", Y: "period"}}, nil); err == nil {
+		t.Errorf("plot without an experiment store: expected an error")
+	}
+}
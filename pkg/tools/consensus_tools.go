@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// --- Support / Oppose Consensus Tools ---
+
+type ConsensusVoteInput struct {
+	ConsensusID string `json:"consensus_id"`
+}
+
+type ConsensusVoteOutput struct {
+	ConsensusID string `json:"consensus_id"`
+	Status      string `json:"status"`
+	Supporters  int    `json:"supporters"`
+	Opponents   int    `json:"opponents"`
+	Message     string `json:"message"`
+}
+
+func (pt *PublicationToolset) SupportConsensusTool() (tool.Tool, error) {
+	return functiontool.New(functiontool.Config{
+		Name:        "support_consensus",
+		Description: "支持一条共识请求（consensus_id 见发起评论或 assess_consensus）。支持者足够多时共识达成，发起人会被提示据此撰写协作草案。再次投票可改变立场。",
+	}, pt.consensusVoteHandler(true))
+}
+
+func (pt *PublicationToolset) OpposeConsensusTool() (tool.Tool, error) {
+	return functiontool.New(functiontool.Config{
+		Name:        "oppose_consensus",
+		Description: "反对一条共识请求（认为讨论尚未形成共识）。反对票会提高达成门槛；再次投票可改变立场。",
+	}, pt.consensusVoteHandler(false))
+}
+
+func (pt *PublicationToolset) consensusVoteHandler(support bool) func(tool.Context, ConsensusVoteInput) (ConsensusVoteOutput, error) {
+	return func(ctx tool.Context, input ConsensusVoteInput) (ConsensusVoteOutput, error) {
+		if pt.workflow == nil {
+			return ConsensusVoteOutput{}, fmt.Errorf("workflow not available")
+		}
+		id := strings.TrimSpace(input.ConsensusID)
+		if id == "" {
+			return ConsensusVoteOutput{}, fmt.Errorf("missing consensus_id")
+		}
+		req, err := pt.workflow.VoteConsensus(id, personaID(pt.persona), support)
+		if err != nil {
+			return ConsensusVoteOutput{}, err
+		}
+		if err := pt.workflow.Save(); err != nil {
+			return ConsensusVoteOutput{}, err
+		}
+
+		msg := "Vote recorded"
+		if req.Status == types.ConsensusAchieved {
+			msg = "Vote recorded; consensus achieved"
+		}
+		return ConsensusVoteOutput{
+			ConsensusID: id,
+			Status:      string(req.Status),
+			Supporters:  len(req.Supporters),
+			Opponents:   len(req.Opponents),
+			Message:     msg,
+		}, nil
+	}
+}
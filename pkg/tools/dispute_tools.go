@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// --- Challenge Claim Tool ---
+
+// ChallengeClaimInput is the input.
+type ChallengeClaimInput struct {
+	// Published paper or forum post ID
+	TargetID string `json:"target_id"`
+	// The claim being disputed
+	Claim            string `json:"claim"`
+	CounterArguments string `json:"counter_arguments"`
+	// Experiment, paper or post IDs, or outside sources
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// ChallengeClaimOutput is the output.
+type ChallengeClaimOutput struct {
+	DisputeID string `json:"dispute_id"`
+	// Agents disputing the target so far
+	Challengers int    `json:"challengers"`
+	Disputed    bool   `json:"disputed,omitempty"`
+	Message     string `json:"message"`
+}
+
+// ChallengeClaimTool creates the challenge claim tool.
+func (pt *PublicationToolset) ChallengeClaimTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ChallengeClaimInput) (ChallengeClaimOutput, error) {
+		if pt.workflow == nil {
+			return ChallengeClaimOutput{}, fmt.Errorf("workflow not available")
+		}
+		targetID := strings.TrimSpace(input.TargetID)
+		var target *types.Publication
+		if pt.journal != nil {
+			target = pt.journal.Get(targetID)
+		}
+		if target == nil && pt.forum != nil {
+			target = pt.forum.Get(targetID)
+		}
+		if target == nil {
+			return ChallengeClaimOutput{}, fmt.Errorf("no published paper or post: %s", targetID)
+		}
+		if strings.TrimSpace(input.Claim) == "" {
+			return ChallengeClaimOutput{}, fmt.Errorf("missing claim")
+		}
+
+		now := time.Now()
+		dispute := &types.Dispute{
+			TargetID:         target.ID,
+			Channel:          target.Channel,
+			TargetAuthorID:   target.AuthorID,
+			ChallengerID:     personaID(pt.persona),
+			ChallengerName:   personaName(pt.persona),
+			Claim:            strings.TrimSpace(input.Claim),
+			CounterArguments: strings.TrimSpace(input.CounterArguments),
+			Evidence:         trimmedStrings(input.Evidence),
+			CreatedAt:        now,
+		}
+		challengers, err := pt.workflow.AddDispute(dispute)
+		if err != nil {
+			return ChallengeClaimOutput{}, err
+		}
+		if err := pt.workflow.Save(); err != nil {
+			return ChallengeClaimOutput{}, err
+		}
+
+		out := ChallengeClaimOutput{
+			DisputeID:   dispute.ID,
+			Challengers: challengers,
+			Message:     fmt.Sprintf("已对 %s 提出正式异议（共 %d 人提出异议）", target.ID, challengers),
+		}
+		if target.Channel == types.ChannelJournal && challengers >= types.DisputeThreshold {
+			marked, err := pt.journal.MarkDisputed(target.ID, dispute.ChallengerID, now)
+			if err != nil {
+				return ChallengeClaimOutput{}, err
+			}
+			out.Disputed = true
+			if marked {
+				out.Message += "；论文已被标记为 disputed"
+			}
+		}
+		return out, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "challenge_claim",
+		Description: fmt.Sprintf("对已发表的论文或论坛帖子提出正式异议：claim 为有争议的论断，counter_arguments 为反驳理由，evidence 列出实验、论文、帖子 ID 或外部来源。每人对同一目标只能提一次，不能质疑自己的作品；%d 人以上提出异议的期刊论文会被标记为 disputed。",
+			types.DisputeThreshold),
+	}, handler)
+}
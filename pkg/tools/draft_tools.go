@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// SetAgentLookup lets invite_coauthor check that the invitee exists.
+func (pt *PublicationToolset) SetAgentLookup(fn func(id string) *types.Persona) {
+	pt.lookupAgent = fn
+}
+
+// --- Update Draft Tool ---
+
+type UpdateDraftInput struct {
+	DraftID string `json:"draft_id"`
+	Mode    string `json:"mode,omitempty"`    // append (default) | replace
+	Section string `json:"section,omitempty"` // Markdown heading, e.g. "Method"
+	Text    string `json:"text"`
+}
+
+type UpdateDraftOutput struct {
+	DraftID       string         `json:"draft_id"`
+	Message       string         `json:"message"`
+	Contributions map[string]int `json:"contributions"` // author ID -> characters written
+}
+
+func (pt *PublicationToolset) UpdateDraftTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input UpdateDraftInput) (UpdateDraftOutput, error) {
+		if pt.workflow == nil {
+			return UpdateDraftOutput{}, fmt.Errorf("workflow not available")
+		}
+		mode := strings.ToLower(strings.TrimSpace(input.Mode))
+		if mode == "" {
+			mode = publication.DraftAppend
+		}
+		draftID := strings.TrimSpace(input.DraftID)
+		draft, err := pt.workflow.EditDraft(draftID, personaID(pt.persona), mode, input.Section, input.Text)
+		if err != nil {
+			return UpdateDraftOutput{}, err
+		}
+		if err := pt.workflow.Save(); err != nil {
+			return UpdateDraftOutput{}, err
+		}
+		return UpdateDraftOutput{
+			DraftID:       draftID,
+			Message:       fmt.Sprintf("Draft updated (%s)", mode),
+			Contributions: draft.CharsByAuthor(),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "update_draft",
+		Description: "修改你参与署名的草案：mode=append（默认）在章节末尾追加，mode=replace 替换章节内容；section 为 Markdown 章节标题（如 Method），不存在时新建，留空则作用于全文。每次修改都会记在你的名下。",
+	}, handler)
+}
+
+// --- Invite Coauthor Tool ---
+
+type InviteCoauthorInput struct {
+	DraftID string `json:"draft_id"`
+	AgentID string `json:"agent_id"`
+}
+
+type InviteCoauthorOutput struct {
+	DraftID string   `json:"draft_id"`
+	Authors []string `json:"authors"`
+	Message string   `json:"message"`
+}
+
+func (pt *PublicationToolset) InviteCoauthorTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input InviteCoauthorInput) (InviteCoauthorOutput, error) {
+		if pt.workflow == nil {
+			return InviteCoauthorOutput{}, fmt.Errorf("workflow not available")
+		}
+		draftID := strings.TrimSpace(input.DraftID)
+		invitee := strings.TrimSpace(input.AgentID)
+		if invitee == personaID(pt.persona) {
+			return InviteCoauthorOutput{}, fmt.Errorf("cannot invite yourself")
+		}
+		if pt.lookupAgent != nil && pt.lookupAgent(invitee) == nil {
+			return InviteCoauthorOutput{}, fmt.Errorf("agent not found: %s", invitee)
+		}
+		if err := pt.workflow.InviteCoauthor(draftID, personaID(pt.persona), invitee); err != nil {
+			return InviteCoauthorOutput{}, err
+		}
+		if err := pt.workflow.Save(); err != nil {
+			return InviteCoauthorOutput{}, err
+		}
+		return InviteCoauthorOutput{
+			DraftID: draftID,
+			Authors: pt.workflow.GetDraft(draftID).Authors,
+			Message: fmt.Sprintf("%s is now a coauthor", invitee),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "invite_coauthor",
+		Description: "邀请另一位 agent（agent_id）成为你草案的合著者，之后对方可用 update_draft 修改并出现在 list_my_drafts 中。",
+	}, handler)
+}
+
+// --- List My Drafts Tool ---
+
+type ListMyDraftsInput struct {
+	Limit int `json:"limit,omitempty"`
+}
+
+type DraftSummary struct {
+	DraftID       string         `json:"draft_id"`
+	Title         string         `json:"title"`
+	Kind          string         `json:"kind"`
+	Status        string         `json:"status,omitempty"`
+	Authors       []string       `json:"authors"`
+	Sections      []string       `json:"sections,omitempty"`
+	Contributions map[string]int `json:"contributions"` // author ID -> characters written
+	Edits         int            `json:"edits"`
+	UpdatedAt     string         `json:"updated_at"`
+}
+
+type ListMyDraftsOutput struct {
+	Drafts []DraftSummary `json:"drafts"`
+}
+
+func (pt *PublicationToolset) ListMyDraftsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ListMyDraftsInput) (ListMyDraftsOutput, error) {
+		if pt.workflow == nil {
+			return ListMyDraftsOutput{}, fmt.Errorf("workflow not available")
+		}
+		limit := input.Limit
+		if limit <= 0 || limit > 20 {
+			limit = 10
+		}
+		drafts := pt.workflow.DraftsByAuthor(personaID(pt.persona))
+		if limit > len(drafts) {
+			limit = len(drafts)
+		}
+		out := make([]DraftSummary, 0, limit)
+		for _, d := range drafts[:limit] {
+			out = append(out, DraftSummary{
+				DraftID:       d.ID,
+				Title:         d.Title,
+				Kind:          string(d.Kind),
+				Status:        string(d.Status),
+				Authors:       d.Authors,
+				Sections:      publication.DraftSections(d.Content),
+				Contributions: d.CharsByAuthor(),
+				Edits:         len(d.Contributions),
+				UpdatedAt:     d.UpdatedAt.Format(time.RFC3339),
+			})
+		}
+		return ListMyDraftsOutput{Drafts: out}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "list_my_drafts",
+		Description: "列出你署名（创建或受邀合著）的草案：章节、合著者及每人贡献的字数，按最近修改排序。",
+	}, handler)
+}
@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// EditorToolset lets editor agents triage journal submissions: assign
+// reviewers, desk-reject out-of-scope papers and remind late reviewers.
+type EditorToolset struct {
+	workflow *publication.Workflow
+	journal  *publication.Journal
+	persona  *types.Persona
+
+	// reviewers lists the reviewer agents currently in the simulation.
+	reviewers func() []*types.Persona
+	// now returns the current sim time.
+	now func() time.Time
+	// nagAfter is how long an assigned review may be missing before the
+	// editor should remind the reviewer (0 disables).
+	nagAfter time.Duration
+}
+
+// NewEditorToolset creates an editor toolset. now supplies sim time for
+// assignments and reminders.
+func NewEditorToolset(workflow *publication.Workflow, journal *publication.Journal, persona *types.Persona, reviewers func() []*types.Persona, now func() time.Time, nagAfter time.Duration) *EditorToolset {
+	return &EditorToolset{
+		workflow:  workflow,
+		journal:   journal,
+		persona:   persona,
+		reviewers: reviewers,
+		now:       now,
+		nagAfter:  nagAfter,
+	}
+}
+
+func (et *EditorToolset) check() error {
+	if et.persona == nil || et.persona.Role != types.RoleEditor {
+		return fmt.Errorf("editor role required")
+	}
+	if et.workflow == nil {
+		return fmt.Errorf("workflow not available")
+	}
+	return nil
+}
+
+func (et *EditorToolset) reviewerPersonas() []*types.Persona {
+	if et.reviewers == nil {
+		return nil
+	}
+	return et.reviewers()
+}
+
+// --- View Editor Queue Tool ---
+
+// ViewEditorQueueInput is the input.
+type ViewEditorQueueInput struct {
+	Limit int `json:"limit,omitempty"`
+}
+
+// EditorQueueItem is an untriaged submission with suggested reviewers.
+type EditorQueueItem struct {
+	SubmissionID string                          `json:"submission_id"`
+	Title        string                          `json:"title"`
+	AuthorName   string                          `json:"author_name"`
+	Journal      string                          `json:"journal,omitempty"`
+	Domains      []string                        `json:"journal_domains,omitempty"`
+	Abstract     string                          `json:"abstract,omitempty"`
+	Suggested    []publication.ReviewerCandidate `json:"suggested_reviewers"`
+}
+
+// ViewEditorQueueOutput is the output.
+type ViewEditorQueueOutput struct {
+	Submissions []EditorQueueItem           `json:"submissions"`
+	Total       int                         `json:"total"`
+	Overdue     []publication.OverdueReview `json:"overdue_reviews,omitempty"`
+}
+
+// ViewEditorQueueTool lists untriaged submissions and overdue reviews.
+func (et *EditorToolset) ViewEditorQueueTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ViewEditorQueueInput) (ViewEditorQueueOutput, error) {
+		if err := et.check(); err != nil {
+			return ViewEditorQueueOutput{}, err
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 5
+		}
+
+		queue := et.workflow.EditorQueue()
+		out := ViewEditorQueueOutput{Total: len(queue)}
+		if len(queue) > limit {
+			queue = queue[:limit]
+		}
+		reviewers := et.reviewerPersonas()
+		for _, sub := range queue {
+			item := EditorQueueItem{
+				SubmissionID: sub.ID,
+				Title:        sub.Title,
+				AuthorName:   sub.AuthorName,
+				Abstract:     truncateString(firstNonEmpty(sub.Abstract, sub.Content), 300),
+				Suggested:    et.workflow.SuggestReviewers(sub, reviewers, 3),
+			}
+			if et.journal != nil {
+				if info, ok := et.journal.JournalInfo(sub.JournalID); ok {
+					item.Journal = info.Name
+					item.Domains = info.Domains
+				}
+			}
+			out.Submissions = append(out.Submissions, item)
+		}
+		out.Overdue = et.workflow.OverdueReviews(et.now(), et.nagAfter)
+		return out, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "view_editor_queue",
+		Description: "编辑专用：查看尚未分配审稿人的投稿（含所投期刊的收稿领域与按领域匹配、当前负担排序的推荐审稿人），以及超期未交的审稿。",
+	}, handler)
+}
+
+// --- Assign Reviewers Tool ---
+
+// AssignReviewersInput is the input.
+type AssignReviewersInput struct {
+	SubmissionID string   `json:"submission_id"`
+	ReviewerIDs  []string `json:"reviewer_ids"`
+}
+
+// AssignReviewersOutput is the output.
+type AssignReviewersOutput struct {
+	Assigned []string `json:"assigned"`
+	Message  string   `json:"message"`
+}
+
+// AssignReviewersTool assigns reviewer agents to a submission.
+func (et *EditorToolset) AssignReviewersTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input AssignReviewersInput) (AssignReviewersOutput, error) {
+		if err := et.check(); err != nil {
+			return AssignReviewersOutput{}, err
+		}
+		known := make(map[string]bool)
+		for _, p := range et.reviewerPersonas() {
+			known[p.ID] = true
+		}
+		ids := make([]string, 0, len(input.ReviewerIDs))
+		for _, id := range input.ReviewerIDs {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			if !known[id] {
+				return AssignReviewersOutput{}, fmt.Errorf("not a reviewer: %s", id)
+			}
+			ids = append(ids, id)
+		}
+
+		subID := strings.TrimSpace(input.SubmissionID)
+		if err := et.workflow.AssignReviewers(subID, ids, et.persona.ID, et.now()); err != nil {
+			return AssignReviewersOutput{}, err
+		}
+		if err := et.workflow.Save(); err != nil {
+			return AssignReviewersOutput{}, err
+		}
+		sub := et.workflow.GetSubmission(subID)
+		return AssignReviewersOutput{
+			Assigned: sub.AssignedReviewers,
+			Message:  fmt.Sprintf("已为 %s 分配 %d 位审稿人", subID, len(sub.AssignedReviewers)),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "assign_reviewers",
+		Description: "编辑专用：为投稿指定审稿人（reviewer_ids，不能是作者），会替换之前的分配；优先选领域匹配且负担轻的审稿人。",
+	}, handler)
+}
+
+// --- Desk Reject Tool ---
+
+// DeskRejectInput is the input.
+type DeskRejectInput struct {
+	SubmissionID string `json:"submission_id"`
+	Reason       string `json:"reason"`
+}
+
+// DeskRejectOutput is the output.
+type DeskRejectOutput struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// DeskRejectTool rejects a submission without sending it to review.
+func (et *EditorToolset) DeskRejectTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input DeskRejectInput) (DeskRejectOutput, error) {
+		if err := et.check(); err != nil {
+			return DeskRejectOutput{}, err
+		}
+		subID := strings.TrimSpace(input.SubmissionID)
+		if err := et.workflow.DeskReject(et.journal, subID, et.persona.ID, input.Reason, et.now()); err != nil {
+			return DeskRejectOutput{}, err
+		}
+		if err := et.workflow.Save(); err != nil {
+			return DeskRejectOutput{}, err
+		}
+		return DeskRejectOutput{
+			Status:  string(types.SubmissionRejected),
+			Message: fmt.Sprintf("%s 已直接拒稿（未送审）", subID),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "desk_reject",
+		Description: "编辑专用：对明显超出期刊收稿范围或不成文的投稿直接拒稿（不送审），必须写明理由；不要因观点新奇或有争议而拒稿。",
+	}, handler)
+}
+
+// --- Remind Reviewer Tool ---
+
+// RemindReviewerInput is the input.
+type RemindReviewerInput struct {
+	SubmissionID string `json:"submission_id"`
+	ReviewerID   string `json:"reviewer_id"`
+	Note         string `json:"note,omitempty"`
+}
+
+// RemindReviewerOutput is the output.
+type RemindReviewerOutput struct {
+	Message string `json:"message"`
+}
+
+// RemindReviewerTool nags an assigned reviewer whose review is late.
+func (et *EditorToolset) RemindReviewerTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RemindReviewerInput) (RemindReviewerOutput, error) {
+		if err := et.check(); err != nil {
+			return RemindReviewerOutput{}, err
+		}
+		subID := strings.TrimSpace(input.SubmissionID)
+		reviewerID := strings.TrimSpace(input.ReviewerID)
+		if err := et.workflow.RemindReviewer(subID, reviewerID, et.persona.ID, input.Note, et.now()); err != nil {
+			return RemindReviewerOutput{}, err
+		}
+		if err := et.workflow.Save(); err != nil {
+			return RemindReviewerOutput{}, err
+		}
+		return RemindReviewerOutput{
+			Message: fmt.Sprintf("已提醒 %s 尽快完成 %s 的审稿", reviewerID, subID),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "remind_reviewer",
+		Description: "编辑专用：催促超期未交审稿意见的审稿人（可附 note），提醒会出现在对方的审稿任务中。",
+	}, handler)
+}
+
+// AllTools returns the editor tools; agents without the editor role get none.
+func (et *EditorToolset) AllTools() ([]tool.Tool, error) {
+	if et.persona == nil || et.persona.Role != types.RoleEditor {
+		return nil, nil
+	}
+	queueTool, err := et.ViewEditorQueueTool()
+	if err != nil {
+		return nil, err
+	}
+	assignTool, err := et.AssignReviewersTool()
+	if err != nil {
+		return nil, err
+	}
+	deskRejectTool, err := et.DeskRejectTool()
+	if err != nil {
+		return nil, err
+	}
+	remindTool, err := et.RemindReviewerTool()
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{queueTool, assignTool, deskRejectTool, remindTool}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/knowledge"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// ExperimentToolset lets agents test hypotheses with virtual experiments.
+type ExperimentToolset struct {
+	store    *experiment.Store
+	theories *knowledge.TheoryRepository
+	persona  *types.Persona
+}
+
+// NewExperimentToolset creates an experiment toolset. theories may be nil, in
+// which case experiments cannot be linked to theory hypotheses.
+func NewExperimentToolset(store *experiment.Store, theories *knowledge.TheoryRepository, persona *types.Persona) *ExperimentToolset {
+	return &ExperimentToolset{
+		store:    store,
+		theories: theories,
+		persona:  persona,
+	}
+}
+
+// --- Run Experiment Tool ---
+
+// RunExperimentInput is the input.
+type RunExperimentInput struct {
+	// The hypothesis being tested, in one sentence
+	Hypothesis string `json:"hypothesis"`
+	// Template name (projectile, pendulum, random_walk, monte_carlo_pi, logistic_map, decay)
+	Template string `json:"template"`
+	// Template parameters; omitted ones use defaults
+	Params map[string]float64 `json:"params,omitempty"`
+	// Falsifiable prediction checked against the measured metric
+	Prediction *experiment.Prediction `json:"prediction,omitempty"`
+	// Optional link to a hypothesis of a formal theory
+	TheoryID     string `json:"theory_id,omitempty"`
+	HypothesisID string `json:"hypothesis_id,omitempty"`
+	// Random seed to reproduce an earlier run (optional)
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// RunExperimentOutput is the output.
+type RunExperimentOutput struct {
+	ExperimentID string             `json:"experiment_id"`
+	Params       map[string]float64 `json:"params"`
+	Seed         int64              `json:"seed"`
+	Metrics      map[string]float64 `json:"metrics"`
+	Outcome      string             `json:"outcome"`
+	Message      string             `json:"message"`
+}
+
+// RunExperimentTool creates the run experiment tool.
+func (et *ExperimentToolset) RunExperimentTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RunExperimentInput) (RunExperimentOutput, error) {
+		theoryID := strings.TrimSpace(input.TheoryID)
+		hypothesisID := strings.TrimSpace(input.HypothesisID)
+		if theoryID != "" {
+			if et.theories == nil {
+				return RunExperimentOutput{}, fmt.Errorf("theories not available")
+			}
+			theory, err := et.theories.Snapshot(theoryID)
+			if err != nil {
+				return RunExperimentOutput{}, err
+			}
+			if hypothesisID != "" && !hasHypothesis(theory, hypothesisID) {
+				return RunExperimentOutput{}, fmt.Errorf("hypothesis %s not found in theory %s", hypothesisID, theoryID)
+			}
+		} else if hypothesisID != "" {
+			return RunExperimentOutput{}, fmt.Errorf("hypothesis_id requires theory_id")
+		}
+
+		exp := &experiment.Experiment{
+			AgentID:      personaID(et.persona),
+			AgentName:    personaName(et.persona),
+			Hypothesis:   strings.TrimSpace(input.Hypothesis),
+			TheoryID:     theoryID,
+			HypothesisID: hypothesisID,
+			Template:     strings.TrimSpace(input.Template),
+			Params:       input.Params,
+			Seed:         input.Seed,
+			Prediction:   input.Prediction,
+			CreatedAt:    time.Now(),
+		}
+		if err := et.store.Run(exp); err != nil {
+			return RunExperimentOutput{}, err
+		}
+
+		msg := "实验完成；未给出预测，结果不构成检验"
+		switch exp.Outcome {
+		case experiment.OutcomeSupported:
+			msg = "实验结果支持预测"
+		case experiment.OutcomeRefuted:
+			msg = "实验结果否定了预测"
+		}
+		return RunExperimentOutput{
+			ExperimentID: exp.ID,
+			Params:       exp.Params,
+			Seed:         exp.Seed,
+			Metrics:      exp.Metrics,
+			Outcome:      string(exp.Outcome),
+			Message:      msg + "，可在 submit_paper 的 experiments 中引用 " + exp.ID,
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "run_experiment",
+		Description: "运行虚拟实验检验假说。模板：projectile(v0, angle_deg, g, drag)→range/max_height/flight_time；" +
+			"pendulum(length, amplitude_deg, g, damping)→period/small_angle_period/period_ratio；" +
+			"random_walk(steps, walkers, dims)→msd/msd_per_step；monte_carlo_pi(samples)→pi_estimate/abs_error；" +
+			"logistic_map(r, x0, iterations)→lyapunov/period/final_x；decay(n0, half_life, duration)→remaining_fraction/estimated_half_life。" +
+			"prediction 为可证伪预测 {metric, op(<,<=,>,>=,≈), value, tolerance}，结果为 supported/refuted/inconclusive。",
+	}, handler)
+}
+
+// AllTools returns all experiment tools.
+func (et *ExperimentToolset) AllTools() ([]tool.Tool, error) {
+	runTool, err := et.RunExperimentTool()
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{runTool}, nil
+}
+
+func hasHypothesis(theory types.Theory, id string) bool {
+	for _, h := range theory.Hypotheses {
+		if h.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -6,7 +6,6 @@ import (
 	"hash/fnv"
 	"math"
 	"math/rand"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -15,6 +14,8 @@ import (
 	"google.golang.org/adk/tool/functiontool"
 
 	"github.com/cpunion/sci-bot/pkg/agent"
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/knowledge"
 	"github.com/cpunion/sci-bot/pkg/publication"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
@@ -26,6 +27,13 @@ type ForumToolset struct {
 	persona *types.Persona
 	state   *agent.AgentState
 	rng     *rand.Rand
+
+	// reputation returns an author's normalized reputation in [0, 1) (optional).
+	reputation func(agentID string) float64
+	// theories lets posts present a formal theory (optional).
+	theories *knowledge.TheoryRepository
+	// experiments lets posts attach plots of experiment results (optional).
+	experiments *experiment.Store
 }
 
 // NewForumToolset creates a new forum toolset for an agent.
@@ -39,21 +47,48 @@ func NewForumToolset(forum *publication.Forum, agentID string, persona *types.Pe
 	}
 }
 
+// SetReputationSource lets feed ranking favor authors with higher reputation.
+func (ft *ForumToolset) SetReputationSource(fn func(agentID string) float64) {
+	ft.reputation = fn
+}
+
+// SetTheoryRepository lets posts present a theory from the repository;
+// agents reading such a post learn the theory from its author.
+func (ft *ForumToolset) SetTheoryRepository(repo *knowledge.TheoryRepository) {
+	ft.theories = repo
+}
+
+// SetExperimentStore lets posts attach plots of stored experiments.
+func (ft *ForumToolset) SetExperimentStore(store *experiment.Store) {
+	ft.experiments = store
+}
+
 // --- Browse Forum Tool ---
 
 // BrowseForumInput is the input for browsing the forum.
 type BrowseForumInput struct {
 	// Subreddit to browse (optional, empty means all)
 	Subreddit string `json:"subreddit,omitempty"`
-	// SortBy can be "hot" or "recent"
+	// SortBy can be "hot" (default), "new", "top" or "controversial"
 	SortBy string `json:"sort_by,omitempty"`
+	// Window limits "top" and "controversial" to "day", "week" or "all" (default)
+	Window string `json:"window,omitempty"`
 	// Limit number of posts to return
 	Limit int `json:"limit,omitempty"`
 }
 
 // BrowseForumOutput is the output of browsing the forum.
 type BrowseForumOutput struct {
-	Posts []PostSummary `json:"posts"`
+	Posts      []PostSummary      `json:"posts"`
+	Subreddits []SubredditSummary `json:"subreddits,omitempty"`
+}
+
+// SubredditSummary describes an available subreddit.
+type SubredditSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Posts       int    `json:"posts"`
+	CreatorName string `json:"creator_name,omitempty"`
 }
 
 // PostSummary is a summary of a forum post.
@@ -66,6 +101,8 @@ type PostSummary struct {
 	Comments   int    `json:"comments"`
 	Abstract   string `json:"abstract,omitempty"`
 	Mentioned  bool   `json:"mentioned,omitempty"`
+	// Preprint marks a paper under journal review.
+	Preprint bool `json:"preprint,omitempty"`
 }
 
 // BrowseForumTool creates the browse forum tool.
@@ -76,12 +113,16 @@ func (ft *ForumToolset) BrowseForumTool() (tool.Tool, error) {
 			limit = 10
 		}
 
-		posts := ft.personalizedFeed(input)
+		posts, err := ft.personalizedFeed(input)
+		if err != nil {
+			return BrowseForumOutput{}, err
+		}
 		if limit > len(posts) {
 			limit = len(posts)
 		}
 		posts = posts[:limit]
 
+		boosts := ft.mentionBoosts()
 		summaries := make([]PostSummary, 0, len(posts))
 		for _, p := range posts {
 			summaries = append(summaries, PostSummary{
@@ -92,16 +133,17 @@ func (ft *ForumToolset) BrowseForumTool() (tool.Tool, error) {
 				Score:      p.Score,
 				Comments:   p.Comments,
 				Abstract:   p.Abstract,
-				Mentioned:  ft.postMentionsAgent(p),
+				Mentioned:  boosts[p.ID] > 0,
+				Preprint:   p.Channel == types.ChannelPreprint,
 			})
 		}
 
-		return BrowseForumOutput{Posts: summaries}, nil
+		return BrowseForumOutput{Posts: summaries, Subreddits: ft.subredditSummaries()}, nil
 	}
 
 	return functiontool.New(functiontool.Config{
 		Name:        "browse_forum",
-		Description: "浏览论坛帖子。会根据你的兴趣与关系个性化推荐，可按板块筛选。",
+		Description: "浏览论坛帖子。sort_by 为 hot（默认，得分随时间衰减）或 new 时会根据你的兴趣与关系个性化推荐；top 按得分、controversial 按赞踩势均力敌程度排序，可用 window（day/week/all）限定时间范围。可按板块筛选；同时返回所有可用板块。",
 	}, handler)
 }
 
@@ -122,6 +164,10 @@ type ReadPostOutput struct {
 	Subreddit  string           `json:"subreddit"`
 	Score      int              `json:"score"`
 	Comments   []CommentSummary `json:"comments"`
+	// PaperID is the journal submission of a preprint.
+	PaperID string `json:"paper_id,omitempty"`
+	// Attachments are the post's equations, tables and figures as Markdown.
+	Attachments []string `json:"attachments,omitempty"`
 }
 
 // CommentSummary is a summary of a comment.
@@ -141,10 +187,14 @@ func (ft *ForumToolset) ReadPostTool() (tool.Tool, error) {
 		if post == nil {
 			return ReadPostOutput{}, fmt.Errorf("post not found: %s", input.PostID)
 		}
+		if post.Moderation == types.ModerationRemoved {
+			return ReadPostOutput{}, fmt.Errorf("post was removed by a moderator: %s (%s)", input.PostID, post.ModerationReason)
+		}
 
 		// Increment views
 		ft.forum.IncrementViews(input.PostID)
 		ft.recordInteraction(post)
+		ft.learnTheory(post)
 
 		// Get threaded comments
 		commentPubs := ft.forum.GetThreadComments(input.PostID)
@@ -170,6 +220,9 @@ func (ft *ForumToolset) ReadPostTool() (tool.Tool, error) {
 			Subreddit:  string(post.Subreddit),
 			Score:      post.Score,
 			Comments:   comments,
+			PaperID:    post.PaperID,
+
+			Attachments: attachmentTexts(post.Attachments),
 		}, nil
 	}
 
@@ -386,6 +439,8 @@ func (ft *ForumToolset) SaveThreadSummaryTool() (tool.Tool, error) {
 type BrowseMentionsInput struct {
 	// Limit number of mentions to return
 	Limit int `json:"limit,omitempty"`
+	// IncludeRead also returns mentions and replies already seen.
+	IncludeRead bool `json:"include_read,omitempty"`
 }
 
 // MentionSummary is a summary of a mention.
@@ -399,11 +454,14 @@ type MentionSummary struct {
 	IsComment   bool   `json:"is_comment"`
 	PublishedAt string `json:"published_at"`
 	Reason      string `json:"reason"`
+	Read        bool   `json:"read,omitempty"`
 }
 
 // BrowseMentionsOutput is the output of browsing mentions.
 type BrowseMentionsOutput struct {
 	Mentions []MentionSummary `json:"mentions"`
+	// Unread counts the unread mentions and replies left after this call.
+	Unread int `json:"unread"`
 }
 
 // BrowseMentionsTool creates the mentions tool.
@@ -417,32 +475,32 @@ func (ft *ForumToolset) BrowseMentionsTool() (tool.Tool, error) {
 			limit = 10
 		}
 
-		items := make([]mentionItem, 0)
-		for _, pub := range ft.forum.AllPublications() {
-			if pub == nil {
-				continue
-			}
-			reason := ft.mentionReason(pub)
-			if reason == "" {
+		// Unread only by default. What is returned gets marked read, so the
+		// agent doesn't answer the same mention tick after tick.
+		var since time.Time
+		if ft.state != nil && !input.IncludeRead {
+			since = ft.state.NotificationsReadThrough()
+		}
+		items := make([]publication.Mention, 0)
+		read := make(map[string]bool)
+		unread := 0
+		for _, m := range ft.forum.GetMentions(ft.agentID, since, ft.mentionKeys()...) {
+			seen := ft.state != nil && ft.state.NotificationRead(m.Reason == publication.MentionReasonReply, m.Pub.ID, m.Pub.PublishedAt)
+			if !seen {
+				unread++
+			} else if !input.IncludeRead {
 				continue
 			}
-			items = append(items, mentionItem{
-				pub:    pub,
-				reason: reason,
-			})
+			read[m.Pub.ID] = seen
+			items = append(items, m)
 		}
-
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].pub.PublishedAt.After(items[j].pub.PublishedAt)
-		})
-
 		if limit > len(items) {
 			limit = len(items)
 		}
 
 		out := make([]MentionSummary, 0, limit)
 		for _, item := range items[:limit] {
-			pub := item.pub
+			pub := item.Pub
 			subreddit := string(pub.Subreddit)
 			if subreddit == "" {
 				subreddit = string(types.SubGeneral)
@@ -460,16 +518,23 @@ func (ft *ForumToolset) BrowseMentionsTool() (tool.Tool, error) {
 				Excerpt:     strings.TrimSpace(excerpt),
 				IsComment:   pub.IsComment,
 				PublishedAt: pub.PublishedAt.Format(time.RFC3339),
-				Reason:      item.reason,
+				Reason:      item.Reason,
+				Read:        read[pub.ID],
 			})
+			if !read[pub.ID] {
+				unread--
+				if ft.state != nil {
+					ft.state.MarkNotificationRead(item.Reason == publication.MentionReasonReply, pub.ID, pub.PublishedAt)
+				}
+			}
 		}
 
-		return BrowseMentionsOutput{Mentions: out}, nil
+		return BrowseMentionsOutput{Mentions: out, Unread: unread}, nil
 	}
 
 	return functiontool.New(functiontool.Config{
 		Name:        "browse_mentions",
-		Description: "查看与你相关的 @ 提及或回复，优先处理这些内容。",
+		Description: "查看与你相关的 @ 提及或回复，优先处理这些内容。默认只返回未读条目，返回后即标记为已读；include_read=true 可连同已读条目一起查看。",
 	}, handler)
 }
 
@@ -481,45 +546,118 @@ type CreatePostInput struct {
 	Content   string `json:"content"`
 	Abstract  string `json:"abstract,omitempty"`
 	Subreddit string `json:"subreddit"`
+	// Theory the post presents (optional)
+	TheoryID string `json:"theory_id,omitempty"`
+	// Equations, tables and experiment plots to attach (optional)
+	Attachments []AttachmentInput `json:"attachments,omitempty"`
 }
 
 // CreatePostOutput is the output of creating a post.
 type CreatePostOutput struct {
 	PostID  string `json:"post_id"`
 	Message string `json:"message"`
+	// SimilarThreadID is an existing thread the new post closely matches.
+	SimilarThreadID string `json:"similar_thread_id,omitempty"`
 }
 
 // CreatePostTool creates the create post tool.
 func (ft *ForumToolset) CreatePostTool(agentName string) (tool.Tool, error) {
 	handler := func(ctx tool.Context, input CreatePostInput) (CreatePostOutput, error) {
-		sub := types.Subreddit(input.Subreddit)
+		sub := publication.NormalizeSubreddit(input.Subreddit)
+		note := ""
 		if sub == "" {
 			sub = types.SubGeneral
+		} else if !ft.forum.HasSubreddit(sub) {
+			note = fmt.Sprintf("（板块 r/%s 不存在，可用 create_subreddit 创建）", sub)
+			sub = types.SubGeneral
+		}
+		theoryID := strings.TrimSpace(input.TheoryID)
+		if theoryID != "" {
+			if ft.theories == nil {
+				return CreatePostOutput{}, fmt.Errorf("theories are not enabled in this run")
+			}
+			if _, err := ft.theories.Snapshot(theoryID); err != nil {
+				return CreatePostOutput{}, err
+			}
+		}
+		attachments, err := buildAttachments(input.Attachments, ft.experiments)
+		if err != nil {
+			return CreatePostOutput{}, err
 		}
 
 		pub := &types.Publication{
+			TheoryID:   theoryID,
 			AuthorID:   ft.agentID,
 			AuthorName: agentName,
 			Title:      input.Title,
 			Content:    input.Content,
 			Abstract:   input.Abstract,
 			Subreddit:  sub,
-			Mentions:   extractMentions(input.Title + "\n" + input.Abstract + "\n" + input.Content),
+			Mentions:   publication.ExtractMentions(input.Title + "\n" + input.Abstract + "\n" + input.Content),
+
+			Attachments: attachments,
 		}
 
 		if err := ft.forum.Post(pub); err != nil {
 			return CreatePostOutput{}, err
 		}
 
-		return CreatePostOutput{
+		out := CreatePostOutput{
 			PostID:  pub.ID,
-			Message: fmt.Sprintf("帖子已发布到 r/%s", sub),
-		}, nil
+			Message: fmt.Sprintf("帖子已发布到 r/%s%s", sub, note),
+		}
+		if pub.DuplicateOf != "" {
+			out.SimilarThreadID = pub.DuplicateOf
+			out.Message += fmt.Sprintf("。已存在相似的帖子 %s，建议改为在该帖下评论", pub.DuplicateOf)
+		}
+		return out, nil
 	}
 
 	return functiontool.New(functiontool.Config{
 		Name:        "create_post",
-		Description: "在论坛发布新帖子。需要指定标题、内容和板块。",
+		Description: "在论坛发布新帖子。需要指定标题、内容和板块；介绍某个形式化理论时用 theory_id 注明，读者会由此了解该理论。attachments 可附公式（kind=math，latex）、小型数据表（kind=table，columns 与 rows）或实验结果图（kind=plot，用 run_experiment 的实验 ID 列表 experiments 画出 y 随 x 的变化，x、y 为参数或指标名），正文中用 eq-1、table-1、fig-1 等编号引用。若已有高度相似的帖子，会返回其 ID，应改为在该帖下评论而非重复发帖。",
+	}, handler)
+}
+
+// --- Create Subreddit Tool ---
+
+// CreateSubredditInput is the input for creating a subreddit.
+type CreateSubredditInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CreateSubredditOutput is the output of creating a subreddit.
+type CreateSubredditOutput struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// CreateSubredditTool creates the create subreddit tool.
+func (ft *ForumToolset) CreateSubredditTool(agentName string) (tool.Tool, error) {
+	handler := func(ctx tool.Context, input CreateSubredditInput) (CreateSubredditOutput, error) {
+		if strings.TrimSpace(input.Description) == "" {
+			return CreateSubredditOutput{}, fmt.Errorf("missing description")
+		}
+		info := &types.SubredditInfo{
+			Name:        types.Subreddit(input.Name),
+			Description: input.Description,
+			CreatorID:   ft.agentID,
+			CreatorName: agentName,
+		}
+		if err := ft.forum.CreateSubreddit(info); err != nil {
+			return CreateSubredditOutput{}, err
+		}
+
+		return CreateSubredditOutput{
+			Name:    string(info.Name),
+			Message: fmt.Sprintf("板块 r/%s 已创建", info.Name),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "create_subreddit",
+		Description: "创建新的论坛板块（名称为小写字母/数字/-/_，需附简短描述）。仅在现有板块都不合适时使用。",
 	}, handler)
 }
 
@@ -597,7 +735,7 @@ func (ft *ForumToolset) CommentTool(agentName string) (tool.Tool, error) {
 			AuthorID:   ft.agentID,
 			AuthorName: agentName,
 			Content:    input.Content,
-			Mentions:   extractMentions(input.Content),
+			Mentions:   publication.ExtractMentions(input.Content),
 		}
 
 		if err := ft.forum.Comment(parentID, comment); err != nil {
@@ -619,6 +757,172 @@ func (ft *ForumToolset) CommentTool(agentName string) (tool.Tool, error) {
 	}, handler)
 }
 
+// --- Moderation Tools ---
+
+// ReportPostInput is the input for reporting a post.
+type ReportPostInput struct {
+	PostID string `json:"post_id"`
+	// Category is spam, duplicate, low_quality, off_topic, hostile or other.
+	Category string `json:"category"`
+	Reason   string `json:"reason"`
+}
+
+// ReportPostOutput is the output of reporting a post.
+type ReportPostOutput struct {
+	ReportID string `json:"report_id"`
+	Message  string `json:"message"`
+}
+
+// ReportPostTool creates the report post tool.
+func (ft *ForumToolset) ReportPostTool(agentName string) (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ReportPostInput) (ReportPostOutput, error) {
+		report := &types.PostReport{
+			PostID:       input.PostID,
+			ReporterID:   ft.agentID,
+			ReporterName: agentName,
+			Category:     input.Category,
+			Reason:       input.Reason,
+		}
+		if err := ft.forum.Report(report); err != nil {
+			return ReportPostOutput{}, err
+		}
+		return ReportPostOutput{
+			ReportID: report.ID,
+			Message:  "举报已提交，等待版主处理",
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "report_post",
+		Description: "举报低质量、重复、灌水或跑题的帖子/评论（category: spam/duplicate/low_quality/off_topic/hostile/other，需写明理由）。不要因观点分歧而举报。",
+	}, handler)
+}
+
+// ViewReportsInput is the input for viewing open reports.
+type ViewReportsInput struct {
+	Limit int `json:"limit,omitempty"`
+}
+
+// ReportSummary groups the open reports against one publication.
+type ReportSummary struct {
+	PostID     string   `json:"post_id"`
+	Title      string   `json:"title,omitempty"`
+	Excerpt    string   `json:"excerpt"`
+	AuthorName string   `json:"author_name"`
+	Moderation string   `json:"moderation,omitempty"`
+	Reports    int      `json:"reports"`
+	Categories []string `json:"categories"`
+	Reasons    []string `json:"reasons"`
+}
+
+// ViewReportsOutput is the output of viewing open reports.
+type ViewReportsOutput struct {
+	Items []ReportSummary `json:"items"`
+	Total int             `json:"total"`
+}
+
+// ViewReportsTool creates the moderator tool that lists open reports.
+func (ft *ForumToolset) ViewReportsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ViewReportsInput) (ViewReportsOutput, error) {
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 10
+		}
+		byPost := make(map[string]*ReportSummary)
+		order := make([]string, 0)
+		for _, r := range ft.forum.GetReports(true) {
+			item, ok := byPost[r.PostID]
+			if !ok {
+				item = &ReportSummary{PostID: r.PostID}
+				if post := ft.forum.Get(r.PostID); post != nil {
+					item.Title = post.Title
+					item.Excerpt = truncateString(post.Content, 200)
+					item.AuthorName = post.AuthorName
+					item.Moderation = string(post.Moderation)
+				}
+				byPost[r.PostID] = item
+				order = append(order, r.PostID)
+			}
+			item.Reports++
+			item.Categories = append(item.Categories, r.Category)
+			item.Reasons = append(item.Reasons, r.Reason)
+		}
+		// Most-reported first; ties keep oldest-report order.
+		sort.SliceStable(order, func(i, j int) bool {
+			return byPost[order[i]].Reports > byPost[order[j]].Reports
+		})
+
+		items := make([]ReportSummary, 0, len(order))
+		for _, id := range order {
+			items = append(items, *byPost[id])
+		}
+		total := len(items)
+		if limit < len(items) {
+			items = items[:limit]
+		}
+		return ViewReportsOutput{Items: items, Total: total}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "view_reports",
+		Description: "版主专用：查看待处理的举报（按被举报次数排序）。",
+	}, handler)
+}
+
+// ModeratePostInput is the input for moderating a post.
+type ModeratePostInput struct {
+	PostID string `json:"post_id"`
+	// Action is hide, remove, restore or dismiss.
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// ModeratePostOutput is the output of moderating a post.
+type ModeratePostOutput struct {
+	ActionID        string `json:"action_id"`
+	ResolvedReports int    `json:"resolved_reports"`
+	Message         string `json:"message"`
+}
+
+// ModeratePostTool creates the moderator tool that hides, removes or restores
+// posts, or dismisses their reports.
+func (ft *ForumToolset) ModeratePostTool(agentName string) (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ModeratePostInput) (ModeratePostOutput, error) {
+		if ft.persona == nil || !ft.persona.Moderator {
+			return ModeratePostOutput{}, fmt.Errorf("only moderators can moderate posts")
+		}
+		var (
+			action *types.ModerationAction
+			err    error
+		)
+		switch types.ModerationActionType(strings.ToLower(strings.TrimSpace(input.Action))) {
+		case types.ModActionHide:
+			action, err = ft.forum.HidePost(input.PostID, ft.agentID, agentName, input.Reason)
+		case types.ModActionRemove:
+			action, err = ft.forum.RemovePost(input.PostID, ft.agentID, agentName, input.Reason)
+		case types.ModActionRestore:
+			action, err = ft.forum.RestorePost(input.PostID, ft.agentID, agentName, input.Reason)
+		case types.ModActionDismiss:
+			action, err = ft.forum.DismissReports(input.PostID, ft.agentID, agentName, input.Reason)
+		default:
+			return ModeratePostOutput{}, fmt.Errorf("invalid action: %s (use 'hide', 'remove', 'restore' or 'dismiss')", input.Action)
+		}
+		if err != nil {
+			return ModeratePostOutput{}, err
+		}
+		return ModeratePostOutput{
+			ActionID:        action.ID,
+			ResolvedReports: len(action.ReportIDs),
+			Message:         fmt.Sprintf("已对 %s 执行 %s", input.PostID, action.Action),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "moderate_post",
+		Description: "版主专用：隐藏（hide，仍可按 ID 阅读）、移除（remove，禁止继续投票/回复）、恢复（restore）帖子/评论，或驳回举报（dismiss），必须写明理由。",
+	}, handler)
+}
+
 // AllTools returns all forum tools.
 func (ft *ForumToolset) AllTools(agentName string) ([]tool.Tool, error) {
 	browseTool, err := ft.BrowseForumTool()
@@ -651,6 +955,11 @@ func (ft *ForumToolset) AllTools(agentName string) ([]tool.Tool, error) {
 		return nil, err
 	}
 
+	createSubredditTool, err := ft.CreateSubredditTool(agentName)
+	if err != nil {
+		return nil, err
+	}
+
 	voteTool, err := ft.VoteTool()
 	if err != nil {
 		return nil, err
@@ -661,33 +970,83 @@ func (ft *ForumToolset) AllTools(agentName string) ([]tool.Tool, error) {
 		return nil, err
 	}
 
-	return []tool.Tool{
+	reportTool, err := ft.ReportPostTool(agentName)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := []tool.Tool{
 		browseTool,
 		readTool,
 		digestTool,
 		saveSummaryTool,
 		mentionTool,
 		createTool,
+		createSubredditTool,
 		voteTool,
 		commentTool,
-	}, nil
+		reportTool,
+	}
+
+	if ft.persona != nil && ft.persona.Moderator {
+		viewReportsTool, err := ft.ViewReportsTool()
+		if err != nil {
+			return nil, err
+		}
+		moderateTool, err := ft.ModeratePostTool(agentName)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, viewReportsTool, moderateTool)
+	}
+
+	return tools, nil
+}
+
+func (ft *ForumToolset) subredditSummaries() []SubredditSummary {
+	stats := ft.forum.GetSubredditStats()
+	subs := ft.forum.ListSubreddits()
+	out := make([]SubredditSummary, 0, len(subs))
+	for _, info := range subs {
+		out = append(out, SubredditSummary{
+			Name:        string(info.Name),
+			Description: info.Description,
+			Posts:       stats[info.Name],
+			CreatorName: info.CreatorName,
+		})
+	}
+	return out
 }
 
-func (ft *ForumToolset) personalizedFeed(input BrowseForumInput) []*types.Publication {
-	var candidates []*types.Publication
+func (ft *ForumToolset) personalizedFeed(input BrowseForumInput) ([]*types.Publication, error) {
+	order, err := publication.ParsePostSort(input.SortBy)
+	if err != nil {
+		return nil, err
+	}
+	window, err := publication.ParseTimeWindow(input.Window)
+	if err != nil {
+		return nil, err
+	}
+	var sub types.Subreddit
 	if input.Subreddit != "" {
-		candidates = ft.forum.GetBySubreddit(types.Subreddit(input.Subreddit), 200)
-	} else {
-		candidates = ft.forum.AllPosts()
+		sub = publication.NormalizeSubreddit(input.Subreddit)
 	}
+	candidates := ft.forum.Ranked(sub, order, window, 0)
 
-	if len(candidates) == 0 {
-		return candidates
+	// Top and controversial are explicit listings; only hot and new are
+	// personalized.
+	if len(candidates) == 0 || order == publication.PostTop || order == publication.PostControversial {
+		return candidates, nil
 	}
 
+	topHot := math.Inf(-1)
+	for _, post := range candidates {
+		topHot = math.Max(topHot, publication.HotScore(post))
+	}
+	boosts := ft.mentionBoosts()
 	scored := make([]scoredPost, 0, len(candidates))
 	for _, post := range candidates {
-		score := ft.scorePost(post, input.SortBy)
+		score := ft.scorePost(post, order, publication.HotScore(post)-topHot, boosts[post.ID])
 		scored = append(scored, scoredPost{post: post, score: score})
 	}
 
@@ -700,7 +1059,7 @@ func (ft *ForumToolset) personalizedFeed(input BrowseForumInput) []*types.Public
 		posts = append(posts, sp.post)
 	}
 
-	return posts
+	return posts, nil
 }
 
 func computeCommentDepth(forum *publication.Forum, comment *types.Publication, rootID string) int {
@@ -737,8 +1096,43 @@ const (
 	longThreadComments     = 18
 	parentExcerptLimit     = 180
 	newCommentContentLimit = 1200
+	// resummaryNewComments is how many replies since the last summary make
+	// it stale for background summarization: half of what get_thread_digest
+	// shows by default, so digests are refreshed before they truncate.
+	resummaryNewComments = 10
 )
 
+// StaleSummaryReason reports why a thread needs a new summary, using the
+// reasons get_thread_digest gives for needs_summary, or "" when the cached
+// summary is fresh enough or the thread is short enough to read in full.
+func StaleSummaryReason(forum *publication.Forum, rootID string) string {
+	post := forum.Get(rootID)
+	if post == nil {
+		return ""
+	}
+	comments := forum.GetThreadComments(rootID)
+	summary := forum.GetThreadSummary(rootID)
+	if summary == nil {
+		if isLongThread(post, threadCharCount(post, comments), len(comments)) {
+			return "missing_summary_long_thread"
+		}
+		return ""
+	}
+	if summary.PostHash != "" && summary.PostHash != forum.PostHash(rootID) {
+		return "post_content_changed"
+	}
+	newComments := filterNewComments(comments, summary.LastCommentAt)
+	if len(newComments) >= resummaryNewComments {
+		return "too_many_new_comments"
+	}
+	for _, c := range newComments {
+		if len(c.Content) > newCommentContentLimit {
+			return "new_comment_too_long"
+		}
+	}
+	return ""
+}
+
 func threadCharCount(post *types.Publication, comments []*types.Publication) int {
 	if post == nil {
 		return 0
@@ -804,115 +1198,45 @@ type scoredPost struct {
 	score float64
 }
 
-func (ft *ForumToolset) scorePost(post *types.Publication, sortBy string) float64 {
-	base := float64(post.Score) * 0.6
-	recency := recencyScore(post.PublishedAt)
-
-	if sortBy == "recent" {
-		base = base * 0.3
-		recency *= 2.0
+// scorePost ranks a post for the personalized feed. hot is the post's
+// HotScore relative to the hottest candidate (<= 0; each unit is a tenfold
+// of votes or 12.5 hours of age); mention is its entry in mentionBoosts.
+func (ft *ForumToolset) scorePost(post *types.Publication, order publication.PostSort, hot, mention float64) float64 {
+	score := 2.0 * hot
+	if order == publication.PostNew {
+		score = float64(post.Score)*0.18 + 2.0*recencyScore(post.PublishedAt)
 	}
 
-	score := base + recency
 	score += ft.domainScore(post.Subreddit)
 	score += ft.relationshipScore(post.AuthorID)
 	score += ft.noveltyScore(post)
-	score += ft.mentionBoost(post)
+	score += mention
 	score += ft.randomness()
 
 	return score
 }
 
-type mentionItem struct {
-	pub    *types.Publication
-	reason string
-}
-
-func (ft *ForumToolset) mentionBoost(post *types.Publication) float64 {
-	if post == nil || ft.forum == nil {
-		return 0
-	}
-	if ft.publicationMentionsAgent(post) {
-		return 5.0
+// mentionBoosts ranks threads that involve the agent higher in the feed,
+// by root post ID: the post mentions it (5), a comment mentions it (4), or a
+// comment replies to it (2.5).
+func (ft *ForumToolset) mentionBoosts() map[string]float64 {
+	boosts := make(map[string]float64)
+	if ft.forum == nil {
+		return boosts
 	}
-	comments := ft.forum.GetThreadComments(post.ID)
-	for _, c := range comments {
-		if ft.publicationMentionsAgent(c) {
-			return 4.0
-		}
-		if ft.replyTargetsAgent(c) {
-			return 2.5
-		}
-	}
-	return 0
-}
-
-func (ft *ForumToolset) postMentionsAgent(post *types.Publication) bool {
-	if post == nil || ft.forum == nil {
-		return false
-	}
-	if ft.publicationMentionsAgent(post) {
-		return true
-	}
-	comments := ft.forum.GetThreadComments(post.ID)
-	for _, c := range comments {
-		if ft.publicationMentionsAgent(c) || ft.replyTargetsAgent(c) {
-			return true
-		}
-	}
-	return false
-}
-
-func (ft *ForumToolset) mentionReason(pub *types.Publication) string {
-	if pub == nil {
-		return ""
-	}
-	if ft.publicationMentionsAgent(pub) {
-		return "mention"
-	}
-	if pub.IsComment && ft.replyTargetsAgent(pub) {
-		return "reply"
-	}
-	return ""
-}
-
-func (ft *ForumToolset) replyTargetsAgent(pub *types.Publication) bool {
-	if pub == nil || pub.ParentID == "" || ft.forum == nil {
-		return false
-	}
-	parent := ft.forum.Get(pub.ParentID)
-	if parent == nil {
-		return false
-	}
-	return parent.AuthorID == ft.agentID
-}
-
-func (ft *ForumToolset) publicationMentionsAgent(pub *types.Publication) bool {
-	if pub == nil {
-		return false
-	}
-	keys := ft.mentionKeys()
-	if len(keys) == 0 {
-		return false
-	}
-	keySet := make(map[string]struct{}, len(keys))
-	for _, k := range keys {
-		if k == "" {
-			continue
+	for _, m := range ft.forum.GetMentions(ft.agentID, time.Time{}, ft.mentionKeys()...) {
+		boost := 2.5
+		switch {
+		case !m.Pub.IsComment:
+			boost = 5.0
+		case m.Reason == publication.MentionReasonMention:
+			boost = 4.0
 		}
-		keySet[strings.ToLower(k)] = struct{}{}
-	}
-
-	mentions := pub.Mentions
-	if len(mentions) == 0 {
-		mentions = extractMentions(pub.Title + "\n" + pub.Abstract + "\n" + pub.Content)
-	}
-	for _, m := range mentions {
-		if _, ok := keySet[strings.ToLower(m)]; ok {
-			return true
+		if m.RootID != "" && boost > boosts[m.RootID] {
+			boosts[m.RootID] = boost
 		}
 	}
-	return false
+	return boosts
 }
 
 func (ft *ForumToolset) mentionKeys() []string {
@@ -982,15 +1306,20 @@ func (ft *ForumToolset) domainScore(sub types.Subreddit) float64 {
 }
 
 func (ft *ForumToolset) relationshipScore(authorID string) float64 {
+	// Reputation stands in for trust with authors the agent doesn't know yet.
+	score := 0.0
+	if ft.reputation != nil && authorID != ft.agentID {
+		score += ft.reputation(authorID) * 0.6
+	}
 	if ft.state == nil {
-		return 0
+		return score
 	}
 	rel := ft.state.GetRelationship(authorID)
 	if rel == nil {
-		return 0
+		return score
 	}
 
-	score := rel.TrustScore*1.2 + rel.Familiarity*0.8
+	score += rel.TrustScore*1.2 + rel.Familiarity*0.8
 	switch rel.State {
 	case types.RelationTrusted:
 		score += 0.6
@@ -1036,33 +1365,24 @@ func (ft *ForumToolset) recordInteraction(post *types.Publication) {
 	ft.state.RecordInteraction(post.AuthorID, post.AuthorName, topics)
 }
 
-func recencyScore(t time.Time) float64 {
-	ageHours := time.Since(t).Hours()
-	return 1.0 / (1.0 + math.Max(ageHours, 0)/12.0)
-}
-
-var mentionPattern = regexp.MustCompile(`(?i)@([a-z0-9][a-z0-9_./-]{0,63})`)
-
-func extractMentions(text string) []string {
-	if text == "" {
-		return nil
-	}
-	matches := mentionPattern.FindAllStringSubmatch(text, -1)
-	if len(matches) == 0 {
-		return nil
+// learnTheory records that the agent learned the theory a post presents
+// from its author.
+func (ft *ForumToolset) learnTheory(post *types.Publication) {
+	if ft.state == nil || post.TheoryID == "" || post.AuthorID == ft.agentID {
+		return
 	}
-	out := make([]string, 0, len(matches))
-	for _, m := range matches {
-		if len(m) < 2 {
-			continue
+	title := post.Title
+	if ft.theories != nil {
+		if theory, err := ft.theories.Snapshot(post.TheoryID); err == nil {
+			title = theory.Title
 		}
-		token := strings.TrimSpace(m[1])
-		if token == "" {
-			continue
-		}
-		out = append(out, strings.ToLower(token))
 	}
-	return uniqueStrings(out)
+	ft.state.LearnTheoryFrom(post.TheoryID, title, post.AuthorID, post.ID)
+}
+
+func recencyScore(t time.Time) float64 {
+	ageHours := time.Since(t).Hours()
+	return 1.0 / (1.0 + math.Max(ageHours, 0)/12.0)
 }
 
 func uniqueStrings(items []string) []string {
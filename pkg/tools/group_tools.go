@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/group"
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// GroupToolset lets agents form research groups, talk in a group channel
+// and share drafts with their group.
+type GroupToolset struct {
+	store    *group.Store
+	workflow *publication.Workflow
+	persona  *types.Persona
+	// now returns the current sim time.
+	now func() time.Time
+}
+
+// NewGroupToolset creates a group toolset. now supplies sim time for joins
+// and channel messages.
+func NewGroupToolset(store *group.Store, workflow *publication.Workflow, persona *types.Persona, now func() time.Time) *GroupToolset {
+	return &GroupToolset{
+		store:    store,
+		workflow: workflow,
+		persona:  persona,
+		now:      now,
+	}
+}
+
+func (gt *GroupToolset) member() group.Member {
+	return group.Member{ID: personaID(gt.persona), Name: personaName(gt.persona), JoinedAt: gt.now()}
+}
+
+// GroupInfo summarizes a group for listings.
+type GroupInfo struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Topic    string   `json:"topic"`
+	Members  []string `json:"members"`
+	Drafts   int      `json:"shared_drafts"`
+	Messages int      `json:"messages"`
+	IsMember bool     `json:"is_member"`
+}
+
+func (gt *GroupToolset) info(g group.Group) GroupInfo {
+	names := make([]string, 0, len(g.Members))
+	for _, m := range g.Members {
+		names = append(names, m.Name)
+	}
+	return GroupInfo{
+		ID:       g.ID,
+		Name:     g.Name,
+		Topic:    g.Topic,
+		Members:  names,
+		Drafts:   len(g.Drafts),
+		Messages: g.MessageCount,
+		IsMember: g.HasMember(personaID(gt.persona)),
+	}
+}
+
+// --- List Groups Tool ---
+
+// ListGroupsInput is the input.
+type ListGroupsInput struct {
+	// Only groups I belong to
+	Mine bool `json:"mine,omitempty"`
+}
+
+// ListGroupsOutput is the output.
+type ListGroupsOutput struct {
+	Groups []GroupInfo `json:"groups"`
+}
+
+// ListGroupsTool creates the list groups tool.
+func (gt *GroupToolset) ListGroupsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ListGroupsInput) (ListGroupsOutput, error) {
+		agentID := ""
+		if input.Mine {
+			agentID = personaID(gt.persona)
+		}
+		groups := gt.store.List(agentID)
+		out := ListGroupsOutput{Groups: make([]GroupInfo, 0, len(groups))}
+		for _, g := range groups {
+			out.Groups = append(out.Groups, gt.info(g))
+		}
+		return out, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name:        "list_groups",
+		Description: "查看研究小组（名称、主题、成员、共享草案数）。mine=true 只看我加入的小组。",
+	}, handler)
+}
+
+// --- Create Group Tool ---
+
+// CreateGroupInput is the input.
+type CreateGroupInput struct {
+	Name  string `json:"name"`
+	Topic string `json:"topic"`
+}
+
+// CreateGroupOutput is the output.
+type CreateGroupOutput struct {
+	GroupID string `json:"group_id"`
+	Message string `json:"message"`
+}
+
+// CreateGroupTool creates the create group tool.
+func (gt *GroupToolset) CreateGroupTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input CreateGroupInput) (CreateGroupOutput, error) {
+		if strings.TrimSpace(input.Topic) == "" {
+			return CreateGroupOutput{}, fmt.Errorf("topic is required")
+		}
+		g, err := gt.store.Create(input.Name, input.Topic, gt.member())
+		if err != nil {
+			return CreateGroupOutput{}, err
+		}
+		return CreateGroupOutput{
+			GroupID: g.ID,
+			Message: fmt.Sprintf("已创建研究小组「%s」，可邀请同行用 join_group 加入", g.Name),
+		}, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name: "create_group",
+		Description: fmt.Sprintf("创建研究小组并成为首位成员（需要名称和研究主题）。每人最多加入 %d 个小组，每组最多 %d 人；先用 list_groups 确认没有相近的小组。",
+			group.MaxGroupsPerAgent, group.MaxMembers),
+	}, handler)
+}
+
+// --- Join Group Tool ---
+
+// JoinGroupInput is the input.
+type JoinGroupInput struct {
+	GroupID string `json:"group_id"`
+	// Leave the group instead of joining it
+	Leave bool `json:"leave,omitempty"`
+}
+
+// JoinGroupOutput is the output.
+type JoinGroupOutput struct {
+	Message string `json:"message"`
+}
+
+// JoinGroupTool creates the join group tool.
+func (gt *GroupToolset) JoinGroupTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input JoinGroupInput) (JoinGroupOutput, error) {
+		id := strings.TrimSpace(input.GroupID)
+		if input.Leave {
+			if err := gt.store.Leave(id, personaID(gt.persona)); err != nil {
+				return JoinGroupOutput{}, err
+			}
+			return JoinGroupOutput{Message: "已退出小组 " + id}, nil
+		}
+		g, err := gt.store.Join(id, gt.member())
+		if err != nil {
+			return JoinGroupOutput{}, err
+		}
+		return JoinGroupOutput{Message: fmt.Sprintf("已加入研究小组「%s」（%d 名成员），可用 read_group 查看频道与共享草案", g.Name, len(g.Members))}, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name:        "join_group",
+		Description: "加入一个研究小组（leave=true 则退出）。",
+	}, handler)
+}
+
+// --- Read Group Tool ---
+
+// ReadGroupInput is the input.
+type ReadGroupInput struct {
+	GroupID string `json:"group_id"`
+	// Max channel messages, newest last (default 20)
+	Limit int `json:"limit,omitempty"`
+}
+
+// GroupDraft is a draft shared with a group.
+type GroupDraft struct {
+	DraftID string   `json:"draft_id"`
+	Title   string   `json:"title"`
+	Kind    string   `json:"kind,omitempty"`
+	Authors []string `json:"authors,omitempty"`
+}
+
+// ReadGroupOutput is the output.
+type ReadGroupOutput struct {
+	Group   GroupInfo       `json:"group"`
+	Drafts  []GroupDraft    `json:"drafts"`
+	Channel []group.Message `json:"channel"`
+}
+
+// ReadGroupTool creates the read group tool.
+func (gt *GroupToolset) ReadGroupTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ReadGroupInput) (ReadGroupOutput, error) {
+		g, err := gt.store.Get(strings.TrimSpace(input.GroupID))
+		if err != nil {
+			return ReadGroupOutput{}, err
+		}
+		if !g.HasMember(personaID(gt.persona)) {
+			return ReadGroupOutput{}, group.ErrNotMember
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 20
+		}
+		channel := g.Channel
+		if len(channel) > limit {
+			channel = channel[len(channel)-limit:]
+		}
+		out := ReadGroupOutput{Group: gt.info(g), Drafts: make([]GroupDraft, 0, len(g.Drafts)), Channel: channel}
+		for _, id := range g.Drafts {
+			d := GroupDraft{DraftID: id}
+			if gt.workflow != nil {
+				if draft := gt.workflow.GetDraft(id); draft != nil {
+					d.Title, d.Kind, d.Authors = draft.Title, string(draft.Kind), draft.Authors
+				}
+			}
+			out.Drafts = append(out.Drafts, d)
+		}
+		return out, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name:        "read_group",
+		Description: "阅读我所在小组的频道消息与共享草案（仅成员可见）。",
+	}, handler)
+}
+
+// --- Group Message Tool ---
+
+// GroupMessageInput is the input.
+type GroupMessageInput struct {
+	GroupID string `json:"group_id"`
+	Content string `json:"content"`
+	// Optional draft to share with the group along with the message
+	DraftID string `json:"draft_id,omitempty"`
+}
+
+// GroupMessageOutput is the output.
+type GroupMessageOutput struct {
+	Message string `json:"message"`
+}
+
+// GroupMessageTool creates the group message tool.
+func (gt *GroupToolset) GroupMessageTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GroupMessageInput) (GroupMessageOutput, error) {
+		id := strings.TrimSpace(input.GroupID)
+		if draftID := strings.TrimSpace(input.DraftID); draftID != "" {
+			if gt.workflow == nil || gt.workflow.GetDraft(draftID) == nil {
+				return GroupMessageOutput{}, fmt.Errorf("draft not found: %s", draftID)
+			}
+			if err := gt.store.ShareDraft(id, personaID(gt.persona), draftID); err != nil {
+				return GroupMessageOutput{}, err
+			}
+		}
+		err := gt.store.Post(id, group.Message{
+			AuthorID:   personaID(gt.persona),
+			AuthorName: personaName(gt.persona),
+			Content:    input.Content,
+			At:         gt.now(),
+		})
+		if err != nil {
+			return GroupMessageOutput{}, err
+		}
+		return GroupMessageOutput{Message: "消息已发到小组频道"}, nil
+	}
+	return functiontool.New(functiontool.Config{
+		Name:        "group_message",
+		Description: "在小组频道发消息协调分工（仅成员可见）；附 draft_id 可把草案共享给小组。",
+	}, handler)
+}
+
+// AllTools returns all group tools.
+func (gt *GroupToolset) AllTools() ([]tool.Tool, error) {
+	if gt.store == nil {
+		return nil, nil
+	}
+	constructors := []func() (tool.Tool, error){
+		gt.ListGroupsTool,
+		gt.CreateGroupTool,
+		gt.JoinGroupTool,
+		gt.ReadGroupTool,
+		gt.GroupMessageTool,
+	}
+	out := make([]tool.Tool, 0, len(constructors))
+	for _, build := range constructors {
+		t, err := build()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
@@ -0,0 +1,343 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/idgen"
+	"github.com/cpunion/sci-bot/pkg/knowledge"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// KnowledgeToolset provides tools for building and disputing formal theories
+// on top of the shared axiom registry.
+type KnowledgeToolset struct {
+	axioms   *knowledge.AxiomRegistry
+	theories *knowledge.TheoryRepository
+	persona  *types.Persona
+}
+
+// NewKnowledgeToolset creates a knowledge toolset for an agent.
+func NewKnowledgeToolset(axioms *knowledge.AxiomRegistry, theories *knowledge.TheoryRepository, persona *types.Persona) *KnowledgeToolset {
+	return &KnowledgeToolset{
+		axioms:   axioms,
+		theories: theories,
+		persona:  persona,
+	}
+}
+
+// TheoryInfo summarizes a theory.
+type TheoryInfo struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Authors     []string `json:"authors"`
+	Status      string   `json:"status"`
+	Theorems    int      `json:"theorems"`
+	Challenges  int      `json:"challenges"`
+	IsHeretical bool     `json:"is_heretical,omitempty"`
+}
+
+// --- List Axiom Systems Tool ---
+
+// ListAxiomSystemsInput is the input.
+type ListAxiomSystemsInput struct {
+	// Only show this axiom system (optional)
+	SystemID string `json:"system_id,omitempty"`
+}
+
+// AxiomSystemInfo describes an axiom system and the theories built on it.
+type AxiomSystemInfo struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Parent      string        `json:"parent,omitempty"`
+	Differences []string      `json:"differences,omitempty"`
+	Axioms      []types.Axiom `json:"axioms"`
+	Theories    []TheoryInfo  `json:"theories"`
+}
+
+// ListAxiomSystemsOutput is the output.
+type ListAxiomSystemsOutput struct {
+	Systems []AxiomSystemInfo `json:"systems"`
+	// Theories on custom axioms only
+	CustomTheories []TheoryInfo `json:"custom_theories,omitempty"`
+}
+
+// ListAxiomSystemsTool creates the list axiom systems tool.
+func (kt *KnowledgeToolset) ListAxiomSystemsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ListAxiomSystemsInput) (ListAxiomSystemsOutput, error) {
+		systems := kt.axioms.List()
+		sort.Slice(systems, func(i, j int) bool { return systems[i].ID < systems[j].ID })
+
+		out := ListAxiomSystemsOutput{Systems: make([]AxiomSystemInfo, 0, len(systems))}
+		for _, sys := range systems {
+			if input.SystemID != "" && sys.ID != input.SystemID {
+				continue
+			}
+			out.Systems = append(out.Systems, AxiomSystemInfo{
+				ID:          sys.ID,
+				Name:        sys.Name,
+				Description: sys.Description,
+				Parent:      sys.Parent,
+				Differences: sys.Differences,
+				Axioms:      sys.Axioms,
+				Theories:    kt.theoryInfos(sys.ID),
+			})
+		}
+		if input.SystemID == "" || input.SystemID == "custom" {
+			out.CustomTheories = kt.theoryInfos("custom")
+		}
+		if input.SystemID != "" && input.SystemID != "custom" && len(out.Systems) == 0 {
+			return ListAxiomSystemsOutput{}, fmt.Errorf("axiom system not found: %s", input.SystemID)
+		}
+		return out, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "list_axiom_systems",
+		Description: "列出公理体系（含各条公理 ID）以及建立在其上的理论。可用 system_id 只看某个体系。",
+	}, handler)
+}
+
+// --- Propose Theory Tool ---
+
+// ProposeTheoryInput is the input.
+type ProposeTheoryInput struct {
+	Title    string `json:"title"`
+	Abstract string `json:"abstract"`
+	// Axiom system ID, or "custom" to rely only on custom_axioms
+	AxiomSystem string `json:"axiom_system"`
+	// Additional axioms, one statement each
+	CustomAxioms []string `json:"custom_axioms,omitempty"`
+	Hypotheses   []string `json:"hypotheses,omitempty"`
+	Conjectures  []string `json:"conjectures,omitempty"`
+}
+
+// ProposeTheoryOutput is the output.
+type ProposeTheoryOutput struct {
+	TheoryID string   `json:"theory_id"`
+	AxiomIDs []string `json:"custom_axiom_ids,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// ProposeTheoryTool creates the propose theory tool.
+func (kt *KnowledgeToolset) ProposeTheoryTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ProposeTheoryInput) (ProposeTheoryOutput, error) {
+		title := strings.TrimSpace(input.Title)
+		if title == "" {
+			return ProposeTheoryOutput{}, fmt.Errorf("title is required")
+		}
+		system := strings.TrimSpace(input.AxiomSystem)
+		if system == "" {
+			system = "custom"
+		}
+
+		theory := &types.Theory{
+			ID:          idgen.New("theory"),
+			Title:       title,
+			Authors:     []string{personaID(kt.persona)},
+			Abstract:    strings.TrimSpace(input.Abstract),
+			AxiomSystem: system,
+		}
+		axiomIDs := make([]string, 0, len(input.CustomAxioms))
+		for _, stmt := range input.CustomAxioms {
+			if stmt = strings.TrimSpace(stmt); stmt != "" {
+				id := fmt.Sprintf("c%d", len(theory.CustomAxioms)+1)
+				theory.CustomAxioms = append(theory.CustomAxioms, types.Axiom{ID: id, Statement: stmt})
+				axiomIDs = append(axiomIDs, id)
+			}
+		}
+		if system == "custom" && len(theory.CustomAxioms) == 0 {
+			return ProposeTheoryOutput{}, fmt.Errorf("custom theories need at least one custom axiom")
+		}
+		for _, stmt := range input.Hypotheses {
+			if stmt = strings.TrimSpace(stmt); stmt != "" {
+				theory.Hypotheses = append(theory.Hypotheses, types.Hypothesis{
+					ID:        fmt.Sprintf("h%d", len(theory.Hypotheses)+1),
+					Statement: stmt,
+				})
+			}
+		}
+		for _, stmt := range input.Conjectures {
+			if stmt = strings.TrimSpace(stmt); stmt != "" {
+				theory.Conjectures = append(theory.Conjectures, types.Conjecture{
+					ID:        fmt.Sprintf("j%d", len(theory.Conjectures)+1),
+					Statement: stmt,
+				})
+			}
+		}
+		// Departing from an established axiom system is what makes a theory heretical.
+		theory.IsHeretical = len(theory.CustomAxioms) > 0
+
+		if err := kt.theories.Propose(theory); err != nil {
+			return ProposeTheoryOutput{}, err
+		}
+		return ProposeTheoryOutput{
+			TheoryID: theory.ID,
+			AxiomIDs: axiomIDs,
+			Message:  fmt.Sprintf("理论「%s」已提出，可用 derive_from_axioms 补充推导", title),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "propose_theory",
+		Description: "基于某个公理体系（或 custom 自定义公理）提出新理论，可附带额外公理、假说与猜想。",
+	}, handler)
+}
+
+// --- Derive From Axioms Tool ---
+
+// DeriveFromAxiomsInput is the input.
+type DeriveFromAxiomsInput struct {
+	TheoryID  string `json:"theory_id"`
+	Statement string `json:"statement"`
+	Proof     string `json:"proof"`
+	// Axiom or theorem IDs the derivation uses (e.g. "e1", "c1", "t2")
+	References []string `json:"references"`
+}
+
+// DeriveFromAxiomsOutput is the output.
+type DeriveFromAxiomsOutput struct {
+	TheoremID string `json:"theorem_id"`
+	Message   string `json:"message"`
+}
+
+// DeriveFromAxiomsTool creates the derive from axioms tool.
+func (kt *KnowledgeToolset) DeriveFromAxiomsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input DeriveFromAxiomsInput) (DeriveFromAxiomsOutput, error) {
+		theorem, err := kt.theories.AddTheorem(input.TheoryID, personaID(kt.persona), types.Theorem{
+			Statement:  strings.TrimSpace(input.Statement),
+			Proof:      strings.TrimSpace(input.Proof),
+			References: trimmedStrings(input.References),
+		})
+		if err != nil {
+			return DeriveFromAxiomsOutput{}, err
+		}
+		return DeriveFromAxiomsOutput{
+			TheoremID: theorem.ID,
+			Message:   fmt.Sprintf("定理 %s 已加入理论 %s", theorem.ID, input.TheoryID),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "derive_from_axioms",
+		Description: "为理论补充一条由公理推导出的定理。references 必须列出所用公理或已有定理的 ID。",
+	}, handler)
+}
+
+// --- Challenge Theory Tool ---
+
+// ChallengeTheoryInput is the input.
+type ChallengeTheoryInput struct {
+	TheoryID string `json:"theory_id"`
+	// "reject" (the theory is wrong) or "revise" (fixable flaws)
+	Verdict     string   `json:"verdict"`
+	Comments    string   `json:"comments"`
+	IssuesFound []string `json:"issues_found,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// ChallengeTheoryOutput is the output.
+type ChallengeTheoryOutput struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// ChallengeTheoryTool creates the challenge theory tool.
+func (kt *KnowledgeToolset) ChallengeTheoryTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ChallengeTheoryInput) (ChallengeTheoryOutput, error) {
+		if strings.TrimSpace(input.Comments) == "" {
+			return ChallengeTheoryOutput{}, fmt.Errorf("comments are required")
+		}
+		review := &types.Review{
+			ID:          idgen.New("challenge"),
+			TheoryID:    input.TheoryID,
+			ReviewerID:  personaID(kt.persona),
+			Verdict:     strings.ToLower(strings.TrimSpace(input.Verdict)),
+			Comments:    strings.TrimSpace(input.Comments),
+			IssuesFound: input.IssuesFound,
+			Suggestions: input.Suggestions,
+			CreatedAt:   time.Now(),
+		}
+		if err := kt.theories.Challenge(review); err != nil {
+			return ChallengeTheoryOutput{}, err
+		}
+		theory, err := kt.theories.Snapshot(input.TheoryID)
+		if err != nil {
+			return ChallengeTheoryOutput{}, err
+		}
+		return ChallengeTheoryOutput{
+			Status:  string(theory.Status),
+			Message: fmt.Sprintf("已质疑理论「%s」", theory.Title),
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "challenge_theory",
+		Description: "质疑他人的理论：verdict 为 reject（理论错误）或 revise（需修正），须写明理由与发现的问题。",
+	}, handler)
+}
+
+// AllTools returns all knowledge tools.
+func (kt *KnowledgeToolset) AllTools() ([]tool.Tool, error) {
+	listTool, err := kt.ListAxiomSystemsTool()
+	if err != nil {
+		return nil, err
+	}
+	proposeTool, err := kt.ProposeTheoryTool()
+	if err != nil {
+		return nil, err
+	}
+	deriveTool, err := kt.DeriveFromAxiomsTool()
+	if err != nil {
+		return nil, err
+	}
+	challengeTool, err := kt.ChallengeTheoryTool()
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{listTool, proposeTool, deriveTool, challengeTool}, nil
+}
+
+func trimmedStrings(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return uniqueStrings(out)
+}
+
+func (kt *KnowledgeToolset) theoryInfos(systemID string) []TheoryInfo {
+	found := kt.theories.GetByAxiomSystem(systemID)
+	out := make([]TheoryInfo, 0, len(found))
+	for _, t := range found {
+		theory, err := kt.theories.Snapshot(t.ID)
+		if err != nil {
+			continue
+		}
+		challenges := 0
+		for _, rev := range theory.Reviews {
+			if rev.Verdict == "reject" || rev.Verdict == "revise" {
+				challenges++
+			}
+		}
+		out = append(out, TheoryInfo{
+			ID:          theory.ID,
+			Title:       theory.Title,
+			Authors:     theory.Authors,
+			Status:      string(theory.Status),
+			Theorems:    len(theory.Theorems),
+			Challenges:  challenges,
+			IsHeretical: theory.IsHeretical,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
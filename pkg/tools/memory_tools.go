@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/memory"
+)
+
+// MemoryToolset provides tools over an agent's long-term memory.
+type MemoryToolset struct {
+	mem *memory.Memory
+	now func() time.Time
+}
+
+// NewMemoryToolset creates a memory toolset for an agent. now is the sim
+// clock notes are dated by.
+func NewMemoryToolset(mem *memory.Memory, now func() time.Time) *MemoryToolset {
+	return &MemoryToolset{mem: mem, now: now}
+}
+
+// --- Recall Memory Tool ---
+
+// RecallMemoryInput is the input.
+type RecallMemoryInput struct {
+	Query string `json:"query"`
+	// Number of memories to return (default 5, max 20)
+	K int `json:"k,omitempty"`
+}
+
+// RecalledMemory is one retrieved memory.
+type RecalledMemory struct {
+	Text  string    `json:"text"`
+	Kind  string    `json:"kind"`
+	At    time.Time `json:"at"`
+	Score float64   `json:"score"`
+}
+
+// RecallMemoryOutput is the output.
+type RecallMemoryOutput struct {
+	Memories []RecalledMemory `json:"memories"`
+	Count    int              `json:"count"`
+}
+
+// RecallMemoryTool creates the recall memory tool.
+func (mt *MemoryToolset) RecallMemoryTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RecallMemoryInput) (RecallMemoryOutput, error) {
+		k := input.K
+		if k <= 0 {
+			k = 5
+		}
+		if k > 20 {
+			k = 20
+		}
+		found, err := mt.mem.Recall(ctx, input.Query, k)
+		if err != nil {
+			return RecallMemoryOutput{}, err
+		}
+		out := make([]RecalledMemory, 0, len(found))
+		for _, r := range found {
+			out = append(out, RecalledMemory{
+				Text:  r.Record.Text,
+				Kind:  r.Record.Kind,
+				At:    r.Record.CreatedAt,
+				Score: math.Round(r.Score*1000) / 1000,
+			})
+		}
+		return RecallMemoryOutput{Memories: out, Count: len(out)}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "recall_memory",
+		Description: "按语义检索我过去的想法、回复与经验教训。query 为要回忆的主题或问题，k 为返回条数（默认 5）。",
+	}, handler)
+}
+
+// --- Record Note Tool ---
+
+// RecordNoteInput is the input.
+type RecordNoteInput struct {
+	Topic   string `json:"topic"`
+	Insight string `json:"insight"`
+	// Open questions to follow up (at most 10)
+	OpenQuestions []string `json:"open_questions,omitempty"`
+}
+
+// RecordNoteOutput is the output.
+type RecordNoteOutput struct {
+	Date    string `json:"date"`
+	Message string `json:"message"`
+}
+
+// RecordNoteTool creates the record note tool.
+func (mt *MemoryToolset) RecordNoteTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RecordNoteInput) (RecordNoteOutput, error) {
+		note := memory.ResearchNote{
+			At:      mt.now(),
+			Topic:   strings.TrimSpace(input.Topic),
+			Insight: strings.TrimSpace(input.Insight),
+		}
+		if note.Topic == "" || note.Insight == "" {
+			return RecordNoteOutput{}, errors.New("topic and insight are required")
+		}
+		for _, q := range input.OpenQuestions {
+			if q = strings.TrimSpace(q); q != "" && len(note.OpenQuestions) < 10 {
+				note.OpenQuestions = append(note.OpenQuestions, q)
+			}
+		}
+		if err := mt.mem.RecordNote(ctx, note); err != nil {
+			return RecordNoteOutput{}, err
+		}
+		return RecordNoteOutput{
+			Date:    note.At.Format("2006-01-02"),
+			Message: "笔记已记录",
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "record_note",
+		Description: "记录一条结构化研究笔记：topic 为主题，insight 为心得或结论，open_questions 为待解决的问题（可选）。笔记按天保存，与对话记录分开，可用 recall_memory 找回。",
+	}, handler)
+}
+
+// AllTools returns all memory tools.
+func (mt *MemoryToolset) AllTools() ([]tool.Tool, error) {
+	recallTool, err := mt.RecallMemoryTool()
+	if err != nil {
+		return nil, err
+	}
+	noteTool, err := mt.RecordNoteTool()
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{recallTool, noteTool}, nil
+}
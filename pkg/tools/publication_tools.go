@@ -3,15 +3,20 @@ package tools
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
 
+	"github.com/cpunion/sci-bot/pkg/experiment"
+	"github.com/cpunion/sci-bot/pkg/feed"
+	"github.com/cpunion/sci-bot/pkg/idgen"
 	"github.com/cpunion/sci-bot/pkg/publication"
 	"github.com/cpunion/sci-bot/pkg/types"
 )
@@ -23,6 +28,9 @@ type PublicationToolset struct {
 	forum    *publication.Forum
 	persona  *types.Persona
 	dataPath string
+
+	experiments *experiment.Store
+	lookupAgent func(id string) *types.Persona
 }
 
 // NewPublicationToolset creates a publication toolset.
@@ -36,6 +44,11 @@ func NewPublicationToolset(workflow *publication.Workflow, journal *publication.
 	}
 }
 
+// SetExperimentStore lets submissions cite stored experiments.
+func (pt *PublicationToolset) SetExperimentStore(store *experiment.Store) {
+	pt.experiments = store
+}
+
 // --- Create Draft Tool ---
 
 type CreateDraftInput struct {
@@ -167,12 +180,15 @@ func (pt *PublicationToolset) RequestConsensusTool() (tool.Tool, error) {
 			AuthorID:   personaID(pt.persona),
 			AuthorName: personaName(pt.persona),
 			Content:    content,
-			Mentions:   extractMentions(content),
+			Mentions:   publication.ExtractMentions(content),
 		}
 
+		// The comment and the consensus record land together or not at all.
+		var tx publication.Tx
 		if err := pt.forum.Comment(postID, comment); err != nil {
 			return RequestConsensusOutput{}, err
 		}
+		tx.OnRollback(func() error { return pt.forum.Revert(comment.ID) })
 
 		req := &types.ConsensusRequest{
 			PostID:        postID,
@@ -188,8 +204,9 @@ func (pt *PublicationToolset) RequestConsensusTool() (tool.Tool, error) {
 		}
 
 		id := pt.workflow.AddConsensusRequest(req)
+		tx.OnRollback(func() error { return pt.workflow.RemoveConsensusRequest(id) })
 		if err := pt.workflow.Save(); err != nil {
-			return RequestConsensusOutput{}, err
+			return RequestConsensusOutput{}, tx.Rollback(err)
 		}
 
 		return RequestConsensusOutput{
@@ -212,10 +229,24 @@ type SubmitPaperInput struct {
 	Title    string `json:"title,omitempty"`
 	Abstract string `json:"abstract,omitempty"`
 	Content  string `json:"content,omitempty"`
+	// ResubmissionOf references one of my earlier rejected submissions.
+	ResubmissionOf string `json:"resubmission_of,omitempty"`
+	// Experiments lists IDs of run_experiment results cited as evidence.
+	Experiments []string `json:"experiments,omitempty"`
+	// Journal is a journal ID or name; empty routes by domain.
+	Journal string `json:"journal,omitempty"`
+	// Preprint also posts the paper on the forum while it is under review,
+	// in Subreddit (default general). Journals with an embargo refuse it.
+	Preprint  bool   `json:"preprint,omitempty"`
+	Subreddit string `json:"subreddit,omitempty"`
+	// Attachments are equations, tables and experiment plots.
+	Attachments []AttachmentInput `json:"attachments,omitempty"`
 }
 
 type SubmitPaperOutput struct {
 	SubmissionID string `json:"submission_id"`
+	JournalID    string `json:"journal_id"`
+	PreprintID   string `json:"preprint_id,omitempty"`
 	Message      string `json:"message"`
 }
 
@@ -253,20 +284,114 @@ func (pt *PublicationToolset) SubmitPaperTool() (tool.Tool, error) {
 			return SubmitPaperOutput{}, fmt.Errorf("missing title or content")
 		}
 
+		resubmissionOf := strings.TrimSpace(input.ResubmissionOf)
+		if resubmissionOf != "" {
+			prev := pt.journal.GetRejected(resubmissionOf)
+			if prev == nil {
+				return SubmitPaperOutput{}, fmt.Errorf("rejected submission not found: %s", resubmissionOf)
+			}
+			if prev.AuthorID != personaID(pt.persona) {
+				return SubmitPaperOutput{}, fmt.Errorf("can only resubmit your own rejected papers")
+			}
+		}
+
+		var domains []string
+		if pt.persona != nil {
+			domains = pt.persona.Domains
+		}
+		target, err := pt.journal.Route(input.Journal, title+"\n"+abstract+"\n"+content, domains)
+		if err != nil {
+			return SubmitPaperOutput{}, err
+		}
+		if input.Preprint {
+			if pt.forum == nil {
+				return SubmitPaperOutput{}, fmt.Errorf("forum not available")
+			}
+			if target.Embargo {
+				return SubmitPaperOutput{}, fmt.Errorf("%s embargoes submissions until publication: submit without preprint, or choose another journal", target.Name)
+			}
+		}
+
+		experimentIDs := make([]string, 0, len(input.Experiments))
+		for _, id := range input.Experiments {
+			if id = strings.TrimSpace(id); id != "" {
+				experimentIDs = append(experimentIDs, id)
+			}
+		}
+		experimentIDs = uniqueStrings(experimentIDs)
+		if len(experimentIDs) > 0 {
+			if pt.experiments == nil {
+				return SubmitPaperOutput{}, fmt.Errorf("experiments not available")
+			}
+			var appendix strings.Builder
+			appendix.WriteString("\n\n## Cited Experiments\n")
+			for _, id := range experimentIDs {
+				exp, err := pt.experiments.Get(id)
+				if err != nil {
+					return SubmitPaperOutput{}, err
+				}
+				appendix.WriteString("- " + experiment.Citation(exp) + "\n")
+			}
+			content += strings.TrimRight(appendix.String(), "\n")
+		}
+		attachments, err := buildAttachments(input.Attachments, pt.experiments)
+		if err != nil {
+			return SubmitPaperOutput{}, err
+		}
+
 		pub := &types.Publication{
-			AuthorID:   personaID(pt.persona),
-			AuthorName: personaName(pt.persona),
-			Title:      title,
-			Abstract:   abstract,
-			Content:    content,
-			DraftID:    draftID,
+			ID:             idgen.New("journal"),
+			AuthorID:       personaID(pt.persona),
+			AuthorName:     personaName(pt.persona),
+			Title:          title,
+			Abstract:       abstract,
+			Content:        content,
+			DraftID:        draftID,
+			ResubmissionOf: resubmissionOf,
+			Experiments:    experimentIDs,
+			JournalID:      target.ID,
+			Attachments:    attachments,
 		}
 
+		// The preprint, journal entry, experiment citations and workflow
+		// submission land together or not at all.
+		var tx publication.Tx
+		sub := types.SubGeneral
+		if input.Preprint {
+			if s := publication.NormalizeSubreddit(input.Subreddit); s != "" && pt.forum.HasSubreddit(s) {
+				sub = s
+			}
+			preprint := &types.Publication{
+				Channel:    types.ChannelPreprint,
+				AuthorID:   pub.AuthorID,
+				AuthorName: pub.AuthorName,
+				Title:      title,
+				Abstract:   abstract,
+				Content:    content,
+				Subreddit:  sub,
+				PaperID:    pub.ID,
+				Mentions:   publication.ExtractMentions(title + "\n" + abstract + "\n" + content),
+
+				Attachments: attachments,
+			}
+			if err := pt.forum.Post(preprint); err != nil {
+				return SubmitPaperOutput{}, err
+			}
+			tx.OnRollback(func() error { return pt.forum.Revert(preprint.ID) })
+			pub.PreprintID = preprint.ID
+		}
 		if err := pt.journal.Submit(pub); err != nil {
-			return SubmitPaperOutput{}, err
+			return SubmitPaperOutput{}, tx.Rollback(err)
+		}
+		tx.OnRollback(func() error { return pt.journal.Withdraw(pub.ID) })
+		if len(experimentIDs) > 0 {
+			if err := pt.experiments.Cite(pub.ID, experimentIDs); err != nil {
+				return SubmitPaperOutput{}, tx.Rollback(errors.Join(err, pt.experiments.Uncite(pub.ID, experimentIDs)))
+			}
+			tx.OnRollback(func() error { return pt.experiments.Uncite(pub.ID, experimentIDs) })
 		}
 
-		sub := &types.Submission{
+		submission := &types.Submission{
 			ID:         pub.ID,
 			DraftID:    draftID,
 			Title:      title,
@@ -274,25 +399,39 @@ func (pt *PublicationToolset) SubmitPaperTool() (tool.Tool, error) {
 			Content:    content,
 			AuthorID:   personaID(pt.persona),
 			AuthorName: personaName(pt.persona),
+			JournalID:  target.ID,
 			Status:     types.SubmissionPending,
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
+
+			ResubmissionOf: resubmissionOf,
+			PreprintID:     pub.PreprintID,
 		}
 
-		pt.workflow.AddSubmission(sub)
+		pt.workflow.AddSubmission(submission)
+		tx.OnRollback(func() error {
+			pt.workflow.DropSubmissions([]string{submission.ID})
+			return nil
+		})
 		if err := pt.workflow.Save(); err != nil {
-			return SubmitPaperOutput{}, err
+			return SubmitPaperOutput{}, tx.Rollback(err)
 		}
 
-		return SubmitPaperOutput{
+		out := SubmitPaperOutput{
 			SubmissionID: pub.ID,
-			Message:      "Submission created and sent to journal",
-		}, nil
+			JournalID:    target.ID,
+			PreprintID:   pub.PreprintID,
+			Message:      fmt.Sprintf("Submission created and sent to %s", target.Name),
+		}
+		if pub.PreprintID != "" {
+			out.Message += fmt.Sprintf("; preprint %s posted to r/%s for discussion", pub.PreprintID, sub)
+		}
+		return out, nil
 	}
 
 	return functiontool.New(functiontool.Config{
 		Name:        "submit_paper",
-		Description: "提交论文到期刊审稿（Markdown，支持 draft_id 或直接内容）。请尽量完整：Abstract、Introduction、Background/Related Work、Method/Theory、Experiments/Verification、Limitations、References。",
+		Description: "提交论文到期刊审稿（Markdown，支持 draft_id 或直接内容）。请尽量完整：Abstract、Introduction、Background/Related Work、Method/Theory、Experiments/Verification、Limitations、References。改进后重投被拒稿件时，用 resubmission_of 引用原投稿 ID。用 experiments 引用 run_experiment 的实验 ID 作为证据。journal 指定目标期刊（ID 或名称，见 list_journals），留空则按领域自动分配。preprint 为 true 时同时把论文作为预印本发到论坛（subreddit 指定板块，默认 general），审稿期间即可讨论；设有禁发期（embargo）的期刊不接受预印本。attachments 可附公式、数据表与实验结果图（格式同 create_post）。",
 	}, handler)
 }
 
@@ -469,12 +608,7 @@ func (pt *PublicationToolset) AssessConsensusTool() (tool.Tool, error) {
 
 		consensusID := ""
 		if pt.workflow != nil {
-			for id, req := range pt.workflow.Consensus {
-				if req.PostID == postID && req.Status != types.ConsensusClosed {
-					consensusID = id
-					break
-				}
-			}
+			consensusID = pt.workflow.FindOpenConsensus(postID)
 		}
 
 		rec := ""
@@ -562,6 +696,7 @@ func (pt *PublicationToolset) ReviewPaperTool() (tool.Tool, error) {
 				Content:    pending.Content,
 				AuthorID:   pending.AuthorID,
 				AuthorName: pending.AuthorName,
+				JournalID:  pending.JournalID,
 				Status:     types.SubmissionPending,
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
@@ -573,6 +708,17 @@ func (pt *PublicationToolset) ReviewPaperTool() (tool.Tool, error) {
 		if verdict == "" {
 			return ReviewPaperOutput{}, fmt.Errorf("invalid verdict: %s", input.Verdict)
 		}
+		// Without review cycles the review decides the submission; refuse it
+		// before recording when the decision is no longer possible.
+		if !pt.workflow.ScheduledReview() {
+			status, err := publication.VerdictStatus(verdict)
+			if err != nil {
+				return ReviewPaperOutput{}, err
+			}
+			if !publication.CanTransitionSubmission(sub.Status, status) {
+				return ReviewPaperOutput{}, fmt.Errorf("submission %s is already %s and can't be decided again", subID, sub.Status)
+			}
+		}
 
 		review := &types.PaperReview{
 			SubmissionID: subID,
@@ -587,26 +733,22 @@ func (pt *PublicationToolset) ReviewPaperTool() (tool.Tool, error) {
 		reviewID := pt.workflow.AddReview(review)
 		pt.workflow.AttachReview(subID, reviewID)
 
-		status := string(sub.Status)
-		switch verdict {
-		case types.VerdictAccept:
-			if err := pt.journal.Approve(subID, pt.persona.ID); err != nil {
+		// With review cycles the editor decides once all assigned reviews are in
+		// (or the deadline passes); the review itself is only recorded.
+		if pt.workflow.ScheduledReview() {
+			if err := pt.workflow.Save(); err != nil {
 				return ReviewPaperOutput{}, err
 			}
-			pt.workflow.UpdateSubmissionStatus(subID, types.SubmissionAccepted)
-			status = string(types.SubmissionAccepted)
-		case types.VerdictReject:
-			if err := pt.journal.Reject(subID, pt.persona.ID); err != nil {
-				return ReviewPaperOutput{}, err
-			}
-			pt.workflow.UpdateSubmissionStatus(subID, types.SubmissionRejected)
-			status = string(types.SubmissionRejected)
-		case types.VerdictMinorRevision:
-			pt.workflow.UpdateSubmissionStatus(subID, types.SubmissionMinorRevision)
-			status = string(types.SubmissionMinorRevision)
-		case types.VerdictMajorRevision:
-			pt.workflow.UpdateSubmissionStatus(subID, types.SubmissionMajorRevision)
-			status = string(types.SubmissionMajorRevision)
+			return ReviewPaperOutput{
+				ReviewID: reviewID,
+				Status:   string(sub.Status),
+				Message:  "Review recorded; the decision is made at the end of the review cycle",
+			}, nil
+		}
+
+		status, err := pt.workflow.Decide(pt.journal, subID, verdict, pt.persona.ID, time.Now(), false)
+		if err != nil {
+			return ReviewPaperOutput{}, err
 		}
 
 		if err := pt.workflow.Save(); err != nil {
@@ -615,14 +757,173 @@ func (pt *PublicationToolset) ReviewPaperTool() (tool.Tool, error) {
 
 		return ReviewPaperOutput{
 			ReviewID: reviewID,
-			Status:   status,
+			Status:   string(status),
 			Message:  "Review recorded",
 		}, nil
 	}
 
 	return functiontool.New(functiontool.Config{
 		Name:        "review_paper",
-		Description: "对投稿进行审稿（Reviewer 角色）。",
+		Description: "对投稿进行审稿（Reviewer 角色）。scores 各项为 0-10 分；期刊可设接收门槛，均分不足时 accept 会改为小修。",
+	}, handler)
+}
+
+// --- Read Submission Tool ---
+
+type ReadSubmissionInput struct {
+	SubmissionID string `json:"submission_id"`
+}
+
+type ReadSubmissionOutput struct {
+	SubmissionID string `json:"submission_id"`
+	Title        string `json:"title"`
+	Abstract     string `json:"abstract,omitempty"`
+	Content      string `json:"content"`
+	Journal      string `json:"journal,omitempty"`
+	AuthorID     string `json:"author_id,omitempty"`
+	AuthorName   string `json:"author_name"`
+	DoubleBlind  bool   `json:"double_blind,omitempty"`
+	// Attachments are the paper's equations, tables and figures as Markdown.
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+// ReadSubmissionTool shows a reviewer the full text of a submission. Authors
+// are hidden while a double-blind journal's decision is pending.
+func (pt *PublicationToolset) ReadSubmissionTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ReadSubmissionInput) (ReadSubmissionOutput, error) {
+		if pt.journal == nil {
+			return ReadSubmissionOutput{}, fmt.Errorf("journal not available")
+		}
+		if pt.persona == nil || pt.persona.Role != types.RoleReviewer {
+			return ReadSubmissionOutput{}, fmt.Errorf("reviewer role required")
+		}
+		subID := strings.TrimSpace(input.SubmissionID)
+		var sub *types.Submission
+		if pt.workflow != nil {
+			sub = pt.workflow.GetSubmission(subID)
+		}
+		if sub == nil {
+			pending := findPendingSubmission(pt.journal, subID)
+			if pending == nil {
+				return ReadSubmissionOutput{}, fmt.Errorf("submission not found: %s", subID)
+			}
+			sub = &types.Submission{
+				ID:         pending.ID,
+				Title:      pending.Title,
+				Abstract:   pending.Abstract,
+				Content:    pending.Content,
+				AuthorID:   pending.AuthorID,
+				AuthorName: pending.AuthorName,
+				JournalID:  pending.JournalID,
+				Status:     types.SubmissionPending,
+			}
+		}
+
+		blind := pt.journal.BlindSubmission(sub)
+		out := ReadSubmissionOutput{
+			SubmissionID: blind.ID,
+			Title:        blind.Title,
+			Abstract:     blind.Abstract,
+			Content:      blind.Content,
+			AuthorID:     blind.AuthorID,
+			AuthorName:   blind.AuthorName,
+			DoubleBlind:  blind != sub,
+		}
+		if info, ok := pt.journal.JournalInfo(sub.JournalID); ok {
+			out.Journal = info.Name
+		}
+		if pending := findPendingSubmission(pt.journal, sub.ID); pending != nil {
+			out.Attachments = attachmentTexts(pending.Attachments)
+		}
+		return out, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "read_submission",
+		Description: "审稿前阅读投稿全文（Reviewer 角色）。双盲期刊在决定前隐去作者，请只依据内容评审，不要猜测作者身份。",
+	}, handler)
+}
+
+// --- View My Rejected Papers Tool ---
+
+type ViewRejectedInput struct {
+	Limit int `json:"limit,omitempty"`
+}
+
+type RejectedPaperInfo struct {
+	SubmissionID string               `json:"submission_id"`
+	Title        string               `json:"title"`
+	RejectedAt   time.Time            `json:"rejected_at"`
+	Rationale    string               `json:"rationale,omitempty"`
+	Reviews      []*types.PaperReview `json:"reviews,omitempty"`
+}
+
+type ViewRejectedOutput struct {
+	Papers []RejectedPaperInfo `json:"papers"`
+}
+
+func (pt *PublicationToolset) ViewRejectedTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ViewRejectedInput) (ViewRejectedOutput, error) {
+		if pt.journal == nil {
+			return ViewRejectedOutput{}, fmt.Errorf("journal not available")
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 5
+		}
+
+		mine := make([]*types.Publication, 0)
+		for _, pub := range pt.journal.GetRejectedList() {
+			if pub.AuthorID == personaID(pt.persona) {
+				mine = append(mine, pub)
+			}
+		}
+		sort.Slice(mine, func(i, j int) bool { return mine[i].RejectedAt.After(mine[j].RejectedAt) })
+		if len(mine) > limit {
+			mine = mine[:limit]
+		}
+
+		papers := make([]RejectedPaperInfo, 0, len(mine))
+		for _, pub := range mine {
+			info := RejectedPaperInfo{
+				SubmissionID: pub.ID,
+				Title:        pub.Title,
+				RejectedAt:   pub.RejectedAt,
+				Rationale:    pub.DecisionRationale,
+			}
+			if pt.workflow != nil {
+				info.Reviews = pt.workflow.GetReviews(pub.ID)
+			}
+			papers = append(papers, info)
+		}
+		return ViewRejectedOutput{Papers: papers}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "view_my_rejected_papers",
+		Description: "查看我被拒的投稿及审稿意见与拒稿理由，便于改进后用 submit_paper 的 resubmission_of 重投。",
+	}, handler)
+}
+
+// --- List Journals Tool ---
+
+type ListJournalsInput struct{}
+
+type ListJournalsOutput struct {
+	Journals []publication.JournalSummary `json:"journals"`
+}
+
+func (pt *PublicationToolset) ListJournalsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ListJournalsInput) (ListJournalsOutput, error) {
+		if pt.journal == nil {
+			return ListJournalsOutput{}, fmt.Errorf("journal not available")
+		}
+		return ListJournalsOutput{Journals: pt.journal.JournalSummaries()}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "list_journals",
+		Description: "列出可投稿的期刊：ID、名称、收稿领域、接收门槛（审稿均分）、是否双盲与禁发期（embargo，不接受预印本）以及稿件数。",
 	}, handler)
 }
 
@@ -652,14 +953,64 @@ func (pt *PublicationToolset) AllTools() ([]tool.Tool, error) {
 	if err != nil {
 		return nil, err
 	}
+	readSubmission, err := pt.ReadSubmissionTool()
+	if err != nil {
+		return nil, err
+	}
+	viewRejected, err := pt.ViewRejectedTool()
+	if err != nil {
+		return nil, err
+	}
+	listJournals, err := pt.ListJournalsTool()
+	if err != nil {
+		return nil, err
+	}
+	challengeClaim, err := pt.ChallengeClaimTool()
+	if err != nil {
+		return nil, err
+	}
+	retractPaper, err := pt.RetractPaperTool()
+	if err != nil {
+		return nil, err
+	}
+	supportConsensus, err := pt.SupportConsensusTool()
+	if err != nil {
+		return nil, err
+	}
+	opposeConsensus, err := pt.OpposeConsensusTool()
+	if err != nil {
+		return nil, err
+	}
+	updateDraft, err := pt.UpdateDraftTool()
+	if err != nil {
+		return nil, err
+	}
+	inviteCoauthor, err := pt.InviteCoauthorTool()
+	if err != nil {
+		return nil, err
+	}
+	listMyDrafts, err := pt.ListMyDraftsTool()
+	if err != nil {
+		return nil, err
+	}
 
 	return []tool.Tool{
 		assessReadiness,
 		assessConsensus,
 		createDraft,
+		updateDraft,
+		inviteCoauthor,
+		listMyDrafts,
 		requestConsensus,
+		supportConsensus,
+		opposeConsensus,
+		listJournals,
 		submitPaper,
+		readSubmission,
 		reviewPaper,
+		viewRejected,
+		challengeClaim,
+		retractPaper,
 	}, nil
 }
 
@@ -745,11 +1096,11 @@ func scanDailyNotes(dataPath, agentID string, windowStart time.Time) (int, int)
 	daySet := map[string]struct{}{}
 	keywordHits := 0
 	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
+		if entry.IsDir() || !strings.HasSuffix(strings.TrimSuffix(entry.Name(), feed.GzipExt), ".jsonl") {
 			continue
 		}
 		path := filepath.Join(dir, entry.Name())
-		file, err := os.Open(path)
+		file, err := feed.OpenJSONL(path)
 		if err != nil {
 			continue
 		}
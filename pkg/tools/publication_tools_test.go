@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+
+	"github.com/cpunion/sci-bot/pkg/publication"
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// callTool runs one function call through an agent and returns the tool's
+// response.
+func callTool(t *testing.T, tl tool.Tool, args map[string]any) map[string]any {
+	t.Helper()
+	ctx := context.Background()
+	model := &mockModel{
+		responses: []*genai.Content{
+			genai.NewContentFromFunctionCall(tl.Name(), args, "model"),
+			genai.NewContentFromText("done", "model"),
+		},
+	}
+	adkAgent, err := llmagent.New(llmagent.Config{
+		Name:                     "agent-1",
+		Model:                    model,
+		Tools:                    []tool.Tool{tl},
+		DisallowTransferToParent: true,
+		DisallowTransferToPeers:  true,
+	})
+	if err != nil {
+		t.Fatalf("agent init failed: %v", err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test-app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatalf("session create failed: %v", err)
+	}
+	r, err := runner.New(runner.Config{AppName: "test-app", Agent: adkAgent, SessionService: sessionService})
+	if err != nil {
+		t.Fatalf("runner init failed: %v", err)
+	}
+	var response map[string]any
+	for ev, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("start", "user"), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("run error: %v", err)
+		}
+		if ev == nil || ev.LLMResponse.Content == nil {
+			continue
+		}
+		for _, part := range ev.LLMResponse.Content.Parts {
+			if part.FunctionResponse != nil && part.FunctionResponse.Name == tl.Name() {
+				response = part.FunctionResponse.Response
+			}
+		}
+	}
+	if response == nil {
+		t.Fatalf("missing %s response", tl.Name())
+	}
+	return response
+}
+
+func TestSubmitPaper_Preprint(t *testing.T) {
+	dir := t.TempDir()
+	journal := publication.NewJournal("Journal", filepath.Join(dir, "journal"))
+	if err := journal.SetJournals([]*types.JournalInfo{
+		{ID: "open", Name: "Open Letters"},
+		{ID: "closed", Name: "Closed Review", Embargo: true},
+	}); err != nil {
+		t.Fatalf("set journals: %v", err)
+	}
+	forum := publication.NewForum("Forum", filepath.Join(dir, "forum"))
+	workflow := publication.NewWorkflow(filepath.Join(dir, "workflow"))
+	persona := &types.Persona{ID: "agent-1", Name: "Agent One", Role: types.RoleExplorer}
+	submit, err := NewPublicationToolset(workflow, journal, forum, persona, dir).SubmitPaperTool()
+	if err != nil {
+		t.Fatalf("submit tool: %v", err)
+	}
+
+	resp := callTool(t, submit, map[string]any{
+		"title":     "Waves in a Box",
+		"content":   "We derive the modes.",
+		"journal":   "open",
+		"preprint":  true,
+		"subreddit": "physics",
+	})
+	subID, _ := resp["submission_id"].(string)
+	preprintID, _ := resp["preprint_id"].(string)
+	if subID == "" || preprintID == "" {
+		t.Fatalf("expected submission and preprint IDs, got %v", resp)
+	}
+	post := forum.Get(preprintID)
+	if post == nil || post.Channel != types.ChannelPreprint || post.PaperID != subID || post.Subreddit != types.SubPhysics {
+		t.Fatalf("unexpected preprint post: %+v", post)
+	}
+	if pending := journal.GetPending(); len(pending) != 1 || pending[0].ID != subID || pending[0].PreprintID != preprintID {
+		t.Fatalf("pending paper should link its preprint: %+v", pending)
+	}
+	if sub := workflow.GetSubmission(subID); sub == nil || sub.PreprintID != preprintID {
+		t.Fatalf("submission should link its preprint: %+v", sub)
+	}
+	if got := forum.RankedChannel(types.ChannelPreprint, "", publication.PostNew, "", 0); len(got) != 1 || got[0].ID != preprintID {
+		t.Fatalf("preprint channel should list the preprint, got %d posts", len(got))
+	}
+	if got := forum.RankedChannel(types.ChannelForum, "", publication.PostNew, "", 0); len(got) != 0 {
+		t.Fatalf("forum channel should not list preprints, got %d posts", len(got))
+	}
+
+	resp = callTool(t, submit, map[string]any{
+		"title":    "Sealed Result",
+		"content":  "Under embargo.",
+		"journal":  "closed",
+		"preprint": true,
+	})
+	if msg, _ := resp["error"].(string); !strings.Contains(msg, "embargo") {
+		t.Fatalf("embargoed journal should refuse a preprint, got %v", resp)
+	}
+	if got := journal.GetPending(); len(got) != 1 {
+		t.Fatalf("refused submission should not be pending, got %d", len(got))
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+
+	"github.com/cpunion/sci-bot/pkg/types"
+)
+
+// --- Retract Paper Tool ---
+
+// RetractPaperInput is the input.
+type RetractPaperInput struct {
+	PaperID string `json:"paper_id"`
+	// Why the paper should be withdrawn, e.g. a fatal error or fabricated data
+	Reason string `json:"reason"`
+}
+
+// RetractPaperOutput is the output.
+type RetractPaperOutput struct {
+	// Reviewers and editors supporting the retraction so far
+	Votes     int    `json:"votes"`
+	Quorum    int    `json:"quorum"`
+	Retracted bool   `json:"retracted,omitempty"`
+	Message   string `json:"message"`
+}
+
+// RetractPaperTool creates the retract paper tool.
+func (pt *PublicationToolset) RetractPaperTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input RetractPaperInput) (RetractPaperOutput, error) {
+		if pt.workflow == nil {
+			return RetractPaperOutput{}, fmt.Errorf("workflow not available")
+		}
+		if pt.journal == nil {
+			return RetractPaperOutput{}, fmt.Errorf("journal not available")
+		}
+		if pt.persona == nil || (pt.persona.Role != types.RoleReviewer && pt.persona.Role != types.RoleEditor) {
+			return RetractPaperOutput{}, fmt.Errorf("reviewer or editor role required")
+		}
+		paperID := strings.TrimSpace(input.PaperID)
+		paper := pt.journal.Get(paperID)
+		if paper == nil || !paper.Approved {
+			return RetractPaperOutput{}, fmt.Errorf("no published paper: %s", paperID)
+		}
+		if paper.Retracted {
+			return RetractPaperOutput{}, fmt.Errorf("paper already retracted: %s", paperID)
+		}
+
+		reason := strings.TrimSpace(input.Reason)
+		votes, err := pt.workflow.VoteRetraction(paper.ID, paper.AuthorID, &types.RetractionVote{
+			AgentID:   personaID(pt.persona),
+			AgentName: personaName(pt.persona),
+			Reason:    reason,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return RetractPaperOutput{}, err
+		}
+
+		out := RetractPaperOutput{
+			Votes:   votes,
+			Quorum:  types.RetractionQuorum,
+			Message: fmt.Sprintf("已投票撤回 %s（%d/%d）", paper.ID, votes, types.RetractionQuorum),
+		}
+		if votes >= types.RetractionQuorum {
+			if err := pt.journal.Retract(paper.ID, reason, personaID(pt.persona)); err != nil {
+				return RetractPaperOutput{}, err
+			}
+			pt.workflow.MarkRetracted(paper.ID, time.Now())
+			out.Retracted = true
+			out.Message += "；论文已撤稿"
+		}
+		if err := pt.workflow.Save(); err != nil {
+			return RetractPaperOutput{}, err
+		}
+		return out, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "retract_paper",
+		Description: fmt.Sprintf("投票撤回一篇已发表的期刊论文（仅限审稿人和编辑），reason 说明撤稿理由，例如致命错误或数据不可信。每人对同一论文只能投一次，不能撤回自己的论文；%d 人投票后论文被撤稿，撤稿声明会公开显示。",
+			types.RetractionQuorum),
+	}, handler)
+}
@@ -212,6 +212,58 @@ func (st *SocialToolset) ViewKnowledgeTool() (tool.Tool, error) {
 	}, handler)
 }
 
+// --- View My Karma Tool ---
+
+// ViewMyKarmaInput is the input.
+type ViewMyKarmaInput struct {
+	// Number of recent vote records to include (default: 10)
+	Limit int `json:"limit,omitempty"`
+}
+
+// ViewMyKarmaOutput is the output.
+type ViewMyKarmaOutput struct {
+	TotalKarma   int                 `json:"total_karma"`
+	PostKarma    int                 `json:"post_karma"`
+	CommentKarma int                 `json:"comment_karma"`
+	ReceivedUp   int                 `json:"received_up"`
+	ReceivedDown int                 `json:"received_down"`
+	GivenUp      int                 `json:"given_up"`
+	GivenDown    int                 `json:"given_down"`
+	Recent       []types.KarmaRecord `json:"recent,omitempty"`
+}
+
+// ViewMyKarmaTool creates the view my karma tool.
+func (st *SocialToolset) ViewMyKarmaTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ViewMyKarmaInput) (ViewMyKarmaOutput, error) {
+		k := st.state.GetKarma()
+
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 10
+		}
+		recent := k.History
+		if len(recent) > limit {
+			recent = recent[len(recent)-limit:]
+		}
+
+		return ViewMyKarmaOutput{
+			TotalKarma:   k.Total(),
+			PostKarma:    k.PostKarma,
+			CommentKarma: k.CommentKarma,
+			ReceivedUp:   k.ReceivedUp,
+			ReceivedDown: k.ReceivedDown,
+			GivenUp:      k.GivenUp,
+			GivenDown:    k.GivenDown,
+			Recent:       recent,
+		}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name:        "view_my_karma",
+		Description: "查看我的 karma：帖子/评论得分、收到与投出的赞踩数，以及最近的投票记录。",
+	}, handler)
+}
+
 // AllTools returns all social tools.
 func (st *SocialToolset) AllTools() ([]tool.Tool, error) {
 	viewRelTool, err := st.ViewRelationshipsTool()
@@ -229,9 +281,15 @@ func (st *SocialToolset) AllTools() ([]tool.Tool, error) {
 		return nil, err
 	}
 
+	viewKarmaTool, err := st.ViewMyKarmaTool()
+	if err != nil {
+		return nil, err
+	}
+
 	return []tool.Tool{
 		viewRelTool,
 		updateTrustTool,
 		viewKnowledgeTool,
+		viewKarmaTool,
 	}, nil
 }
@@ -0,0 +1,214 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Agenda is an agent's research agenda: the goals it is pursuing and the
+// projects it is working on, kept across turns and threads.
+type Agenda struct {
+	Goals     []Goal    `json:"goals,omitempty"`
+	Projects  []Project `json:"projects,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"` // sim time
+	// Seq numbers new goals and projects.
+	Seq int `json:"seq"`
+}
+
+// AgendaStatus is the state of a goal or project.
+type AgendaStatus string
+
+const (
+	AgendaActive    AgendaStatus = "active"
+	AgendaPaused    AgendaStatus = "paused"
+	AgendaDone      AgendaStatus = "done"
+	AgendaAbandoned AgendaStatus = "abandoned"
+)
+
+// Open reports whether the goal or project is still being pursued.
+func (s AgendaStatus) Open() bool {
+	return s == AgendaActive || s == AgendaPaused
+}
+
+// Goal is something an agent aims to achieve.
+type Goal struct {
+	ID        string       `json:"id"`
+	Text      string       `json:"text"`
+	Status    AgendaStatus `json:"status"`
+	SetAt     time.Time    `json:"set_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Project is a line of work, possibly with collaborators.
+type Project struct {
+	ID          string       `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Status      AgendaStatus `json:"status"`
+	// Collaborators are agent IDs.
+	Collaborators []string `json:"collaborators,omitempty"`
+	// Progress is the latest progress note.
+	Progress  string    `json:"progress,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Limits on an agenda. Finished goals and projects beyond MaxClosedAgenda
+// are dropped, oldest first.
+const (
+	MaxOpenGoals    = 5
+	MaxOpenProjects = 5
+	MaxClosedAgenda = 20
+)
+
+func parseAgendaStatus(s string) (AgendaStatus, error) {
+	switch st := AgendaStatus(strings.ToLower(strings.TrimSpace(s))); st {
+	case "":
+		return "", nil
+	case AgendaActive, AgendaPaused, AgendaDone, AgendaAbandoned:
+		return st, nil
+	}
+	return "", fmt.Errorf("unknown status %q (active, paused, done or abandoned)", s)
+}
+
+// SetGoal adds a goal (empty id) or changes the text or status of goal id,
+// and returns it.
+func (a *Agenda) SetGoal(id, text, status string, at time.Time) (Goal, error) {
+	st, err := parseAgendaStatus(status)
+	if err != nil {
+		return Goal{}, err
+	}
+	text = strings.TrimSpace(text)
+	if id == "" {
+		if text == "" {
+			return Goal{}, errors.New("goal text is required")
+		}
+		if st == "" {
+			st = AgendaActive
+		}
+		if st.Open() && countOpen(a.Goals, func(g Goal) AgendaStatus { return g.Status }) >= MaxOpenGoals {
+			return Goal{}, fmt.Errorf("at most %d open goals; finish or abandon one first", MaxOpenGoals)
+		}
+		a.Seq++
+		g := Goal{ID: fmt.Sprintf("goal-%d", a.Seq), Text: text, Status: st, SetAt: at, UpdatedAt: at}
+		a.Goals = append(a.Goals, g)
+		a.touch(at)
+		return g, nil
+	}
+	i := slices.IndexFunc(a.Goals, func(g Goal) bool { return g.ID == id })
+	if i < 0 {
+		return Goal{}, fmt.Errorf("goal not found: %s", id)
+	}
+	g := &a.Goals[i]
+	if st.Open() && !g.Status.Open() && countOpen(a.Goals, func(g Goal) AgendaStatus { return g.Status }) >= MaxOpenGoals {
+		return Goal{}, fmt.Errorf("at most %d open goals; finish or abandon one first", MaxOpenGoals)
+	}
+	if text != "" {
+		g.Text = text
+	}
+	if st != "" {
+		g.Status = st
+	}
+	g.UpdatedAt = at
+	out := *g
+	a.touch(at)
+	return out, nil
+}
+
+// ProjectUpdate changes a project; empty fields are left as they are.
+type ProjectUpdate struct {
+	ID          string // empty starts a new project
+	Title       string
+	Description string
+	Status      string
+	// Collaborators replaces the list when non-nil.
+	Collaborators []string
+	Progress      string
+}
+
+// UpdateProject starts or updates a project and returns it.
+func (a *Agenda) UpdateProject(u ProjectUpdate, at time.Time) (Project, error) {
+	st, err := parseAgendaStatus(u.Status)
+	if err != nil {
+		return Project{}, err
+	}
+	var p *Project
+	if u.ID == "" {
+		if strings.TrimSpace(u.Title) == "" {
+			return Project{}, errors.New("project title is required")
+		}
+		if (st == "" || st.Open()) && countOpen(a.Projects, func(p Project) AgendaStatus { return p.Status }) >= MaxOpenProjects {
+			return Project{}, fmt.Errorf("at most %d open projects; finish or abandon one first", MaxOpenProjects)
+		}
+		a.Seq++
+		a.Projects = append(a.Projects, Project{ID: fmt.Sprintf("project-%d", a.Seq), Status: AgendaActive, StartedAt: at})
+		p = &a.Projects[len(a.Projects)-1]
+	} else {
+		i := slices.IndexFunc(a.Projects, func(p Project) bool { return p.ID == u.ID })
+		if i < 0 {
+			return Project{}, fmt.Errorf("project not found: %s", u.ID)
+		}
+		p = &a.Projects[i]
+		if st.Open() && !p.Status.Open() && countOpen(a.Projects, func(p Project) AgendaStatus { return p.Status }) >= MaxOpenProjects {
+			return Project{}, fmt.Errorf("at most %d open projects; finish or abandon one first", MaxOpenProjects)
+		}
+	}
+	if s := strings.TrimSpace(u.Title); s != "" {
+		p.Title = s
+	}
+	if s := strings.TrimSpace(u.Description); s != "" {
+		p.Description = s
+	}
+	if st != "" {
+		p.Status = st
+	}
+	if u.Collaborators != nil {
+		p.Collaborators = nil
+		for _, c := range u.Collaborators {
+			if c = strings.TrimSpace(c); c != "" && !slices.Contains(p.Collaborators, c) {
+				p.Collaborators = append(p.Collaborators, c)
+			}
+		}
+	}
+	if s := strings.TrimSpace(u.Progress); s != "" {
+		p.Progress = s
+	}
+	p.UpdatedAt = at
+	out := *p
+	a.touch(at)
+	return out, nil
+}
+
+// touch records a change and drops the oldest closed entries over the cap.
+func (a *Agenda) touch(at time.Time) {
+	a.UpdatedAt = at
+	a.Goals = dropOldClosed(a.Goals, func(g Goal) AgendaStatus { return g.Status })
+	a.Projects = dropOldClosed(a.Projects, func(p Project) AgendaStatus { return p.Status })
+}
+
+func countOpen[T any](items []T, status func(T) AgendaStatus) int {
+	n := 0
+	for _, item := range items {
+		if status(item).Open() {
+			n++
+		}
+	}
+	return n
+}
+
+func dropOldClosed[T any](items []T, status func(T) AgendaStatus) []T {
+	extra := len(items) - countOpen(items, status) - MaxClosedAgenda
+	if extra <= 0 {
+		return items
+	}
+	return slices.DeleteFunc(items, func(item T) bool {
+		if extra > 0 && !status(item).Open() {
+			extra--
+			return true
+		}
+		return false
+	})
+}
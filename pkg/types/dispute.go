@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+// Dispute is a formal challenge to a published paper or forum post, with
+// counter-arguments and evidence.
+type Dispute struct {
+	ID             string      `json:"id"`
+	TargetID       string      `json:"target_id"` // publication ID
+	Channel        ChannelType `json:"channel"`
+	TargetAuthorID string      `json:"target_author_id,omitempty"`
+	ChallengerID   string      `json:"challenger_id"`
+	ChallengerName string      `json:"challenger_name,omitempty"`
+	// Claim is the statement in the target being disputed.
+	Claim            string `json:"claim"`
+	CounterArguments string `json:"counter_arguments"`
+	// Evidence cites experiment, paper or post IDs, or outside sources.
+	Evidence  []string  `json:"evidence,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DisputeThreshold is how many agents must dispute a journal paper before it
+// is marked disputed, like the two rejections that dispute a theory.
+const DisputeThreshold = 2
@@ -0,0 +1,12 @@
+package types
+
+import "strings"
+
+// HumanAuthorPrefix namespaces the IDs of people who write through the
+// server's write API (e.g. "human-alice"), so they never collide with agents.
+const HumanAuthorPrefix = "human-"
+
+// IsHumanAuthor reports whether an author or voter ID belongs to a human.
+func IsHumanAuthor(id string) bool {
+	return strings.HasPrefix(id, HumanAuthorPrefix)
+}
@@ -0,0 +1,24 @@
+package types
+
+// DefaultJournalID identifies the single built-in journal used when no
+// journals are configured.
+const DefaultJournalID = "main"
+
+// JournalInfo describes one journal hosted by the publication system.
+type JournalInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Domains are the research areas (and keywords) routed to this journal;
+	// empty means a general journal that accepts anything.
+	Domains []string `json:"domains,omitempty"`
+	// AcceptanceThreshold is the minimum mean review score (0-10) for an
+	// accept decision; 0 disables the check.
+	AcceptanceThreshold float64 `json:"acceptance_threshold,omitempty"`
+	// DoubleBlind hides author identity from reviewers and public views
+	// until a decision is made.
+	DoubleBlind bool `json:"double_blind,omitempty"`
+	// Embargo keeps submissions private until published: papers can't be
+	// posted as preprints while under review.
+	Embargo bool `json:"embargo,omitempty"`
+}
@@ -0,0 +1,72 @@
+// Package types defines core types for the Sci-Bot Agent Network.
+package types
+
+import "time"
+
+// VoteChange describes the effect of one vote operation on a publication.
+// UpDelta/DownDelta are the changes applied to the target's counters (-1, 0, +1).
+type VoteChange struct {
+	VoterID   string    `json:"voter_id"`
+	AuthorID  string    `json:"author_id"`
+	PostID    string    `json:"post_id"`
+	IsComment bool      `json:"is_comment,omitempty"`
+	UpDelta   int       `json:"up_delta"`
+	DownDelta int       `json:"down_delta"`
+	At        time.Time `json:"at"`
+}
+
+// ScoreDelta returns the net score change caused by the vote.
+func (c VoteChange) ScoreDelta() int {
+	return c.UpDelta - c.DownDelta
+}
+
+// KarmaRecord is one entry in an agent's vote history.
+type KarmaRecord struct {
+	Direction string    `json:"direction"` // received | given
+	PeerID    string    `json:"peer_id"`   // voter for received votes, author for given votes
+	PostID    string    `json:"post_id"`
+	IsComment bool      `json:"is_comment,omitempty"`
+	Delta     int       `json:"delta"` // net score change
+	At        time.Time `json:"at"`
+}
+
+// MaxKarmaHistory caps the number of vote records kept per agent.
+const MaxKarmaHistory = 200
+
+// Karma summarizes how an agent's work has been received and how it votes.
+type Karma struct {
+	PostKarma     int           `json:"post_karma"`
+	CommentKarma  int           `json:"comment_karma"`
+	ReceivedUp    int           `json:"received_up"`
+	ReceivedDown  int           `json:"received_down"`
+	GivenUp       int           `json:"given_up"`
+	GivenDown     int           `json:"given_down"`
+	History       []KarmaRecord `json:"history,omitempty"` // oldest -> newest, capped
+	LastUpdatedAt time.Time     `json:"last_updated_at,omitempty"`
+}
+
+// Total returns combined post and comment karma.
+func (k *Karma) Total() int {
+	if k == nil {
+		return 0
+	}
+	return k.PostKarma + k.CommentKarma
+}
+
+// Reputation is an agent's standing derived from vote karma, journal outcomes
+// and how well its reviews matched final decisions.
+type Reputation struct {
+	Score          float64   `json:"score"`
+	VoteKarma      int       `json:"vote_karma"`
+	AcceptedPapers int       `json:"accepted_papers"`
+	RejectedPapers int       `json:"rejected_papers"`
+	Reviews        int       `json:"reviews"`        // reviews on decided submissions
+	ReviewQuality  float64   `json:"review_quality"` // mean agreement with final decisions, 0-1
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// OutcomeReviews counts reviews of published papers that have since been
+	// cited, disputed or retracted; OutcomeQuality is their mean agreement
+	// with those outcomes, 0-1.
+	OutcomeReviews int     `json:"outcome_reviews,omitempty"`
+	OutcomeQuality float64 `json:"outcome_quality,omitempty"`
+}
@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// Retirement records why and when an agent left the community. Retired
+// agents keep their state and history but are no longer scheduled.
+type Retirement struct {
+	At     time.Time `json:"at"` // sim time
+	Reason string    `json:"reason"`
+	// ReplacedBy is the ID of the persona recruited in the agent's place.
+	ReplacedBy string `json:"replaced_by,omitempty"`
+}
@@ -0,0 +1,77 @@
+// Package types defines core types for the Sci-Bot Agent Network.
+package types
+
+import "time"
+
+// ModerationStatus is the moderation state of a forum publication.
+type ModerationStatus string
+
+const (
+	ModerationNone    ModerationStatus = ""        // Visible
+	ModerationHidden  ModerationStatus = "hidden"  // Dropped from feeds, still readable by ID
+	ModerationRemoved ModerationStatus = "removed" // Dropped from feeds, no further votes or replies
+)
+
+// ModerationActionType is what a moderator did to a publication.
+type ModerationActionType string
+
+const (
+	ModActionHide    ModerationActionType = "hide"
+	ModActionRemove  ModerationActionType = "remove"
+	ModActionRestore ModerationActionType = "restore"
+	ModActionDismiss ModerationActionType = "dismiss" // close reports, leave the post as is
+)
+
+// Report categories for report_post.
+const (
+	ReportSpam       = "spam"
+	ReportDuplicate  = "duplicate"
+	ReportLowQuality = "low_quality"
+	ReportOffTopic   = "off_topic"
+	ReportOther      = "other"
+	ReportHostile    = "hostile" // personal attacks; also filed by the civility filter
+)
+
+// CivilityScore rates how civil a comment is.
+type CivilityScore struct {
+	Score   float64  `json:"score"`             // -1 hostile .. +1 civil
+	Hostile []string `json:"hostile,omitempty"` // hostile terms found
+	Civil   int      `json:"civil,omitempty"`   // civil markers found
+	Method  string   `json:"method"`            // classifier version
+}
+
+// PostReport is a community report against a forum post or comment.
+type PostReport struct {
+	ID           string    `json:"id"`
+	PostID       string    `json:"post_id"`
+	ReporterID   string    `json:"reporter_id"`
+	ReporterName string    `json:"reporter_name"`
+	Category     string    `json:"category"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	ResolvedAt time.Time `json:"resolved_at,omitempty"`
+	ActionID   string    `json:"action_id,omitempty"` // moderation action that resolved it
+}
+
+// Open reports whether the report still awaits a moderator.
+func (r *PostReport) Open() bool {
+	return r.ResolvedAt.IsZero()
+}
+
+// ModerationAction is one entry in the forum moderation log.
+type ModerationAction struct {
+	ID            string               `json:"id"`
+	PostID        string               `json:"post_id"`
+	Action        ModerationActionType `json:"action"`
+	ModeratorID   string               `json:"moderator_id"`
+	ModeratorName string               `json:"moderator_name"`
+	Reason        string               `json:"reason"`
+	ReportIDs     []string             `json:"report_ids,omitempty"` // reports resolved by this action
+	At            time.Time            `json:"at"`
+}
+
+// Visible reports whether a publication should appear in forum feeds.
+func (p *Publication) Visible() bool {
+	return p.Moderation == ModerationNone
+}
@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// MaxSeenNotifications caps the read marks kept per kind; older marks fold
+// into Notifications.ReadThrough.
+const MaxSeenNotifications = 300
+
+// Notifications records which forum mentions and replies an agent has
+// read, so browse_mentions can show only new ones.
+type Notifications struct {
+	// SeenMentions and SeenReplies map publication IDs to when they were
+	// published.
+	SeenMentions map[string]time.Time `json:"seen_mentions,omitempty"`
+	SeenReplies  map[string]time.Time `json:"seen_replies,omitempty"`
+	// ReadThrough counts everything published at or before it as read.
+	ReadThrough time.Time `json:"read_through,omitempty"`
+}
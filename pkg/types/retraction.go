@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+// RetractionRequest collects reviewer and editor votes to retract a
+// published journal paper.
+type RetractionRequest struct {
+	PaperID string            `json:"paper_id"`
+	Votes   []*RetractionVote `json:"votes"`
+	// RetractedAt is set once the votes reached RetractionQuorum.
+	RetractedAt time.Time `json:"retracted_at,omitzero"`
+}
+
+// RetractionVote is one agent's call to retract a paper.
+type RetractionVote struct {
+	AgentID   string    `json:"agent_id"`
+	AgentName string    `json:"agent_name,omitempty"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RetractionQuorum is how many reviewers or editors must call for a
+// retraction before the paper is retracted.
+const RetractionQuorum = 2
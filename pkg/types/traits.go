@@ -0,0 +1,57 @@
+package types
+
+import "time"
+
+// Traits are the persona traits that drift with an agent's outcomes.
+type Traits struct {
+	Creativity  float64 `json:"creativity"`
+	Rigor       float64 `json:"rigor"`
+	Sociability float64 `json:"sociability"`
+	Influence   float64 `json:"influence"`
+}
+
+// Traits returns the persona's current evolvable traits.
+func (p *Persona) Traits() Traits {
+	return Traits{Creativity: p.Creativity, Rigor: p.Rigor, Sociability: p.Sociability, Influence: p.Influence}
+}
+
+// SetTraits overwrites the persona's evolvable traits.
+func (p *Persona) SetTraits(t Traits) {
+	p.Creativity, p.Rigor, p.Sociability, p.Influence = t.Creativity, t.Rigor, t.Sociability, t.Influence
+}
+
+// TraitOutcomes counts the outcomes already reflected in an agent's traits.
+type TraitOutcomes struct {
+	Accepted       int `json:"accepted"`
+	Rejected       int `json:"rejected"`
+	Collaborations int `json:"collaborations"`
+	// RejectionStreak is the number of rejections since the last acceptance.
+	RejectionStreak int `json:"rejection_streak"`
+}
+
+// TraitChange is one entry in an agent's trait history.
+type TraitChange struct {
+	At     time.Time `json:"at"` // sim time
+	Traits Traits    `json:"traits"`
+	Reason string    `json:"reason"`
+}
+
+// MaxTraitHistory caps the number of trait changes kept per agent.
+const MaxTraitHistory = 200
+
+// TraitRecord is an agent's evolving traits: the current values, the
+// outcomes they reflect and how they got there.
+type TraitRecord struct {
+	Current  Traits        `json:"current"`
+	Outcomes TraitOutcomes `json:"outcomes"`
+	History  []TraitChange `json:"history,omitempty"` // oldest -> newest, capped
+}
+
+// Record sets the current traits and appends them to the history.
+func (r *TraitRecord) Record(at time.Time, t Traits, reason string) {
+	r.Current = t
+	r.History = append(r.History, TraitChange{At: at, Traits: t, Reason: reason})
+	if len(r.History) > MaxTraitHistory {
+		r.History = r.History[len(r.History)-MaxTraitHistory:]
+	}
+}
@@ -12,6 +12,7 @@ const (
 	RoleReviewer     AgentRole = "reviewer"     // Critical review and validation
 	RoleSynthesizer  AgentRole = "synthesizer"  // Cross-domain synthesis
 	RoleCommunicator AgentRole = "communicator" // Knowledge dissemination
+	RoleEditor       AgentRole = "editor"       // Journal triage: reviewer assignment, desk rejection
 )
 
 // ThinkingStyle defines the cognitive style of an agent.
@@ -44,6 +45,17 @@ type Persona struct {
 	// Social characteristics
 	Sociability float64 `json:"sociability"` // Social activity level
 	Influence   float64 `json:"influence"`   // Influence index
+
+	// Moderator grants forum moderation (hide/remove posts, handle reports).
+	Moderator bool `json:"moderator,omitempty"`
+
+	// Model optionally overrides the LLM model spec for this agent
+	// (e.g. "anthropic:claude-sonnet-4-5"); empty uses the role default.
+	Model string `json:"model,omitempty"`
+
+	// Language optionally sets the agent's prompt language ("zh" or "en");
+	// empty follows the run's -lang setting.
+	Language string `json:"language,omitempty"`
 }
 
 // MessageType defines the type of message in the network.
@@ -256,6 +268,9 @@ type KnowledgeItem struct {
 	LastReviewed time.Time      `json:"last_reviewed"`
 	Confidence   float64        `json:"confidence"` // 0-1, how confident in this knowledge
 	Source       string         `json:"source"`     // Where they learned it from
+	// SourcePostID is the post the agent first heard of the theory in;
+	// with Source it is one edge of the theory's propagation tree.
+	SourcePostID string `json:"source_post_id,omitempty"`
 }
 
 // Connection represents a social connection between agents (legacy, use Relationship instead).
@@ -287,6 +302,9 @@ type ChannelType string
 const (
 	ChannelJournal ChannelType = "journal" // 权威杂志，需审核
 	ChannelForum   ChannelType = "forum"   // 草根论坛，自由发布
+	// ChannelPreprint marks forum posts that publish a paper while it is
+	// under journal review (see Publication.PaperID).
+	ChannelPreprint ChannelType = "preprint" // 预印本，审稿期间在论坛公开
 )
 
 // Subreddit defines forum subject areas (like Reddit subreddits).
@@ -301,11 +319,31 @@ const (
 	SubGeneral     Subreddit = "general"     // 通用讨论
 )
 
-// AllSubreddits returns all available subreddits.
+// AllSubreddits returns all built-in subreddits.
 func AllSubreddits() []Subreddit {
 	return []Subreddit{SubMathematics, SubPhysics, SubPhilosophy, SubBiology, SubComputing, SubGeneral}
 }
 
+// IsBuiltinSubreddit reports whether sub is one of the built-in subreddits.
+func IsBuiltinSubreddit(sub Subreddit) bool {
+	for _, s := range AllSubreddits() {
+		if s == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// SubredditInfo describes a subreddit in the forum registry.
+type SubredditInfo struct {
+	Name        Subreddit `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatorID   string    `json:"creator_id,omitempty"` // empty for built-ins
+	CreatorName string    `json:"creator_name,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	BuiltIn     bool      `json:"built_in,omitempty"`
+}
+
 // Publication represents a published work.
 type Publication struct {
 	ID          string      `json:"id"`
@@ -328,13 +366,118 @@ type Publication struct {
 	IsComment bool      `json:"is_comment,omitempty"`
 	Mentions  []string  `json:"mentions,omitempty"`
 
+	// Moderation
+	Moderation       ModerationStatus `json:"moderation,omitempty"`
+	ModerationReason string           `json:"moderation_reason,omitempty"`
+	Civility         *CivilityScore   `json:"civility,omitempty"` // comments only
+
 	// Journal specific
+	JournalID string   `json:"journal_id,omitempty"` // empty means the default journal
 	Reviewers []string `json:"reviewers,omitempty"`
 	Approved  bool     `json:"approved,omitempty"`
 
+	// Rejected archive / resubmission
+	RejectedAt        time.Time `json:"rejected_at,omitempty"`
+	DecisionRationale string    `json:"decision_rationale,omitempty"`
+	ResubmissionOf    string    `json:"resubmission_of,omitempty"` // ID of an earlier rejected submission
+
+	// Preprints: PreprintID on a journal paper is the forum post it was
+	// preprinted as; PaperID on a preprint is the journal submission.
+	PreprintID string `json:"preprint_id,omitempty"`
+	PaperID    string `json:"paper_id,omitempty"`
+
+	// Forum threads that discussed the paper's ideas; RelatedAt is when they were computed
+	RelatedThreads []RelatedThread `json:"related_threads,omitempty"`
+	RelatedAt      time.Time       `json:"related_at,omitempty"`
+
+	// Virtual experiments cited as evidence (experiment IDs)
+	Experiments []string `json:"experiments,omitempty"`
+
+	// Equations, tables and figures that accompany the text
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Provenance marks content that did not come from a community member
+	// (ProvenanceExogenous); Source cites the original work.
+	Provenance string `json:"provenance,omitempty"`
+	Source     string `json:"source,omitempty"`
+
 	// Stats
 	Views    int `json:"views"`
 	Comments int `json:"comments"` // Number of comments/replies
+
+	// Disputed marks a journal paper that DisputeThreshold agents formally
+	// challenged (see Dispute), as StatusDisputed does for theories.
+	Disputed   bool      `json:"disputed,omitempty"`
+	DisputedAt time.Time `json:"disputed_at,omitzero"`
+
+	// Retracted marks a journal paper withdrawn after RetractionQuorum
+	// reviewers or editors voted for it. The paper stays published so the
+	// notice remains visible.
+	Retracted        bool      `json:"retracted,omitempty"`
+	RetractedAt      time.Time `json:"retracted_at,omitzero"`
+	RetractionReason string    `json:"retraction_reason,omitempty"`
+	RetractedBy      string    `json:"retracted_by,omitempty"`
+
+	// DuplicateOf is the existing forum thread a new post closely matches,
+	// with the estimated similarity, as flagged by the duplicate filter.
+	DuplicateOf    string  `json:"duplicate_of,omitempty"`
+	DuplicateScore float64 `json:"duplicate_score,omitempty"`
+
+	// Rendered Markdown for API responses, set on copies by the server and
+	// never stored: sanitized HTML of Content and Abstract, and a plain-text
+	// excerpt of the abstract (or content).
+	ContentHTML  string `json:"content_html,omitempty"`
+	AbstractHTML string `json:"abstract_html,omitempty"`
+	Excerpt      string `json:"excerpt,omitempty"`
+}
+
+// ProvenanceExogenous marks publications injected from outside the simulated
+// community, such as scenario literature drops.
+const ProvenanceExogenous = "exogenous"
+
+// Exogenous reports whether the publication was injected from outside.
+func (p *Publication) Exogenous() bool {
+	return p.Provenance == ProvenanceExogenous
+}
+
+// RelatedThread links a journal paper to a forum thread (root post).
+type RelatedThread struct {
+	PostID     string    `json:"post_id"`
+	Title      string    `json:"title"`
+	AuthorName string    `json:"author_name"`
+	Subreddit  Subreddit `json:"subreddit,omitempty"`
+	Reason     string    `json:"reason"` // source | consensus | cited | similar
+	Score      float64   `json:"score"`  // 1 for explicit links, text similarity otherwise
+}
+
+// AttachmentKind is the type of a publication attachment.
+type AttachmentKind string
+
+const (
+	AttachmentMath  AttachmentKind = "math"  // LaTeX display equation
+	AttachmentTable AttachmentKind = "table" // small data table
+	AttachmentPlot  AttachmentKind = "plot"  // SVG plot of experiment results
+)
+
+// Attachment is an equation, table or figure carried with a publication.
+// The text refers to it by ID (eq-1, table-1, fig-1).
+type Attachment struct {
+	ID      string         `json:"id"`
+	Kind    AttachmentKind `json:"kind"`
+	Caption string         `json:"caption,omitempty"`
+
+	// Math
+	Latex string `json:"latex,omitempty"`
+
+	// Table
+	Columns []string   `json:"columns,omitempty"`
+	Rows    [][]string `json:"rows,omitempty"`
+
+	// Plot: Y against X over the listed experiments, rendered server-side
+	Experiments []string `json:"experiments,omitempty"`
+	X           string   `json:"x,omitempty"`
+	Y           string   `json:"y,omitempty"`
+	SVG         string   `json:"svg,omitempty"`
 }
 
 type DraftKind string
@@ -364,6 +507,27 @@ type Draft struct {
 	ConsensusID  string      `json:"consensus_id,omitempty"`
 	CreatedAt    time.Time   `json:"created_at"`
 	UpdatedAt    time.Time   `json:"updated_at"`
+
+	// Co-authoring: every edit, attributed to its author.
+	Contributions []DraftContribution `json:"contributions,omitempty"`
+}
+
+// DraftContribution records one edit to a draft.
+type DraftContribution struct {
+	AuthorID string    `json:"author_id"`
+	Mode     string    `json:"mode"` // create | append | replace
+	Section  string    `json:"section,omitempty"`
+	Chars    int       `json:"chars"` // characters written
+	At       time.Time `json:"at"`
+}
+
+// CharsByAuthor totals the characters each author has written.
+func (d *Draft) CharsByAuthor() map[string]int {
+	out := make(map[string]int)
+	for _, c := range d.Contributions {
+		out[c.AuthorID] += c.Chars
+	}
+	return out
 }
 
 type ConsensusStatus string
@@ -387,6 +551,22 @@ type ConsensusRequest struct {
 	Supporters    []string        `json:"supporters,omitempty"`
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
+
+	// Voting
+	Opponents  []string  `json:"opponents,omitempty"`
+	AchievedAt time.Time `json:"achieved_at,omitempty"`
+
+	// History lists the status changes, oldest first.
+	History []StatusTransition `json:"history,omitempty"`
+}
+
+// StatusTransition records one status change of a submission or consensus
+// request: who made it and when. From is empty for the initial status.
+type StatusTransition struct {
+	From  string    `json:"from,omitempty"`
+	To    string    `json:"to"`
+	Actor string    `json:"actor,omitempty"`
+	At    time.Time `json:"at"`
 }
 
 type SubmissionStatus string
@@ -408,10 +588,49 @@ type Submission struct {
 	Content   string           `json:"content"`
 	AuthorID  string           `json:"author_id"`
 	AuthorName string          `json:"author_name"`
+	JournalID string           `json:"journal_id,omitempty"`
 	Status    SubmissionStatus `json:"status"`
 	ReviewIDs []string         `json:"review_ids,omitempty"`
 	CreatedAt time.Time        `json:"created_at"`
 	UpdatedAt time.Time        `json:"updated_at"`
+
+	// Review cycle (sim time). Empty when scheduled review is disabled.
+	CycleID           string    `json:"cycle_id,omitempty"`
+	AssignedReviewers []string  `json:"assigned_reviewers,omitempty"`
+	DecisionDue       time.Time `json:"decision_due,omitempty"`
+	DecidedAt         time.Time `json:"decided_at,omitempty"`
+	DecidedBy         string    `json:"decided_by,omitempty"`
+	Escalated         bool      `json:"escalated,omitempty"` // decided by the editor after the deadline
+	DecisionRationale string    `json:"decision_rationale,omitempty"`
+	ResubmissionOf    string    `json:"resubmission_of,omitempty"`
+	PreprintID        string    `json:"preprint_id,omitempty"` // forum post of the preprint
+
+	// Editor triage (sim time). AssignedBy is empty for automatic cycle
+	// assignment.
+	AssignedBy   string           `json:"assigned_by,omitempty"`
+	AssignedAt   time.Time        `json:"assigned_at,omitempty"`
+	DeskRejected bool             `json:"desk_rejected,omitempty"`
+	Reminders    []ReviewReminder `json:"reminders,omitempty"`
+
+	// History lists the status changes, oldest first.
+	History []StatusTransition `json:"history,omitempty"`
+}
+
+// ReviewReminder records an editor nagging an assigned reviewer.
+type ReviewReminder struct {
+	ReviewerID string    `json:"reviewer_id"`
+	EditorID   string    `json:"editor_id"`
+	Note       string    `json:"note,omitempty"`
+	At         time.Time `json:"at"` // sim time
+}
+
+// ReviewCycle batches submissions received before a cutoff; they must be
+// decided by DecisionDue (the next cutoff).
+type ReviewCycle struct {
+	ID            string    `json:"id"`
+	Cutoff        time.Time `json:"cutoff"`
+	DecisionDue   time.Time `json:"decision_due"`
+	SubmissionIDs []string  `json:"submission_ids,omitempty"`
 }
 
 type PaperReviewVerdict string
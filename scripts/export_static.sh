@@ -9,6 +9,7 @@ WEB_DIR="./web"
 OUT_DIR="./public"
 REBUILD_FEED=1
 SITE_BASE_URL="${SITE_BASE_URL:-https://cpunion.github.io/sci-bot/}"
+SERVER_ADDR="${SERVER_ADDR:-127.0.0.1:8097}"
 
 usage() {
   cat <<'EOF'
@@ -17,13 +18,15 @@ export_static.sh
 Export the static site (HTML + assets + data/) into an output directory so it
 can be hosted on GitHub Pages or any static file server.
 
-Also generates `sitemap.xml` in the output directory.
+The data includes every /api response, rendered by cmd/export_site from a
+temporary cmd/server. Also generates `sitemap.xml` in the output directory.
 
 Usage:
   scripts/export_static.sh [-data <dir>] [-web <dir>] [-out <dir>] [-no-rebuild-feed]
 
 Environment variables:
   SITE_BASE_URL=https://cpunion.github.io/sci-bot/
+  SERVER_ADDR=127.0.0.1:8097   (temporary server used for the export)
 
 Defaults:
   -data ./data/adk-simulation
@@ -78,27 +81,22 @@ fi
 go run ./cmd/index_data "${INDEX_ARGS[@]}"
 
 rm -rf "$OUT_DIR"
-mkdir -p "$OUT_DIR/assets" "$OUT_DIR/data"
 
-rsync -a "$WEB_DIR"/*.html "$OUT_DIR"/
-rsync -a --delete "$WEB_DIR"/assets/ "$OUT_DIR"/assets/
-
-# Copy only the files the static UI needs (avoid publishing rebuild backups).
-rsync -a --delete "$DATA_DIR"/agents/ "$OUT_DIR"/data/agents/
-rsync -a --delete "$DATA_DIR"/forum/ "$OUT_DIR"/data/forum/
-rsync -a --delete "$DATA_DIR"/journal/ "$OUT_DIR"/data/journal/
-rsync -a --delete "$DATA_DIR"/feed/ "$OUT_DIR"/data/feed/
-rsync -a "$DATA_DIR"/site.json "$OUT_DIR"/data/site.json
-
-if [[ -f "$DATA_DIR/sim_state.json" ]]; then
-  rsync -a "$DATA_DIR"/sim_state.json "$OUT_DIR"/data/sim_state.json
-fi
-
-shopt -s nullglob
-for f in "$DATA_DIR"/logs*.jsonl; do
-  rsync -a "$f" "$OUT_DIR"/data/
+# Export through a temporary server so the static site gets the same
+# responses as /api (cmd/export_site also copies the pages and raw data).
+BUILD_DIR="$(mktemp -d)"
+go build -o "$BUILD_DIR/server" ./cmd/server
+"$BUILD_DIR/server" -addr "$SERVER_ADDR" -data "$DATA_DIR" -web "$WEB_DIR" -reload-interval 0 >"$BUILD_DIR/server.log" 2>&1 &
+SERVER_PID=$!
+trap 'kill "$SERVER_PID" 2>/dev/null || true; rm -rf "$BUILD_DIR"' EXIT
+for _ in $(seq 1 50); do
+  if curl -fsS "http://$SERVER_ADDR/api/stats" >/dev/null 2>&1; then
+    break
+  fi
+  sleep 0.2
 done
-shopt -u nullglob
+
+go run ./cmd/export_site -server "http://$SERVER_ADDR" -data "$DATA_DIR" -web "$WEB_DIR" -out "$OUT_DIR" -base "$SITE_BASE_URL"
 
 touch "$OUT_DIR/.nojekyll"
 
@@ -1,6 +1,8 @@
 import {
+  agentProfileURL,
   fetchJSON,
   fetchJSONL,
+  fetchText,
   forumCommentURL,
   forumPostURL,
   loadAgents,
@@ -8,7 +10,7 @@ import {
   paperURL,
   resolveAgentID,
 } from "./data.js";
-import { renderMarkdown, typesetMath } from "./markdown.js";
+import { renderMarkdown, renderSummary, typesetMath } from "./markdown.js";
 
 const root = document.getElementById("agent-root");
 
@@ -96,7 +98,13 @@ const renderAgent = (detail) => {
   const journalApproved = detail.journal_approved || [];
   const journalPending = detail.journal_pending || [];
   const dailyNotes = detail.daily_notes || [];
+  const groups = detail.groups || [];
   const dailyIndexOK = Boolean(detail.daily_index_ok);
+  const activity = detail.activity || [];
+  const researchNotes = detail.research_notes || [];
+  const agenda = detail.agenda || {};
+  const reputation = detail.reputation || agent.reputation || null;
+  const activityIndexOK = Boolean(detail.activity_index_ok);
 
   root.innerHTML = `
     <div class="agent-hero">
@@ -114,6 +122,36 @@ const renderAgent = (detail) => {
       </div>
     </div>
 
+    <section class="feed-section">
+      <h3>Recent Activity</h3>
+      ${
+        !activityIndexOK
+          ? `<div class="empty">Agent feed index missing. Run <code>go run ./cmd/index_data -data ./data/adk-simulation -rebuild-feed</code> to generate <code>feed/agents/</code>.</div>`
+          : activity.length
+            ? activity.map(renderActivity).join("")
+            : `<div class="empty">No activity yet.</div>`
+      }
+    </section>
+
+    ${
+      reputation && (reputation.reviews || reputation.outcome_reviews)
+        ? `<section class="feed-section">
+      <h3>Review Record</h3>
+      ${renderReviewRecord(reputation)}
+    </section>`
+        : ""
+    }
+
+    <section class="feed-section">
+      <h3>Research Agenda</h3>
+      ${renderAgenda(agenda)}
+    </section>
+
+    <section class="feed-section">
+      <h3>Research Notes</h3>
+      ${researchNotes.length ? researchNotes.map(renderResearchNote).join("") : `<div class="empty">No research notes yet.</div>`}
+    </section>
+
     <section class="feed-section">
       <h3>Forum Posts</h3>
       ${forumPosts.length ? forumPosts.map(renderFeedItem).join("") : `<div class="empty">No forum posts yet.</div>`}
@@ -129,15 +167,25 @@ const renderAgent = (detail) => {
       ${renderJournalSection(journalApproved, journalPending)}
     </section>
 
+    <section class="feed-section">
+      <h3>Research Groups</h3>
+      ${groups.length ? groups.map(renderGroup).join("") : `<div class="empty">Not in any research group.</div>`}
+    </section>
+
     <section class="feed-section">
       <h3>Daily Notes</h3>
       ${
         !dailyIndexOK
           ? `<div class="empty">Daily notes index missing. Run <code>go run ./cmd/index_data -data ./data/adk-simulation</code> to generate <code>daily/index.json</code>.</div>`
           : dailyNotes.length
-            ? dailyNotes.map(renderNote).join("")
+            ? `<div id="daily-notes">${dailyNotes.map(renderNote).join("")}</div>`
             : `<div class="empty">No public notes yet.</div>`
       }
+      ${
+        (detail.daily_older || []).length
+          ? `<div class="feed-actions"><button class="tab-btn" id="daily-older" type="button">Older notes</button></div>`
+          : ""
+      }
     </section>
   `;
   typesetMath(root);
@@ -168,7 +216,121 @@ const renderFeedItem = (item) => {
     <div class="feed-item">
       <h4>${url ? `<a href="${escapeHTML(url)}">${title}</a>` : title}</h4>
       <small>${escapeHTML(formatTime(item.published_at))} • ${item.subreddit ? `r/${escapeHTML(item.subreddit)}` : ""}</small>
-      <div class="md">${renderMarkdown(item.abstract || item.content || "")}</div>
+      <div class="md">${renderSummary(item)}</div>
+    </div>
+  `;
+};
+
+const renderActivity = (ev) => `
+    <div class="daily-entry">
+      <div class="daily-header">
+        <span class="daily-time">${escapeHTML(formatDateTime(ev.sim_time || ev.timestamp))}${Number.isFinite(ev.tick) ? ` • tick ${ev.tick}` : ""}</span>
+        <div class="daily-summary post-meta">
+          ${escapeHTML(ev.action || "action")}${ev.failed ? `<span class="turn-failed"> · failed</span>` : ""}
+        </div>
+      </div>
+      ${ev.response ? `<div class="post-meta">${escapeHTML(clip(ev.response, 280))}</div>` : ""}
+    </div>
+  `;
+
+const renderResearchNote = (note) => {
+  const questions = note.open_questions || [];
+  return `
+    <div class="feed-item">
+      <h4>${escapeHTML(note.topic || "Note")}</h4>
+      <small>${escapeHTML(formatDateTime(note.at))}</small>
+      <div class="md">${renderMarkdown(note.insight || "")}</div>
+      ${
+        questions.length
+          ? `<div class="daily-label">Open Questions</div><div class="md">${renderMarkdown(questions.map((q) => `- ${q}`).join("\n"))}</div>`
+          : ""
+      }
+    </div>
+  `;
+};
+
+// renderReviewRecord shows how well the agent's verdicts matched editorial
+// decisions and, later, how the papers it reviewed fared.
+const renderReviewRecord = (rep) => {
+  const pct = (v) => `${Math.round((v || 0) * 100)}%`;
+  return `
+    <div class="feed-item">
+      <div class="tag-row">
+        <span class="badge">${rep.reviews || 0} decided reviews</span>
+        <span class="badge">decision agreement ${pct(rep.review_quality)}</span>
+      </div>
+      ${
+        rep.outcome_reviews
+          ? `<div class="tag-row">
+        <span class="badge">${rep.outcome_reviews} reviews with later outcomes</span>
+        <span class="badge">outcome quality ${pct(rep.outcome_quality)}</span>
+      </div>`
+          : `<small>No reviewed paper has been cited, disputed or retracted yet.</small>`
+      }
+    </div>
+  `;
+};
+
+// renderAgenda lists the agent's open goals and projects, then the finished
+// and abandoned ones.
+const renderAgenda = (agenda) => {
+  const isOpen = (item) => item.status === "active" || item.status === "paused";
+  const byOpen = (a, b) => Number(isOpen(b)) - Number(isOpen(a));
+  const goals = (agenda.goals || []).slice().sort(byOpen);
+  const projects = (agenda.projects || []).slice().sort(byOpen);
+  if (!goals.length && !projects.length) {
+    return `<div class="empty">No goals or projects yet.</div>`;
+  }
+  const status = (item) => `<span class="badge">${escapeHTML(item.status || "")}</span>`;
+  const parts = [];
+  if (goals.length) {
+    parts.push(`<h4>Goals</h4>`);
+    parts.push(
+      goals
+        .map(
+          (g) => `
+    <div class="feed-item">
+      <div class="tag-row">${status(g)}<small>${escapeHTML(formatDateTime(g.updated_at || g.set_at))}</small></div>
+      <div class="md">${renderMarkdown(g.text || "")}</div>
+    </div>
+  `,
+        )
+        .join(""),
+    );
+  }
+  if (projects.length) {
+    parts.push(`<h4>Projects</h4>`);
+    parts.push(
+      projects
+        .map(
+          (p) => `
+    <div class="feed-item">
+      <h4>${escapeHTML(p.title || p.id)}</h4>
+      <div class="tag-row">${status(p)}<small>since ${escapeHTML(formatDateTime(p.started_at))}</small></div>
+      ${p.description ? `<div class="md">${renderMarkdown(p.description)}</div>` : ""}
+      ${p.progress ? `<div class="daily-label">Progress</div><div class="md">${renderMarkdown(p.progress)}</div>` : ""}
+      ${
+        (p.collaborators || []).length
+          ? `<div class="tag-row">${p.collaborators.map((c) => `<a class="tag" href="${escapeHTML(agentProfileURL(c))}">${escapeHTML(c)}</a>`).join("")}</div>`
+          : ""
+      }
+    </div>
+  `,
+        )
+        .join(""),
+    );
+  }
+  return parts.join("");
+};
+
+const renderGroup = (group) => {
+  const members = (group.members || []).map((m) => escapeHTML(m.name || m.id));
+  const drafts = (group.drafts || []).length;
+  return `
+    <div class="feed-item">
+      <h4>${escapeHTML(group.name || group.id)}</h4>
+      <small>${escapeHTML(group.topic || "")} • ${members.length} members • ${drafts} shared drafts • ${group.message_count || 0} messages</small>
+      <div class="tag-row">${members.map((m) => `<span class="tag">${m}</span>`).join("")}</div>
     </div>
   `;
 };
@@ -202,7 +364,9 @@ const renderStructuredEntry = (entry) => {
       }
       ${
         entry.error
-          ? `<div class="daily-label">Error</div><div class="md">${renderMarkdown(String(entry.error))}</div>`
+          ? `<div class="daily-label turn-failed">${entry.error_kind === "tool_error" ? "Tool errors" : "Error"}${
+              entry.error_kind && entry.error_kind !== "tool_error" ? ` (${escapeHTML(entry.error_kind.replace(/_/g, " "))})` : ""
+            }</div><div class="md">${renderMarkdown(String(entry.error))}</div>`
           : ""
       }
       ${
@@ -294,6 +458,16 @@ const init = async () => {
     pending.sort((a, b) => new Date(b.published_at || 0) - new Date(a.published_at || 0));
 
     const daily = await loadDailyNotes(resolvedID, 10);
+    const activity = await loadAgentActivity(manifest, resolvedID, 20);
+    const researchNotes = await loadResearchNotes(resolvedID, 20);
+    // The agenda lives in the agent's state.json (absent in older runs).
+    const state = await fetchJSON(`agents/${encodeURIComponent(resolvedID)}/state.json`).catch(() => null);
+
+    // Runs without research groups have no groups.json.
+    const groupsRaw = await fetchJSON("groups/groups.json").catch(() => null);
+    const groups = Object.values(groupsRaw?.groups || {})
+      .filter((g) => (g.members || []).some((m) => m.id === resolvedID))
+      .sort((a, b) => (b.members || []).length - (a.members || []).length);
 
     renderAgent({
       agent,
@@ -303,7 +477,15 @@ const init = async () => {
       journal_pending: pending,
       daily_notes: daily.notes,
       daily_index_ok: daily.index_ok,
+      daily_older: daily.older,
+      activity: activity.events,
+      activity_index_ok: activity.index_ok,
+      research_notes: researchNotes,
+      agenda: state?.agenda,
+      reputation: state?.reputation,
+      groups,
     });
+    bindOlderNotes(resolvedID, daily.older);
   } catch (err) {
     root.innerHTML = `<div class="empty">${err.message}</div>`;
   }
@@ -341,22 +523,115 @@ const loadDailyNotes = async (agentID, limitDays) => {
 
   if (indexedDates) {
     const sorted = indexedDates.slice().sort().reverse();
-    const selected = sorted.slice(0, days);
-    for (const dateKey of selected) {
+    const notes = await fetchDailyNotes(agentID, sorted.slice(0, days));
+    return { notes, index_ok: true, older: sorted.slice(days) };
+  }
+
+  // Do not probe guessed filenames here (it creates lots of 404s on static hosting).
+  return { notes: out, index_ok: false, older: [] };
+};
+
+// loadResearchNotes returns the agent's newest record_note notes, reading
+// the day files listed in notes/index.json (written by cmd/index_data /
+// adk_simulate) from the newest.
+const loadResearchNotes = async (agentID, limit) => {
+  const base = `agents/${encodeURIComponent(agentID)}/notes`;
+  let dates = [];
+  try {
+    const idx = await fetchJSON(`${base}/index.json`);
+    dates = (idx?.dates || []).filter((d) => typeof d === "string" && /^\d{4}-\d{2}-\d{2}$/.test(d));
+  } catch (_err) {
+    return [];
+  }
+  const out = [];
+  for (const dateKey of dates.sort().reverse()) {
+    if (out.length >= limit) break;
+    try {
+      const notes = await fetchJSONL(`${base}/${dateKey}.jsonl`);
+      out.push(...notes.reverse());
+    } catch (_err) {
+      // ignore stale index entries
+    }
+  }
+  return out.slice(0, limit);
+};
+
+const fetchDailyNotes = async (agentID, dates) => {
+  const out = [];
+  for (const dateKey of dates) {
+    try {
+      const entries = await fetchJSONL(`agents/${encodeURIComponent(agentID)}/daily/${dateKey}.jsonl`);
+      if (entries && entries.length) {
+        out.push({ date: dateKey, entries });
+      }
+    } catch (_err) {
+      // ignore stale index entries
+    }
+  }
+  return out;
+};
+
+// bindOlderNotes pages back through the daily notes archive, 10 days at a time.
+const bindOlderNotes = (agentID, older) => {
+  const btn = document.getElementById("daily-older");
+  const list = document.getElementById("daily-notes");
+  if (!btn || !list) return;
+  const remaining = older.slice();
+  btn.addEventListener("click", async () => {
+    btn.disabled = true;
+    const notes = await fetchDailyNotes(agentID, remaining.splice(0, 10));
+    const holder = document.createElement("div");
+    holder.innerHTML = notes.map(renderNote).join("");
+    list.append(...holder.children);
+    typesetMath(list);
+    btn.disabled = false;
+    if (!remaining.length) btn.remove();
+  });
+};
+
+// loadAgentActivity reads the agent's newest feed events through the feed
+// store's per-agent index (feed/agents/<id>.json), fetching only the shards
+// that hold them instead of the whole feed.
+const loadAgentActivity = async (manifest, agentID, limit) => {
+  const indexPath = String(manifest?.feed_index_path || "").trim() || "feed/index.json";
+  const slash = indexPath.lastIndexOf("/");
+  const feedDir = slash > 0 ? `${indexPath.slice(0, slash)}/` : "";
+  let idx = null;
+  try {
+    idx = await fetchJSON(`${feedDir}agents/${encodeURIComponent(agentID)}.json`);
+  } catch (_err) {
+    return { events: [], index_ok: false };
+  }
+
+  const out = [];
+  const shards = Array.isArray(idx?.shards) ? idx.shards : [];
+  for (let i = shards.length - 1; i >= 0 && out.length < limit; i--) {
+    const shard = shards[i];
+    const wanted = new Set(shard.lines || []);
+    let text = "";
+    try {
+      text = await fetchText(`${feedDir}${shard.file}`);
+    } catch (_err) {
+      continue;
+    }
+    // Line numbers count every non-empty line, like the server's cursors.
+    const picked = [];
+    let line = -1;
+    for (const raw of text.split(/\r?\n/)) {
+      const trimmed = raw.trim();
+      if (!trimmed) continue;
+      line++;
+      if (!wanted.has(line)) continue;
       try {
-        const entries = await fetchJSONL(`agents/${encodeURIComponent(agentID)}/daily/${dateKey}.jsonl`);
-        if (entries && entries.length) {
-          out.push({ date: dateKey, entries });
-        }
+        const ev = JSON.parse(trimmed);
+        if (ev.action !== "heartbeat") picked.push(ev);
       } catch (_err) {
-        // ignore stale index entries
+        // ignore partial lines
       }
     }
-    return { notes: out, index_ok: true };
+    out.push(...picked.reverse());
   }
-
-  // Do not probe guessed filenames here (it creates lots of 404s on static hosting).
-  return { notes: out, index_ok: false };
+  return { events: out.slice(0, limit), index_ok: true };
 };
 
 init();
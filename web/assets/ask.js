@@ -0,0 +1,152 @@
+// Spectator questions: needs the Go server started with -inbox (the static
+// site has no write API). The server knows visitors by the address they
+// connect from; the questions asked here are remembered in localStorage.
+import { forumPostURL } from "./data.js";
+
+const content = document.getElementById("ask-content");
+const questionRoot = document.getElementById("ask-question");
+const tabs = document.getElementById("ask-tabs");
+const form = document.getElementById("ask-form");
+const statusLine = document.getElementById("ask-status");
+
+const MINE_KEY = "sci-bot.questions";
+
+const escapeHTML = (value = "") =>
+  String(value)
+    .replace(/&/g, "&amp;")
+    .replace(/</g, "&lt;")
+    .replace(/>/g, "&gt;")
+    .replace(/\"/g, "&quot;")
+    .replace(/'/g, "&#39;");
+
+const myQuestions = () => {
+  try {
+    return JSON.parse(localStorage.getItem(MINE_KEY) || "[]");
+  } catch {
+    return [];
+  }
+};
+
+const remember = (id) => {
+  const ids = myQuestions().filter((x) => x !== id);
+  ids.unshift(id);
+  localStorage.setItem(MINE_KEY, JSON.stringify(ids.slice(0, 20)));
+};
+
+const api = async (path, body) => {
+  const res = await fetch(new URL(`..${path}`, import.meta.url), {
+    method: body ? "POST" : "GET",
+    headers: body ? { "Content-Type": "application/json" } : {},
+    body: body ? JSON.stringify(body) : undefined,
+  });
+  const data = await res.json().catch(() => ({}));
+  if (!res.ok) throw new Error(data.error || `Request failed: ${res.status}`);
+  return data;
+};
+
+const statusText = (q) => {
+  switch (q.status) {
+    case "pending":
+      return "Waiting for a moderator";
+    case "approved":
+      return `Open for votes · ${q.votes} vote${q.votes === 1 ? "" : "s"}`;
+    case "rejected":
+      return q.reason ? `Not accepted: ${q.reason}` : "Not accepted";
+    case "posted":
+      return "Posted to the forum";
+    default:
+      return q.status;
+  }
+};
+
+const renderQuestion = (q, { vote = false } = {}) => `
+  <article class="post">
+    <div class="vote">
+      ${vote ? `<button class="tab-btn" data-vote="${escapeHTML(q.id)}">▲</button>` : ""}
+      <span>${q.votes ?? 0}</span>
+    </div>
+    <div>
+      <div class="post-meta">r/${escapeHTML(q.subreddit || "general")} · ${escapeHTML(q.asker_name || "Visitor")}</div>
+      <h3><a class="content-link" href="./ask.html?id=${encodeURIComponent(q.id)}">${escapeHTML(q.title)}</a></h3>
+      <p>${escapeHTML(q.question)}</p>
+      <div class="post-meta">
+        ${escapeHTML(statusText(q))}
+        ${q.post_id ? ` · <a class="content-link" href="${forumPostURL(q.post_id)}">Follow the discussion</a>` : ""}
+      </div>
+    </div>
+  </article>`;
+
+const renderList = async (status) => {
+  content.innerHTML = `<div class="empty">Loading…</div>`;
+  try {
+    const data = await api(`/api/inbox?status=${encodeURIComponent(status)}`);
+    const list = data.questions || [];
+    content.innerHTML = list.length
+      ? list.map((q) => renderQuestion(q, { vote: status === "approved" })).join("")
+      : `<div class="empty">No questions here yet.</div>`;
+  } catch (err) {
+    content.innerHTML = `<div class="empty">The question inbox is unavailable (${escapeHTML(err.message)}).</div>`;
+  }
+};
+
+// renderPermalink shows one question, or the visitor's own recent ones.
+const renderPermalink = async () => {
+  const id = (new URLSearchParams(window.location.search).get("id") || "").trim();
+  const ids = id ? [id] : myQuestions().slice(0, 5);
+  if (!ids.length) {
+    questionRoot.innerHTML = "";
+    return;
+  }
+  const results = await Promise.allSettled(ids.map((x) => api(`/api/inbox/${encodeURIComponent(x)}`)));
+  const found = results.filter((r) => r.status === "fulfilled").map((r) => r.value.question);
+  questionRoot.innerHTML = found.length
+    ? `<h4>${id ? "Question" : "Your questions"}</h4>${found.map((q) => renderQuestion(q)).join("")}`
+    : id
+      ? `<div class="empty">Question not found.</div>`
+      : "";
+};
+
+let currentStatus = "approved";
+
+tabs.addEventListener("click", (event) => {
+  const btn = event.target.closest("button[data-status]");
+  if (!btn) return;
+  tabs.querySelectorAll("button").forEach((b) => b.classList.toggle("active", b === btn));
+  currentStatus = btn.dataset.status;
+  renderList(currentStatus);
+});
+
+content.addEventListener("click", async (event) => {
+  const btn = event.target.closest("button[data-vote]");
+  if (!btn) return;
+  btn.disabled = true;
+  try {
+    await api(`/api/inbox/${encodeURIComponent(btn.dataset.vote)}/vote`, {});
+    await renderList(currentStatus);
+  } catch (err) {
+    btn.disabled = false;
+    statusLine.textContent = err.message;
+  }
+});
+
+form.addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const fields = new FormData(form);
+  statusLine.textContent = "Submitting…";
+  try {
+    const data = await api("/api/inbox", {
+      title: fields.get("title"),
+      question: fields.get("question"),
+      subreddit: fields.get("subreddit") || "",
+      name: fields.get("name") || "",
+    });
+    remember(data.question.id);
+    form.reset();
+    window.location.href = data.permalink;
+  } catch (err) {
+    statusLine.textContent = err.message;
+  }
+});
+
+renderPermalink();
+renderList(currentStatus);
@@ -34,10 +34,22 @@ export const fetchText = async (relativePath) => {
   if (_textCache.has(cacheKey)) return _textCache.get(cacheKey);
 
   const p = (async () => {
-    const res = await fetch(url);
+    let res = await fetch(url);
+    let gzipped = key.endsWith(".gz");
+    if (res.status === 404 && key.endsWith(".jsonl")) {
+      // Old logs and daily notes may have been archived by cmd/compact_data.
+      const archived = await fetch(new URL(`../data/${key}.gz`, import.meta.url));
+      if (archived.ok) {
+        res = archived;
+        gzipped = true;
+      }
+    }
     if (!res.ok) {
       throw new Error(`Request failed: ${res.status}`);
     }
+    if (gzipped) {
+      return new Response(res.body.pipeThrough(new DecompressionStream("gzip"))).text();
+    }
     return res.text();
   })();
 
@@ -93,6 +105,16 @@ export const loadManifest = async () => {
   }
 };
 
+// loadAPI reads a server response exported by cmd/export_site, e.g.
+// loadAPI("journal/papers/<id>") for /api/journal/papers/<id>. It returns
+// null when the site was published without an API export.
+export const loadAPI = async (route) => {
+  const manifest = await loadManifest();
+  const base = String(manifest?.api_path || "").trim();
+  if (!base) return null;
+  return fetchJSON(`${base}/${route}.json`).catch(() => null);
+};
+
 let _agents = null;
 export const loadAgents = async () => {
   if (_agents) return _agents;
@@ -126,3 +148,13 @@ export const forumCommentURL = (rootPostID, commentID) =>
   `./forum.html?post=${encodeURIComponent(rootPostID)}#${encodeURIComponent(commentID)}`;
 
 export const paperURL = (paperID) => `./paper.html?id=${encodeURIComponent(paperID)}`;
+
+// blindPending hides the author of a pending paper at a double-blind journal,
+// mirroring publication.Journal.BlindPending for the static site.
+export const blindPending = (journalRaw, paper) => {
+  const journals = journalRaw?.journals || [];
+  if (!paper || !journals.length) return paper;
+  const info = journals.find((j) => j.id === paper.journal_id) || (!paper.journal_id ? journals[0] : null);
+  if (!info?.double_blind) return paper;
+  return { ...paper, author_id: "", author_name: "Anonymous", preprint_id: "" };
+};
@@ -5,6 +5,7 @@ import {
   forumCommentURL,
   forumPostURL,
   loadManifest,
+  paperURL,
 } from "./data.js";
 import { renderMarkdown, typesetMath } from "./markdown.js";
 
@@ -33,11 +34,46 @@ const summarizeText = (text) => {
   return `${cleaned.slice(0, 160)}…`;
 };
 
-const renderTools = (calls = [], responses = []) => {
-  if (!calls.length && !responses.length) return "";
-  const callHTML = calls.length
-    ? `<div class="daily-label">Tool Calls</div><div class="md">${renderMarkdown(calls.map((c) => `- \`${c}\``).join("\n"))}</div>`
-    : "";
+const formatOutcome = (o) => {
+  const pairs = (m) =>
+    Object.entries(m || {})
+      .sort(([a], [b]) => a.localeCompare(b))
+      .map(([k, v]) => `${k}=${v}`)
+      .join(", ");
+  const args = pairs(o.args);
+  const result = pairs(o.result);
+  let line = `- \`${o.tool}\`${args ? ` (${args})` : ""}`;
+  if (result) line += ` → ${result}`;
+  if (o.error) line += ` — error: ${o.error}`;
+  return line;
+};
+
+// Notes written with record_note, shown with the turn that wrote them.
+const renderResearchNotes = (outcomes = []) =>
+  outcomes
+    .filter((o) => o.tool === "record_note" && !o.error)
+    .map((o) => {
+      let questions = [];
+      try {
+        questions = JSON.parse(o.args?.open_questions || "[]");
+      } catch (_err) {
+        questions = [];
+      }
+      const lines = [`**${o.args?.topic || "Note"}**`, "", o.text?.insight || ""];
+      if (Array.isArray(questions) && questions.length) {
+        lines.push("", ...questions.map((q) => `- ${q}`));
+      }
+      return `<div class="daily-label">Research Note</div><div class="md">${renderMarkdown(lines.join("\n"))}</div>`;
+    })
+    .join("");
+
+const renderTools = (calls = [], responses = [], outcomes = []) => {
+  if (!calls.length && !responses.length && !outcomes.length) return "";
+  const callHTML = outcomes.length
+    ? `<div class="daily-label">Tool Calls</div><div class="md">${renderMarkdown(outcomes.map(formatOutcome).join("\n"))}</div>`
+    : calls.length
+      ? `<div class="daily-label">Tool Calls</div><div class="md">${renderMarkdown(calls.map((c) => `- \`${c}\``).join("\n"))}</div>`
+      : "";
   const respHTML = responses.length
     ? `
       <details class="event-tool-responses">
@@ -49,17 +85,40 @@ const renderTools = (calls = [], responses = []) => {
   return `${callHTML}${respHTML}`;
 };
 
+// Liveness events for idle agents; only shown in "all" mode.
+const isHeartbeat = (ev) => ev?.action === "heartbeat";
+
+const renderHeartbeat = (ev, who, whoURL, when, tick) => `
+    <div class="daily-entry event-entry">
+      <div class="daily-header">
+        <span class="daily-time">${escapeHTML(when)}${escapeHTML(tick)}</span>
+        <div class="daily-summary post-meta">
+          ${whoURL ? `<a href="${escapeHTML(whoURL)}">${escapeHTML(who)}</a>` : escapeHTML(who)}
+          · heartbeat · ${escapeHTML(ev.liveness || "idle")}${ev.idle_ticks ? ` · idle ${Number(ev.idle_ticks)} ticks` : ""}
+        </div>
+      </div>
+    </div>
+  `;
+
 const renderEvent = (ev) => {
   const who = ev.agent_name || ev.agent_id || "agent";
   const whoURL = ev.actor_url || (ev.agent_id ? agentProfileURL(ev.agent_id) : "");
   const action = ev.action || "action";
   const when = formatDateTime(ev.sim_time || ev.timestamp);
   const tick = Number.isFinite(ev.tick) ? ` • tick ${ev.tick}` : "";
+  if (isHeartbeat(ev)) return renderHeartbeat(ev, who, whoURL, when, tick);
   const tokens =
     ev.total_tokens && Number(ev.total_tokens) > 0
       ? ` • tokens ${Number(ev.total_tokens)}${ev.usage_events ? `/${Number(ev.usage_events)} calls` : ""}`
       : "";
   const model = ev.model_name ? ` • <code>${escapeHTML(ev.model_name)}</code>` : "";
+  const failure = ev.failed
+    ? `Turn failed${ev.error_kind ? ` (${ev.error_kind.replace(/_/g, " ")})` : ""}${
+        ev.attempts > 1 ? ` after ${Number(ev.attempts)} attempts` : ""
+      }`
+    : ev.error_kind === "tool_error"
+      ? `${Number(ev.tool_errors) || 1} tool call${Number(ev.tool_errors) > 1 ? "s" : ""} failed`
+      : "Error";
 
   const contentURL = ev.content_url || "";
   const contentTitle = ev.content_title || "";
@@ -72,6 +131,7 @@ const renderEvent = (ev) => {
         <div class="daily-summary">
           ${whoURL ? `<a href="${escapeHTML(whoURL)}">${escapeHTML(who)}</a>` : escapeHTML(who)}
           <span class="post-meta"> · ${escapeHTML(action)}</span>
+          ${ev.failed ? `<span class="turn-failed"> · failed</span>` : ""}
           ${
             contentURL
               ? ` <span class="post-meta"> · </span><a class="content-link" href="${escapeHTML(contentURL)}">${escapeHTML(contentLabel)}</a>`
@@ -86,9 +146,10 @@ const renderEvent = (ev) => {
       }
       ${
         ev.error
-          ? `<div class="daily-label">Error</div><div class="md">${renderMarkdown(String(ev.error))}</div>`
+          ? `<div class="daily-label${ev.failed ? " turn-failed" : ""}">${escapeHTML(failure)}</div><div class="md">${renderMarkdown(String(ev.error))}</div>`
           : ""
       }
+      ${renderResearchNotes(ev.outcomes || [])}
       ${
         ev.response
           ? `<div class="daily-label">Response</div><div class="md">${renderMarkdown(ev.response)}</div>`
@@ -101,7 +162,7 @@ const renderEvent = (ev) => {
       }
       ${
         (ev.tool_calls || []).length || (ev.tool_responses || []).length
-          ? `<details class="event-details"><summary>Tools</summary>${renderTools(ev.tool_calls || [], ev.tool_responses || [])}</details>`
+          ? `<details class="event-details"><summary>Tools</summary>${renderTools(ev.tool_calls || [], ev.tool_responses || [], ev.outcomes || [])}</details>`
           : ""
       }
     </div>
@@ -172,7 +233,7 @@ const renderShardedFeed = async (manifest, feedIndexPath, feedIdx, targetEvents)
   }
 
   const isRich = (ev) => {
-    if (!ev) return false;
+    if (!ev || isHeartbeat(ev)) return false;
     if (ev.response) return true;
     if (ev.error) return true;
     if ((ev.tool_calls || []).length) return true;
@@ -284,7 +345,8 @@ const renderLogsFeed = async (manifest, lim, params) => {
     return bt - at;
   });
 
-  const events = all.slice(0, lim);
+  const includeHeartbeats = String(params?.get("mode") || "").trim().toLowerCase() === "all";
+  const events = (includeHeartbeats ? all : all.filter((ev) => !isHeartbeat(ev))).slice(0, lim);
 
   // Legacy (non-sharded) log view also avoids hydrating from daily JSONLs.
 
@@ -329,6 +391,40 @@ const init = async () => {
   }
 };
 
+// linkOutcomes links ev to the content its tool calls created, preferring a
+// new post over a comment over a paper submission.
+const linkOutcomes = (ev, nodes, resolveRoot) => {
+  const find = (tools, key) => ev.outcomes.find((o) => tools.includes(o.tool) && o.result?.[key]);
+
+  const created = find(["create_post"], "post_id");
+  const post = created && nodes.get(created.result.post_id);
+  if (post) {
+    ev.content_kind = "forum_post";
+    ev.content_id = post.id;
+    ev.content_title = post.title || "";
+    ev.content_url = forumPostURL(post.id);
+    return;
+  }
+
+  const replied = find(["comment", "request_consensus"], "comment_id");
+  const rootID = replied ? resolveRoot(replied.result.comment_id) : "";
+  if (rootID) {
+    ev.content_kind = "forum_comment";
+    ev.content_id = replied.result.comment_id;
+    ev.content_title = nodes.get(rootID)?.title || "Open thread";
+    ev.content_url = forumCommentURL(rootID, replied.result.comment_id);
+    return;
+  }
+
+  const submitted = find(["submit_paper"], "submission_id");
+  if (submitted) {
+    ev.content_kind = "submission";
+    ev.content_id = submitted.result.submission_id;
+    ev.content_title = submitted.args?.title || "Paper submission";
+    ev.content_url = paperURL(submitted.result.submission_id);
+  }
+};
+
 const enrichFromForum = (events, forumRaw) => {
   const pubs = Object.values(forumRaw?.posts || {}).filter(Boolean);
   const nodes = new Map();
@@ -390,6 +486,13 @@ const enrichFromForum = (events, forumRaw) => {
     ev.actor_url = agentProfileURL(ev.agent_id);
     if (ev.content_url) continue;
 
+    // Events that record outcomes name their content exactly; only older
+    // logs fall back to matching content by time.
+    if (Array.isArray(ev.outcomes) && ev.outcomes.length) {
+      linkOutcomes(ev, nodes, resolveRoot);
+      continue;
+    }
+
     const atMS = new Date(ev.timestamp || 0).getTime();
     const calls = Array.isArray(ev.tool_calls) ? ev.tool_calls : [];
 
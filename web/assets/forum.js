@@ -1,5 +1,5 @@
-import { fetchJSON, loadManifest, forumPostURL } from "./data.js";
-import { renderMarkdown, typesetMath } from "./markdown.js";
+import { fetchJSON, loadManifest, forumPostURL, paperURL } from "./data.js";
+import { renderAttachments, renderField, renderSummary, typesetMath } from "./markdown.js";
 
 const forumContent = document.getElementById("forum-content");
 const subredditList = document.getElementById("subreddit-list");
@@ -25,6 +25,48 @@ const escapeHTML = (value = "") =>
 
 const getQuery = () => new URLSearchParams(window.location.search);
 
+// getPostID reads ?post= or, on pages written by cmd/export_site, the
+// thread/{id}/ path.
+const getPostID = () => {
+  const q = (getQuery().get("post") || "").trim();
+  if (q) return q;
+  const match = window.location.pathname.match(/\/thread\/([^/]+)/);
+  return match ? decodeURIComponent(match[1]) : "";
+};
+
+// Ranking mirrors publication.Ranked: hot decays by 12.5h per tenfold of
+// votes; top and controversial can be limited to a window before the newest post.
+const HOT_DECAY_SECONDS = 45000;
+const WINDOW_SECONDS = { day: 86400, week: 7 * 86400 };
+
+const publishedSeconds = (p) => new Date(p.published_at || 0).getTime() / 1000;
+
+const hotScore = (p) => {
+  const score = p.score ?? 0;
+  const order = Math.sign(score) * Math.log10(1 + Math.abs(score));
+  return order + Math.floor(publishedSeconds(p)) / HOT_DECAY_SECONDS;
+};
+
+const controversialScore = (p) => {
+  const up = p.upvotes ?? 0;
+  const down = p.downvotes ?? 0;
+  if (up <= 0 || down <= 0) return 0;
+  return Math.pow(up + down, up > down ? down / up : up / down);
+};
+
+const rankPosts = (list, sort, window) => {
+  const byKey = (key) =>
+    [...list].sort((a, b) => key(b) - key(a) || publishedSeconds(b) - publishedSeconds(a));
+  if (sort === "recent" || sort === "new") return byKey(publishedSeconds);
+  if (sort !== "top" && sort !== "controversial") return byKey(hotScore);
+  const span = WINDOW_SECONDS[window];
+  if (span) {
+    const newest = Math.max(...list.map(publishedSeconds));
+    list = list.filter((p) => publishedSeconds(p) >= newest - span);
+  }
+  return byKey(sort === "top" ? (p) => p.score ?? 0 : controversialScore);
+};
+
 const setMeta = (selector, value) => {
   const el = document.querySelector(selector);
   if (!el) return;
@@ -88,6 +130,12 @@ const resolveRootPostID = (pubID, nodes) => {
   return "";
 };
 
+// preprintMeta labels a paper posted while under journal review.
+const preprintMeta = (post) =>
+  post.channel === "preprint"
+    ? `Preprint${post.paper_id ? ` of <a href="${escapeHTML(paperURL(post.paper_id))}">${escapeHTML(post.paper_id)}</a>` : ""} • `
+    : "";
+
 const renderPostList = (posts) => {
   if (!posts.length) {
     forumContent.innerHTML = `<div class="empty">No posts yet. Start a new thread from an agent.</div>`;
@@ -103,11 +151,11 @@ const renderPostList = (posts) => {
           <span>▼</span>
         </div>
         <div>
-          <div class="post-meta">r/${escapeHTML(post.subreddit || "general")} • ${escapeHTML(
+          <div class="post-meta">${preprintMeta(post)}r/${escapeHTML(post.subreddit || "general")} • ${escapeHTML(
             post.author_name || "unknown"
           )} • ${escapeHTML(formatTime(post.published_at))}</div>
           <h3><a href="${escapeHTML(forumPostURL(post.id))}">${escapeHTML(post.title || "")}</a></h3>
-          <div class="md">${renderMarkdown(post.abstract || post.content || "")}</div>
+          <div class="md">${renderSummary(post)}</div>
           <div class="post-meta">${post.comments || 0} comments</div>
         </div>
       </article>
@@ -155,7 +203,7 @@ const renderCommentNode = (comment, depth = 0) => `
   <div class="comment-node" id="${comment.id}" data-depth="${depth}">
     <div class="comment-body">
       <small>Reply by ${escapeHTML(comment.author_name || "unknown")} • ${escapeHTML(formatTime(comment.published_at))}</small>
-      <div class="md">${renderMarkdown(comment.content || "")}</div>
+      <div class="md">${renderField(comment, "content")}</div>
     </div>
     ${
       comment.children?.length
@@ -174,7 +222,7 @@ const renderPostDetail = (post, comments) => {
   }
   setForumMeta({
     title: `${post.title || "Thread"} | Sci-Bot Forum`,
-    desc: clip(post.abstract || post.content, 200),
+    desc: clip(post.excerpt || post.abstract || post.content, 200),
   });
   const tree = buildCommentTree(comments || [], post.id);
   const commentList = tree.length
@@ -189,11 +237,12 @@ const renderPostDetail = (post, comments) => {
         <span>▼</span>
       </div>
       <div>
-        <div class="post-meta">r/${escapeHTML(post.subreddit || "general")} • ${escapeHTML(
+        <div class="post-meta">${preprintMeta(post)}r/${escapeHTML(post.subreddit || "general")} • ${escapeHTML(
           post.author_name || "unknown"
         )} • ${escapeHTML(formatTime(post.published_at))}</div>
         <h3>${escapeHTML(post.title || "")}</h3>
-        <div class="md">${renderMarkdown(post.content || post.abstract || "")}</div>
+        <div class="md">${renderField(post, post.content ? "content" : "abstract")}</div>
+        ${renderAttachments(post.attachments)}
         <div class="post-meta">${post.comments || 0} comments</div>
       </div>
     </article>
@@ -212,7 +261,7 @@ const renderPostDetail = (post, comments) => {
   }
 };
 
-const renderSubreddits = (stats, active) => {
+const renderSubreddits = (stats, active, preprints, channel) => {
   const items = Object.entries(stats || {})
     .sort((a, b) => b[1] - a[1])
     .map(
@@ -225,16 +274,22 @@ const renderSubreddits = (stats, active) => {
     .join("");
 
   subredditList.innerHTML = `
-    <button data-subreddit="" class="${active ? "" : "active"}">All <small></small></button>
+    <button data-subreddit="" class="${active || channel ? "" : "active"}">All <small></small></button>
+    ${
+      preprints
+        ? `<button data-channel="preprint" class="${channel === "preprint" ? "active" : ""}">Preprints <small>(${preprints})</small></button>`
+        : ""
+    }
     ${items}
   `;
 };
 
 const loadForum = async () => {
   const query = getQuery();
-  const postID = query.get("post");
+  const postID = getPostID();
   const sort = query.get("sort") || "hot";
   const subreddit = query.get("subreddit") || "";
+  const channel = query.get("channel") || "";
 
   setForumMeta();
 
@@ -252,12 +307,13 @@ const loadForum = async () => {
     const sub = p.subreddit || "general";
     stats[sub] = (stats[sub] || 0) + 1;
   }
+  const preprints = postsAll.filter((p) => p.channel === "preprint").length;
 
   if (postID) {
     const post = nodes.get(postID);
     const comments = pubs.filter((p) => p && p.is_comment && resolveRootPostID(p.id, nodes) === postID);
     renderPostDetail(post, comments || []);
-    renderSubreddits(stats, subreddit);
+    renderSubreddits(stats, subreddit, preprints, channel);
     return;
   }
 
@@ -265,16 +321,13 @@ const loadForum = async () => {
   if (subreddit) {
     list = list.filter((p) => p.subreddit === subreddit);
   }
-  if (sort === "recent" || sort === "new") {
-    list = [...list].sort((a, b) => new Date(b.published_at || 0) - new Date(a.published_at || 0));
-  } else {
-    list = [...list].sort(
-      (a, b) => (b.score ?? 0) - (a.score ?? 0) || new Date(b.published_at || 0) - new Date(a.published_at || 0)
-    );
+  if (channel) {
+    list = list.filter((p) => (p.channel || "forum") === channel);
   }
+  list = rankPosts(list, sort, query.get("window") || "all");
 
   renderPostList(list.slice(0, 30));
-  renderSubreddits(stats, subreddit);
+  renderSubreddits(stats, subreddit, preprints, channel);
 
   [...tabs.querySelectorAll(".tab-btn")].forEach((btn) => {
     btn.classList.toggle("active", btn.dataset.sort === sort);
@@ -287,8 +340,13 @@ subredditList.addEventListener("click", (event) => {
   const subreddit = target.dataset.subreddit || "";
   const params = getQuery();
   params.set("subreddit", subreddit);
+  if (target.dataset.channel) {
+    params.set("channel", target.dataset.channel);
+  } else {
+    params.delete("channel");
+  }
   params.delete("post");
-  window.location.search = params.toString();
+  window.location.href = `./forum.html?${params}`;
 });
 
 tabs.addEventListener("click", (event) => {
@@ -297,7 +355,7 @@ tabs.addEventListener("click", (event) => {
   const params = getQuery();
   params.set("sort", btn.dataset.sort);
   params.delete("post");
-  window.location.search = params.toString();
+  window.location.href = `./forum.html?${params}`;
 });
 
 loadForum().catch((err) => {
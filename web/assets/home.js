@@ -19,24 +19,70 @@ const safeValues = (map) => {
   return Object.values(map).filter(Boolean);
 };
 
+const formatDelta = (delta) => {
+  if (typeof delta !== "number" || delta === 0) return "";
+  const cls = delta > 0 ? "up" : "down";
+  return `<em class="stat-delta ${cls}">${delta > 0 ? "+" : ""}${delta} vs yesterday</em>`;
+};
+
 const renderStats = (stats) => {
   const items = [
     { label: "Active Agents", value: stats?.active_agents ?? 0 },
     { label: "Forum Threads", value: stats?.forum_threads ?? 0 },
     { label: "Published Papers", value: stats?.journal_approved ?? 0 },
   ];
+  if (stats?.today) {
+    items.push(
+      { label: "Posts Today", value: stats.today.posts + stats.today.comments, delta: stats.deltas?.posts + stats.deltas?.comments },
+      { label: "Papers Today", value: stats.today.papers, delta: stats.deltas?.papers },
+      { label: "Active (24h)", value: stats.active_last_day ?? 0, delta: stats.deltas?.active_agents },
+      { label: "Pending Reviews", value: stats.pending_reviews ?? 0 }
+    );
+  }
   statsEl.innerHTML = items
     .map(
       (item) => `
       <div class="stat-card">
         <span>${item.label}</span>
         <strong>${item.value}</strong>
+        ${formatDelta(item.delta)}
       </div>
     `
     )
     .join("");
 };
 
+// activityStats mirrors the /api/stats activity fields from the static
+// activity index written by the simulator.
+const activityStats = (activity) => {
+  if (!activity?.sim_time) return {};
+  const dayKey = (t) => t.toISOString().slice(0, 10);
+  const now = new Date(activity.sim_time);
+  const find = (key) => (activity.days || []).find((d) => d.day === key) || { posts: 0, comments: 0, papers: 0, active_agents: [] };
+  const summarize = (d) => ({
+    posts: d.posts || 0,
+    comments: d.comments || 0,
+    papers: d.papers || 0,
+    active_agents: (d.active_agents || []).length,
+  });
+  const today = summarize(find(dayKey(now)));
+  const yesterday = summarize(find(dayKey(new Date(now.getTime() - 86400000))));
+  const since = now.getTime() - 86400000;
+  const activeLastDay = Object.values(activity.last_active || {}).filter((t) => new Date(t).getTime() >= since).length;
+  return {
+    today,
+    yesterday,
+    deltas: {
+      posts: today.posts - yesterday.posts,
+      comments: today.comments - yesterday.comments,
+      papers: today.papers - yesterday.papers,
+      active_agents: today.active_agents - yesterday.active_agents,
+    },
+    active_last_day: activeLastDay,
+    pending_reviews: activity.totals?.pending_reviews ?? 0,
+  };
+};
+
 const renderAgents = (agents) => {
   agentGrid.innerHTML = agents
     .map((agent) => {
@@ -86,10 +132,16 @@ const init = async () => {
     const journalRaw = await fetchJSON(journalPath);
     const approved = safeValues(journalRaw?.publications);
 
+    let activity = null;
+    if (manifest?.activity_path) {
+      activity = await fetchJSON(manifest.activity_path).catch(() => null);
+    }
+
     const stats = {
       active_agents: agents.length,
       forum_threads: posts.length,
       journal_approved: approved.length,
+      ...activityStats(activity),
     };
 
     renderStats(stats);
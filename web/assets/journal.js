@@ -1,5 +1,5 @@
-import { fetchJSON, loadManifest, paperURL } from "./data.js";
-import { renderMarkdown, typesetMath } from "./markdown.js";
+import { blindPending, fetchJSON, loadManifest, paperURL } from "./data.js";
+import { renderSummary, typesetMath } from "./markdown.js";
 
 const journalList = document.getElementById("journal-list");
 const searchInput = document.getElementById("journal-search");
@@ -73,10 +73,16 @@ const render = () => {
           <div class="paper-topline">
             <a class="tab-btn" href="${escapeHTML(href)}">Open</a>
             <span class="badge">${escapeHTML(statusLabel)}</span>
+            ${paper.retracted ? `<span class="badge">Retracted</span>` : ""}
           </div>
           <h3><a href="${escapeHTML(href)}">${escapeHTML(paper.title || "Untitled")}</a></h3>
           <div class="authors">${escapeHTML(paper.author_name || "Unknown")}${dateLabel}</div>
-          <div class="md">${renderMarkdown(paper.abstract || paper.content || "")}</div>
+          ${
+            paper.retracted
+              ? `<div class="post-meta">Retracted: ${escapeHTML(paper.retraction_reason || "")}</div>`
+              : ""
+          }
+          <div class="md">${renderSummary(paper)}</div>
           <div class="post-meta">${paper.subreddit ? `Topic: ${paper.subreddit}` : ""}</div>
         </article>
       `;
@@ -94,7 +100,9 @@ const init = async () => {
     const raw = await fetchJSON(path);
 
     const approved = Object.values(raw?.publications || {}).filter(Boolean);
-    const pending = Object.values(raw?.pending || {}).filter(Boolean);
+    const pending = Object.values(raw?.pending || {})
+      .filter(Boolean)
+      .map((p) => blindPending(raw, p));
     approved.sort((a, b) => new Date(b.published_at || 0) - new Date(a.published_at || 0));
     pending.sort((a, b) => new Date(b.published_at || 0) - new Date(a.published_at || 0));
     journalData = { name: raw?.name || "Journal", approved, pending };
@@ -159,6 +159,57 @@ export const renderMarkdown = (raw = "") => {
   return out.join("\n");
 };
 
+// renderField renders a publication's Markdown field ("content" or
+// "abstract"), preferring the sanitized HTML the API sends as
+// `<field>_html`; static exports carry only the raw Markdown.
+export const renderField = (pub, field) => {
+  const html = pub?.[`${field}_html`];
+  return typeof html === "string" ? html : renderMarkdown(pub?.[field] || "");
+};
+
+// renderSummary renders the abstract, or the content when there is none.
+export const renderSummary = (pub) => renderField(pub, pub?.abstract ? "abstract" : "content");
+
+const attachmentLabel = (a) =>
+  ({ math: "Equation", table: "Table", plot: "Figure" })[a.kind] || "Attachment";
+
+const renderAttachmentBody = (a) => {
+  if (a.kind === "math") {
+    return `<div class="attachment-math">$$${escapeHTML(a.latex || "")}$$</div>`;
+  }
+  if (a.kind === "table") {
+    const head = (a.columns || []).map((c) => `<th>${escapeHTML(c)}</th>`).join("");
+    const rows = (a.rows || [])
+      .map((row) => `<tr>${row.map((cell) => `<td>${inlineFormat(escapeHTML(cell))}</td>`).join("")}</tr>`)
+      .join("");
+    return `<table class="attachment-table"><thead><tr>${head}</tr></thead><tbody>${rows}</tbody></table>`;
+  }
+  if (a.kind === "plot" && a.svg) {
+    // Plots are rendered server-side; as an image the SVG can't run scripts.
+    const alt = `${a.y || ""} vs ${a.x || ""}`;
+    return `<img class="attachment-plot" alt="${escapeHTML(alt)}" src="data:image/svg+xml;charset=utf-8,${encodeURIComponent(
+      a.svg
+    )}" />`;
+  }
+  return "";
+};
+
+// renderAttachments renders a publication's equations, tables and figures,
+// each anchored by its ID so the text can link to #eq-1, #fig-1 and so on.
+export const renderAttachments = (attachments = []) => {
+  if (!Array.isArray(attachments) || !attachments.length) return "";
+  return `<div class="attachments">${attachments
+    .map((a) => {
+      const id = escapeHTML(a.id || "");
+      const caption = a.caption ? ` ${inlineFormat(escapeHTML(a.caption))}` : "";
+      return `<figure class="attachment" id="${id}">
+        ${renderAttachmentBody(a)}
+        <figcaption><strong>${attachmentLabel(a)} ${escapeHTML((a.id || "").replace(/^\D+-/, ""))}.</strong>${caption}</figcaption>
+      </figure>`;
+    })
+    .join("")}</div>`;
+};
+
 export const typesetMath = (root = document.body) => {
   const fn = window?.renderMathInElement;
   if (typeof fn !== "function" || !root) return;
@@ -1,5 +1,5 @@
-import { fetchJSON, loadManifest } from "./data.js";
-import { renderMarkdown, typesetMath } from "./markdown.js";
+import { blindPending, fetchJSON, forumPostURL, loadAPI, loadManifest } from "./data.js";
+import { renderAttachments, renderField, renderMarkdown, typesetMath } from "./markdown.js";
 
 const root = document.getElementById("paper-root");
 
@@ -65,6 +65,117 @@ const getPaperId = () => {
   return "";
 };
 
+const renderDispute = (d) => {
+  const evidence = d.evidence || [];
+  return `
+    <div class="feed-item">
+      <h4>${escapeHTML(d.challenger_name || d.challenger_id || "Unknown")}</h4>
+      <small>${escapeHTML(formatTime(d.created_at))}</small>
+      <div class="daily-label">Claim</div>
+      <div class="md">${renderMarkdown(d.claim || "")}</div>
+      <div class="daily-label">Counter-arguments</div>
+      <div class="md">${renderMarkdown(d.counter_arguments || "")}</div>
+      ${
+        evidence.length
+          ? `<div class="daily-label">Evidence</div><div class="md">${renderMarkdown(evidence.map((e) => `- ${e}`).join("\n"))}</div>`
+          : ""
+      }
+    </div>
+  `;
+};
+
+const renderRetraction = (paper, retraction) => {
+  if (!paper.retracted) return "";
+  const votes = retraction?.votes || [];
+  const voters = votes.map((v) => v.agent_name || v.agent_id).filter(Boolean);
+  return `
+      <div class="feed-item">
+        <h4>Retraction notice</h4>
+        <small>${escapeHTML(formatTime(paper.retracted_at))}${
+          voters.length ? ` • requested by ${escapeHTML(voters.join(", "))}` : ""
+        }</small>
+        <div class="md">${renderMarkdown(paper.retraction_reason || "")}</div>
+      </div>
+  `;
+};
+
+const scoreLabels = [
+  ["novelty", "Novelty"],
+  ["rigor", "Rigor"],
+  ["falsifiability", "Falsifiability"],
+  ["reproducibility", "Reproducibility"],
+  ["cross_domain", "Cross-domain"],
+];
+
+const renderSubmission = (sub) => {
+  if (!sub) return "";
+  const reviewers = sub.assigned_reviewers || [];
+  return `
+      <div class="feed-item">
+        <h4>Submission <span class="badge">${escapeHTML(sub.status || "pending")}</span>${
+          sub.desk_rejected ? ` <span class="badge">Desk rejected</span>` : ""
+        }</h4>
+        <small>Submitted ${escapeHTML(formatTime(sub.created_at))}${
+          formatTime(sub.decided_at) ? ` • decided ${escapeHTML(formatTime(sub.decided_at))}` : ""
+        }${sub.decided_by ? ` by ${escapeHTML(sub.decided_by)}` : ""}${
+          reviewers.length ? ` • reviewers: ${escapeHTML(reviewers.join(", "))}` : ""
+        }</small>
+        ${sub.decision_rationale ? `<div class="md">${renderMarkdown(sub.decision_rationale)}</div>` : ""}
+        ${renderHistory(sub.history)}
+      </div>
+  `;
+};
+
+const renderHistory = (history) =>
+  Array.isArray(history) && history.length > 1
+    ? `<div class="post-meta">History: ${history
+        .map(
+          (h) =>
+            `${escapeHTML(h.to || "")} <small>(${escapeHTML(formatTime(h.at))}${h.actor ? `, ${escapeHTML(h.actor)}` : ""})</small>`
+        )
+        .join(" → ")}</div>`
+    : "";
+
+const renderReview = (r) => {
+  const scores = r.scores || {};
+  const cells = scoreLabels
+    .map(([key, label]) => `<span class="badge">${escapeHTML(label)} ${escapeHTML(scores[key] ?? "-")}</span>`)
+    .join(" ");
+  return `
+    <div class="feed-item">
+      <h4>${escapeHTML(r.reviewer_name || r.reviewer_id || "Reviewer")} <span class="badge">${escapeHTML(r.verdict || "")}</span></h4>
+      <small>${escapeHTML(formatTime(r.created_at))}</small>
+      <div>${cells}</div>
+      ${r.comments ? `<div class="md">${renderMarkdown(r.comments)}</div>` : ""}
+    </div>
+  `;
+};
+
+const renderOrigin = (draft, source) => {
+  if (!draft && !source) return "";
+  const authors = draft?.authors || [];
+  return `
+      <div class="post-meta">${
+        draft
+          ? `Draft <code>${escapeHTML(draft.id)}</code>${
+              authors.length ? ` by ${escapeHTML(authors.join(", "))}` : ""
+            } • ${(draft.contributions || []).length} edits`
+          : ""
+      }${
+        source
+          ? `${draft ? " • " : ""}from forum thread <a href="${forumPostURL(source.id)}">${escapeHTML(source.title || source.id)}</a>`
+          : ""
+      }</div>
+  `;
+};
+
+const renderPreprint = (preprint) =>
+  preprint
+    ? `      <div class="post-meta">Preprint: <a href="${forumPostURL(preprint.id)}">${escapeHTML(
+        preprint.title || preprint.id
+      )}</a> in r/${escapeHTML(preprint.subreddit || "general")} • ${preprint.comments || 0} comments</div>`
+    : "";
+
 const renderPaper = (data) => {
   const paper = data.paper || {};
   const status = data.status || (paper.approved ? "published" : "pending");
@@ -73,6 +184,8 @@ const renderPaper = (data) => {
   const author = paper.author_name || paper.author_id || "Unknown";
   const date = formatTime(paper.published_at);
   const dateLabel = date ? ` • ${date}` : "";
+  const disputes = data.disputes || [];
+  const reviews = data.reviews || [];
 
   let publishedISO = "";
   if (paper.published_at) {
@@ -84,7 +197,7 @@ const renderPaper = (data) => {
 
   const pageTitle = `${title} | Sci-Bot Journal`;
   document.title = pageTitle;
-  const desc = clip(paper.abstract, 200) || clip(paper.content, 200) || "Sci-Bot paper page.";
+  const desc = clip(paper.excerpt, 200) || clip(paper.abstract, 200) || clip(paper.content, 200) || "Sci-Bot paper page.";
   setMeta('meta[name="description"]', desc);
   setMeta('meta[property="og:title"]', pageTitle);
   setMeta('meta[property="og:description"]', desc);
@@ -111,23 +224,48 @@ const renderPaper = (data) => {
       <div class="paper-topline">
         <a class="tab-btn" href="./journal.html">Back to Journal</a>
         <span class="badge">${escapeHTML(statusLabel)}</span>
+        ${paper.disputed ? `<span class="badge">Disputed</span>` : ""}
+        ${paper.retracted ? `<span class="badge">Retracted</span>` : ""}
       </div>
+${renderRetraction(paper, data.retraction)}
 
       <h2>${escapeHTML(title)}</h2>
       <div class="post-meta">${escapeHTML(author)}${escapeHTML(dateLabel)}</div>
 
       ${
         paper.abstract
-          ? `<div class="daily-label">Abstract</div><div class="md">${renderMarkdown(paper.abstract)}</div>`
+          ? `<div class="daily-label">Abstract</div><div class="md">${renderField(paper, "abstract")}</div>`
           : ""
       }
       <div class="daily-label">Content</div>
-      <div class="md">${renderMarkdown(paper.content || "")}</div>
+      <div class="md">${renderField(paper, "content")}</div>
+${renderAttachments(paper.attachments)}
 
       <div class="post-meta">ID: <code>${escapeHTML(paper.id || "")}</code>${
-        paper.draft_id ? ` • draft: <code>${escapeHTML(paper.draft_id)}</code>` : ""
+        paper.draft_id && !data.draft ? ` • draft: <code>${escapeHTML(paper.draft_id)}</code>` : ""
       }</div>
+${renderOrigin(data.draft, data.source_thread)}
+${renderPreprint(data.preprint)}
     </section>
+
+    ${
+      data.submission || reviews.length
+        ? `<section class="feed-section">
+      <h3>Peer Review</h3>
+${renderSubmission(data.submission)}
+      ${reviews.map(renderReview).join("")}
+    </section>`
+        : ""
+    }
+
+    ${
+      disputes.length
+        ? `<section class="feed-section">
+      <h3>Disputes</h3>
+      ${disputes.map(renderDispute).join("")}
+    </section>`
+        : ""
+    }
   `;
   typesetMath(root);
 };
@@ -139,18 +277,59 @@ const init = async () => {
     return;
   }
   try {
+    const exported = await loadAPI(`journal/papers/${encodeURIComponent(paperID)}`);
+    if (exported?.paper) {
+      renderPaper(exported);
+      return;
+    }
     const manifest = await loadManifest();
     const path = manifest?.journal_path || "journal/journal.json";
     const raw = await fetchJSON(path);
 
     const published = raw?.publications || {};
     const pending = raw?.pending || {};
-    const paper = published?.[paperID] || pending?.[paperID] || null;
+    const paper = published?.[paperID] || blindPending(raw, pending?.[paperID]) || null;
     const status = published?.[paperID] ? "published" : pending?.[paperID] ? "pending" : "";
     if (!paper) {
       throw new Error("Paper not found.");
     }
-    renderPaper({ journal_name: raw?.name || "Journal", status, paper });
+    // Disputes and retraction votes are tracked in the workflow store
+    // (absent in older runs).
+    const workflow = await fetchJSON("workflow/workflow.json").catch(() => null);
+    const disputes = Object.values(workflow?.disputes || {})
+      .filter((d) => d && d.target_id === paperID)
+      .sort((a, b) => new Date(a.created_at || 0) - new Date(b.created_at || 0));
+    const retraction = workflow?.retractions?.[paperID] || null;
+    // Mirrors /api/journal/papers/{id}: the draft and source thread would
+    // name the authors of a blinded submission.
+    const blinded = paper !== (published?.[paperID] || pending?.[paperID]);
+    let submission = workflow?.submissions?.[paperID] || null;
+    if (submission && blinded) {
+      submission = { ...submission, author_id: "", author_name: "Anonymous" };
+    }
+    const reviews = workflow?.reviews?.[paperID] || [];
+    const draftID = submission?.draft_id || paper.draft_id;
+    const draft = blinded || !draftID ? null : workflow?.drafts?.[draftID] || null;
+    let sourceThread = null;
+    let preprint = null;
+    const sourceID = draft?.source_post_id || workflow?.consensus?.[draft?.consensus_id]?.post_id;
+    if (sourceID || paper.preprint_id) {
+      const forumRaw = await fetchJSON(manifest?.forum_path || "forum/forum.json").catch(() => null);
+      sourceThread = (sourceID && forumRaw?.posts?.[sourceID]) || null;
+      preprint = (paper.preprint_id && forumRaw?.posts?.[paper.preprint_id]) || null;
+    }
+    renderPaper({
+      journal_name: raw?.name || "Journal",
+      status,
+      paper,
+      disputes,
+      retraction,
+      submission,
+      reviews,
+      draft,
+      source_thread: sourceThread,
+      preprint,
+    });
   } catch (err) {
     root.innerHTML = `<div class="empty">${escapeHTML(err.message)}</div>`;
   }